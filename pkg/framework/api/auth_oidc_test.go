@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// newTestOIDCIssuer serves a discovery document and a JWKS holding key under kid "test-key"
+func newTestOIDCIssuer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   server.URL,
+			"jwks_uri": server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	return server
+}
+
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test-key", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCVerifier_Verify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := newTestOIDCIssuer(t, key)
+	verifier := newOIDCVerifier(issuer.URL, "conductor", issuer.Client())
+
+	future := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "sub": "alice", "aud": "conductor", "exp": future}),
+		},
+		{
+			name:  "audience list",
+			token: signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "sub": "alice", "aud": []string{"other", "conductor"}, "exp": future}),
+		},
+		{
+			name:    "wrong audience",
+			token:   signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "sub": "alice", "aud": "other", "exp": future}),
+			wantErr: true,
+		},
+		{
+			name:    "missing audience",
+			token:   signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "sub": "alice", "exp": future}),
+			wantErr: true,
+		},
+		{
+			name:    "expired token",
+			token:   signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "exp": time.Now().Add(-time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			token:   signTestJWT(t, key, map[string]interface{}{"iss": "https://other.example.com", "exp": future}),
+			wantErr: true,
+		},
+		{
+			name:    "wrong signing key",
+			token:   signTestJWT(t, otherKey, map[string]interface{}{"iss": issuer.URL, "exp": future}),
+			wantErr: true,
+		},
+		{
+			name:    "not a JWT",
+			token:   "secret-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && claims.Subject != "alice" {
+				t.Errorf("Verify() subject = %q, want %q", claims.Subject, "alice")
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_OIDCToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := newTestOIDCIssuer(t, key)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthMiddleware(AuthConfig{Enabled: true, OIDCIssuerURL: issuer.URL, OIDCAudience: "conductor"}, logr.Discard())(next)
+
+	token := signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "aud": "conductor", "exp": time.Now().Add(time.Hour).Unix()})
+	if w := authRequest(handler, "/api/services", "Bearer "+token); w.Code != http.StatusOK {
+		t.Errorf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w := authRequest(handler, "/api/services", "Bearer not-a-jwt"); w.Code != http.StatusUnauthorized {
+		t.Errorf("status code = %v, want %v", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuditMiddleware_RecordsVerifiedSubject(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := newTestOIDCIssuer(t, key)
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	eventStore := events.NewStorage(db, logr.Discard())
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	auth := AuthMiddleware(AuthConfig{Enabled: true, OIDCIssuerURL: issuer.URL, OIDCAudience: "conductor"}, logr.Discard())
+	handler := AuditMiddleware(eventStore, logr.Discard())(auth(next))
+
+	token := signTestJWT(t, key, map[string]interface{}{"iss": issuer.URL, "sub": "alice", "aud": "conductor", "exp": time.Now().Add(time.Hour).Unix()})
+	req := httptest.NewRequest("POST", "/api/up", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Remote-User", "mallory")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	entries, err := eventStore.ListAuditEntries(events.AuditFilters{})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].User != "alice" {
+		t.Errorf("audit entries = %+v, want one by the token subject alice", entries)
+	}
+}
+
+func TestAuthMiddleware_OIDCTenantsClaim(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	issuer := newTestOIDCIssuer(t, key)
+
+	var identity *authIdentity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = authIdentityFrom(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthMiddleware(AuthConfig{Enabled: true, OIDCIssuerURL: issuer.URL, OIDCAudience: "conductor", OIDCTenantsClaim: "groups"}, logr.Discard())(next)
+
+	token := signTestJWT(t, key, map[string]interface{}{
+		"iss": issuer.URL, "sub": "alice", "aud": "conductor", "exp": time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"team-a"}, "tenants": []string{"team-b"},
+	})
+	if w := authRequest(handler, "/api/manifests", "Bearer "+token); w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if !identity.allowsTenant("team-a") || identity.allowsTenant("team-b") {
+		t.Errorf("identity tenants = %v, want those of the configured claim only", identity.tenants)
+	}
+}
@@ -8,3 +8,5 @@ const DefaultRequestTimeout = 5 * time.Second
 // DefaultHealthCheckTimeout is the default timeout for individual health check requests
 const DefaultHealthCheckTimeout = 2 * time.Second
 
+// DefaultProbeTimeout is the default timeout for probing a service's health path
+const DefaultProbeTimeout = 5 * time.Second
@@ -15,12 +15,22 @@ func httpStatus(err error) int {
 	if errors.Is(err, apperrors.ErrNotFound) {
 		return http.StatusNotFound
 	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
 	if errors.Is(err, apperrors.ErrInvalid) || errors.Is(err, apperrors.ErrInvalidYAML) ||
 		errors.Is(err, apperrors.ErrMissingParameter) || errors.Is(err, apperrors.ErrInvalidParameter) ||
 		errors.Is(err, apperrors.ErrInvalidRequest) || errors.Is(err, apperrors.ErrInvalidNamespace) ||
 		errors.Is(err, apperrors.ErrInvalidServiceName) {
 		return http.StatusBadRequest
 	}
+	if errors.Is(err, apperrors.ErrPreconditionRequired) {
+		return http.StatusPreconditionRequired
+	}
+	if errors.Is(err, apperrors.ErrPreconditionFailed) {
+		return http.StatusPreconditionFailed
+	}
 	if errors.Is(err, apperrors.ErrStorage) {
 		return http.StatusInternalServerError
 	}
@@ -42,6 +52,10 @@ func extractErrorCode(err error) string {
 	if errors.Is(err, apperrors.ErrNotFound) {
 		return "not_found"
 	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return "request_too_large"
+	}
 	if errors.Is(err, apperrors.ErrMissingParameter) {
 		return "missing_parameter"
 	}
@@ -63,6 +77,12 @@ func extractErrorCode(err error) string {
 	if errors.Is(err, apperrors.ErrInvalidYAML) {
 		return "invalid_yaml"
 	}
+	if errors.Is(err, apperrors.ErrPreconditionRequired) {
+		return "precondition_required"
+	}
+	if errors.Is(err, apperrors.ErrPreconditionFailed) {
+		return "precondition_failed"
+	}
 	if errors.Is(err, apperrors.ErrStorage) {
 		return "storage_error"
 	}
@@ -78,4 +98,3 @@ func extractErrorCode(err error) string {
 
 	return "internal_error"
 }
-
@@ -6,21 +6,27 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	texttemplate "text/template"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 	"github.com/garunski/conductor-framework/pkg/framework/events"
 	"github.com/garunski/conductor-framework/pkg/framework/crd"
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/notifier"
 	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
 	"github.com/garunski/conductor-framework/pkg/framework/store"
+	"github.com/garunski/conductor-framework/pkg/framework/webhook"
 )
 
 type Handler struct {
 	logger          logr.Logger
 	reconcileCh     chan string
 	reconciler      reconciler.Reconciler
+	clusters        *reconciler.MultiReconciler
 	appName         string
 	version         string
 	templates       *template.Template
@@ -29,6 +35,52 @@ type Handler struct {
 	parameterClient *crd.Client
 	manifestFS      embed.FS
 	manifestRoot    string
+
+	webhookInvoker     webhook.Invoker
+	preDeployWebhooks  []webhook.Config
+	postDeployWebhooks []webhook.Config
+	notifier           notifier.Notifier
+
+	readLimiter  *RateLimiter
+	writeLimiter *RateLimiter
+
+	// maxBodyBytes and maxBulkBodyBytes limit request bodies; zero leaves them unlimited
+	maxBodyBytes     int64
+	maxBulkBodyBytes int64
+
+	resourceStatuses resourceStatusCache
+	manifestLabels   manifestLabelCache
+	jobs             jobStore
+	namespaces       namespaceCache
+
+	auth AuthConfig
+
+	webhookTrigger WebhookTriggerConfig
+
+	corsAllowedOrigins []string
+
+	metricsGatherer prometheus.Gatherer
+
+	db             *database.DB
+	gcDiscardRatio float64
+
+	probeClient  *http.Client
+	probeTimeout time.Duration
+
+	skipCapacityCheck       bool
+	requireDownConfirmation bool
+	strictKeyValidation     bool
+
+	// defaultParameterSpec is returned by GetParameters while no DeploymentParameters instance exists
+	defaultParameterSpec map[string]interface{}
+
+	// templateFuncs are the custom template functions GetRenderedManifest renders with
+	templateFuncs texttemplate.FuncMap
+	// clusterVersion is the Kubernetes server version GetRenderedManifest exposes as .Cluster.Version
+	clusterVersion string
+
+	newTenantStore func(tenantID string) store.ManifestStore
+	tenantStores   tenantStoreCache
 }
 
 func NewHandler(store store.ManifestStore, eventStore events.EventStorage, logger logr.Logger, reconcileCh chan string, rec reconciler.Reconciler, appName, version string, parameterClient *crd.Client, customTemplateFS *embed.FS, manifestFS embed.FS, manifestRoot string) (*Handler, error) {
@@ -54,11 +106,28 @@ func NewHandler(store store.ManifestStore, eventStore events.EventStorage, logge
 		parameterClient: parameterClient,
 		manifestFS:      manifestFS,
 		manifestRoot:    manifestRoot,
+
+		corsAllowedOrigins: []string{"*"},
+
+		probeClient:  newProbeClient(&http.Client{}),
+		probeTimeout: DefaultProbeTimeout,
 	}
 
 	return h, nil
 }
 
+// SetAuth enables bearer token authentication for every route except the health probes.
+// It must be called before SetupRoutes.
+func (h *Handler) SetAuth(cfg AuthConfig) {
+	h.auth = cfg
+}
+
+// SetCORSAllowedOrigins restricts CORS to the given origin patterns; see CORSMiddleware.
+// Every origin is allowed until it is called. It must be called before SetupRoutes.
+func (h *Handler) SetCORSAllowedOrigins(origins []string) {
+	h.corsAllowedOrigins = origins
+}
+
 func (h *Handler) renderTemplate(w http.ResponseWriter, name string, data interface{}) error {
 	// Ensure AppName and AppVersion are always available in template context
 	templateData := make(map[string]interface{})
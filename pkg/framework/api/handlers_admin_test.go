@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+)
+
+func newAdminTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetDatabase(db, 0)
+	if err := db.Set("default/Deployment/web", []byte("kind: Deployment")); err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+	return handler
+}
+
+func TestRunDatabaseGC(t *testing.T) {
+	handler := newAdminTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/admin/gc?discard_ratio=0.7", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("RunDatabaseGC() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp GCResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("RunDatabaseGC() response is not valid JSON: %v", err)
+	}
+	if resp.DiscardRatio != 0.7 {
+		t.Errorf("RunDatabaseGC() discard_ratio = %v, want 0.7", resp.DiscardRatio)
+	}
+	if resp.Stats.NumKeys != 1 {
+		t.Errorf("RunDatabaseGC() stats.num_keys = %d, want 1", resp.Stats.NumKeys)
+	}
+}
+
+func TestRunDatabaseGC_InvalidDiscardRatio(t *testing.T) {
+	handler := newAdminTestHandler(t)
+
+	for _, ratio := range []string{"abc", "0", "1.5"} {
+		w := httptest.NewRecorder()
+		handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/admin/gc?discard_ratio="+ratio, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("RunDatabaseGC(discard_ratio=%s) status = %d, want %d", ratio, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestDatabaseStats(t *testing.T) {
+	handler := newAdminTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/admin/db/stats", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("DatabaseStats() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var raw map[string]float64
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("DatabaseStats() response is not valid JSON: %v", err)
+	}
+	for _, field := range []string{"lsm_size_bytes", "vlog_size_bytes", "num_keys", "num_pending_writes"} {
+		value, ok := raw[field]
+		if !ok {
+			t.Errorf("DatabaseStats() is missing %s", field)
+		} else if value < 0 {
+			t.Errorf("DatabaseStats() %s = %v, want non-negative", field, value)
+		}
+	}
+	if raw["num_keys"] != 1 {
+		t.Errorf("DatabaseStats() num_keys = %v, want 1", raw["num_keys"])
+	}
+}
+
+func TestDatabaseStats_NoDatabase(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/admin/db/stats", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("DatabaseStats() status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// GetAuditLog returns the chronological audit log of user-triggered operations, at most
+// limit entries (default 100) starting at from
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if h.eventStore == nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: event store not available", apperrors.ErrEventStore))
+		return
+	}
+
+	filters, err := ParseAuditQueryParams(r.URL.Query())
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	entries, err := h.eventStore.ListAuditEntries(filters)
+	if err != nil {
+		h.logger.Error(err, "failed to list audit entries")
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, entries)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+func TestAuditLog_IgnoresRemoteUserFromUntrustedClients(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetAuth(AuthConfig{Enabled: true, Tokens: []string{"deploy-token"}, TrustedProxies: []string{"10.0.0.1"}})
+	router := handler.SetupRoutes()
+
+	req := httptest.NewRequest("POST", "/api/up", nil)
+	req.Header.Set("Authorization", "Bearer deploy-token")
+	req.Header.Set(RemoteUserHeader, "mallory")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /api/up status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest("GET", "/api/audit", nil)
+	req.Header.Set("Authorization", "Bearer deploy-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var entries []events.AuditEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("GetAuditLog() response is not valid JSON: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("GetAuditLog() returned %d entries, want 1", len(entries))
+	}
+	// The static token identifies the caller, not the header a client set itself
+	if want := staticTokenSubject("deploy-token"); entries[0].User != want {
+		t.Errorf("entry.User = %q, want the static token subject %q", entries[0].User, want)
+	}
+}
+
+func TestAuditLog_RecordsUp(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	// httptest requests come from 192.0.2.1
+	handler.SetAuth(AuthConfig{TrustedProxies: []string{"192.0.2.0/24"}})
+	router := handler.SetupRoutes()
+
+	before := time.Now()
+	req := httptest.NewRequest("POST", "/api/up", nil)
+	req.Header.Set(RemoteUserHeader, "alice")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /api/up status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	// GET requests are not audited
+	req = httptest.NewRequest("GET", "/api/audit", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/audit status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var entries []events.AuditEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("GetAuditLog() response is not valid JSON: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("GetAuditLog() returned %d entries, want 1", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Action != "POST /api/up" {
+		t.Errorf("entry.Action = %v, want %v", entry.Action, "POST /api/up")
+	}
+	if entry.User != "alice" {
+		t.Errorf("entry.User = %v, want alice", entry.User)
+	}
+	if entry.StatusCode != http.StatusOK {
+		t.Errorf("entry.StatusCode = %v, want %v", entry.StatusCode, http.StatusOK)
+	}
+	if entry.Timestamp.Before(before.Add(-time.Second)) || entry.Timestamp.After(time.Now()) {
+		t.Errorf("entry.Timestamp = %v, want between %v and now", entry.Timestamp, before)
+	}
+}
+
+func TestAuditLog_SkipsThrottledAndUnauthorizedRequests(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetRateLimits(RateLimitConfig{}, RateLimitConfig{RequestsPerSecond: 0.001, BurstSize: 1})
+	router := handler.SetupRoutes()
+
+	want := []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}
+	for i, code := range want {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/up", nil))
+		if w.Code != code {
+			t.Fatalf("POST /api/up #%d status code = %v, want %v", i+1, w.Code, code)
+		}
+	}
+
+	handler.SetRateLimits(RateLimitConfig{}, RateLimitConfig{})
+	handler.SetAuth(AuthConfig{Enabled: true, Tokens: []string{"deploy-token"}})
+	router = handler.SetupRoutes()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/update", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("POST /api/update without a token status code = %v, want %v", w.Code, http.StatusUnauthorized)
+	}
+
+	req := httptest.NewRequest("GET", "/api/audit", nil)
+	req.Header.Set("Authorization", "Bearer deploy-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var entries []events.AuditEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("GetAuditLog() response is not valid JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].StatusCode != http.StatusOK {
+		t.Errorf("GetAuditLog() = %+v, want only the accepted POST /api/up", entries)
+	}
+}
+
+func TestAuditLog_Filters(t *testing.T) {
+	handler, _, eventStore := setupTestHandlerWithEventStore(t)
+
+	now := time.Now().UTC()
+	_ = eventStore.StoreAuditEntry(events.AuditEntry{Timestamp: now.Add(-2 * time.Hour), Action: "POST /api/up"})
+	_ = eventStore.StoreAuditEntry(events.AuditEntry{Timestamp: now, Action: "POST /api/down"})
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"no filters", "", 2},
+		{"action", "?action=POST+/api/down", 1},
+		{"from", "?from=" + now.Add(-time.Hour).Format(time.RFC3339), 1},
+		{"to", "?to=" + now.Add(-time.Hour).Format(time.RFC3339), 1},
+		{"limit", "?limit=1", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/audit"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.GetAuditLog(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("GetAuditLog() status code = %v, want %v", w.Code, http.StatusOK)
+			}
+			var entries []events.AuditEntry
+			if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+				t.Fatalf("GetAuditLog() response is not valid JSON: %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("GetAuditLog() returned %d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditLog_InvalidParams(t *testing.T) {
+	handler, _, _ := setupTestHandlerWithEventStore(t)
+
+	for _, query := range []string{"?from=yesterday", "?limit=0", "?limit=1001"} {
+		req := httptest.NewRequest("GET", "/api/audit"+query, nil)
+		w := httptest.NewRecorder()
+		handler.GetAuditLog(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GetAuditLog(%s) status code = %v, want %v", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestAuditLog_NoEventStore(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilEventStore())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/audit", nil)
+	w := httptest.NewRecorder()
+	handler.GetAuditLog(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GetAuditLog() status code = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+}
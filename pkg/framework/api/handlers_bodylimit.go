@@ -0,0 +1,34 @@
+package api
+
+import "net/http"
+
+// bulkBodyLimitedPaths are the upload endpoints that use the higher bulk body limit
+var bulkBodyLimitedPaths = []string{"/api/manifests/bulk", "/api/manifests/import"}
+
+// SetBodyLimits configures the maximum request body size of the API and, separately, of the
+// bulk manifest uploads. Zero leaves that class of endpoints unlimited.
+func (h *Handler) SetBodyLimits(maxBytes, bulkMaxBytes int64) {
+	h.maxBodyBytes = maxBytes
+	h.maxBulkBodyBytes = bulkMaxBytes
+}
+
+// isBulkBodyLimited reports whether r targets a bulk upload endpoint
+func isBulkBodyLimited(r *http.Request) bool {
+	for _, path := range bulkBodyLimitedPaths {
+		if r.URL.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyLimitMiddleware applies the bulk body limit to bulk uploads and the default limit to the rest
+func (h *Handler) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxBytes := h.maxBodyBytes
+		if isBulkBodyLimited(r) {
+			maxBytes = h.maxBulkBodyBytes
+		}
+		MaxBodyMiddleware(maxBytes)(next).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// CanaryLabel marks a canary Deployment, its selector and its pods
+const CanaryLabel = "canary"
+
+// CanaryWeightAnnotation records the weight a canary Deployment was promoted with
+const CanaryWeightAnnotation = "conductor.io/canary-weight"
+
+// DefaultCanaryWeight is the percentage of replicas a canary gets when ?weight= is omitted
+const DefaultCanaryWeight = 20
+
+// canarySuffix is appended to the main Deployment name to name its canary
+const canarySuffix = "-canary"
+
+// canaryTarget identifies the main Deployment of a service and its canary in the store
+type canaryTarget struct {
+	service   string
+	namespace string
+	mainKey   string
+	canaryKey string
+}
+
+// resolveCanaryTarget finds the Deployment named after the {service} URL parameter, in the
+// namespace given by ?namespace= or in the only namespace that stores such a Deployment
+func (h *Handler) resolveCanaryTarget(r *http.Request) (canaryTarget, error) {
+	service := chi.URLParam(r, "service")
+	if !isValidKubernetesName(service) {
+		return canaryTarget{}, fmt.Errorf("%w: %q", apperrors.ErrInvalidServiceName, service)
+	}
+
+	namespace := getQueryNamespace(r)
+	if namespace == "" {
+		var namespaces []string
+		for key := range h.store.ListByKind("Deployment") {
+			parts := strings.Split(key, "/")
+			if len(parts) == 3 && parts[2] == service {
+				namespaces = append(namespaces, parts[0])
+			}
+		}
+		switch len(namespaces) {
+		case 0:
+			return canaryTarget{}, fmt.Errorf("%w: no Deployment named %s", apperrors.ErrNotFound, service)
+		case 1:
+			namespace = namespaces[0]
+		default:
+			return canaryTarget{}, fmt.Errorf("%w: Deployment %s exists in several namespaces, set ?namespace=", apperrors.ErrMissingParameter, service)
+		}
+	} else if !isValidKubernetesName(namespace) {
+		return canaryTarget{}, fmt.Errorf("%w: %q", apperrors.ErrInvalidNamespace, namespace)
+	}
+
+	return canaryTarget{
+		service:   service,
+		namespace: namespace,
+		mainKey:   fmt.Sprintf("%s/Deployment/%s", namespace, service),
+		canaryKey: fmt.Sprintf("%s/Deployment/%s%s", namespace, service, canarySuffix),
+	}, nil
+}
+
+// PromoteCanary creates {name}-canary from the main Deployment of a service with ?weight=
+// percent of its replicas and a canary: "true" label, stores it and deploys it
+func (h *Handler) PromoteCanary(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	weight := DefaultCanaryWeight
+	if weightStr := r.URL.Query().Get("weight"); weightStr != "" {
+		parsed, err := strconv.Atoi(weightStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			WriteError(w, h.logger, fmt.Errorf("%w: weight must be an integer between 1 and 100", apperrors.ErrInvalidParameter))
+			return
+		}
+		weight = parsed
+	}
+
+	target, err := h.resolveCanaryTarget(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if _, exists := h.store.Get(target.canaryKey); exists {
+		WriteErrorResponse(w, h.logger, http.StatusConflict, "canary_exists", fmt.Sprintf("Canary %s already exists; finalize or roll it back first", target.canaryKey), nil)
+		return
+	}
+
+	mainYAML, ok := h.store.Get(target.mainKey)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, target.mainKey))
+		return
+	}
+	var deployment map[string]interface{}
+	if err := yaml.Unmarshal(mainYAML, &deployment); err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to parse %s: %w", apperrors.ErrInvalidYAML, target.mainKey, err))
+		return
+	}
+
+	mainReplicas := deploymentReplicas(deployment)
+	canary := buildCanaryDeployment(deployment, target.service+canarySuffix, canaryReplicas(mainReplicas, weight), weight)
+	canaryYAML, err := yaml.Marshal(canary)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to encode canary: %w", apperrors.ErrInvalid, err))
+		return
+	}
+
+	if err := h.store.Create(target.canaryKey, canaryYAML); err != nil {
+		h.logger.Error(err, "failed to store canary", "key", target.canaryKey)
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to store canary: %w", apperrors.ErrStorage, err))
+		return
+	}
+
+	ctx := h.beginDeploymentSession(r.Context(), "canary-promote", map[string][]byte{target.canaryKey: canaryYAML})
+	_, deployErr := rec.DeployManifests(ctx, map[string][]byte{target.canaryKey: canaryYAML})
+	h.endDeploymentSession(ctx, deployErr)
+	if deployErr != nil {
+		h.logger.Error(deployErr, "failed to deploy canary", "key", target.canaryKey)
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deployment_failed", fmt.Sprintf("Canary was stored but could not be deployed. Error: %s", deployErr.Error()), apperrors.Details(deployErr))
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusCreated, h.canaryStatus(r, target, canary, mainReplicas))
+}
+
+// GetCanary reports the canary of a service and the live status of its Deployment
+func (h *Handler) GetCanary(w http.ResponseWriter, r *http.Request) {
+	target, err := h.resolveCanaryTarget(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	canary, err := h.loadDeployment(target.canaryKey)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	mainReplicas := 0
+	if main, err := h.loadDeployment(target.mainKey); err == nil {
+		mainReplicas = deploymentReplicas(main)
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, h.canaryStatus(r, target, canary, mainReplicas))
+}
+
+// FinalizeCanary replaces the main Deployment spec with the canary spec, keeping the main
+// replica count and selector, and removes the canary Deployment
+func (h *Handler) FinalizeCanary(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	target, err := h.resolveCanaryTarget(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	canary, err := h.loadDeployment(target.canaryKey)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	main, err := h.loadDeployment(target.mainKey)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	promoted := promoteCanarySpec(main, canary)
+	promotedYAML, err := yaml.Marshal(promoted)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to encode %s: %w", apperrors.ErrInvalid, target.mainKey, err))
+		return
+	}
+	canaryYAML, _ := h.store.Get(target.canaryKey)
+
+	ctx := h.beginDeploymentSession(r.Context(), "canary-finalize", map[string][]byte{target.mainKey: promotedYAML})
+	var deployErr error
+	defer func() { h.endDeploymentSession(ctx, deployErr) }()
+
+	if err := h.store.Update(target.mainKey, promotedYAML); err != nil {
+		deployErr = fmt.Errorf("%w: failed to store %s: %w", apperrors.ErrStorage, target.mainKey, err)
+		WriteError(w, h.logger, deployErr)
+		return
+	}
+	if _, deployErr = rec.UpdateManifests(ctx, map[string][]byte{target.mainKey: promotedYAML}); deployErr != nil {
+		h.logger.Error(deployErr, "failed to update main deployment from canary", "key", target.mainKey)
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "update_failed", fmt.Sprintf("Update of %s failed; the canary was kept. Error: %s", target.mainKey, deployErr.Error()), apperrors.Details(deployErr))
+		return
+	}
+	if deployErr = h.removeCanary(ctx, rec, target.canaryKey, canaryYAML); deployErr != nil {
+		WriteError(w, h.logger, deployErr)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("Canary of %s promoted to %s", target.service, target.mainKey),
+	})
+}
+
+// RollbackCanary deletes the canary Deployment of a service and leaves the main one untouched
+func (h *Handler) RollbackCanary(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	target, err := h.resolveCanaryTarget(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	canaryYAML, ok := h.store.Get(target.canaryKey)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: no canary for %s", apperrors.ErrNotFound, target.mainKey))
+		return
+	}
+
+	ctx := h.beginDeploymentSession(r.Context(), "canary-rollback", map[string][]byte{target.canaryKey: canaryYAML})
+	err = h.removeCanary(ctx, rec, target.canaryKey, canaryYAML)
+	h.endDeploymentSession(ctx, err)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("Canary of %s removed", target.service),
+	})
+}
+
+// removeCanary deletes the canary Deployment from the cluster and from the store
+func (h *Handler) removeCanary(ctx context.Context, rec reconciler.Reconciler, key string, content []byte) error {
+	if err := rec.DeleteManifests(ctx, map[string][]byte{key: content}); err != nil {
+		return fmt.Errorf("%w: failed to delete canary %s: %w", apperrors.ErrKubernetes, key, err)
+	}
+	if err := h.store.Delete(key); err != nil {
+		return fmt.Errorf("%w: failed to remove canary %s from the store: %w", apperrors.ErrStorage, key, err)
+	}
+	return nil
+}
+
+// loadDeployment reads and parses the Deployment manifest stored under key
+func (h *Handler) loadDeployment(key string) (map[string]interface{}, error) {
+	content, ok := h.store.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key)
+	}
+	var deployment map[string]interface{}
+	if err := yaml.Unmarshal(content, &deployment); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s: %w", apperrors.ErrInvalidYAML, key, err)
+	}
+	return deployment, nil
+}
+
+// canaryStatus summarizes a canary Deployment, with its live status when a reconciler is available
+func (h *Handler) canaryStatus(r *http.Request, target canaryTarget, canary map[string]interface{}, mainReplicas int) CanaryStatus {
+	status := CanaryStatus{
+		Service:      target.service,
+		Namespace:    target.namespace,
+		Key:          target.canaryKey,
+		Replicas:     deploymentReplicas(canary),
+		MainReplicas: mainReplicas,
+	}
+	if annotations, ok := nestedMap(canary, "metadata", "annotations"); ok {
+		if weight, ok := annotations[CanaryWeightAnnotation].(string); ok {
+			status.Weight, _ = strconv.Atoi(weight)
+		}
+	}
+	if rec := h.reconcilerFor(r); rec != nil {
+		live := h.resourceStatus(r.Context(), rec, target.canaryKey)
+		status.Live = &live
+	}
+	return status
+}
+
+// canaryReplicas returns weight percent of replicas, rounded up to at least one replica
+func canaryReplicas(replicas, weight int) int {
+	n := int(math.Ceil(float64(replicas) * float64(weight) / 100))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// deploymentReplicas returns .spec.replicas, which Kubernetes defaults to one
+func deploymentReplicas(deployment map[string]interface{}) int {
+	spec, _ := nestedMap(deployment, "spec")
+	switch replicas := spec["replicas"].(type) {
+	case int:
+		return replicas
+	case float64:
+		return int(replicas)
+	default:
+		return 1
+	}
+}
+
+// buildCanaryDeployment copies a Deployment under name with replicas replicas and adds the
+// canary label to its metadata, selector and pod template
+func buildCanaryDeployment(main map[string]interface{}, name string, replicas, weight int) map[string]interface{} {
+	canary := deepCopyMapInterface(main)
+
+	metadata := ensureMap(canary, "metadata")
+	metadata["name"] = name
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields"} {
+		delete(metadata, field)
+	}
+	delete(canary, "status")
+	ensureMap(metadata, "labels")[CanaryLabel] = "true"
+	ensureMap(metadata, "annotations")[CanaryWeightAnnotation] = strconv.Itoa(weight)
+
+	spec := ensureMap(canary, "spec")
+	spec["replicas"] = replicas
+	ensureMap(ensureMap(spec, "selector"), "matchLabels")[CanaryLabel] = "true"
+	ensureMap(ensureMap(ensureMap(spec, "template"), "metadata"), "labels")[CanaryLabel] = "true"
+	return canary
+}
+
+// promoteCanarySpec returns main with the spec of canary, keeping the replica count and
+// selector of main and dropping the canary label from the pod template
+func promoteCanarySpec(main, canary map[string]interface{}) map[string]interface{} {
+	promoted := deepCopyMapInterface(main)
+	mainSpec, _ := nestedMap(main, "spec")
+
+	spec := ensureMap(deepCopyMapInterface(canary), "spec")
+	if replicas, ok := mainSpec["replicas"]; ok {
+		spec["replicas"] = replicas
+	} else {
+		delete(spec, "replicas")
+	}
+	if selector, ok := mainSpec["selector"]; ok {
+		spec["selector"] = selector
+	}
+	delete(ensureMap(ensureMap(ensureMap(spec, "template"), "metadata"), "labels"), CanaryLabel)
+
+	promoted["spec"] = spec
+	return promoted
+}
+
+// nestedMap returns the map at fields within obj
+func nestedMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool) {
+	current := obj
+	for _, field := range fields {
+		next, ok := current[field].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// ensureMap returns obj[field] as a map, creating it when missing
+func ensureMap(obj map[string]interface{}, field string) map[string]interface{} {
+	if m, ok := obj[field].(map[string]interface{}); ok {
+		return m
+	}
+	m := make(map[string]interface{})
+	obj[field] = m
+	return m
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const canaryTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  labels:
+    app: web
+spec:
+  replicas: 10
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: web:1.0
+`
+
+func newCanaryTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{}, nil
+	})
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.store.Create("default/Deployment/web", []byte(canaryTestDeployment)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	return handler
+}
+
+func storedDeployment(t *testing.T, handler *Handler, key string) map[string]interface{} {
+	t.Helper()
+	deployment, err := handler.loadDeployment(key)
+	if err != nil {
+		t.Fatalf("loadDeployment(%s) error = %v", key, err)
+	}
+	return deployment
+}
+
+func TestPromoteCanary(t *testing.T) {
+	handler := newCanaryTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/canary/promote/web?weight=20", nil))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("PromoteCanary() status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var status CanaryStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("PromoteCanary() response is not valid JSON: %v", err)
+	}
+	if status.Key != "default/Deployment/web-canary" || status.Replicas != 2 || status.MainReplicas != 10 || status.Weight != 20 {
+		t.Errorf("PromoteCanary() = %+v, want key default/Deployment/web-canary with 2 of 10 replicas at weight 20", status)
+	}
+
+	canary := storedDeployment(t, handler, "default/Deployment/web-canary")
+	if name, _ := nestedMap(canary, "metadata"); name["name"] != "web-canary" {
+		t.Errorf("canary name = %v, want web-canary", name["name"])
+	}
+	if got := deploymentReplicas(canary); got != 2 {
+		t.Errorf("canary replicas = %d, want 2", got)
+	}
+	for _, path := range [][]string{
+		{"metadata", "labels"},
+		{"spec", "selector", "matchLabels"},
+		{"spec", "template", "metadata", "labels"},
+	} {
+		labels, _ := nestedMap(canary, path...)
+		if labels[CanaryLabel] != "true" || labels["app"] != "web" {
+			t.Errorf("canary %v = %v, want app: web and canary: \"true\"", path, labels)
+		}
+	}
+
+	main := storedDeployment(t, handler, "default/Deployment/web")
+	if labels, _ := nestedMap(main, "metadata", "labels"); labels[CanaryLabel] != nil {
+		t.Errorf("main Deployment labels = %v, want them unchanged", labels)
+	}
+}
+
+func TestPromoteCanary_RoundsUpToOneReplica(t *testing.T) {
+	for _, tt := range []struct {
+		replicas, weight, want int
+	}{
+		{10, 20, 2},
+		{3, 20, 1},
+		{1, 1, 1},
+		{5, 50, 3},
+		{4, 100, 4},
+	} {
+		if got := canaryReplicas(tt.replicas, tt.weight); got != tt.want {
+			t.Errorf("canaryReplicas(%d, %d) = %d, want %d", tt.replicas, tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestPromoteCanary_Errors(t *testing.T) {
+	handler := newCanaryTestHandler(t)
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/canary/promote/web?weight=0", http.StatusBadRequest},
+		{"/api/canary/promote/web?weight=101", http.StatusBadRequest},
+		{"/api/canary/promote/missing", http.StatusNotFound},
+		{"/api/canary/promote/web", http.StatusCreated},
+		{"/api/canary/promote/web", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("POST %s status = %d, want %d: %s", tt.path, w.Code, tt.want, w.Body.String())
+		}
+	}
+}
+
+func TestGetCanary(t *testing.T) {
+	handler := newCanaryTestHandler(t)
+	router := handler.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/canary/web", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetCanary() without canary status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/canary/promote/web?weight=50", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/canary/web?namespace=default", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetCanary() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var status CanaryStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("GetCanary() response is not valid JSON: %v", err)
+	}
+	if status.Weight != 50 || status.Replicas != 5 || status.MainReplicas != 10 || status.Live == nil {
+		t.Errorf("GetCanary() = %+v, want weight 50 with 5 of 10 replicas and a live status", status)
+	}
+}
+
+func TestFinalizeCanary(t *testing.T) {
+	handler := newCanaryTestHandler(t)
+	router := handler.SetupRoutes()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/canary/promote/web", nil))
+
+	// Roll a new image out to the canary only
+	canary := storedDeployment(t, handler, "default/Deployment/web-canary")
+	containers := canary["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	containers[0].(map[string]interface{})["image"] = "web:2.0"
+	updated, _ := yaml.Marshal(canary)
+	if err := handler.store.Update("default/Deployment/web-canary", updated); err != nil {
+		t.Fatalf("failed to update canary: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/canary/finalize/web", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("FinalizeCanary() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if _, ok := handler.store.Get("default/Deployment/web-canary"); ok {
+		t.Error("FinalizeCanary() kept the canary manifest")
+	}
+	main := storedDeployment(t, handler, "default/Deployment/web")
+	if got := deploymentReplicas(main); got != 10 {
+		t.Errorf("main replicas = %d, want 10", got)
+	}
+	mainContainers, _ := nestedMap(main, "spec", "template", "spec")
+	if image := mainContainers["containers"].([]interface{})[0].(map[string]interface{})["image"]; image != "web:2.0" {
+		t.Errorf("main image = %v, want the canary image web:2.0", image)
+	}
+	for _, path := range [][]string{{"metadata", "labels"}, {"spec", "selector", "matchLabels"}, {"spec", "template", "metadata", "labels"}} {
+		if labels, _ := nestedMap(main, path...); labels[CanaryLabel] != nil {
+			t.Errorf("main %v = %v, want no canary label", path, labels)
+		}
+	}
+	if name, _ := nestedMap(main, "metadata"); name["name"] != "web" {
+		t.Errorf("main name = %v, want web", name["name"])
+	}
+}
+
+func TestRollbackCanary(t *testing.T) {
+	handler := newCanaryTestHandler(t)
+	router := handler.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/canary/rollback/web", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("RollbackCanary() without canary status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/canary/promote/web", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/canary/rollback/web", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("RollbackCanary() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if _, ok := handler.store.Get("default/Deployment/web-canary"); ok {
+		t.Error("RollbackCanary() kept the canary manifest")
+	}
+	if main := storedDeployment(t, handler, "default/Deployment/web"); deploymentReplicas(main) != 10 {
+		t.Error("RollbackCanary() changed the main Deployment")
+	}
+}
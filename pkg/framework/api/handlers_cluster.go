@@ -21,12 +21,13 @@ func (h *Handler) ClusterRequirements(w http.ResponseWriter, r *http.Request) {
 
 	requirements := []ClusterRequirement{}
 
-	if h.reconciler == nil {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
 		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "reconciler_not_available", "Reconciler not available", nil)
 		return
 	}
 
-	clientset := h.reconciler.GetClientset()
+	clientset := rec.GetClientset()
 	if clientset == nil {
 		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "clientset_not_available", "Kubernetes client not available", nil)
 		return
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// SetSkipCapacityCheck disables the check in Up that the cluster has room for the resource
+// requests of the workloads being deployed
+func (h *Handler) SetSkipCapacityCheck(skip bool) {
+	h.skipCapacityCheck = skip
+}
+
+// ClusterCapacity reports the allocatable CPU and memory of the Ready nodes and how much of
+// it is not yet requested by running pods
+func (h *Handler) ClusterCapacity(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "reconciler_not_available", "Reconciler not available", nil)
+		return
+	}
+	clientset := rec.GetClientset()
+	if clientset == nil {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "clientset_not_available", "Kubernetes client not available", nil)
+		return
+	}
+
+	cluster, err := clusterCapacity(r.Context(), clientset)
+	if err != nil {
+		h.logger.Error(err, "failed to compute cluster capacity")
+		WriteError(w, h.logger, err)
+		return
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, cluster.response())
+}
+
+// capacity holds CPU and memory quantities
+type capacity struct {
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+func newCapacity() capacity {
+	return capacity{
+		cpu:    *resource.NewQuantity(0, resource.DecimalSI),
+		memory: *resource.NewQuantity(0, resource.BinarySI),
+	}
+}
+
+func (c *capacity) add(list corev1.ResourceList) {
+	c.cpu.Add(list[corev1.ResourceCPU])
+	c.memory.Add(list[corev1.ResourceMemory])
+}
+
+func (c *capacity) addScaled(other capacity, n int64) {
+	for i := int64(0); i < n; i++ {
+		c.cpu.Add(other.cpu)
+		c.memory.Add(other.memory)
+	}
+}
+
+func (c capacity) isZero() bool {
+	return c.cpu.IsZero() && c.memory.IsZero()
+}
+
+// fits reports whether c is within available for both CPU and memory
+func (c capacity) fits(available capacity) bool {
+	return c.cpu.Cmp(available.cpu) <= 0 && c.memory.Cmp(available.memory) <= 0
+}
+
+func (c capacity) amounts() ResourceAmounts {
+	return ResourceAmounts{CPU: c.cpu.String(), Memory: c.memory.String()}
+}
+
+// nodeCapacity is the capacity of the Ready nodes of a cluster
+type nodeCapacity struct {
+	readyNodes int
+	total      capacity
+	available  capacity
+}
+
+func (n nodeCapacity) response() ClusterCapacityResponse {
+	return ClusterCapacityResponse{
+		ReadyNodes: n.readyNodes,
+		Total:      n.total.amounts(),
+		Available:  n.available.amounts(),
+	}
+}
+
+// clusterCapacity sums the allocatable resources of the Ready nodes and subtracts the requests
+// of the pods scheduled on them that have not terminated
+func clusterCapacity(ctx context.Context, clientset kubernetes.Interface) (nodeCapacity, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nodeCapacity{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	result := nodeCapacity{total: newCapacity(), available: newCapacity()}
+	readyNodes := make(map[string]bool)
+	for _, node := range nodes.Items {
+		if !isNodeReady(node) {
+			continue
+		}
+		readyNodes[node.Name] = true
+		result.readyNodes++
+		result.total.add(node.Status.Allocatable)
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nodeCapacity{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	requested := newCapacity()
+	for _, pod := range pods.Items {
+		if !readyNodes[pod.Spec.NodeName] || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requested.addScaled(podRequests(pod.Spec), 1)
+	}
+
+	result.available = capacity{cpu: result.total.cpu.DeepCopy(), memory: result.total.memory.DeepCopy()}
+	result.available.cpu.Sub(requested.cpu)
+	result.available.memory.Sub(requested.memory)
+	return result, nil
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podRequests returns the effective requests of a pod: the larger of the summed container
+// requests and the largest init container request, per resource
+func podRequests(spec corev1.PodSpec) capacity {
+	requests := newCapacity()
+	for _, container := range spec.Containers {
+		requests.add(container.Resources.Requests)
+	}
+	for _, container := range spec.InitContainers {
+		if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok && cpu.Cmp(requests.cpu) > 0 {
+			requests.cpu = cpu.DeepCopy()
+		}
+		if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok && memory.Cmp(requests.memory) > 0 {
+			requests.memory = memory.DeepCopy()
+		}
+	}
+	return requests
+}
+
+// requiredCapacity sums the pod requests of the Deployments and StatefulSets in manifests
+// times their replicas. Workloads that already run count only the replicas or requests they add.
+func requiredCapacity(ctx context.Context, clientset kubernetes.Interface, manifests map[string][]byte) capacity {
+	required := newCapacity()
+	decoder := scheme.Codecs.UniversalDeserializer()
+	for _, content := range manifests {
+		obj, _, err := decoder.Decode(content, nil, nil)
+		if err != nil {
+			continue
+		}
+
+		// current stays zero for workloads that do not exist yet or cannot be read
+		var desired, current capacity
+		switch workload := obj.(type) {
+		case *appsv1.Deployment:
+			desired = workloadRequests(workload.Spec.Replicas, workload.Spec.Template.Spec)
+			live, err := clientset.AppsV1().Deployments(namespaceOrDefault(workload.Namespace)).Get(ctx, workload.Name, metav1.GetOptions{})
+			if err == nil {
+				current = workloadRequests(live.Spec.Replicas, live.Spec.Template.Spec)
+			}
+		case *appsv1.StatefulSet:
+			desired = workloadRequests(workload.Spec.Replicas, workload.Spec.Template.Spec)
+			live, err := clientset.AppsV1().StatefulSets(namespaceOrDefault(workload.Namespace)).Get(ctx, workload.Name, metav1.GetOptions{})
+			if err == nil {
+				current = workloadRequests(live.Spec.Replicas, live.Spec.Template.Spec)
+			}
+		default:
+			continue
+		}
+
+		if cpu := desired.cpu.DeepCopy(); current.cpu.Cmp(cpu) < 0 {
+			cpu.Sub(current.cpu)
+			required.cpu.Add(cpu)
+		}
+		if memory := desired.memory.DeepCopy(); current.memory.Cmp(memory) < 0 {
+			memory.Sub(current.memory)
+			required.memory.Add(memory)
+		}
+	}
+	return required
+}
+
+// workloadRequests returns the pod requests of a workload template times its replicas, which default to one
+func workloadRequests(replicas *int32, spec corev1.PodSpec) capacity {
+	n := int64(1)
+	if replicas != nil {
+		n = int64(*replicas)
+	}
+	total := newCapacity()
+	total.addScaled(podRequests(spec), n)
+	return total
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return namespace
+}
+
+// checkCapacity writes a 409 insufficient_capacity response and returns false when the Ready
+// nodes cannot fit the requests of the workloads in manifests. Failing to read the cluster
+// capacity does not block the deployment.
+func (h *Handler) checkCapacity(w http.ResponseWriter, r *http.Request, clientset kubernetes.Interface, manifests map[string][]byte) bool {
+	if h.skipCapacityCheck || clientset == nil {
+		return true
+	}
+
+	required := requiredCapacity(r.Context(), clientset, manifests)
+	if required.isZero() {
+		return true
+	}
+
+	cluster, err := clusterCapacity(r.Context(), clientset)
+	if err != nil {
+		h.logger.Error(err, "failed to compute cluster capacity, skipping capacity check")
+		return true
+	}
+	if required.fits(cluster.available) {
+		return true
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusConflict, CapacityErrorResponse{
+		Error:     "insufficient_capacity",
+		Message:   "The Ready nodes do not have enough allocatable CPU or memory for the requested workloads",
+		Required:  required.amounts(),
+		Available: cluster.available.amounts(),
+	})
+	return false
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const capacityTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: default
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: api
+        image: api:1.0
+        resources:
+          requests:
+            cpu: %s
+            memory: 1Gi
+`
+
+func capacityTestNode(name, cpu, memory string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+// newCapacityTestHandler returns a handler whose cluster has two Ready 2 CPU / 8Gi nodes, one
+// NotReady node and a running pod requesting 1 CPU / 2Gi
+func newCapacityTestHandler(t *testing.T, cpuRequest string) *Handler {
+	t.Helper()
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	clientset := rec.GetClientset().(*kubefake.Clientset)
+	ctx := context.Background()
+	for _, node := range []*corev1.Node{
+		capacityTestNode("node-a", "2", "8Gi", true),
+		capacityTestNode("node-b", "2", "8Gi", true),
+		capacityTestNode("node-c", "16", "64Gi", false),
+	} {
+		if _, err := clientset.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create node %s: %v", node.Name, err)
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-a",
+			Containers: []corev1.Container{{
+				Name: "existing",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if _, err := clientset.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	manifest := []byte(fmt.Sprintf(capacityTestDeployment, cpuRequest))
+	if err := handler.store.Create("default/Deployment/api", manifest); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	return handler
+}
+
+func TestClusterCapacity(t *testing.T) {
+	handler := newCapacityTestHandler(t, "500m")
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/cluster/capacity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ClusterCapacity() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var response ClusterCapacityResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("ClusterCapacity() response is not valid JSON: %v", err)
+	}
+	if response.ReadyNodes != 2 {
+		t.Errorf("ReadyNodes = %d, want 2", response.ReadyNodes)
+	}
+	if response.Total != (ResourceAmounts{CPU: "4", Memory: "16Gi"}) {
+		t.Errorf("Total = %+v, want 4 CPU and 16Gi", response.Total)
+	}
+	if response.Available != (ResourceAmounts{CPU: "3", Memory: "14Gi"}) {
+		t.Errorf("Available = %+v, want 3 CPU and 14Gi", response.Available)
+	}
+}
+
+func TestUp_InsufficientCapacity(t *testing.T) {
+	// 3 replicas of 2 CPU need 6 CPU while 3 are available
+	handler := newCapacityTestHandler(t, "2")
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Up() status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body.String())
+	}
+	var response CapacityErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Up() response is not valid JSON: %v", err)
+	}
+	if response.Error != "insufficient_capacity" {
+		t.Errorf("Error = %q, want insufficient_capacity", response.Error)
+	}
+	if response.Required != (ResourceAmounts{CPU: "6", Memory: "3Gi"}) {
+		t.Errorf("Required = %+v, want 6 CPU and 3Gi", response.Required)
+	}
+	if response.Available != (ResourceAmounts{CPU: "3", Memory: "14Gi"}) {
+		t.Errorf("Available = %+v, want 3 CPU and 14Gi", response.Available)
+	}
+}
+
+func TestUp_SufficientCapacity(t *testing.T) {
+	handler := newCapacityTestHandler(t, "500m")
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Up() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
+
+func TestUp_SkipCapacityCheck(t *testing.T) {
+	handler := newCapacityTestHandler(t, "2")
+	handler.SetSkipCapacityCheck(true)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Up() with the check skipped status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// namespaceCacheTTL is how long a ListNamespaces response is served from cache
+const namespaceCacheTTL = 10 * time.Second
+
+// namespaceCache holds recent ListNamespaces results keyed by label selector. The zero value is an empty cache.
+type namespaceCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedNamespaces
+}
+
+type cachedNamespaces struct {
+	fetchedAt  time.Time
+	namespaces []NamespaceSummary
+}
+
+// ListNamespaces lists the cluster namespaces, optionally filtered with ?label= (a label selector)
+func (h *Handler) ListNamespaces(w http.ResponseWriter, r *http.Request) {
+	clientset := h.namespaceClientset(w, r)
+	if clientset == nil {
+		return
+	}
+
+	selector := r.URL.Query().Get("label")
+	if _, err := labels.Parse(selector); err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid label selector %q: %w", apperrors.ErrInvalidParameter, selector, err))
+		return
+	}
+
+	h.namespaces.mu.Lock()
+	defer h.namespaces.mu.Unlock()
+
+	if entry, ok := h.namespaces.entries[selector]; ok && time.Since(entry.fetchedAt) < namespaceCacheTTL {
+		WriteJSONResponse(w, h.logger, http.StatusOK, entry.namespaces)
+		return
+	}
+
+	list, err := clientset.CoreV1().Namespaces().List(r.Context(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		h.logger.Error(err, "failed to list namespaces")
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to list namespaces: %w", apperrors.ErrKubernetes, err))
+		return
+	}
+
+	namespaces := make([]NamespaceSummary, 0, len(list.Items))
+	for i := range list.Items {
+		namespaces = append(namespaces, namespaceSummary(&list.Items[i]))
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+
+	if h.namespaces.entries == nil {
+		h.namespaces.entries = make(map[string]cachedNamespaces)
+	}
+	h.namespaces.entries[selector] = cachedNamespaces{fetchedAt: time.Now(), namespaces: namespaces}
+	WriteJSONResponse(w, h.logger, http.StatusOK, namespaces)
+}
+
+// GetNamespace returns a namespace together with the usage of its resource quotas
+func (h *Handler) GetNamespace(w http.ResponseWriter, r *http.Request) {
+	clientset := h.namespaceClientset(w, r)
+	if clientset == nil {
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if !isValidKubernetesName(name) {
+		WriteError(w, h.logger, fmt.Errorf("%w: %q", apperrors.ErrInvalidNamespace, name))
+		return
+	}
+
+	ctx := r.Context()
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			WriteError(w, h.logger, fmt.Errorf("%w: namespace %s", apperrors.ErrNotFound, name))
+			return
+		}
+		h.logger.Error(err, "failed to get namespace", "namespace", name)
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to get namespace %s: %w", apperrors.ErrKubernetes, name, err))
+		return
+	}
+
+	quotas, err := clientset.CoreV1().ResourceQuotas(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.logger.Error(err, "failed to list resource quotas", "namespace", name)
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to list resource quotas in %s: %w", apperrors.ErrKubernetes, name, err))
+		return
+	}
+
+	details := NamespaceDetails{
+		NamespaceSummary: namespaceSummary(ns),
+		Annotations:      ns.Annotations,
+		ResourceQuotas:   make([]ResourceQuotaUsage, 0, len(quotas.Items)),
+	}
+	for _, quota := range quotas.Items {
+		details.ResourceQuotas = append(details.ResourceQuotas, ResourceQuotaUsage{
+			Name: quota.Name,
+			Hard: resourceListStrings(quota.Status.Hard),
+			Used: resourceListStrings(quota.Status.Used),
+		})
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, details)
+}
+
+// namespaceClientset returns the Kubernetes client of the cluster r selected, or writes a 503
+// and returns nil when there is none
+func (h *Handler) namespaceClientset(w http.ResponseWriter, r *http.Request) kubernetes.Interface {
+	rec := h.reconcilerFor(r)
+	if rec == nil || rec.GetClientset() == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "clientset_not_available", "Kubernetes client not available", nil)
+		return nil
+	}
+	return rec.GetClientset()
+}
+
+func namespaceSummary(ns *corev1.Namespace) NamespaceSummary {
+	return NamespaceSummary{
+		Name:      ns.Name,
+		Status:    string(ns.Status.Phase),
+		CreatedAt: ns.CreationTimestamp.Time,
+		Labels:    ns.Labels,
+	}
+}
+
+func resourceListStrings(resources corev1.ResourceList) map[string]string {
+	result := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
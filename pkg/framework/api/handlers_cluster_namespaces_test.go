@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNamespacesTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	ctx := context.Background()
+	clientset := rec.GetClientset()
+	for _, ns := range []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a", "env": "prod"}}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b", "env": "dev"}}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+	} {
+		if _, err := clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create namespace %s: %v", ns.Name, err)
+		}
+	}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "team-a"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+			Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("3")},
+		},
+	}
+	if _, err := clientset.CoreV1().ResourceQuotas("team-a").Create(ctx, quota, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create resource quota: %v", err)
+	}
+	return handler
+}
+
+func listNamespaceNames(t *testing.T, handler *Handler, query string) []string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/cluster/namespaces"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListNamespaces() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var namespaces []NamespaceSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &namespaces); err != nil {
+		t.Fatalf("ListNamespaces() response is not valid JSON: %v", err)
+	}
+	names := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Status != "Active" {
+			t.Errorf("namespace %s status = %q, want Active", ns.Name, ns.Status)
+		}
+		names = append(names, ns.Name)
+	}
+	return names
+}
+
+func TestListNamespaces(t *testing.T) {
+	handler := newNamespacesTestHandler(t)
+
+	if got := listNamespaceNames(t, handler, ""); len(got) != 2 || got[0] != "team-a" || got[1] != "team-b" {
+		t.Errorf("namespaces = %v, want [team-a team-b]", got)
+	}
+	if got := listNamespaceNames(t, handler, "?label=env%3Ddev"); len(got) != 1 || got[0] != "team-b" {
+		t.Errorf("namespaces with env=dev = %v, want [team-b]", got)
+	}
+}
+
+func TestListNamespaces_Cached(t *testing.T) {
+	handler := newNamespacesTestHandler(t)
+	if got := listNamespaceNames(t, handler, ""); len(got) != 2 {
+		t.Fatalf("namespaces = %v, want 2", got)
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-c"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}}
+	if _, err := handler.reconciler.GetClientset().CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create namespace: %v", err)
+	}
+	if got := listNamespaceNames(t, handler, ""); len(got) != 2 {
+		t.Errorf("namespaces within the cache TTL = %v, want the 2 cached ones", got)
+	}
+}
+
+func TestListNamespaces_InvalidSelector(t *testing.T) {
+	handler := newNamespacesTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/cluster/namespaces?label=env+in+(dev", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ListNamespaces() status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestListNamespaces_NoClient(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	for _, path := range []string{"/api/cluster/namespaces", "/api/cluster/namespaces/default"} {
+		w := httptest.NewRecorder()
+		handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func TestGetNamespace(t *testing.T) {
+	handler := newNamespacesTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/cluster/namespaces/team-a", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetNamespace() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var details NamespaceDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &details); err != nil {
+		t.Fatalf("GetNamespace() response is not valid JSON: %v", err)
+	}
+	if details.Name != "team-a" || details.Labels["team"] != "a" {
+		t.Errorf("GetNamespace() = %+v, want team-a with its labels", details.NamespaceSummary)
+	}
+	if len(details.ResourceQuotas) != 1 {
+		t.Fatalf("ResourceQuotas = %+v, want 1", details.ResourceQuotas)
+	}
+	quota := details.ResourceQuotas[0]
+	if quota.Name != "compute" || quota.Hard["pods"] != "10" || quota.Used["pods"] != "3" {
+		t.Errorf("ResourceQuotas[0] = %+v, want compute with 3 of 10 pods used", quota)
+	}
+}
+
+func TestGetNamespace_NotFound(t *testing.T) {
+	handler := newNamespacesTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/cluster/namespaces/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetNamespace() status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
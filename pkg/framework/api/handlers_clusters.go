@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+type clusterContextKey struct{}
+
+// selectedCluster is the cluster a request selected with ?cluster=
+type selectedCluster struct {
+	name       string
+	reconciler reconciler.Reconciler
+}
+
+// SetClusters registers the named clusters that deployment and status endpoints select with
+// ?cluster=<name>. Without clusters every request uses the handler's reconciler.
+func (h *Handler) SetClusters(clusters *reconciler.MultiReconciler) {
+	h.clusters = clusters
+}
+
+// clusterMiddleware resolves ?cluster= to the reconciler of that cluster; unknown names get a 404
+func (h *Handler) clusterMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("cluster")
+		if name == "" || name == reconciler.DefaultClusterName {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var rec reconciler.Reconciler
+		if h.clusters != nil {
+			rec, _ = h.clusters.Get(name)
+		}
+		if rec == nil {
+			WriteError(w, h.logger, fmt.Errorf("%w: cluster %s is not configured", apperrors.ErrNotFound, name))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), clusterContextKey{}, selectedCluster{name: name, reconciler: rec})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// reconcilerFor returns the reconciler of the cluster selected by r, defaulting to the handler's
+func (h *Handler) reconcilerFor(r *http.Request) reconciler.Reconciler {
+	if selected, ok := r.Context().Value(clusterContextKey{}).(selectedCluster); ok {
+		return selected.reconciler
+	}
+	return h.reconciler
+}
+
+// clusterNameFor returns the name of the cluster selected by r
+func clusterNameFor(r *http.Request) string {
+	if selected, ok := r.Context().Value(clusterContextKey{}).(selectedCluster); ok {
+		return selected.name
+	}
+	return reconciler.DefaultClusterName
+}
+
+// ListClusters reports every configured cluster and whether its reconciler is ready
+func (h *Handler) ListClusters(w http.ResponseWriter, r *http.Request) {
+	clusters := []ClusterInfo{}
+	if h.clusters == nil {
+		if h.reconciler != nil {
+			clusters = append(clusters, ClusterInfo{Name: reconciler.DefaultClusterName, Default: true, Ready: h.reconciler.IsReady()})
+		}
+		WriteJSONResponse(w, h.logger, http.StatusOK, clusters)
+		return
+	}
+
+	for _, name := range h.clusters.Names() {
+		rec, _ := h.clusters.Get(name)
+		clusters = append(clusters, ClusterInfo{
+			Name:    name,
+			Default: name == reconciler.DefaultClusterName,
+			Ready:   rec.IsReady(),
+		})
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, clusters)
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// newMultiClusterTestHandler returns a handler for a "default" and an "east" cluster, each
+// with its own fake dynamic client
+func newMultiClusterTestHandler(t *testing.T) (*Handler, *dynamicfake.FakeDynamicClient, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	defaultRec, defaultClient := setupTestReconcilerWithDynamicClient(t, true)
+	eastRec, eastClient := setupTestReconcilerWithDynamicClient(t, false)
+
+	handler, err := newTestHandler(t, WithTestReconciler(defaultRec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	clusters, err := reconciler.NewMultiReconciler(map[string]reconciler.Reconciler{
+		reconciler.DefaultClusterName: defaultRec,
+		"east":                        eastRec,
+	})
+	if err != nil {
+		t.Fatalf("NewMultiReconciler() error = %v", err)
+	}
+	handler.SetClusters(clusters)
+
+	for _, client := range []*dynamicfake.FakeDynamicClient{defaultClient, eastClient} {
+		client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, &unstructured.Unstructured{}, nil
+		})
+	}
+	return handler, defaultClient, eastClient
+}
+
+func countPatches(client *dynamicfake.FakeDynamicClient) int {
+	patches := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" {
+			patches++
+		}
+	}
+	return patches
+}
+
+func TestUp_RoutesToSelectedCluster(t *testing.T) {
+	handler, defaultClient, eastClient := newMultiClusterTestHandler(t)
+	if err := handler.store.Create("default/Service/web", []byte(createTestManifest("Service", "web", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up?cluster=east", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := countPatches(eastClient); got != 1 {
+		t.Errorf("east cluster received %d applies, want 1", got)
+	}
+	if got := countPatches(defaultClient); got != 0 {
+		t.Errorf("default cluster received %d applies, want 0", got)
+	}
+
+	w = httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := countPatches(defaultClient); got != 1 {
+		t.Errorf("default cluster received %d applies without ?cluster=, want 1", got)
+	}
+}
+
+func TestResourceStatusByKey_RoutesToSelectedCluster(t *testing.T) {
+	handler, _, eastClient := newMultiClusterTestHandler(t)
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+	}}
+	deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	if _, err := eastClient.Resource(deploymentGVR).Namespace("default").Create(context.Background(), live, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create live Deployment: %v", err)
+	}
+
+	router := handler.SetupRoutes()
+	for cluster, wantExists := range map[string]bool{"": false, "default": false, "east": true} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/status/resources/default/Deployment/web?cluster="+cluster, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("ResourceStatusByKey(cluster=%q) status = %d, want %d", cluster, w.Code, http.StatusOK)
+		}
+		var status ResourceStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("ResourceStatusByKey() response is not valid JSON: %v", err)
+		}
+		if status.Exists != wantExists {
+			t.Errorf("ResourceStatusByKey(cluster=%q) exists = %v, want %v", cluster, status.Exists, wantExists)
+		}
+	}
+}
+
+func TestClusterSelection_UnknownCluster(t *testing.T) {
+	handler, defaultClient, _ := newMultiClusterTestHandler(t)
+	router := handler.SetupRoutes()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/api/up?cluster=west", nil),
+		httptest.NewRequest("GET", "/api/status/resources?cluster=west", nil),
+		httptest.NewRequest("GET", "/api/diff?cluster=west", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s %s status = %d, want %d", req.Method, req.URL, w.Code, http.StatusNotFound)
+		}
+	}
+	if got := countPatches(defaultClient); got != 0 {
+		t.Errorf("default cluster received %d applies for an unknown cluster, want 0", got)
+	}
+}
+
+func TestListClusters(t *testing.T) {
+	handler, _, _ := newMultiClusterTestHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/clusters", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListClusters() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var clusters []ClusterInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &clusters); err != nil {
+		t.Fatalf("ListClusters() response is not valid JSON: %v", err)
+	}
+	want := []ClusterInfo{
+		{Name: "default", Default: true, Ready: true},
+		{Name: "east", Ready: false},
+	}
+	if len(clusters) != len(want) {
+		t.Fatalf("ListClusters() = %+v, want %+v", clusters, want)
+	}
+	for i := range want {
+		if clusters[i] != want[i] {
+			t.Errorf("ListClusters()[%d] = %+v, want %+v", i, clusters[i], want[i])
+		}
+	}
+}
+
+func TestPauseReconciler_RoutesToSelectedCluster(t *testing.T) {
+	handler, _, _ := newMultiClusterTestHandler(t)
+	defaultRec, _ := handler.clusters.Get(reconciler.DefaultClusterName)
+	eastRec, _ := handler.clusters.Get("east")
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/reconciler/pause?cluster=east", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PauseReconciler() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if !eastRec.IsPaused() || defaultRec.IsPaused() {
+		t.Errorf("paused east = %v, default = %v, want only east paused", eastRec.IsPaused(), defaultRec.IsPaused())
+	}
+
+	w = httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/cluster/namespaces?cluster=west", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("ListNamespaces(cluster=west) status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
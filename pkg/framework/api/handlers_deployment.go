@@ -1,13 +1,21 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/garunski/conductor-framework/pkg/framework/diff"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+	"github.com/garunski/conductor-framework/pkg/framework/webhook"
+	"gopkg.in/yaml.v3"
 )
 
 func (h *Handler) Up(w http.ResponseWriter, r *http.Request) {
-	if h.reconciler == nil {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
 		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
 		return
 	}
@@ -22,6 +30,12 @@ func (h *Handler) Up(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+
+	waitOpts, err := parseWaitOptions(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
 	
 	manifests := h.store.List()
 	
@@ -45,33 +59,78 @@ func (h *Handler) Up(w http.ResponseWriter, r *http.Request) {
 		manifests = updatedManifests
 	}
 	
+	if isDryRun(r) {
+		message := "Dry run: deployment planned for all services"
+		if len(req.Services) > 0 {
+			message = fmt.Sprintf("Dry run: deployment planned for %d service(s): %s", len(req.Services), strings.Join(req.Services, ", "))
+		}
+		h.writeDryRunResponse(w, r, rec, message, manifestKeys(manifests), plannedActionUpdate, plannedActionCreate)
+		return
+	}
+
+	if !h.checkCapacity(w, r, rec.GetClientset(), manifests) {
+		return
+	}
+
+	ctx = h.beginDeploymentSession(ctx, "up", manifests)
+	var result reconciler.ReconciliationResult
+	var deployErr error
+	defer func() {
+		h.endDeploymentSession(ctx, deployErr)
+		h.notifyDeployment(ctx, "up", req.Services, deployErr)
+	}()
+
+	if deployErr = h.runDeployWebhooks(ctx, webhook.StagePreDeploy, "up", req.Services, h.preDeployWebhooks); deployErr != nil {
+		h.logger.Error(deployErr, "pre-deploy webhook failed, aborting")
+		WriteErrorResponse(w, h.logger, http.StatusBadGateway, "webhook_failed", fmt.Sprintf("Pre-deploy webhook failed. Error: %s", deployErr.Error()), nil)
+		return
+	}
+
 	if len(req.Services) > 0 {
-		if err := h.reconciler.DeployManifests(ctx, manifests); err != nil {
-			h.logger.Error(err, "failed to deploy selected services")
+		result, deployErr = rec.DeployManifests(ctx, manifests)
+		if deployErr != nil {
+			h.logger.Error(deployErr, "failed to deploy selected services")
 			serviceList := strings.Join(req.Services, ", ")
-			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deployment_failed", fmt.Sprintf("Deployment failed for service(s): %s. Error: %s", serviceList, err.Error()), nil)
+			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deployment_failed", fmt.Sprintf("Deployment failed for service(s): %s. Error: %s", serviceList, deployErr.Error()), apperrors.Details(deployErr))
 			return
 		}
 		
+		if err := h.runDeployWebhooks(ctx, webhook.StagePostDeploy, "up", req.Services, h.postDeployWebhooks); err != nil {
+			h.logger.Error(err, "post-deploy webhook failed")
+		}
+
 		serviceList := strings.Join(req.Services, ", ")
-		WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{
-			"message": fmt.Sprintf("Deployment initiated for %d service(s): %s", len(req.Services), serviceList),
+		h.writeDeploymentResponse(w, r, waitOpts, manifests, DeploymentResponse{
+			Message:       fmt.Sprintf("Deployment initiated for %d service(s): %s", len(req.Services), serviceList),
+			TimedOutCount: result.TimedOutCount,
 		})
 		return
 	}
 	
 	// No services specified, deploy all using updated manifests with current namespace from CRD
-	if err := h.reconciler.DeployManifests(ctx, manifests); err != nil {
-		h.logger.Error(err, "failed to deploy all")
-		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deployment_failed", fmt.Sprintf("Deployment failed for all services. Error: %s", err.Error()), nil)
+	result, deployErr = rec.DeployManifests(ctx, manifests)
+	if deployErr != nil {
+		h.logger.Error(deployErr, "failed to deploy all")
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deployment_failed", fmt.Sprintf("Deployment failed for all services. Error: %s", deployErr.Error()), apperrors.Details(deployErr))
 		return
 	}
 
-	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{"message": "Deployment initiated for all services"})
+	if err := h.runDeployWebhooks(ctx, webhook.StagePostDeploy, "up", req.Services, h.postDeployWebhooks); err != nil {
+		h.logger.Error(err, "post-deploy webhook failed")
+	}
+
+	h.writeDeploymentResponse(w, r, waitOpts, manifests, DeploymentResponse{
+		Message:       "Deployment initiated for all services",
+		TimedOutCount: result.TimedOutCount,
+	})
 }
 
+// Down deletes the selected services, or every managed resource when none are selected. When
+// SetDownConfirmation is on, a call without a confirmation_token only returns a token and the
+// resources it would delete; calling Down again with that token carries out the deletion.
 func (h *Handler) Down(w http.ResponseWriter, r *http.Request) {
-	if h.reconciler == nil {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
 		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
 		return
 	}
@@ -86,6 +145,20 @@ func (h *Handler) Down(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+
+	// A confirmed deletion deletes the services the token was issued for
+	confirming := h.requireDownConfirmation && !isDryRun(r)
+	if confirming && req.ConfirmationToken == "" && isConfirm(r) {
+		WriteErrorResponse(w, h.logger, http.StatusBadRequest, "confirmation_token_required", "confirm=true requires a confirmation_token in the request body", nil)
+		return
+	}
+	if confirming && req.ConfirmationToken != "" {
+		confirmation, ok := h.consumeDownConfirmation(w, r, req.ConfirmationToken)
+		if !ok {
+			return
+		}
+		req.Services = confirmation.Services
+	}
 	
 	manifests := h.store.List()
 	
@@ -96,11 +169,41 @@ func (h *Handler) Down(w http.ResponseWriter, r *http.Request) {
 			WriteErrorResponse(w, h.logger, http.StatusBadRequest, "no_manifests", "No manifests found for selected services", nil)
 			return
 		}
-		
-		if err := h.reconciler.DeleteManifests(ctx, manifests); err != nil {
-			h.logger.Error(err, "failed to delete selected services")
+	}
+	
+	// Without a selection Down runs DeleteAll, which also deletes the managed resources whose
+	// manifests are gone; the confirmation and the dry run list exactly those keys
+	keys := manifestKeys(manifests)
+	if len(req.Services) == 0 {
+		keys = rec.DeleteAllKeys(ctx)
+	}
+
+	if confirming && req.ConfirmationToken == "" {
+		h.writeDownConfirmation(w, r, req.Services, keys)
+		return
+	}
+
+	if isDryRun(r) {
+		message := "Dry run: deletion planned for all services"
+		if len(req.Services) > 0 {
+			message = fmt.Sprintf("Dry run: deletion planned for %d service(s): %s", len(req.Services), strings.Join(req.Services, ", "))
+		}
+		h.writeDryRunResponse(w, r, rec, message, keys, plannedActionDelete, "")
+		return
+	}
+
+	ctx = h.beginDeploymentSession(ctx, "down", manifests)
+	var deployErr error
+	defer func() {
+		h.endDeploymentSession(ctx, deployErr)
+		h.notifyDeployment(ctx, "down", req.Services, deployErr)
+	}()
+
+	if len(req.Services) > 0 {
+		if deployErr = rec.DeleteManifests(ctx, manifests); deployErr != nil {
+			h.logger.Error(deployErr, "failed to delete selected services")
 			serviceList := strings.Join(req.Services, ", ")
-			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deletion_failed", fmt.Sprintf("Deletion failed for service(s): %s. Error: %s", serviceList, err.Error()), nil)
+			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deletion_failed", fmt.Sprintf("Deletion failed for service(s): %s. Error: %s", serviceList, deployErr.Error()), apperrors.Details(deployErr))
 			return
 		}
 		
@@ -112,9 +215,9 @@ func (h *Handler) Down(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// No services specified, delete all
-	if err := h.reconciler.DeleteAll(ctx); err != nil {
-		h.logger.Error(err, "failed to delete all")
-		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deletion_failed", fmt.Sprintf("Deletion failed for all services. Error: %s", err.Error()), nil)
+	if deployErr = rec.DeleteAll(ctx); deployErr != nil {
+		h.logger.Error(deployErr, "failed to delete all")
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "deletion_failed", fmt.Sprintf("Deletion failed for all services. Error: %s", deployErr.Error()), apperrors.Details(deployErr))
 		return
 	}
 
@@ -122,12 +225,19 @@ func (h *Handler) Down(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
-	if h.reconciler == nil {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
 		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
 		return
 	}
 
 	ctx := r.Context()
+
+	rolling, err := parseRollingOptions(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
 	
 	// Parse request body for service selection
 	var req DeploymentRequest
@@ -160,27 +270,107 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		manifests = updatedManifests
 	}
 	
+	if isDryRun(r) {
+		message := "Dry run: update planned for all services"
+		if len(req.Services) > 0 {
+			message = fmt.Sprintf("Dry run: update planned for %d service(s): %s", len(req.Services), strings.Join(req.Services, ", "))
+		}
+		h.writeDryRunResponse(w, r, rec, message, manifestKeys(manifests), plannedActionUpdate, plannedActionCreate)
+		return
+	}
+
+	ctx = h.beginDeploymentSession(ctx, "update", manifests)
+	var result reconciler.ReconciliationResult
+	var rolloutKeys []string
+	var deployErr error
+	defer func() {
+		h.endDeploymentSession(ctx, deployErr)
+		h.notifyDeployment(ctx, "update", req.Services, deployErr)
+	}()
+
+	if deployErr = h.runDeployWebhooks(ctx, webhook.StagePreDeploy, "update", req.Services, h.preDeployWebhooks); deployErr != nil {
+		h.logger.Error(deployErr, "pre-deploy webhook failed, aborting")
+		WriteErrorResponse(w, h.logger, http.StatusBadGateway, "webhook_failed", fmt.Sprintf("Pre-deploy webhook failed. Error: %s", deployErr.Error()), nil)
+		return
+	}
+
 	if len(req.Services) > 0 {
-		if err := h.reconciler.UpdateManifests(ctx, manifests); err != nil {
-			h.logger.Error(err, "failed to update selected services")
+		result, rolloutKeys, deployErr = applyUpdate(ctx, rec, manifests, rolling)
+		if deployErr != nil {
+			h.logger.Error(deployErr, "failed to update selected services")
 			serviceList := strings.Join(req.Services, ", ")
-			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "update_failed", fmt.Sprintf("Update failed for service(s): %s. Error: %s", serviceList, err.Error()), nil)
+			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "update_failed", fmt.Sprintf("Update failed for service(s): %s. Error: %s", serviceList, deployErr.Error()), apperrors.Details(deployErr))
 			return
 		}
 		
+		if err := h.runDeployWebhooks(ctx, webhook.StagePostDeploy, "update", req.Services, h.postDeployWebhooks); err != nil {
+			h.logger.Error(err, "post-deploy webhook failed")
+		}
+
 		serviceList := strings.Join(req.Services, ", ")
-		WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{
-			"message": fmt.Sprintf("Update initiated for %d service(s): %s", len(req.Services), serviceList),
+		h.writeUpdateResponse(w, ctx, rec, rolloutKeys, DeploymentResponse{
+			Message:       fmt.Sprintf("Update initiated for %d service(s): %s", len(req.Services), serviceList),
+			TimedOutCount: result.TimedOutCount,
 		})
 		return
 	}
 	
 	// No services specified, update all using updated manifests with current namespace from CRD
-	if err := h.reconciler.UpdateManifests(ctx, manifests); err != nil {
-		h.logger.Error(err, "failed to update all")
-		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "update_failed", fmt.Sprintf("Update failed for all services. Error: %s", err.Error()), nil)
+	result, rolloutKeys, deployErr = applyUpdate(ctx, rec, manifests, rolling)
+	if deployErr != nil {
+		h.logger.Error(deployErr, "failed to update all")
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "update_failed", fmt.Sprintf("Update failed for all services. Error: %s", deployErr.Error()), apperrors.Details(deployErr))
 		return
 	}
 
-	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{"message": "Update initiated for all services"})
+	if err := h.runDeployWebhooks(ctx, webhook.StagePostDeploy, "update", req.Services, h.postDeployWebhooks); err != nil {
+		h.logger.Error(err, "post-deploy webhook failed")
+	}
+
+	h.writeUpdateResponse(w, ctx, rec, rolloutKeys, DeploymentResponse{
+		Message:       "Update initiated for all services",
+		TimedOutCount: result.TimedOutCount,
+	})
+}
+
+// Diff compares every stored manifest, re-rendered as Up would deploy it, with its live cluster object
+func (h *Handler) Diff(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	ctx := r.Context()
+	manifests := h.store.List()
+
+	updatedManifests, err := h.updateManifestsWithCurrentParameters(ctx, manifests, getInstanceName(r))
+	if err != nil {
+		h.logger.Error(err, "failed to update manifests with current parameters, using existing manifests")
+	} else {
+		manifests = updatedManifests
+	}
+
+	results := make(map[string]diff.DiffResult, len(manifests))
+	for key, yamlData := range manifests {
+		var desired map[string]interface{}
+		if err := yaml.Unmarshal(yamlData, &desired); err != nil {
+			h.logger.V(1).Info("failed to parse manifest for diff, skipping", "key", key, "error", err)
+			continue
+		}
+
+		live, err := rec.GetLiveObject(ctx, key)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				results[key] = diff.ComputeDiff(desired, nil)
+				continue
+			}
+			h.logger.Error(err, "failed to get live object for diff", "key", key)
+			continue
+		}
+
+		results[key] = diff.ComputeDiff(desired, live.Object)
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, results)
 }
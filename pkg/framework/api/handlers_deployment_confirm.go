@@ -0,0 +1,125 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+)
+
+const (
+	// ConfirmationKeyPrefix is the database prefix of pending Down confirmation tokens
+	ConfirmationKeyPrefix = "confirm/"
+	// DownConfirmationTTL is how long a Down confirmation token can be used
+	DownConfirmationTTL = 5 * time.Minute
+	// confirmationRetention keeps expired tokens in the database for a while, so confirming
+	// one reports that it expired rather than that it never existed
+	confirmationRetention = time.Hour
+)
+
+// downConfirmation is the deletion a confirmation token approves
+type downConfirmation struct {
+	Services  []string  `json:"services,omitempty"`
+	Cluster   string    `json:"cluster"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SetDownConfirmation makes Down return a confirmation token instead of deleting, and delete
+// only when called again with that token. Tokens are stored in the database of SetDatabase.
+func (h *Handler) SetDownConfirmation(required bool) {
+	h.requireDownConfirmation = required
+}
+
+// isConfirm reports whether the request asked to carry out a confirmed deletion via ?confirm=true
+func isConfirm(r *http.Request) bool {
+	confirm, err := strconv.ParseBool(r.URL.Query().Get("confirm"))
+	return err == nil && confirm
+}
+
+// writeDownConfirmation stores a confirmation token for deleting the manifests of services and
+// writes it with keys, the resources that would be deleted
+func (h *Handler) writeDownConfirmation(w http.ResponseWriter, r *http.Request, services []string, keys []string) {
+	if h.db == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "database_not_available", "Database not available", nil)
+		return
+	}
+
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		WriteError(w, h.logger, fmt.Errorf("failed to generate confirmation token: %w", err))
+		return
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	confirmation := downConfirmation{
+		Services:  services,
+		Cluster:   clusterNameFor(r),
+		ExpiresAt: time.Now().UTC().Add(DownConfirmationTTL),
+	}
+	data, err := json.Marshal(confirmation)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("failed to encode confirmation: %w", err))
+		return
+	}
+	if err := h.db.SetWithTTL(ConfirmationKeyPrefix+token, data, DownConfirmationTTL+confirmationRetention); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	resources := append([]string(nil), keys...)
+	sort.Strings(resources)
+
+	WriteJSONResponse(w, h.logger, http.StatusAccepted, DownConfirmationResponse{
+		ConfirmationToken: token,
+		ExpiresAt:         confirmation.ExpiresAt,
+		ResourcesToDelete: resources,
+	})
+}
+
+// consumeDownConfirmation removes token and returns the deletion it approved. It writes a 404,
+// 409 or 410 response and returns false when the token is unknown, was issued for another
+// cluster, or has expired.
+func (h *Handler) consumeDownConfirmation(w http.ResponseWriter, r *http.Request, token string) (downConfirmation, bool) {
+	if h.db == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "database_not_available", "Database not available", nil)
+		return downConfirmation{}, false
+	}
+
+	var confirmation downConfirmation
+	key := ConfirmationKeyPrefix + token
+	err := h.db.Transaction(func(txn *database.DB) error {
+		data, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &confirmation); err != nil {
+			return fmt.Errorf("failed to decode confirmation: %w", err)
+		}
+		return txn.Delete(key)
+	})
+	if errors.Is(err, database.ErrNotFound) {
+		WriteErrorResponse(w, h.logger, http.StatusNotFound, "confirmation_token_not_found", "Confirmation token not found or already used", nil)
+		return downConfirmation{}, false
+	}
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return downConfirmation{}, false
+	}
+
+	if time.Now().After(confirmation.ExpiresAt) {
+		WriteErrorResponse(w, h.logger, http.StatusGone, "confirmation_token_expired", fmt.Sprintf("Confirmation token expired at %s", confirmation.ExpiresAt.Format(time.RFC3339)), nil)
+		return downConfirmation{}, false
+	}
+	if cluster := clusterNameFor(r); confirmation.Cluster != cluster {
+		WriteErrorResponse(w, h.logger, http.StatusConflict, "confirmation_cluster_mismatch", fmt.Sprintf("Confirmation token was issued for cluster %s, not %s", confirmation.Cluster, cluster), nil)
+		return downConfirmation{}, false
+	}
+	return confirmation, true
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newConfirmTestHandler returns a handler that requires Down confirmations, storing them in db
+func newConfirmTestHandler(t *testing.T) (*Handler, *database.DB) {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	handler.SetDatabase(db, 0)
+	handler.SetDownConfirmation(true)
+
+	if err := handler.store.Create("default/Service/test-service", []byte(createTestManifest("Service", "test-service", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	return handler, db
+}
+
+func postDown(handler *Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/down", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.Down(w, req)
+	return w
+}
+
+func requestDownConfirmation(t *testing.T, handler *Handler, body string) DownConfirmationResponse {
+	t.Helper()
+	w := postDown(handler, body)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Down() without a token status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var resp DownConfirmationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Down() response is not valid JSON: %v", err)
+	}
+	if resp.ConfirmationToken == "" {
+		t.Fatal("Down() without a token returned no confirmation_token")
+	}
+	return resp
+}
+
+func TestDown_Confirmation(t *testing.T) {
+	handler, _ := newConfirmTestHandler(t)
+
+	resp := requestDownConfirmation(t, handler, `{"services": ["test-service"]}`)
+	if len(resp.ResourcesToDelete) != 1 || resp.ResourcesToDelete[0] != "default/Service/test-service" {
+		t.Errorf("ResourcesToDelete = %v, want [default/Service/test-service]", resp.ResourcesToDelete)
+	}
+	if until := time.Until(resp.ExpiresAt); until <= 0 || until > DownConfirmationTTL {
+		t.Errorf("ExpiresAt = %v, want within %v", resp.ExpiresAt, DownConfirmationTTL)
+	}
+
+	w := postDown(handler, `{"confirmation_token": "`+resp.ConfirmationToken+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Down() with the token status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "test-service") {
+		t.Errorf("Down() with the token did not delete the confirmed service: %s", w.Body.String())
+	}
+
+	// Tokens can be used once
+	if w := postDown(handler, `{"confirmation_token": "`+resp.ConfirmationToken+`"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Down() with a used token status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestDown_ConfirmationListsDeletedResources(t *testing.T) {
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	handler.SetDatabase(db, 0)
+	handler.SetDownConfirmation(true)
+
+	// The managed Service has no stored manifest, so only the managed keys name it
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{}, nil
+	})
+	managed := map[string][]byte{"default/Service/managed": []byte(createTestManifest("Service", "managed", "default"))}
+	if _, err := rec.DeployManifests(context.Background(), managed); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	createLiveService(t, dynamicClient, "managed")
+
+	resp := requestDownConfirmation(t, handler, "")
+	dynamicClient.ClearActions()
+
+	if w := postDown(handler, `{"confirmation_token": "`+resp.ConfirmationToken+`"}`); w.Code != http.StatusOK {
+		t.Fatalf("Down() with the token status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var deleted []string
+	for _, action := range dynamicClient.Actions() {
+		if del, ok := action.(k8stesting.DeleteAction); ok {
+			deleted = append(deleted, del.GetNamespace()+"/Service/"+del.GetName())
+		}
+	}
+	sort.Strings(deleted)
+	if len(deleted) == 0 || !reflect.DeepEqual(resp.ResourcesToDelete, deleted) {
+		t.Errorf("ResourcesToDelete = %v, want the deleted resources %v", resp.ResourcesToDelete, deleted)
+	}
+}
+
+func TestDown_ConfirmationExpired(t *testing.T) {
+	handler, db := newConfirmTestHandler(t)
+	resp := requestDownConfirmation(t, handler, "")
+
+	expired, err := json.Marshal(downConfirmation{Cluster: "default", ExpiresAt: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("failed to encode confirmation: %v", err)
+	}
+	if err := db.Set(ConfirmationKeyPrefix+resp.ConfirmationToken, expired); err != nil {
+		t.Fatalf("failed to expire confirmation: %v", err)
+	}
+
+	if w := postDown(handler, `{"confirmation_token": "`+resp.ConfirmationToken+`"}`); w.Code != http.StatusGone {
+		t.Errorf("Down() with an expired token status = %d, want %d: %s", w.Code, http.StatusGone, w.Body.String())
+	}
+}
+
+func TestDown_ConfirmTrueRequiresToken(t *testing.T) {
+	handler, _ := newConfirmTestHandler(t)
+
+	req := httptest.NewRequest("POST", "/api/down?confirm=true", nil)
+	w := httptest.NewRecorder()
+	handler.Down(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Down() with confirm=true and no token status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDown_SkipConfirmation(t *testing.T) {
+	handler, _ := newConfirmTestHandler(t)
+	handler.SetDownConfirmation(false)
+
+	if w := postDown(handler, `{"services": ["test-service"]}`); w.Code != http.StatusOK {
+		t.Errorf("Down() with confirmation skipped status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// Planned change actions reported by dry-run deployments
+const (
+	plannedActionCreate = "create"
+	plannedActionUpdate = "update"
+	plannedActionDelete = "delete"
+)
+
+// isDryRun reports whether the request asked for a dry run via ?dry_run=true
+func isDryRun(r *http.Request) bool {
+	dryRun, err := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	return err == nil && dryRun
+}
+
+// manifestKeys returns the keys of manifests
+func manifestKeys(manifests map[string][]byte) []string {
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// plannedChanges reads each key from the cluster and lists the action a real run would take,
+// sorted by key: ifLive when the resource exists and ifMissing when it does not. Keys whose
+// action is empty are left out.
+func plannedChanges(ctx context.Context, rec reconciler.Reconciler, keys []string, ifLive, ifMissing string) ([]PlannedChange, error) {
+	changes := make([]PlannedChange, 0, len(keys))
+	for _, key := range keys {
+		action := ifLive
+		if _, err := rec.GetLiveObject(ctx, key); errors.Is(err, apperrors.ErrNotFound) {
+			action = ifMissing
+		} else if err != nil {
+			return nil, err
+		}
+		if action != "" {
+			changes = append(changes, PlannedChange{Key: key, Action: action})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes, nil
+}
+
+// writeDryRunResponse plans the changes of keys with plannedChanges and writes the usual
+// deployment response extended with them
+func (h *Handler) writeDryRunResponse(w http.ResponseWriter, r *http.Request, rec reconciler.Reconciler, message string, keys []string, ifLive, ifMissing string) {
+	changes, err := plannedChanges(r.Context(), rec, keys, ifLive, ifMissing)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, DryRunResponse{
+		Message:        message,
+		DryRun:         true,
+		PlannedChanges: changes,
+	})
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// createLiveService creates the Service default/name in the fake cluster
+func createLiveService(t *testing.T, dynamicClient *dynamicfake.FakeDynamicClient, name string) {
+	t.Helper()
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+	}}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	if _, err := dynamicClient.Resource(gvr).Namespace("default").Create(context.Background(), service, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create live Service: %v", err)
+	}
+}
+
+// mutatingActions returns the verbs of the actions of dynamicClient that change the cluster
+func mutatingActions(dynamicClient *dynamicfake.FakeDynamicClient) []string {
+	var verbs []string
+	for _, action := range dynamicClient.Actions() {
+		if verb := action.GetVerb(); verb != "get" && verb != "list" && verb != "watch" {
+			verbs = append(verbs, verb)
+		}
+	}
+	return verbs
+}
+
+func decodeDryRunResponse(t *testing.T, w *httptest.ResponseRecorder) DryRunResponse {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp DryRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	if !resp.DryRun {
+		t.Error("dry_run = false, want true")
+	}
+	if resp.Message == "" {
+		t.Error("message is empty")
+	}
+	return resp
+}
+
+func TestUp_DryRunLeavesClusterUntouched(t *testing.T) {
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	testManifest := createTestManifest("Service", "test-service", "default")
+	if err := handler.store.Create("default/Service/test-service", []byte(testManifest)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/up?dry_run=true", nil)
+	w := httptest.NewRecorder()
+
+	handler.Up(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	if verbs := mutatingActions(dynamicClient); len(verbs) != 0 {
+		t.Errorf("Up() dry run issued %v, want only reads", verbs)
+	}
+}
+
+func TestDeploymentHandlers_DryRunPlannedChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		handle func(h *Handler) http.HandlerFunc
+		want   []PlannedChange
+	}{
+		{
+			name:   "up",
+			path:   "/api/up?dry_run=true",
+			handle: func(h *Handler) http.HandlerFunc { return h.Up },
+			want: []PlannedChange{
+				{Key: "default/Service/new-service", Action: plannedActionCreate},
+				{Key: "default/Service/test-service", Action: plannedActionUpdate},
+			},
+		},
+		{
+			name:   "update with services",
+			path:   "/api/update?dry_run=true",
+			body:   `{"services":["test-service"]}`,
+			handle: func(h *Handler) http.HandlerFunc { return h.Update },
+			want:   []PlannedChange{{Key: "default/Service/test-service", Action: plannedActionUpdate}},
+		},
+		{
+			name:   "down with services",
+			path:   "/api/down?dry_run=true",
+			body:   `{"services":["new-service","test-service"]}`,
+			handle: func(h *Handler) http.HandlerFunc { return h.Down },
+			want:   []PlannedChange{{Key: "default/Service/test-service", Action: plannedActionDelete}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+			handler, err := newTestHandler(t, WithTestReconciler(rec))
+			if err != nil {
+				t.Fatalf("newTestHandler() error = %v", err)
+			}
+
+			for _, name := range []string{"test-service", "new-service"} {
+				if err := handler.store.Create("default/Service/"+name, []byte(createTestManifest("Service", name, "default"))); err != nil {
+					t.Fatalf("failed to create test manifest: %v", err)
+				}
+			}
+			createLiveService(t, dynamicClient, "test-service")
+			dynamicClient.ClearActions()
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			tt.handle(handler)(w, req)
+
+			resp := decodeDryRunResponse(t, w)
+			if !reflect.DeepEqual(resp.PlannedChanges, tt.want) {
+				t.Errorf("planned_changes = %+v, want %+v", resp.PlannedChanges, tt.want)
+			}
+			if verbs := mutatingActions(dynamicClient); len(verbs) != 0 {
+				t.Errorf("dry run issued %v, want only reads", verbs)
+			}
+		})
+	}
+}
+
+func TestDown_DryRunPlansManagedResources(t *testing.T) {
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	// Applies succeed without touching the tracker so only the Service created below exists
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{}, nil
+	})
+	managed := map[string][]byte{"default/Service/managed": []byte(createTestManifest("Service", "managed", "default"))}
+	if _, err := rec.DeployManifests(context.Background(), managed); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	createLiveService(t, dynamicClient, "managed")
+
+	// A stored manifest that was never deployed is not deleted by Down
+	if err := handler.store.Create("default/Service/stored", []byte(createTestManifest("Service", "stored", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	dynamicClient.ClearActions()
+
+	w := httptest.NewRecorder()
+	handler.Down(w, httptest.NewRequest("POST", "/api/down?dry_run=true", nil))
+
+	resp := decodeDryRunResponse(t, w)
+	want := []PlannedChange{{Key: "default/Service/managed", Action: plannedActionDelete}}
+	if !reflect.DeepEqual(resp.PlannedChanges, want) {
+		t.Errorf("planned_changes = %+v, want %+v", resp.PlannedChanges, want)
+	}
+	if verbs := mutatingActions(dynamicClient); len(verbs) != 0 {
+		t.Errorf("Down() dry run issued %v, want only reads", verbs)
+	}
+}
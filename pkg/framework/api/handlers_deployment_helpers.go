@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
 	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
 	"gopkg.in/yaml.v3"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -198,10 +200,13 @@ func (h *Handler) updateManifestsWithCurrentParameters(ctx context.Context, mani
 		return updatedManifests, nil
 	}
 	
+	namespaceMapping := crd.DeploymentParametersSpec(spec).NamespaceMapping()
+
 	// Update each service's manifests with current parameters
 	for serviceName, serviceManifestsMap := range serviceManifests {
 		// Determine target namespace from spec
 		targetNamespace := "default"
+		var imageOverrides map[string]string
 		
 		// Check global namespace first
 		if spec != nil {
@@ -217,12 +222,24 @@ func (h *Handler) updateManifestsWithCurrentParameters(ctx context.Context, mani
 					if ns, ok := service["namespace"].(string); ok && ns != "" {
 						targetNamespace = ns
 					}
+					imageOverrides = serviceImageOverrides(service)
 				}
 			}
 		}
+
+		// spec.namespaceMapping takes precedence over both
+		if ns, ok := namespaceMapping[serviceName]; ok {
+			targetNamespace = ns
+		}
 		
 		// Update each manifest in this service
 		for key, yamlData := range serviceManifestsMap {
+			if patched, err := manifest.ApplyImageOverrides(yamlData, imageOverrides); err != nil {
+				h.logger.V(1).Info("failed to apply image overrides, using as-is", "key", key, "error", err)
+			} else {
+				yamlData = patched
+			}
+
 			// Parse YAML
 			var obj map[string]interface{}
 			if err := yaml.Unmarshal(yamlData, &obj); err != nil {
@@ -272,3 +289,19 @@ func (h *Handler) updateManifestsWithCurrentParameters(ctx context.Context, mani
 	return updatedManifests, nil
 }
 
+// serviceImageOverrides returns the imageOverrides of a service spec, mapping container
+// names to image tags
+func serviceImageOverrides(service map[string]interface{}) map[string]string {
+	raw, ok := service["imageOverrides"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	overrides := make(map[string]string, len(raw))
+	for container, tag := range raw {
+		if tag, ok := tag.(string); ok && tag != "" {
+			overrides[container] = tag
+		}
+	}
+	return overrides
+}
+
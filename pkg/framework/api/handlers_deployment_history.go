@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+const maxDeploymentHistoryPageSize = 100
+
+// beginDeploymentSession records the start of a deployment and returns a context that
+// stamps every event emitted while deploying with the new deployment ID
+func (h *Handler) beginDeploymentSession(ctx context.Context, triggeredBy string, manifests map[string][]byte) context.Context {
+	services := make(map[string]bool)
+	for key := range manifests {
+		services[manifest.ServiceForKey(key)] = true
+	}
+
+	deploymentID := uuid.New().String()
+	events.StoreEventSafeContext(ctx, h.eventStore, h.logger, events.DeploymentStarted(deploymentID, triggeredBy, len(services)))
+	return events.WithDeploymentID(ctx, deploymentID)
+}
+
+// endDeploymentSession records how the deployment started with ctx finished
+func (h *Handler) endDeploymentSession(ctx context.Context, err error) {
+	event := events.Success("", events.OperationDeployment, "Deployment finished")
+	if err != nil {
+		event = events.Error("", events.OperationDeployment, "Deployment failed", err)
+	}
+	events.StoreEventSafeContext(ctx, h.eventStore, h.logger, event)
+}
+
+// DeploymentHistory returns one page of past Up, Down and Update calls, newest first
+func (h *Handler) DeploymentHistory(w http.ResponseWriter, r *http.Request) {
+	if h.eventStore == nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: event store not available", apperrors.ErrEventStore))
+		return
+	}
+
+	page, err := parsePositiveQueryInt(r, "page", 1)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	pageSize, err := parsePositiveQueryInt(r, "page_size", 20)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if pageSize > maxDeploymentHistoryPageSize {
+		WriteError(w, h.logger, fmt.Errorf("%w: page_size cannot exceed %d", apperrors.ErrInvalid, maxDeploymentHistoryPageSize))
+		return
+	}
+
+	sessions, err := h.eventStore.ListDeploymentSessions(r.Context(), r.URL.Query().Get("namespace"), page, pageSize)
+	if err != nil {
+		h.logger.Error(err, "failed to list deployment sessions")
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, DeploymentHistoryResponse{
+		Deployments: sessions,
+		Page:        page,
+		PageSize:    pageSize,
+	})
+}
+
+func parsePositiveQueryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: invalid %s parameter: must be a positive integer", apperrors.ErrInvalid, name)
+	}
+	return n, nil
+}
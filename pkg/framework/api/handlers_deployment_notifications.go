@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+
+	"github.com/garunski/conductor-framework/pkg/framework/notifier"
+)
+
+// SetNotifier configures where Up, Down and Update report how they finished.
+// It must be called before SetupRoutes.
+func (h *Handler) SetNotifier(n notifier.Notifier) {
+	h.notifier = n
+}
+
+// notifyDeployment sends the lifecycle event for action in the background so that slow
+// notification endpoints do not delay the API response
+func (h *Handler) notifyDeployment(ctx context.Context, action string, services []string, err error) {
+	if h.notifier == nil {
+		return
+	}
+
+	event := notifier.NewDeploymentEvent(action, services, err)
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := h.notifier.Notify(ctx, event); err != nil {
+			h.logger.Error(err, "failed to send deployment notification", "event", event.Type)
+		}
+	}()
+}
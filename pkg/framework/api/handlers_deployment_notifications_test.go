@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/notifier"
+)
+
+// channelNotifier forwards every notified event to a channel
+type channelNotifier chan notifier.DeploymentEvent
+
+func (c channelNotifier) Notify(ctx context.Context, event notifier.DeploymentEvent) error {
+	c <- event
+	return nil
+}
+
+func receiveDeploymentEvent(t *testing.T, events channelNotifier) notifier.DeploymentEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for deployment notification")
+	}
+	return notifier.DeploymentEvent{}
+}
+
+func TestDeployment_Notifications(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		call     func(h *Handler) http.HandlerFunc
+		wantType string
+	}{
+		{name: "up", path: "/api/up", call: func(h *Handler) http.HandlerFunc { return h.Up }, wantType: notifier.EventDeploySuccess},
+		{name: "update", path: "/api/update", call: func(h *Handler) http.HandlerFunc { return h.Update }, wantType: notifier.EventUpdateSuccess},
+		{name: "down", path: "/api/down", call: func(h *Handler) http.HandlerFunc { return h.Down }, wantType: notifier.EventDeleteSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := setupTestReconciler(t, true)
+			handler, err := newTestHandler(t, WithTestReconciler(rec))
+			if err != nil {
+				t.Fatalf("newTestHandler() error = %v", err)
+			}
+			events := make(channelNotifier, 1)
+			handler.SetNotifier(events)
+
+			if err := handler.store.Create("default/Service/test-service", []byte(createTestManifest("Service", "test-service", "default"))); err != nil {
+				t.Fatalf("failed to create test manifest: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			tt.call(handler)(w, httptest.NewRequest("POST", tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s status code = %v, want %v, body = %s", tt.name, w.Code, http.StatusOK, w.Body.String())
+			}
+
+			event := receiveDeploymentEvent(t, events)
+			if event.Type != tt.wantType || event.Action != tt.name {
+				t.Errorf("notified event = %s/%s, want %s/%s", event.Action, event.Type, tt.name, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestUp_NotifiesSubscribedEndpointsOnly(t *testing.T) {
+	recorder := &hookRecorder{}
+	server := recorder.server(t, nil)
+
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetNotifier(notifier.NewHTTPNotifier(nil, []notifier.Config{
+		{URL: server.URL + "/failed", Events: []string{notifier.EventDeployFailed}},
+		{URL: server.URL + "/success", Events: []string{notifier.EventDeploySuccess}},
+	}))
+
+	w := httptest.NewRecorder()
+	handler.Up(w, httptest.NewRequest("POST", "/api/up", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.called()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got, want := recorder.called(), []string{"/success"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("notification calls = %v, want %v", got, want)
+	}
+}
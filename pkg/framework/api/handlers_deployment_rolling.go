@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// jobTypeRollingUpdate is the type of the jobs that finish StatefulSet rolling updates
+const jobTypeRollingUpdate = "rolling_update"
+
+// rollingOptions holds ?strategy=rolling and ?batch_size= of an update
+type rollingOptions struct {
+	enabled   bool
+	batchSize int
+}
+
+// parseRollingOptions reads ?strategy= (empty or rolling) and ?batch_size= (default 1)
+func parseRollingOptions(r *http.Request) (rollingOptions, error) {
+	opts := rollingOptions{batchSize: 1}
+
+	switch strategy := r.URL.Query().Get("strategy"); strategy {
+	case "":
+	case "rolling":
+		opts.enabled = true
+	default:
+		return opts, fmt.Errorf("%w: strategy must be rolling", apperrors.ErrInvalidParameter)
+	}
+
+	if batchSize := r.URL.Query().Get("batch_size"); batchSize != "" {
+		n, err := strconv.Atoi(batchSize)
+		if err != nil || n < 1 {
+			return opts, fmt.Errorf("%w: batch_size must be a positive integer", apperrors.ErrInvalidParameter)
+		}
+		opts.batchSize = n
+	}
+	return opts, nil
+}
+
+// applyUpdate updates manifests, starting partitioned rollouts of their StatefulSets when
+// opts selects the rolling strategy, and returns the keys of the rollouts started
+func applyUpdate(ctx context.Context, rec reconciler.Reconciler, manifests map[string][]byte, opts rollingOptions) (reconciler.ReconciliationResult, []string, error) {
+	if !opts.enabled {
+		result, err := rec.UpdateManifests(ctx, manifests)
+		return result, nil, err
+	}
+	return rec.StartRollingUpdate(ctx, manifests, opts.batchSize)
+}
+
+// writeUpdateResponse responds to a successful update. When StatefulSet rollouts were
+// started, it responds 202 with a job that releases their remaining batches one rollout at a
+// time; a failed rollout does not stop the ones after it.
+func (h *Handler) writeUpdateResponse(w http.ResponseWriter, ctx context.Context, rec reconciler.Reconciler, rolloutKeys []string, resp DeploymentResponse) {
+	if len(rolloutKeys) == 0 {
+		WriteJSONResponse(w, h.logger, http.StatusOK, resp)
+		return
+	}
+
+	jobCtx := context.WithoutCancel(ctx)
+	resp.JobID, _ = h.jobs.start(jobTypeRollingUpdate, func() error {
+		var errs []error
+		for _, key := range rolloutKeys {
+			if err := rec.CompleteRollingUpdate(jobCtx, key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+	resp.Message += fmt.Sprintf("; rolling out %d StatefulSet(s)", len(rolloutKeys))
+	WriteJSONResponse(w, h.logger, http.StatusAccepted, resp)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+func TestUpdate_InvalidRollingOptions(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	tests := []string{
+		"/api/update?strategy=bogus",
+		"/api/update?strategy=rolling&batch_size=0",
+		"/api/update?strategy=rolling&batch_size=abc",
+	}
+	for _, target := range tests {
+		req := httptest.NewRequest("POST", target, nil)
+		w := httptest.NewRecorder()
+
+		handler.Update(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Update(%s) status code = %v, want %v", target, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// rolloutRecordingReconciler records the rollouts it is asked to complete and fails those in failing
+type rolloutRecordingReconciler struct {
+	reconciler.Reconciler
+	mu        sync.Mutex
+	completed []string
+	failing   map[string]bool
+}
+
+func (r *rolloutRecordingReconciler) CompleteRollingUpdate(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completed = append(r.completed, key)
+	if r.failing[key] {
+		return errors.New("rollout failed")
+	}
+	return nil
+}
+
+func TestWriteUpdateResponse_CompletesEveryRollout(t *testing.T) {
+	rec := &rolloutRecordingReconciler{failing: map[string]bool{"default/StatefulSet/a": true}}
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.writeUpdateResponse(w, context.Background(), rec, []string{"default/StatefulSet/a", "default/StatefulSet/b"}, DeploymentResponse{})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("writeUpdateResponse() status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	var resp DeploymentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	job, _ := handler.jobs.get(resp.JobID)
+	for job.Status == JobStatusRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		job, _ = handler.jobs.get(resp.JobID)
+	}
+	if job.Status != JobStatusFailed {
+		t.Errorf("job status = %s, want %s", job.Status, JobStatusFailed)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if !reflect.DeepEqual(rec.completed, []string{"default/StatefulSet/a", "default/StatefulSet/b"}) {
+		t.Errorf("completed rollouts = %v, want both after the first failed", rec.completed)
+	}
+}
@@ -58,13 +58,13 @@ func TestUp_AllServices(t *testing.T) {
 		t.Errorf("Up() status code = %v, want %v", w.Code, http.StatusOK)
 	}
 
-	var resp map[string]string
+	var resp DeploymentResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("Up() response is not valid JSON: %v", err)
 	}
 
-	if !strings.Contains(resp["message"], "all services") {
-		t.Errorf("Up() message = %v, want to contain 'all services'", resp["message"])
+	if !strings.Contains(resp.Message, "all services") {
+		t.Errorf("Up() message = %v, want to contain 'all services'", resp.Message)
 	}
 }
 
@@ -92,13 +92,13 @@ func TestUp_WithServices(t *testing.T) {
 		t.Errorf("Up() status code = %v, want %v", w.Code, http.StatusOK)
 	}
 
-	var resp map[string]string
+	var resp DeploymentResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("Up() response is not valid JSON: %v", err)
 	}
 
-	if !strings.Contains(resp["message"], "test-service") {
-		t.Errorf("Up() message = %v, want to contain 'test-service'", resp["message"])
+	if !strings.Contains(resp.Message, "test-service") {
+		t.Errorf("Up() message = %v, want to contain 'test-service'", resp.Message)
 	}
 }
 
@@ -262,13 +262,13 @@ func TestUpdate_AllServices(t *testing.T) {
 		t.Errorf("Update() status code = %v, want %v", w.Code, http.StatusOK)
 	}
 
-	var resp map[string]string
+	var resp DeploymentResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("Update() response is not valid JSON: %v", err)
 	}
 
-	if !strings.Contains(resp["message"], "all services") {
-		t.Errorf("Update() message = %v, want to contain 'all services'", resp["message"])
+	if !strings.Contains(resp.Message, "all services") {
+		t.Errorf("Update() message = %v, want to contain 'all services'", resp.Message)
 	}
 }
 
@@ -296,13 +296,13 @@ func TestUpdate_WithServices(t *testing.T) {
 		t.Errorf("Update() status code = %v, want %v", w.Code, http.StatusOK)
 	}
 
-	var resp map[string]string
+	var resp DeploymentResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("Update() response is not valid JSON: %v", err)
 	}
 
-	if !strings.Contains(resp["message"], "test-service") {
-		t.Errorf("Update() message = %v, want to contain 'test-service'", resp["message"])
+	if !strings.Contains(resp.Message, "test-service") {
+		t.Errorf("Update() message = %v, want to contain 'test-service'", resp.Message)
 	}
 }
 
@@ -433,3 +433,104 @@ func TestUpdateManifestsWithCurrentParameters_NoSpec(t *testing.T) {
 	}
 }
 
+func TestUpdateManifestsWithCurrentParameters_ImageOverrides(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	ctx := context.Background()
+	spec := map[string]interface{}{
+		"services": map[string]interface{}{
+			"web": map[string]interface{}{
+				"imageOverrides": map[string]interface{}{"app": "2.0", "sidecar": "v1.29"},
+			},
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(ctx, crd.DefaultName, "default", spec); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	manifests := map[string][]byte{
+		"default/Deployment/web": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: web/app:1.0
+      - name: sidecar
+        image: envoyproxy/envoy:v1.28
+      - name: metrics
+        image: prom/exporter:0.9
+`),
+	}
+
+	updated, err := handler.updateManifestsWithCurrentParameters(ctx, manifests, crd.DefaultName)
+	if err != nil {
+		t.Fatalf("updateManifestsWithCurrentParameters() error = %v", err)
+	}
+	result := string(updated["default/Deployment/web"])
+	for _, image := range []string{"image: web/app:2.0", "image: envoyproxy/envoy:v1.29", "image: prom/exporter:0.9"} {
+		if !strings.Contains(result, image) {
+			t.Errorf("updateManifestsWithCurrentParameters() result is missing %q:\n%s", image, result)
+		}
+	}
+}
+
+func TestUpdateManifestsWithCurrentParameters_NamespaceMapping(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	ctx := context.Background()
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "apps"},
+		crd.NamespaceMappingKey: map[string]interface{}{
+			"redis":    "cache",
+			"postgres": "data",
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(ctx, crd.DefaultName, "default", spec); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	manifests := map[string][]byte{
+		"default/Deployment/redis":    []byte(createTestManifest("Deployment", "redis", "default")),
+		"default/Service/postgres":    []byte(createTestManifest("Service", "postgres", "default")),
+		"default/Secret/postgres-pvc": []byte(createTestManifest("Secret", "postgres-pvc", "default")),
+		"default/Deployment/web":      []byte(createTestManifest("Deployment", "web", "default")),
+	}
+
+	updated, err := handler.updateManifestsWithCurrentParameters(ctx, manifests, crd.DefaultName)
+	if err != nil {
+		t.Fatalf("updateManifestsWithCurrentParameters() error = %v", err)
+	}
+
+	want := map[string]string{
+		"cache/Deployment/redis":   "cache",
+		"data/Service/postgres":    "data",
+		"data/Secret/postgres-pvc": "data",
+		"apps/Deployment/web":      "apps",
+	}
+	if len(updated) != len(want) {
+		t.Errorf("updateManifestsWithCurrentParameters() returned %d manifests, want %d", len(updated), len(want))
+	}
+	for key, namespace := range want {
+		manifest, ok := updated[key]
+		if !ok {
+			t.Errorf("updateManifestsWithCurrentParameters() is missing %s", key)
+			continue
+		}
+		if !strings.Contains(string(manifest), "namespace: "+namespace) {
+			t.Errorf("%s metadata does not have namespace %s:\n%s", key, namespace, manifest)
+		}
+	}
+}
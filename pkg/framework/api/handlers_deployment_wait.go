@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// defaultWaitTimeout bounds a readiness wait started without ?timeout=
+const defaultWaitTimeout = 120 * time.Second
+
+// maxWaitTimeout is the longest readiness wait a request may ask for
+const maxWaitTimeout = 30 * time.Minute
+
+// waitResponseMargin is how long before the deadline of its route a blocking wait stops
+// blocking and returns the job for polling, so the response is written before the route
+// timeout answers 504
+const waitResponseMargin = 5 * time.Second
+
+// jobTypeWaitForReady is the type of the jobs that wait for deployed workloads
+const jobTypeWaitForReady = "wait_for_ready"
+
+// waitMode selects what Up does after the manifests have been applied
+type waitMode int
+
+const (
+	waitNone  waitMode = iota // respond as soon as the manifests are applied
+	waitBlock                 // ?wait=true: respond once every workload is ready
+	waitAsync                 // ?wait=async: respond at once with a job to poll
+)
+
+type waitOptions struct {
+	mode    waitMode
+	timeout time.Duration
+}
+
+// parseWaitOptions reads ?wait= (true, false or async) and ?timeout= (a duration such as 120s)
+func parseWaitOptions(r *http.Request) (waitOptions, error) {
+	opts := waitOptions{timeout: defaultWaitTimeout}
+
+	switch wait := r.URL.Query().Get("wait"); wait {
+	case "", "false":
+		opts.mode = waitNone
+	case "true":
+		opts.mode = waitBlock
+	case "async":
+		opts.mode = waitAsync
+	default:
+		return opts, fmt.Errorf("%w: wait must be true, false or async", apperrors.ErrInvalidParameter)
+	}
+
+	if timeout := r.URL.Query().Get("timeout"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 || d > maxWaitTimeout {
+			return opts, fmt.Errorf("%w: timeout must be a positive duration up to %s", apperrors.ErrInvalidParameter, maxWaitTimeout)
+		}
+		opts.timeout = d
+	}
+	return opts, nil
+}
+
+// writeDeploymentResponse responds to a successful deployment of manifests. With ?wait= it
+// starts a job waiting for the deployed workloads to become ready. A blocking wait that would
+// outlive the route timeout responds 202 with the job shortly before it; the job keeps waiting
+// for the full ?timeout= and is polled at GET /api/jobs/{id}.
+func (h *Handler) writeDeploymentResponse(w http.ResponseWriter, r *http.Request, opts waitOptions, manifests map[string][]byte, resp DeploymentResponse) {
+	if opts.mode == waitNone {
+		WriteJSONResponse(w, h.logger, http.StatusOK, resp)
+		return
+	}
+
+	clientset := h.reconcilerFor(r).GetClientset()
+	if clientset == nil {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "clientset_not_available", "Kubernetes client not available", nil)
+		return
+	}
+
+	keys := workloadKeys(manifests)
+	waitCtx := context.WithoutCancel(r.Context())
+	jobID, done := h.jobs.start(jobTypeWaitForReady, func() error {
+		ctx, cancel := context.WithTimeout(waitCtx, opts.timeout)
+		defer cancel()
+		return reconciler.WaitForReady(ctx, keys, clientset)
+	})
+	resp.JobID = jobID
+
+	if opts.mode == waitAsync {
+		WriteJSONResponse(w, h.logger, http.StatusAccepted, resp)
+		return
+	}
+
+	var routeDeadline <-chan time.Time
+	if deadline, ok := r.Context().Deadline(); ok {
+		timer := time.NewTimer(time.Until(deadline) - waitResponseMargin)
+		defer timer.Stop()
+		routeDeadline = timer.C
+	}
+	select {
+	case <-done:
+	case <-routeDeadline:
+		resp.Message += "; still waiting for workloads, poll the job for the result"
+		WriteJSONResponse(w, h.logger, http.StatusAccepted, resp)
+		return
+	case <-r.Context().Done():
+		WriteJSONResponse(w, h.logger, http.StatusAccepted, resp)
+		return
+	}
+
+	job, _ := h.jobs.get(jobID)
+	if job.Status == JobStatusFailed {
+		WriteErrorResponse(w, h.logger, http.StatusGatewayTimeout, "workloads_not_ready",
+			fmt.Sprintf("Manifests were applied but workloads did not become ready. Error: %s", job.Error),
+			map[string]interface{}{"job_id": jobID})
+		return
+	}
+
+	resp.Message += "; all workloads are ready"
+	WriteJSONResponse(w, h.logger, http.StatusOK, resp)
+}
+
+// workloadKeys returns the namespace/Kind/name keys of manifests as rendered for deployment,
+// falling back to the store key when a manifest cannot be parsed
+func workloadKeys(manifests map[string][]byte) []string {
+	keys := make([]string, 0, len(manifests))
+	for key, content := range manifests {
+		if rendered, err := manifest.DocumentKey(content); err == nil {
+			key = rendered
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
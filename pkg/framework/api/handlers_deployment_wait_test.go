@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newWaitTestHandler returns a handler storing one Deployment whose live status reports ready
+func newWaitTestHandler(t *testing.T, ready bool) *Handler {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.store.Create("default/Deployment/web", []byte(createTestManifest("Deployment", "web", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	status := corev1.ConditionFalse
+	var readyReplicas int32
+	if ready {
+		status, readyReplicas = corev1.ConditionTrue, 1
+	}
+	clientset := rec.GetClientset().(*kubefake.Clientset)
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Status: appsv1.DeploymentStatus{
+				Replicas:        1,
+				UpdatedReplicas: 1,
+				ReadyReplicas:   readyReplicas,
+				Conditions:      []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: status}},
+			},
+		}, nil
+	})
+	return handler
+}
+
+func TestUp_WaitForReady(t *testing.T) {
+	handler := newWaitTestHandler(t, true)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up?wait=true&timeout=5s", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp DeploymentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Up() response is not valid JSON: %v", err)
+	}
+	if resp.JobID == "" {
+		t.Error("Up() job_id is empty")
+	}
+	if !strings.Contains(resp.Message, "ready") {
+		t.Errorf("Up() message = %q, want it to report readiness", resp.Message)
+	}
+
+	job, ok := handler.jobs.get(resp.JobID)
+	if !ok || job.Status != JobStatusSucceeded {
+		t.Errorf("job = %+v, want status %s", job, JobStatusSucceeded)
+	}
+}
+
+func TestUp_WaitForReadyTimeout(t *testing.T) {
+	handler := newWaitTestHandler(t, false)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up?wait=true&timeout=50ms", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Up() status = %d, want %d: %s", w.Code, http.StatusGatewayTimeout, w.Body.String())
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Up() response is not valid JSON: %v", err)
+	}
+	if errResp.Error != "workloads_not_ready" {
+		t.Errorf("Up() error = %q, want workloads_not_ready", errResp.Error)
+	}
+	if !strings.Contains(errResp.Message, "default/Deployment/web") {
+		t.Errorf("Up() message = %q, want it to name the pending Deployment", errResp.Message)
+	}
+	if errResp.Details["job_id"] == nil {
+		t.Error("Up() details are missing job_id")
+	}
+}
+
+func TestUp_WaitBeyondRouteTimeoutReturnsJob(t *testing.T) {
+	handler := newWaitTestHandler(t, false)
+
+	// The route leaves a little more than the response margin
+	ctx, cancel := context.WithTimeout(context.Background(), waitResponseMargin+100*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest("POST", "/api/up?wait=true", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	handler.writeDeploymentResponse(w, req, waitOptions{mode: waitBlock, timeout: time.Minute}, handler.store.List(), DeploymentResponse{Message: "Deployed"})
+
+	if ctx.Err() != nil {
+		t.Fatal("writeDeploymentResponse() returned after the route deadline")
+	}
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("writeDeploymentResponse() status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var resp DeploymentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	if job, ok := handler.jobs.get(resp.JobID); !ok || job.Status != JobStatusRunning {
+		t.Errorf("job = %+v, want it still running after the response", job)
+	}
+}
+
+func TestUp_WaitAsyncAndPollJob(t *testing.T) {
+	handler := newWaitTestHandler(t, true)
+	router := handler.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/up?wait=async", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Up() status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var resp DeploymentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Up() response is not valid JSON: %v", err)
+	}
+	if resp.JobID == "" {
+		t.Fatal("Up() job_id is empty")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/jobs/"+resp.JobID, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetJob() status = %d, want %d", w.Code, http.StatusOK)
+		}
+		var job Job
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("GetJob() response is not valid JSON: %v", err)
+		}
+		if job.Status == JobStatusSucceeded {
+			if job.CompletedAt == nil {
+				t.Error("GetJob() completed_at is not set")
+			}
+			return
+		}
+		if job.Status != JobStatusRunning || time.Now().After(deadline) {
+			t.Fatalf("GetJob() = %+v, want it to succeed", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUp_WithoutWaitStartsNoJob(t *testing.T) {
+	handler := newWaitTestHandler(t, false)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp DeploymentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Up() response is not valid JSON: %v", err)
+	}
+	if resp.JobID != "" {
+		t.Errorf("Up() job_id = %q, want empty without ?wait", resp.JobID)
+	}
+}
+
+func TestUp_InvalidWaitParameters(t *testing.T) {
+	handler := newWaitTestHandler(t, true)
+
+	for _, query := range []string{"wait=soon", "wait=true&timeout=forever", "wait=true&timeout=-1s", "wait=true&timeout=2h"} {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/up?"+query, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Up() status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/jobs/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetJob() status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
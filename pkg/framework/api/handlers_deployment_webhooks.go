@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/webhook"
+)
+
+// SetDeployWebhooks configures the hooks Up and Update call before and after deploying
+func (h *Handler) SetDeployWebhooks(invoker webhook.Invoker, pre, post []webhook.Config) {
+	h.webhookInvoker = invoker
+	h.preDeployWebhooks = pre
+	h.postDeployWebhooks = post
+}
+
+// runDeployWebhooks invokes hooks in order. Failures of optional hooks are logged;
+// the first failing required hook stops the sequence and its error is returned.
+func (h *Handler) runDeployWebhooks(ctx context.Context, stage, action string, services []string, hooks []webhook.Config) error {
+	if h.webhookInvoker == nil || len(hooks) == 0 {
+		return nil
+	}
+
+	payload := webhook.Payload{
+		Stage:     stage,
+		Action:    action,
+		Services:  services,
+		Timestamp: time.Now(),
+	}
+	for _, hook := range hooks {
+		if err := h.webhookInvoker.Invoke(ctx, hook, payload); err != nil {
+			if hook.Optional {
+				h.logger.Error(err, "optional deploy webhook failed", "stage", stage, "url", hook.URL)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
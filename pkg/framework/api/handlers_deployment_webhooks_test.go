@@ -0,0 +1,135 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/webhook"
+)
+
+// hookRecorder serves webhooks and records the order in which their paths were called
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (rec *hookRecorder) server(t *testing.T, statusByPath map[string]int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		rec.calls = append(rec.calls, r.URL.Path)
+		rec.mu.Unlock()
+		if status, ok := statusByPath[r.URL.Path]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (rec *hookRecorder) called() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]string{}, rec.calls...)
+}
+
+func TestUp_DeployWebhooksOrder(t *testing.T) {
+	recorder := &hookRecorder{}
+	server := recorder.server(t, nil)
+
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetDeployWebhooks(webhook.NewHTTPInvoker(nil),
+		[]webhook.Config{{URL: server.URL + "/pre-1"}, {URL: server.URL + "/pre-2"}},
+		[]webhook.Config{{URL: server.URL + "/post"}},
+	)
+
+	if err := handler.store.Create("default/Service/test-service", []byte(createTestManifest("Service", "test-service", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/up", nil)
+	w := httptest.NewRecorder()
+
+	handler.Up(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if got, want := recorder.called(), []string{"/pre-1", "/pre-2", "/post"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("webhook calls = %v, want %v", got, want)
+	}
+}
+
+func TestUpdate_RequiredPreDeployWebhookFailureAborts(t *testing.T) {
+	recorder := &hookRecorder{}
+	server := recorder.server(t, map[string]int{"/required": http.StatusInternalServerError})
+
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetDeployWebhooks(webhook.NewHTTPInvoker(nil),
+		[]webhook.Config{{URL: server.URL + "/required"}, {URL: server.URL + "/never"}},
+		[]webhook.Config{{URL: server.URL + "/post"}},
+	)
+
+	if err := handler.store.Create("default/Service/test-service", []byte(createTestManifest("Service", "test-service", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/update", nil)
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("Update() status code = %v, want %v", w.Code, http.StatusBadGateway)
+	}
+	if got, want := recorder.called(), []string{"/required"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("webhook calls = %v, want %v", got, want)
+	}
+	if actions := dynamicClient.Actions(); len(actions) != 0 {
+		t.Errorf("Update() issued %d dynamic client actions after aborted pre-deploy hook, want 0", len(actions))
+	}
+}
+
+func TestUp_OptionalPreDeployWebhookFailureContinues(t *testing.T) {
+	recorder := &hookRecorder{}
+	server := recorder.server(t, map[string]int{"/optional": http.StatusServiceUnavailable})
+
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetDeployWebhooks(webhook.NewHTTPInvoker(nil),
+		[]webhook.Config{{URL: server.URL + "/optional", Optional: true}, {URL: server.URL + "/required"}},
+		nil,
+	)
+
+	if err := handler.store.Create("default/Service/test-service", []byte(createTestManifest("Service", "test-service", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/up", nil)
+	w := httptest.NewRecorder()
+
+	handler.Up(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if got, want := recorder.called(), []string{"/optional", "/required"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("webhook calls = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/garunski/conductor-framework/pkg/framework/diff"
+)
+
+func TestDiff(t *testing.T) {
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	liveConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "app-config", "namespace": "default"},
+		"data":       map[string]interface{}{"mode": "debug"},
+	}}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if _, err := dynamicClient.Resource(gvr).Namespace("default").Create(context.Background(), liveConfig, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create live ConfigMap: %v", err)
+	}
+
+	configMap := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n  namespace: default\ndata:\n  mode: release\n"
+	if err := handler.store.Create("default/ConfigMap/app-config", []byte(configMap)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	if err := handler.store.Create("default/Service/new-service", []byte(createTestManifest("Service", "new-service", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	router := handler.SetupRoutes()
+	req := httptest.NewRequest("GET", "/api/diff", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Diff() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var results map[string]diff.DiffResult
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Fatalf("Diff() response is not valid JSON: %v", err)
+	}
+
+	if got := results["default/Service/new-service"].Status; got != diff.StatusCreated {
+		t.Errorf("Diff() new-service status = %q, want %q", got, diff.StatusCreated)
+	}
+
+	modified := results["default/ConfigMap/app-config"]
+	if modified.Status != diff.StatusModified {
+		t.Fatalf("Diff() app-config status = %q, want %q", modified.Status, diff.StatusModified)
+	}
+	want := `~ data.mode: "debug" -> "release"`
+	if len(modified.Changes) != 1 || modified.Changes[0] != want {
+		t.Errorf("Diff() app-config changes = %v, want [%s]", modified.Changes, want)
+	}
+}
+
+func TestDiff_NoReconciler(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/diff", nil)
+	w := httptest.NewRecorder()
+
+	handler.Diff(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Diff() status code = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+}
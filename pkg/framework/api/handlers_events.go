@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -70,18 +71,10 @@ func (h *Handler) GetEventsByResource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit := 100
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 {
-			WriteError(w, h.logger, fmt.Errorf("%w: limit must be a positive integer", apperrors.ErrInvalid))
-			return
-		}
-		if parsedLimit > 1000 {
-			WriteError(w, h.logger, fmt.Errorf("%w: limit cannot exceed 1000", apperrors.ErrInvalid))
-			return
-		}
-		limit = parsedLimit
+	filters, err := ParseQueryParams(r)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid query parameters: %w", apperrors.ErrInvalid, err))
+		return
 	}
 
 	if h.eventStore == nil {
@@ -89,7 +82,7 @@ func (h *Handler) GetEventsByResource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventList, err := h.eventStore.GetEventsByResource(resourceKey, limit)
+	eventList, err := h.eventStore.GetEventsByResource(resourceKey, filters)
 	if err != nil {
 		h.logger.Error(err, "failed to get events by resource", "resource", resourceKey)
 		WriteError(w, h.logger, err)
@@ -99,19 +92,16 @@ func (h *Handler) GetEventsByResource(w http.ResponseWriter, r *http.Request) {
 	WriteJSONResponse(w, h.logger, http.StatusOK, eventList)
 }
 
+// GetRecentErrors lists recent error events. ?severity=error,warning widens the list to other
+// event types; the remaining ListEvents query parameters filter it further.
 func (h *Handler) GetRecentErrors(w http.ResponseWriter, r *http.Request) {
-	limit := 50
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 {
-			WriteError(w, h.logger, fmt.Errorf("%w: limit must be a positive integer", apperrors.ErrInvalid))
-			return
-		}
-		if parsedLimit > 1000 {
-			WriteError(w, h.logger, fmt.Errorf("%w: limit cannot exceed 1000", apperrors.ErrInvalid))
-			return
-		}
-		limit = parsedLimit
+	filters, err := ParseQueryParams(r)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid query parameters: %w", apperrors.ErrInvalid, err))
+		return
+	}
+	if r.URL.Query().Get("limit") == "" {
+		filters.Limit = 50
 	}
 
 	if h.eventStore == nil {
@@ -119,7 +109,7 @@ func (h *Handler) GetRecentErrors(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventList, err := h.eventStore.GetRecentErrors(limit)
+	eventList, err := h.eventStore.GetRecentErrors(filters)
 	if err != nil {
 		h.logger.Error(err, "failed to get recent errors")
 		WriteError(w, h.logger, err)
@@ -155,3 +145,74 @@ func (h *Handler) CleanupEvents(w http.ResponseWriter, r *http.Request) {
 
 	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{"message": "Events cleaned up successfully"})
 }
+
+// TrimEvents deletes the oldest events of every resource over ?max= events, or over the
+// configured per-resource limit when max is omitted
+func (h *Handler) TrimEvents(w http.ResponseWriter, r *http.Request) {
+	maxEvents := 0
+	if maxStr := r.URL.Query().Get("max"); maxStr != "" {
+		parsed, err := strconv.Atoi(maxStr)
+		if err != nil || parsed < 1 {
+			WriteError(w, h.logger, fmt.Errorf("%w: max must be a positive integer", apperrors.ErrInvalidParameter))
+			return
+		}
+		maxEvents = parsed
+	}
+
+	if h.eventStore == nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: event store not available", apperrors.ErrEventStore))
+		return
+	}
+
+	trimmed, err := h.eventStore.TrimAll(maxEvents)
+	if err != nil {
+		h.logger.Error(err, "failed to trim events")
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, EventTrimResponse{Trimmed: trimmed})
+}
+
+// StreamEvents streams newly stored events as Server-Sent Events until the client disconnects
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if h.eventStore == nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: event store not available", apperrors.ErrEventStore))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "streaming_unsupported", "Streaming not supported", nil)
+		return
+	}
+
+	ctx := r.Context()
+	eventCh := h.eventStore.Subscribe(ctx)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Error(err, "failed to marshal streamed event", "eventID", event.ID)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
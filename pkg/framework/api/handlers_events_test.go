@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -220,3 +224,151 @@ func TestCleanupEvents_Success(t *testing.T) {
 	}
 }
 
+func TestStreamEvents_MultipleClients(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	server := httptest.NewServer(handler.SetupRoutes())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	readers := make([]*bufio.Reader, 0, 2)
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/events/stream", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("stream request %d error = %v", i, err)
+		}
+		defer resp.Body.Close()
+
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("stream %d Content-Type = %q, want text/event-stream", i, ct)
+		}
+		readers = append(readers, bufio.NewReader(resp.Body))
+	}
+
+	event := events.Info("default/Service/test", "apply", "streamed event")
+	if err := handler.eventStore.StoreEvent(event); err != nil {
+		t.Fatalf("StoreEvent() error = %v", err)
+	}
+
+	for i, reader := range readers {
+		lineCh := make(chan string, 1)
+		go func(reader *bufio.Reader) {
+			line, _ := reader.ReadString('\n')
+			lineCh <- line
+		}(reader)
+
+		select {
+		case line := <-lineCh:
+			if !strings.HasPrefix(line, "data: ") {
+				t.Fatalf("stream %d line = %q, want data: prefix", i, line)
+			}
+			var got events.Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "data: ")), &got); err != nil {
+				t.Fatalf("stream %d event is not valid JSON: %v", i, err)
+			}
+			if got.Message != "streamed event" {
+				t.Errorf("stream %d event message = %q, want %q", i, got.Message, "streamed event")
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("stream %d did not receive event within timeout", i)
+		}
+	}
+}
+
+func TestTrimEvents(t *testing.T) {
+	handler, _, eventStore := setupTestHandlerWithEventStore(t)
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		event := events.Info("default/Deployment/web", "apply", "event "+strconv.Itoa(i))
+		event.Timestamp = start.Add(time.Duration(i) * time.Second)
+		if err := eventStore.StoreEvent(event); err != nil {
+			t.Fatalf("StoreEvent() error = %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/events/trim?max=5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("TrimEvents() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp EventTrimResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("TrimEvents() response is not valid JSON: %v", err)
+	}
+	if resp.Trimmed != 2 {
+		t.Errorf("TrimEvents() trimmed = %d, want 2", resp.Trimmed)
+	}
+
+	remaining, err := eventStore.GetEventsByResource("default/Deployment/web", events.EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(remaining) != 5 {
+		t.Errorf("GetEventsByResource() returned %d events, want 5", len(remaining))
+	}
+}
+
+func TestTrimEvents_InvalidMax(t *testing.T) {
+	handler, _, _ := setupTestHandlerWithEventStore(t)
+
+	for _, max := range []string{"0", "-1", "many"} {
+		w := httptest.NewRecorder()
+		handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", "/api/events/trim?max="+max, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("TrimEvents(max=%s) status = %d, want %d", max, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestEventHandlers_SeverityFilter(t *testing.T) {
+	handler, _, eventStore := setupTestHandlerWithEventStore(t)
+	for _, event := range []events.Event{
+		events.Info("default/Deployment/web", "apply", "applied"),
+		events.Warning("default/Deployment/web", "apply", "slow rollout"),
+		events.Error("default/Deployment/web", "apply", "failed", errors.New("boom")),
+	} {
+		if err := eventStore.StoreEvent(event); err != nil {
+			t.Fatalf("failed to store test event: %v", err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/events?severity=error,warning", want: 2},
+		{path: "/api/events/errors", want: 1},
+		{path: "/api/events/errors?severity=error,warning", want: 2},
+		{path: "/api/events/default/Deployment/web?severity=info", want: 1},
+		{path: "/api/events/default/Deployment/web", want: 3},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want %d: %s", tt.path, w.Code, http.StatusOK, w.Body.String())
+		}
+		var got []events.Event
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("GET %s response is not valid JSON: %v", tt.path, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("GET %s returned %d events, want %d", tt.path, len(got), tt.want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/events?severity=fatal", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/events?severity=fatal status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
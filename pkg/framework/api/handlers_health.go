@@ -1,10 +1,21 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
 )
 
+// parameterStoreCheckTimeout bounds how long Readyz waits for the DeploymentParameters instance
+const parameterStoreCheckTimeout = 2 * time.Second
+
 func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
 	status := HealthStatus{
 		Status:    "healthy",
@@ -22,46 +33,65 @@ func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
 		Timestamp:  time.Now(),
 		Components: make(map[string]ComponentStatus),
 	}
+	setComponent := func(name string, component ComponentStatus) {
+		component.LastChecked = time.Now()
+		status.Components[name] = component
+	}
 
 	if h.store != nil {
-		_ = h.store.List()
-		status.Components["database"] = ComponentStatus{Status: "healthy"}
+		count := h.store.Count()
+		setComponent("database", ComponentStatus{Status: "healthy"})
+		setComponent("manifests", ComponentStatus{Status: "healthy", Count: &count})
 	} else {
-		status.Components["database"] = ComponentStatus{
+		setComponent("database", ComponentStatus{
 			Status:  "unhealthy",
 			Message: "Store not initialized",
-		}
+		})
 		status.Status = "unhealthy"
 	}
 
 	if h.reconciler != nil {
-		if !h.reconciler.IsReady() {
-			status.Components["manager"] = ComponentStatus{
+		// A paused reconciler is reported but keeps the pod ready so it can still be resumed
+		if h.reconciler.IsPaused() {
+			setComponent("manager", ComponentStatus{
+				Status:  "paused",
+				Message: "Periodic reconciliation paused",
+			})
+		} else if !h.reconciler.IsReady() {
+			setComponent("manager", ComponentStatus{
 				Status:  "not_ready",
 				Message: "Manager not ready",
-			}
+			})
 			status.Status = "unhealthy"
 		} else {
-			status.Components["manager"] = ComponentStatus{Status: "ready"}
+			setComponent("manager", ComponentStatus{Status: "ready"})
+		}
+	}
+
+	if h.parameterClient != nil {
+		parameterStore := h.parameterStoreStatus(r.Context())
+		setComponent("parameterStore", parameterStore)
+		if parameterStore.Status == "unhealthy" {
+			status.Status = "unhealthy"
 		}
 	}
 
 	if h.eventStore != nil {
 
-		_, err := h.eventStore.GetRecentErrors(1)
+		_, err := h.eventStore.GetRecentErrors(events.EventFilters{Limit: 1})
 		if err != nil {
-			status.Components["eventStore"] = ComponentStatus{
+			setComponent("eventStore", ComponentStatus{
 				Status:  "unavailable",
 				Message: err.Error(),
-			}
+			})
 		} else {
-			status.Components["eventStore"] = ComponentStatus{Status: "available"}
+			setComponent("eventStore", ComponentStatus{Status: "available"})
 		}
 	} else {
-		status.Components["eventStore"] = ComponentStatus{
+		setComponent("eventStore", ComponentStatus{
 			Status:  "unavailable",
 			Message: "Event store not initialized",
-		}
+		})
 	}
 
 	statusCode := http.StatusOK
@@ -71,3 +101,41 @@ func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
 
 	WriteJSONResponse(w, h.logger, statusCode, status)
 }
+
+// parameterStoreStatus checks that the default DeploymentParameters instance can be read.
+// A missing instance or CRD is a warning and an unreachable API server degrades the
+// component; only other errors make it unhealthy.
+func (h *Handler) parameterStoreStatus(ctx context.Context) ComponentStatus {
+	namespace := "default"
+	if h.store != nil {
+		if detected := h.getDetectedNamespace(); detected != "" {
+			namespace = detected
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, parameterStoreCheckTimeout)
+	defer cancel()
+	params, err := h.parameterClient.Get(ctx, crd.DefaultName, namespace)
+	switch {
+	case err != nil && isKubernetesUnavailable(err):
+		return ComponentStatus{Status: "degraded", Reason: "kubernetes_unavailable", Message: err.Error()}
+	case err != nil:
+		return ComponentStatus{Status: "unhealthy", Reason: "parameters_unreadable", Message: err.Error()}
+	case params == nil:
+		return ComponentStatus{Status: "warning", Reason: "crd_not_found"}
+	default:
+		return ComponentStatus{Status: "healthy"}
+	}
+}
+
+// isKubernetesUnavailable reports whether err means the Kubernetes API server could not be
+// reached or did not answer in time, rather than rejecting the request
+func isKubernetesUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr) ||
+		k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsTooManyRequests(err)
+}
@@ -1,10 +1,23 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
 )
 
 func TestHealthz(t *testing.T) {
@@ -196,3 +209,178 @@ func TestCORSPreflight(t *testing.T) {
 	}
 }
 
+func TestReadyz_ManifestCount(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.store.Create("default/Service/test", []byte(createTestManifest("Service", "test", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+
+	handler.Readyz(w, req)
+
+	var status HealthStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Readyz() response is not valid JSON: %v", err)
+	}
+
+	manifests, ok := status.Components["manifests"]
+	if !ok {
+		t.Fatal("Readyz() missing manifests component")
+	}
+	if manifests.Status != "healthy" {
+		t.Errorf("Readyz() manifests status = %v, want healthy", manifests.Status)
+	}
+	if manifests.Count == nil || *manifests.Count != 1 {
+		t.Errorf("Readyz() manifests count = %v, want 1", manifests.Count)
+	}
+}
+
+func TestReadyz_ManagerPaused(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	readyzManagerStatus := func() string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Readyz() status code = %v, want %v", w.Code, http.StatusOK)
+		}
+		var status HealthStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Readyz() response is not valid JSON: %v", err)
+		}
+		return status.Components["manager"].Status
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/reconciler/pause", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PauseReconciler() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if rec.IsReady() {
+		t.Error("IsReady() = true while paused, want false")
+	}
+	if got := readyzManagerStatus(); got != "paused" {
+		t.Errorf("Readyz() manager status = %v, want paused", got)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/reconciler/resume", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ResumeReconciler() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if got := readyzManagerStatus(); got != "ready" {
+		t.Errorf("Readyz() manager status = %v, want ready", got)
+	}
+}
+
+func TestReadyz_ParameterStore(t *testing.T) {
+	deploymentParametersGR := schema.GroupResource{Group: "conductor.io", Resource: "deploymentparameters"}
+	tests := []struct {
+		name        string
+		setup       func(*dynamicfake.FakeDynamicClient)
+		wantStatus  string
+		wantReason  string
+		wantCode    int
+		wantOverall string
+	}{
+		{
+			name: "instance exists",
+			setup: func(client *dynamicfake.FakeDynamicClient) {
+				params := &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "conductor.io/v1alpha1",
+					"kind":       "DeploymentParameters",
+					"metadata":   map[string]interface{}{"name": "default", "namespace": "default"},
+					"spec":       map[string]interface{}{},
+				}}
+				gvr := schema.GroupVersionResource{Group: "conductor.io", Version: "v1alpha1", Resource: "deploymentparameters"}
+				if _, err := client.Resource(gvr).Namespace("default").Create(context.Background(), params, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create DeploymentParameters: %v", err)
+				}
+			},
+			wantStatus:  "healthy",
+			wantCode:    http.StatusOK,
+			wantOverall: "healthy",
+		},
+		{
+			name:        "instance missing",
+			setup:       func(*dynamicfake.FakeDynamicClient) {},
+			wantStatus:  "warning",
+			wantReason:  "crd_not_found",
+			wantCode:    http.StatusOK,
+			wantOverall: "healthy",
+		},
+		{
+			name: "api server unavailable",
+			setup: func(client *dynamicfake.FakeDynamicClient) {
+				client.PrependReactor("get", "deploymentparameters", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, k8serrors.NewServiceUnavailable("apiserver is shutting down")
+				})
+			},
+			wantStatus:  "degraded",
+			wantReason:  "kubernetes_unavailable",
+			wantCode:    http.StatusOK,
+			wantOverall: "healthy",
+		},
+		{
+			name: "get forbidden",
+			setup: func(client *dynamicfake.FakeDynamicClient) {
+				client.PrependReactor("get", "deploymentparameters", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, k8serrors.NewForbidden(deploymentParametersGR, "default", errors.New("no RBAC"))
+				})
+			},
+			wantStatus:  "unhealthy",
+			wantReason:  "parameters_unreadable",
+			wantCode:    http.StatusServiceUnavailable,
+			wantOverall: "unhealthy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			tt.setup(dynamicClient)
+			parameterClient := crd.NewClient(dynamicClient, logr.Discard(), "conductor.io", "v1alpha1", "deploymentparameters")
+			handler, err := newTestHandler(t, WithTestReconciler(setupTestReconciler(t, true)), WithTestParameterClient(parameterClient))
+			if err != nil {
+				t.Fatalf("newTestHandler() error = %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			handler.Readyz(w, httptest.NewRequest("GET", "/readyz", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("Readyz() status code = %v, want %v", w.Code, tt.wantCode)
+			}
+
+			var status HealthStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("Readyz() response is not valid JSON: %v", err)
+			}
+			if status.Status != tt.wantOverall {
+				t.Errorf("Readyz() status = %v, want %v", status.Status, tt.wantOverall)
+			}
+			parameterStore, ok := status.Components["parameterStore"]
+			if !ok {
+				t.Fatal("Readyz() missing parameterStore component")
+			}
+			if parameterStore.Status != tt.wantStatus || parameterStore.Reason != tt.wantReason {
+				t.Errorf("Readyz() parameterStore = %+v, want status %q reason %q", parameterStore, tt.wantStatus, tt.wantReason)
+			}
+			for name, component := range status.Components {
+				if component.LastChecked.IsZero() {
+					t.Errorf("Readyz() %s lastChecked is not set", name)
+				}
+			}
+		})
+	}
+}
@@ -5,13 +5,18 @@ import (
 	"net/http"
 
 	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
 )
 
 // getNamespaceAndInstance extracts the namespace and instance name from the request.
-// It returns the detected namespace (from manifests) and instance name (from query parameter).
-// The namespace defaults to "default" if not detected.
+// It returns the namespace query parameter when set, otherwise the detected namespace (from manifests),
+// and the instance name (from query parameter). The namespace defaults to "default" if not detected.
 func (h *Handler) getNamespaceAndInstance(r *http.Request) (namespace, instance string) {
 	instance = getInstanceName(r)
+	if namespace = getQueryNamespace(r); namespace != "" {
+		return namespace, instance
+	}
 	namespace = h.getDetectedNamespace()
 	if namespace == "" {
 		namespace = "default"
@@ -51,12 +56,13 @@ func (h *Handler) getCRDSchemaWithFallback(ctx context.Context) (map[string]inte
 }
 
 // getSpecWithFallback retrieves the parameter spec with fallback to default namespace.
-// It tries the detected namespace first, then falls back to "default" namespace if not found.
-func (h *Handler) getSpecWithFallback(ctx context.Context, instanceName, detectedNamespace string) (map[string]interface{}, error) {
+// It tries the detected namespace first, then falls back to "default" namespace if not found
+// and fallback is set. Callers disable the fallback when the namespace was requested explicitly.
+func (h *Handler) getSpecWithFallback(ctx context.Context, instanceName, detectedNamespace string, fallback bool) (map[string]interface{}, error) {
 	spec, err := h.parameterClient.GetSpec(ctx, instanceName, detectedNamespace)
 	if err != nil || spec == nil || len(spec) == 0 {
 		// Fallback to default namespace
-		if detectedNamespace != "default" {
+		if fallback && detectedNamespace != "default" {
 			spec, err = h.parameterClient.GetSpec(ctx, instanceName, "default")
 		}
 		if err != nil || spec == nil {
@@ -77,3 +83,19 @@ func (h *Handler) getCRDSchemaRawWithFallback(ctx context.Context, logger logr.L
 	return crdSchema
 }
 
+// validateParametersSpec checks spec against the spec schema of the installed CRD. Validation is
+// skipped when the CRD cannot be read, so local development without the CRD keeps working.
+func (h *Handler) validateParametersSpec(ctx context.Context, spec map[string]interface{}) []crd.FieldError {
+	crdSchema, err := h.parameterClient.GetCRDSchema(ctx)
+	if err != nil {
+		h.logger.V(1).Info("failed to get CRD schema, skipping parameter validation", "error", err)
+		return nil
+	}
+
+	properties, _ := crdSchema["properties"].(map[string]interface{})
+	specSchema, ok := properties["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return crd.ValidateSpecAgainstSchema(spec, specSchema)
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 )
 
 func TestWriteYAMLResponse(t *testing.T) {
@@ -37,3 +41,60 @@ metadata:
 	}
 }
 
+func TestWriteErrorResponse_Details(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	WriteErrorResponse(w, handler.logger, http.StatusInternalServerError, "apply_failed", "apply failed",
+		map[string]interface{}{"key": "default/Deployment/app", "http_code": 422})
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("WriteErrorResponse() response is not valid JSON: %v", err)
+	}
+	if resp.Details["key"] != "default/Deployment/app" {
+		t.Errorf("WriteErrorResponse() details[key] = %v, want default/Deployment/app", resp.Details["key"])
+	}
+	// JSON numbers decode as float64
+	if resp.Details["http_code"] != float64(422) {
+		t.Errorf("WriteErrorResponse() details[http_code] = %v, want 422", resp.Details["http_code"])
+	}
+}
+
+func TestWriteErrorResponse_NilDetailsOmitted(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	WriteErrorResponse(w, handler.logger, http.StatusBadRequest, "invalid_request", "bad", nil)
+
+	if strings.Contains(w.Body.String(), "details") {
+		t.Errorf("WriteErrorResponse() body = %s, want no details field", w.Body.String())
+	}
+}
+
+func TestWriteError_PassesDetails(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	WriteError(w, handler.logger, apperrors.WithDetails(apperrors.WrapInvalid(errors.New("bad value"), "parse"), map[string]interface{}{"line": 3}))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("WriteError() status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("WriteError() response is not valid JSON: %v", err)
+	}
+	if resp.Details["line"] != float64(3) {
+		t.Errorf("WriteError() details[line] = %v, want 3", resp.Details["line"])
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// jobRetention is how long a finished job stays available at GET /api/jobs/{id}
+const jobRetention = time.Hour
+
+// jobStore keeps the background jobs started by API calls in memory. The zero value is an empty store.
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// start records a running job of jobType, runs fn in the background and returns the job ID
+// together with a channel closed once fn has returned
+func (s *jobStore) start(jobType string, fn func() error) (string, <-chan struct{}) {
+	job := &Job{
+		ID:        uuid.New().String(),
+		Type:      jobType,
+		Status:    JobStatusRunning,
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	if s.jobs == nil {
+		s.jobs = make(map[string]*Job)
+	}
+	s.pruneLocked(job.CreatedAt)
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := fn()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		now := time.Now()
+		job.CompletedAt = &now
+		job.Status = JobStatusSucceeded
+		if err != nil {
+			job.Status = JobStatusFailed
+			job.Error = err.Error()
+		}
+	}()
+	return job.ID, done
+}
+
+// get returns a copy of the job with id
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// pruneLocked drops jobs that finished more than jobRetention before now; callers hold s.mu
+func (s *jobStore) pruneLocked(now time.Time) {
+	for id, job := range s.jobs {
+		if job.CompletedAt != nil && now.Sub(*job.CompletedAt) > jobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// GetJob reports the status of a background job
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	job, ok := h.jobs.get(id)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: job %s", apperrors.ErrNotFound, id))
+		return
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, job)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobStore(t *testing.T) {
+	var store jobStore
+
+	okID, okDone := store.start("test", func() error { return nil })
+	failedID, failedDone := store.start("test", func() error { return errors.New("boom") })
+	<-okDone
+	<-failedDone
+
+	if job, _ := store.get(okID); job.Status != JobStatusSucceeded || job.CompletedAt == nil {
+		t.Errorf("successful job = %+v, want status %s with completed_at", job, JobStatusSucceeded)
+	}
+	if job, _ := store.get(failedID); job.Status != JobStatusFailed || job.Error != "boom" {
+		t.Errorf("failed job = %+v, want status %s with error boom", job, JobStatusFailed)
+	}
+
+	// Finished jobs are dropped once they are older than jobRetention
+	store.mu.Lock()
+	store.pruneLocked(time.Now().Add(jobRetention + time.Minute))
+	store.mu.Unlock()
+	if _, ok := store.get(okID); ok {
+		t.Error("job still stored after its retention expired")
+	}
+}
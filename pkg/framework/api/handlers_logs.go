@@ -0,0 +1,177 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// logCopyBufferSize is how much of a plain log stream is read before it is flushed to the client
+const logCopyBufferSize = 32 * 1024
+
+// PodLogs streams the logs of a pod as text/plain.
+// Supports ?container=, ?tail= (lines), ?since= (duration such as 10m) and ?follow=true.
+func (h *Handler) PodLogs(w http.ResponseWriter, r *http.Request) {
+	opts, err := podLogOptions(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	stream, err := h.openPodLogs(r, chi.URLParam(r, "namespace"), chi.URLParam(r, "pod"), opts)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, logCopyBufferSize)
+	for {
+		n, readErr := stream.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr != nil {
+			if readErr != io.EOF && r.Context().Err() == nil {
+				h.logger.Error(readErr, "failed to read pod logs", "namespace", chi.URLParam(r, "namespace"), "pod", chi.URLParam(r, "pod"))
+			}
+			return
+		}
+	}
+}
+
+// StreamPodLogs follows the logs of a pod as Server-Sent Events, one data frame per log line.
+// It accepts the same query parameters as PodLogs and sends an "end" event when the log stream closes.
+func (h *Handler) StreamPodLogs(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "streaming_unsupported", "Streaming not supported", nil)
+		return
+	}
+
+	opts, err := podLogOptions(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	opts.Follow = true
+
+	stream, err := h.openPodLogs(r, chi.URLParam(r, "namespace"), chi.URLParam(r, "pod"), opts)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if _, err := io.WriteString(w, sseFrame("", scanner.Text())); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+
+	if err := scanner.Err(); err != nil {
+		if r.Context().Err() != nil {
+			return
+		}
+		h.logger.Error(err, "failed to read pod logs", "namespace", chi.URLParam(r, "namespace"), "pod", chi.URLParam(r, "pod"))
+		_, _ = io.WriteString(w, sseFrame("error", err.Error()))
+	} else {
+		_, _ = io.WriteString(w, sseFrame("end", "EOF"))
+	}
+	flusher.Flush()
+}
+
+// openPodLogs validates the pod reference and opens its log stream in the cluster r selected
+func (h *Handler) openPodLogs(r *http.Request, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if !isValidKubernetesName(namespace) {
+		return nil, fmt.Errorf("%w: %q", apperrors.ErrInvalidNamespace, namespace)
+	}
+	if errs := validation.IsDNS1123Subdomain(pod); len(errs) > 0 {
+		return nil, fmt.Errorf("%w: invalid pod name %q", apperrors.ErrInvalidRequest, pod)
+	}
+
+	rec := h.reconcilerFor(r)
+	if rec == nil || rec.GetClientset() == nil {
+		return nil, fmt.Errorf("%w: Kubernetes client not available", apperrors.ErrKubernetes)
+	}
+
+	stream, err := rec.GetClientset().CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(r.Context())
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: pod %s/%s", apperrors.ErrNotFound, namespace, pod)
+		}
+		return nil, fmt.Errorf("%w: failed to stream logs for pod %s/%s: %w", apperrors.ErrKubernetes, namespace, pod, err)
+	}
+	return stream, nil
+}
+
+// podLogOptions maps the container, tail, since and follow query parameters to PodLogOptions
+func podLogOptions(r *http.Request) (*corev1.PodLogOptions, error) {
+	query := r.URL.Query()
+	opts := &corev1.PodLogOptions{Container: query.Get("container")}
+
+	if tail := query.Get("tail"); tail != "" {
+		lines, err := strconv.ParseInt(tail, 10, 64)
+		if err != nil || lines < 0 {
+			return nil, fmt.Errorf("%w: tail must be a non-negative number of lines", apperrors.ErrInvalidParameter)
+		}
+		opts.TailLines = &lines
+	}
+
+	if since := query.Get("since"); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: since must be a positive duration such as 10m", apperrors.ErrInvalidParameter)
+		}
+		seconds := int64(math.Ceil(d.Seconds()))
+		opts.SinceSeconds = &seconds
+	}
+
+	if follow := query.Get("follow"); follow != "" {
+		value, err := strconv.ParseBool(follow)
+		if err != nil {
+			return nil, fmt.Errorf("%w: follow must be true or false", apperrors.ErrInvalidParameter)
+		}
+		opts.Follow = value
+	}
+
+	return opts, nil
+}
+
+// sseFrame formats data as a Server-Sent Events frame, naming the event when event is not empty
+func sseFrame(event, data string) string {
+	if event == "" {
+		return fmt.Sprintf("data: %s\n\n", data)
+	}
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)
+}
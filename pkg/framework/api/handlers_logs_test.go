@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newPodLogsTestHandler(t *testing.T) (*Handler, *kubefake.Clientset) {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	return handler, rec.GetClientset().(*kubefake.Clientset)
+}
+
+// lastLogOptions returns the options of the most recent pod log request made through clientset
+func lastLogOptions(t *testing.T, clientset *kubefake.Clientset) *corev1.PodLogOptions {
+	t.Helper()
+	actions := clientset.Actions()
+	for i := len(actions) - 1; i >= 0; i-- {
+		if action, ok := actions[i].(k8stesting.GenericActionImpl); ok && action.Subresource == "log" {
+			return action.Value.(*corev1.PodLogOptions)
+		}
+	}
+	t.Fatal("no pod log request recorded")
+	return nil
+}
+
+func TestPodLogs(t *testing.T) {
+	handler, clientset := newPodLogsTestHandler(t)
+
+	req := httptest.NewRequest("GET", "/api/logs/default/web-0?container=app&tail=50&since=90s", nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/plain", got)
+	}
+	if got := w.Body.String(); got != "fake logs" {
+		t.Errorf("body = %q, want %q", got, "fake logs")
+	}
+
+	opts := lastLogOptions(t, clientset)
+	if opts.Container != "app" {
+		t.Errorf("Container = %q, want app", opts.Container)
+	}
+	if opts.TailLines == nil || *opts.TailLines != 50 {
+		t.Errorf("TailLines = %v, want 50", opts.TailLines)
+	}
+	if opts.SinceSeconds == nil || *opts.SinceSeconds != 90 {
+		t.Errorf("SinceSeconds = %v, want 90", opts.SinceSeconds)
+	}
+	if opts.Follow {
+		t.Error("Follow = true, want false without ?follow")
+	}
+}
+
+func TestPodLogOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+		check   func(t *testing.T, opts *corev1.PodLogOptions)
+	}{
+		{
+			name:  "no parameters",
+			query: "",
+			check: func(t *testing.T, opts *corev1.PodLogOptions) {
+				if opts.Container != "" || opts.TailLines != nil || opts.SinceSeconds != nil || opts.Follow {
+					t.Errorf("options = %+v, want zero value", opts)
+				}
+			},
+		},
+		{
+			name:  "since rounds up to whole seconds",
+			query: "since=1500ms",
+			check: func(t *testing.T, opts *corev1.PodLogOptions) {
+				if opts.SinceSeconds == nil || *opts.SinceSeconds != 2 {
+					t.Errorf("SinceSeconds = %v, want 2", opts.SinceSeconds)
+				}
+			},
+		},
+		{
+			name:  "follow",
+			query: "follow=true",
+			check: func(t *testing.T, opts *corev1.PodLogOptions) {
+				if !opts.Follow {
+					t.Error("Follow = false, want true")
+				}
+			},
+		},
+		{name: "negative tail", query: "tail=-1", wantErr: true},
+		{name: "non-numeric tail", query: "tail=all", wantErr: true},
+		{name: "invalid since", query: "since=yesterday", wantErr: true},
+		{name: "invalid follow", query: "follow=maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/logs/default/web-0?"+tt.query, nil)
+			opts, err := podLogOptions(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("podLogOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, opts)
+			}
+		})
+	}
+}
+
+func TestPodLogs_InvalidRequest(t *testing.T) {
+	handler, _ := newPodLogsTestHandler(t)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "invalid namespace", path: "/api/logs/Bad_NS/web-0"},
+		{name: "invalid pod name", path: "/api/logs/default/Web_0"},
+		{name: "invalid tail", path: "/api/logs/default/web-0?tail=x"},
+		{name: "invalid since on stream", path: "/api/logs/default/web-0/stream?since=x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestStreamPodLogs(t *testing.T) {
+	handler, clientset := newPodLogsTestHandler(t)
+
+	req := httptest.NewRequest("GET", "/api/logs/default/web-0/stream?container=app&tail=10", nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	if want := "data: fake logs\n\nevent: end\ndata: EOF\n\n"; w.Body.String() != want {
+		t.Errorf("body = %q, want %q", w.Body.String(), want)
+	}
+
+	opts := lastLogOptions(t, clientset)
+	if !opts.Follow {
+		t.Error("Follow = false, want true for the SSE stream")
+	}
+	if opts.Container != "app" || opts.TailLines == nil || *opts.TailLines != 10 {
+		t.Errorf("options = %+v, want container app and tail 10", opts)
+	}
+}
+
+func TestSSEFrame(t *testing.T) {
+	if got, want := sseFrame("", "line"), "data: line\n\n"; got != want {
+		t.Errorf("sseFrame(\"\", line) = %q, want %q", got, want)
+	}
+	if got, want := sseFrame("end", "EOF"), "event: end\ndata: EOF\n\n"; got != want {
+		t.Errorf("sseFrame(end, EOF) = %q, want %q", got, want)
+	}
+}
+
+func TestPodLogs_NoReconciler(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/logs/default/web-0", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/store"
 )
 
 func TestGetManifest_NotFound(t *testing.T) {
@@ -55,12 +59,12 @@ func TestListManifests(t *testing.T) {
 		t.Errorf("ListManifests() status code = %v, want %v", w.Code, http.StatusOK)
 	}
 
-	var manifests map[string]string
-	if err := json.Unmarshal(w.Body.Bytes(), &manifests); err != nil {
+	var resp ListManifestsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("ListManifests() response is not valid JSON: %v", err)
 	}
 
-	if len(manifests) == 0 {
+	if len(resp.Manifests) == 0 {
 		t.Error("ListManifests() returned no manifests")
 	}
 }
@@ -173,6 +177,7 @@ func TestUpdateManifest_Success(t *testing.T) {
 	}`
 	req := httptest.NewRequest("PUT", "/manifests/default/Service/test-service", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", quoteETag(store.ComputeETag([]byte(testManifest))))
 	w := httptest.NewRecorder()
 
 	handler.UpdateManifest(w, req)
@@ -226,6 +231,7 @@ func TestDeleteManifest_Success(t *testing.T) {
 	}
 
 	req := httptest.NewRequest("DELETE", "/manifests/default/Service/test-service", nil)
+	req.Header.Set("If-Match", quoteETag(store.ComputeETag([]byte(testManifest))))
 	w := httptest.NewRecorder()
 
 	handler.DeleteManifest(w, req)
@@ -258,3 +264,284 @@ func TestDeleteManifest_NotFound(t *testing.T) {
 	}
 }
 
+func TestListManifestFiles_EmptyFS(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	router := handler.SetupRoutes()
+	req := httptest.NewRequest("GET", "/api/manifests/files", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListManifestFiles() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var files []string
+	if err := json.Unmarshal(w.Body.Bytes(), &files); err != nil {
+		t.Fatalf("ListManifestFiles() response is not a JSON array: %v", err)
+	}
+	if files == nil || len(files) != 0 {
+		t.Errorf("ListManifestFiles() = %v, want empty array", files)
+	}
+}
+
+func TestCreateManifest_KeyMismatch(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	reqBody := `{
+		"key": "default/Service/other",
+		"value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: test-service\n"
+	}`
+	req := httptest.NewRequest("POST", "/manifests", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
+
+	handler.CreateManifest(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("CreateManifest() status code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+	if _, ok := handler.store.Get("default/Service/other"); ok {
+		t.Error("CreateManifest() stored a manifest whose key does not match its metadata")
+	}
+}
+
+func TestValidateManifest(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantValid  bool
+		wantFields []string
+	}{
+		{
+			name:      "valid manifest",
+			body:      `{"key": "default/Service/web", "value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"}`,
+			wantValid: true,
+		},
+		{
+			name:       "missing fields",
+			body:       `{"key": "default/Service/web", "value": "metadata:\n  name: web\n"}`,
+			wantFields: []string{"apiVersion", "kind"},
+		},
+		{
+			name:       "key does not match metadata",
+			body:       `{"key": "default/Service/api", "value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"}`,
+			wantFields: []string{"metadata.name"},
+		},
+		{
+			name:       "unparseable YAML",
+			body:       `{"key": "default/Service/web", "value": "invalid: yaml: ["}`,
+			wantFields: []string{"value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/manifests/validate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("ValidateManifest() status code = %v, want %v", w.Code, http.StatusOK)
+			}
+
+			var resp ValidateManifestResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("ValidateManifest() response is not valid JSON: %v", err)
+			}
+			if resp.Valid != tt.wantValid {
+				t.Errorf("ValidateManifest() valid = %v, want %v", resp.Valid, tt.wantValid)
+			}
+			if len(resp.Errors) != len(tt.wantFields) {
+				t.Fatalf("ValidateManifest() errors = %v, want fields %v", resp.Errors, tt.wantFields)
+			}
+			for i, field := range tt.wantFields {
+				if resp.Errors[i].Field != field {
+					t.Errorf("ValidateManifest() errors[%d].field = %q, want %q", i, resp.Errors[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestManifestETag_ConditionalUpdate(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	key := "default/Service/etag-service"
+	if err := handler.store.Create(key, []byte(createTestManifest("Service", "etag-service", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	getETag := func() string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/manifests/"+key, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetManifest() status code = %v, want %v", w.Code, http.StatusOK)
+		}
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("GetManifest() did not set an ETag header")
+		}
+		return etag
+	}
+	update := func(ifMatch, name string) int {
+		body := `{"value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: ` + name + `\n  namespace: default\nspec: {}"}`
+		req := httptest.NewRequest("PUT", "/manifests/"+key, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	original := getETag()
+
+	if code := update("", "no-precondition"); code != http.StatusPreconditionRequired {
+		t.Errorf("UpdateManifest() without If-Match status code = %v, want %v", code, http.StatusPreconditionRequired)
+	}
+
+	if code := update(original, "first-update"); code != http.StatusOK {
+		t.Fatalf("UpdateManifest() with current ETag status code = %v, want %v", code, http.StatusOK)
+	}
+
+	updated := getETag()
+	if updated == original {
+		t.Error("ETag did not change after update")
+	}
+
+	if code := update(original, "stale-update"); code != http.StatusPreconditionFailed {
+		t.Errorf("UpdateManifest() with stale ETag status code = %v, want %v", code, http.StatusPreconditionFailed)
+	}
+	manifest, _ := handler.store.Get(key)
+	if !strings.Contains(string(manifest), "first-update") {
+		t.Error("UpdateManifest() with stale ETag overwrote the manifest")
+	}
+}
+
+func TestManifestETag_StaleDelete(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	key := "default/Service/etag-delete"
+	if err := handler.store.Create(key, []byte(createTestManifest("Service", "etag-delete", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	req := httptest.NewRequest("DELETE", "/manifests/"+key, nil)
+	req.Header.Set("If-Match", `"stale"`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("DeleteManifest() status code = %v, want %v", w.Code, http.StatusPreconditionFailed)
+	}
+	if _, ok := handler.store.Get(key); !ok {
+		t.Error("DeleteManifest() with stale ETag deleted the manifest")
+	}
+}
+
+func TestManifestETag_ConcurrentUpdates(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	key := "default/Service/etag-race"
+	original := createTestManifest("Service", "etag-race", "default")
+	if err := handler.store.Create(key, []byte(original)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	ifMatch := quoteETag(store.ComputeETag([]byte(original)))
+
+	const writers = 10
+	codes := make(chan int, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: etag-race\n  namespace: default\n  labels:\n    writer: w%d\n"}`, i)
+			req := httptest.NewRequest("PUT", "/manifests/"+key, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", ifMatch)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes <- w.Code
+		}(i)
+	}
+	wg.Wait()
+	close(codes)
+
+	succeeded := 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			succeeded++
+		case http.StatusPreconditionFailed:
+		default:
+			t.Errorf("UpdateManifest() status code = %v, want %v or %v", code, http.StatusOK, http.StatusPreconditionFailed)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d updates with the same If-Match succeeded, want exactly 1", succeeded)
+	}
+}
+
+func TestManifestMultiDoc_RoundTrip(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	docs := []string{
+		createTestManifest("ConfigMap", "app-config", "default"),
+		createTestManifest("Service", "app", "default"),
+		createTestManifest("Secret", "app-secret", "default"),
+	}
+	value := strings.Join(docs, "---\n")
+	body, _ := json.Marshal(map[string]string{"key": "default/Bundle/app", "value": value})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/manifests/", strings.NewReader(string(body))))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateManifest() status code = %v, want %v, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	for _, key := range []string{"default/ConfigMap/app-config", "default/Service/app", "default/Secret/app-secret"} {
+		if _, ok := handler.store.Get(key); !ok {
+			t.Errorf("document %s was not stored", key)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/manifests/default/Bundle/app", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetManifest() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != value {
+		t.Errorf("GetManifest() = %q, want %q", w.Body.String(), value)
+	}
+}
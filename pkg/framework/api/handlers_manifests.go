@@ -1,11 +1,15 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
-	"github.com/go-chi/chi/v5"
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+	"github.com/go-chi/chi/v5"
 )
 
 func extractManifestKey(r *http.Request) string {
@@ -32,9 +36,46 @@ func extractManifestKey(r *http.Request) string {
 	return key
 }
 
+// ListManifests returns the stored manifests, optionally filtered with
+// ?kind=, ?namespace= and ?label= (a label selector such as app=web,tier!=db),
+// together with a checksum of the returned manifests that changes whenever one of them does.
+// Any of ?page=, ?page_size=, ?sort_by=, ?sort_order= and ?prefix= returns one page instead;
+// see listManifestsPage.
 func (h *Handler) ListManifests(w http.ResponseWriter, r *http.Request) {
-	manifests := h.store.List()
-	WriteJSONResponse(w, h.logger, http.StatusOK, manifests)
+	if isPagedListRequest(r) {
+		h.listManifestsPage(w, r)
+		return
+	}
+
+	manifests, err := h.filterManifests(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	// The checksum is computed from the manifests being returned, so a write that lands after
+	// filterManifests cannot pair this body with the checksum of newer content
+	WriteJSONResponse(w, h.logger, http.StatusOK, ListManifestsResponse{
+		Manifests: manifests,
+		Checksum:  store.ComputeChecksum(manifests),
+	})
+}
+
+// ListManifestFiles returns the relative paths of all files in the embedded manifest filesystem
+func (h *Handler) ListManifestFiles(w http.ResponseWriter, r *http.Request) {
+	root := h.manifestRoot
+	if root == "" {
+		root = "manifests"
+	}
+
+	files, err := manifest.NewFileSystem(h.manifestFS, root).ListAll()
+	if err != nil {
+		h.logger.Error(err, "failed to list manifest files")
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, files)
 }
 
 func (h *Handler) GetManifest(w http.ResponseWriter, r *http.Request) {
@@ -45,12 +86,21 @@ func (h *Handler) GetManifest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	manifest, ok := h.store.Get(key)
+	st := h.storeFor(r)
+	manifest, etag, ok := st.GetWithETag(key)
 	if !ok {
 		WriteError(w, h.logger, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key))
 		return
 	}
 
+	w.Header().Set("ETag", quoteETag(etag))
+	if modified, ok := st.LastModified(key); ok {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	WriteYAMLResponse(w, h.logger, manifest)
 }
 
@@ -70,24 +120,88 @@ func (h *Handler) CreateManifest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ValidateYAML([]byte(req.Value)); err != nil {
+	if err := h.checkManifestKey(req.Key, []byte(req.Value)); err != nil {
+		h.writeKeyMismatch(w, err)
+		return
+	}
+
+	if err := validateManifestValue([]byte(req.Value), req.Key); err != nil {
 		WriteError(w, h.logger, err)
 		return
 	}
 
-	if err := h.store.Create(req.Key, []byte(req.Value)); err != nil {
+	st := h.storeFor(r)
+	previousChildren, _ := st.Children(req.Key)
+	if err := st.Create(req.Key, []byte(req.Value)); err != nil {
 		h.logger.Error(err, "failed to create manifest", "key", req.Key)
 		WriteError(w, h.logger, fmt.Errorf("creation failed: %w", err))
 		return
 	}
 
-	select {
-	case h.reconcileCh <- req.Key:
-	default:
+	h.queueManifestReconcile(r, req.Key, previousChildren)
+
+	w.WriteHeader(http.StatusCreated)
+}
 
+// ValidateManifest checks a {"key","value"} body the same way CreateManifest does, without storing it
+func (h *Handler) ValidateManifest(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	validationErrors, err := manifest.ValidateManifest([]byte(req.Value), req.Key)
+	if err != nil {
+		validationErrors = []manifest.ValidationError{{Field: "value", Message: err.Error()}}
+	}
+	if err := ValidateKey(req.Key); err != nil {
+		validationErrors = append(validationErrors, manifest.ValidationError{Field: "key", Message: err.Error()})
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, ValidateManifestResponse{
+		Valid:  len(validationErrors) == 0,
+		Errors: validationErrors,
+	})
+}
+
+// validateManifestValue runs manifest.ValidateManifest and folds any problems into one error.
+// Each document of a multi-document value is validated on its own against the key derived from it.
+func validateManifestValue(value []byte, key string) error {
+	docs, err := manifest.SplitMultiDoc(value)
+	if err != nil {
+		return apperrors.WithDetails(fmt.Errorf("%w: invalid YAML: %w", apperrors.ErrInvalidYAML, err), yamlErrorDetails(err, key))
+	}
+	if len(docs) > 1 {
+		for i, doc := range docs {
+			if err := validateManifestValue(doc, ""); err != nil {
+				details := map[string]interface{}{"document": i + 1}
+				if key != "" {
+					details["key"] = key
+				}
+				return apperrors.WithDetails(fmt.Errorf("document %d: %w", i+1, err), details)
+			}
+		}
+		return nil
+	}
+
+	validationErrors, err := manifest.ValidateManifest(value, key)
+	if err != nil {
+		return apperrors.WithDetails(fmt.Errorf("%w: invalid YAML: %w", apperrors.ErrInvalidYAML, err), yamlErrorDetails(err, key))
+	}
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(validationErrors))
+	for i, validationErr := range validationErrors {
+		messages[i] = validationErr.Error()
+	}
+	return fmt.Errorf("%w: %s", apperrors.ErrInvalid, strings.Join(messages, "; "))
 }
 
 func (h *Handler) UpdateManifest(w http.ResponseWriter, r *http.Request) {
@@ -112,16 +226,24 @@ func (h *Handler) UpdateManifest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.Update(key, []byte(req.Value)); err != nil {
+	st := h.storeFor(r)
+	previousChildren, _ := st.Children(key)
+	err := writeIfMatch(r, st, key, func() error {
+		return st.Update(key, []byte(req.Value))
+	}, func(txn *store.ManifestStoreTxn) error {
+		return txn.Update(key, []byte(req.Value))
+	})
+	if err != nil {
+		if isPreconditionError(err) {
+			WriteError(w, h.logger, err)
+			return
+		}
 		h.logger.Error(err, "failed to update manifest", "key", key)
 		WriteError(w, h.logger, fmt.Errorf("update failed: %w", err))
 		return
 	}
 
-	select {
-	case h.reconcileCh <- key:
-	default:
-	}
+	h.queueManifestReconcile(r, key, previousChildren)
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -134,16 +256,125 @@ func (h *Handler) DeleteManifest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.Delete(key); err != nil {
+	st := h.storeFor(r)
+	previousChildren, _ := st.Children(key)
+	err := writeIfMatch(r, st, key, func() error {
+		return st.Delete(key)
+	}, func(txn *store.ManifestStoreTxn) error {
+		return txn.Delete(key)
+	})
+	if err != nil {
+		if isPreconditionError(err) {
+			WriteError(w, h.logger, err)
+			return
+		}
 		h.logger.Error(err, "failed to delete manifest", "key", key)
 		WriteError(w, h.logger, fmt.Errorf("deletion failed: %w", err))
 		return
 	}
 
+	h.queueManifestReconcile(r, key, previousChildren)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeIfMatch runs write once the If-Match header of r matches the ETag of the manifest
+// stored under key. The ETag is compared in the same store transaction write runs in, so a
+// concurrent change cannot slip in between. Multi-document manifests cannot be written in a
+// transaction; for them writeParent runs after the comparison instead.
+func writeIfMatch(r *http.Request, st store.ManifestStore, key string, writeParent func() error, write func(txn *store.ManifestStoreTxn) error) error {
+	if _, isParent := st.Children(key); isParent {
+		current, ok := st.Get(key)
+		if !ok {
+			return fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key)
+		}
+		if err := checkIfMatch(r, key, store.ComputeETag(current)); err != nil {
+			return err
+		}
+		return writeParent()
+	}
+
+	return st.WithTransaction(func(txn *store.ManifestStoreTxn) error {
+		current, ok := txn.Get(key)
+		if !ok {
+			return fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key)
+		}
+		if err := checkIfMatch(r, key, store.ComputeETag(current)); err != nil {
+			return err
+		}
+		return write(txn)
+	})
+}
+
+// isPreconditionError reports whether err is a missing or mismatched If-Match, or a missing manifest
+func isPreconditionError(err error) bool {
+	return errors.Is(err, apperrors.ErrPreconditionRequired) || errors.Is(err, apperrors.ErrPreconditionFailed) ||
+		errors.Is(err, apperrors.ErrNotFound)
+}
+
+// checkIfMatch requires the If-Match header of r to carry etag, the current ETag of the manifest stored under key
+func checkIfMatch(r *http.Request, key, etag string) error {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return fmt.Errorf("%w: If-Match header with the manifest ETag is required", apperrors.ErrPreconditionRequired)
+	}
+	if etagMatches(ifMatch, etag) {
+		return nil
+	}
+	return fmt.Errorf("%w: manifest %s has been modified", apperrors.ErrPreconditionFailed, key)
+}
+
+// etagMatches reports whether the comma-separated ETags of an If-Match or If-None-Match header include etag
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if candidate = unquoteETag(candidate); candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteETag(etag string) string {
+	return `"` + etag + `"`
+}
+
+func unquoteETag(value string) string {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	return strings.Trim(value, `"`)
+}
+
+// queueManifestReconcile queues key after a write, or for a multi-document manifest every
+// document it has now or had before, so dropped documents are removed from the cluster.
+// Manifests of a tenant selected with X-Tenant-ID are not reconciled by this instance.
+func (h *Handler) queueManifestReconcile(r *http.Request, key string, previousChildren []string) {
+	if tenantSelected(r) {
+		return
+	}
+	children, _ := h.store.Children(key)
+	if len(children) == 0 && len(previousChildren) == 0 {
+		h.queueReconcile(key)
+		return
+	}
+
+	queued := make(map[string]bool, len(children)+len(previousChildren))
+	for _, child := range append(children, previousChildren...) {
+		if !queued[child] {
+			queued[child] = true
+			h.queueReconcile(child)
+		}
+	}
+}
+
+// queueReconcile asks the reconcile loop to pick up key without blocking when it is busy and
+// reports whether key was queued
+func (h *Handler) queueReconcile(key string) bool {
 	select {
 	case h.reconcileCh <- key:
+		return true
 	default:
+		return false
 	}
-
-	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// maxImportArchiveBytes caps the size of an uploaded manifest archive
+const maxImportArchiveBytes = 32 << 20
+
+// Limits on what an uploaded archive decompresses to, so a small archive cannot expand
+// into an unbounded amount of memory
+const (
+	maxArchiveEntryBytes = 4 << 20
+	maxArchiveTotalBytes = 128 << 20
+	maxArchiveEntries    = 10000
+)
+
+// manifestFileExt is the extension of every manifest file inside an export archive
+const manifestFileExt = ".yaml"
+
+// ExportManifests writes every stored manifest into an archive as {namespace}/{kind}/{name}.yaml.
+// The archive is a gzipped tar by default, or a zip with ?format=zip.
+func (h *Handler) ExportManifests(w http.ResponseWriter, r *http.Request) {
+	manifests := h.storeFor(r).List()
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	var contentType, filename string
+	var err error
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "tar.gz", "tgz":
+		contentType, filename = "application/gzip", "manifests.tar.gz"
+		err = writeTarGzArchive(&buf, keys, manifests)
+	case "zip":
+		contentType, filename = "application/zip", "manifests.zip"
+		err = writeZipArchive(&buf, keys, manifests)
+	default:
+		WriteError(w, h.logger, fmt.Errorf("%w: unsupported export format %q", apperrors.ErrInvalidRequest, format))
+		return
+	}
+	if err != nil {
+		h.logger.Error(err, "failed to export manifests")
+		WriteError(w, h.logger, fmt.Errorf("export failed: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Error(err, "failed to write manifest archive")
+	}
+}
+
+// ImportManifests stores every manifest of an archive uploaded in the "file" form field.
+// The archive is a gzipped tar or a zip in the layout produced by ExportManifests.
+// Files are imported independently; failures are reported without rolling back the rest.
+// With strict key validation an archive holding a file whose path does not match its
+// metadata is rejected with 422 before anything is stored.
+func (h *Handler) ImportManifests(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportArchiveBytes)
+	if err := r.ParseMultipartForm(maxImportArchiveBytes); err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid multipart upload: %w", apperrors.ErrInvalidRequest, err))
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: missing archive in form field \"file\": %w", apperrors.ErrInvalidRequest, err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to read archive: %w", apperrors.ErrInvalidRequest, err))
+		return
+	}
+
+	files, err := readManifestArchive(data)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	resp := ImportManifestsResponse{
+		Created: []string{},
+		Updated: []string{},
+		Failed:  []ImportManifestError{},
+	}
+	for _, f := range files {
+		if key, err := importManifestKey(f.name); err == nil {
+			if err := h.checkManifestKey(key, f.content); err != nil {
+				resp.Failed = append(resp.Failed, ImportManifestError{File: f.name, Error: err.Error()})
+			}
+		}
+	}
+	if len(resp.Failed) > 0 {
+		WriteJSONResponse(w, h.logger, http.StatusUnprocessableEntity, resp)
+		return
+	}
+
+	st := h.storeFor(r)
+	for _, f := range files {
+		key, err := importManifestKey(f.name)
+		if err == nil {
+			err = validateManifestValue(f.content, key)
+		}
+		if err != nil {
+			resp.Failed = append(resp.Failed, ImportManifestError{File: f.name, Error: err.Error()})
+			continue
+		}
+
+		_, exists := st.Get(key)
+		previousChildren, _ := st.Children(key)
+		if err := st.Create(key, f.content); err != nil {
+			h.logger.Error(err, "failed to import manifest", "key", key)
+			resp.Failed = append(resp.Failed, ImportManifestError{File: f.name, Error: err.Error()})
+			continue
+		}
+		h.queueManifestReconcile(r, key, previousChildren)
+
+		if exists {
+			resp.Updated = append(resp.Updated, key)
+		} else {
+			resp.Created = append(resp.Created, key)
+		}
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, resp)
+}
+
+// archiveFile is one regular file read from an uploaded archive
+type archiveFile struct {
+	name    string
+	content []byte
+}
+
+// archiveBudget counts the entries and decompressed bytes read from an archive
+type archiveBudget struct {
+	entries int
+	total   int64
+}
+
+// entry counts one more archive entry against maxArchiveEntries
+func (b *archiveBudget) entry() error {
+	b.entries++
+	if b.entries > maxArchiveEntries {
+		return fmt.Errorf("%w: archive has more than %d entries", apperrors.ErrInvalidRequest, maxArchiveEntries)
+	}
+	return nil
+}
+
+// read returns the content of the archive entry name, failing once it is larger than
+// maxArchiveEntryBytes or the archive has decompressed to more than maxArchiveTotalBytes
+func (b *archiveBudget) read(name string, r io.Reader) ([]byte, error) {
+	limit := min(int64(maxArchiveEntryBytes), maxArchiveTotalBytes-b.total)
+	content, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %w", apperrors.ErrInvalidRequest, name, err)
+	}
+	if int64(len(content)) > limit {
+		if limit < maxArchiveEntryBytes {
+			return nil, fmt.Errorf("%w: archive decompresses to more than %d bytes: %w", apperrors.ErrInvalidRequest, maxArchiveTotalBytes, &http.MaxBytesError{Limit: maxArchiveTotalBytes})
+		}
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes: %w", apperrors.ErrInvalidRequest, name, maxArchiveEntryBytes, &http.MaxBytesError{Limit: maxArchiveEntryBytes})
+	}
+	b.total += int64(len(content))
+	return content, nil
+}
+
+func writeTarGzArchive(w io.Writer, keys []string, manifests map[string][]byte) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := time.Now()
+	for _, key := range keys {
+		content := manifests[key]
+		header := &tar.Header{
+			Name:    key + manifestFileExt,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("write tar header for %s: %w", key, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("write tar entry for %s: %w", key, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar: %w", err)
+	}
+	return gz.Close()
+}
+
+func writeZipArchive(w io.Writer, keys []string, manifests map[string][]byte) error {
+	zw := zip.NewWriter(w)
+	for _, key := range keys {
+		entry, err := zw.Create(key + manifestFileExt)
+		if err != nil {
+			return fmt.Errorf("create zip entry for %s: %w", key, err)
+		}
+		if _, err := entry.Write(manifests[key]); err != nil {
+			return fmt.Errorf("write zip entry for %s: %w", key, err)
+		}
+	}
+	return zw.Close()
+}
+
+// readManifestArchive returns the regular files of a gzipped tar or zip archive,
+// telling the two apart by their leading magic bytes. Archives with more than
+// maxArchiveEntries entries or files larger than maxArchiveEntryBytes are rejected, as are
+// archives that decompress to more than maxArchiveTotalBytes.
+func readManifestArchive(data []byte) ([]archiveFile, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		return readZipArchive(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		return readTarGzArchive(data)
+	default:
+		return nil, fmt.Errorf("%w: archive must be a gzipped tar or a zip file", apperrors.ErrInvalidRequest)
+	}
+}
+
+func readTarGzArchive(data []byte) ([]archiveFile, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid gzip archive: %w", apperrors.ErrInvalidRequest, err)
+	}
+	defer gz.Close()
+
+	var files []archiveFile
+	var budget archiveBudget
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid tar archive: %w", apperrors.ErrInvalidRequest, err)
+		}
+		if err := budget.entry(); err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := budget.read(header.Name, tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archiveFile{name: header.Name, content: content})
+	}
+	return files, nil
+}
+
+func readZipArchive(data []byte) ([]archiveFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid zip archive: %w", apperrors.ErrInvalidRequest, err)
+	}
+
+	var files []archiveFile
+	var budget archiveBudget
+	for _, entry := range zr.File {
+		if err := budget.entry(); err != nil {
+			return nil, err
+		}
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to open %s: %w", apperrors.ErrInvalidRequest, entry.Name, err)
+		}
+		content, err := budget.read(entry.Name, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archiveFile{name: entry.Name, content: content})
+	}
+	return files, nil
+}
+
+// importManifestKey turns an archive path {namespace}/{kind}/{name}.yaml back into a manifest key
+func importManifestKey(name string) (string, error) {
+	key := strings.TrimPrefix(name, "./")
+	if !strings.HasSuffix(key, manifestFileExt) {
+		return "", fmt.Errorf("%w: %s is not a %s file", apperrors.ErrInvalid, name, manifestFileExt)
+	}
+	key = strings.TrimSuffix(key, manifestFileExt)
+	if parts := strings.Split(key, "/"); len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("%w: %s does not match {namespace}/{kind}/{name}%s", apperrors.ErrInvalid, name, manifestFileExt)
+	}
+	return key, ValidateKey(key)
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func postManifestArchive(t *testing.T, handler *Handler, archive []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "manifests.tar.gz")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	if _, err := part.Write(archive); err != nil {
+		t.Fatalf("failed to write form file: %v", err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("failed to close multipart writer: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/manifests/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestManifestArchive_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		wantContentType string
+	}{
+		{name: "tar.gz", query: "", wantContentType: "application/gzip"},
+		{name: "zip", query: "?format=zip", wantContentType: "application/zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := newTestHandler(t)
+			if err != nil {
+				t.Fatalf("newTestHandler() error = %v", err)
+			}
+			for i := 0; i < 10; i++ {
+				entry := bulkConfigMap(fmt.Sprintf("config-%d", i))
+				if err := source.store.Create(entry.Key, []byte(entry.Value)); err != nil {
+					t.Fatalf("store.Create() error = %v", err)
+				}
+			}
+
+			w := httptest.NewRecorder()
+			source.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/manifests/export"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("ExportManifests() status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("ExportManifests() Content-Type = %v, want %v", got, tt.wantContentType)
+			}
+
+			target, err := newTestHandler(t)
+			if err != nil {
+				t.Fatalf("newTestHandler() error = %v", err)
+			}
+			imported := postManifestArchive(t, target, w.Body.Bytes())
+			if imported.Code != http.StatusOK {
+				t.Fatalf("ImportManifests() status code = %v, want %v, body = %s", imported.Code, http.StatusOK, imported.Body.String())
+			}
+
+			var resp ImportManifestsResponse
+			if err := json.Unmarshal(imported.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("ImportManifests() response is not valid JSON: %v", err)
+			}
+			if len(resp.Created) != 10 || len(resp.Updated) != 0 || len(resp.Failed) != 0 {
+				t.Errorf("ImportManifests() summary = %d created, %d updated, %d failed, want 10/0/0: %+v", len(resp.Created), len(resp.Updated), len(resp.Failed), resp)
+			}
+
+			want := source.store.List()
+			got := target.store.List()
+			if len(got) != len(want) {
+				t.Fatalf("imported store has %d manifests, want %d", len(got), len(want))
+			}
+			for key, value := range want {
+				if !bytes.Equal(got[key], value) {
+					t.Errorf("imported manifest %s = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestImportManifests_Summary(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	existing := bulkConfigMap("existing")
+	if err := handler.store.Create(existing.Key, []byte(existing.Value)); err != nil {
+		t.Fatalf("store.Create() error = %v", err)
+	}
+
+	fresh := bulkConfigMap("fresh")
+	manifests := map[string][]byte{
+		existing.Key:            []byte(existing.Value),
+		fresh.Key:               []byte(fresh.Value),
+		"default/ConfigMap/bad": []byte("not: [valid"),
+	}
+	var archive bytes.Buffer
+	if err := writeTarGzArchive(&archive, []string{existing.Key, fresh.Key, "default/ConfigMap/bad"}, manifests); err != nil {
+		t.Fatalf("writeTarGzArchive() error = %v", err)
+	}
+
+	w := postManifestArchive(t, handler, archive.Bytes())
+	if w.Code != http.StatusOK {
+		t.Fatalf("ImportManifests() status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp ImportManifestsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ImportManifests() response is not valid JSON: %v", err)
+	}
+	if len(resp.Created) != 1 || resp.Created[0] != fresh.Key {
+		t.Errorf("ImportManifests() created = %v, want [%s]", resp.Created, fresh.Key)
+	}
+	if len(resp.Updated) != 1 || resp.Updated[0] != existing.Key {
+		t.Errorf("ImportManifests() updated = %v, want [%s]", resp.Updated, existing.Key)
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0].File != "default/ConfigMap/bad.yaml" {
+		t.Errorf("ImportManifests() failed = %v, want default/ConfigMap/bad.yaml", resp.Failed)
+	}
+}
+
+func TestImportManifests_InvalidArchive(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := postManifestArchive(t, handler, []byte("plain text"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ImportManifests() status code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestImportManifests_RejectsOversizedEntry(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	// Zeros compress well, so the archive stays far below the upload limit
+	var buf bytes.Buffer
+	content := bytes.Repeat([]byte{0}, maxArchiveEntryBytes+1)
+	if err := writeTarGzArchive(&buf, []string{"default/ConfigMap/big"}, map[string][]byte{"default/ConfigMap/big": content}); err != nil {
+		t.Fatalf("writeTarGzArchive() error = %v", err)
+	}
+
+	w := postManifestArchive(t, handler, buf.Bytes())
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("ImportManifests() status code = %v, want %v", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	if _, ok := handler.store.Get("default/ConfigMap/big"); ok {
+		t.Error("ImportManifests() stored the oversized entry")
+	}
+}
+
+func TestImportManifests_RejectsTooManyEntries(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	keys := make([]string, 0, maxArchiveEntries+1)
+	manifests := make(map[string][]byte, maxArchiveEntries+1)
+	for i := 0; i <= maxArchiveEntries; i++ {
+		key := fmt.Sprintf("default/ConfigMap/cm-%d", i)
+		keys = append(keys, key)
+		manifests[key] = nil
+	}
+	var buf bytes.Buffer
+	if err := writeZipArchive(&buf, keys, manifests); err != nil {
+		t.Fatalf("writeZipArchive() error = %v", err)
+	}
+
+	w := postManifestArchive(t, handler, buf.Bytes())
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ImportManifests() status code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
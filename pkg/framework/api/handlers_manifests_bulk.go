@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// maxBulkManifests caps the number of entries accepted by one bulk request
+const maxBulkManifests = 500
+
+// BulkCreateManifests validates every entry and then stores them all in one transaction.
+// If any entry is invalid or its key already holds a manifest, nothing is stored and the
+// per-entry errors are returned with 422.
+func (h *Handler) BulkCreateManifests(w http.ResponseWriter, r *http.Request) {
+	var req BulkCreateManifestsRequest
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if err := validateBulkSize(len(req.Manifests)); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	st := h.storeFor(r)
+	entries := make(map[string][]byte, len(req.Manifests))
+	var entryErrors []BulkManifestError
+	for i, entry := range req.Manifests {
+		err := ValidateKey(entry.Key)
+		if err == nil {
+			err = h.checkManifestKey(entry.Key, []byte(entry.Value))
+		}
+		if err == nil {
+			err = validateManifestValue([]byte(entry.Value), entry.Key)
+		}
+		if err == nil {
+			if _, duplicate := entries[entry.Key]; duplicate {
+				err = fmt.Errorf("%w: duplicate key %s", apperrors.ErrInvalid, entry.Key)
+			} else if _, exists := st.Get(entry.Key); exists {
+				err = fmt.Errorf("%w: manifest already exists: %s", apperrors.ErrInvalid, entry.Key)
+			}
+		}
+		if err != nil {
+			entryErrors = append(entryErrors, BulkManifestError{Index: i, Key: entry.Key, Error: err.Error()})
+			continue
+		}
+		entries[entry.Key] = []byte(entry.Value)
+	}
+
+	if len(entryErrors) > 0 {
+		WriteJSONResponse(w, h.logger, http.StatusUnprocessableEntity, BulkValidationErrorResponse{
+			Error:   "validation_failed",
+			Message: fmt.Sprintf("%d of %d manifests failed validation, none were stored", len(entryErrors), len(req.Manifests)),
+			Errors:  entryErrors,
+		})
+		return
+	}
+
+	if err := st.CreateBatch(entries); err != nil {
+		h.logger.Error(err, "failed to create manifests in bulk", "count", len(entries))
+		WriteError(w, h.logger, fmt.Errorf("bulk creation failed: %w", err))
+		return
+	}
+
+	if !tenantSelected(r) {
+		for key := range entries {
+			h.queueReconcile(key)
+		}
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusCreated, map[string]interface{}{
+		"message": fmt.Sprintf("Created %d manifests", len(entries)),
+		"count":   len(entries),
+	})
+}
+
+// BulkDeleteManifests deletes every listed manifest in one transaction.
+// If any key is invalid or missing nothing is deleted.
+func (h *Handler) BulkDeleteManifests(w http.ResponseWriter, r *http.Request) {
+	var req BulkDeleteManifestsRequest
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if err := validateBulkSize(len(req.Keys)); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	seen := make(map[string]bool, len(req.Keys))
+	keys := make([]string, 0, len(req.Keys))
+	for _, key := range req.Keys {
+		if err := ValidateKey(key); err != nil {
+			WriteError(w, h.logger, err)
+			return
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	if err := h.storeFor(r).DeleteBatch(keys); err != nil {
+		h.logger.Error(err, "failed to delete manifests in bulk", "count", len(keys))
+		WriteError(w, h.logger, fmt.Errorf("bulk deletion failed: %w", err))
+		return
+	}
+
+	if !tenantSelected(r) {
+		for _, key := range keys {
+			h.queueReconcile(key)
+		}
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Deleted %d manifests", len(keys)),
+		"count":   len(keys),
+	})
+}
+
+func validateBulkSize(n int) error {
+	if n == 0 {
+		return fmt.Errorf("%w: at least one entry is required", apperrors.ErrInvalidRequest)
+	}
+	if n > maxBulkManifests {
+		return fmt.Errorf("%w: at most %d entries are allowed per request", apperrors.ErrInvalidRequest, maxBulkManifests)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func bulkConfigMap(name string) BulkManifestEntry {
+	return BulkManifestEntry{
+		Key:   "default/ConfigMap/" + name,
+		Value: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n",
+	}
+}
+
+func postBulkManifests(t *testing.T, handler *Handler, entries []BulkManifestEntry) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(BulkCreateManifestsRequest{Manifests: entries})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	handler.BulkCreateManifests(w, httptest.NewRequest("POST", "/api/manifests/bulk", strings.NewReader(string(body))))
+	return w
+}
+
+func TestBulkCreateManifests_Success(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	entries := make([]BulkManifestEntry, 100)
+	for i := range entries {
+		entries[i] = bulkConfigMap(fmt.Sprintf("cm-%d", i))
+	}
+
+	w := postBulkManifests(t, handler, entries)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("BulkCreateManifests() status code = %v, want %v: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	for _, entry := range entries {
+		if _, ok := handler.store.Get(entry.Key); !ok {
+			t.Fatalf("BulkCreateManifests() did not store %s", entry.Key)
+		}
+	}
+}
+
+func TestBulkCreateManifests_ValidationFailureStoresNothing(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	invalid := BulkManifestEntry{Key: "default/ConfigMap/broken", Value: "metadata:\n  name: broken\n"}
+	w := postBulkManifests(t, handler, []BulkManifestEntry{bulkConfigMap("good"), invalid, bulkConfigMap("also-good")})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("BulkCreateManifests() status code = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	var resp BulkValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("BulkCreateManifests() response is not valid JSON: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Index != 1 || resp.Errors[0].Key != invalid.Key {
+		t.Errorf("BulkCreateManifests() errors = %+v, want one error for entry 1", resp.Errors)
+	}
+	if handler.store.Count() != 0 {
+		t.Errorf("store has %d manifests after rejected batch, want 0", handler.store.Count())
+	}
+}
+
+func TestBulkCreateManifests_DuplicateKey(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := postBulkManifests(t, handler, []BulkManifestEntry{bulkConfigMap("dup"), bulkConfigMap("dup")})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("BulkCreateManifests() status code = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	var resp BulkValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("BulkCreateManifests() response is not valid JSON: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Index != 1 || !strings.Contains(resp.Errors[0].Error, "duplicate") {
+		t.Errorf("BulkCreateManifests() errors = %+v, want a duplicate error for entry 1", resp.Errors)
+	}
+	if handler.store.Count() != 0 {
+		t.Errorf("store has %d manifests after rejected batch, want 0", handler.store.Count())
+	}
+}
+
+func TestBulkCreateManifests_ExistingKey(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	existing := bulkConfigMap("existing")
+	if err := handler.store.Create(existing.Key, []byte(existing.Value)); err != nil {
+		t.Fatalf("failed to create manifest: %v", err)
+	}
+
+	w := postBulkManifests(t, handler, []BulkManifestEntry{bulkConfigMap("new"), existing})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("BulkCreateManifests() status code = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	var resp BulkValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("BulkCreateManifests() response is not valid JSON: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Index != 1 || !strings.Contains(resp.Errors[0].Error, "already exists") {
+		t.Errorf("BulkCreateManifests() errors = %+v, want an already exists error for entry 1", resp.Errors)
+	}
+	if handler.store.Count() != 1 {
+		t.Errorf("store has %d manifests after rejected batch, want 1", handler.store.Count())
+	}
+}
+
+func TestBulkCreateManifests_TooManyEntries(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	entries := make([]BulkManifestEntry, maxBulkManifests+1)
+	for i := range entries {
+		entries[i] = bulkConfigMap(fmt.Sprintf("cm-%d", i))
+	}
+	if w := postBulkManifests(t, handler, entries); w.Code != http.StatusBadRequest {
+		t.Errorf("BulkCreateManifests() status code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestBulkDeleteManifests(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	if w := postBulkManifests(t, handler, []BulkManifestEntry{bulkConfigMap("a"), bulkConfigMap("b")}); w.Code != http.StatusCreated {
+		t.Fatalf("BulkCreateManifests() status code = %v, want %v", w.Code, http.StatusCreated)
+	}
+
+	deleteKeys := func(keys ...string) int {
+		body, _ := json.Marshal(BulkDeleteManifestsRequest{Keys: keys})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/manifests/bulk", strings.NewReader(string(body))))
+		return w.Code
+	}
+
+	if code := deleteKeys("default/ConfigMap/a", "default/ConfigMap/missing"); code != http.StatusNotFound {
+		t.Fatalf("BulkDeleteManifests() with missing key status code = %v, want %v", code, http.StatusNotFound)
+	}
+	if handler.store.Count() != 2 {
+		t.Fatalf("store has %d manifests after rejected delete, want 2", handler.store.Count())
+	}
+
+	if code := deleteKeys("default/ConfigMap/a", "default/ConfigMap/b"); code != http.StatusOK {
+		t.Fatalf("BulkDeleteManifests() status code = %v, want %v", code, http.StatusOK)
+	}
+	if handler.store.Count() != 0 {
+		t.Errorf("store has %d manifests after delete, want 0", handler.store.Count())
+	}
+}
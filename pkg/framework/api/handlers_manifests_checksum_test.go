@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+func listManifestsChecksum(t *testing.T, handler *Handler, query string) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/manifests"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListManifests() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp ListManifestsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ListManifests() response is not valid JSON: %v", err)
+	}
+	if resp.Checksum == "" {
+		t.Fatal("ListManifests() returned an empty checksum")
+	}
+	if want := store.ComputeChecksum(resp.Manifests); resp.Checksum != want {
+		t.Fatalf("ListManifests() checksum = %s, want the checksum of the returned manifests %s", resp.Checksum, want)
+	}
+	return resp.Checksum
+}
+
+func TestListManifests_Checksum(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	key := "default/Service/checksum"
+	empty := listManifestsChecksum(t, handler, "")
+
+	if err := handler.store.Create(key, []byte(createTestManifest("Service", "checksum", "default"))); err != nil {
+		t.Fatalf("failed to create manifest: %v", err)
+	}
+	created := listManifestsChecksum(t, handler, "")
+	if created == empty {
+		t.Error("checksum did not change after create")
+	}
+	if again := listManifestsChecksum(t, handler, ""); again != created {
+		t.Errorf("checksum changed without a write: %s != %s", again, created)
+	}
+
+	if err := handler.store.Update(key, []byte(createTestManifest("Service", "checksum-updated", "default"))); err != nil {
+		t.Fatalf("failed to update manifest: %v", err)
+	}
+	updated := listManifestsChecksum(t, handler, "")
+	if updated == created {
+		t.Error("checksum did not change after update")
+	}
+
+	if err := handler.store.Delete(key); err != nil {
+		t.Fatalf("failed to delete manifest: %v", err)
+	}
+	if deleted := listManifestsChecksum(t, handler, ""); deleted != empty {
+		t.Errorf("checksum after delete = %s, want the empty checksum %s", deleted, empty)
+	}
+}
+
+func TestListManifests_FilteredChecksum(t *testing.T) {
+	handler := newFilterTestHandler(t)
+
+	staging := listManifestsChecksum(t, handler, "?namespace=staging")
+	if err := handler.store.Update("default/Deployment/web", []byte(labeledTestManifest("Deployment", "web", "default", "    app: api\n"))); err != nil {
+		t.Fatalf("failed to update manifest: %v", err)
+	}
+	if got := listManifestsChecksum(t, handler, "?namespace=staging"); got != staging {
+		t.Error("checksum of the staging manifests changed after a write to another namespace")
+	}
+}
+
+func TestGetManifest_ConditionalGet(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	key := "default/Service/conditional"
+	if err := handler.store.Create(key, []byte(createTestManifest("Service", "conditional", "default"))); err != nil {
+		t.Fatalf("failed to create manifest: %v", err)
+	}
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/manifests/"+key, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetManifest() status = %d, want %d", w.Code, http.StatusOK)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("GetManifest() did not set an ETag header")
+	}
+	lastModified, err := http.ParseTime(w.Header().Get("Last-Modified"))
+	if err != nil {
+		t.Fatalf("Last-Modified header %q: %v", w.Header().Get("Last-Modified"), err)
+	}
+	if time.Since(lastModified) > time.Minute {
+		t.Errorf("Last-Modified = %v, want the time the manifest was created", lastModified)
+	}
+
+	w = get(etag)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("GetManifest() with current ETag status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("304 response has a body: %q", w.Body.String())
+	}
+
+	if err := handler.store.Update(key, []byte(createTestManifest("Service", "conditional-updated", "default"))); err != nil {
+		t.Fatalf("failed to update manifest: %v", err)
+	}
+	if w = get(etag); w.Code != http.StatusOK {
+		t.Errorf("GetManifest() with stale ETag status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// maxDependencyDepth bounds how far dependency chains are followed
+const maxDependencyDepth = 10
+
+// dependencyEdge is a directed edge from a manifest to one of its dependencies
+type dependencyEdge struct {
+	from string
+	to   string
+}
+
+// GetManifestDependencies returns the conductor.io/depends-on graph of a manifest in DOT format
+func (h *Handler) GetManifestDependencies(w http.ResponseWriter, r *http.Request) {
+	key := fmt.Sprintf("%s/%s/%s", chi.URLParam(r, "namespace"), chi.URLParam(r, "kind"), chi.URLParam(r, "name"))
+
+	if err := ValidateKey(key); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "dot" {
+		WriteError(w, h.logger, fmt.Errorf("%w: unsupported format %q (must be dot)", apperrors.ErrInvalidParameter, format))
+		return
+	}
+
+	st := h.storeFor(r)
+	if _, ok := st.Get(key); !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key))
+		return
+	}
+
+	edges, err := resolveDependencyEdges(st, key)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(renderDOT(edges))); err != nil {
+		h.logger.Error(err, "failed to write DOT response")
+	}
+}
+
+// resolveDependencyEdges walks depends-on annotations from key, returning every edge
+// reachable within maxDependencyDepth. A cycle is reported as an ErrInvalid error.
+func resolveDependencyEdges(st store.ManifestStore, key string) ([]dependencyEdge, error) {
+	var edges []dependencyEdge
+	visited := make(map[string]bool)
+	onPath := make(map[string]bool)
+
+	var walk func(current string, depth int, path []string) error
+	walk = func(current string, depth int, path []string) error {
+		if onPath[current] {
+			return fmt.Errorf("%w: dependency cycle detected: %s", apperrors.ErrInvalid, strings.Join(append(path, current), " -> "))
+		}
+		if visited[current] || depth > maxDependencyDepth {
+			return nil
+		}
+
+		data, ok := st.Get(current)
+		if !ok {
+			// Unknown dependencies are kept as leaf nodes
+			visited[current] = true
+			return nil
+		}
+
+		deps, err := manifest.ParseDependencies(data)
+		if err != nil {
+			return fmt.Errorf("%w: manifest %s: %w", apperrors.ErrInvalidYAML, current, err)
+		}
+
+		onPath[current] = true
+		for _, dep := range deps {
+			edges = append(edges, dependencyEdge{from: current, to: dep})
+			if err := walk(dep, depth+1, append(path, current)); err != nil {
+				return err
+			}
+		}
+		onPath[current] = false
+		visited[current] = true
+		return nil
+	}
+
+	if err := walk(key, 0, nil); err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// renderDOT renders edges as a Graphviz digraph with a stable edge order
+func renderDOT(edges []dependencyEdge) string {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from == edges[j].from {
+			return edges[i].to < edges[j].to
+		}
+		return edges[i].from < edges[j].from
+	})
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", edge.from, edge.to)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// dependentManifest returns a manifest annotated with the given depends-on value
+func dependentManifest(kind, name, dependsOn string) string {
+	return `apiVersion: v1
+kind: ` + kind + `
+metadata:
+  name: ` + name + `
+  namespace: default
+  annotations:
+    conductor.io/depends-on: "` + dependsOn + `"
+`
+}
+
+func getDependencies(t *testing.T, handler *Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := handler.SetupRoutes()
+	req := httptest.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetManifestDependencies_Chain(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	manifests := map[string]string{
+		"default/Deployment/app": dependentManifest("Deployment", "app", "default/Service/api"),
+		"default/Service/api":    dependentManifest("Service", "api", "default/StatefulSet/db"),
+		"default/StatefulSet/db": createTestManifest("StatefulSet", "db", "default"),
+	}
+	for key, value := range manifests {
+		if err := handler.store.Create(key, []byte(value)); err != nil {
+			t.Fatalf("failed to create test manifest: %v", err)
+		}
+	}
+
+	w := getDependencies(t, handler, "/api/manifests/default/Deployment/app/dependencies?format=dot")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetManifestDependencies() status code = %v, want %v: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "digraph dependencies {") {
+		t.Errorf("GetManifestDependencies() body is not a DOT digraph: %s", body)
+	}
+	for _, edge := range []string{
+		`"default/Deployment/app" -> "default/Service/api";`,
+		`"default/Service/api" -> "default/StatefulSet/db";`,
+	} {
+		if !strings.Contains(body, edge) {
+			t.Errorf("GetManifestDependencies() missing edge %s in:\n%s", edge, body)
+		}
+	}
+	if strings.Count(body, "->") != 2 {
+		t.Errorf("GetManifestDependencies() expected 2 edges, got:\n%s", body)
+	}
+}
+
+func TestGetManifestDependencies_Cycle(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	if err := handler.store.Create("default/Service/a", []byte(dependentManifest("Service", "a", "default/Service/b"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	if err := handler.store.Create("default/Service/b", []byte(dependentManifest("Service", "b", "default/Service/a"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	w := getDependencies(t, handler, "/api/manifests/default/Service/a/dependencies")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("GetManifestDependencies() status code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("GetManifestDependencies() error response is not valid JSON: %v", err)
+	}
+	if !strings.Contains(errResp.Message, "cycle") {
+		t.Errorf("GetManifestDependencies() message = %q, want cycle error", errResp.Message)
+	}
+}
+
+func TestGetManifestDependencies_NoDependencies(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	if err := handler.store.Create("default/Service/solo", []byte(createTestManifest("Service", "solo", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	w := getDependencies(t, handler, "/api/manifests/default/Service/solo/dependencies")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetManifestDependencies() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != "digraph dependencies {\n}\n" {
+		t.Errorf("GetManifestDependencies() = %q, want empty graph", w.Body.String())
+	}
+}
+
+func TestGetManifestDependencies_Errors(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := getDependencies(t, handler, "/api/manifests/default/Service/missing/dependencies")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing manifest status code = %v, want %v", w.Code, http.StatusNotFound)
+	}
+
+	if err := handler.store.Create("default/Service/solo", []byte(createTestManifest("Service", "solo", "default"))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	w = getDependencies(t, handler, "/api/manifests/default/Service/solo/dependencies?format=json")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unsupported format status code = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
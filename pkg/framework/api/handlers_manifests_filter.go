@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// manifestLabelCache holds the metadata.labels parsed from each manifest, keyed by manifest
+// key and invalidated when the manifest content changes. The zero value is an empty cache.
+type manifestLabelCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedManifestLabels
+}
+
+type cachedManifestLabels struct {
+	etag   string
+	labels labels.Set
+}
+
+// get returns the labels of the manifest stored under key with the given content
+func (c *manifestLabelCache) get(key string, content []byte) labels.Set {
+	etag := store.ComputeETag(content)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok && entry.etag == etag {
+		return entry.labels
+	}
+
+	var obj struct {
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+	}
+	// Manifests that fail to parse simply have no labels
+	_ = yaml.Unmarshal(content, &obj)
+
+	if c.entries == nil {
+		c.entries = make(map[string]cachedManifestLabels)
+	}
+	set := labels.Set(obj.Metadata.Labels)
+	c.entries[key] = cachedManifestLabels{etag: etag, labels: set}
+	return set
+}
+
+// filterManifests returns the manifests matching the kind, namespace and label query parameters.
+// Kind and namespace match the manifest key exactly; label is a Kubernetes label selector.
+func (h *Handler) filterManifests(r *http.Request) (map[string][]byte, error) {
+	query := r.URL.Query()
+	kind := query.Get("kind")
+	namespace := query.Get("namespace")
+
+	var selector labels.Selector
+	if label := query.Get("label"); label != "" {
+		parsed, err := labels.Parse(label)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid label selector %q: %w", apperrors.ErrInvalidParameter, label, err)
+		}
+		selector = parsed
+	}
+
+	st := h.storeFor(r)
+	var manifests map[string][]byte
+	switch {
+	case kind != "":
+		manifests = st.ListByKind(kind)
+	case namespace != "":
+		manifests = st.ListByNamespace(namespace)
+	default:
+		manifests = st.List()
+	}
+
+	for key, content := range manifests {
+		if namespace != "" && !strings.HasPrefix(key, namespace+"/") {
+			delete(manifests, key)
+			continue
+		}
+		if selector != nil && !selector.Matches(h.manifestLabels.get(key, content)) {
+			delete(manifests, key)
+		}
+	}
+	return manifests, nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func labeledTestManifest(kind, name, namespace, labels string) string {
+	return `apiVersion: v1
+kind: ` + kind + `
+metadata:
+  name: ` + name + `
+  namespace: ` + namespace + `
+  labels:
+` + labels + `spec: {}
+`
+}
+
+func newFilterTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	manifests := map[string]string{
+		"default/Deployment/web": labeledTestManifest("Deployment", "web", "default", "    app: web\n    tier: frontend\n"),
+		"default/Service/web":    labeledTestManifest("Service", "web", "default", "    app: web\n"),
+		"default/Deployment/db":  labeledTestManifest("Deployment", "db", "default", "    app: db\n    tier: backend\n"),
+		"staging/Deployment/web": labeledTestManifest("Deployment", "web", "staging", "    app: web\n    tier: frontend\n"),
+		"staging/ConfigMap/cfg":  createTestManifest("ConfigMap", "cfg", "staging"),
+	}
+	for key, content := range manifests {
+		if err := handler.store.Create(key, []byte(content)); err != nil {
+			t.Fatalf("failed to create test manifest %s: %v", key, err)
+		}
+	}
+	return handler
+}
+
+func listManifestKeys(t *testing.T, handler *Handler, query url.Values) []string {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/manifests?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/manifests?%s status = %d, want %d: %s", query.Encode(), w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ListManifestsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+
+	keys := make([]string, 0, len(resp.Manifests))
+	for key := range resp.Manifests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestListManifests_Filters(t *testing.T) {
+	handler := newFilterTestHandler(t)
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []string
+	}{
+		{
+			name:  "no filters",
+			query: url.Values{},
+			want:  []string{"default/Deployment/db", "default/Deployment/web", "default/Service/web", "staging/ConfigMap/cfg", "staging/Deployment/web"},
+		},
+		{
+			name:  "kind",
+			query: url.Values{"kind": {"Deployment"}},
+			want:  []string{"default/Deployment/db", "default/Deployment/web", "staging/Deployment/web"},
+		},
+		{
+			name:  "namespace",
+			query: url.Values{"namespace": {"staging"}},
+			want:  []string{"staging/ConfigMap/cfg", "staging/Deployment/web"},
+		},
+		{
+			name:  "label equality",
+			query: url.Values{"label": {"app=web"}},
+			want:  []string{"default/Deployment/web", "default/Service/web", "staging/Deployment/web"},
+		},
+		{
+			name:  "label set-based selector",
+			query: url.Values{"label": {"tier in (frontend,backend),app!=web"}},
+			want:  []string{"default/Deployment/db"},
+		},
+		{
+			name:  "kind and namespace",
+			query: url.Values{"kind": {"Deployment"}, "namespace": {"default"}},
+			want:  []string{"default/Deployment/db", "default/Deployment/web"},
+		},
+		{
+			name:  "kind, namespace and label",
+			query: url.Values{"kind": {"Deployment"}, "namespace": {"default"}, "label": {"tier=frontend"}},
+			want:  []string{"default/Deployment/web"},
+		},
+		{
+			name:  "kind is matched exactly",
+			query: url.Values{"kind": {"deployment"}},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listManifestKeys(t, handler, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListManifests_LabelFilterSeesUpdates(t *testing.T) {
+	handler := newFilterTestHandler(t)
+	query := url.Values{"label": {"app=db"}}
+
+	if got, want := listManifestKeys(t, handler, query), []string{"default/Deployment/db"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+
+	updated := labeledTestManifest("Deployment", "db", "default", "    app: database\n")
+	if err := handler.store.Update("default/Deployment/db", []byte(updated)); err != nil {
+		t.Fatalf("failed to update manifest: %v", err)
+	}
+	if got := listManifestKeys(t, handler, query); len(got) != 0 {
+		t.Errorf("keys after relabel = %v, want none", got)
+	}
+}
+
+func TestListManifests_InvalidLabelSelector(t *testing.T) {
+	handler := newFilterTestHandler(t)
+
+	req := httptest.NewRequest("GET", "/api/manifests?label="+url.QueryEscape("app in (web"), nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// GetManifestHistory lists the versions the manifest replaced on each update, newest first
+func (h *Handler) GetManifestHistory(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.historyKey(w, r)
+	if !ok {
+		return
+	}
+
+	versions, err := h.storeFor(r).GetHistory(key)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if _, exists := h.storeFor(r).Get(key); !exists && len(versions) == 0 {
+		WriteError(w, h.logger, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key))
+		return
+	}
+
+	resp := ManifestHistoryResponse{Key: key, Versions: make([]ManifestVersion, 0, len(versions))}
+	for _, version := range versions {
+		resp.Versions = append(resp.Versions, manifestVersion(version))
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, resp)
+}
+
+// GetManifestVersion returns the YAML of the version of the manifest replaced at {timestamp}
+func (h *Handler) GetManifestVersion(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.historyKey(w, r)
+	if !ok {
+		return
+	}
+	version, ok := h.versionParam(w, r, key)
+	if !ok {
+		return
+	}
+	WriteYAMLResponse(w, h.logger, version.Value)
+}
+
+// RestoreManifestVersion writes the version of the manifest replaced at {timestamp} back to
+// the store. The content it replaces becomes a version of its own, so a restore can be undone.
+// A deleted manifest is recreated.
+func (h *Handler) RestoreManifestVersion(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.historyKey(w, r)
+	if !ok {
+		return
+	}
+	version, ok := h.versionParam(w, r, key)
+	if !ok {
+		return
+	}
+
+	st := h.storeFor(r)
+	previousChildren, _ := st.Children(key)
+	var err error
+	if _, exists := st.Get(key); exists {
+		err = st.Update(key, version.Value)
+	} else {
+		err = st.Create(key, version.Value)
+	}
+	if err != nil {
+		h.logger.Error(err, "failed to restore manifest", "key", key, "timestamp", version.Timestamp)
+		WriteError(w, h.logger, fmt.Errorf("restore failed: %w", err))
+		return
+	}
+
+	events.StoreEventSafeContext(r.Context(), h.eventStore, h.logger, events.ManifestRestored(key, version.Timestamp))
+	h.queueManifestReconcile(r, key, previousChildren)
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, manifestVersion(version))
+}
+
+// historyKey returns the namespace/Kind/name key of a history route, writing an error
+// response when it is invalid
+func (h *Handler) historyKey(w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := fmt.Sprintf("%s/%s/%s", chi.URLParam(r, "namespace"), chi.URLParam(r, "kind"), chi.URLParam(r, "name"))
+	if err := ValidateKey(key); err != nil {
+		WriteError(w, h.logger, err)
+		return "", false
+	}
+	return key, true
+}
+
+// versionParam returns the version of key named by the {timestamp} route parameter,
+// writing an error response when it is malformed or not found
+func (h *Handler) versionParam(w http.ResponseWriter, r *http.Request, key string) (store.ManifestVersion, bool) {
+	timestamp, err := strconv.ParseInt(chi.URLParam(r, "timestamp"), 10, 64)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: timestamp must be Unix nanoseconds", apperrors.ErrInvalidParameter))
+		return store.ManifestVersion{}, false
+	}
+	version, err := h.storeFor(r).GetVersion(key, timestamp)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return store.ManifestVersion{}, false
+	}
+	return version, true
+}
+
+func manifestVersion(version store.ManifestVersion) ManifestVersion {
+	return ManifestVersion{
+		Timestamp:  version.Timestamp,
+		ReplacedAt: time.Unix(0, version.Timestamp).UTC(),
+		Content:    string(version.Value),
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestManifestHistory_ListGetRestore(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	key := "default/ConfigMap/app"
+	versionManifest := func(i int) string {
+		return fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n  namespace: default\ndata:\n  version: \"%d\"\n", i)
+	}
+	if err := handler.store.Create(key, []byte(versionManifest(0))); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		if err := handler.store.Update(key, []byte(versionManifest(i))); err != nil {
+			t.Fatalf("failed to update test manifest: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/manifests/"+key+"/history", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetManifestHistory() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var history ManifestHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("GetManifestHistory() response is not valid JSON: %v", err)
+	}
+	if len(history.Versions) != 5 {
+		t.Fatalf("GetManifestHistory() returned %d versions, want 5", len(history.Versions))
+	}
+	for i, version := range history.Versions {
+		if version.Content != versionManifest(4-i) {
+			t.Errorf("version %d content = %q, want %q", i, version.Content, versionManifest(4-i))
+		}
+	}
+
+	oldest := history.Versions[4]
+	versionPath := fmt.Sprintf("/api/manifests/%s/history/%d", key, oldest.Timestamp)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", versionPath, nil))
+	if w.Code != http.StatusOK || w.Body.String() != versionManifest(0) {
+		t.Errorf("GetManifestVersion() = %d %q, want version 0", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", versionPath+"/restore", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("RestoreManifestVersion() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if current, _ := handler.store.Get(key); string(current) != versionManifest(0) {
+		t.Errorf("manifest after restore = %q, want version 0", current)
+	}
+	// The restore kept the content it replaced
+	if versions, _ := handler.store.GetHistory(key); len(versions) != 6 || string(versions[0].Value) != versionManifest(5) {
+		t.Errorf("history after restore has %d versions, want 6 with version 5 newest", len(versions))
+	}
+}
+
+func TestManifestHistory_Errors(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"GET", "/api/manifests/default/ConfigMap/missing/history", http.StatusNotFound},
+		{"GET", "/api/manifests/default/ConfigMap/missing/history/abc", http.StatusBadRequest},
+		{"GET", "/api/manifests/default/ConfigMap/missing/history/123", http.StatusNotFound},
+		{"POST", "/api/manifests/default/ConfigMap/missing/history/123/restore", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// SetStrictKeyValidation makes CreateManifest, BulkCreateManifests and ImportManifests reject
+// manifests whose key does not match their metadata with 422 key_mismatch
+func (h *Handler) SetStrictKeyValidation(strict bool) {
+	h.strictKeyValidation = strict
+}
+
+// checkManifestKey runs manifest.ValidateKey on a single-document value when strict key
+// validation is enabled. Multi-document values are stored under a parent key that does not
+// name any one document, and values that do not parse are left to validateManifestValue.
+func (h *Handler) checkManifestKey(key string, value []byte) error {
+	if !h.strictKeyValidation {
+		return nil
+	}
+	if docs, err := manifest.SplitMultiDoc(value); err != nil || len(docs) > 1 {
+		return nil
+	}
+	return manifest.ValidateKey(key, value)
+}
+
+func (h *Handler) writeKeyMismatch(w http.ResponseWriter, err error) {
+	WriteErrorResponse(w, h.logger, http.StatusUnprocessableEntity, "key_mismatch", err.Error(), nil)
+}
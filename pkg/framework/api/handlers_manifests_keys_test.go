@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newStrictKeyTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetStrictKeyValidation(true)
+	return handler
+}
+
+func TestCreateManifest_StrictKeyValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		wantCode int
+	}{
+		{name: "matching key", key: "default/Service/web", wantCode: http.StatusCreated},
+		{name: "mismatched name", key: "default/Service/api", wantCode: http.StatusUnprocessableEntity},
+		{name: "mismatched namespace", key: "prod/Service/web", wantCode: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newStrictKeyTestHandler(t)
+			body, _ := json.Marshal(map[string]string{
+				"key":   tt.key,
+				"value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+			})
+			w := httptest.NewRecorder()
+			handler.CreateManifest(w, httptest.NewRequest("POST", "/manifests", bytes.NewReader(body)))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("CreateManifest() status code = %v, want %v: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode == http.StatusUnprocessableEntity {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error != "key_mismatch" {
+					t.Errorf("CreateManifest() error = %q, want key_mismatch", resp.Error)
+				}
+				if _, ok := handler.store.Get(tt.key); ok {
+					t.Error("CreateManifest() stored a manifest whose key does not match its metadata")
+				}
+			}
+		})
+	}
+}
+
+func TestBulkCreateManifests_StrictKeyValidation(t *testing.T) {
+	handler := newStrictKeyTestHandler(t)
+	mismatched := bulkConfigMap("app")
+	mismatched.Key = "default/ConfigMap/other"
+
+	w := postBulkManifests(t, handler, []BulkManifestEntry{bulkConfigMap("web"), mismatched})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("BulkCreateManifests() status code = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+	}
+	var resp BulkValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("BulkCreateManifests() response is not valid JSON: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Index != 1 || !strings.Contains(resp.Errors[0].Error, "metadata.name") {
+		t.Errorf("BulkCreateManifests() errors = %+v, want a name mismatch for entry 1", resp.Errors)
+	}
+	if _, ok := handler.store.Get("default/ConfigMap/web"); ok {
+		t.Error("BulkCreateManifests() stored entries of a rejected request")
+	}
+}
+
+func TestImportManifests_StrictKeyValidation(t *testing.T) {
+	handler := newStrictKeyTestHandler(t)
+	web := bulkConfigMap("web")
+	manifests := map[string][]byte{
+		web.Key:                   []byte(web.Value),
+		"default/ConfigMap/other": []byte(bulkConfigMap("app").Value),
+	}
+	var archive bytes.Buffer
+	if err := writeTarGzArchive(&archive, []string{web.Key, "default/ConfigMap/other"}, manifests); err != nil {
+		t.Fatalf("writeTarGzArchive() error = %v", err)
+	}
+
+	w := postManifestArchive(t, handler, archive.Bytes())
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("ImportManifests() status code = %v, want %v: %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+	}
+	var resp ImportManifestsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ImportManifests() response is not valid JSON: %v", err)
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0].File != "default/ConfigMap/other.yaml" {
+		t.Errorf("ImportManifests() failed = %v, want default/ConfigMap/other.yaml", resp.Failed)
+	}
+	if _, ok := handler.store.Get(web.Key); ok {
+		t.Error("ImportManifests() stored manifests of a rejected archive")
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+const (
+	defaultManifestPageSize = 50
+	maxManifestPageSize     = 500
+)
+
+// pagedListParams are the query parameters that select a paged ListManifests response
+var pagedListParams = []string{"page", "page_size", "sort_by", "sort_order", "prefix"}
+
+func isPagedListRequest(r *http.Request) bool {
+	query := r.URL.Query()
+	for _, param := range pagedListParams {
+		if query.Has(param) {
+			return true
+		}
+	}
+	return false
+}
+
+// listManifestsPage writes one page of the manifests whose key starts with ?prefix=, such as
+// default/Deployment/, sorted by ?sort_by=key|modified in ?sort_order=asc|desc
+func (h *Handler) listManifestsPage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("kind") != "" || query.Get("namespace") != "" || query.Get("label") != "" {
+		WriteError(w, h.logger, fmt.Errorf("%w: kind, namespace and label filters cannot be combined with pagination; use prefix", apperrors.ErrInvalidParameter))
+		return
+	}
+
+	page, err := parsePositiveQueryInt(r, "page", 1)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	pageSize, err := parsePositiveQueryInt(r, "page_size", defaultManifestPageSize)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if pageSize > maxManifestPageSize {
+		WriteError(w, h.logger, fmt.Errorf("%w: page_size cannot exceed %d", apperrors.ErrInvalid, maxManifestPageSize))
+		return
+	}
+
+	result, err := h.storeFor(r).ListPaged(page, pageSize, query.Get("sort_by"), query.Get("sort_order"), query.Get("prefix"))
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	items := make(map[string]string, len(result.Items))
+	for key, value := range result.Items {
+		items[key] = string(value)
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, ManifestPageResponse{
+		Items:      items,
+		Keys:       result.Keys,
+		Total:      result.Total,
+		Page:       result.Page,
+		PageSize:   result.PageSize,
+		TotalPages: result.TotalPages,
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListManifests_Paged(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	entries := make(map[string][]byte, 150)
+	for i := 0; i < 150; i++ {
+		name := fmt.Sprintf("app-%03d", i)
+		entries["default/Deployment/"+name] = []byte(createTestManifest("Deployment", name, "default"))
+	}
+	if err := handler.store.CreateBatch(entries); err != nil {
+		t.Fatalf("failed to create test manifests: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/manifests?page=2&page_size=50&sort_by=key&sort_order=asc&prefix=default/Deployment/", nil)
+	w := httptest.NewRecorder()
+	handler.ListManifests(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListManifests() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ManifestPageResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ListManifests() response is not valid JSON: %v", err)
+	}
+	if resp.Total != 150 || resp.TotalPages != 3 || resp.Page != 2 || resp.PageSize != 50 {
+		t.Errorf("ListManifests() = total %d, %d pages, page %d of size %d; want 150, 3, 2 and 50", resp.Total, resp.TotalPages, resp.Page, resp.PageSize)
+	}
+	if len(resp.Items) != 50 || resp.Keys[0] != "default/Deployment/app-050" {
+		t.Errorf("ListManifests() returned %d items starting at %v, want 50 starting at app-050", len(resp.Items), resp.Keys[0])
+	}
+	if resp.Items["default/Deployment/app-050"] != string(entries["default/Deployment/app-050"]) {
+		t.Errorf("ListManifests() item = %q, want the manifest YAML", resp.Items["default/Deployment/app-050"])
+	}
+}
+
+func TestListManifests_PagedInvalidParameters(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	for _, query := range []string{"page=0", "page_size=1000", "sort_by=name", "sort_order=up", "page=1&kind=Deployment"} {
+		req := httptest.NewRequest("GET", "/api/manifests?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListManifests(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ListManifests(%s) status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// GenerateRBAC stores a ServiceAccount, Role and RoleBinding for the serviceAccountName of the
+// Deployment or StatefulSet {namespace}/{name}. Manifests that already exist are kept unless
+// ?overwrite=true; the Role rules come from the workload's rbac.conductor.io/rules annotation.
+func (h *Handler) GenerateRBAC(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	if !isValidKubernetesName(namespace) || !isValidKubernetesName(name) {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid namespace or workload name", apperrors.ErrInvalidParameter))
+		return
+	}
+	overwrite := r.URL.Query().Get("overwrite") == "true"
+
+	st := h.storeFor(r)
+	var workload []byte
+	for _, kind := range []string{"Deployment", "StatefulSet"} {
+		if content, ok := st.Get(fmt.Sprintf("%s/%s/%s", namespace, kind, name)); ok {
+			workload = content
+			break
+		}
+	}
+	if workload == nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: no Deployment or StatefulSet %s/%s", apperrors.ErrNotFound, namespace, name))
+		return
+	}
+
+	generated, err := manifest.GenerateRBAC(workload)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	resp := GenerateRBACResponse{Keys: []string{}}
+	entries := make(map[string][]byte, len(generated))
+	for key, content := range generated {
+		if _, exists := st.Get(key); exists && !overwrite {
+			resp.Skipped = append(resp.Skipped, key)
+			continue
+		}
+		entries[key] = content
+		resp.Keys = append(resp.Keys, key)
+	}
+	sort.Strings(resp.Keys)
+	sort.Strings(resp.Skipped)
+
+	if len(entries) == 0 {
+		WriteJSONResponse(w, h.logger, http.StatusOK, resp)
+		return
+	}
+	if err := st.PutBatch(entries); err != nil {
+		h.logger.Error(err, "failed to store generated RBAC manifests", "namespace", namespace, "name", name)
+		WriteError(w, h.logger, fmt.Errorf("creation failed: %w", err))
+		return
+	}
+
+	if !tenantSelected(r) {
+		for _, key := range resp.Keys {
+			h.queueReconcile(key)
+		}
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusCreated, resp)
+}
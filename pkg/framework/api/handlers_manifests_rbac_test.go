@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const rbacTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: apps
+  annotations:
+    rbac.conductor.io/rules: '[{"resources": ["configmaps"], "verbs": ["get"]}]'
+spec:
+  template:
+    spec:
+      serviceAccountName: api-reader
+`
+
+func generateRBAC(handler *Handler, path string) (*httptest.ResponseRecorder, GenerateRBACResponse) {
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+	var resp GenerateRBACResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestGenerateRBAC(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.store.Create("apps/Deployment/api", []byte(rbacTestDeployment)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	w, resp := generateRBAC(handler, "/api/manifests/generate-rbac/apps/api")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("GenerateRBAC() status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	want := []string{"apps/Role/api-reader", "apps/RoleBinding/api-reader", "apps/ServiceAccount/api-reader"}
+	if !reflect.DeepEqual(resp.Keys, want) {
+		t.Errorf("GenerateRBAC() keys = %v, want %v", resp.Keys, want)
+	}
+	for _, key := range want {
+		if _, ok := handler.store.Get(key); !ok {
+			t.Errorf("GenerateRBAC() did not store %s", key)
+		}
+	}
+
+	// Edited manifests are kept unless overwrite is requested
+	if err := handler.store.Update("apps/Role/api-reader", []byte("apiVersion: rbac.authorization.k8s.io/v1\nkind: Role\nmetadata:\n  name: api-reader\n  namespace: apps\nrules: []\n")); err != nil {
+		t.Fatalf("failed to update Role: %v", err)
+	}
+	w, resp = generateRBAC(handler, "/api/manifests/generate-rbac/apps/api")
+	if w.Code != http.StatusOK || len(resp.Keys) != 0 || !reflect.DeepEqual(resp.Skipped, want) {
+		t.Errorf("second GenerateRBAC() = %d %+v, want 200 with every key skipped", w.Code, resp)
+	}
+	w, resp = generateRBAC(handler, "/api/manifests/generate-rbac/apps/api?overwrite=true")
+	if w.Code != http.StatusCreated || !reflect.DeepEqual(resp.Keys, want) {
+		t.Errorf("GenerateRBAC() with overwrite = %d %+v, want 201 with every key stored", w.Code, resp)
+	}
+}
+
+func TestGenerateRBAC_Errors(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	noAccount := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: apps\nspec:\n  template:\n    spec: {}\n"
+	if err := handler.store.Create("apps/Deployment/web", []byte(noAccount)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{name: "missing workload", path: "/api/manifests/generate-rbac/apps/missing", want: http.StatusNotFound},
+		{name: "no service account", path: "/api/manifests/generate-rbac/apps/web", want: http.StatusBadRequest},
+		{name: "invalid namespace", path: "/api/manifests/generate-rbac/Apps_1/web", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w, _ := generateRBAC(handler, tt.path); w.Code != tt.want {
+				t.Errorf("GenerateRBAC() status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
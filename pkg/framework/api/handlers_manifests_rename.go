@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// renameSuffix ends the path of POST /api/manifests/{key}/rename
+const renameSuffix = "/rename"
+
+// RenameManifest moves the manifest stored under {key} to the new_key of a {"new_key"} body.
+// The events of the old key are kept, and an info event on the new key links the two; a
+// managed resource stays managed under its new key.
+func (h *Handler) RenameManifest(w http.ResponseWriter, r *http.Request) {
+	path := chi.URLParam(r, "*")
+	if !strings.HasSuffix(path, renameSuffix) {
+		WriteError(w, h.logger, fmt.Errorf("%w: %s", apperrors.ErrNotFound, r.URL.Path))
+		return
+	}
+	oldKey := strings.TrimSuffix(path, renameSuffix)
+
+	var req struct {
+		NewKey string `json:"new_key"`
+	}
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	if err := validateManifestKeyFormat(oldKey); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if err := validateManifestKeyFormat(req.NewKey); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if req.NewKey == oldKey {
+		WriteError(w, h.logger, fmt.Errorf("%w: new_key must differ from the current key", apperrors.ErrInvalid))
+		return
+	}
+
+	st := h.storeFor(r)
+	value, ok := st.Get(oldKey)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, oldKey))
+		return
+	}
+	if _, exists := st.Get(req.NewKey); exists {
+		WriteErrorResponse(w, h.logger, http.StatusConflict, "manifest_exists", fmt.Sprintf("Manifest %s already exists", req.NewKey), nil)
+		return
+	}
+	if err := h.checkManifestKey(req.NewKey, value); err != nil {
+		h.writeKeyMismatch(w, err)
+		return
+	}
+
+	if err := st.Rename(oldKey, req.NewKey); err != nil {
+		h.logger.Error(err, "failed to rename manifest", "key", oldKey, "newKey", req.NewKey)
+		WriteError(w, h.logger, fmt.Errorf("rename failed: %w", err))
+		return
+	}
+
+	events.StoreEventSafeContext(r.Context(), h.eventStore, h.logger, events.ManifestRenamed(oldKey, req.NewKey))
+
+	// Manifests of a tenant selected with X-Tenant-ID are not reconciled by this instance
+	if !tenantSelected(r) {
+		if h.reconciler != nil {
+			if err := h.reconciler.RenameKey(oldKey, req.NewKey); err != nil {
+				h.logger.Error(err, "failed to rename managed key", "key", oldKey, "newKey", req.NewKey)
+			}
+		}
+		h.queueReconcile(req.NewKey)
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, RenameManifestResponse{OldKey: oldKey, NewKey: req.NewKey})
+}
+
+// validateManifestKeyFormat checks that key has the form namespace/Kind/name
+func validateManifestKeyFormat(key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("%w: key %q must have the form namespace/Kind/name", apperrors.ErrInvalid, key)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// renameRecordingReconciler records the RenameKey calls made on the wrapped reconciler
+type renameRecordingReconciler struct {
+	reconciler.Reconciler
+	renamed [][2]string
+}
+
+func (r *renameRecordingReconciler) RenameKey(oldKey, newKey string) error {
+	r.renamed = append(r.renamed, [2]string{oldKey, newKey})
+	return r.Reconciler.RenameKey(oldKey, newKey)
+}
+
+func renameManifest(t *testing.T, handler *Handler, key, newKey string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"new_key": newKey})
+	req := httptest.NewRequest("POST", "/api/manifests/"+key+"/rename", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestRenameManifest(t *testing.T) {
+	rec := &renameRecordingReconciler{Reconciler: setupTestReconciler(t, true)}
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	value := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n")
+	if err := handler.store.Create("default/ConfigMap/settings", value); err != nil {
+		t.Fatalf("failed to create manifest: %v", err)
+	}
+
+	w := renameManifest(t, handler, "default/ConfigMap/settings", "staging/ConfigMap/settings")
+	if w.Code != http.StatusOK {
+		t.Fatalf("RenameManifest() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp RenameManifestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.OldKey != "default/ConfigMap/settings" || resp.NewKey != "staging/ConfigMap/settings" {
+		t.Errorf("response = %+v", resp)
+	}
+
+	if got, ok := handler.store.Get("staging/ConfigMap/settings"); !ok || !bytes.Equal(got, value) {
+		t.Errorf("new key = %q, %v, want %q", got, ok, value)
+	}
+	if _, ok := handler.store.Get("default/ConfigMap/settings"); ok {
+		t.Error("old key still exists after rename")
+	}
+
+	if len(rec.renamed) != 1 || rec.renamed[0] != [2]string{"default/ConfigMap/settings", "staging/ConfigMap/settings"} {
+		t.Errorf("RenameKey calls = %v, want one from the old to the new key", rec.renamed)
+	}
+
+	renameEvents, err := handler.eventStore.GetEventsByResource("staging/ConfigMap/settings", events.EventFilters{})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(renameEvents) != 1 {
+		t.Fatalf("events for new key = %d, want 1", len(renameEvents))
+	}
+	event := renameEvents[0]
+	if event.Type != events.EventTypeInfo || event.Details["old_key"] != "default/ConfigMap/settings" || event.Details["operation"] != events.OperationRename {
+		t.Errorf("rename event = %+v", event)
+	}
+
+	select {
+	case key := <-handler.reconcileCh:
+		if key != "staging/ConfigMap/settings" {
+			t.Errorf("queued key = %s, want the new key", key)
+		}
+	default:
+		t.Error("rename did not queue the new key for reconciliation")
+	}
+}
+
+func TestRenameManifest_Errors(t *testing.T) {
+	handler, err := newTestHandler(t, WithTestReconciler(setupTestReconciler(t, true)))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	for _, key := range []string{"default/ConfigMap/a", "default/ConfigMap/b"} {
+		if err := handler.store.Create(key, []byte("apiVersion: v1\nkind: ConfigMap\n")); err != nil {
+			t.Fatalf("failed to create manifest: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		newKey string
+		want   int
+	}{
+		{"missing manifest", "default/ConfigMap/missing", "default/ConfigMap/c", http.StatusNotFound},
+		{"existing new key", "default/ConfigMap/a", "default/ConfigMap/b", http.StatusConflict},
+		{"malformed new key", "default/ConfigMap/a", "ConfigMap/a", http.StatusBadRequest},
+		{"empty new key", "default/ConfigMap/a", "", http.StatusBadRequest},
+		{"same key", "default/ConfigMap/a", "default/ConfigMap/a", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := renameManifest(t, handler, tt.key, tt.newKey); w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	if _, ok := handler.store.Get("default/ConfigMap/a"); !ok {
+		t.Error("failed renames removed the manifest")
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/go-chi/chi/v5"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// SetTemplateFuncs sets the custom template functions GetRenderedManifest renders with, the
+// same functions the manifests were loaded with
+func (h *Handler) SetTemplateFuncs(funcs template.FuncMap) {
+	h.templateFuncs = funcs
+}
+
+// SetClusterVersion sets the Kubernetes server version GetRenderedManifest exposes to templates
+// as .Cluster.Version, e.g. "v1.28.3"
+func (h *Handler) SetClusterVersion(version string) {
+	h.clusterVersion = version
+}
+
+// GetRenderedManifest renders a stored manifest as a template with the current deployment
+// parameters of ?instance= and returns the resulting YAML. ?overlay= merges a parameter overlay
+// first. With ?dry_run=true, secretValue and configValue do not read the cluster and render "".
+func (h *Handler) GetRenderedManifest(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	key := fmt.Sprintf("%s/%s/%s", chi.URLParam(r, "namespace"), chi.URLParam(r, "kind"), name)
+	if err := ValidateKey(key); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	st := h.storeFor(r)
+	source, ok := st.Get(key)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: manifest %s", apperrors.ErrNotFound, key))
+		return
+	}
+
+	spec := make(map[string]interface{})
+	if h.parameterClient != nil {
+		namespace, instanceName := h.getNamespaceAndInstance(r)
+		current, err := h.getSpecWithFallback(r.Context(), instanceName, namespace, getQueryNamespace(r) == "")
+		if err != nil {
+			h.logger.Error(err, "failed to get DeploymentParameters spec", "instance", instanceName)
+			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_parameters_failed", err.Error(), nil)
+			return
+		}
+		if current != nil {
+			spec = current
+		}
+	}
+	spec, err := specWithOverlay(spec, getOverlayName(r))
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	// servicePort and .ServiceNames resolve against the manifests of the same store
+	opts := manifest.RenderOptions{
+		Files:          manifest.NewFileSystem(h.manifestFS, h.manifestRoot),
+		CustomFuncs:    h.templateFuncs,
+		Manifests:      st,
+		ClusterVersion: h.clusterVersion,
+		Logger:         h.logger,
+	}
+	if !isDryRun(r) && h.reconciler != nil {
+		opts.KubeClient = h.reconciler.GetClientset()
+	}
+
+	rendered, err := manifest.RenderTemplateWithOptions(r.Context(), source, renderServiceName(name), spec, opts)
+	if err != nil {
+		WriteErrorResponse(w, h.logger, http.StatusUnprocessableEntity, "template_render_failed", err.Error(), nil)
+		return
+	}
+	WriteYAMLResponse(w, h.logger, rendered)
+}
+
+// renderServiceName returns the service whose parameters apply to the resource name, dropping
+// the suffixes getServiceNames drops
+func renderServiceName(name string) string {
+	for _, suffix := range []string{"-service", "-svc", "-deployment", "-statefulset", "-deploy"} {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
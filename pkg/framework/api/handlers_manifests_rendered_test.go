@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
+const renderedTestManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: {{ .Spec.global.namespace }}
+data:
+  replicas: "{{ param "replicas" }}"
+`
+
+func newRenderedTestHandler(t *testing.T, manifest string) *Handler {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "apps", "replicas": float64(2)},
+		"services": map[string]interface{}{
+			"app-config": map[string]interface{}{"replicas": float64(3)},
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(context.Background(), crd.DefaultName, "default", spec); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+	if err := handler.store.Create("default/ConfigMap/app-config", []byte(manifest)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	return handler
+}
+
+func TestGetRenderedManifest(t *testing.T) {
+	handler := newRenderedTestHandler(t, renderedTestManifest)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/manifests/default/ConfigMap/app-config/rendered?instance=default&dry_run=true", nil)
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetRenderedManifest() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/yaml" {
+		t.Errorf("Content-Type = %q, want application/yaml", got)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "namespace: apps") {
+		t.Errorf("GetRenderedManifest() did not substitute the global namespace:\n%s", body)
+	}
+	if !strings.Contains(body, `replicas: "3"`) {
+		t.Errorf("GetRenderedManifest() did not apply the service parameters of app-config:\n%s", body)
+	}
+}
+
+func TestGetRenderedManifest_ServicePort(t *testing.T) {
+	handler := newRenderedTestHandler(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\ndata:\n  port: \"{{ servicePort \"web\" \"http\" }}\"\n")
+	service := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: default\nspec:\n  ports:\n  - name: http\n    port: 8080\n"
+	if err := handler.store.Create("default/Service/web", []byte(service)); err != nil {
+		t.Fatalf("failed to create test Service: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/manifests/default/ConfigMap/app-config/rendered?dry_run=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetRenderedManifest() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, `port: "8080"`) {
+		t.Errorf("GetRenderedManifest() did not resolve servicePort against the stored Service:\n%s", body)
+	}
+}
+
+func TestGetRenderedManifest_ServiceNames(t *testing.T) {
+	handler := newRenderedTestHandler(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\ndata:\n  services: \"{{ join \",\" .ServiceNames }}\"\n")
+	for _, name := range []string{"web", "api"} {
+		service := "apiVersion: v1\nkind: Service\nmetadata:\n  name: " + name + "\n  namespace: default\n"
+		if err := handler.store.Create("default/Service/"+name, []byte(service)); err != nil {
+			t.Fatalf("failed to create test Service: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/manifests/default/ConfigMap/app-config/rendered?dry_run=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetRenderedManifest() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, `services: "api,web"`) {
+		t.Errorf("GetRenderedManifest() did not list the stored Services in .ServiceNames:\n%s", body)
+	}
+}
+
+func TestGetRenderedManifest_ClusterVersion(t *testing.T) {
+	handler := newRenderedTestHandler(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\ndata:\n  version: \"{{ .Cluster.Version }}\"\n  modern: \"{{ .Cluster.IsVersionGTE \"1.25\" }}\"\n")
+	handler.SetClusterVersion("v1.28.3")
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/manifests/default/ConfigMap/app-config/rendered?dry_run=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetRenderedManifest() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `version: "v1.28.3"`) || !strings.Contains(body, `modern: "true"`) {
+		t.Errorf("GetRenderedManifest() did not expose the cluster version:\n%s", body)
+	}
+}
+
+func TestGetRenderedManifest_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		path     string
+		want     int
+	}{
+		{name: "template error", manifest: "metadata:\n  name: {{ .Spec.global.namespace | missingFunc }}\n", path: "/api/manifests/default/ConfigMap/app-config/rendered", want: http.StatusUnprocessableEntity},
+		{name: "missing manifest", manifest: renderedTestManifest, path: "/api/manifests/default/ConfigMap/other/rendered", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newRenderedTestHandler(t, tt.manifest)
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("GetRenderedManifest() status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRenderServiceName(t *testing.T) {
+	for name, want := range map[string]string{"api-service": "api", "worker-deployment": "worker", "app-config": "app-config", "-svc": "-svc"} {
+		if got := renderServiceName(name); got != want {
+			t.Errorf("renderServiceName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
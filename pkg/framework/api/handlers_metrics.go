@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// SetMetrics serves the metrics collected by gatherer on GET /metrics.
+// It must be called before SetupRoutes.
+func (h *Handler) SetMetrics(gatherer prometheus.Gatherer) {
+	h.metricsGatherer = gatherer
+}
+
+// Metrics serves the registered metrics in the Prometheus text format
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if h.metricsGatherer == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "metrics_unavailable", "Metrics not enabled", nil)
+		return
+	}
+	promhttp.HandlerFor(h.metricsGatherer, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
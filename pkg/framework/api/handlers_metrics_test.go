@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	registry := prometheus.NewRegistry()
+	metrics.New(registry).ObserveApply(nil)
+	handler.SetMetrics(registry)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Metrics() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), `conductor_apply_total{result="success"} 1`) {
+		t.Errorf("Metrics() body = %s, want the apply counter", w.Body.String())
+	}
+}
+
+func TestMetrics_NotEnabled(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.Metrics(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Metrics() status code = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/garunski/conductor-framework/pkg/framework/crd"
@@ -21,33 +22,35 @@ func (h *Handler) getDetectedNamespace() string {
 	return detectNamespaceFromManifests(manifests)
 }
 
-// GetParameters retrieves the current deployment parameters
+// GetParameters retrieves the current deployment parameters. With ?overlay=<name> it returns
+// the spec with spec.overlays.<name> merged on top.
 func (h *Handler) GetParameters(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	
 	// Get namespace and instance name
 	detectedNamespace, instanceName := h.getNamespaceAndInstance(r)
+	fallback := getQueryNamespace(r) == ""
 
-	spec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace)
-	if err != nil {
+	spec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace, fallback)
+	if err != nil && fallback {
 		// Try fallback to default instance in default namespace
 		spec, err = h.parameterClient.GetSpec(ctx, crd.DefaultName, "default")
-		if err != nil {
-			h.logger.Error(err, "failed to get DeploymentParameters spec")
-			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_parameters_failed", err.Error(), nil)
-			return
-		}
+	}
+	if err != nil {
+		h.logger.Error(err, "failed to get DeploymentParameters spec")
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_parameters_failed", err.Error(), nil)
+		return
 	}
 
 	if spec == nil || len(spec) == 0 {
 		// Return default parameters if CRD doesn't exist
-		spec = map[string]interface{}{
-			"global": map[string]interface{}{
-				"namespace":  "default",
-				"namePrefix": "",
-				"replicas":   int32(1),
-			},
-		}
+		spec = h.defaultParameters()
+	}
+
+	spec, err = specWithOverlay(spec, getOverlayName(r))
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
 	}
 
 	WriteJSONResponse(w, h.logger, http.StatusOK, spec)
@@ -67,6 +70,12 @@ func (h *Handler) UpdateParameters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if fieldErrs := h.validateParametersSpec(ctx, spec); len(fieldErrs) > 0 {
+		WriteErrorResponse(w, h.logger, http.StatusUnprocessableEntity, "validation_failed",
+			fmt.Sprintf("Parameters do not match the CRD schema: %d invalid field(s)", len(fieldErrs)), fieldErrorDetails(fieldErrs))
+		return
+	}
+
 	// Get existing parameters to check if it exists
 	params, err := h.parameterClient.Get(ctx, instanceName, detectedNamespace)
 	if err != nil {
@@ -90,3 +99,39 @@ func (h *Handler) UpdateParameters(w http.ResponseWriter, r *http.Request) {
 
 	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{"message": "Parameters updated successfully"})
 }
+
+// fieldErrorDetails maps the field of each schema validation error to its message
+func fieldErrorDetails(fieldErrs []crd.FieldError) map[string]interface{} {
+	details := make(map[string]interface{}, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		field := fe.Field
+		if field == "" {
+			field = "spec"
+		}
+		details[field] = fe.Message
+	}
+	return details
+}
+
+// MergeParameters deep-merges the request body into the existing deployment parameters.
+// Nested maps are merged key by key; scalars and arrays in the body replace existing values.
+func (h *Handler) MergeParameters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get namespace and instance name
+	namespace, instanceName := h.getNamespaceAndInstance(r)
+
+	var overlay map[string]interface{}
+	if err := h.parseJSONRequest(r, &overlay); err != nil {
+		WriteErrorResponse(w, h.logger, http.StatusBadRequest, "invalid_request", err.Error(), nil)
+		return
+	}
+
+	spec, err := h.parameterClient.MergeSpec(ctx, instanceName, namespace, overlay)
+	if err != nil {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "merge_parameters_failed", err.Error(), nil)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, spec)
+}
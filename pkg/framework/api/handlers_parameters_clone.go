@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CloneParameterInstance creates a parameter instance named new_name with a deep copy of the
+// spec of the instance {name}, with patch deep-merged into it. It returns 409 when new_name exists.
+func (h *Handler) CloneParameterInstance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sourceName := chi.URLParam(r, "name")
+	namespace, _ := h.getNamespaceAndInstance(r)
+
+	var req CloneParameterInstanceRequest
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteErrorResponse(w, h.logger, http.StatusBadRequest, "invalid_request", err.Error(), nil)
+		return
+	}
+	if !isValidKubernetesName(req.NewName) {
+		WriteErrorResponse(w, h.logger, http.StatusBadRequest, "invalid_name",
+			fmt.Sprintf("new_name %q must be lowercase alphanumeric characters or '-' and start and end with an alphanumeric character", req.NewName), nil)
+		return
+	}
+
+	source, err := h.parameterClient.Get(ctx, sourceName, namespace)
+	if err != nil {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_parameters_failed", err.Error(), nil)
+		return
+	}
+	if source == nil {
+		WriteErrorResponse(w, h.logger, http.StatusNotFound, "instance_not_found", fmt.Sprintf("Parameter instance %s not found in namespace %s", sourceName, namespace), nil)
+		return
+	}
+
+	existing, err := h.parameterClient.Get(ctx, req.NewName, namespace)
+	if err != nil {
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_parameters_failed", err.Error(), nil)
+		return
+	}
+	if existing != nil {
+		WriteErrorResponse(w, h.logger, http.StatusConflict, "instance_exists", fmt.Sprintf("Parameter instance %s already exists in namespace %s", req.NewName, namespace), nil)
+		return
+	}
+
+	spec := deepCopySpecMap(source.Spec)
+	if req.Patch != nil {
+		spec = applyOverlay(spec, req.Patch)
+	}
+
+	if fieldErrs := h.validateParametersSpec(ctx, spec); len(fieldErrs) > 0 {
+		WriteErrorResponse(w, h.logger, http.StatusUnprocessableEntity, "validation_failed",
+			fmt.Sprintf("Cloned parameters do not match the CRD schema: %d invalid field(s)", len(fieldErrs)), fieldErrorDetails(fieldErrs))
+		return
+	}
+
+	if err := h.parameterClient.CreateWithSpec(ctx, req.NewName, namespace, spec); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			WriteErrorResponse(w, h.logger, http.StatusConflict, "instance_exists", fmt.Sprintf("Parameter instance %s already exists in namespace %s", req.NewName, namespace), nil)
+			return
+		}
+		h.logger.Error(err, "failed to create cloned parameter instance", "source", sourceName, "name", req.NewName)
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "create_instance_failed", err.Error(), nil)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusCreated, map[string]string{
+		"name":      req.NewName,
+		"namespace": namespace,
+		"message":   fmt.Sprintf("Cloned parameter instance %s to %s", sourceName, req.NewName),
+	})
+}
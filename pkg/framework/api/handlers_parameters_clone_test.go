@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
+func newCloneTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "apps", "replicas": float64(1)},
+		"services": map[string]interface{}{
+			"api": map[string]interface{}{"imageTag": "v1"},
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(context.Background(), crd.DefaultName, "default", spec); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+	return handler
+}
+
+func cloneInstance(handler *Handler, source, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/parameters/instances/"+source+"/clone", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestCloneParameterInstance(t *testing.T) {
+	handler := newCloneTestHandler(t)
+	ctx := context.Background()
+
+	if w := cloneInstance(handler, crd.DefaultName, `{"new_name": "config-staging"}`); w.Code != http.StatusCreated {
+		t.Fatalf("CloneParameterInstance() status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	source, _ := handler.parameterClient.GetSpec(ctx, crd.DefaultName, "default")
+	clone, _ := handler.parameterClient.GetSpec(ctx, "config-staging", "default")
+	if !reflect.DeepEqual(clone, source) {
+		t.Errorf("cloned spec = %v, want %v", clone, source)
+	}
+
+	w := cloneInstance(handler, crd.DefaultName, `{"new_name": "config-prod", "patch": {"global": {"replicas": 3}}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CloneParameterInstance() with a patch status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	prod, _ := handler.parameterClient.GetSpec(ctx, "config-prod", "default")
+	global, _ := prod["global"].(map[string]interface{})
+	if global["namespace"] != "apps" {
+		t.Errorf("patched clone global.namespace = %v, want the source value apps", global["namespace"])
+	}
+	if replicas, ok := global["replicas"].(float64); !ok || replicas != 3 {
+		t.Errorf("patched clone global.replicas = %v, want 3", global["replicas"])
+	}
+	if source, _ := handler.parameterClient.GetSpec(ctx, crd.DefaultName, "default"); source["global"].(map[string]interface{})["replicas"] != float64(1) {
+		t.Error("patching the clone changed the source instance")
+	}
+}
+
+func TestCloneParameterInstance_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		body   string
+		want   int
+	}{
+		{name: "invalid name", source: crd.DefaultName, body: `{"new_name": "Config_Prod"}`, want: http.StatusBadRequest},
+		{name: "missing name", source: crd.DefaultName, body: `{}`, want: http.StatusBadRequest},
+		{name: "existing name", source: crd.DefaultName, body: `{"new_name": "default"}`, want: http.StatusConflict},
+		{name: "missing source", source: "config-missing", body: `{"new_name": "config-prod"}`, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newCloneTestHandler(t)
+			if w := cloneInstance(handler, tt.source, tt.body); w.Code != tt.want {
+				t.Errorf("CloneParameterInstance() status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package api
+
+// SetDefaultParameterSpec sets the spec GetParameters returns while no DeploymentParameters
+// instance exists, such as the parsed values.yaml of the manifest filesystem. An empty spec
+// restores the built-in defaults.
+func (h *Handler) SetDefaultParameterSpec(spec map[string]interface{}) {
+	h.defaultParameterSpec = deepCopyMapInterface(spec)
+}
+
+// defaultParameters returns a copy of the default parameter spec
+func (h *Handler) defaultParameters() map[string]interface{} {
+	if len(h.defaultParameterSpec) > 0 {
+		return deepCopyMapInterface(h.defaultParameterSpec)
+	}
+	return map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace":  "default",
+			"namePrefix": "",
+			"replicas":   int32(1),
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
+func getParametersNamespace(t *testing.T, handler *Handler) interface{} {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", "/api/parameters", nil)
+	w := httptest.NewRecorder()
+	handler.GetParameters(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetParameters() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	global, ok := result["global"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("response global = %#v, want a map", result["global"])
+	}
+	return global["namespace"]
+}
+
+func TestGetParameters_DefaultParameterSpec(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	handler.SetDefaultParameterSpec(map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace": "values-ns",
+		},
+	})
+
+	if ns := getParametersNamespace(t, handler); ns != "values-ns" {
+		t.Errorf("namespace before CRD = %v, want values-ns", ns)
+	}
+
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace": "crd-ns",
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(context.Background(), crd.DefaultName, "default", spec); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	if ns := getParametersNamespace(t, handler); ns != "crd-ns" {
+		t.Errorf("namespace after CRD = %v, want crd-ns", ns)
+	}
+}
+
+func TestGetParameters_EmptyDefaultParameterSpec(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	handler.SetDefaultParameterSpec(nil)
+
+	if ns := getParametersNamespace(t, handler); ns != "default" {
+		t.Errorf("namespace = %v, want default", ns)
+	}
+}
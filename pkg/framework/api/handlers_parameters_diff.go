@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
+// DiffParameterInstances compares the specs of the parameter instances ?from= and ?to= in
+// the request namespace and returns the added, removed and changed values
+func (h *Handler) DiffParameterInstances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace, _ := h.getNamespaceAndInstance(r)
+
+	names := map[string]string{
+		"from": r.URL.Query().Get("from"),
+		"to":   r.URL.Query().Get("to"),
+	}
+	specs := map[string]map[string]interface{}{}
+	for _, param := range []string{"from", "to"} {
+		name := names[param]
+		if name == "" {
+			WriteErrorResponse(w, h.logger, http.StatusBadRequest, "missing_parameter", fmt.Sprintf("%s query parameter is required", param), nil)
+			return
+		}
+		params, err := h.parameterClient.Get(ctx, name, namespace)
+		if err != nil {
+			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_parameters_failed", err.Error(), nil)
+			return
+		}
+		if params == nil {
+			WriteErrorResponse(w, h.logger, http.StatusNotFound, "instance_not_found", fmt.Sprintf("Parameter instance %s not found in namespace %s", name, namespace), nil)
+			return
+		}
+		specs[param] = params.Spec
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, crd.DiffSpecs(specs["from"], specs["to"]))
+}
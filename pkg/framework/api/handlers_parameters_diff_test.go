@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
+func TestDiffParameterInstances(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	ctx := context.Background()
+	dev := map[string]interface{}{
+		"global":   map[string]interface{}{"namespace": "dev", "debug": true},
+		"services": map[string]interface{}{"api": map[string]interface{}{"imageTag": "v1"}},
+	}
+	prod := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "prod"},
+		"services": map[string]interface{}{
+			"api":   map[string]interface{}{"imageTag": "v1"},
+			"redis": map[string]interface{}{"imageTag": "7"},
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(ctx, "config-dev", "default", dev); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+	if err := handler.parameterClient.CreateWithSpec(ctx, "config-prod", "default", prod); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/parameters/diff?from=config-dev&to=config-prod", nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("DiffParameterInstances() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var diff crd.SpecDiff
+	if err := json.Unmarshal(w.Body.Bytes(), &diff); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := diff.Added["services.redis"]; !ok || len(diff.Added) != 1 {
+		t.Errorf("DiffParameterInstances() added = %v, want services.redis", diff.Added)
+	}
+	if diff.Removed["global.debug"] != true || len(diff.Removed) != 1 {
+		t.Errorf("DiffParameterInstances() removed = %v, want global.debug", diff.Removed)
+	}
+	if change := diff.Changed["global.namespace"]; change.From != "dev" || change.To != "prod" || len(diff.Changed) != 1 {
+		t.Errorf("DiffParameterInstances() changed = %v, want global.namespace dev -> prod", diff.Changed)
+	}
+}
+
+func TestDiffParameterInstances_Errors(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.parameterClient.CreateWithSpec(context.Background(), "config-dev", "default", map[string]interface{}{}); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "missing from", query: "?to=config-dev", want: http.StatusBadRequest},
+		{name: "missing to", query: "?from=config-dev", want: http.StatusBadRequest},
+		{name: "unknown instance", query: "?from=config-dev&to=config-missing", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/parameters/diff"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("DiffParameterInstances() status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
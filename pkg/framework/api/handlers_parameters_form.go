@@ -0,0 +1,139 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// FormGroupExtension names the form group of a schema property; nested properties inherit the
+// group of their closest ancestor that sets it
+const FormGroupExtension = "x-form-group"
+
+// defaultFormGroup holds controls that neither they nor a top-level ancestor place in a group
+const defaultFormGroup = "general"
+
+// GetParametersFormConfig returns the parameters schema as form control descriptors for the web UI
+func (h *Handler) GetParametersFormConfig(w http.ResponseWriter, r *http.Request) {
+	specSchema, _ := h.parametersSpecSchema(r.Context())
+	WriteJSONResponse(w, h.logger, http.StatusOK, SchemaToFormConfig(specSchema))
+}
+
+// SchemaToFormConfig turns every scalar leaf property of an OpenAPI object schema into a form
+// control. Controls are grouped by x-form-group, falling back to the top-level property they sit
+// under. Arrays and objects without declared properties have no control.
+func SchemaToFormConfig(schema map[string]interface{}) FormConfig {
+	config := FormConfig{Groups: []FormGroup{}}
+	groupIndex := make(map[string]int)
+	addControl := func(group string, control FormControl) {
+		i, ok := groupIndex[group]
+		if !ok {
+			i = len(config.Groups)
+			groupIndex[group] = i
+			config.Groups = append(config.Groups, FormGroup{Name: group})
+		}
+		config.Groups[i].Controls = append(config.Groups[i].Controls, control)
+	}
+	collectFormControls(schema, nil, "", addControl)
+	return config
+}
+
+func collectFormControls(schema map[string]interface{}, path []string, group string, add func(string, FormControl)) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		propPath := append(append([]string{}, path...), name)
+
+		propGroup := group
+		if g, ok := prop[FormGroupExtension].(string); ok && g != "" {
+			propGroup = g
+		} else if propGroup == "" && len(path) == 0 {
+			if _, nested := prop["properties"].(map[string]interface{}); nested {
+				propGroup = name
+			}
+		}
+
+		if _, nested := prop["properties"].(map[string]interface{}); nested {
+			collectFormControls(prop, propPath, propGroup, add)
+			continue
+		}
+
+		control, ok := formControl(prop)
+		if !ok {
+			continue
+		}
+		control.Path = strings.Join(propPath, ".")
+		control.Required = required[name]
+		if control.Label == "" {
+			control.Label = name
+		}
+		if propGroup == "" {
+			propGroup = defaultFormGroup
+		}
+		add(propGroup, control)
+	}
+}
+
+// formControl describes the control of a leaf property; ok is false for types no control edits
+func formControl(prop map[string]interface{}) (FormControl, bool) {
+	control := FormControl{Default: prop["default"]}
+	control.Label, _ = prop["description"].(string)
+
+	if enum, ok := prop["enum"].([]interface{}); ok && len(enum) > 0 {
+		control.Type = "select"
+		control.Enum = enum
+		return control, true
+	}
+
+	switch prop["type"] {
+	case "integer", "number":
+		control.Type = "number"
+		control.Min = schemaNumber(prop["minimum"])
+		control.Max = schemaNumber(prop["maximum"])
+	case "boolean":
+		control.Type = "boolean"
+	case "string":
+		control.Type = "text"
+	default:
+		if intOrString, _ := prop["x-kubernetes-int-or-string"].(bool); intOrString {
+			control.Type = "text"
+			return control, true
+		}
+		return FormControl{}, false
+	}
+	return control, true
+}
+
+// schemaNumber converts a numeric schema keyword, decoded from YAML or JSON, to a float
+func schemaNumber(v interface{}) *float64 {
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		return nil
+	}
+	return &f
+}
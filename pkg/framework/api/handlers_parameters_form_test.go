@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func formControlsByPath(config FormConfig) map[string]FormControl {
+	controls := make(map[string]FormControl)
+	for _, group := range config.Groups {
+		for _, control := range group.Controls {
+			controls[control.Path] = control
+		}
+	}
+	return controls
+}
+
+func TestSchemaToFormConfig_ControlTypes(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"namespace"},
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"default":     "default",
+				"description": "Namespace to deploy into",
+			},
+			"replicas": map[string]interface{}{
+				"type":    "integer",
+				"default": 1,
+				"minimum": 0,
+				"maximum": 10,
+			},
+			"debug": map[string]interface{}{
+				"type":    "boolean",
+				"default": false,
+			},
+			"pullPolicy": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"Always", "IfNotPresent", "Never"},
+			},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	controls := formControlsByPath(SchemaToFormConfig(schema))
+
+	namespace := controls["namespace"]
+	if namespace.Type != "text" || namespace.Label != "Namespace to deploy into" || namespace.Default != "default" || !namespace.Required {
+		t.Errorf("namespace control = %+v, want a required text control labelled by its description", namespace)
+	}
+
+	replicas := controls["replicas"]
+	if replicas.Type != "number" || replicas.Label != "replicas" || replicas.Required {
+		t.Errorf("replicas control = %+v, want an optional number control labelled by its name", replicas)
+	}
+	if replicas.Min == nil || *replicas.Min != 0 || replicas.Max == nil || *replicas.Max != 10 {
+		t.Errorf("replicas min/max = %v/%v, want 0/10", replicas.Min, replicas.Max)
+	}
+
+	if controls["debug"].Type != "boolean" {
+		t.Errorf("debug control type = %q, want boolean", controls["debug"].Type)
+	}
+
+	pullPolicy := controls["pullPolicy"]
+	if pullPolicy.Type != "select" || !reflect.DeepEqual(pullPolicy.Enum, []interface{}{"Always", "IfNotPresent", "Never"}) {
+		t.Errorf("pullPolicy control = %+v, want a select of the enum values", pullPolicy)
+	}
+
+	if _, ok := controls["tags"]; ok {
+		t.Error("SchemaToFormConfig() generated a control for an array")
+	}
+}
+
+func TestSchemaToFormConfig_Groups(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"global": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace":     map[string]interface{}{"type": "string"},
+					"imageRegistry": map[string]interface{}{"type": "string", FormGroupExtension: "images"},
+				},
+			},
+			"images": map[string]interface{}{
+				"type":             "object",
+				FormGroupExtension: "images",
+				"properties": map[string]interface{}{
+					"tag": map[string]interface{}{"type": "string"},
+				},
+			},
+			"enabled": map[string]interface{}{"type": "boolean"},
+		},
+	}
+
+	config := SchemaToFormConfig(schema)
+
+	groups := make(map[string][]string)
+	for _, group := range config.Groups {
+		for _, control := range group.Controls {
+			groups[group.Name] = append(groups[group.Name], control.Path)
+		}
+	}
+	want := map[string][]string{
+		defaultFormGroup: {"enabled"},
+		"global":         {"global.namespace"},
+		"images":         {"global.imageRegistry", "images.tag"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("SchemaToFormConfig() groups = %v, want %v", groups, want)
+	}
+}
+
+func TestGetParametersFormConfig(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/parameters/form-config", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetParametersFormConfig() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var config FormConfig
+	if err := json.Unmarshal(w.Body.Bytes(), &config); err != nil {
+		t.Fatalf("GetParametersFormConfig() response is not valid JSON: %v", err)
+	}
+	if replicas := formControlsByPath(config)["global.replicas"]; replicas.Type != "number" {
+		t.Errorf("global.replicas control = %+v, want a number control from the sample schema", replicas)
+	}
+}
@@ -18,8 +18,8 @@ func (h *Handler) ListParameterInstances(w http.ResponseWriter, r *http.Request)
 	// List all instances
 	instances, err := h.parameterClient.List(ctx, detectedNamespace)
 	if err != nil {
-		// If not found in detected namespace, try default namespace
-		if detectedNamespace != "default" {
+		// If not found in detected namespace, try default namespace unless one was requested
+		if detectedNamespace != "default" && getQueryNamespace(r) == "" {
 			instances, err = h.parameterClient.List(ctx, "default")
 		}
 		if err != nil {
@@ -70,8 +70,8 @@ func (h *Handler) CreateParameterInstance(w http.ResponseWriter, r *http.Request
 	// List existing instances to find next available name
 	existingInstances, err := h.parameterClient.List(ctx, detectedNamespace)
 	if err != nil {
-		// If not found in detected namespace, try default namespace
-		if detectedNamespace != "default" {
+		// If not found in detected namespace, try default namespace unless one was requested
+		if detectedNamespace != "default" && getQueryNamespace(r) == "" {
 			existingInstances, err = h.parameterClient.List(ctx, "default")
 			if err == nil {
 				detectedNamespace = "default"
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// getQueryNamespace returns the namespace query parameter, or "" when the request does not set one
+func getQueryNamespace(r *http.Request) string {
+	return r.URL.Query().Get("namespace")
+}
+
+// requireQueryNamespace rejects parameter requests whose namespace query parameter is not a
+// valid Kubernetes name or names a namespace that does not exist in the cluster
+func (h *Handler) requireQueryNamespace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace := getQueryNamespace(r)
+		if namespace == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !isValidKubernetesName(namespace) {
+			WriteErrorResponse(w, h.logger, http.StatusBadRequest, "invalid_namespace", "Namespace does not follow Kubernetes naming rules", nil)
+			return
+		}
+
+		exists, err := h.parameterClient.NamespaceExists(r.Context(), namespace)
+		if err != nil {
+			h.logger.Error(err, "failed to check namespace", "namespace", namespace)
+			WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "namespace_lookup_failed", err.Error(), nil)
+			return
+		}
+		if !exists {
+			WriteErrorResponse(w, h.logger, http.StatusNotFound, "namespace_not_found", fmt.Sprintf("Namespace %s not found", namespace), nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// getOverlayName returns the overlay selected with ?overlay=, or "" for the base spec
+func getOverlayName(r *http.Request) string {
+	return r.URL.Query().Get("overlay")
+}
+
+// specWithOverlay returns a copy of spec with spec.overlays[name] merged on top and the
+// overlays field removed. An empty name returns spec unchanged.
+func specWithOverlay(spec map[string]interface{}, name string) (map[string]interface{}, error) {
+	if name == "" {
+		return spec, nil
+	}
+	overlay, ok := crd.DeploymentParametersSpec(spec).Overlays()[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: overlay %q is not defined in spec.%s", apperrors.ErrNotFound, name, crd.OverlaysKey)
+	}
+
+	merged := applyOverlay(spec, overlay)
+	delete(merged, crd.OverlaysKey)
+	return merged, nil
+}
+
+// applyOverlay recursively merges overlay into a copy of base, the way Kustomize applies a
+// strategic merge patch to maps: nested maps are merged key by key, and every other overlay
+// value, including lists, replaces the value in base
+func applyOverlay(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := deepCopyMapInterface(base)
+	for key, value := range overlay {
+		overlayMap, overlayIsMap := value.(map[string]interface{})
+		baseMap, baseIsMap := merged[key].(map[string]interface{})
+		switch {
+		case overlayIsMap && baseIsMap:
+			merged[key] = applyOverlay(baseMap, overlayMap)
+		case overlayIsMap:
+			merged[key] = deepCopyMapInterface(overlayMap)
+		default:
+			if list, ok := value.([]interface{}); ok {
+				value = deepCopySliceInterface(list)
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
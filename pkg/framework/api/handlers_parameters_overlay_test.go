@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
+// overlayTestSpec has a base spec and staging and prod overlays
+func overlayTestSpec() map[string]interface{} {
+	return map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace": "apps",
+			"replicas":  float64(1),
+			"resources": map[string]interface{}{"cpu": "100m", "memory": "128Mi"},
+		},
+		"services": map[string]interface{}{
+			"api": map[string]interface{}{"imageTag": "v1", "args": []interface{}{"--debug"}},
+		},
+		"overlays": map[string]interface{}{
+			"staging": map[string]interface{}{
+				"global": map[string]interface{}{"namespace": "apps-staging"},
+			},
+			"prod": map[string]interface{}{
+				"global": map[string]interface{}{
+					"replicas":  float64(3),
+					"resources": map[string]interface{}{"cpu": "500m"},
+				},
+				"services": map[string]interface{}{
+					"api": map[string]interface{}{"args": []interface{}{}},
+				},
+			},
+		},
+	}
+}
+
+func TestSpecWithOverlay(t *testing.T) {
+	tests := []struct {
+		overlay      string
+		wantGlobal   map[string]interface{}
+		wantServices map[string]interface{}
+	}{
+		{
+			overlay: "staging",
+			wantGlobal: map[string]interface{}{
+				"namespace": "apps-staging",
+				"replicas":  float64(1),
+				"resources": map[string]interface{}{"cpu": "100m", "memory": "128Mi"},
+			},
+			wantServices: map[string]interface{}{
+				"api": map[string]interface{}{"imageTag": "v1", "args": []interface{}{"--debug"}},
+			},
+		},
+		{
+			overlay: "prod",
+			wantGlobal: map[string]interface{}{
+				"namespace": "apps",
+				"replicas":  float64(3),
+				"resources": map[string]interface{}{"cpu": "500m", "memory": "128Mi"},
+			},
+			wantServices: map[string]interface{}{
+				"api": map[string]interface{}{"imageTag": "v1", "args": []interface{}{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.overlay, func(t *testing.T) {
+			spec := overlayTestSpec()
+			got, err := specWithOverlay(spec, tt.overlay)
+			if err != nil {
+				t.Fatalf("specWithOverlay() error = %v", err)
+			}
+			if !reflect.DeepEqual(got["global"], tt.wantGlobal) {
+				t.Errorf("global = %v, want %v", got["global"], tt.wantGlobal)
+			}
+			if !reflect.DeepEqual(got["services"], tt.wantServices) {
+				t.Errorf("services = %v, want %v", got["services"], tt.wantServices)
+			}
+			if _, ok := got[crd.OverlaysKey]; ok {
+				t.Error("specWithOverlay() kept the overlays field")
+			}
+			if !reflect.DeepEqual(spec, overlayTestSpec()) {
+				t.Error("specWithOverlay() modified the base spec")
+			}
+		})
+	}
+
+	if _, err := specWithOverlay(overlayTestSpec(), "dev"); err == nil {
+		t.Error("specWithOverlay() of an undefined overlay should fail")
+	}
+}
+
+func TestGetParameters_Overlay(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.parameterClient.CreateWithSpec(context.Background(), crd.DefaultName, "default", overlayTestSpec()); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.GetParameters(w, httptest.NewRequest("GET", "/api/parameters?overlay=prod", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetParameters() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("GetParameters() response is not valid JSON: %v", err)
+	}
+	global, _ := result["global"].(map[string]interface{})
+	if global["replicas"] != float64(3) || global["namespace"] != "apps" {
+		t.Errorf("GetParameters() global = %v, want prod replicas in the base namespace", global)
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetParameters(w, httptest.NewRequest("GET", "/api/parameters?overlay=dev", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetParameters() of an undefined overlay status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetServiceValuesMap_Overlay(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := handler.parameterClient.CreateWithSpec(ctx, crd.DefaultName, "default", overlayTestSpec()); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	for overlay, wantNamespace := range map[string]string{"": "apps", "staging": "apps-staging", "prod": "apps"} {
+		values := handler.getServiceValuesMap(ctx, []string{"api"}, "default", crd.DefaultName, overlay)
+		merged, _ := values["api"]["merged"].(map[string]interface{})
+		if merged["namespace"] != wantNamespace {
+			t.Errorf("overlay %q: merged namespace = %v, want %s", overlay, merged["namespace"], wantNamespace)
+		}
+	}
+}
@@ -1,13 +1,48 @@
 package api
 
 import (
+	"context"
 	"net/http"
 )
 
 // GetParametersSchema returns the CRD schema for form generation
 func (h *Handler) GetParametersSchema(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	specSchema, usingSample := h.parametersSpecSchema(r.Context())
+
+	// Debug: Check if descriptions are present in the returned schema
+	if usingSample {
+		h.logger.Info("using sample schema for /api/parameters/schema")
+		if specProps, ok := specSchema["properties"].(map[string]interface{}); ok {
+			if global, ok := specProps["global"].(map[string]interface{}); ok {
+				if globalProps, ok := global["properties"].(map[string]interface{}); ok {
+					if namespace, ok := globalProps["namespace"].(map[string]interface{}); ok {
+						if desc, ok := namespace["description"].(string); ok {
+							h.logger.Info("sample schema has description for namespace field", "description", desc)
+						} else {
+							h.logger.Info("sample schema missing description for namespace field", "namespace", namespace)
+						}
+					} else {
+						h.logger.Info("namespace field not found in global properties")
+					}
+				} else {
+					h.logger.Info("global properties not found")
+				}
+			} else {
+				h.logger.Info("global not found in spec properties")
+			}
+		} else {
+			h.logger.Info("spec properties not found in specSchema")
+		}
+	} else {
+		h.logger.Info("using CRD schema from cluster (not sample schema)")
+	}
 	
+	WriteJSONResponse(w, h.logger, http.StatusOK, specSchema)
+}
+
+// parametersSpecSchema returns the spec schema of the parameters CRD, falling back to the sample
+// schema when the cluster has none, and whether the sample was used
+func (h *Handler) parametersSpecSchema(ctx context.Context) (map[string]interface{}, bool) {
 	// Get CRD schema definition (raw OpenAPI schema) for form generation
 	crdSchema, err := h.parameterClient.GetCRDSchema(ctx)
 	usingSample := false
@@ -53,36 +88,8 @@ func (h *Handler) GetParametersSchema(w http.ResponseWriter, r *http.Request) {
 	if specSchema == nil {
 		specSchema = make(map[string]interface{})
 	}
-	
-	// Debug: Check if descriptions are present in the returned schema
-	if usingSample {
-		h.logger.Info("using sample schema for /api/parameters/schema")
-		if specProps, ok := specSchema["properties"].(map[string]interface{}); ok {
-			if global, ok := specProps["global"].(map[string]interface{}); ok {
-				if globalProps, ok := global["properties"].(map[string]interface{}); ok {
-					if namespace, ok := globalProps["namespace"].(map[string]interface{}); ok {
-						if desc, ok := namespace["description"].(string); ok {
-							h.logger.Info("sample schema has description for namespace field", "description", desc)
-						} else {
-							h.logger.Info("sample schema missing description for namespace field", "namespace", namespace)
-						}
-					} else {
-						h.logger.Info("namespace field not found in global properties")
-					}
-				} else {
-					h.logger.Info("global properties not found")
-				}
-			} else {
-				h.logger.Info("global not found in spec properties")
-			}
-		} else {
-			h.logger.Info("spec properties not found in specSchema")
-		}
-	} else {
-		h.logger.Info("using CRD schema from cluster (not sample schema)")
-	}
-	
-	WriteJSONResponse(w, h.logger, http.StatusOK, specSchema)
+
+	return specSchema, usingSample
 }
 
 // checkSchemaHasDescriptions checks if a schema has any descriptions
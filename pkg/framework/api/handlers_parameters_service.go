@@ -23,7 +23,7 @@ func (h *Handler) GetServiceParameters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	spec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace)
+	spec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace, getQueryNamespace(r) == "")
 	if err != nil {
 		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "get_service_parameters_failed", err.Error(), nil)
 		return
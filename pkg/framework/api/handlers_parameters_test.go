@@ -345,3 +345,141 @@ func TestGetInstanceName(t *testing.T) {
 	}
 }
 
+func TestParameters_NamespaceIsolation(t *testing.T) {
+	client := newTestParameterClientWithNamespaces(t, "staging", "production")
+	handler, err := newTestHandler(t, WithTestParameterClient(client))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	ctx := context.Background()
+	for _, namespace := range []string{"staging", "production"} {
+		spec := map[string]interface{}{
+			"global": map[string]interface{}{"namespace": namespace},
+		}
+		if err := client.CreateWithSpec(ctx, crd.DefaultName, namespace, spec); err != nil {
+			t.Fatalf("failed to create spec in %s: %v", namespace, err)
+		}
+	}
+
+	update := `{"global": {"namespace": "staging", "replicas": 2}}`
+	req := httptest.NewRequest("POST", "/api/parameters?namespace=staging", strings.NewReader(update))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateParameters() status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	tests := []struct {
+		namespace    string
+		wantReplicas interface{}
+	}{
+		{namespace: "staging", wantReplicas: float64(2)},
+		{namespace: "production", wantReplicas: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/api/parameters?namespace="+tt.namespace, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("GetParameters() status code = %v, want %v", w.Code, http.StatusOK)
+			}
+
+			var result map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("GetParameters() response is not valid JSON: %v", err)
+			}
+			global, _ := result["global"].(map[string]interface{})
+			if global["namespace"] != tt.namespace {
+				t.Errorf("GetParameters() namespace = %v, want %v", global["namespace"], tt.namespace)
+			}
+			if global["replicas"] != tt.wantReplicas {
+				t.Errorf("GetParameters() replicas = %v, want %v", global["replicas"], tt.wantReplicas)
+			}
+		})
+	}
+}
+
+func TestParameters_NamespaceValidation(t *testing.T) {
+	client := newTestParameterClientWithNamespaces(t, "staging")
+	handler, err := newTestHandler(t, WithTestParameterClient(client))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name      string
+		namespace string
+		wantCode  int
+		wantError string
+	}{
+		{name: "invalid name", namespace: "Staging_1", wantCode: http.StatusBadRequest, wantError: "invalid_namespace"},
+		{name: "missing namespace", namespace: "qa", wantCode: http.StatusNotFound, wantError: "namespace_not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/api/parameters?namespace="+tt.namespace, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GetParameters() status code = %v, want %v", w.Code, tt.wantCode)
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("GetParameters() response is not valid JSON: %v", err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("GetParameters() error = %v, want %v", resp.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestMergeParameters(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	ctx := context.Background()
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "default", "replicas": float64(1)},
+		"services": map[string]interface{}{
+			"redis": map[string]interface{}{"image": "redis:7"},
+		},
+	}
+	if err := handler.parameterClient.CreateWithSpec(ctx, crd.DefaultName, "default", spec); err != nil {
+		t.Fatalf("failed to create CRD spec: %v", err)
+	}
+
+	reqBody := `{"global": {"replicas": 3}, "services": {"postgres": {"image": "postgres:16"}}}`
+	req := httptest.NewRequest("POST", "/api/parameters/merge", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("MergeParameters() status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	stored, err := handler.parameterClient.GetSpec(ctx, crd.DefaultName, "default")
+	if err != nil {
+		t.Fatalf("GetSpec() error = %v", err)
+	}
+	global := stored["global"].(map[string]interface{})
+	if global["namespace"] != "default" || global["replicas"] != float64(3) {
+		t.Errorf("MergeParameters() global = %v, want namespace=default replicas=3", global)
+	}
+	services := stored["services"].(map[string]interface{})
+	if _, ok := services["redis"]; !ok {
+		t.Errorf("MergeParameters() removed existing service redis: %v", services)
+	}
+	if _, ok := services["postgres"]; !ok {
+		t.Errorf("MergeParameters() did not add service postgres: %v", services)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newValidatingParametersHandler(t *testing.T) *Handler {
+	t.Helper()
+	client := newTestParameterClientWithSchema(t, map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"global"},
+		"properties": map[string]interface{}{
+			"global": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"namespace": map[string]interface{}{"type": "string"},
+					"replicas":  map[string]interface{}{"type": "integer"},
+				},
+			},
+		},
+	})
+	handler, err := newTestHandler(t, WithTestReconciler(setupTestReconciler(t, true)), WithTestParameterClient(client))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	return handler
+}
+
+func TestUpdateParameters_SchemaValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantDetails map[string]string
+	}{
+		{
+			name:        "type mismatch",
+			body:        `{"global": {"replicas": "three"}}`,
+			wantDetails: map[string]string{"global.replicas": "must be of type integer, got string"},
+		},
+		{
+			name:        "missing required field",
+			body:        `{}`,
+			wantDetails: map[string]string{"global": "is required"},
+		},
+		{
+			name:        "extra field",
+			body:        `{"global": {"namespace": "apps", "extra": true}}`,
+			wantDetails: map[string]string{"global.extra": "is not a known field"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newValidatingParametersHandler(t)
+			w := httptest.NewRecorder()
+			handler.UpdateParameters(w, httptest.NewRequest("POST", "/api/parameters", strings.NewReader(tt.body)))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("UpdateParameters() status = %d, want %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+			}
+			var errResp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("UpdateParameters() response is not valid JSON: %v", err)
+			}
+			if errResp.Error != "validation_failed" {
+				t.Errorf("UpdateParameters() error = %q, want validation_failed", errResp.Error)
+			}
+			for field, message := range tt.wantDetails {
+				if errResp.Details[field] != message {
+					t.Errorf("UpdateParameters() details[%q] = %v, want %q", field, errResp.Details[field], message)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateParameters_SchemaValidationAcceptsValidSpec(t *testing.T) {
+	handler := newValidatingParametersHandler(t)
+
+	w := httptest.NewRecorder()
+	body := `{"global": {"namespace": "apps", "replicas": 2}}`
+	handler.UpdateParameters(w, httptest.NewRequest("POST", "/api/parameters", strings.NewReader(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateParameters() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
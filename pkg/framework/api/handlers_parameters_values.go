@@ -28,7 +28,7 @@ func (h *Handler) GetServiceValues(w http.ResponseWriter, r *http.Request) {
 		var merged map[string]interface{}
 		
 		// Try to get spec with fallback, but don't fail if cluster is unavailable
-		spec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace)
+		spec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace, getQueryNamespace(r) == "")
 		if err == nil && spec != nil {
 			// Merge global and service-specific parameters
 			merged = make(map[string]interface{})
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// writeRateLimitedPaths are the mutating endpoints that use the stricter write limit
+var writeRateLimitedPaths = []string{"/api/up", "/api/down", "/api/update", "/api/parameters", "/api/webhooks/trigger", "/api/ratelimit"}
+
+// rateLimitExemptPaths are never rate limited so probes keep working under load
+var rateLimitExemptPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// SetRateLimits configures per-client-IP limits for read and write endpoints.
+// A zero RequestsPerSecond leaves that class of endpoints unlimited.
+func (h *Handler) SetRateLimits(read, write RateLimitConfig) {
+	h.readLimiter = NewRateLimiter(read, h.logger)
+	h.writeLimiter = NewRateLimiter(write, h.logger)
+}
+
+// isWriteRateLimited reports whether r targets a write endpoint
+func isWriteRateLimited(r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+		return false
+	}
+	for _, path := range writeRateLimitedPaths {
+		if r.URL.Path == path || strings.HasPrefix(r.URL.Path, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitMiddleware applies the write limiter to write endpoints and the read limiter to the rest
+func (h *Handler) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimitExemptPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limiter := h.readLimiter
+		if isWriteRateLimited(r) {
+			limiter = h.writeLimiter
+		}
+		if limiter.Enabled() {
+			limiter.Middleware(next).ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ResetRateLimits clears all per-client limiters. It requires an authenticated caller, so it
+// is refused while authentication is disabled.
+func (h *Handler) ResetRateLimits(w http.ResponseWriter, r *http.Request) {
+	if authIdentityFrom(r) == nil {
+		WriteErrorResponse(w, h.logger, http.StatusForbidden, "authentication_required", "Resetting rate limits requires an authenticated caller", nil)
+		return
+	}
+	h.readLimiter.Reset()
+	h.writeLimiter.Reset()
+	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]string{"message": "Rate limits reset"})
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// PauseReconciler stops periodic reconciliation until ResumeReconciler is called
+func (h *Handler) PauseReconciler(w http.ResponseWriter, r *http.Request) {
+	h.setReconcilerPaused(w, r, true, "Reconciler paused")
+}
+
+// ResumeReconciler restarts periodic reconciliation after PauseReconciler
+func (h *Handler) ResumeReconciler(w http.ResponseWriter, r *http.Request) {
+	h.setReconcilerPaused(w, r, false, "Reconciler resumed")
+}
+
+func (h *Handler) setReconcilerPaused(w http.ResponseWriter, r *http.Request, paused bool, message string) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	rec.SetPaused(paused)
+	h.logger.Info(message)
+	WriteJSONResponse(w, h.logger, http.StatusOK, map[string]interface{}{
+		"message": message,
+		"paused":  paused,
+	})
+}
+
+// GetReconcileInterval returns the periodic reconciliation interval
+func (h *Handler) GetReconcileInterval(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, ReconcileIntervalRequest{Interval: rec.ReconcileInterval().String()})
+}
+
+// SetReconcileInterval changes the periodic reconciliation interval; the new value survives restarts
+func (h *Handler) SetReconcileInterval(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	var req ReconcileIntervalRequest
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	interval, err := time.ParseDuration(req.Interval)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: interval must be a duration such as 30s: %w", apperrors.ErrInvalidRequest, err))
+		return
+	}
+
+	if err := rec.SetReconcileInterval(interval); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	h.logger.Info("reconcile interval updated", "interval", interval.String())
+	WriteJSONResponse(w, h.logger, http.StatusOK, ReconcileIntervalRequest{Interval: interval.String()})
+}
+
+// GetManagedResourceStatus reports the live status of every resource the reconciler manages
+func (h *Handler) GetManagedResourceStatus(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	statuses, err := rec.GetManagedResourceStatus(r.Context())
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("get managed resource status: %w", err))
+		return
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, statuses)
+}
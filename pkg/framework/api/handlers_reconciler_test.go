@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+func TestReconcileInterval_GetAndSet(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/api/reconciler/interval", strings.NewReader(`{"interval":"30s"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/reconciler/interval", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp ReconcileIntervalRequest
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("GET response is not valid JSON: %v", err)
+	}
+	if resp.Interval != "30s" {
+		t.Errorf("interval = %q, want 30s", resp.Interval)
+	}
+}
+
+func TestReconcileInterval_InvalidRequests(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	for _, body := range []string{`{"interval":"soon"}`, `{"interval":"10ms"}`, `{"interval":`} {
+		t.Run(body, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("PUT", "/api/reconciler/interval", strings.NewReader(body)))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("PUT status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestReconcileInterval_NoReconciler(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/reconciler/interval", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestGetManagedResourceStatus(t *testing.T) {
+	handler, _ := setupResourceStatusHandler(t)
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/reconciler/managed", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var statuses map[string]reconciler.ManagedResourceStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("GET response is not valid JSON: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("GET returned %d resources, want 2: %v", len(statuses), statuses)
+	}
+	if web := statuses["default/Deployment/web"]; !web.Exists || web.ReadyReplicas == nil || *web.ReadyReplicas != 2 {
+		t.Errorf("Deployment status = %+v, want exists with 2 ready replicas", web)
+	}
+	if missing := statuses["default/Service/web"]; missing.Exists {
+		t.Errorf("Service status = %+v, want exists=false", missing)
+	}
+}
+
+func TestGetManagedResourceStatus_NoReconciler(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", "/api/reconciler/managed", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
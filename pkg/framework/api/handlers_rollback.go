@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// Rollback redeploys a stored rollback snapshot, the latest one unless ?version= is given
+func (h *Handler) Rollback(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	ctx := r.Context()
+
+	version := 0
+	if versionStr := r.URL.Query().Get("version"); versionStr != "" {
+		parsedVersion, err := strconv.Atoi(versionStr)
+		if err != nil || parsedVersion <= 0 {
+			WriteError(w, h.logger, fmt.Errorf("%w: version must be a positive integer", apperrors.ErrInvalid))
+			return
+		}
+		version = parsedVersion
+	}
+
+	snapshot, err := rec.GetRollbackSnapshot(ctx, version)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	manifests := snapshot.Manifests
+	updatedManifests, err := h.updateManifestsWithCurrentParameters(ctx, manifests, getInstanceName(r))
+	if err != nil {
+		h.logger.Error(err, "failed to update manifests with current parameters, using snapshot manifests")
+	} else {
+		manifests = updatedManifests
+	}
+
+	if _, err := rec.DeployManifests(ctx, manifests); err != nil {
+		h.logger.Error(err, "failed to roll back", "version", snapshot.Version)
+		WriteErrorResponse(w, h.logger, http.StatusInternalServerError, "rollback_failed", fmt.Sprintf("Rollback to version %d failed. Error: %s", snapshot.Version, err.Error()), apperrors.Details(err))
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, RollbackResponse{
+		Message: fmt.Sprintf("Rolled back to version %d", snapshot.Version),
+		Version: snapshot.Version,
+	})
+}
+
+// ListRollbackVersions returns metadata for every stored rollback snapshot
+func (h *Handler) ListRollbackVersions(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	snapshots, err := rec.ListRollbackSnapshots(r.Context())
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	versions := make([]RollbackVersion, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		versions = append(versions, RollbackVersion{
+			Version:   snapshot.Version,
+			Timestamp: snapshot.Timestamp,
+			KeyCount:  len(snapshot.ManagedKeys),
+		})
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, RollbackVersionsResponse{Versions: versions})
+}
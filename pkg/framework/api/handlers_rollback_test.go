@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListRollbackVersions_Empty(t *testing.T) {
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	router := handler.SetupRoutes()
+	req := httptest.NewRequest("GET", "/api/rollback/versions", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListRollbackVersions() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var resp RollbackVersionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ListRollbackVersions() response is not valid JSON: %v", err)
+	}
+	if resp.Versions == nil || len(resp.Versions) != 0 {
+		t.Errorf("ListRollbackVersions() versions = %v, want empty array", resp.Versions)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{name: "no snapshots", path: "/api/rollback", wantCode: http.StatusNotFound},
+		{name: "invalid version", path: "/api/rollback?version=abc", wantCode: http.StatusBadRequest},
+		{name: "non-positive version", path: "/api/rollback?version=0", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := setupTestReconciler(t, true)
+			handler, err := newTestHandler(t, WithTestReconciler(rec))
+			if err != nil {
+				t.Fatalf("newTestHandler() error = %v", err)
+			}
+
+			req := httptest.NewRequest("POST", tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.Rollback(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Rollback() status code = %v, want %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRollback_NoReconciler(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/rollback", nil)
+	w := httptest.NewRecorder()
+
+	handler.Rollback(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Rollback() status code = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+}
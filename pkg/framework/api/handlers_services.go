@@ -30,8 +30,8 @@ func (h *Handler) ListServices(w http.ResponseWriter, r *http.Request) {
 	for _, svc := range serviceInfos {
 		// Check if service is installed in Kubernetes
 		installed := false
-		if h.reconciler != nil {
-			clientset := h.reconciler.GetClientset()
+		if rec := h.reconcilerFor(r); rec != nil {
+			clientset := rec.GetClientset()
 			if clientset != nil {
 				_, err := clientset.CoreV1().Services(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
 				installed = err == nil && !k8serrors.IsNotFound(err)
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// defaultServiceEventsLimit is the number of events ServiceEvents returns without ?limit=
+const defaultServiceEventsLimit = 50
+
+// ServiceEvents returns the Kubernetes events of the pods selected by a managed Service,
+// most recent first. Supports ?limit= (default 50) and ?since= (duration such as 10m).
+func (h *Handler) ServiceEvents(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "service")
+	if !isValidKubernetesName(namespace) || !isValidKubernetesName(name) {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid namespace or service name", apperrors.ErrInvalidParameter))
+		return
+	}
+
+	limit, since, err := serviceEventsQuery(r)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	key := fmt.Sprintf("%s/Service/%s", namespace, name)
+	content, ok := h.store.Get(key)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: service %s", apperrors.ErrNotFound, key))
+		return
+	}
+	selector, err := extractServiceSelector(r.Context(), content)
+	if err != nil {
+		WriteError(w, h.logger, apperrors.WrapInvalid(err, "invalid service"))
+		return
+	}
+
+	rec := h.reconcilerFor(r)
+	if rec == nil || rec.GetClientset() == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "clientset_not_available", "Kubernetes client not available", nil)
+		return
+	}
+	clientset := rec.GetClientset()
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(r.Context(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to list pods of service %s: %w", apperrors.ErrKubernetes, key, err))
+		return
+	}
+
+	var podEvents []corev1.Event
+	for _, pod := range pods.Items {
+		list, err := clientset.CoreV1().Events(namespace).List(r.Context(), metav1.ListOptions{
+			FieldSelector: "involvedObject.name=" + pod.Name,
+		})
+		if err != nil {
+			WriteError(w, h.logger, fmt.Errorf("%w: failed to list events of pod %s/%s: %w", apperrors.ErrKubernetes, namespace, pod.Name, err))
+			return
+		}
+		for _, event := range list.Items {
+			// Field selectors are not applied by every client, so match the pod again
+			if event.InvolvedObject.Name == pod.Name && event.InvolvedObject.Kind == "Pod" {
+				podEvents = append(podEvents, event)
+			}
+		}
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, aggregateKubernetesEvents(podEvents, since, limit))
+}
+
+// serviceEventsQuery parses ?limit= and ?since=, returning the zero time when since is not set
+func serviceEventsQuery(r *http.Request) (int, time.Time, error) {
+	query := r.URL.Query()
+
+	limit := defaultServiceEventsLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return 0, time.Time{}, fmt.Errorf("%w: limit must be a positive number", apperrors.ErrInvalidParameter)
+		}
+		limit = parsed
+	}
+
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, time.Time{}, fmt.Errorf("%w: since must be a positive duration such as 10m", apperrors.ErrInvalidParameter)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	return limit, since, nil
+}
+
+// aggregateKubernetesEvents merges events with the same type, reason and message, drops those
+// last seen before since and returns at most limit of them, most recent first
+func aggregateKubernetesEvents(items []corev1.Event, since time.Time, limit int) []KubernetesEvent {
+	type eventKey struct{ eventType, reason, message string }
+	seenUIDs := make(map[string]bool)
+	merged := make(map[eventKey]*KubernetesEvent)
+
+	for _, item := range items {
+		if item.UID != "" {
+			if seenUIDs[string(item.UID)] {
+				continue
+			}
+			seenUIDs[string(item.UID)] = true
+		}
+		last := eventLastTimestamp(item)
+		if !since.IsZero() && last.Before(since) {
+			continue
+		}
+
+		count := item.Count
+		if count == 0 {
+			count = 1
+		}
+		key := eventKey{item.Type, item.Reason, item.Message}
+		if existing, ok := merged[key]; ok {
+			existing.Count += count
+			if last.After(existing.LastTimestamp) {
+				existing.LastTimestamp = last
+			}
+			continue
+		}
+		merged[key] = &KubernetesEvent{
+			Type:          item.Type,
+			Reason:        item.Reason,
+			Message:       item.Message,
+			Count:         count,
+			LastTimestamp: last,
+		}
+	}
+
+	result := make([]KubernetesEvent, 0, len(merged))
+	for _, event := range merged {
+		result = append(result, *event)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastTimestamp.Equal(result[j].LastTimestamp) {
+			return result[i].LastTimestamp.After(result[j].LastTimestamp)
+		}
+		if result[i].Reason != result[j].Reason {
+			return result[i].Reason < result[j].Reason
+		}
+		return result[i].Message < result[j].Message
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// eventLastTimestamp returns when an event was last seen, falling back to its event time and
+// creation time for events that do not set lastTimestamp
+func eventLastTimestamp(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
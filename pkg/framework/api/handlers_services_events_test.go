@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const eventsServiceManifest = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: apps
+spec:
+  selector:
+    app: web
+  ports:
+    - port: 80
+`
+
+// newServiceEventsTestHandler stores the web Service and creates two of its pods, a pod of
+// another app and a pod with the same labels in another namespace, each with events
+func newServiceEventsTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	if err := handler.store.Create("apps/Service/web", []byte(eventsServiceManifest)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	ctx := context.Background()
+	clientset := rec.GetClientset()
+	pods := []struct{ namespace, name, app string }{
+		{"apps", "web-1", "web"},
+		{"apps", "web-2", "web"},
+		{"apps", "db-1", "db"},
+		{"other", "web-1", "web"},
+	}
+	for _, p := range pods {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: p.namespace, Labels: map[string]string{"app": p.app}}}
+		if _, err := clientset.CoreV1().Pods(p.namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod %s/%s: %v", p.namespace, p.name, err)
+		}
+	}
+
+	now := time.Now()
+	events := []struct {
+		namespace, name, pod, eventType, reason, message string
+		count                                            int32
+		age                                              time.Duration
+	}{
+		{"apps", "web-1.oom", "web-1", corev1.EventTypeWarning, "OOMKilled", "Container api was OOM killed", 2, time.Minute},
+		{"apps", "web-2.oom", "web-2", corev1.EventTypeWarning, "OOMKilled", "Container api was OOM killed", 1, 3 * time.Minute},
+		{"apps", "web-1.backoff", "web-1", corev1.EventTypeWarning, "BackOff", "Back-off restarting failed container", 4, 2 * time.Minute},
+		{"apps", "web-2.pulled", "web-2", corev1.EventTypeNormal, "Pulled", "Successfully pulled image", 1, time.Hour},
+		{"apps", "db-1.backoff", "db-1", corev1.EventTypeWarning, "BackOff", "Back-off restarting db", 1, time.Minute},
+		{"other", "web-1.killing", "web-1", corev1.EventTypeNormal, "Killing", "Stopping container api", 1, time.Minute},
+	}
+	for _, e := range events {
+		event := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: e.name, Namespace: e.namespace, UID: types.UID(e.namespace + "/" + e.name)},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: e.pod, Namespace: e.namespace},
+			Type:           e.eventType,
+			Reason:         e.reason,
+			Message:        e.message,
+			Count:          e.count,
+			LastTimestamp:  metav1.NewTime(now.Add(-e.age)),
+		}
+		if _, err := clientset.CoreV1().Events(e.namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create event %s: %v", e.name, err)
+		}
+	}
+	return handler
+}
+
+func getServiceEvents(t *testing.T, handler *Handler, path string) (int, []KubernetesEvent) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	var events []KubernetesEvent
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+			t.Fatalf("ServiceEvents() response is not valid JSON: %v", err)
+		}
+	}
+	return w.Code, events
+}
+
+func TestServiceEvents(t *testing.T) {
+	handler := newServiceEventsTestHandler(t)
+
+	code, events := getServiceEvents(t, handler, "/api/services/apps/web/events")
+	if code != http.StatusOK {
+		t.Fatalf("ServiceEvents() status = %d, want %d", code, http.StatusOK)
+	}
+
+	want := []struct {
+		reason string
+		count  int32
+	}{{"OOMKilled", 3}, {"BackOff", 4}, {"Pulled", 1}}
+	if len(events) != len(want) {
+		t.Fatalf("ServiceEvents() returned %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Reason != w.reason || events[i].Count != w.count {
+			t.Errorf("ServiceEvents()[%d] = %s x%d, want %s x%d", i, events[i].Reason, events[i].Count, w.reason, w.count)
+		}
+	}
+	if events[0].Type != corev1.EventTypeWarning || events[0].Message != "Container api was OOM killed" {
+		t.Errorf("ServiceEvents()[0] = %+v, want the OOMKilled warning", events[0])
+	}
+	if time.Since(events[0].LastTimestamp) > 2*time.Minute {
+		t.Errorf("ServiceEvents()[0] last_timestamp = %v, want the most recent OOMKilled event", events[0].LastTimestamp)
+	}
+}
+
+func TestServiceEvents_Filters(t *testing.T) {
+	handler := newServiceEventsTestHandler(t)
+
+	_, events := getServiceEvents(t, handler, "/api/services/apps/web/events?since=30m")
+	if len(events) != 2 {
+		t.Errorf("ServiceEvents() with since=30m returned %d events, want 2: %+v", len(events), events)
+	}
+
+	_, events = getServiceEvents(t, handler, "/api/services/apps/web/events?limit=1")
+	if len(events) != 1 || events[0].Reason != "OOMKilled" {
+		t.Errorf("ServiceEvents() with limit=1 = %+v, want only the OOMKilled event", events)
+	}
+}
+
+func TestServiceEvents_Errors(t *testing.T) {
+	handler := newServiceEventsTestHandler(t)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{name: "unknown service", path: "/api/services/apps/missing/events", want: http.StatusNotFound},
+		{name: "invalid limit", path: "/api/services/apps/web/events?limit=zero", want: http.StatusBadRequest},
+		{name: "invalid since", path: "/api/services/apps/web/events?since=yesterday", want: http.StatusBadRequest},
+		{name: "invalid namespace", path: "/api/services/Apps_1/web/events", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := getServiceEvents(t, handler, tt.path); code != tt.want {
+				t.Errorf("ServiceEvents() status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// HealthPathAnnotation on a Service manifest selects the path ProbeService requests
+const HealthPathAnnotation = "conductor.io/health-path"
+
+// defaultProbePath is requested when a Service has no HealthPathAnnotation
+const defaultProbePath = "/healthz"
+
+// SetServiceProbe sets the HTTP client and per-probe timeout used by ProbeService.
+// A nil client keeps the current one and a non-positive timeout keeps DefaultProbeTimeout.
+// Probes never follow redirects, whatever the CheckRedirect of client.
+func (h *Handler) SetServiceProbe(client *http.Client, timeout time.Duration) {
+	if client != nil {
+		h.probeClient = newProbeClient(client)
+	}
+	if timeout > 0 {
+		h.probeTimeout = timeout
+	}
+}
+
+// newProbeClient returns a copy of client that returns redirect responses instead of following
+// them, so a probed Service cannot send the probe to another host
+func newProbeClient(client *http.Client) *http.Client {
+	probeClient := *client
+	probeClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &probeClient
+}
+
+// probeServiceManifest is the subset of a Service manifest needed to find its health path
+type probeServiceManifest struct {
+	Metadata struct {
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+}
+
+// validateProbePath checks that path is an absolute path on the probed Service, so that it
+// cannot point the probe at another host
+func validateProbePath(path string) error {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") ||
+		strings.ContainsAny(path, "@\\") || strings.Contains(path, "://") {
+		return fmt.Errorf("%w: %s must be an absolute path such as /healthz", apperrors.ErrInvalid, HealthPathAnnotation)
+	}
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil {
+		return fmt.Errorf("%w: %s must be an absolute path such as /healthz", apperrors.ErrInvalid, HealthPathAnnotation)
+	}
+	return nil
+}
+
+// ProbeService requests the health path of a managed Service and reports whether it answered
+// with a 2xx status. The Service is addressed by the clusterIP and first port of the live
+// Service, or by its cluster DNS name when it is headless. Redirects are not followed.
+func (h *Handler) ProbeService(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "service")
+	if !isValidKubernetesName(namespace) || !isValidKubernetesName(name) {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid namespace or service name", apperrors.ErrInvalidParameter))
+		return
+	}
+
+	key := fmt.Sprintf("%s/Service/%s", namespace, name)
+	content, ok := h.store.Get(key)
+	if !ok {
+		WriteError(w, h.logger, fmt.Errorf("%w: service %s", apperrors.ErrNotFound, key))
+		return
+	}
+
+	var manifest probeServiceManifest
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to parse service %s: %w", apperrors.ErrInvalid, key, err))
+		return
+	}
+	path := manifest.Metadata.Annotations[HealthPathAnnotation]
+	if path == "" {
+		path = defaultProbePath
+	}
+	if err := validateProbePath(path); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	rec := h.reconcilerFor(r)
+	if rec == nil || rec.GetClientset() == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "clientset_not_available", "Kubernetes client not available", nil)
+		return
+	}
+	svc, err := rec.GetClientset().CoreV1().Services(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			WriteError(w, h.logger, fmt.Errorf("%w: service %s is not deployed", apperrors.ErrNotFound, key))
+			return
+		}
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to get service %s: %w", apperrors.ErrKubernetes, key, err))
+		return
+	}
+	if len(svc.Spec.Ports) == 0 {
+		WriteError(w, h.logger, fmt.Errorf("%w: service %s exposes no ports", apperrors.ErrInvalid, key))
+		return
+	}
+
+	host := svc.Spec.ClusterIP
+	if host == "" || host == "None" {
+		host = fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace)
+	}
+	target := "http://" + net.JoinHostPort(host, strconv.Itoa(int(svc.Spec.Ports[0].Port))) + path
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, h.probe(r, target))
+}
+
+// probe sends a GET request to target, bounded by the probe timeout
+func (h *Handler) probe(r *http.Request, target string) ServiceProbeResponse {
+	resp := ServiceProbeResponse{URL: target}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+
+	start := time.Now()
+	httpResp, err := h.probeClient.Do(req)
+	resp.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	httpResp.Body.Close()
+
+	resp.StatusCode = httpResp.StatusCode
+	resp.Reachable = httpResp.StatusCode >= 200 && httpResp.StatusCode < 300
+	return resp
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newProbeTestHandler stores a Service manifest with the given health path annotation and
+// deploys a live Service addressing target
+func newProbeTestHandler(t *testing.T, target, healthPath string) *Handler {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		t.Fatalf("invalid probe target %q: %v", target, err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("invalid probe port %q: %v", port, err)
+	}
+	live := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: host,
+			Ports:     []corev1.ServicePort{{Port: int32(portNumber)}},
+		},
+	}
+	if _, err := rec.GetClientset().CoreV1().Services("default").Create(context.Background(), live, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create live service: %v", err)
+	}
+	annotations := ""
+	if healthPath != "" {
+		annotations = fmt.Sprintf("\n  annotations:\n    %s: %s", HealthPathAnnotation, healthPath)
+	}
+	// The manifest addresses another host; the probe must use the live Service
+	manifest := fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default%s
+spec:
+  clusterIP: 192.0.2.1
+  ports:
+    - port: 80
+`, annotations)
+	if err := handler.store.Create("default/Service/web", []byte(manifest)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	return handler
+}
+
+func probe(t *testing.T, handler *Handler, path string) (int, ServiceProbeResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	var resp ServiceProbeResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("ProbeService() response is not valid JSON: %v", err)
+		}
+	}
+	return w.Code, resp
+}
+
+func TestProbeService_Reachable(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	handler := newProbeTestHandler(t, server.Listener.Addr().String(), "/ready")
+	code, resp := probe(t, handler, "/api/services/default/web/probe")
+
+	if code != http.StatusOK {
+		t.Fatalf("ProbeService() status = %d, want %d", code, http.StatusOK)
+	}
+	if !resp.Reachable || resp.StatusCode != http.StatusOK {
+		t.Errorf("ProbeService() = %+v, want reachable with status 200", resp)
+	}
+	if requested != "/ready" {
+		t.Errorf("probed path = %q, want the annotated /ready", requested)
+	}
+	if resp.LatencyMs < 0 {
+		t.Errorf("ProbeService() latency_ms = %d, want non-negative", resp.LatencyMs)
+	}
+}
+
+func TestProbeService_DefaultPathAndErrorStatus(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	handler := newProbeTestHandler(t, server.Listener.Addr().String(), "")
+	_, resp := probe(t, handler, "/api/services/default/web/probe")
+
+	if resp.Reachable || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("ProbeService() = %+v, want unreachable with status 503", resp)
+	}
+	if requested != defaultProbePath {
+		t.Errorf("probed path = %q, want %q", requested, defaultProbePath)
+	}
+}
+
+func TestProbeService_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	target := server.Listener.Addr().String()
+	server.Close()
+
+	handler := newProbeTestHandler(t, target, "")
+	_, resp := probe(t, handler, "/api/services/default/web/probe")
+
+	if resp.Reachable || resp.StatusCode != 0 || resp.Error == "" {
+		t.Errorf("ProbeService() = %+v, want unreachable with an error", resp)
+	}
+}
+
+func TestProbeService_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	handler := newProbeTestHandler(t, server.Listener.Addr().String(), "")
+	handler.SetServiceProbe(nil, 20*time.Millisecond)
+	_, resp := probe(t, handler, "/api/services/default/web/probe")
+
+	if resp.Reachable || resp.Error == "" {
+		t.Errorf("ProbeService() = %+v, want unreachable after the probe timeout", resp)
+	}
+}
+
+func TestProbeService_UnknownService(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	if code, _ := probe(t, handler, "/api/services/default/missing/probe"); code != http.StatusNotFound {
+		t.Errorf("ProbeService() status = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestProbeService_RejectsUnsafeHealthPath(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	paths := []string{"healthz", "//evil.example.com/x", "/@evil.example.com", "http://evil.example.com/", `/\\evil.example.com`}
+	for _, path := range paths {
+		handler := newProbeTestHandler(t, server.Listener.Addr().String(), fmt.Sprintf("%q", path))
+		if code, _ := probe(t, handler, "/api/services/default/web/probe"); code != http.StatusBadRequest {
+			t.Errorf("ProbeService() with health path %q status = %d, want %d", path, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestProbeService_DoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	handler := newProbeTestHandler(t, server.Listener.Addr().String(), "")
+	handler.SetServiceProbe(&http.Client{}, 0)
+	_, resp := probe(t, handler, "/api/services/default/web/probe")
+
+	if redirected {
+		t.Error("ProbeService() followed the redirect to another host")
+	}
+	if resp.Reachable || resp.StatusCode != http.StatusFound {
+		t.Errorf("ProbeService() = %+v, want unreachable with status 302", resp)
+	}
+}
+
+func TestProbeService_NotDeployed(t *testing.T) {
+	handler, err := newTestHandler(t, WithTestReconciler(setupTestReconciler(t, true)))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	manifest := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: default\n"
+	if err := handler.store.Create("default/Service/web", []byte(manifest)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+
+	if code, _ := probe(t, handler, "/api/services/default/web/probe"); code != http.StatusNotFound {
+		t.Errorf("ProbeService() status = %d, want %d", code, http.StatusNotFound)
+	}
+}
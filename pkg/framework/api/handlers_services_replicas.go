@@ -0,0 +1,219 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// GetServiceReplicas returns the desired, ready and available replicas of the live Deployment,
+// or else StatefulSet, named after the service
+func (h *Handler) GetServiceReplicas(w http.ResponseWriter, r *http.Request) {
+	namespace, name, clientset, ok := h.serviceReplicasRequest(w, r)
+	if !ok {
+		return
+	}
+
+	replicas, err := serviceWorkloadReplicas(r.Context(), clientset, namespace, name)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	WriteJSONResponse(w, h.logger, http.StatusOK, replicas)
+}
+
+// ScaleService sets the replicas of the Deployment or StatefulSet named after the service
+// through its scale subresource from a {"replicas": n} body. The new count is recorded in the
+// stored manifest first, so a reconcile running meanwhile cannot revert the scale, and the
+// manifest is restored when scaling fails.
+func (h *Handler) ScaleService(w http.ResponseWriter, r *http.Request) {
+	namespace, name, clientset, ok := h.serviceReplicasRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Replicas *int32 `json:"replicas"`
+	}
+	if err := h.parseJSONRequest(r, &req); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+	if req.Replicas == nil || *req.Replicas < 0 {
+		WriteError(w, h.logger, fmt.Errorf("%w: replicas must be a non-negative integer", apperrors.ErrInvalidParameter))
+		return
+	}
+
+	current, err := serviceWorkloadReplicas(r.Context(), clientset, namespace, name)
+	if err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", namespace, current.Kind, name)
+	previous, stored, err := h.setManifestReplicas(key, *req.Replicas)
+	if err != nil {
+		h.logger.Error(err, "failed to record scaled replicas", "key", key)
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	scale := &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       autoscalingv1.ScaleSpec{Replicas: *req.Replicas},
+	}
+	if current.Kind == "StatefulSet" {
+		_, err = clientset.AppsV1().StatefulSets(namespace).UpdateScale(r.Context(), name, scale, metav1.UpdateOptions{})
+	} else {
+		_, err = clientset.AppsV1().Deployments(namespace).UpdateScale(r.Context(), name, scale, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		if stored {
+			if restoreErr := h.store.Update(key, previous); restoreErr != nil {
+				h.logger.Error(restoreErr, "failed to restore manifest after failed scale", "key", key)
+			}
+		}
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to scale %s %s/%s: %w", apperrors.ErrKubernetes, current.Kind, namespace, name, err))
+		return
+	}
+
+	current.Desired = *req.Replicas
+	WriteJSONResponse(w, h.logger, http.StatusOK, current)
+}
+
+// serviceReplicasRequest validates the namespace and service of a replicas request and returns
+// the clientset, writing the error response and returning false when either is unavailable
+func (h *Handler) serviceReplicasRequest(w http.ResponseWriter, r *http.Request) (string, string, kubernetes.Interface, bool) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "service")
+	if !isValidKubernetesName(namespace) || !isValidKubernetesName(name) {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid namespace or service name", apperrors.ErrInvalidParameter))
+		return "", "", nil, false
+	}
+	rec := h.reconcilerFor(r)
+	if rec == nil || rec.GetClientset() == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "clientset_not_available", "Kubernetes client not available", nil)
+		return "", "", nil, false
+	}
+	return namespace, name, rec.GetClientset(), true
+}
+
+// serviceWorkloadReplicas reads the replica counts of the Deployment namespace/name, falling
+// back to the StatefulSet of that name
+func serviceWorkloadReplicas(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (ServiceReplicas, error) {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return ServiceReplicas{
+			Kind:      "Deployment",
+			Desired:   desiredReplicas(deployment.Spec.Replicas),
+			Ready:     deployment.Status.ReadyReplicas,
+			Available: deployment.Status.AvailableReplicas,
+		}, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return ServiceReplicas{}, fmt.Errorf("%w: failed to get deployment %s/%s: %w", apperrors.ErrKubernetes, namespace, name, err)
+	}
+
+	statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return ServiceReplicas{
+			Kind:      "StatefulSet",
+			Desired:   desiredReplicas(statefulSet.Spec.Replicas),
+			Ready:     statefulSet.Status.ReadyReplicas,
+			Available: statefulSet.Status.AvailableReplicas,
+		}, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return ServiceReplicas{}, fmt.Errorf("%w: failed to get statefulset %s/%s: %w", apperrors.ErrKubernetes, namespace, name, err)
+	}
+	return ServiceReplicas{}, fmt.Errorf("%w: no Deployment or StatefulSet %s/%s", apperrors.ErrNotFound, namespace, name)
+}
+
+// desiredReplicas returns .spec.replicas, which Kubernetes defaults to one
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// setManifestReplicas sets .spec.replicas of the manifest stored under key and returns the
+// previous content and whether the manifest is stored. Workloads that are not stored are
+// scaled in the cluster only.
+func (h *Handler) setManifestReplicas(key string, replicas int32) ([]byte, bool, error) {
+	content, ok := h.store.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	updated, err := withReplicas(content, replicas)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: failed to set replicas in %s: %w", apperrors.ErrInvalidYAML, key, err)
+	}
+	if err := h.store.Update(key, updated); err != nil {
+		return nil, false, fmt.Errorf("%w: failed to store %s: %w", apperrors.ErrStorage, key, err)
+	}
+	return content, true, nil
+}
+
+// withReplicas returns the manifest content with .spec.replicas set to replicas. Only that
+// node is edited, so the order of the other fields and their comments are kept.
+func withReplicas(content []byte, replicas int32) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("manifest is not a YAML mapping")
+	}
+
+	spec := mappingValue(doc.Content[0], "spec")
+	if spec == nil {
+		spec = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		doc.Content[0].Content = append(doc.Content[0].Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "spec"}, spec)
+	}
+	if spec.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("spec is not a mapping")
+	}
+
+	count := strconv.Itoa(int(replicas))
+	if node := mappingValue(spec, "replicas"); node != nil {
+		node.Kind, node.Tag, node.Style, node.Value, node.Content = yaml.ScalarNode, "!!int", 0, count, nil
+	} else {
+		spec.Content = append(spec.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "replicas"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: count})
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value node of key in a YAML mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const replicasDeploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+spec:
+  replicas: 3
+`
+
+// newReplicasTestHandler creates the live web Deployment with 3 desired and 2 ready replicas
+// and the api StatefulSet, and stores the manifest of the Deployment
+func newReplicasTestHandler(t *testing.T) (*Handler, *kubefake.Clientset) {
+	t.Helper()
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	clientset := rec.GetClientset().(*kubefake.Clientset)
+	ctx := context.Background()
+
+	three, one := int32(3), int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &three},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, AvailableReplicas: 2},
+	}
+	if _, err := clientset.AppsV1().Deployments("apps").Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "apps"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &one},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1, AvailableReplicas: 1},
+	}
+	if _, err := clientset.AppsV1().StatefulSets("apps").Create(ctx, statefulSet, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create statefulset: %v", err)
+	}
+
+	if err := handler.store.Create("apps/Deployment/web", []byte(replicasDeploymentManifest)); err != nil {
+		t.Fatalf("failed to create test manifest: %v", err)
+	}
+	return handler, clientset
+}
+
+// recordScales captures the scale subresource updates sent for resource
+func recordScales(clientset *kubefake.Clientset, resource string) *[]*autoscalingv1.Scale {
+	var scales []*autoscalingv1.Scale
+	clientset.PrependReactor("update", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+		scales = append(scales, scale)
+		return true, scale, nil
+	})
+	return &scales
+}
+
+func TestGetServiceReplicas(t *testing.T) {
+	handler, _ := newReplicasTestHandler(t)
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		path string
+		want ServiceReplicas
+	}{
+		{"/api/services/apps/web/replicas", ServiceReplicas{Kind: "Deployment", Desired: 3, Ready: 2, Available: 2}},
+		{"/api/services/apps/api/replicas", ServiceReplicas{Kind: "StatefulSet", Desired: 1, Ready: 1, Available: 1}},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want %d: %s", tt.path, w.Code, http.StatusOK, w.Body.String())
+		}
+		var got ServiceReplicas
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("GET %s = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/services/apps/missing/replicas", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET missing workload status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestScaleService(t *testing.T) {
+	handler, clientset := newReplicasTestHandler(t)
+	scales := recordScales(clientset, "deployments")
+
+	req := httptest.NewRequest("PUT", "/api/services/apps/web/replicas", strings.NewReader(`{"replicas": 5}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ScaleService() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(*scales) != 1 || (*scales)[0].Spec.Replicas != 5 || (*scales)[0].Name != "web" {
+		t.Fatalf("scale updates = %+v, want one of web to 5 replicas", *scales)
+	}
+
+	var resp ServiceReplicas
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Desired != 5 {
+		t.Errorf("desired = %d, want 5", resp.Desired)
+	}
+
+	content, ok := handler.store.Get("apps/Deployment/web")
+	if !ok {
+		t.Fatal("manifest missing after scale")
+	}
+	var stored struct {
+		Metadata struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Replicas int `yaml:"replicas"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(content, &stored); err != nil {
+		t.Fatalf("failed to parse stored manifest: %v", err)
+	}
+	if stored.Spec.Replicas != 5 || stored.Metadata.Name != "web" {
+		t.Errorf("stored manifest = %+v, want web with 5 replicas", stored)
+	}
+}
+
+func TestScaleService_StatefulSetWithoutManifest(t *testing.T) {
+	handler, clientset := newReplicasTestHandler(t)
+	scales := recordScales(clientset, "statefulsets")
+
+	req := httptest.NewRequest("PUT", "/api/services/apps/api/replicas", strings.NewReader(`{"replicas": 0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ScaleService() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(*scales) != 1 || (*scales)[0].Spec.Replicas != 0 {
+		t.Errorf("scale updates = %+v, want one to 0 replicas", *scales)
+	}
+	if _, ok := handler.store.Get("apps/StatefulSet/api"); ok {
+		t.Error("scaling a workload without a manifest stored one")
+	}
+}
+
+func TestScaleService_InvalidReplicas(t *testing.T) {
+	handler, clientset := newReplicasTestHandler(t)
+	scales := recordScales(clientset, "deployments")
+
+	for _, body := range []string{`{}`, `{"replicas": -1}`, `{"replicas": "two"}`} {
+		req := httptest.NewRequest("PUT", "/api/services/apps/web/replicas", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.SetupRoutes().ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ScaleService(%s) status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(*scales) != 0 {
+		t.Errorf("invalid requests sent scale updates: %+v", *scales)
+	}
+}
+
+func TestScaleService_RestoresManifestWhenScaleFails(t *testing.T) {
+	handler, clientset := newReplicasTestHandler(t)
+	clientset.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		// The manifest is recorded before the cluster is scaled
+		if content, _ := handler.store.Get("apps/Deployment/web"); !strings.Contains(string(content), "replicas: 5") {
+			t.Errorf("manifest at scale = %s, want 5 replicas recorded first", content)
+		}
+		return true, nil, k8serrors.NewForbidden(autoscalingv1.Resource("scale"), "web", nil)
+	})
+
+	req := httptest.NewRequest("PUT", "/api/services/apps/web/replicas", strings.NewReader(`{"replicas": 5}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code == http.StatusOK {
+		t.Fatalf("ScaleService() status = %d, want an error", w.Code)
+	}
+	if content, _ := handler.store.Get("apps/Deployment/web"); string(content) != replicasDeploymentManifest {
+		t.Errorf("manifest after failed scale = %s, want it restored", content)
+	}
+}
+
+func TestWithReplicas_KeepsRestOfManifest(t *testing.T) {
+	content := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web # the public site
+spec:
+  selector:
+    matchLabels:
+      app: web
+  replicas: 3
+  template: {}
+`
+	got, err := withReplicas([]byte(content), 5)
+	if err != nil {
+		t.Fatalf("withReplicas() error = %v", err)
+	}
+	want := strings.Replace(content, "replicas: 3", "replicas: 5", 1)
+	if string(got) != want {
+		t.Errorf("withReplicas() = %s, want %s", got, want)
+	}
+
+	got, err = withReplicas([]byte("apiVersion: apps/v1\nkind: Deployment\n"), 2)
+	if err != nil {
+		t.Fatalf("withReplicas() without spec error = %v", err)
+	}
+	if want := "apiVersion: apps/v1\nkind: Deployment\nspec:\n  replicas: 2\n"; string(got) != want {
+		t.Errorf("withReplicas() without spec = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Topology edge types
+const (
+	TopologyEdgeSelector  = "selector"
+	TopologyEdgeEnvRef    = "envRef"
+	TopologyEdgeSecretRef = "secretRef"
+)
+
+// topologyObject holds the fields of a manifest needed to build the topology
+type topologyObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Selector map[string]interface{} `yaml:"selector"`
+		Template struct {
+			Metadata struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Containers []struct {
+					Env []struct {
+						Value     string `yaml:"value"`
+						ValueFrom struct {
+							SecretKeyRef struct {
+								Name string `yaml:"name"`
+							} `yaml:"secretKeyRef"`
+						} `yaml:"valueFrom"`
+					} `yaml:"env"`
+					EnvFrom []struct {
+						SecretRef struct {
+							Name string `yaml:"name"`
+						} `yaml:"secretRef"`
+					} `yaml:"envFrom"`
+				} `yaml:"containers"`
+				Volumes []struct {
+					Secret struct {
+						SecretName string `yaml:"secretName"`
+					} `yaml:"secret"`
+				} `yaml:"volumes"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+func (o *topologyObject) namespace() string {
+	if o.Metadata.Namespace == "" {
+		return "default"
+	}
+	return o.Metadata.Namespace
+}
+
+func (o *topologyObject) key() string {
+	return fmt.Sprintf("%s/%s/%s", o.namespace(), o.Kind, o.Metadata.Name)
+}
+
+func isWorkloadKind(kind string) bool {
+	return kind == "Deployment" || kind == "StatefulSet"
+}
+
+// ServiceTopology returns a graph of Services, workloads and Secrets connected by
+// selector matches, env var references to Services and Secret references
+func (h *Handler) ServiceTopology(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), DefaultRequestTimeout)
+	defer cancel()
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, buildTopology(ctx, h.store.List()))
+}
+
+func buildTopology(ctx context.Context, manifests map[string][]byte) TopologyResponse {
+	var services, workloads []*topologyObject
+	nodes := make(map[string]TopologyNode)
+
+	for _, yamlData := range manifests {
+		select {
+		case <-ctx.Done():
+			return TopologyResponse{Nodes: []TopologyNode{}, Edges: []TopologyEdge{}}
+		default:
+		}
+
+		obj := &topologyObject{}
+		if err := yaml.Unmarshal(yamlData, obj); err != nil || obj.Metadata.Name == "" {
+			continue
+		}
+
+		switch {
+		case obj.Kind == "Service":
+			services = append(services, obj)
+		case isWorkloadKind(obj.Kind):
+			workloads = append(workloads, obj)
+		case obj.Kind == "Secret":
+		default:
+			continue
+		}
+		nodes[obj.key()] = TopologyNode{Name: obj.Metadata.Name, Kind: obj.Kind, Namespace: obj.namespace()}
+	}
+
+	edges := make(map[TopologyEdge]bool)
+
+	for _, svc := range services {
+		selector := make(map[string]string)
+		for k, v := range svc.Spec.Selector {
+			if str, ok := v.(string); ok {
+				selector[k] = str
+			}
+		}
+		if len(selector) == 0 {
+			continue
+		}
+		for _, wl := range workloads {
+			if wl.namespace() == svc.namespace() && labelsMatch(wl.Spec.Template.Metadata.Labels, selector) {
+				edges[TopologyEdge{Source: svc.key(), Target: wl.key(), Type: TopologyEdgeSelector}] = true
+			}
+		}
+	}
+
+	for _, wl := range workloads {
+		addSecret := func(name string) {
+			if name == "" {
+				return
+			}
+			key := fmt.Sprintf("%s/Secret/%s", wl.namespace(), name)
+			if _, ok := nodes[key]; !ok {
+				nodes[key] = TopologyNode{Name: name, Kind: "Secret", Namespace: wl.namespace()}
+			}
+			edges[TopologyEdge{Source: wl.key(), Target: key, Type: TopologyEdgeSecretRef}] = true
+		}
+
+		podSpec := wl.Spec.Template.Spec
+		for _, container := range podSpec.Containers {
+			for _, env := range container.Env {
+				addSecret(env.ValueFrom.SecretKeyRef.Name)
+				if env.Value == "" {
+					continue
+				}
+				for _, svc := range services {
+					if referencesService(env.Value, svc.Metadata.Name, svc.namespace(), wl.namespace()) {
+						edges[TopologyEdge{Source: wl.key(), Target: svc.key(), Type: TopologyEdgeEnvRef}] = true
+					}
+				}
+			}
+			for _, envFrom := range container.EnvFrom {
+				addSecret(envFrom.SecretRef.Name)
+			}
+		}
+		for _, volume := range podSpec.Volumes {
+			addSecret(volume.Secret.SecretName)
+		}
+	}
+
+	response := TopologyResponse{
+		Nodes: make([]TopologyNode, 0, len(nodes)),
+		Edges: make([]TopologyEdge, 0, len(edges)),
+	}
+	for _, node := range nodes {
+		response.Nodes = append(response.Nodes, node)
+	}
+	for edge := range edges {
+		response.Edges = append(response.Edges, edge)
+	}
+
+	sort.Slice(response.Nodes, func(i, j int) bool {
+		a, b := response.Nodes[i], response.Nodes[j]
+		return fmt.Sprintf("%s/%s/%s", a.Namespace, a.Kind, a.Name) < fmt.Sprintf("%s/%s/%s", b.Namespace, b.Kind, b.Name)
+	})
+	sort.Slice(response.Edges, func(i, j int) bool {
+		a, b := response.Edges[i], response.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Type < b.Type
+	})
+
+	return response
+}
+
+// labelsMatch reports whether labels contain every key/value in selector
+func labelsMatch(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// referencesService reports whether value refers to the Service by hostname.
+// Services in other namespaces must be addressed as name.namespace.
+func referencesService(value, name, serviceNamespace, workloadNamespace string) bool {
+	host := regexp.QuoteMeta(name)
+	if serviceNamespace != workloadNamespace {
+		host += `\.` + regexp.QuoteMeta(serviceNamespace)
+	}
+	pattern := `(^|[^A-Za-z0-9.-])` + host + `($|[^A-Za-z0-9-])`
+	matched, err := regexp.MatchString(pattern, value)
+	return err == nil && matched
+}
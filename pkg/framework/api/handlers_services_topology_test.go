@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServiceTopology(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	manifests := map[string]string{
+		"default/Service/frontend": `apiVersion: v1
+kind: Service
+metadata:
+  name: frontend
+  namespace: default
+spec:
+  selector:
+    app: frontend
+  ports:
+    - port: 80
+`,
+		"default/Service/api": `apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: default
+spec:
+  selector:
+    app: api
+  ports:
+    - port: 8080
+`,
+		"default/Service/db": `apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+spec:
+  ports:
+    - port: 5432
+`,
+		"default/Deployment/frontend": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+  namespace: default
+spec:
+  template:
+    metadata:
+      labels:
+        app: frontend
+    spec:
+      containers:
+        - name: frontend
+          env:
+            - name: API_URL
+              value: http://api:8080
+            - name: API_TOKEN
+              valueFrom:
+                secretKeyRef:
+                  name: api-token
+                  key: token
+`,
+	}
+	for key, value := range manifests {
+		if err := handler.store.Create(key, []byte(value)); err != nil {
+			t.Fatalf("failed to create test manifest: %v", err)
+		}
+	}
+
+	router := handler.SetupRoutes()
+	req := httptest.NewRequest("GET", "/api/services/topology", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ServiceTopology() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var resp TopologyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("ServiceTopology() response is not valid JSON: %v", err)
+	}
+
+	// 3 Services, 1 Deployment and the referenced Secret
+	if len(resp.Nodes) != 5 {
+		t.Errorf("ServiceTopology() returned %d nodes, want 5: %+v", len(resp.Nodes), resp.Nodes)
+	}
+
+	want := []TopologyEdge{
+		{Source: "default/Deployment/frontend", Target: "default/Secret/api-token", Type: TopologyEdgeSecretRef},
+		{Source: "default/Deployment/frontend", Target: "default/Service/api", Type: TopologyEdgeEnvRef},
+		{Source: "default/Service/frontend", Target: "default/Deployment/frontend", Type: TopologyEdgeSelector},
+	}
+	if len(resp.Edges) != len(want) {
+		t.Fatalf("ServiceTopology() edges = %+v, want %+v", resp.Edges, want)
+	}
+	for i := range want {
+		if resp.Edges[i] != want[i] {
+			t.Errorf("ServiceTopology() edge[%d] = %+v, want %+v", i, resp.Edges[i], want[i])
+		}
+	}
+}
+
+func TestServiceTopology_Empty(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/services/topology", nil)
+	w := httptest.NewRecorder()
+	handler.ServiceTopology(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ServiceTopology() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if got := w.Body.String(); got != "{\"nodes\":[],\"edges\":[]}\n" {
+		t.Errorf("ServiceTopology() = %q, want empty graph", got)
+	}
+}
+
+func TestReferencesService(t *testing.T) {
+	tests := []struct {
+		value string
+		ns    string
+		want  bool
+	}{
+		{value: "http://api:8080", ns: "default", want: true},
+		{value: "api.default.svc.cluster.local", ns: "default", want: true},
+		{value: "http://my-api:8080", ns: "default", want: false},
+		{value: "http://api-gateway", ns: "default", want: false},
+		{value: "http://api.other:8080", ns: "other", want: true},
+		{value: "http://api:8080", ns: "other", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := referencesService(tt.value, "api", tt.ns, "default"); got != tt.want {
+			t.Errorf("referencesService(%q, ns=%s) = %v, want %v", tt.value, tt.ns, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// resourceStatusCacheTTL is how long a ResourceStatuses response is served from cache
+const resourceStatusCacheTTL = 5 * time.Second
+
+// resourceStatusFillTimeout bounds fetching the live statuses that fill the cache
+const resourceStatusFillTimeout = 30 * time.Second
+
+// resourceStatusCache holds the last ResourceStatuses result of each cluster. The zero value is an empty cache.
+type resourceStatusCache struct {
+	mu       sync.Mutex
+	clusters map[string]resourceStatusEntry
+}
+
+type resourceStatusEntry struct {
+	fetchedAt time.Time
+	statuses  map[string]ResourceStatus
+}
+
+// ResourceStatuses reports the live Kubernetes status of every managed resource
+func (h *Handler) ResourceStatuses(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	h.resourceStatuses.mu.Lock()
+	defer h.resourceStatuses.mu.Unlock()
+
+	cluster := clusterNameFor(r)
+	entry, ok := h.resourceStatuses.clusters[cluster]
+	if !ok || time.Since(entry.fetchedAt) >= resourceStatusCacheTTL {
+		// The fill is shared with the requests waiting on the lock, so a client that goes
+		// away does not cut it short
+		fillCtx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), resourceStatusFillTimeout)
+		defer cancel()
+
+		statuses := make(map[string]ResourceStatus)
+		interrupted := false
+		for _, key := range rec.ManagedKeys(fillCtx) {
+			status, err := h.fetchResourceStatus(fillCtx, rec, key)
+			statuses[key] = status
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				interrupted = true
+			}
+		}
+		entry = resourceStatusEntry{fetchedAt: time.Now(), statuses: statuses}
+		// A fill cut short says nothing about the resources, so it is not served to others
+		if !interrupted && fillCtx.Err() == nil {
+			if h.resourceStatuses.clusters == nil {
+				h.resourceStatuses.clusters = make(map[string]resourceStatusEntry)
+			}
+			h.resourceStatuses.clusters[cluster] = entry
+		}
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, entry.statuses)
+}
+
+// ResourceStatusByKey reports the live Kubernetes status of a single resource
+func (h *Handler) ResourceStatusByKey(w http.ResponseWriter, r *http.Request) {
+	rec := h.reconcilerFor(r)
+	if rec == nil {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconciler_unavailable", "Reconciler not available", nil)
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", chi.URLParam(r, "namespace"), chi.URLParam(r, "kind"), chi.URLParam(r, "name"))
+	if err := ValidateKey(key); err != nil {
+		WriteError(w, h.logger, err)
+		return
+	}
+
+	WriteJSONResponse(w, h.logger, http.StatusOK, h.resourceStatus(r.Context(), rec, key))
+}
+
+// resourceStatus fetches the live object for key from rec and summarizes its status,
+// including the failure backoff of the resource
+func (h *Handler) resourceStatus(ctx context.Context, rec reconciler.Reconciler, key string) ResourceStatus {
+	status, _ := h.fetchResourceStatus(ctx, rec, key)
+	return status
+}
+
+// fetchResourceStatus is resourceStatus that also returns the error fetching the live object,
+// which the status only carries as a message
+func (h *Handler) fetchResourceStatus(ctx context.Context, rec reconciler.Reconciler, key string) (ResourceStatus, error) {
+	status, err := h.liveResourceStatus(ctx, rec, key)
+	if until, ok := rec.BackoffUntil(key); ok {
+		status.BackoffUntil = &until
+	}
+	return status, err
+}
+
+func (h *Handler) liveResourceStatus(ctx context.Context, rec reconciler.Reconciler, key string) (ResourceStatus, error) {
+	live, err := rec.GetLiveObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return ResourceStatus{}, nil
+		}
+		h.logger.Error(err, "failed to get live object", "key", key)
+		return ResourceStatus{Error: err.Error()}, err
+	}
+	return resourceStatusFromObject(live), nil
+}
+
+// resourceStatusFromObject derives a ResourceStatus from .metadata.generation and .status.
+// An object is ready when a Ready or Available condition is True; without those conditions
+// it is ready when .status.readyReplicas reaches .spec.replicas, and otherwise when it exists.
+func resourceStatusFromObject(obj *unstructured.Unstructured) ResourceStatus {
+	status := ResourceStatus{
+		Exists:     true,
+		Generation: obj.GetGeneration(),
+	}
+	status.ObservedGeneration, _, _ = unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	readyCondition := ""
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		extracted := ResourceCondition{
+			Type:    stringField(condition, "type"),
+			Status:  stringField(condition, "status"),
+			Reason:  stringField(condition, "reason"),
+			Message: stringField(condition, "message"),
+		}
+		status.Conditions = append(status.Conditions, extracted)
+		if (extracted.Type == "Ready" || extracted.Type == "Available") && readyCondition != "True" {
+			readyCondition = extracted.Status
+		}
+	}
+
+	switch {
+	case readyCondition != "":
+		status.Ready = readyCondition == "True"
+	default:
+		readyReplicas, hasReadyReplicas, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		replicas, hasReplicas, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !hasReplicas {
+			replicas = 1
+		}
+		if hasReadyReplicas || hasReplicas {
+			status.Ready = readyReplicas >= replicas
+		} else {
+			status.Ready = true
+		}
+	}
+
+	return status
+}
+
+func stringField(m map[string]interface{}, field string) string {
+	value, _ := m[field].(string)
+	return value
+}
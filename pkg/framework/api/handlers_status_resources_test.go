@@ -0,0 +1,304 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func setupResourceStatusHandler(t *testing.T) (*Handler, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	rec, dynamicClient := setupTestReconcilerWithDynamicClient(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	liveDeployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default", "generation": int64(3)},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(2),
+			"readyReplicas":      int64(2),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable", "message": "Deployment does not have minimum availability."},
+				map[string]interface{}{"type": "Progressing", "status": "True", "reason": "ReplicaSetUpdated"},
+			},
+		},
+	}}
+	deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	if _, err := dynamicClient.Resource(deploymentGVR).Namespace("default").Create(context.Background(), liveDeployment, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create live Deployment: %v", err)
+	}
+
+	// Applies succeed without touching the tracker so only the objects created above exist
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	deployment := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: default\nspec:\n  replicas: 2\n"
+	manifests := map[string][]byte{
+		"default/Deployment/web": []byte(deployment),
+		"default/Service/web":    []byte(createTestManifest("Service", "web", "default")),
+	}
+	if _, err := rec.DeployManifests(context.Background(), manifests); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+
+	return handler, dynamicClient
+}
+
+func TestResourceStatuses(t *testing.T) {
+	handler, _ := setupResourceStatusHandler(t)
+
+	req := httptest.NewRequest("GET", "/api/status/resources", nil)
+	w := httptest.NewRecorder()
+
+	handler.SetupRoutes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ResourceStatuses() status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var statuses map[string]ResourceStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("ResourceStatuses() response is not valid JSON: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("ResourceStatuses() returned %d resources, want 2: %v", len(statuses), statuses)
+	}
+
+	if missing := statuses["default/Service/web"]; missing.Exists || missing.Ready {
+		t.Errorf("ResourceStatuses() missing Service = %+v, want exists=false ready=false", missing)
+	}
+
+	web := statuses["default/Deployment/web"]
+	if !web.Exists {
+		t.Fatalf("ResourceStatuses() Deployment exists = false, want true")
+	}
+	if web.Ready {
+		t.Errorf("ResourceStatuses() Deployment ready = true, want false from the Available condition")
+	}
+	if web.Generation != 3 || web.ObservedGeneration != 2 {
+		t.Errorf("ResourceStatuses() Deployment generations = %d/%d, want 3/2", web.Generation, web.ObservedGeneration)
+	}
+	wantConditions := []ResourceCondition{
+		{Type: "Available", Status: "False", Reason: "MinimumReplicasUnavailable", Message: "Deployment does not have minimum availability."},
+		{Type: "Progressing", Status: "True", Reason: "ReplicaSetUpdated"},
+	}
+	if len(web.Conditions) != len(wantConditions) {
+		t.Fatalf("ResourceStatuses() Deployment conditions = %+v, want %+v", web.Conditions, wantConditions)
+	}
+	for i, want := range wantConditions {
+		if web.Conditions[i] != want {
+			t.Errorf("ResourceStatuses() condition %d = %+v, want %+v", i, web.Conditions[i], want)
+		}
+	}
+}
+
+func TestResourceStatuses_Cached(t *testing.T) {
+	handler, dynamicClient := setupResourceStatusHandler(t)
+	router := handler.SetupRoutes()
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/status/resources", nil))
+	gets := 0
+	for _, action := range dynamicClient.Actions() {
+		if action.GetVerb() == "get" {
+			gets++
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/status/resources", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ResourceStatuses() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	getsAfter := 0
+	for _, action := range dynamicClient.Actions() {
+		if action.GetVerb() == "get" {
+			getsAfter++
+		}
+	}
+	if getsAfter != gets {
+		t.Errorf("second ResourceStatuses() issued %d get calls, want 0 while cached", getsAfter-gets)
+	}
+}
+
+func TestResourceStatuses_InterruptedFillNotCached(t *testing.T) {
+	handler, dynamicClient := setupResourceStatusHandler(t)
+	router := handler.SetupRoutes()
+
+	timedOut := true
+	dynamicClient.PrependReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if timedOut {
+			return true, nil, fmt.Errorf("client rate limiter: %w", context.DeadlineExceeded)
+		}
+		return false, nil, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/status/resources", nil))
+	var statuses map[string]ResourceStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("ResourceStatuses() response is not valid JSON: %v", err)
+	}
+	if statuses["default/Deployment/web"].Error == "" {
+		t.Fatalf("ResourceStatuses() = %v, want the timeout reported", statuses)
+	}
+
+	timedOut = false
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/status/resources", nil))
+	statuses = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("ResourceStatuses() response is not valid JSON: %v", err)
+	}
+	if web := statuses["default/Deployment/web"]; web.Error != "" || !web.Exists {
+		t.Errorf("ResourceStatuses() web = %+v, want a fresh status instead of the cached timeout", web)
+	}
+}
+
+func TestResourceStatusByKey(t *testing.T) {
+	handler, _ := setupResourceStatusHandler(t)
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantExists bool
+	}{
+		{name: "existing resource", path: "/api/status/resources/default/Deployment/web", wantExists: true},
+		{name: "missing resource", path: "/api/status/resources/default/Service/web", wantExists: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("ResourceStatusByKey() status code = %v, want %v", w.Code, http.StatusOK)
+			}
+			var status ResourceStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("ResourceStatusByKey() response is not valid JSON: %v", err)
+			}
+			if status.Exists != tt.wantExists {
+				t.Errorf("ResourceStatusByKey() exists = %v, want %v", status.Exists, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestResourceStatusByKey_Backoff(t *testing.T) {
+	handler, _ := setupResourceStatusHandler(t)
+	broken := map[string][]byte{"default/ConfigMap/broken": []byte("invalid: yaml: content")}
+	if _, err := handler.reconciler.DeployManifests(context.Background(), broken); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name        string
+		path        string
+		wantBackoff bool
+	}{
+		{name: "failing resource", path: "/api/status/resources/default/ConfigMap/broken", wantBackoff: true},
+		{name: "applied resource", path: "/api/status/resources/default/Deployment/web", wantBackoff: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			var status ResourceStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("ResourceStatusByKey() response is not valid JSON: %v", err)
+			}
+			if (status.BackoffUntil != nil) != tt.wantBackoff {
+				t.Errorf("ResourceStatusByKey() backoff_until = %v, want set = %v", status.BackoffUntil, tt.wantBackoff)
+			}
+			if status.BackoffUntil != nil && !status.BackoffUntil.After(time.Now()) {
+				t.Errorf("ResourceStatusByKey() backoff_until = %v, want a time in the future", status.BackoffUntil)
+			}
+		})
+	}
+}
+
+func TestResourceStatuses_NoReconciler(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ResourceStatuses(w, httptest.NewRequest("GET", "/api/status/resources", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("ResourceStatuses() status code = %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestResourceStatusFromObject_ReadyReplicas(t *testing.T) {
+	tests := []struct {
+		name   string
+		object map[string]interface{}
+		want   bool
+	}{
+		{
+			name: "all replicas ready",
+			object: map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"readyReplicas": int64(3)},
+			},
+			want: true,
+		},
+		{
+			name: "replicas not ready",
+			object: map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"readyReplicas": int64(1)},
+			},
+			want: false,
+		},
+		{
+			name: "ready condition wins",
+			object: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{
+					"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:   "no readiness signal",
+			object: map[string]interface{}{"data": map[string]interface{}{"key": "value"}},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := resourceStatusFromObject(&unstructured.Unstructured{Object: tt.object})
+			if status.Ready != tt.want {
+				t.Errorf("resourceStatusFromObject() ready = %v, want %v", status.Ready, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// TenantHeader selects the tenant whose manifests a manifest request reads and writes
+const TenantHeader = "X-Tenant-ID"
+
+type tenantContextKey struct{}
+
+// selectedTenant is the tenant a request selected with the X-Tenant-ID header
+type selectedTenant struct {
+	id    string
+	store store.ManifestStore
+}
+
+// tenantStoreCache keeps one manifest store per tenant, so parent records are loaded once
+type tenantStoreCache struct {
+	mu     sync.Mutex
+	stores map[string]store.ManifestStore
+}
+
+func (c *tenantStoreCache) get(tenantID string, newStore func(string) store.ManifestStore) store.ManifestStore {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.stores[tenantID]; ok {
+		return s
+	}
+	if c.stores == nil {
+		c.stores = make(map[string]store.ManifestStore)
+	}
+	s := newStore(tenantID)
+	c.stores[tenantID] = s
+	return s
+}
+
+// SetTenantStores lets manifest requests select a tenant with the X-Tenant-ID header.
+// newStore returns the manifest store of a tenant, typically store.NewTenantManifestStore
+// over the handler store's database and index. The header is only honoured when
+// authentication is enabled and the caller's token lists the tenant, see
+// AuthConfig.OIDCTenantsClaim and AuthConfig.TokenTenants. Tenant manifests are read-only:
+// this instance only reconciles its own store, so writes to another tenant would never be
+// deployed. They are made by the instance whose TenantID is that tenant.
+func (h *Handler) SetTenantStores(newStore func(tenantID string) store.ManifestStore) {
+	h.newTenantStore = newStore
+}
+
+// tenantMiddleware resolves the X-Tenant-ID header to the manifest store of that tenant.
+// Requests without the header use the handler's store. Callers not authorized for the
+// tenant are rejected with 403, as are requests that would write to it.
+func (h *Handler) tenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID := r.Header.Get(TenantHeader)
+		if tenantID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !h.auth.Enabled {
+			WriteErrorResponse(w, h.logger, http.StatusForbidden, "tenant_requires_auth", "The "+TenantHeader+" header requires authentication to be enabled", nil)
+			return
+		}
+		if h.newTenantStore == nil {
+			WriteErrorResponse(w, h.logger, http.StatusBadRequest, "tenants_not_supported", "Tenant selection is not configured", nil)
+			return
+		}
+		if err := store.ValidateTenantID(tenantID); err != nil {
+			WriteError(w, h.logger, err)
+			return
+		}
+		if !authIdentityFrom(r).allowsTenant(tenantID) {
+			WriteErrorResponse(w, h.logger, http.StatusForbidden, "tenant_forbidden", "The token is not authorized for tenant "+tenantID, nil)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			WriteErrorResponse(w, h.logger, http.StatusForbidden, "tenant_read_only", "Manifests of tenant "+tenantID+" are read-only here; write them through the instance serving that tenant", nil)
+			return
+		}
+
+		tenant := selectedTenant{id: tenantID, store: h.tenantStores.get(tenantID, h.newTenantStore)}
+		ctx := context.WithValue(r.Context(), tenantContextKey{}, tenant)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// storeFor returns the manifest store of the tenant selected by r, defaulting to the handler's
+func (h *Handler) storeFor(r *http.Request) store.ManifestStore {
+	if tenant, ok := r.Context().Value(tenantContextKey{}).(selectedTenant); ok {
+		return tenant.store
+	}
+	return h.store
+}
+
+// tenantSelected reports whether r selected a tenant store with the X-Tenant-ID header
+func tenantSelected(r *http.Request) bool {
+	_, ok := r.Context().Value(tenantContextKey{}).(selectedTenant)
+	return ok
+}
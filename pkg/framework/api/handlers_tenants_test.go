@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+const tenantTestToken = "tenant-token"
+
+// newTenantTestHandler returns a handler with token authentication whose tenant stores share
+// the database and index of its own store
+func newTenantTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	idx := index.NewIndex()
+	handler.store = store.NewManifestStore(db, idx, logr.Discard())
+	handler.SetTenantStores(func(tenantID string) store.ManifestStore {
+		return store.NewTenantManifestStore(db, idx, logr.Discard(), tenantID)
+	})
+	handler.SetAuth(AuthConfig{
+		Enabled:      true,
+		Tokens:       []string{tenantTestToken},
+		TokenTenants: map[string][]string{tenantTestToken: {"team-a", "team-b"}},
+	})
+	return handler
+}
+
+func tenantRequest(method, target, tenantID, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tenantTestToken)
+	if tenantID != "" {
+		req.Header.Set(TenantHeader, tenantID)
+	}
+	return req
+}
+
+func TestTenantManifests_Isolated(t *testing.T) {
+	handler := newTenantTestHandler(t)
+	router := handler.SetupRoutes()
+
+	teamA := handler.newTenantStore("team-a")
+	if err := teamA.Create("default/Service/api", []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: api\n  namespace: default\n")); err != nil {
+		t.Fatalf("Create() for team-a error = %v", err)
+	}
+
+	tests := []struct {
+		tenantID string
+		want     int
+	}{
+		{tenantID: "team-a", want: http.StatusOK},
+		{tenantID: "team-b", want: http.StatusNotFound},
+		{tenantID: "", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tenantRequest("GET", "/manifests/default/Service/api", tt.tenantID, ""))
+		if w.Code != tt.want {
+			t.Errorf("GetManifest() for tenant %q status = %d, want %d", tt.tenantID, w.Code, tt.want)
+		}
+	}
+
+	if _, ok := handler.store.Get("default/Service/api"); ok {
+		t.Error("team-a manifest is visible in the handler's store")
+	}
+}
+
+func TestTenantManifests_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenantID string
+		noAuth   bool
+		want     int
+	}{
+		{name: "invalid tenant", tenantID: "Team_A", want: http.StatusBadRequest},
+		{name: "reserved tenant", tenantID: "events", want: http.StatusBadRequest},
+		{name: "authentication disabled", tenantID: "team-a", noAuth: true, want: http.StatusForbidden},
+		{name: "tenant not granted to the token", tenantID: "team-c", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTenantTestHandler(t)
+			if tt.noAuth {
+				handler.SetAuth(AuthConfig{})
+			}
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, tenantRequest("GET", "/api/manifests", tt.tenantID, ""))
+			if w.Code != tt.want {
+				t.Errorf("ListManifests() status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTenantManifests_ReadOnly(t *testing.T) {
+	handler := newTenantTestHandler(t)
+	router := handler.SetupRoutes()
+
+	body := `{"key": "default/Service/api", "value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\n  namespace: default\n"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, tenantRequest("POST", "/manifests", "team-a", body))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("CreateManifest() for team-a status = %d, want %d: %s", w.Code, http.StatusForbidden, w.Body.String())
+	}
+	if _, ok := handler.newTenantStore("team-a").Get("default/Service/api"); ok {
+		t.Error("CreateManifest() wrote a manifest no reconciler would deploy")
+	}
+}
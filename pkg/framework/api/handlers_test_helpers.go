@@ -102,6 +102,12 @@ func WithTestEventStore(eventStore events.EventStorage) testHandlerOption {
 	}
 }
 
+func WithTestParameterClient(client *crd.Client) testHandlerOption {
+	return func(cfg *testHandlerConfig) {
+		cfg.parameterClient = client
+	}
+}
+
 func WithNilReconciler() testHandlerOption {
 	return func(cfg *testHandlerConfig) {
 		cfg.reconciler = nil
@@ -1,6 +1,59 @@
 package api
 
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+)
+
 // This file contains parameter-specific test helpers.
-// Currently, parameters tests use the generic helpers from handlers_test_helpers.go.
-// Add parameter-specific helpers here as needed.
+// Other parameters tests use the generic helpers from handlers_test_helpers.go.
+
+// newTestParameterClientWithNamespaces returns a parameter client whose fake cluster
+// contains the given namespaces
+func newTestParameterClientWithNamespaces(t *testing.T, namespaces ...string) *crd.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core/v1 to scheme: %v", err)
+	}
+	objects := make([]runtime.Object, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme, objects...)
+	return crd.NewClient(dynamicClient, logr.Discard(), "conductor.io", "v1alpha1", "deploymentparameters")
+}
 
+// newTestParameterClientWithSchema returns a parameter client whose fake cluster has the
+// DeploymentParameters CRD installed with specSchema as the schema of its spec
+func newTestParameterClientWithSchema(t *testing.T, specSchema map[string]interface{}) *crd.Client {
+	t.Helper()
+	crdObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "deploymentparameters.conductor.io"},
+		"spec": map[string]interface{}{
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name": "v1alpha1",
+					"schema": map[string]interface{}{
+						"openAPIV3Schema": map[string]interface{}{
+							"type":       "object",
+							"properties": map[string]interface{}{"spec": specSchema},
+						},
+					},
+				},
+			},
+		},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), crdObj)
+	return crd.NewClient(dynamicClient, logr.Discard(), "conductor.io", "v1alpha1", "deploymentparameters")
+}
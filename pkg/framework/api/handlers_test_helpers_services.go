@@ -20,6 +20,13 @@ import (
 
 // setupTestReconciler creates a test reconciler for services tests
 func setupTestReconciler(t *testing.T, ready bool) reconciler.Reconciler {
+	t.Helper()
+	rec, _ := setupTestReconcilerWithDynamicClient(t, ready)
+	return rec
+}
+
+// setupTestReconcilerWithDynamicClient creates a test reconciler and returns its fake dynamic client
+func setupTestReconcilerWithDynamicClient(t *testing.T, ready bool) (reconciler.Reconciler, *dynamicfake.FakeDynamicClient) {
 	t.Helper()
 	logger := logr.Discard()
 	clientset := kubefake.NewSimpleClientset()
@@ -34,6 +41,7 @@ func setupTestReconciler(t *testing.T, ready bool) reconciler.Reconciler {
 	if err != nil {
 		t.Fatalf("failed to create test DB: %v", err)
 	}
+	t.Cleanup(func() { testDB.Close() })
 	idx := index.NewIndex()
 	manifestStore := store.NewManifestStore(testDB, idx, logger)
 	eventStore := events.NewStorage(testDB, logger)
@@ -43,7 +51,7 @@ func setupTestReconciler(t *testing.T, ready bool) reconciler.Reconciler {
 		t.Fatalf("failed to create reconciler: %v", err)
 	}
 	rec.SetReady(ready)
-	return rec
+	return rec, dynamicClient
 }
 
 // setupTestHandlerWithReconciler creates a test handler with a reconciler for services tests
@@ -61,7 +61,7 @@ func (h *Handler) DeploymentsPage(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Get CRD instance values with fallback
-	instanceSpec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace)
+	instanceSpec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace, getQueryNamespace(r) == "")
 	if err != nil || instanceSpec == nil {
 		instanceSpec = make(map[string]interface{})
 	}
@@ -72,7 +72,7 @@ func (h *Handler) DeploymentsPage(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Get service values for current values display
-	serviceValues := h.getServiceValuesMap(ctx, services, detectedNamespace, instanceName)
+	serviceValues := h.getServiceValuesMap(ctx, services, detectedNamespace, instanceName, getOverlayName(r))
 	
 	// Convert schema and instance to JSON for JavaScript library
 	var specSchemaJSON, instanceSpecJSON string
@@ -122,7 +122,7 @@ func (h *Handler) ParametersPage(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Get instance values with fallback
-	instanceSpec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace)
+	instanceSpec, err := h.getSpecWithFallback(ctx, instanceName, detectedNamespace, getQueryNamespace(r) == "")
 	if err != nil || instanceSpec == nil {
 		instanceSpec = make(map[string]interface{})
 	}
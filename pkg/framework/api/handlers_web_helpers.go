@@ -57,7 +57,9 @@ func (h *Handler) getServiceNames() []string {
 // getServiceValuesMap returns merged/default and deployed values for all services
 // Similar to GetServiceValues but returns a map instead of writing HTTP response
 // Optimized to fetch service values in parallel for better performance
-func (h *Handler) getServiceValuesMap(ctx context.Context, services []string, defaultNamespace string, instanceName string) map[string]map[string]interface{} {
+// A non-empty overlay is merged on top of the spec before global and service values are merged;
+// an overlay the spec does not define is ignored.
+func (h *Handler) getServiceValuesMap(ctx context.Context, services []string, defaultNamespace string, instanceName string, overlay string) map[string]map[string]interface{} {
 	result := make(map[string]map[string]interface{})
 	if len(services) == 0 {
 		return result
@@ -73,6 +75,13 @@ func (h *Handler) getServiceValuesMap(ctx context.Context, services []string, de
 	if specErr != nil || spec == nil {
 		spec = nil
 	}
+	if spec != nil {
+		if withOverlay, err := specWithOverlay(spec, overlay); err == nil {
+			spec = withOverlay
+		} else {
+			h.logger.Info("ignoring parameter overlay", "overlay", overlay, "error", err)
+		}
+	}
 
 	// Use a mutex to protect the result map
 	var mu sync.Mutex
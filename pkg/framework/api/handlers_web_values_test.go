@@ -42,7 +42,7 @@ func TestGetServiceValuesMap(t *testing.T) {
 	}
 
 	services := []string{"service1", "service2"}
-	result := handler.getServiceValuesMap(ctx, services, "default", "default")
+	result := handler.getServiceValuesMap(ctx, services, "default", "default", "")
 
 	// Check service1 - should have merged values (global + service-specific)
 	if service1Data, ok := result["service1"]; !ok {
@@ -101,7 +101,7 @@ func TestGetServiceValuesMap_EmptySpec(t *testing.T) {
 
 	ctx := context.Background()
 	services := []string{"service1"}
-	result := handler.getServiceValuesMap(ctx, services, "default", "default")
+	result := handler.getServiceValuesMap(ctx, services, "default", "default", "")
 
 	// Should return empty merged values when no CRD spec exists
 	if service1Data, ok := result["service1"]; !ok {
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 signature of a trigger payload as sha256=<hex>,
+// the format GitHub sends
+const WebhookSignatureHeader = "X-Hub-Signature-256"
+
+// ReconcileAllKey is sent on the reconcile channel to reconcile every stored manifest at once
+const ReconcileAllKey = "*"
+
+// WebhookTriggerConfig configures POST /api/webhooks/trigger, which CI/CD systems call on push
+type WebhookTriggerConfig struct {
+	// Secret signs trigger payloads; the endpoint rejects every request while it is empty
+	Secret string
+	// AllowedBranches, when set, limits the branches whose pushes trigger a deployment
+	AllowedBranches []string
+}
+
+// pushEvent is the part of a GitHub push event payload the trigger reads
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// SetWebhookTrigger enables POST /api/webhooks/trigger with cfg.
+// It must be called before SetupRoutes.
+func (h *Handler) SetWebhookTrigger(cfg WebhookTriggerConfig) {
+	h.webhookTrigger = cfg
+}
+
+// TriggerWebhook verifies the signature of a push event and queues every manifest for
+// reconciliation. Pushes to branches outside AllowedBranches, and of tags, are acknowledged
+// without triggering anything so the sender does not retry them.
+func (h *Handler) TriggerWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhookTrigger.Secret == "" {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "webhook_trigger_disabled", "Webhook trigger is not configured", nil)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: failed to read payload: %w", apperrors.ErrInvalidRequest, err))
+		return
+	}
+	if !validWebhookSignature(h.webhookTrigger.Secret, body, r.Header.Get(WebhookSignatureHeader)) {
+		WriteErrorResponse(w, h.logger, http.StatusUnauthorized, "invalid_signature", "Missing or invalid "+WebhookSignatureHeader+" signature", nil)
+		return
+	}
+
+	var push pushEvent
+	if err := json.Unmarshal(body, &push); err != nil {
+		WriteError(w, h.logger, fmt.Errorf("%w: invalid push event payload: %w", apperrors.ErrInvalidRequest, err))
+		return
+	}
+	branch, ok := strings.CutPrefix(push.Ref, "refs/heads/")
+	if !ok {
+		WriteJSONResponse(w, h.logger, http.StatusOK, WebhookTriggerResponse{Reason: "not a branch push"})
+		return
+	}
+	if !h.branchAllowed(branch) {
+		WriteJSONResponse(w, h.logger, http.StatusOK, WebhookTriggerResponse{Branch: branch, Reason: "branch not allowed"})
+		return
+	}
+
+	// One reconcile of the whole store, so no manifest is lost to a full reconcile queue
+	if !h.queueReconcile(ReconcileAllKey) {
+		WriteErrorResponse(w, h.logger, http.StatusServiceUnavailable, "reconcile_queue_full", "Reconcile queue is full, retry the delivery later", nil)
+		return
+	}
+	count := h.store.Count()
+
+	h.logger.Info("deployment triggered by webhook", "repository", push.Repository.FullName, "branch", branch, "commit", push.After, "manifests", count)
+	events.StoreEventSafeContext(r.Context(), h.eventStore, h.logger, events.DeploymentTriggered(push.Repository.FullName, branch, push.After))
+	WriteJSONResponse(w, h.logger, http.StatusAccepted, WebhookTriggerResponse{Triggered: true, Branch: branch, Commit: push.After, Queued: count})
+}
+
+func (h *Handler) branchAllowed(branch string) bool {
+	if len(h.webhookTrigger.AllowedBranches) == 0 {
+		return true
+	}
+	for _, allowed := range h.webhookTrigger.AllowedBranches {
+		if allowed == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// validWebhookSignature reports whether signature is sha256=<hex> of the HMAC-SHA256 of body keyed with secret
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	encoded, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testWebhookSecret = "s3cret"
+
+func signWebhookPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookTriggerHandler(t *testing.T, allowedBranches ...string) *Handler {
+	t.Helper()
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetWebhookTrigger(WebhookTriggerConfig{Secret: testWebhookSecret, AllowedBranches: allowedBranches})
+	for _, name := range []string{"api", "worker"} {
+		if err := handler.store.Create("default/Service/"+name, []byte(createTestManifest("Service", name, "default"))); err != nil {
+			t.Fatalf("failed to create test manifest: %v", err)
+		}
+	}
+	return handler
+}
+
+func sendWebhookTrigger(handler *Handler, payload, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/webhooks/trigger", strings.NewReader(payload))
+	if signature != "" {
+		req.Header.Set(WebhookSignatureHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestTriggerWebhook_QueuesReconcile(t *testing.T) {
+	handler := newWebhookTriggerHandler(t)
+	payload := `{"ref":"refs/heads/main","after":"abc123","repository":{"full_name":"org/app"}}`
+
+	w := sendWebhookTrigger(handler, payload, signWebhookPayload(testWebhookSecret, payload))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("TriggerWebhook() status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+
+	var resp WebhookTriggerResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("TriggerWebhook() response is not valid JSON: %v", err)
+	}
+	if !resp.Triggered || resp.Branch != "main" || resp.Commit != "abc123" || resp.Queued != 2 {
+		t.Errorf("TriggerWebhook() response = %+v, want triggered for main at abc123 with 2 queued", resp)
+	}
+
+	if len(handler.reconcileCh) != 1 {
+		t.Fatalf("reconcile channel holds %d keys, want a single full reconcile", len(handler.reconcileCh))
+	}
+	if key := <-handler.reconcileCh; key != ReconcileAllKey {
+		t.Errorf("queued key = %q, want %q", key, ReconcileAllKey)
+	}
+}
+
+func TestTriggerWebhook_QueueFull(t *testing.T) {
+	handler := newWebhookTriggerHandler(t)
+	for len(handler.reconcileCh) < cap(handler.reconcileCh) {
+		handler.reconcileCh <- "default/Service/api"
+	}
+	payload := `{"ref":"refs/heads/main","after":"abc123"}`
+
+	w := sendWebhookTrigger(handler, payload, signWebhookPayload(testWebhookSecret, payload))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("TriggerWebhook() with a full queue status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestTriggerWebhook_InvalidSignature(t *testing.T) {
+	handler := newWebhookTriggerHandler(t)
+	payload := `{"ref":"refs/heads/main"}`
+
+	for name, signature := range map[string]string{
+		"missing":      "",
+		"wrong secret": signWebhookPayload("other", payload),
+		"malformed":    "sha256=zz",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := sendWebhookTrigger(handler, payload, signature)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("TriggerWebhook() status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+	if len(handler.reconcileCh) != 0 {
+		t.Errorf("reconcile channel holds %d keys after rejected triggers, want 0", len(handler.reconcileCh))
+	}
+}
+
+func TestTriggerWebhook_BranchNotAllowed(t *testing.T) {
+	handler := newWebhookTriggerHandler(t, "main", "release")
+	payload := `{"ref":"refs/heads/feature","after":"def456"}`
+
+	w := sendWebhookTrigger(handler, payload, signWebhookPayload(testWebhookSecret, payload))
+	if w.Code != http.StatusOK {
+		t.Fatalf("TriggerWebhook() status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp WebhookTriggerResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("TriggerWebhook() response is not valid JSON: %v", err)
+	}
+	if resp.Triggered || resp.Branch != "feature" {
+		t.Errorf("TriggerWebhook() response = %+v, want an untriggered feature push", resp)
+	}
+	if len(handler.reconcileCh) != 0 {
+		t.Errorf("reconcile channel holds %d keys, want 0", len(handler.reconcileCh))
+	}
+}
+
+func TestTriggerWebhook_Disabled(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	payload := `{"ref":"refs/heads/main"}`
+
+	w := sendWebhookTrigger(handler, payload, signWebhookPayload("", payload))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("TriggerWebhook() status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestTriggerWebhook_BypassesBearerAuth(t *testing.T) {
+	handler := newWebhookTriggerHandler(t)
+	handler.SetAuth(AuthConfig{Enabled: true, Tokens: []string{"token"}})
+	payload := `{"ref":"refs/heads/main"}`
+
+	w := sendWebhookTrigger(handler, payload, signWebhookPayload(testWebhookSecret, payload))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("TriggerWebhook() status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+}
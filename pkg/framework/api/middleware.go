@@ -1,22 +1,516 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	"github.com/garunski/conductor-framework/pkg/framework/events"
 )
 
-func CORSMiddleware(next http.Handler) http.Handler {
+// RequestIDHeader carries the correlation ID of an API request
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the X-Request-ID values accepted from clients
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware reads X-Request-ID from the request, or generates a UUID when it is
+// missing, stores it in the request context for events and echoes it on the response. The ID
+// is also stored where middleware.GetReqID finds it, so the access log line includes it.
+func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Max-Age", "3600")
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.New().String()
+		}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusNoContent)
-			return
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(events.WithRequestID(ctx, requestID)))
+	})
+}
+
+// MaxBodyMiddleware limits request bodies to maxBytes with http.MaxBytesReader. A request whose
+// Content-Length already exceeds the limit is rejected with 413 before it reaches next; a handler
+// that reads past the limit gets an *http.MaxBytesError, which WriteError reports as 413 as well.
+// A maxBytes of zero or less disables the limit.
+func MaxBodyMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxBytes <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				WriteError(w, logr.Discard(), &http.MaxBytesError{Limit: maxBytes})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORSMiddleware sets CORS headers for requests whose Origin matches allowedOrigins.
+// A "*" entry allows every origin, and entries like "https://*.example.com" allow any
+// subdomain. Requests from other origins get no CORS headers.
+func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowAll := false
+	for _, pattern := range allowedOrigins {
+		if pattern == "*" {
+			allowAll = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			switch {
+			case allowAll:
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			case origin != "" && matchOrigin(origin, allowedOrigins):
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			default:
+				if r.Method == "OPTIONS" {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, X-Request-ID, X-Tenant-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified, X-Request-ID")
+			w.Header().Set("Access-Control-Max-Age", "3600")
+
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchOrigin reports whether origin matches one of patterns. A pattern is "*", an exact
+// origin such as "https://app.example.com", or a wildcard subdomain origin such as
+// "https://*.example.com", which matches subdomains but not the bare domain.
+func matchOrigin(origin string, patterns []string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+
+		prefix, suffix, ok := strings.Cut(pattern, "*.")
+		if !ok || !strings.HasPrefix(origin, prefix) {
+			continue
+		}
+		host := strings.TrimPrefix(origin, prefix)
+		subdomain, found := strings.CutSuffix(host, "."+suffix)
+		if found && subdomain != "" && !strings.ContainsAny(subdomain, "/:") {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditMiddleware records every non-GET request to the event store's audit bucket, except
+// those rejected with 401 or 429. The action is the HTTP method followed by the matched route pattern, e.g. "POST /api/up".
+func AuditMiddleware(eventStore events.EventStorage, logger logr.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if eventStore == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			identity := &authIdentity{}
+			r = r.WithContext(context.WithValue(r.Context(), authIdentityKey{}, identity))
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			// Rejected credentials and throttled requests changed nothing and would let any
+			// client fill the audit log
+			if status == http.StatusUnauthorized || status == http.StatusTooManyRequests {
+				return
+			}
+
+			entry := events.AuditEntry{
+				Timestamp:  start,
+				User:       auditUser(r, identity),
+				IP:         clientIP(r),
+				Action:     r.Method + " " + routePattern(r),
+				Resource:   r.URL.Path,
+				StatusCode: status,
+			}
+			if err := eventStore.StoreAuditEntry(entry); err != nil {
+				logger.V(1).Info("failed to store audit entry", "error", err, "action", entry.Action)
+			}
+		})
+	}
+}
+
+// auditUser returns the subject AuthMiddleware verified or else the caller identity that
+// AuthMiddleware accepted from a trusted authenticating proxy, if any. Headers the client
+// could set itself are never used.
+func auditUser(r *http.Request, identity *authIdentity) string {
+	if identity.subject != "" {
+		return identity.subject
+	}
+	return identity.proxyUser
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
 		}
+	}
+	return r.URL.Path
+}
+
+// RateLimitConfig configures a per-client-IP token bucket.
+// A zero RequestsPerSecond disables limiting.
+type RateLimitConfig struct {
+	RequestsPerSecond float64
+	BurstSize         int
+}
+
+// rateLimiterIdleTTL is how long the limiter of a client that sends no requests is kept.
+// Limiters are kept at least until their bucket would have refilled, so evicting one never
+// grants a client more requests than it would have had.
+const rateLimiterIdleTTL = 10 * time.Minute
+
+// RateLimiter enforces a RateLimitConfig independently for each client IP
+type RateLimiter struct {
+	cfg    RateLimitConfig
+	logger logr.Logger
+	now    func() time.Time
 
+	mu        sync.Mutex
+	limiters  map[string]*clientLimiter // client IP -> limiter
+	lastSweep time.Time
+}
+
+// clientLimiter is the token bucket of one client IP
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewRateLimiter(cfg RateLimitConfig, logger logr.Logger) *RateLimiter {
+	return &RateLimiter{cfg: cfg, logger: logger, now: time.Now, limiters: make(map[string]*clientLimiter)}
+}
+
+// burst returns the configured burst size, at least 1
+func (l *RateLimiter) burst() int {
+	if l.cfg.BurstSize <= 0 {
+		return 1
+	}
+	return l.cfg.BurstSize
+}
+
+// idleTTL returns how long an idle client's limiter is kept: rateLimiterIdleTTL, or the time
+// its bucket takes to refill when that is longer
+func (l *RateLimiter) idleTTL() time.Duration {
+	refill := time.Duration(float64(l.burst()) / l.cfg.RequestsPerSecond * float64(time.Second))
+	if refill > rateLimiterIdleTTL {
+		return refill
+	}
+	return rateLimiterIdleTTL
+}
+
+// sweep deletes the limiters of clients idle for longer than idleTTL, at most once per idleTTL.
+// l.mu must be held.
+func (l *RateLimiter) sweep(now time.Time) {
+	ttl := l.idleTTL()
+	if now.Sub(l.lastSweep) < ttl {
+		return
+	}
+	l.lastSweep = now
+	for ip, client := range l.limiters {
+		if now.Sub(client.lastSeen) > ttl {
+			delete(l.limiters, ip)
+		}
+	}
+}
+
+// Enabled reports whether the limiter restricts any requests
+func (l *RateLimiter) Enabled() bool {
+	return l != nil && l.cfg.RequestsPerSecond > 0
+}
+
+// Allow reports whether a request from ip is within its limit
+func (l *RateLimiter) Allow(ip string) bool {
+	if !l.Enabled() {
+		return true
+	}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	client, ok := l.limiters[ip]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(l.cfg.RequestsPerSecond), l.burst())}
+		l.limiters[ip] = client
+	}
+	client.lastSeen = now
+	return client.limiter.AllowN(now, 1)
+}
+
+// Reset forgets every client's limiter so all clients start with a full burst
+func (l *RateLimiter) Reset() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limiters = make(map[string]*clientLimiter)
+}
+
+// clients returns the number of client IPs that have a limiter
+func (l *RateLimiter) clients() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.limiters)
+}
+
+// Middleware responds 429 to clients that exceed the limit
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow(clientIP(r)) {
+			WriteErrorResponse(w, l.logger, http.StatusTooManyRequests, "rate_limited", "Too many requests, please retry later", nil)
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// RateLimitMiddleware limits each client IP according to cfg
+func RateLimitMiddleware(cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return NewRateLimiter(cfg, logr.Discard()).Middleware
+}
+
+// AuthConfig configures bearer token authentication for the HTTP API
+type AuthConfig struct {
+	Enabled bool
+	// Tokens are static bearer tokens accepted as-is
+	Tokens []string
+	// OIDCIssuerURL, when set, also accepts JWTs signed by this issuer's published keys
+	OIDCIssuerURL string
+	// OIDCAudience is required with OIDCIssuerURL; JWTs whose aud claim does not include it are rejected
+	OIDCAudience string
+	// OIDCTenantsClaim names the JWT claim listing the tenants a token may select with the
+	// X-Tenant-ID header; empty uses DefaultOIDCTenantsClaim
+	OIDCTenantsClaim string
+	// TokenTenants lists the tenants each static token may select with the X-Tenant-ID header
+	TokenTenants map[string][]string
+	// TrustedProxies are the IP addresses and CIDR ranges of authenticating proxies whose
+	// RemoteUserHeader names the caller in the audit log; the header is ignored from anyone else
+	TrustedProxies []string
+}
+
+// RemoteUserHeader carries the caller an authenticating proxy in AuthConfig.TrustedProxies
+// signed in
+const RemoteUserHeader = "X-Remote-User"
+
+// ParseTrustedProxies parses AuthConfig.TrustedProxies; a plain IP address matches only itself
+func ParseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 8 * net.IPv6len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: not an IP address or CIDR range", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// fromTrustedProxy reports whether r was sent by one of proxies
+func fromTrustedProxy(r *http.Request, proxies []*net.IPNet) bool {
+	ip := net.ParseIP(clientIP(r))
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultOIDCTenantsClaim is the JWT claim listing the tenants a token may select
+const DefaultOIDCTenantsClaim = "tenants"
+
+// authExemptPaths are served without authentication so probes keep working
+var authExemptPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	// Trigger requests are authenticated by their HMAC signature instead
+	"/api/webhooks/trigger": true,
+}
+
+// authExemptPrefix is the path prefix of the static UI assets, which hold no data and which
+// browsers load without the Authorization header. The UI pages and the API they call still
+// require a token, so browsers reach the UI through an authenticating proxy that adds it.
+const authExemptPrefix = "/static/"
+
+// authIdentityKey is the request context key of the *authIdentity AuditMiddleware records
+type authIdentityKey struct{}
+
+// authIdentity is the caller identity AuthMiddleware verified for a request
+type authIdentity struct {
+	// verified is set once AuthMiddleware accepted the caller's token
+	verified bool
+	subject  string
+	// proxyUser is the caller named by a trusted proxy's RemoteUserHeader; it is recorded
+	// in the audit log but grants nothing
+	proxyUser string
+	// tenants are the tenants the caller may select with the X-Tenant-ID header
+	tenants []string
+}
+
+// allowsTenant reports whether the caller may select tenantID
+func (i *authIdentity) allowsTenant(tenantID string) bool {
+	return i != nil && jwtStrings(i.tenants).contains(tenantID)
+}
+
+// withAuthIdentity returns r carrying the verified subject and tenants of its caller, filling
+// in the identity AuditMiddleware placed in the context when there is one
+func withAuthIdentity(r *http.Request, subject string, tenants []string) *http.Request {
+	if identity, ok := r.Context().Value(authIdentityKey{}).(*authIdentity); ok {
+		identity.verified = true
+		identity.subject = subject
+		identity.tenants = tenants
+		return r
+	}
+	identity := &authIdentity{verified: true, subject: subject, tenants: tenants}
+	return r.WithContext(context.WithValue(r.Context(), authIdentityKey{}, identity))
+}
+
+// recordProxyUser notes the caller named by a trusted proxy in the identity AuditMiddleware
+// placed in the context, if any
+func recordProxyUser(r *http.Request, user string) {
+	if identity, ok := r.Context().Value(authIdentityKey{}).(*authIdentity); ok {
+		identity.proxyUser = user
+	}
+}
+
+// staticTokenSubject identifies a static token in the audit log without revealing it
+func staticTokenSubject(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// authIdentityFrom returns the identity AuthMiddleware verified for r, or nil
+func authIdentityFrom(r *http.Request) *authIdentity {
+	identity, _ := r.Context().Value(authIdentityKey{}).(*authIdentity)
+	if identity == nil || !identity.verified {
+		return nil
+	}
+	return identity
+}
+
+// AuthMiddleware requires an "Authorization: Bearer <token>" header matching one of the
+// configured static tokens or, with an OIDC issuer, a valid JWT from that issuer.
+// Other requests are rejected with 401.
+func AuthMiddleware(cfg AuthConfig, logger logr.Logger) func(http.Handler) http.Handler {
+	var verifier *oidcVerifier
+	if cfg.OIDCIssuerURL != "" {
+		verifier = newOIDCVerifier(cfg.OIDCIssuerURL, cfg.OIDCAudience, nil)
+	}
+	tenantsClaim := cfg.OIDCTenantsClaim
+	if tenantsClaim == "" {
+		tenantsClaim = DefaultOIDCTenantsClaim
+	}
+	trustedProxies, err := ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		logger.Error(err, "ignoring trusted proxies")
+		trustedProxies = nil
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user := r.Header.Get(RemoteUserHeader); user != "" && fromTrustedProxy(r, trustedProxies) {
+				recordProxyUser(r, user)
+			}
+			if !cfg.Enabled || authExemptPaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, authExemptPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if ok && validStaticToken(cfg.Tokens, token) {
+				next.ServeHTTP(w, withAuthIdentity(r, staticTokenSubject(token), cfg.TokenTenants[token]))
+				return
+			}
+			if ok && verifier != nil {
+				claims, err := verifier.Verify(r.Context(), token)
+				if err == nil {
+					next.ServeHTTP(w, withAuthIdentity(r, claims.Subject, claims.stringsClaim(tenantsClaim)))
+					return
+				}
+				logger.V(1).Info("rejected bearer token", "error", err, "path", r.URL.Path)
+			}
+
+			WriteErrorResponse(w, logger, http.StatusUnauthorized, "unauthorized", "", nil)
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
+
+// validStaticToken compares token against every configured token in constant time
+func validStaticToken(tokens []string, token string) bool {
+	valid := 0
+	for _, candidate := range tokens {
+		valid |= subtle.ConstantTimeCompare([]byte(candidate), []byte(token))
+	}
+	return valid == 1
+}
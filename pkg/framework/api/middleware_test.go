@@ -0,0 +1,534 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+func rateLimitRequest(t *testing.T, handler http.Handler, method, path, ip string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware_BurstExhaustion(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimitMiddleware(RateLimitConfig{RequestsPerSecond: 0.001, BurstSize: 2})(next)
+
+	for i := 0; i < 2; i++ {
+		if w := rateLimitRequest(t, handler, "GET", "/", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d status code = %v, want %v", i, w.Code, http.StatusOK)
+		}
+	}
+
+	w := rateLimitRequest(t, handler, "GET", "/", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request after burst status code = %v, want %v", w.Code, http.StatusTooManyRequests)
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("429 response is not valid JSON: %v", err)
+	}
+	if errResp.Error != "rate_limited" {
+		t.Errorf("error = %v, want %v", errResp.Error, "rate_limited")
+	}
+}
+
+func TestRateLimitMiddleware_IndependentIPs(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimitMiddleware(RateLimitConfig{RequestsPerSecond: 0.001, BurstSize: 1})(next)
+
+	if w := rateLimitRequest(t, handler, "GET", "/", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Fatalf("first IP status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w := rateLimitRequest(t, handler, "GET", "/", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("first IP second request status code = %v, want %v", w.Code, http.StatusTooManyRequests)
+	}
+	if w := rateLimitRequest(t, handler, "GET", "/", "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("second IP status code = %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandler_RateLimitsWriteEndpointsAndReset(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetRateLimits(
+		RateLimitConfig{RequestsPerSecond: 0.001, BurstSize: 5},
+		RateLimitConfig{RequestsPerSecond: 0.001, BurstSize: 1},
+	)
+	handler.SetAuth(AuthConfig{Enabled: true, Tokens: []string{"secret-token"}})
+	router := handler.SetupRoutes()
+
+	// Without a token the write is rejected by AuthMiddleware, after it counted against the limit
+	if w := rateLimitRequest(t, router, "POST", "/api/up", "10.0.0.1"); w.Code == http.StatusTooManyRequests {
+		t.Fatalf("first write status code = %v, want it allowed", w.Code)
+	}
+	if w := rateLimitRequest(t, router, "POST", "/api/down", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second write status code = %v, want %v", w.Code, http.StatusTooManyRequests)
+	}
+
+	// Reads use their own, more lenient limiter
+	if w := rateLimitRequest(t, router, "GET", "/api/events", "10.0.0.1"); w.Code == http.StatusTooManyRequests {
+		t.Fatalf("read status code = %v, want it allowed", w.Code)
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/ratelimit/reset", nil)
+	req.RemoteAddr = "10.0.0.2:12345"
+	req.Header.Set("Authorization", "Bearer secret-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("reset status code = %v, want %v: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w := rateLimitRequest(t, router, "POST", "/api/up", "10.0.0.1"); w.Code == http.StatusTooManyRequests {
+		t.Errorf("write after reset status code = %v, want it allowed", w.Code)
+	}
+}
+
+func TestHandler_ResetRateLimitsRequiresAuthentication(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetRateLimits(RateLimitConfig{}, RateLimitConfig{})
+
+	w := rateLimitRequest(t, handler.SetupRoutes(), "DELETE", "/api/ratelimit/reset", "10.0.0.1")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("reset without authentication status code = %v, want %v", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestHandler_RateLimitExemptsProbes(t *testing.T) {
+	handler, err := newTestHandler(t, WithNilReconciler())
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetRateLimits(RateLimitConfig{RequestsPerSecond: 0.001, BurstSize: 1}, RateLimitConfig{})
+	router := handler.SetupRoutes()
+
+	for _, path := range []string{"/healthz", "/healthz", "/readyz", "/readyz"} {
+		if w := rateLimitRequest(t, router, "GET", path, "10.0.0.1"); w.Code == http.StatusTooManyRequests {
+			t.Errorf("GET %s status code = %v, want probes never rate limited", path, w.Code)
+		}
+	}
+}
+
+func TestRateLimiter_EvictsIdleClients(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 1, BurstSize: 1}, logr.Discard())
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("10.0.0.1")
+	limiter.Allow("10.0.0.2")
+	if got := limiter.clients(); got != 2 {
+		t.Fatalf("clients() = %d, want 2", got)
+	}
+
+	// 10.0.0.2 stays active while 10.0.0.1 goes idle past the TTL
+	now = now.Add(rateLimiterIdleTTL / 2)
+	limiter.Allow("10.0.0.2")
+	now = now.Add(rateLimiterIdleTTL/2 + time.Second)
+	limiter.Allow("10.0.0.2")
+	if got := limiter.clients(); got != 1 {
+		t.Errorf("clients() after the idle TTL = %d, want only the active client", got)
+	}
+}
+
+func TestRateLimiter_KeepsClientsUntilTheirBucketRefills(t *testing.T) {
+	// One request per hour: a client idle for the TTL has not earned its request back yet
+	limiter := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 1.0 / 3600, BurstSize: 1}, logr.Discard())
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	if !limiter.Allow("10.0.0.1") {
+		t.Fatal("first request was not allowed")
+	}
+	now = now.Add(rateLimiterIdleTTL + time.Second)
+	if limiter.Allow("10.0.0.1") {
+		t.Error("request after the idle TTL was allowed before the bucket refilled")
+	}
+}
+
+func authRequest(handler http.Handler, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthMiddleware(AuthConfig{Enabled: true, Tokens: []string{"first", "secret-token"}}, logr.Discard())(next)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{"valid static token", "/api/services", "Bearer secret-token", http.StatusOK},
+		{"invalid token", "/api/services", "Bearer wrong", http.StatusUnauthorized},
+		{"missing header", "/api/services", "", http.StatusUnauthorized},
+		{"non-bearer scheme", "/api/services", "Basic c2VjcmV0LXRva2Vu", http.StatusUnauthorized},
+		{"healthz exempt", "/healthz", "", http.StatusOK},
+		{"readyz exempt", "/readyz", "", http.StatusOK},
+		{"static assets exempt", "/static/app.js", "", http.StatusOK},
+		{"UI page", "/deployments", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := authRequest(handler, tt.path, tt.authorization)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				if body := strings.TrimSpace(w.Body.String()); body != `{"error":"unauthorized"}` {
+					t.Errorf("body = %s, want %s", body, `{"error":"unauthorized"}`)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthMiddleware(AuthConfig{Tokens: []string{"secret-token"}}, logr.Discard())(next)
+
+	if w := authRequest(handler, "/api/services", ""); w.Code != http.StatusOK {
+		t.Errorf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var contextID string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contextID = events.RequestIDFromContext(r.Context())
+	}))
+
+	t.Run("echoes header", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/services", nil)
+		req.Header.Set(RequestIDHeader, "req-123")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if got := w.Header().Get(RequestIDHeader); got != "req-123" {
+			t.Errorf("response %s = %q, want %q", RequestIDHeader, got, "req-123")
+		}
+		if contextID != "req-123" {
+			t.Errorf("context request ID = %q, want %q", contextID, "req-123")
+		}
+	})
+
+	t.Run("generates when missing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/services", nil))
+
+		got := w.Header().Get(RequestIDHeader)
+		if _, err := uuid.Parse(got); err != nil {
+			t.Errorf("response %s = %q, want a UUID: %v", RequestIDHeader, got, err)
+		}
+		if contextID != got {
+			t.Errorf("context request ID = %q, want %q", contextID, got)
+		}
+	})
+}
+
+func TestRequestIDMiddleware_AccessLog(t *testing.T) {
+	var logged bytes.Buffer
+	previous := middleware.DefaultLogger
+	middleware.DefaultLogger = middleware.RequestLogger(&middleware.DefaultLogFormatter{Logger: log.New(&logged, "", 0), NoColor: true})
+	defer func() { middleware.DefaultLogger = previous }()
+
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	req.Header.Set(RequestIDHeader, "probe-7")
+	handler.SetupRoutes().ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(logged.String(), "[probe-7]") {
+		t.Errorf("access log = %q, want the request ID", logged.String())
+	}
+}
+
+func TestRequestIDMiddleware_StampsEvents(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	eventStore := events.NewStorage(db, logr.Discard())
+	rec := setupTestReconciler(t, true)
+	handler, err := newTestHandler(t, WithTestReconciler(rec), WithTestEventStore(eventStore))
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/up", nil)
+	req.Header.Set(RequestIDHeader, "deploy-42")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Up() status code = %v, want %v, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	stored, err := eventStore.ListEvents(events.EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(stored) == 0 {
+		t.Fatal("ListEvents() returned no events")
+	}
+	for _, event := range stored {
+		if event.RequestID != "deploy-42" {
+			t.Errorf("event %q request ID = %q, want %q", event.Message, event.RequestID, "deploy-42")
+		}
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   string
+		patterns []string
+		want     bool
+	}{
+		{name: "exact match", origin: "https://app.example.com", patterns: []string{"https://app.example.com"}, want: true},
+		{name: "exact match ignores case", origin: "https://App.Example.com", patterns: []string{"https://app.example.com"}, want: true},
+		{name: "wildcard subdomain", origin: "https://app.example.com", patterns: []string{"https://*.example.com"}, want: true},
+		{name: "wildcard nested subdomain", origin: "https://a.b.example.com", patterns: []string{"https://*.example.com"}, want: true},
+		{name: "wildcard excludes bare domain", origin: "https://example.com", patterns: []string{"https://*.example.com"}, want: false},
+		{name: "wildcard checks scheme", origin: "http://app.example.com", patterns: []string{"https://*.example.com"}, want: false},
+		{name: "wildcard checks port", origin: "https://app.example.com:8443", patterns: []string{"https://*.example.com"}, want: false},
+		{name: "wildcard rejects lookalike domain", origin: "https://app.evilexample.com", patterns: []string{"https://*.example.com"}, want: false},
+		{name: "star matches anything", origin: "https://anything.test", patterns: []string{"*"}, want: true},
+		{name: "no match", origin: "https://evil.test", patterns: []string{"https://app.example.com"}, want: false},
+		{name: "no patterns", origin: "https://app.example.com", patterns: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchOrigin(tt.origin, tt.patterns); got != tt.want {
+				t.Errorf("matchOrigin(%q, %v) = %v, want %v", tt.origin, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func corsRequest(t *testing.T, origins []string, method, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetCORSAllowedOrigins(origins)
+
+	req := httptest.NewRequest(method, "/healthz", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_AllowedOrigins(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.internal.example.com"}
+
+	tests := []struct {
+		name       string
+		origins    []string
+		origin     string
+		wantOrigin string
+	}{
+		{name: "exact match echoes origin", origins: allowed, origin: "https://app.example.com", wantOrigin: "https://app.example.com"},
+		{name: "wildcard subdomain echoes origin", origins: allowed, origin: "https://ui.internal.example.com", wantOrigin: "https://ui.internal.example.com"},
+		{name: "star keeps wildcard", origins: []string{"*"}, origin: "https://anything.test", wantOrigin: "*"},
+		{name: "unlisted origin gets no headers", origins: allowed, origin: "https://evil.test", wantOrigin: ""},
+		{name: "missing origin gets no headers", origins: allowed, origin: "", wantOrigin: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := corsRequest(t, tt.origins, http.MethodGet, tt.origin)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.wantOrigin == "" && w.Header().Get("Access-Control-Allow-Methods") != "" {
+				t.Error("Access-Control-Allow-Methods set for a disallowed origin")
+			}
+			if tt.wantOrigin != "" && tt.wantOrigin != "*" && w.Header().Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_PreflightAllowedOrigin(t *testing.T) {
+	w := corsRequest(t, []string{"https://*.example.com"}, http.MethodOptions, "https://app.example.com")
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, "https://app.example.com")
+	}
+	if w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Error("Access-Control-Allow-Methods is missing")
+	}
+}
+
+func TestCORSMiddleware_PreflightConditionalHeaders(t *testing.T) {
+	w := corsRequest(t, []string{"https://app.example.com"}, http.MethodOptions, "https://app.example.com")
+
+	allowed := w.Header().Get("Access-Control-Allow-Headers")
+	for _, header := range []string{"Authorization", "If-Match", "If-None-Match", "X-Request-ID", "X-Tenant-ID"} {
+		if !strings.Contains(allowed, header) {
+			t.Errorf("Access-Control-Allow-Headers = %q, missing %s", allowed, header)
+		}
+	}
+	exposed := w.Header().Get("Access-Control-Expose-Headers")
+	for _, header := range []string{"ETag", "Last-Modified", "X-Request-ID"} {
+		if !strings.Contains(exposed, header) {
+			t.Errorf("Access-Control-Expose-Headers = %q, missing %s", exposed, header)
+		}
+	}
+}
+
+func TestMaxBodyMiddleware(t *testing.T) {
+	const limit = 64
+	handler := MaxBodyMiddleware(limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			WriteError(w, logr.Discard(), err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name string
+		size int
+		want int
+	}{
+		{"under the limit", limit - 1, http.StatusOK},
+		{"at the limit", limit, http.StatusOK},
+		{"over the limit", limit + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		for _, knownLength := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s/content-length=%v", tt.name, knownLength), func(t *testing.T) {
+				req := httptest.NewRequest("POST", "/api/manifests", strings.NewReader(strings.Repeat("x", tt.size)))
+				if !knownLength {
+					// Chunked bodies are only caught while the handler reads them
+					req.ContentLength = -1
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+
+				if w.Code != tt.want {
+					t.Fatalf("status = %d, want %d", w.Code, tt.want)
+				}
+				if tt.want != http.StatusRequestEntityTooLarge {
+					return
+				}
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("413 response is not an ErrorResponse: %v", err)
+				}
+				if resp.Error != "request_too_large" {
+					t.Errorf("error = %q, want request_too_large", resp.Error)
+				}
+			})
+		}
+	}
+}
+
+func TestHandler_BulkBodyLimit(t *testing.T) {
+	handler, err := newTestHandler(t)
+	if err != nil {
+		t.Fatalf("newTestHandler() error = %v", err)
+	}
+	handler.SetBodyLimits(1024, 4096)
+	router := handler.SetupRoutes()
+
+	body := `{"manifests": [], "padding": "` + strings.Repeat("x", 2048) + `"}`
+	post := func(path string) int {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := post("/api/manifests/bulk"); code == http.StatusRequestEntityTooLarge {
+		t.Error("bulk endpoint rejected a body under the bulk limit")
+	}
+	if code := post("/manifests"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("CreateManifest() status = %d, want %d", code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16", "fd00::1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		remoteAddr string
+		want       bool
+	}{
+		{remoteAddr: "10.0.0.1:443", want: true},
+		{remoteAddr: "10.0.0.2:443", want: false},
+		{remoteAddr: "192.168.4.7:443", want: true},
+		{remoteAddr: "[fd00::1]:443", want: true},
+		{remoteAddr: "[fd00::2]:443", want: false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if got := fromTrustedProxy(req, proxies); got != tt.want {
+			t.Errorf("fromTrustedProxy(%s) = %v, want %v", tt.remoteAddr, got, tt.want)
+		}
+	}
+
+	if _, err := ParseTrustedProxies([]string{"proxy.internal"}); err == nil {
+		t.Error("ParseTrustedProxies() error = nil for a host name, want an error")
+	}
+}
@@ -5,15 +5,26 @@ import (
 	"net/http"
 
 	"github.com/go-logr/logr"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 )
 
 type ErrorResponse struct {
-	Error   string            `json:"error"`
-	Message string            `json:"message,omitempty"`
-	Details map[string]string `json:"details,omitempty"`
+	Error   string                 `json:"error"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// requestLogger adds the request ID that RequestIDMiddleware set on the response to logger
+func requestLogger(w http.ResponseWriter, logger logr.Logger) logr.Logger {
+	if requestID := w.Header().Get(RequestIDHeader); requestID != "" {
+		return logger.WithValues("request_id", requestID)
+	}
+	return logger
 }
 
 func WriteJSONResponse(w http.ResponseWriter, logger logr.Logger, status int, data interface{}) {
+	logger = requestLogger(w, logger)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
@@ -21,7 +32,7 @@ func WriteJSONResponse(w http.ResponseWriter, logger logr.Logger, status int, da
 	}
 }
 
-func WriteErrorResponse(w http.ResponseWriter, logger logr.Logger, status int, errorMsg string, message string, details map[string]string) {
+func WriteErrorResponse(w http.ResponseWriter, logger logr.Logger, status int, errorMsg string, message string, details map[string]interface{}) {
 	resp := ErrorResponse{
 		Error:   errorMsg,
 		Message: message,
@@ -40,10 +51,11 @@ func WriteError(w http.ResponseWriter, logger logr.Logger, err error) {
 	status := httpStatus(err)
 	message := err.Error()
 
-	WriteErrorResponse(w, logger, status, code, message, nil)
+	WriteErrorResponse(w, logger, status, code, message, apperrors.Details(err))
 }
 
 func WriteYAMLResponse(w http.ResponseWriter, logger logr.Logger, data []byte) {
+	logger = requestLogger(w, logger)
 	w.Header().Set("Content-Type", "application/yaml")
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(data); err != nil {
@@ -10,10 +10,15 @@ import (
 func (h *Handler) SetupRoutes() *chi.Mux {
 	r := chi.NewRouter()
 
+	r.Use(RequestIDMiddleware)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.RequestID)
-	r.Use(CORSMiddleware)
+	r.Use(CORSMiddleware(h.corsAllowedOrigins))
+	// Throttled requests are rejected before they are audited, so a flood costs no writes
+	r.Use(h.rateLimitMiddleware)
+	r.Use(AuditMiddleware(h.eventStore, h.logger))
+	r.Use(h.bodyLimitMiddleware)
+	r.Use(AuthMiddleware(h.auth, h.logger))
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Timeout(30 * time.Second))
@@ -27,33 +32,77 @@ func (h *Handler) SetupRoutes() *chi.Mux {
 		r.Use(middleware.Timeout(10 * time.Second))
 		r.Get("/healthz", h.Healthz)
 		r.Get("/readyz", h.Readyz)
+		r.Get("/metrics", h.Metrics)
 	})
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Timeout(60 * time.Second))
+		r.Use(h.clusterMiddleware)
 		r.Post("/api/up", h.Up)
 		r.Post("/api/down", h.Down)
 		r.Post("/api/update", h.Update)
+		r.Post("/api/rollback", h.Rollback)
+		r.Post("/api/webhooks/trigger", h.TriggerWebhook)
+		r.Post("/api/canary/promote/{service}", h.PromoteCanary)
+		r.Post("/api/canary/finalize/{service}", h.FinalizeCanary)
+		r.Post("/api/canary/rollback/{service}", h.RollbackCanary)
 	})
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Timeout(5 * time.Second))
-		r.Get("/api/services", h.ListServices)
+		r.With(h.clusterMiddleware).Get("/api/services", h.ListServices)
 		r.Get("/api/services/health", h.Status)
+		r.Get("/api/services/topology", h.ServiceTopology)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(30 * time.Second))
+		r.Use(h.clusterMiddleware)
+		r.Get("/api/status/resources", h.ResourceStatuses)
+		r.Get("/api/status/resources/{namespace}/{kind}/{name}", h.ResourceStatusByKey)
+		r.Get("/api/canary/{service}", h.GetCanary)
 	})
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Timeout(10 * time.Second))
+		r.Use(h.clusterMiddleware)
 		r.Get("/api/cluster/requirements", h.ClusterRequirements)
+		r.Get("/api/cluster/capacity", h.ClusterCapacity)
+		r.Get("/api/cluster/namespaces", h.ListNamespaces)
+		r.Get("/api/cluster/namespaces/{name}", h.GetNamespace)
+		r.Get("/api/clusters", h.ListClusters)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(10 * time.Second))
+		r.Use(h.clusterMiddleware)
+		r.Post("/api/reconciler/pause", h.PauseReconciler)
+		r.Post("/api/reconciler/resume", h.ResumeReconciler)
+		r.Get("/api/reconciler/interval", h.GetReconcileInterval)
+		r.Put("/api/reconciler/interval", h.SetReconcileInterval)
+		r.Get("/api/reconciler/managed", h.GetManagedResourceStatus)
 	})
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Timeout(30 * time.Second))
+		r.Use(h.clusterMiddleware)
 		r.Get("/api/service/{namespace}/{name}", h.ServiceDetails)
+		r.Get("/api/services/{namespace}/{service}/probe", h.ProbeService)
+		r.Get("/api/services/{namespace}/{service}/events", h.ServiceEvents)
+		r.Get("/api/services/{namespace}/{service}/replicas", h.GetServiceReplicas)
+		r.Put("/api/services/{namespace}/{service}/replicas", h.ScaleService)
+	})
+
+	r.Group(func(r chi.Router) {
+		// Compacting a large database can take a while
+		r.Use(middleware.Timeout(10 * time.Minute))
+		r.Post("/api/admin/gc", h.RunDatabaseGC)
+		r.Get("/api/admin/db/stats", h.DatabaseStats)
 	})
 
 	r.Route("/manifests", func(r chi.Router) {
 		r.Use(middleware.Timeout(30 * time.Second))
+		r.Use(h.tenantMiddleware)
 		r.Get("/", h.ListManifests)
 		r.Post("/", h.CreateManifest)
 		r.Get("/*", h.GetManifest)
@@ -61,23 +110,65 @@ func (h *Handler) SetupRoutes() *chi.Mux {
 		r.Delete("/*", h.DeleteManifest)
 	})
 
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(30 * time.Second))
+		r.Use(h.tenantMiddleware)
+		r.Get("/api/manifests", h.ListManifests)
+		r.Get("/api/manifests/files", h.ListManifestFiles)
+		r.Post("/api/manifests/validate", h.ValidateManifest)
+		r.Post("/api/manifests/bulk", h.BulkCreateManifests)
+		r.Delete("/api/manifests/bulk", h.BulkDeleteManifests)
+		r.Get("/api/manifests/export", h.ExportManifests)
+		r.Post("/api/manifests/import", h.ImportManifests)
+		r.Post("/api/manifests/generate-rbac/{namespace}/{name}", h.GenerateRBAC)
+		r.With(h.clusterMiddleware).Get("/api/diff", h.Diff)
+		r.Get("/api/manifests/{namespace}/{kind}/{name}/dependencies", h.GetManifestDependencies)
+		r.Get("/api/manifests/{namespace}/{kind}/{name}/rendered", h.GetRenderedManifest)
+		r.Get("/api/manifests/{namespace}/{kind}/{name}/history", h.GetManifestHistory)
+		r.Get("/api/manifests/{namespace}/{kind}/{name}/history/{timestamp}", h.GetManifestVersion)
+		r.Post("/api/manifests/{namespace}/{kind}/{name}/history/{timestamp}/restore", h.RestoreManifestVersion)
+		r.Post("/api/manifests/*", h.RenameManifest)
+	})
+
+	// Event stream stays open for the life of the client, so it has no timeout
+	r.Get("/api/events/stream", h.StreamEvents)
+
+	// Pod log streams can follow a container indefinitely, so they have no timeout either
+	r.With(h.clusterMiddleware).Get("/api/logs/{namespace}/{pod}", h.PodLogs)
+	r.With(h.clusterMiddleware).Get("/api/logs/{namespace}/{pod}/stream", h.StreamPodLogs)
+
 	r.Route("/api/events", func(r chi.Router) {
 		r.Use(middleware.Timeout(30 * time.Second))
 		r.Get("/", h.ListEvents)
 		r.Get("/errors", h.GetRecentErrors)
 		r.Delete("/", h.CleanupEvents)
+		r.Post("/trim", h.TrimEvents)
 		r.Get("/*", h.GetEventsByResource)
 	})
 
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(30 * time.Second))
+		r.Get("/api/audit", h.GetAuditLog)
+		r.With(h.clusterMiddleware).Get("/api/rollback/versions", h.ListRollbackVersions)
+		r.Get("/api/deployments/history", h.DeploymentHistory)
+		r.Get("/api/jobs/{id}", h.GetJob)
+		r.Delete("/api/ratelimit/reset", h.ResetRateLimits)
+	})
+
 	r.Route("/api/parameters", func(r chi.Router) {
 		r.Use(middleware.Timeout(30 * time.Second))
+		r.Use(h.requireQueryNamespace)
 		r.Get("/", h.GetParameters)
 		r.Post("/", h.UpdateParameters)
+		r.Post("/merge", h.MergeParameters)
 		r.Get("/schema", h.GetParametersSchema)
+		r.Get("/form-config", h.GetParametersFormConfig)
 		r.Get("/values", h.GetServiceValues)
+		r.Get("/diff", h.DiffParameterInstances)
 		r.Get("/{service}", h.GetServiceParameters)
 		r.Get("/instances", h.ListParameterInstances)
 		r.Post("/instances", h.CreateParameterInstance)
+		r.Post("/instances/{name}/clone", h.CloneParameterInstance)
 	})
 
 	// Serve static files (JS, CSS, etc.)
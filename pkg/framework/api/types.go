@@ -1,6 +1,11 @@
 package api
 
-import "time"
+import (
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
 
 type HealthStatus struct {
 	Status     string                     `json:"status"`
@@ -11,7 +16,11 @@ type HealthStatus struct {
 
 type ComponentStatus struct {
 	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
 	Message string `json:"message,omitempty"`
+	Count   *int   `json:"count,omitempty"`
+	// LastChecked is when the component was checked
+	LastChecked time.Time `json:"lastChecked"`
 }
 
 type ServiceStatus struct {
@@ -27,6 +36,77 @@ type StatusResponse struct {
 	Services []ServiceStatus `json:"services"`
 }
 
+// ServiceProbeResponse reports the outcome of an HTTP probe of a Service's health path
+type ServiceProbeResponse struct {
+	Reachable  bool   `json:"reachable"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	URL        string `json:"url"`
+	Error      string `json:"error,omitempty"`
+}
+
+// GenerateRBACResponse lists the RBAC manifest keys GenerateRBAC stored, and those it kept
+// because they already existed
+type GenerateRBACResponse struct {
+	Keys    []string `json:"keys"`
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ListManifestsResponse is the response of ListManifests. Checksum is the SHA-256 hash of the
+// returned manifests, so polling clients can tell whether anything changed.
+type ListManifestsResponse struct {
+	Manifests map[string][]byte `json:"manifests"`
+	Checksum  string            `json:"checksum"`
+}
+
+// ManifestPageResponse is one page of ListManifests. Keys lists the keys of Items in the
+// requested sort order.
+type ManifestPageResponse struct {
+	Items      map[string]string `json:"items"`
+	Keys       []string          `json:"keys"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// RenameManifestResponse reports the keys a manifest was moved between
+type RenameManifestResponse struct {
+	OldKey string `json:"old_key"`
+	NewKey string `json:"new_key"`
+}
+
+// ManifestVersion is a previous version of a manifest, replaced at Timestamp (Unix nanoseconds)
+type ManifestVersion struct {
+	Timestamp  int64     `json:"timestamp"`
+	ReplacedAt time.Time `json:"replaced_at"`
+	Content    string    `json:"content"`
+}
+
+// ManifestHistoryResponse lists the previous versions of a manifest, newest first
+type ManifestHistoryResponse struct {
+	Key      string            `json:"key"`
+	Versions []ManifestVersion `json:"versions"`
+}
+
+// ServiceReplicas reports the replica counts of the Deployment or StatefulSet of a service
+type ServiceReplicas struct {
+	Kind      string `json:"kind"`
+	Desired   int32  `json:"desired"`
+	Ready     int32  `json:"ready"`
+	Available int32  `json:"available"`
+}
+
+// KubernetesEvent is a Kubernetes event of a pod behind a Service. Events of several pods
+// with the same type, reason and message are reported once with their counts summed.
+type KubernetesEvent struct {
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
 type EnvVar struct {
 	Name   string `json:"name"`
 	Value  string `json:"value,omitempty"`
@@ -57,12 +137,286 @@ type ClusterRequirement struct {
 	Required    bool   `json:"required"`
 }
 
+// ResourceAmounts holds CPU and memory quantities such as "1500m" and "4Gi"
+type ResourceAmounts struct {
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+}
+
+// ClusterCapacityResponse is returned by GET /api/cluster/capacity
+type ClusterCapacityResponse struct {
+	ReadyNodes int             `json:"ready_nodes"`
+	Total      ResourceAmounts `json:"total"`     // Allocatable resources of the Ready nodes
+	Available  ResourceAmounts `json:"available"` // Allocatable resources not requested by running pods
+}
+
+// CapacityErrorResponse is returned with 409 when Up would exceed the available cluster capacity
+type CapacityErrorResponse struct {
+	Error     string          `json:"error"`
+	Message   string          `json:"message"`
+	Required  ResourceAmounts `json:"required"`
+	Available ResourceAmounts `json:"available"`
+}
+
 type ClusterRequirementsResponse struct {
 	Requirements []ClusterRequirement `json:"requirements"`
 	Overall      string               `json:"overall"` // "pass", "fail", "warning"
 }
 
+// CloneParameterInstanceRequest names the instance a clone creates and the values it changes
+type CloneParameterInstanceRequest struct {
+	NewName string `json:"new_name"`
+	// Patch is deep-merged into the copied spec: nested objects are merged, other values replaced
+	Patch map[string]interface{} `json:"patch,omitempty"`
+}
+
 type DeploymentRequest struct {
 	Services []string `json:"services,omitempty"`
+	// ConfirmationToken confirms a Down that returned a DownConfirmationResponse
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// DeploymentResponse is returned by Up and Update once the manifests have been applied
+type DeploymentResponse struct {
+	Message string `json:"message"`
+	// TimedOutCount is the number of resources whose apply exceeded the deploy timeout
+	TimedOutCount int `json:"timed_out_count"`
+	// JobID identifies the readiness wait started with ?wait=, polled at GET /api/jobs/{id}
+	JobID string `json:"job_id,omitempty"`
+}
+
+// CanaryStatus describes the canary Deployment of a service
+type CanaryStatus struct {
+	Service      string          `json:"service"`
+	Namespace    string          `json:"namespace"`
+	Key          string          `json:"key"`    // Manifest key of the canary Deployment
+	Weight       int             `json:"weight"` // Percentage of the main replicas the canary was promoted with
+	Replicas     int             `json:"replicas"`
+	MainReplicas int             `json:"main_replicas"`
+	Live         *ResourceStatus `json:"live,omitempty"` // Live status of the canary Deployment
+}
+
+// WebhookTriggerResponse is returned by POST /api/webhooks/trigger
+type WebhookTriggerResponse struct {
+	Triggered bool   `json:"triggered"`
+	Branch    string `json:"branch,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	Queued    int    `json:"queued"`           // Number of manifests the queued reconcile covers
+	Reason    string `json:"reason,omitempty"` // Why a push was acknowledged without triggering
+}
+
+// EventTrimResponse is returned by POST /api/events/trim
+type EventTrimResponse struct {
+	Trimmed int `json:"trimmed"` // Number of events deleted
+}
+
+// ClusterInfo describes a cluster listed by GET /api/clusters
+type ClusterInfo struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+	Ready   bool   `json:"ready"`
+}
+
+// NamespaceSummary describes a cluster namespace listed by GET /api/cluster/namespaces
+type NamespaceSummary struct {
+	Name      string            `json:"name"`
+	Status    string            `json:"status"` // Namespace phase: "Active" or "Terminating"
+	CreatedAt time.Time         `json:"created_at"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// NamespaceDetails is returned by GET /api/cluster/namespaces/{name}
+type NamespaceDetails struct {
+	NamespaceSummary
+	Annotations    map[string]string    `json:"annotations,omitempty"`
+	ResourceQuotas []ResourceQuotaUsage `json:"resource_quotas"`
+}
+
+// ResourceQuotaUsage reports the limits of a ResourceQuota and how much of them is used
+type ResourceQuotaUsage struct {
+	Name string            `json:"name"`
+	Hard map[string]string `json:"hard"`
+	Used map[string]string `json:"used"`
+}
+
+// ReconcileIntervalRequest sets, and reports, the periodic reconciliation interval, e.g. "30s"
+type ReconcileIntervalRequest struct {
+	Interval string `json:"interval"`
+}
+
+// DBStats reports the size and contents of the BadgerDB database
+type DBStats struct {
+	LSMSizeBytes     int64 `json:"lsm_size_bytes"`
+	VLogSizeBytes    int64 `json:"vlog_size_bytes"`
+	NumKeys          int64 `json:"num_keys"`
+	NumPendingWrites int64 `json:"num_pending_writes"`
+}
+
+// GCResponse reports the outcome of POST /api/admin/gc
+type GCResponse struct {
+	DiscardRatio           float64 `json:"discard_ratio"`
+	ValueLogFilesRewritten int     `json:"value_log_files_rewritten"`
+	Duration               string  `json:"duration"`
+	Stats                  DBStats `json:"stats"`
+}
+
+// Job statuses reported by GET /api/jobs/{id}
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Job describes a background task started by an API call, such as waiting for deployed workloads
+type Job struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type TopologyNode struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+}
+
+type TopologyEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"` // "selector", "envRef", "secretRef"
+}
+
+type TopologyResponse struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+type PlannedChange struct {
+	Key    string `json:"key"`
+	Action string `json:"action"` // "create", "update", "delete"
+}
+
+type DryRunResponse struct {
+	Message        string          `json:"message"`
+	DryRun         bool            `json:"dry_run"`
+	PlannedChanges []PlannedChange `json:"planned_changes"`
+}
+
+// DownConfirmationResponse is returned by Down when deletions must be confirmed by calling it
+// again with the token before it expires
+type DownConfirmationResponse struct {
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	ResourcesToDelete []string  `json:"resources_to_delete"`
+}
+
+type RollbackResponse struct {
+	Message string `json:"message"`
+	Version int    `json:"version"`
+}
+
+type RollbackVersion struct {
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	KeyCount  int       `json:"keyCount"`
+}
+
+type RollbackVersionsResponse struct {
+	Versions []RollbackVersion `json:"versions"`
+}
+
+type ValidateManifestResponse struct {
+	Valid  bool                       `json:"valid"`
+	Errors []manifest.ValidationError `json:"errors,omitempty"`
+}
+
+type DeploymentHistoryResponse struct {
+	Deployments []events.DeploymentSession `json:"deployments"`
+	Page        int                        `json:"page"`
+	PageSize    int                        `json:"page_size"`
 }
 
+type BulkManifestEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type BulkCreateManifestsRequest struct {
+	Manifests []BulkManifestEntry `json:"manifests"`
+}
+
+type BulkDeleteManifestsRequest struct {
+	Keys []string `json:"keys"`
+}
+
+// BulkManifestError reports why one entry of a bulk request was rejected
+type BulkManifestError struct {
+	Index int    `json:"index"`
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+type BulkValidationErrorResponse struct {
+	Error   string              `json:"error"`
+	Message string              `json:"message"`
+	Errors  []BulkManifestError `json:"errors"`
+}
+
+// ImportManifestsResponse summarizes the outcome of importing a manifest archive
+type ImportManifestsResponse struct {
+	Created []string              `json:"created"`
+	Updated []string              `json:"updated"`
+	Failed  []ImportManifestError `json:"failed"`
+}
+
+// ImportManifestError reports why one file of an imported archive was not stored
+type ImportManifestError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// ResourceStatus is the live Kubernetes status of one managed resource
+type ResourceStatus struct {
+	Exists             bool                `json:"exists"`
+	Ready              bool                `json:"ready"`
+	Generation         int64               `json:"generation"`
+	ObservedGeneration int64               `json:"observed_generation"`
+	Conditions         []ResourceCondition `json:"conditions,omitempty"`
+	Error              string              `json:"error,omitempty"`
+	// BackoffUntil is when periodic reconciliation retries the resource after a failed apply
+	BackoffUntil *time.Time `json:"backoff_until,omitempty"`
+}
+
+// ResourceCondition is one entry of a resource's .status.conditions
+type ResourceCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// FormConfig is the parameters schema as grouped form controls
+type FormConfig struct {
+	Groups []FormGroup `json:"groups"`
+}
+
+// FormGroup is a named set of related form controls
+type FormGroup struct {
+	Name     string        `json:"name"`
+	Controls []FormControl `json:"controls"`
+}
+
+// FormControl describes the input for one leaf parameter, addressed by its dotted path in the spec
+type FormControl struct {
+	Path     string        `json:"path"`
+	Type     string        `json:"type"`
+	Label    string        `json:"label"`
+	Default  interface{}   `json:"default,omitempty"`
+	Required bool          `json:"required"`
+	Min      *float64      `json:"min,omitempty"`
+	Max      *float64      `json:"max,omitempty"`
+	Enum     []interface{} `json:"enum,omitempty"`
+}
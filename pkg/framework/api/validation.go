@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
@@ -15,6 +16,7 @@ import (
 var (
 	namespaceRegex    = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
 	resourceNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	yamlLineRegex     = regexp.MustCompile(`line (\d+)`)
 )
 
 func ValidateNamespace(name string) error {
@@ -54,10 +56,24 @@ func ValidateKey(key string) error {
 	return nil
 }
 
+// yamlErrorDetails reports the manifest key and, when the parser names one, the line of a YAML parse error
+func yamlErrorDetails(err error, key string) map[string]interface{} {
+	details := make(map[string]interface{})
+	if key != "" {
+		details["key"] = key
+	}
+	if match := yamlLineRegex.FindStringSubmatch(err.Error()); match != nil {
+		if line, convErr := strconv.Atoi(match[1]); convErr == nil {
+			details["line"] = line
+		}
+	}
+	return details
+}
+
 func ValidateYAML(data []byte) error {
 	var obj map[string]interface{}
 	if err := yaml.Unmarshal(data, &obj); err != nil {
-		return fmt.Errorf("%w: invalid YAML: failed to parse YAML: %w", apperrors.ErrInvalidYAML, err)
+		return apperrors.WithDetails(fmt.Errorf("%w: invalid YAML: failed to parse YAML: %w", apperrors.ErrInvalidYAML, err), yamlErrorDetails(err, ""))
 	}
 
 	if _, ok := obj["apiVersion"]; !ok {
@@ -91,15 +107,28 @@ func ParseEventQueryParams(queryParams map[string][]string) (events.EventFilters
 		filters.ResourceKey = resource
 	}
 
+	if prefix := getFirstQueryParam(queryParams, "resource_prefix"); prefix != "" {
+		filters.ResourcePrefix = prefix
+	}
+
 	if typeStr := getFirstQueryParam(queryParams, "type"); typeStr != "" {
-		eventType := events.EventType(typeStr)
-		if eventType != events.EventTypeError && eventType != events.EventTypeSuccess &&
-			eventType != events.EventTypeInfo && eventType != events.EventTypeWarning {
-			return filters, fmt.Errorf("%w: invalid event type: %s (must be one of: error, success, info, warning)", apperrors.ErrInvalid, typeStr)
+		eventType, err := parseEventType(typeStr)
+		if err != nil {
+			return filters, err
 		}
 		filters.Type = eventType
 	}
 
+	if severityStr := getFirstQueryParam(queryParams, "severity"); severityStr != "" {
+		for _, severity := range strings.Split(severityStr, ",") {
+			eventType, err := parseEventType(strings.TrimSpace(severity))
+			if err != nil {
+				return filters, err
+			}
+			filters.Severities = append(filters.Severities, eventType)
+		}
+	}
+
 	if sinceStr := getFirstQueryParam(queryParams, "since"); sinceStr != "" {
 		t, err := time.Parse(time.RFC3339, sinceStr)
 		if err != nil {
@@ -140,6 +169,15 @@ func ParseEventQueryParams(queryParams map[string][]string) (events.EventFilters
 	return filters, nil
 }
 
+func parseEventType(typeStr string) (events.EventType, error) {
+	eventType := events.EventType(typeStr)
+	if eventType != events.EventTypeError && eventType != events.EventTypeSuccess &&
+		eventType != events.EventTypeInfo && eventType != events.EventTypeWarning {
+		return "", fmt.Errorf("%w: invalid event type: %s (must be one of: error, success, info, warning)", apperrors.ErrInvalid, typeStr)
+	}
+	return eventType, nil
+}
+
 func getFirstQueryParam(queryParams map[string][]string, key string) string {
 	if values, ok := queryParams[key]; ok && len(values) > 0 {
 		return values[0]
@@ -147,3 +185,39 @@ func getFirstQueryParam(queryParams map[string][]string, key string) string {
 	return ""
 }
 
+func ParseAuditQueryParams(queryParams map[string][]string) (events.AuditFilters, error) {
+	filters := events.AuditFilters{
+		Action: getFirstQueryParam(queryParams, "action"),
+	}
+
+	if fromStr := getFirstQueryParam(queryParams, "from"); fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return filters, fmt.Errorf("%w: invalid from parameter format (use RFC3339): %w", apperrors.ErrInvalid, err)
+		}
+		filters.Since = t
+	}
+
+	if toStr := getFirstQueryParam(queryParams, "to"); toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return filters, fmt.Errorf("%w: invalid to parameter format (use RFC3339): %w", apperrors.ErrInvalid, err)
+		}
+		filters.Until = t
+	}
+
+	if limitStr := getFirstQueryParam(queryParams, "limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return filters, fmt.Errorf("%w: invalid limit parameter: must be a positive integer", apperrors.ErrInvalid)
+		}
+		if limit > 1000 {
+			return filters, fmt.Errorf("%w: limit cannot exceed 1000", apperrors.ErrInvalid)
+		}
+		filters.Limit = limit
+	} else {
+		filters.Limit = 100
+	}
+
+	return filters, nil
+}
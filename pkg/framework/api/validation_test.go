@@ -4,6 +4,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 	"github.com/garunski/conductor-framework/pkg/framework/events"
 )
 
@@ -144,6 +145,17 @@ metadata: {}`,
 	}
 }
 
+func TestValidateYAML_LineDetails(t *testing.T) {
+	err := ValidateYAML([]byte("apiVersion: v1\nkind: Service\nmetadata: [\n"))
+	if err == nil {
+		t.Fatal("ValidateYAML() expected error for invalid YAML, got nil")
+	}
+	details := apperrors.Details(err)
+	if _, ok := details["line"].(int); !ok {
+		t.Errorf("ValidateYAML() details = %v, want a line number", details)
+	}
+}
+
 func TestParseQueryParams(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -274,6 +286,16 @@ func TestParseEventQueryParams(t *testing.T) {
 			params:  map[string][]string{"resource": {string(make([]byte, 513))}},
 			wantErr: true,
 		},
+		{
+			name:    "severities",
+			params:  map[string][]string{"severity": {"error, warning"}, "resource_prefix": {"default/"}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid severity",
+			params:  map[string][]string{"severity": {"error,fatal"}},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,71 @@
+package crd
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// schemaYAML is the CustomResourceDefinition of DeploymentParameters in the default group,
+// version and resource
+//
+//go:embed schema.yaml
+var schemaYAML []byte
+
+// CRDName returns the name of the CustomResourceDefinition the client reads, such as
+// deploymentparameters.conductor.io
+func (c *Client) CRDName() string {
+	return fmt.Sprintf("%s.%s", c.resource, c.group)
+}
+
+// EnsureCRD creates the DeploymentParameters CustomResourceDefinition from the embedded
+// schema through crdClient when the cluster does not have it, and reports whether it was created
+func (c *Client) EnsureCRD(ctx context.Context, crdClient apiextensionsclientset.Interface) (bool, error) {
+	crdInterface := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+	_, err := crdInterface.Get(ctx, c.CRDName(), metav1.GetOptions{})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get CRD definition %s: %w", c.CRDName(), err)
+	}
+
+	definition, err := c.crdDefinition()
+	if err != nil {
+		return false, err
+	}
+	if _, err := crdInterface.Create(ctx, definition, metav1.CreateOptions{}); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create CRD definition %s: %w", c.CRDName(), err)
+	}
+	return true, nil
+}
+
+// crdDefinition returns the embedded CustomResourceDefinition with the group, version and
+// resource of the client
+func (c *Client) crdDefinition() (*apiextensionsv1.CustomResourceDefinition, error) {
+	definition := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.UnmarshalStrict(schemaYAML, definition); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded CRD definition: %w", err)
+	}
+	definition.Name = c.CRDName()
+
+	definition.Spec.Group = c.group
+	if definition.Spec.Names.Plural != c.resource {
+		definition.Spec.Names.Plural = c.resource
+		// The API server derives the singular name from the kind
+		definition.Spec.Names.Singular = ""
+	}
+	if len(definition.Spec.Versions) > 0 {
+		definition.Spec.Versions[0].Name = c.version
+	}
+	return definition, nil
+}
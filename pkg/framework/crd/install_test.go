@@ -0,0 +1,96 @@
+package crd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func countCreates(client *apiextensionsfake.Clientset) int {
+	creates := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" && action.GetResource().Resource == "customresourcedefinitions" {
+			creates++
+		}
+	}
+	return creates
+}
+
+func TestEnsureCRD(t *testing.T) {
+	ctx := context.Background()
+	crdClient := apiextensionsfake.NewSimpleClientset()
+	client := NewClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), logr.Discard(), "", "", "")
+
+	created, err := client.EnsureCRD(ctx, crdClient)
+	if err != nil {
+		t.Fatalf("EnsureCRD() error = %v", err)
+	}
+	if !created || countCreates(crdClient) != 1 {
+		t.Fatalf("EnsureCRD() created = %v with %d create calls, want the CRD created once", created, countCreates(crdClient))
+	}
+
+	definition, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, "deploymentparameters.conductor.io", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("CRD deploymentparameters.conductor.io was not created: %v", err)
+	}
+	if len(definition.Spec.Versions) == 0 || definition.Spec.Versions[0].Schema == nil || definition.Spec.Versions[0].Schema.OpenAPIV3Schema == nil {
+		t.Fatalf("installed CRD versions = %v, want a version with a schema", definition.Spec.Versions)
+	}
+	if _, found := definition.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["global"]; !found {
+		t.Errorf("installed CRD schema = %v, want a spec.global property", definition.Spec.Versions[0].Schema.OpenAPIV3Schema)
+	}
+
+	created, err = client.EnsureCRD(ctx, crdClient)
+	if err != nil {
+		t.Fatalf("second EnsureCRD() error = %v", err)
+	}
+	if created || countCreates(crdClient) != 1 {
+		t.Errorf("second EnsureCRD() created = %v with %d create calls, want the existing CRD kept", created, countCreates(crdClient))
+	}
+}
+
+func TestEnsureCRD_CustomResource(t *testing.T) {
+	ctx := context.Background()
+	crdClient := apiextensionsfake.NewSimpleClientset()
+	client := NewClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), logr.Discard(), "mycompany.io", "v1beta1", "appparameters")
+
+	if _, err := client.EnsureCRD(ctx, crdClient); err != nil {
+		t.Fatalf("EnsureCRD() error = %v", err)
+	}
+
+	definition, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, "appparameters.mycompany.io", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("CRD appparameters.mycompany.io was not created: %v", err)
+	}
+	if definition.Spec.Group != "mycompany.io" {
+		t.Errorf("CRD spec.group = %q, want mycompany.io", definition.Spec.Group)
+	}
+	if definition.Spec.Names.Plural != "appparameters" {
+		t.Errorf("CRD spec.names.plural = %q, want appparameters", definition.Spec.Names.Plural)
+	}
+	if len(definition.Spec.Versions) == 0 || definition.Spec.Versions[0].Name != "v1beta1" {
+		t.Errorf("CRD spec.versions = %v, want version v1beta1", definition.Spec.Versions)
+	}
+}
+
+func TestEnsureCRD_Existing(t *testing.T) {
+	ctx := context.Background()
+	existing := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploymentparameters.conductor.io"},
+	}
+	crdClient := apiextensionsfake.NewSimpleClientset(existing)
+
+	created, err := NewClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), logr.Discard(), "", "", "").EnsureCRD(ctx, crdClient)
+	if err != nil {
+		t.Fatalf("EnsureCRD() error = %v", err)
+	}
+	if created || countCreates(crdClient) != 0 {
+		t.Errorf("EnsureCRD() created = %v with %d create calls, want the existing CRD kept", created, countCreates(crdClient))
+	}
+}
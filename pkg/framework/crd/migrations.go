@@ -0,0 +1,147 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// MigrationVersionKey is the spec field that records the framework version the spec was last
+// migrated to, e.g. spec.migrationVersion: 1.4.0. Instances without it have never been migrated.
+const MigrationVersionKey = "migrationVersion"
+
+// MigrationVersion returns spec.migrationVersion, or "" when the spec has never been migrated
+func (s DeploymentParametersSpec) MigrationVersion() string {
+	version, _ := s[MigrationVersionKey].(string)
+	return version
+}
+
+// MigrationFn rewrites a spec written for one framework version into the shape expected by the
+// next. It must be idempotent: running it on an already migrated spec must not change it.
+type MigrationFn func(spec map[string]interface{}) (map[string]interface{}, error)
+
+type migration struct {
+	from string
+	to   string
+	fn   MigrationFn
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   []migration
+)
+
+// RegisterMigration registers fn to migrate specs at fromVersion to toVersion. An empty
+// fromVersion matches instances that have never been migrated. Registering a second migration
+// from the same version replaces the first.
+func RegisterMigration(fromVersion, toVersion string, fn MigrationFn) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	for i, m := range migrations {
+		if m.from == fromVersion {
+			migrations[i] = migration{from: fromVersion, to: toVersion, fn: fn}
+			return
+		}
+	}
+	migrations = append(migrations, migration{from: fromVersion, to: toVersion, fn: fn})
+}
+
+// RunMigrations migrates every DeploymentParameters instance whose spec.migrationVersion is not
+// currentVersion. Starting at the recorded version, the registered migrations are chained until
+// currentVersion is reached or none matches; the spec is then stored with migrationVersion set
+// to the version reached, so a migration registered later from that version still runs.
+// Instances migrated by a newer version than currentVersion are left alone. Without the CRD
+// installed there is nothing to migrate.
+func RunMigrations(ctx context.Context, client *Client, currentVersion string) error {
+	instances, err := client.List(ctx, "")
+	if errors.IsNotFound(err) {
+		// Nothing to migrate while the CRD is not installed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, instance := range instances {
+		from := instance.Spec.MigrationVersion()
+		if from == currentVersion {
+			continue
+		}
+		if versionNewer(from, currentVersion) {
+			client.logger.Info("Skipping DeploymentParameters migrated by a newer version", "namespace", instance.Namespace,
+				"name", instance.Name, "migrationVersion", from, "version", currentVersion)
+			continue
+		}
+
+		spec, reached, applied, err := migrateSpec(deepCopyMap(instance.Spec), currentVersion)
+		if err != nil {
+			return fmt.Errorf("failed to migrate DeploymentParameters %s/%s: %w", instance.Namespace, instance.Name, err)
+		}
+		if applied == 0 {
+			continue
+		}
+		spec[MigrationVersionKey] = reached
+		if err := client.UpdateSpec(ctx, instance.Name, instance.Namespace, spec); err != nil {
+			return fmt.Errorf("failed to store migrated DeploymentParameters %s/%s: %w", instance.Namespace, instance.Name, err)
+		}
+		client.logger.Info("Migrated DeploymentParameters", "namespace", instance.Namespace, "name", instance.Name,
+			"from", from, "to", reached, "migrations", applied)
+	}
+	return nil
+}
+
+// migrateSpec applies the chain of registered migrations that starts at the recorded version of
+// spec, stopping before a migration to a version newer than currentVersion, and returns the
+// migrated spec, the version reached and the number of migrations applied
+func migrateSpec(spec map[string]interface{}, currentVersion string) (map[string]interface{}, string, int, error) {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	version := DeploymentParametersSpec(spec).MigrationVersion()
+	applied := 0
+	// Each migration runs at most once, so a cycle in the registered versions ends
+	for applied < len(migrations) && version != currentVersion {
+		next, ok := migrationFrom(version)
+		if !ok || versionNewer(next.to, currentVersion) {
+			break
+		}
+		migrated, err := next.fn(spec)
+		if err != nil {
+			return nil, version, applied, fmt.Errorf("migration from %q to %q: %w", next.from, next.to, err)
+		}
+		if migrated == nil {
+			migrated = make(map[string]interface{})
+		}
+		spec = migrated
+		version = next.to
+		applied++
+	}
+	return spec, version, applied, nil
+}
+
+// versionNewer reports whether version is a newer semantic version than current. Versions that
+// are not semantic versions, such as "dev", are never newer.
+func versionNewer(version, current string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	c, err := semver.NewVersion(current)
+	if err != nil {
+		return false
+	}
+	return v.GreaterThan(c)
+}
+
+// migrationFrom returns the registered migration starting at version; callers hold migrationsMu
+func migrationFrom(version string) (migration, bool) {
+	for _, m := range migrations {
+		if m.from == version {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
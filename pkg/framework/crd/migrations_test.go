@@ -0,0 +1,209 @@
+package crd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// withMigrations replaces the registered migrations for the duration of a test
+func withMigrations(t *testing.T) {
+	t.Helper()
+	migrationsMu.Lock()
+	saved := migrations
+	migrations = nil
+	migrationsMu.Unlock()
+	t.Cleanup(func() {
+		migrationsMu.Lock()
+		migrations = saved
+		migrationsMu.Unlock()
+	})
+}
+
+// renameImageTag moves global.imageTag to global.image.tag
+func renameImageTag(spec map[string]interface{}) (map[string]interface{}, error) {
+	global, ok := spec["global"].(map[string]interface{})
+	if !ok {
+		return spec, nil
+	}
+	tag, ok := global["imageTag"]
+	if !ok {
+		return spec, nil
+	}
+	delete(global, "imageTag")
+	global["image"] = map[string]interface{}{"tag": tag}
+	return spec, nil
+}
+
+func newMigrationTestClient(t *testing.T, specs map[string]map[string]interface{}) *Client {
+	t.Helper()
+	gvr := schema.GroupVersionResource{Group: DefaultCRDGroup, Version: DefaultCRDVersion, Resource: DefaultCRDResource}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gvr: "DeploymentParametersList",
+	})
+	client := NewClient(dynamicClient, logr.Discard(), "", "", "")
+	for name, spec := range specs {
+		if err := client.CreateWithSpec(context.Background(), name, "default", spec); err != nil {
+			t.Fatalf("CreateWithSpec(%s) error = %v", name, err)
+		}
+	}
+	return client
+}
+
+func TestRunMigrations_RenamesField(t *testing.T) {
+	withMigrations(t)
+	RegisterMigration("1.0.0", "2.0.0", renameImageTag)
+
+	client := newMigrationTestClient(t, map[string]map[string]interface{}{
+		"old": {
+			MigrationVersionKey: "1.0.0",
+			"global":            map[string]interface{}{"namespace": "apps", "imageTag": "v1"},
+		},
+		"current": {
+			MigrationVersionKey: "2.0.0",
+			"global":            map[string]interface{}{"imageTag": "untouched"},
+		},
+	})
+
+	ctx := context.Background()
+	if err := RunMigrations(ctx, client, "2.0.0"); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	migrated, err := client.GetSpec(ctx, "old", "default")
+	if err != nil {
+		t.Fatalf("GetSpec() error = %v", err)
+	}
+	want := map[string]interface{}{
+		MigrationVersionKey: "2.0.0",
+		"global": map[string]interface{}{
+			"namespace": "apps",
+			"image":     map[string]interface{}{"tag": "v1"},
+		},
+	}
+	if !reflect.DeepEqual(migrated, want) {
+		t.Errorf("migrated spec = %v, want %v", migrated, want)
+	}
+
+	current, err := client.GetSpec(ctx, "current", "default")
+	if err != nil {
+		t.Fatalf("GetSpec() error = %v", err)
+	}
+	if current["global"].(map[string]interface{})["imageTag"] != "untouched" {
+		t.Errorf("spec already at the current version was migrated: %v", current)
+	}
+
+	// Running again leaves the migrated spec unchanged
+	if err := RunMigrations(ctx, client, "2.0.0"); err != nil {
+		t.Fatalf("second RunMigrations() error = %v", err)
+	}
+	again, _ := client.GetSpec(ctx, "old", "default")
+	if !reflect.DeepEqual(again, want) {
+		t.Errorf("spec after second run = %v, want %v", again, want)
+	}
+}
+
+func TestRunMigrations_ChainsMigrations(t *testing.T) {
+	withMigrations(t)
+	RegisterMigration("", "1.0.0", func(spec map[string]interface{}) (map[string]interface{}, error) {
+		spec["steps"] = "first"
+		return spec, nil
+	})
+	RegisterMigration("1.0.0", "2.0.0", func(spec map[string]interface{}) (map[string]interface{}, error) {
+		spec["steps"] = spec["steps"].(string) + ",second"
+		return spec, nil
+	})
+
+	client := newMigrationTestClient(t, map[string]map[string]interface{}{"default": {}})
+	ctx := context.Background()
+	if err := RunMigrations(ctx, client, "2.0.0"); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	spec, _ := client.GetSpec(ctx, "default", "default")
+	if spec["steps"] != "first,second" || DeploymentParametersSpec(spec).MigrationVersion() != "2.0.0" {
+		t.Errorf("migrated spec = %v, want both migrations applied and migrationVersion 2.0.0", spec)
+	}
+}
+
+func TestRunMigrations_FailureLeavesSpecUnchanged(t *testing.T) {
+	withMigrations(t)
+	RegisterMigration("1.0.0", "2.0.0", func(map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("cannot migrate")
+	})
+
+	client := newMigrationTestClient(t, map[string]map[string]interface{}{
+		"default": {MigrationVersionKey: "1.0.0", "global": map[string]interface{}{"imageTag": "v1"}},
+	})
+	ctx := context.Background()
+	if err := RunMigrations(ctx, client, "2.0.0"); err == nil {
+		t.Fatal("RunMigrations() with a failing migration should return an error")
+	}
+
+	spec, _ := client.GetSpec(ctx, "default", "default")
+	if DeploymentParametersSpec(spec).MigrationVersion() != "1.0.0" {
+		t.Errorf("migrationVersion = %q after a failed migration, want 1.0.0", DeploymentParametersSpec(spec).MigrationVersion())
+	}
+}
+
+func TestRunMigrations_StampsVersionReached(t *testing.T) {
+	withMigrations(t)
+	RegisterMigration("1.0.0", "1.5.0", renameImageTag)
+
+	client := newMigrationTestClient(t, map[string]map[string]interface{}{
+		"default": {MigrationVersionKey: "1.0.0", "global": map[string]interface{}{"imageTag": "v1"}},
+	})
+	ctx := context.Background()
+	if err := RunMigrations(ctx, client, "2.0.0"); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	spec, _ := client.GetSpec(ctx, "default", "default")
+	if version := DeploymentParametersSpec(spec).MigrationVersion(); version != "1.5.0" {
+		t.Fatalf("migrationVersion = %q, want the 1.5.0 reached by the chain", version)
+	}
+
+	// A migration registered later from the intermediate version still runs
+	RegisterMigration("1.5.0", "2.0.0", func(spec map[string]interface{}) (map[string]interface{}, error) {
+		spec["late"] = true
+		return spec, nil
+	})
+	if err := RunMigrations(ctx, client, "2.0.0"); err != nil {
+		t.Fatalf("second RunMigrations() error = %v", err)
+	}
+	spec, _ = client.GetSpec(ctx, "default", "default")
+	if spec["late"] != true || DeploymentParametersSpec(spec).MigrationVersion() != "2.0.0" {
+		t.Errorf("spec = %v, want the late migration applied and migrationVersion 2.0.0", spec)
+	}
+}
+
+func TestRunMigrations_SkipsNewerInstances(t *testing.T) {
+	withMigrations(t)
+	RegisterMigration("", "1.0.0", func(spec map[string]interface{}) (map[string]interface{}, error) {
+		spec["migrated"] = true
+		return spec, nil
+	})
+
+	client := newMigrationTestClient(t, map[string]map[string]interface{}{
+		"newer":     {MigrationVersionKey: "3.0.0"},
+		"unstamped": {},
+	})
+	ctx := context.Background()
+	if err := RunMigrations(ctx, client, "2.0.0"); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	newer, _ := client.GetSpec(ctx, "newer", "default")
+	if version := DeploymentParametersSpec(newer).MigrationVersion(); version != "3.0.0" || newer["migrated"] != nil {
+		t.Errorf("newer spec = %v, want it left at 3.0.0", newer)
+	}
+	unstamped, _ := client.GetSpec(ctx, "unstamped", "default")
+	if unstamped["migrated"] != true || DeploymentParametersSpec(unstamped).MigrationVersion() != "1.0.0" {
+		t.Errorf("unstamped spec = %v, want it migrated to 1.0.0", unstamped)
+	}
+}
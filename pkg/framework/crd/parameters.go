@@ -2,13 +2,16 @@ package crd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -21,6 +24,8 @@ const (
 	DefaultCRDResource = "deploymentparameters"
 	// DefaultName is the default name for the DeploymentParameters instance
 	DefaultName = "default"
+	// DefaultPollInterval is the default interval between Subscribe polls
+	DefaultPollInterval = 5 * time.Second
 )
 
 // DeploymentParametersSpec represents the spec of DeploymentParameters CRD
@@ -34,6 +39,45 @@ type DeploymentParameters struct {
 	Spec              DeploymentParametersSpec `json:"spec,omitempty"`
 }
 
+// OverlaysKey is the spec field that holds named overlays, e.g. spec.overlays.prod. Each overlay
+// is a partial DeploymentParametersSpec with global and services fields merged on top of the spec.
+const OverlaysKey = "overlays"
+
+// Overlays returns the named overlays of the spec, skipping entries that are not objects
+func (s DeploymentParametersSpec) Overlays() map[string]DeploymentParametersSpec {
+	raw, ok := s[OverlaysKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	overlays := make(map[string]DeploymentParametersSpec, len(raw))
+	for name, value := range raw {
+		if overlay, ok := value.(map[string]interface{}); ok {
+			overlays[name] = DeploymentParametersSpec(overlay)
+		}
+	}
+	return overlays
+}
+
+// NamespaceMappingKey is the spec field that maps service names to the namespace they deploy
+// to, e.g. spec.namespaceMapping.redis: cache. A mapped service ignores global.namespace.
+const NamespaceMappingKey = "namespaceMapping"
+
+// NamespaceMapping returns the target namespace of each mapped service, skipping entries that
+// are not non-empty strings
+func (s DeploymentParametersSpec) NamespaceMapping() map[string]string {
+	raw, ok := s[NamespaceMappingKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	mapping := make(map[string]string, len(raw))
+	for service, value := range raw {
+		if namespace, ok := value.(string); ok && namespace != "" {
+			mapping[service] = namespace
+		}
+	}
+	return mapping
+}
+
 // Client provides methods to interact with DeploymentParameters CRD
 type Client struct {
 	dynamicClient dynamic.Interface
@@ -42,6 +86,8 @@ type Client struct {
 	version       string
 	resource      string
 	gvr           schema.GroupVersionResource
+	// pollInterval is the Subscribe poll interval in nanoseconds, accessed atomically
+	pollInterval int64
 }
 
 // NewClient creates a new DeploymentParameters client
@@ -69,6 +115,7 @@ func NewClient(dynamicClient dynamic.Interface, logger logr.Logger, group, versi
 			Version:  version,
 			Resource: resource,
 		},
+		pollInterval: int64(DefaultPollInterval),
 	}
 }
 
@@ -183,6 +230,33 @@ func (c *Client) UpdateSpec(ctx context.Context, name, namespace string, spec ma
 	return nil
 }
 
+// PatchServiceParameters deep-merges patch into spec.services.<serviceName> using a JSON merge patch.
+// Other services and global parameters are left unchanged; null values in patch remove fields.
+func (c *Client) PatchServiceParameters(ctx context.Context, instanceName, namespace, serviceName string, patch map[string]interface{}) error {
+	if serviceName == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"services": map[string]interface{}{
+				serviceName: patch,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch for service %s: %w", serviceName, err)
+	}
+
+	resourceInterface := c.dynamicClient.Resource(c.gvr).Namespace(namespace)
+	_, err = resourceInterface.Patch(ctx, instanceName, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch service %s in DeploymentParameters %s/%s: %w", serviceName, namespace, instanceName, err)
+	}
+
+	return nil
+}
+
 // List lists all DeploymentParameters instances in a namespace
 func (c *Client) List(ctx context.Context, namespace string) ([]DeploymentParameters, error) {
 	resourceInterface := c.dynamicClient.Resource(c.gvr).Namespace(namespace)
@@ -0,0 +1,68 @@
+package crd
+
+import (
+	"reflect"
+)
+
+// SpecDiff lists the differences between two parameter specs, keyed by the dot-separated
+// path of each differing value, such as "global.namespace"
+type SpecDiff struct {
+	Added   map[string]interface{} `json:"added"`
+	Removed map[string]interface{} `json:"removed"`
+	Changed map[string]ValueChange `json:"changed"`
+}
+
+// ValueChange holds the value of a path in both specs
+type ValueChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Empty reports whether the specs have no differences
+func (d SpecDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffSpecs compares spec a to spec b. Nested maps are compared key by key; any other
+// value, including arrays, is compared as a whole.
+func DiffSpecs(a, b map[string]interface{}) SpecDiff {
+	diff := SpecDiff{
+		Added:   map[string]interface{}{},
+		Removed: map[string]interface{}{},
+		Changed: map[string]ValueChange{},
+	}
+	diffMaps("", a, b, &diff)
+	return diff
+}
+
+func diffMaps(prefix string, a, b map[string]interface{}, diff *SpecDiff) {
+	for key, from := range a {
+		path := joinPath(prefix, key)
+		to, ok := b[key]
+		if !ok {
+			diff.Removed[path] = deepCopyValue(from)
+			continue
+		}
+		fromMap, fromIsMap := from.(map[string]interface{})
+		toMap, toIsMap := to.(map[string]interface{})
+		if fromIsMap && toIsMap {
+			diffMaps(path, fromMap, toMap, diff)
+			continue
+		}
+		if !reflect.DeepEqual(from, to) {
+			diff.Changed[path] = ValueChange{From: deepCopyValue(from), To: deepCopyValue(to)}
+		}
+	}
+	for key, to := range b {
+		if _, ok := a[key]; !ok {
+			diff.Added[joinPath(prefix, key)] = deepCopyValue(to)
+		}
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,65 @@
+package crd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSpecs(t *testing.T) {
+	from := map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace":   "dev",
+			"debug":       true,
+			"storageSize": "1Gi",
+		},
+		"services": map[string]interface{}{
+			"redis": map[string]interface{}{"image": "redis:7", "ports": []interface{}{int64(6379)}},
+		},
+	}
+	to := map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace":   "prod",
+			"storageSize": "1Gi",
+		},
+		"services": map[string]interface{}{
+			"redis":    map[string]interface{}{"image": "redis:7", "ports": []interface{}{int64(6379)}},
+			"postgres": map[string]interface{}{"image": "postgres:16"},
+		},
+	}
+
+	diff := DiffSpecs(from, to)
+
+	wantAdded := map[string]interface{}{
+		"services.postgres": map[string]interface{}{"image": "postgres:16"},
+	}
+	if !reflect.DeepEqual(diff.Added, wantAdded) {
+		t.Errorf("DiffSpecs() Added = %v, want %v", diff.Added, wantAdded)
+	}
+	wantRemoved := map[string]interface{}{"global.debug": true}
+	if !reflect.DeepEqual(diff.Removed, wantRemoved) {
+		t.Errorf("DiffSpecs() Removed = %v, want %v", diff.Removed, wantRemoved)
+	}
+	wantChanged := map[string]ValueChange{"global.namespace": {From: "dev", To: "prod"}}
+	if !reflect.DeepEqual(diff.Changed, wantChanged) {
+		t.Errorf("DiffSpecs() Changed = %v, want %v", diff.Changed, wantChanged)
+	}
+
+	if diff := DiffSpecs(from, from); !diff.Empty() {
+		t.Errorf("DiffSpecs() of identical specs = %+v, want no differences", diff)
+	}
+}
+
+func TestDiffSpecs_TypeChange(t *testing.T) {
+	from := map[string]interface{}{"global": map[string]interface{}{"namespace": "dev"}}
+	to := map[string]interface{}{"global": "none"}
+
+	diff := DiffSpecs(from, to)
+
+	want := map[string]ValueChange{"global": {From: map[string]interface{}{"namespace": "dev"}, To: "none"}}
+	if !reflect.DeepEqual(diff.Changed, want) {
+		t.Errorf("DiffSpecs() Changed = %v, want %v", diff.Changed, want)
+	}
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Errorf("DiffSpecs() = %+v, want only a change", diff)
+	}
+}
@@ -0,0 +1,70 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// MergeSpec deep-merges overlay into the spec of a DeploymentParameters instance, creating the
+// instance when it does not exist, and returns the resulting spec. The update carries the
+// resourceVersion that was read, so a concurrent write makes it conflict; the merge is then
+// redone on the new spec rather than overwriting it.
+func (c *Client) MergeSpec(ctx context.Context, name, namespace string, overlay map[string]interface{}) (map[string]interface{}, error) {
+	resourceInterface := c.dynamicClient.Resource(c.gvr).Namespace(namespace)
+
+	var spec map[string]interface{}
+	retriable := func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}
+	err := retry.OnError(retry.DefaultRetry, retriable, func() error {
+		obj, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			spec = mergeSpecs(map[string]interface{}{}, overlay)
+			// A concurrent create makes this fail with AlreadyExists and the merge is retried
+			return c.CreateWithSpec(ctx, name, namespace, spec)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get DeploymentParameters %s/%s: %w", namespace, name, err)
+		}
+
+		existing, _ := obj.Object["spec"].(map[string]interface{})
+		if existing == nil {
+			existing = map[string]interface{}{}
+		}
+		spec = mergeSpecs(existing, overlay)
+		obj.Object["spec"] = spec
+		if _, err := resourceInterface.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update DeploymentParameters %s/%s: %w", namespace, name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// mergeSpecs recursively merges overlay into a copy of base. Keys missing from base are added,
+// nested maps are merged, and every other value in overlay, including arrays, replaces the
+// value in base. A nil overlay returns base unchanged.
+func mergeSpecs(base, overlay map[string]interface{}) map[string]interface{} {
+	if overlay == nil {
+		return base
+	}
+
+	merged := deepCopyMap(base)
+	for key, value := range overlay {
+		overlayMap, overlayIsMap := value.(map[string]interface{})
+		baseMap, baseIsMap := merged[key].(map[string]interface{})
+		if overlayIsMap && baseIsMap {
+			merged[key] = mergeSpecs(baseMap, overlayMap)
+			continue
+		}
+		merged[key] = deepCopyValue(value)
+	}
+	return merged
+}
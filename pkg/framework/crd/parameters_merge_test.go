@@ -0,0 +1,163 @@
+package crd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMergeSpecs(t *testing.T) {
+	base := map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace": "default",
+			"replicas":  int64(1),
+		},
+		"services": map[string]interface{}{
+			"redis": map[string]interface{}{"image": "redis:7", "ports": []interface{}{int64(6379)}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		overlay map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name: "new service keeps existing services",
+			overlay: map[string]interface{}{
+				"services": map[string]interface{}{
+					"postgres": map[string]interface{}{"image": "postgres:16"},
+				},
+			},
+			want: map[string]interface{}{
+				"global": map[string]interface{}{"namespace": "default", "replicas": int64(1)},
+				"services": map[string]interface{}{
+					"redis":    map[string]interface{}{"image": "redis:7", "ports": []interface{}{int64(6379)}},
+					"postgres": map[string]interface{}{"image": "postgres:16"},
+				},
+			},
+		},
+		{
+			name: "nested update keeps siblings",
+			overlay: map[string]interface{}{
+				"global": map[string]interface{}{"replicas": int64(3)},
+			},
+			want: map[string]interface{}{
+				"global": map[string]interface{}{"namespace": "default", "replicas": int64(3)},
+				"services": map[string]interface{}{
+					"redis": map[string]interface{}{"image": "redis:7", "ports": []interface{}{int64(6379)}},
+				},
+			},
+		},
+		{
+			name: "arrays are replaced",
+			overlay: map[string]interface{}{
+				"services": map[string]interface{}{
+					"redis": map[string]interface{}{"ports": []interface{}{int64(6380)}},
+				},
+			},
+			want: map[string]interface{}{
+				"global": map[string]interface{}{"namespace": "default", "replicas": int64(1)},
+				"services": map[string]interface{}{
+					"redis": map[string]interface{}{"image": "redis:7", "ports": []interface{}{int64(6380)}},
+				},
+			},
+		},
+		{
+			name:    "nil overlay returns base",
+			overlay: nil,
+			want:    base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeSpecs(base, tt.overlay)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeSpecs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if replicas := base["global"].(map[string]interface{})["replicas"]; replicas != int64(1) {
+		t.Errorf("mergeSpecs() modified base: global.replicas = %v, want 1", replicas)
+	}
+}
+
+func TestMergeSpec(t *testing.T) {
+	client := NewClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), logr.Discard(), "", "", "")
+	ctx := context.Background()
+
+	if _, err := client.MergeSpec(ctx, DefaultName, "default", map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "default", "replicas": int64(1)},
+	}); err != nil {
+		t.Fatalf("MergeSpec() create error = %v", err)
+	}
+	if _, err := client.MergeSpec(ctx, DefaultName, "default", map[string]interface{}{
+		"global": map[string]interface{}{"replicas": int64(2)},
+	}); err != nil {
+		t.Fatalf("MergeSpec() update error = %v", err)
+	}
+
+	spec, err := client.GetSpec(ctx, DefaultName, "default")
+	if err != nil {
+		t.Fatalf("GetSpec() error = %v", err)
+	}
+	want := map[string]interface{}{"namespace": "default", "replicas": int64(2)}
+	if got := spec["global"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSpec() global = %v, want %v", got, want)
+	}
+}
+
+func TestMergeSpec_RetriesOnConflict(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client := NewClient(dynamicClient, logr.Discard(), "", "", "")
+	ctx := context.Background()
+
+	if err := client.CreateWithSpec(ctx, DefaultName, "default", map[string]interface{}{"a": "1"}); err != nil {
+		t.Fatalf("CreateWithSpec() error = %v", err)
+	}
+
+	// The first update loses the race against another writer that adds b
+	updates := 0
+	dynamicClient.PrependReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updates++
+		if updates > 1 {
+			return false, nil, nil
+		}
+		current, err := dynamicClient.Tracker().Get(client.gvr, "default", DefaultName)
+		if err != nil {
+			return true, nil, err
+		}
+		concurrent := current.(*unstructured.Unstructured).DeepCopy()
+		concurrent.Object["spec"] = map[string]interface{}{"a": "1", "b": "2"}
+		if err := dynamicClient.Tracker().Update(client.gvr, concurrent, "default"); err != nil {
+			return true, nil, err
+		}
+		return true, nil, k8serrors.NewConflict(client.gvr.GroupResource(), DefaultName, errors.New("object was modified"))
+	})
+
+	spec, err := client.MergeSpec(ctx, DefaultName, "default", map[string]interface{}{"c": "3"})
+	if err != nil {
+		t.Fatalf("MergeSpec() error = %v", err)
+	}
+	want := map[string]interface{}{"a": "1", "b": "2", "c": "3"}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("MergeSpec() = %v, want %v", spec, want)
+	}
+	stored, err := client.GetSpec(ctx, DefaultName, "default")
+	if err != nil {
+		t.Fatalf("GetSpec() error = %v", err)
+	}
+	if !reflect.DeepEqual(stored, want) {
+		t.Errorf("stored spec = %v, want %v with the concurrent write kept", stored, want)
+	}
+}
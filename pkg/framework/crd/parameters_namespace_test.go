@@ -0,0 +1,40 @@
+package crd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestNamespaceExists(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core/v1 to scheme: %v", err)
+	}
+	staging := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}}
+	client := NewClient(dynamicfake.NewSimpleDynamicClient(scheme, staging), logr.Discard(), "", "", "")
+
+	tests := []struct {
+		namespace string
+		want      bool
+	}{
+		{namespace: "staging", want: true},
+		{namespace: "production", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			got, err := client.NamespaceExists(context.Background(), tt.namespace)
+			if err != nil {
+				t.Fatalf("NamespaceExists() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NamespaceExists(%q) = %v, want %v", tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package crd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestPatchServiceParameters(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client := NewClient(dynamicClient, logr.Discard(), "conductor.io", "v1alpha1", "deploymentparameters")
+	ctx := context.Background()
+
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "production"},
+		"services": map[string]interface{}{
+			"frontend": map[string]interface{}{"replicas": int64(1), "image": "frontend:v1"},
+			"backend":  map[string]interface{}{"replicas": int64(2)},
+		},
+	}
+	if err := client.CreateWithSpec(ctx, "default", "default", spec); err != nil {
+		t.Fatalf("CreateWithSpec() error = %v", err)
+	}
+
+	err := client.PatchServiceParameters(ctx, "default", "default", "frontend", map[string]interface{}{"replicas": 3})
+	if err != nil {
+		t.Fatalf("PatchServiceParameters() error = %v", err)
+	}
+
+	got, err := client.GetSpec(ctx, "default", "default")
+	if err != nil {
+		t.Fatalf("GetSpec() error = %v", err)
+	}
+
+	services := got["services"].(map[string]interface{})
+	frontend := services["frontend"].(map[string]interface{})
+	if replicas, _ := frontend["replicas"].(int64); replicas != 3 {
+		t.Errorf("frontend replicas = %v, want 3", frontend["replicas"])
+	}
+	if frontend["image"] != "frontend:v1" {
+		t.Errorf("frontend image = %v, want frontend:v1 (unpatched fields must be kept)", frontend["image"])
+	}
+
+	backend := services["backend"].(map[string]interface{})
+	if replicas, _ := backend["replicas"].(int64); replicas != 2 {
+		t.Errorf("backend replicas = %v, want 2", backend["replicas"])
+	}
+
+	global := got["global"].(map[string]interface{})
+	if global["namespace"] != "production" {
+		t.Errorf("global namespace = %v, want production", global["namespace"])
+	}
+}
+
+func TestPatchServiceParameters_Errors(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client := NewClient(dynamicClient, logr.Discard(), "conductor.io", "v1alpha1", "deploymentparameters")
+	ctx := context.Background()
+
+	if err := client.PatchServiceParameters(ctx, "default", "default", "", map[string]interface{}{"replicas": 3}); err == nil {
+		t.Error("PatchServiceParameters() with empty service name should return error")
+	}
+
+	if err := client.PatchServiceParameters(ctx, "missing", "default", "frontend", map[string]interface{}{"replicas": 3}); err == nil {
+		t.Error("PatchServiceParameters() on missing instance should return error")
+	}
+}
@@ -0,0 +1,101 @@
+package crd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// SetPollInterval sets the interval between Subscribe polls; running subscriptions switch to
+// it after their next poll. Non-positive values reset it to DefaultPollInterval.
+func (c *Client) SetPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	atomic.StoreInt64(&c.pollInterval, int64(interval))
+}
+
+func (c *Client) getPollInterval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.pollInterval))
+}
+
+// Subscribe polls a DeploymentParameters instance and emits it whenever its spec changes.
+// The current state is emitted first. The channel is closed when ctx is cancelled.
+func (c *Client) Subscribe(ctx context.Context, name, namespace string) (<-chan DeploymentParameters, error) {
+	params, err := c.Get(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan DeploymentParameters, 1)
+	interval := c.getPollInterval()
+
+	go func() {
+		defer close(ch)
+
+		lastHash := ""
+		emit := func(params *DeploymentParameters) bool {
+			if params == nil {
+				return true
+			}
+			hash, err := specHash(params.Spec)
+			if err != nil {
+				c.logger.Error(err, "failed to hash DeploymentParameters spec", "name", name, "namespace", namespace)
+				return true
+			}
+			if hash == lastHash {
+				return true
+			}
+			select {
+			case ch <- *params:
+				lastHash = hash
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !emit(params) {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if current := c.getPollInterval(); current != interval {
+					interval = current
+					ticker.Reset(interval)
+				}
+				params, err := c.Get(ctx, name, namespace)
+				if err != nil {
+					c.logger.V(1).Info("failed to poll DeploymentParameters", "name", name, "namespace", namespace, "error", err)
+					continue
+				}
+				if !emit(params) {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// specHash returns a content hash of a spec. encoding/json sorts map keys,
+// so equal specs always produce the same hash.
+func specHash(spec DeploymentParametersSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
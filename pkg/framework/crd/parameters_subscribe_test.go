@@ -0,0 +1,135 @@
+package crd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newSubscribeTestClient(t *testing.T) *Client {
+	t.Helper()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client := NewClient(dynamicClient, logr.Discard(), "conductor.io", "v1alpha1", "deploymentparameters")
+	client.SetPollInterval(10 * time.Millisecond)
+	return client
+}
+
+func receiveParams(t *testing.T, ch <-chan DeploymentParameters) DeploymentParameters {
+	t.Helper()
+	select {
+	case params, ok := <-ch:
+		if !ok {
+			t.Fatal("Subscribe() channel closed unexpectedly")
+		}
+		return params
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Subscribe() emission")
+	}
+	return DeploymentParameters{}
+}
+
+func TestSubscribe_EmitsOnChange(t *testing.T) {
+	client := newSubscribeTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spec := map[string]interface{}{"global": map[string]interface{}{"namespace": "default"}}
+	if err := client.CreateWithSpec(ctx, "default", "default", spec); err != nil {
+		t.Fatalf("CreateWithSpec() error = %v", err)
+	}
+
+	ch, err := client.Subscribe(ctx, "default", "default")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	initial := receiveParams(t, ch)
+	if ns := initial.Spec["global"].(map[string]interface{})["namespace"]; ns != "default" {
+		t.Errorf("initial namespace = %v, want default", ns)
+	}
+
+	updated := map[string]interface{}{"global": map[string]interface{}{"namespace": "production"}}
+	if err := client.UpdateSpec(ctx, "default", "default", updated); err != nil {
+		t.Fatalf("UpdateSpec() error = %v", err)
+	}
+
+	next := receiveParams(t, ch)
+	if ns := next.Spec["global"].(map[string]interface{})["namespace"]; ns != "production" {
+		t.Errorf("updated namespace = %v, want production", ns)
+	}
+}
+
+func TestSubscribe_NoDuplicateEmissions(t *testing.T) {
+	client := newSubscribeTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spec := map[string]interface{}{"global": map[string]interface{}{"replicas": int64(1)}}
+	if err := client.CreateWithSpec(ctx, "default", "default", spec); err != nil {
+		t.Fatalf("CreateWithSpec() error = %v", err)
+	}
+
+	ch, err := client.Subscribe(ctx, "default", "default")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	receiveParams(t, ch)
+
+	// Rewrite the same spec; several polls should pass without an emission
+	if err := client.UpdateSpec(ctx, "default", "default", spec); err != nil {
+		t.Fatalf("UpdateSpec() error = %v", err)
+	}
+
+	select {
+	case params := <-ch:
+		t.Errorf("Subscribe() emitted unchanged spec: %v", params.Spec)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscribe_ClosesOnCancel(t *testing.T) {
+	client := newSubscribeTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := client.Subscribe(ctx, "missing", "default")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Subscribe() emitted for a missing instance")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe() channel not closed after cancel")
+	}
+}
+
+func TestSubscribe_PollIntervalChangedWhileRunning(t *testing.T) {
+	client := newSubscribeTestClient(t)
+	client.SetPollInterval(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spec := map[string]interface{}{"replicas": int64(1)}
+	if err := client.CreateWithSpec(ctx, "default", "default", spec); err != nil {
+		t.Fatalf("CreateWithSpec() error = %v", err)
+	}
+	ch, err := client.Subscribe(ctx, "default", "default")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	receiveParams(t, ch)
+
+	// Runs concurrently with the polling goroutine; -race reports unsynchronized access
+	client.SetPollInterval(10 * time.Millisecond)
+	if client.getPollInterval() != 10*time.Millisecond {
+		t.Errorf("getPollInterval() = %v, want 10ms", client.getPollInterval())
+	}
+}
@@ -0,0 +1,198 @@
+package crd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FieldError describes a single field of a spec that does not match the CRD schema
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateSpecAgainstSchema checks spec against the OpenAPI v3 schema of the spec property of
+// the CRD and returns one FieldError per offending field, sorted by field path. It checks
+// types, required properties, enums and unknown fields; objects marked with
+// x-kubernetes-preserve-unknown-fields or additionalProperties accept arbitrary keys.
+func ValidateSpecAgainstSchema(spec, schema map[string]interface{}) []FieldError {
+	var errs []FieldError
+	validateValue("", spec, schema, &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+func validateValue(path string, value interface{}, schema map[string]interface{}, errs *[]FieldError) {
+	if schema == nil {
+		return
+	}
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); !nullable && schema["type"] != nil {
+			*errs = append(*errs, FieldError{Field: path, Message: "must not be null"})
+		}
+		return
+	}
+
+	if intOrString, _ := schema["x-kubernetes-int-or-string"].(bool); intOrString {
+		if !isInteger(value) && !isString(value) {
+			*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be an integer or a string, got %s", jsonType(value))})
+		}
+		return
+	}
+
+	schemaType, _ := schema["type"].(string)
+	if schemaType != "" && !matchesType(value, schemaType) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be of type %s, got %s", schemaType, jsonType(value))})
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 && !inEnum(value, enum) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be one of %v", enum)})
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(path, v, schema, errs)
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range v {
+			validateValue(fmt.Sprintf("%s[%d]", path, i), item, items, errs)
+		}
+	}
+}
+
+func validateObject(path string, obj map[string]interface{}, schema map[string]interface{}, errs *[]FieldError) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+			if _, present := obj[name]; !present {
+				*errs = append(*errs, FieldError{Field: joinFieldPath(path, name), Message: "is required"})
+			}
+		}
+	}
+
+	preserveUnknown, _ := schema["x-kubernetes-preserve-unknown-fields"].(bool)
+	for key, child := range obj {
+		childPath := joinFieldPath(path, key)
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			validateValue(childPath, child, propSchema, errs)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case map[string]interface{}:
+			validateValue(childPath, child, additional, errs)
+		case bool:
+			if !additional && !preserveUnknown {
+				*errs = append(*errs, FieldError{Field: childPath, Message: "is not a known field"})
+			}
+		default:
+			// Without additionalProperties an object only accepts its declared properties,
+			// unless it declares none or preserves unknown fields
+			if properties != nil && !preserveUnknown {
+				*errs = append(*errs, FieldError{Field: childPath, Message: "is not a known field"})
+			}
+		}
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func matchesType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		return isString(value)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		return isInteger(value)
+	case "number":
+		return isNumber(value)
+	default:
+		return true
+	}
+}
+
+func isString(value interface{}) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func isInteger(value interface{}) bool {
+	switch v := value.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return v == math.Trunc(v) && !math.IsInf(v, 0)
+	default:
+		return false
+	}
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int32, int64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonType names the JSON type of a decoded value for error messages
+func jsonType(value interface{}) string {
+	switch {
+	case isString(value):
+		return "string"
+	case isInteger(value):
+		return "integer"
+	case isNumber(value):
+		return "number"
+	}
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package crd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// testSpecSchema mirrors the spec schema of the DeploymentParameters CRD
+var testSpecSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"global"},
+	"properties": map[string]interface{}{
+		"global": map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"namespace"},
+			"properties": map[string]interface{}{
+				"namespace": map[string]interface{}{"type": "string"},
+				"replicas":  map[string]interface{}{"type": "integer"},
+				"pullPolicy": map[string]interface{}{
+					"type": "string",
+					"enum": []interface{}{"Always", "IfNotPresent", "Never"},
+				},
+				"labels": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+		"services": map[string]interface{}{
+			"type":                                 "object",
+			"x-kubernetes-preserve-unknown-fields": true,
+		},
+		"ports": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "integer"},
+		},
+	},
+}
+
+func decodeSpec(t *testing.T, body string) map[string]interface{} {
+	t.Helper()
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &spec); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	return spec
+}
+
+func TestValidateSpecAgainstSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want []FieldError
+	}{
+		{
+			name: "valid spec",
+			spec: `{"global": {"namespace": "apps", "replicas": 3, "pullPolicy": "Always", "labels": {"team": "a"}}, "services": {"redis": {"anything": true}}, "ports": [80, 443]}`,
+		},
+		{
+			name: "type mismatch",
+			spec: `{"global": {"namespace": "apps", "replicas": "three"}}`,
+			want: []FieldError{{Field: "global.replicas", Message: "must be of type integer, got string"}},
+		},
+		{
+			name: "fractional integer",
+			spec: `{"global": {"namespace": "apps", "replicas": 1.5}}`,
+			want: []FieldError{{Field: "global.replicas", Message: "must be of type integer, got number"}},
+		},
+		{
+			name: "missing required fields",
+			spec: `{"global": {}}`,
+			want: []FieldError{{Field: "global.namespace", Message: "is required"}},
+		},
+		{
+			name: "missing required top-level field",
+			spec: `{}`,
+			want: []FieldError{{Field: "global", Message: "is required"}},
+		},
+		{
+			name: "extra fields",
+			spec: `{"global": {"namespace": "apps", "replica": 2}, "unknown": 1}`,
+			want: []FieldError{
+				{Field: "global.replica", Message: "is not a known field"},
+				{Field: "unknown", Message: "is not a known field"},
+			},
+		},
+		{
+			name: "additionalProperties schema",
+			spec: `{"global": {"namespace": "apps", "labels": {"team": 1}}}`,
+			want: []FieldError{{Field: "global.labels.team", Message: "must be of type string, got integer"}},
+		},
+		{
+			name: "array items",
+			spec: `{"global": {"namespace": "apps"}, "ports": [80, "http"]}`,
+			want: []FieldError{{Field: "ports[1]", Message: "must be of type integer, got string"}},
+		},
+		{
+			name: "enum",
+			spec: `{"global": {"namespace": "apps", "pullPolicy": "Sometimes"}}`,
+			want: []FieldError{{Field: "global.pullPolicy", Message: "must be one of [Always IfNotPresent Never]"}},
+		},
+		{
+			name: "null value",
+			spec: `{"global": {"namespace": null}}`,
+			want: []FieldError{{Field: "global.namespace", Message: "must not be null"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateSpecAgainstSchema(decodeSpec(t, tt.spec), testSpecSchema)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateSpecAgainstSchema() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSpecAgainstSchema_IntOrString(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"port": map[string]interface{}{"x-kubernetes-int-or-string": true},
+		},
+	}
+
+	for _, body := range []string{`{"port": 8080}`, `{"port": "http"}`} {
+		if errs := ValidateSpecAgainstSchema(decodeSpec(t, body), schema); len(errs) != 0 {
+			t.Errorf("ValidateSpecAgainstSchema(%s) = %v, want no errors", body, errs)
+		}
+	}
+	if errs := ValidateSpecAgainstSchema(decodeSpec(t, `{"port": true}`), schema); len(errs) != 1 {
+		t.Errorf("ValidateSpecAgainstSchema(bool port) = %v, want one error", errs)
+	}
+}
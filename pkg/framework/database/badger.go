@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/go-logr/logr"
@@ -13,9 +14,28 @@ import (
 
 var ErrNotFound = errors.New("key not found")
 
+// ErrNestedTransaction is returned when Transaction is called on a transaction-scoped DB
+var ErrNestedTransaction = errors.New("nested transactions are not supported")
+
 type DB struct {
 	db     *badger.DB
 	logger logr.Logger
+	// txn is set on the transaction-scoped DB passed to Transaction callbacks
+	txn *badger.Txn
+	// prefix is prepended to every key of a DB returned by WithPrefix
+	prefix string
+}
+
+// WithPrefix returns a view of d that prepends prefix to every key it reads and writes and
+// strips it from the keys List returns, so components can keep separate key spaces in one
+// database. The view shares d's database and transaction.
+func (d *DB) WithPrefix(prefix string) *DB {
+	return &DB{db: d.db, logger: d.logger, txn: d.txn, prefix: d.prefix + prefix}
+}
+
+// key returns the database key of key in d's key space
+func (d *DB) key(key string) []byte {
+	return []byte(d.prefix + key)
 }
 
 func NewDB(path string, logger logr.Logger) (*DB, error) {
@@ -46,8 +66,8 @@ func NewDB(path string, logger logr.Logger) (*DB, error) {
 
 func (d *DB) Get(key string) ([]byte, error) {
 	var value []byte
-	err := d.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(key))
+	err := d.view(func(txn *badger.Txn) error {
+		item, err := txn.Get(d.key(key))
 		if err != nil {
 			return err
 		}
@@ -69,8 +89,24 @@ func (d *DB) Get(key string) ([]byte, error) {
 	return value, nil
 }
 
+// view runs fn in a read-only transaction, or in the enclosing transaction if d is transaction-scoped
+func (d *DB) view(fn func(*badger.Txn) error) error {
+	if d.txn != nil {
+		return fn(d.txn)
+	}
+	return d.db.View(fn)
+}
+
+// write runs fn in a read-write transaction, or in the enclosing transaction if d is transaction-scoped
+func (d *DB) write(fn func(*badger.Txn) error) error {
+	if d.txn != nil {
+		return fn(d.txn)
+	}
+	return d.db.Update(fn)
+}
+
 func (d *DB) update(operation string, key string, fn func(*badger.Txn) error) error {
-	err := d.db.Update(fn)
+	err := d.write(fn)
 	if err != nil {
 		return fmt.Errorf("%w: storage %s %s: %w", apperrors.ErrStorage, operation, key, err)
 	}
@@ -79,27 +115,34 @@ func (d *DB) update(operation string, key string, fn func(*badger.Txn) error) er
 
 func (d *DB) Set(key string, value []byte) error {
 	return d.update("set", key, func(txn *badger.Txn) error {
-		return txn.Set([]byte(key), value)
+		return txn.Set(d.key(key), value)
+	})
+}
+
+// SetWithTTL stores value under key until ttl has passed, after which the key reads as not found
+func (d *DB) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+	return d.update("set", key, func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry(d.key(key), value).WithTTL(ttl))
 	})
 }
 
 func (d *DB) Delete(key string) error {
 	return d.update("delete", key, func(txn *badger.Txn) error {
-		return txn.Delete([]byte(key))
+		return txn.Delete(d.key(key))
 	})
 }
 
 func (d *DB) List(prefix string) (map[string][]byte, error) {
 	results := make(map[string][]byte)
-	err := d.db.View(func(txn *badger.Txn) error {
+	err := d.view(func(txn *badger.Txn) error {
 		opts := badger.DefaultIteratorOptions
-		opts.Prefix = []byte(prefix)
+		opts.Prefix = d.key(prefix)
 		it := txn.NewIterator(opts)
 		defer it.Close()
 
 		for it.Rewind(); it.Valid(); it.Next() {
 			item := it.Item()
-			key := string(item.Key())
+			key := string(item.Key()[len(d.prefix):])
 			err := item.Value(func(val []byte) error {
 				results[key] = append([]byte{}, val...)
 				return nil
@@ -116,17 +159,75 @@ func (d *DB) List(prefix string) (map[string][]byte, error) {
 	return results, nil
 }
 
-func (d *DB) BatchSet(items map[string][]byte) error {
-	txn := d.db.NewTransaction(true)
-	defer txn.Discard()
+// ListKeys returns up to limit keys under prefix in ascending order without reading their
+// values. A limit of zero returns every key.
+func (d *DB) ListKeys(prefix string, limit int) ([]string, error) {
+	var keys []string
+	err := d.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = d.key(prefix)
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
 
-	for key, value := range items {
-		if err := txn.Set([]byte(key), value); err != nil {
-			return fmt.Errorf("%w: storage batch set %s: %w", apperrors.ErrStorage, key, err)
+		for it.Rewind(); it.Valid() && (limit <= 0 || len(keys) < limit); it.Next() {
+			keys = append(keys, string(it.Item().Key()[len(d.prefix):]))
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: storage list keys %s: %w", apperrors.ErrStorage, prefix, err)
 	}
+	return keys, nil
+}
 
-	if err := txn.Commit(); err != nil {
+// Scan calls fn with every key under prefix, starting at the first key not before
+// prefix+start, in ascending order until fn returns false. The value is only valid during fn.
+func (d *DB) Scan(prefix, start string, fn func(key string, value []byte) bool) error {
+	err := d.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = d.key(prefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(d.key(prefix + start)); it.Valid(); it.Next() {
+			item := it.Item()
+			key := string(item.Key()[len(d.prefix):])
+			more := true
+			err := item.Value(func(val []byte) error {
+				more = fn(key, val)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: storage scan %s: %w", apperrors.ErrStorage, prefix, err)
+	}
+	return nil
+}
+
+func (d *DB) BatchSet(items map[string][]byte) error {
+	var setErr error
+	err := d.write(func(txn *badger.Txn) error {
+		for key, value := range items {
+			if err := txn.Set(d.key(key), value); err != nil {
+				setErr = fmt.Errorf("%w: storage batch set %s: %w", apperrors.ErrStorage, key, err)
+				return setErr
+			}
+		}
+		return nil
+	})
+	if setErr != nil {
+		return setErr
+	}
+	if err != nil {
 		return fmt.Errorf("%w: storage batch set commit: %w", apperrors.ErrStorage, err)
 	}
 
@@ -134,24 +235,58 @@ func (d *DB) BatchSet(items map[string][]byte) error {
 }
 
 func (d *DB) BatchDelete(keys []string) error {
-	txn := d.db.NewTransaction(true)
-	defer txn.Discard()
+	err := d.write(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			if err := txn.Delete(d.key(key)); err != nil {
+
+				d.logger.V(1).Info("failed to delete key in batch", "key", key, "error", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: storage batch delete commit: %w", apperrors.ErrStorage, err)
+	}
 
-	for _, key := range keys {
-		if err := txn.Delete([]byte(key)); err != nil {
+	return nil
+}
 
-			d.logger.V(1).Info("failed to delete key in batch", "key", key, "error", err)
+// BatchWrite sets items and deletes keys in a single atomic transaction
+func (d *DB) BatchWrite(items map[string][]byte, deleteKeys []string) error {
+	return d.Transaction(func(txn *DB) error {
+		if err := txn.BatchDelete(deleteKeys); err != nil {
+			return err
 		}
+		return txn.BatchSet(items)
+	})
+}
+
+// Transaction runs fn with a transaction-scoped DB. All reads and writes made through
+// that DB are committed together when fn returns nil and discarded when it returns an error.
+// Calling Transaction on a transaction-scoped DB returns ErrNestedTransaction.
+func (d *DB) Transaction(fn func(txn *DB) error) error {
+	if d.txn != nil {
+		return fmt.Errorf("%w: storage transaction: %w", apperrors.ErrStorage, ErrNestedTransaction)
+	}
+
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	if err := fn(&DB{db: d.db, logger: d.logger, txn: txn, prefix: d.prefix}); err != nil {
+		return err
 	}
 
 	if err := txn.Commit(); err != nil {
-		return fmt.Errorf("%w: storage batch delete commit: %w", apperrors.ErrStorage, err)
+		return fmt.Errorf("%w: storage transaction commit: %w", apperrors.ErrStorage, err)
 	}
 
 	return nil
 }
 
 func (d *DB) Close() error {
+	if d.txn != nil {
+		return fmt.Errorf("%w: cannot close a transaction-scoped DB", apperrors.ErrStorage)
+	}
 	return d.db.Close()
 }
 
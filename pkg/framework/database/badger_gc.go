@@ -0,0 +1,88 @@
+package database
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v4"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// DefaultGCDiscardRatio is the fraction of a value log file that must be stale before RunGC rewrites it
+const DefaultGCDiscardRatio = 0.5
+
+// pendingWritesMetric is the expvar map in which Badger publishes pending writes per database directory
+const pendingWritesMetric = "badger_write_pending_num_memtable"
+
+// Stats reports the size and contents of the database
+type Stats struct {
+	LSMSizeBytes     int64
+	VLogSizeBytes    int64
+	NumKeys          int64
+	NumPendingWrites int64
+}
+
+// RunGC rewrites value log files in which at least discardRatio of the data is stale, repeating
+// until no file qualifies, and returns the number of files rewritten. An in-memory database has
+// no value log, so there is nothing to collect.
+func (d *DB) RunGC(discardRatio float64) (int, error) {
+	if discardRatio <= 0 || discardRatio >= 1 {
+		return 0, fmt.Errorf("%w: discard ratio must be between 0 and 1", apperrors.ErrInvalid)
+	}
+
+	rewritten := 0
+	for {
+		err := d.db.RunValueLogGC(discardRatio)
+		switch {
+		case err == nil:
+			rewritten++
+		case errors.Is(err, badger.ErrNoRewrite), errors.Is(err, badger.ErrGCInMemoryMode):
+			return rewritten, nil
+		default:
+			return rewritten, fmt.Errorf("%w: storage value log gc: %w", apperrors.ErrStorage, err)
+		}
+	}
+}
+
+// Flatten compacts every level of the LSM tree into the last one using workers concurrent compactions
+func (d *DB) Flatten(workers int) error {
+	if err := d.db.Flatten(workers); err != nil {
+		return fmt.Errorf("%w: storage flatten: %w", apperrors.ErrStorage, err)
+	}
+	return nil
+}
+
+// Size returns the on-disk size of the LSM tree and value log in bytes. Unlike Stats it does
+// not count keys, so it is cheap enough to poll.
+func (d *DB) Size() int64 {
+	lsm, vlog := d.db.Size()
+	return lsm + vlog
+}
+
+// Stats returns the on-disk sizes, the number of live keys and the writes waiting for a memtable
+func (d *DB) Stats() (Stats, error) {
+	var stats Stats
+	stats.LSMSizeBytes, stats.VLogSizeBytes = d.db.Size()
+
+	err := d.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			stats.NumKeys++
+		}
+		return nil
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("%w: storage stats: %w", apperrors.ErrStorage, err)
+	}
+
+	if pending, ok := expvar.Get(pendingWritesMetric).(*expvar.Map); ok {
+		if count, ok := pending.Get(d.db.Opts().Dir).(*expvar.Int); ok {
+			stats.NumPendingWrites = count.Value()
+		}
+	}
+	return stats, nil
+}
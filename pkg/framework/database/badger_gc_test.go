@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+func TestDBRunGCAndFlatten(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "gc-db"), logr.Discard())
+	if err != nil {
+		t.Fatalf("failed to create DB: %v", err)
+	}
+	defer db.Close()
+
+	for i := 0; i < 100; i++ {
+		if err := db.Set(fmt.Sprintf("key-%d", i), []byte("value")); err != nil {
+			t.Fatalf("failed to set value: %v", err)
+		}
+	}
+	for i := 0; i < 50; i++ {
+		if err := db.Delete(fmt.Sprintf("key-%d", i)); err != nil {
+			t.Fatalf("failed to delete value: %v", err)
+		}
+	}
+
+	if _, err := db.RunGC(DefaultGCDiscardRatio); err != nil {
+		t.Errorf("RunGC() error = %v", err)
+	}
+	if err := db.Flatten(1); err != nil {
+		t.Errorf("Flatten() error = %v", err)
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.NumKeys != 50 {
+		t.Errorf("Stats() NumKeys = %d, want 50", stats.NumKeys)
+	}
+	if stats.LSMSizeBytes < 0 || stats.VLogSizeBytes < 0 || stats.NumPendingWrites < 0 {
+		t.Errorf("Stats() = %+v, want non-negative values", stats)
+	}
+}
+
+func TestDBRunGC_InMemory(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	if rewritten, err := db.RunGC(DefaultGCDiscardRatio); err != nil || rewritten != 0 {
+		t.Errorf("RunGC() = %d, %v, want 0, nil for an in-memory DB", rewritten, err)
+	}
+}
+
+func TestDBRunGC_InvalidDiscardRatio(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	for _, ratio := range []float64{0, -0.5, 1, 2} {
+		if _, err := db.RunGC(ratio); !errors.Is(err, apperrors.ErrInvalid) {
+			t.Errorf("RunGC(%v) error = %v, want ErrInvalid", ratio, err)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 )
@@ -261,3 +263,245 @@ func TestDBBatchDelete_NonexistentKeys(t *testing.T) {
 	}
 }
 
+func TestDBBatchWrite(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	if err := db.Set("old", []byte("value")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	err = db.BatchWrite(map[string][]byte{"new": []byte("value")}, []string{"old"})
+	if err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+
+	if _, err := db.Get("old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected old key to be deleted, got err = %v", err)
+	}
+	val, err := db.Get("new")
+	if err != nil {
+		t.Fatalf("failed to get new key: %v", err)
+	}
+	if string(val) != "value" {
+		t.Errorf("expected value, got %s", string(val))
+	}
+}
+
+func TestDBTransaction_Commit(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	if err := db.Set("counter", []byte("1")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	err = db.Transaction(func(txn *DB) error {
+		val, err := txn.Get("counter")
+		if err != nil {
+			return err
+		}
+		if err := txn.Set("counter", append(val, '1')); err != nil {
+			return err
+		}
+		if err := txn.Set("other", []byte("value")); err != nil {
+			return err
+		}
+		// Writes are visible inside the transaction
+		items, err := txn.List("")
+		if err != nil {
+			return err
+		}
+		if len(items) != 2 {
+			t.Errorf("expected 2 keys inside transaction, got %d", len(items))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction() error = %v", err)
+	}
+
+	val, err := db.Get("counter")
+	if err != nil {
+		t.Fatalf("failed to get counter: %v", err)
+	}
+	if string(val) != "11" {
+		t.Errorf("expected counter 11, got %s", string(val))
+	}
+	if _, err := db.Get("other"); err != nil {
+		t.Errorf("expected other key to be committed, got err = %v", err)
+	}
+}
+
+func TestDBTransaction_Rollback(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	if err := db.Set("existing", []byte("value")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	wantErr := errors.New("abort")
+	err = db.Transaction(func(txn *DB) error {
+		if err := txn.Set("new", []byte("value")); err != nil {
+			return err
+		}
+		if err := txn.Delete("existing"); err != nil {
+			return err
+		}
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Transaction() error = %v, want %v", err, wantErr)
+	}
+
+	if _, err := db.Get("new"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected new key to be rolled back, got err = %v", err)
+	}
+	if _, err := db.Get("existing"); err != nil {
+		t.Errorf("expected existing key to survive rollback, got err = %v", err)
+	}
+}
+
+func TestDBTransaction_Nested(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	err = db.Transaction(func(txn *DB) error {
+		if err := txn.Set("outer", []byte("value")); err != nil {
+			return err
+		}
+		return txn.Transaction(func(inner *DB) error {
+			return inner.Set("inner", []byte("value"))
+		})
+	})
+	if !errors.Is(err, ErrNestedTransaction) {
+		t.Fatalf("Transaction() error = %v, want ErrNestedTransaction", err)
+	}
+
+	if _, err := db.Get("outer"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected outer write to be rolled back, got err = %v", err)
+	}
+}
+
+func TestDBSetWithTTL(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+
+	if err := db.SetWithTTL("confirm/token", []byte("value"), time.Second); err != nil {
+		t.Fatalf("SetWithTTL() error = %v", err)
+	}
+	if val, err := db.Get("confirm/token"); err != nil || string(val) != "value" {
+		t.Fatalf("Get() before expiry = %q, %v", val, err)
+	}
+
+	// Badger expires entries with second granularity
+	time.Sleep(2 * time.Second)
+	if _, err := db.Get("confirm/token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after expiry error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDBWithPrefix(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	east := db.WithPrefix("cluster/east/")
+
+	if err := db.Set("managed/a", nil); err != nil {
+		t.Fatalf("failed to set value: %v", err)
+	}
+	if err := east.Set("managed/b", []byte("east")); err != nil {
+		t.Fatalf("failed to set prefixed value: %v", err)
+	}
+	if err := east.Transaction(func(txn *DB) error {
+		return txn.Set("managed/c", nil)
+	}); err != nil {
+		t.Fatalf("failed to set prefixed value in a transaction: %v", err)
+	}
+
+	items, err := east.List("managed/")
+	if err != nil {
+		t.Fatalf("failed to list prefixed values: %v", err)
+	}
+	if len(items) != 2 || string(items["managed/b"]) != "east" {
+		t.Errorf("prefixed List() = %v, want managed/b and managed/c", items)
+	}
+	if items, _ := db.List("managed/"); len(items) != 1 {
+		t.Errorf("List() = %v, want only the unprefixed managed/a", items)
+	}
+	if value, err := db.Get("cluster/east/managed/b"); err != nil || string(value) != "east" {
+		t.Errorf("Get() of the full key = %q, %v, want east", value, err)
+	}
+	if _, err := east.Get("managed/a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("prefixed Get() of an unprefixed key error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDBListKeys(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	east := db.WithPrefix("cluster/east/")
+	for _, key := range []string{"items/c", "items/a", "items/b", "other/a"} {
+		if err := east.Set(key, []byte("value")); err != nil {
+			t.Fatalf("failed to set value: %v", err)
+		}
+	}
+
+	keys, err := east.ListKeys("items/", 0)
+	if err != nil {
+		t.Fatalf("ListKeys() error = %v", err)
+	}
+	if want := []string{"items/a", "items/b", "items/c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ListKeys() = %v, want %v", keys, want)
+	}
+
+	keys, err = east.ListKeys("items/", 2)
+	if err != nil {
+		t.Fatalf("ListKeys() error = %v", err)
+	}
+	if want := []string{"items/a", "items/b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ListKeys() with a limit = %v, want %v", keys, want)
+	}
+}
+
+func TestDBScan(t *testing.T) {
+	db, err := NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	east := db.WithPrefix("cluster/east/")
+	for _, key := range []string{"items/d", "items/a", "items/c", "items/b", "other/a"} {
+		if err := east.Set(key, []byte("value "+key)); err != nil {
+			t.Fatalf("failed to set value: %v", err)
+		}
+	}
+
+	var keys []string
+	err = east.Scan("items/", "b", func(key string, value []byte) bool {
+		if string(value) != "value "+key {
+			t.Errorf("Scan() value of %s = %q", key, value)
+		}
+		keys = append(keys, key)
+		return key != "items/c"
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if want := []string{"items/b", "items/c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Scan() = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,173 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff statuses reported for a manifest
+const (
+	StatusUnchanged = "unchanged"
+	StatusCreated   = "created"
+	StatusModified  = "modified"
+	StatusDeleted   = "deleted"
+)
+
+// DiffResult describes how a live object differs from its desired state
+type DiffResult struct {
+	Status  string   `json:"status"`
+	Changes []string `json:"changes"`
+}
+
+// ignoredPaths are fields populated by the API server that never appear in desired manifests
+var ignoredPaths = map[string]bool{
+	"status":                     true,
+	"metadata.uid":               true,
+	"metadata.resourceVersion":   true,
+	"metadata.generation":        true,
+	"metadata.creationTimestamp": true,
+	"metadata.managedFields":     true,
+	"metadata.selfLink":          true,
+}
+
+// ComputeDiff compares the desired object with the live one. A nil live object means the
+// resource would be created and a nil desired object means it would be deleted.
+// Only the fields of the desired object are compared: fields that exist only on the live
+// object, such as defaults filled in by the API server or fields set by other controllers,
+// are left alone by an apply and not reported. The exception is list items past the end of
+// the desired list, which an apply removes.
+// Changes are sorted lines of the form "+ path: value", "- path: value" and "~ path: old -> new".
+func ComputeDiff(desired, live map[string]interface{}) DiffResult {
+	switch {
+	case live == nil && desired == nil:
+		return DiffResult{Status: StatusUnchanged, Changes: []string{}}
+	case live == nil:
+		return DiffResult{Status: StatusCreated, Changes: []string{}}
+	case desired == nil:
+		return DiffResult{Status: StatusDeleted, Changes: []string{}}
+	}
+
+	changes := make([]string, 0)
+	compare("", normalize(desired), normalize(live), &changes)
+	sort.Strings(changes)
+
+	if len(changes) == 0 {
+		return DiffResult{Status: StatusUnchanged, Changes: changes}
+	}
+	return DiffResult{Status: StatusModified, Changes: changes}
+}
+
+func compare(path string, desired, live interface{}, changes *[]string) {
+	if ignoredPaths[path] {
+		return
+	}
+
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	liveMap, liveIsMap := live.(map[string]interface{})
+	if desiredIsMap && liveIsMap {
+		for key, value := range desiredMap {
+			childPath := joinPath(path, key)
+			liveValue, ok := liveMap[key]
+			if !ok {
+				if !ignoredPaths[childPath] {
+					*changes = append(*changes, fmt.Sprintf("+ %s: %s", childPath, format(value)))
+				}
+				continue
+			}
+			compare(childPath, value, liveValue, changes)
+		}
+		return
+	}
+
+	desiredList, desiredIsList := desired.([]interface{})
+	liveList, liveIsList := live.([]interface{})
+	if desiredIsList && liveIsList {
+		for i := 0; i < len(desiredList) || i < len(liveList); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(liveList):
+				*changes = append(*changes, fmt.Sprintf("+ %s: %s", childPath, format(desiredList[i])))
+			case i >= len(desiredList):
+				*changes = append(*changes, fmt.Sprintf("- %s: %s", childPath, format(liveList[i])))
+			default:
+				compare(childPath, desiredList[i], liveList[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(desired, live) {
+		*changes = append(*changes, fmt.Sprintf("~ %s: %s -> %s", path, format(live), format(desired)))
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// normalize converts all numeric values to float64 so YAML ints and JSON numbers compare equal
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = normalize(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s: %s", key, format(v[key])))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, format(item))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func deployment(namespace string, replicas interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web",
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+	}
+}
+
+func TestComputeDiff(t *testing.T) {
+	live := deployment("default", int64(1))
+	live["status"] = map[string]interface{}{"readyReplicas": int64(1)}
+	live["metadata"].(map[string]interface{})["uid"] = "1234"
+
+	tests := []struct {
+		name        string
+		desired     map[string]interface{}
+		live        map[string]interface{}
+		wantStatus  string
+		wantChanges []string
+	}{
+		{
+			name:        "unchanged ignores server fields and numeric types",
+			desired:     deployment("default", 1),
+			live:        live,
+			wantStatus:  StatusUnchanged,
+			wantChanges: []string{},
+		},
+		{
+			name:        "modified namespace",
+			desired:     deployment("production", 1),
+			live:        live,
+			wantStatus:  StatusModified,
+			wantChanges: []string{`~ metadata.namespace: "default" -> "production"`},
+		},
+		{
+			name:        "changed replica count",
+			desired:     deployment("default", 3),
+			live:        live,
+			wantStatus:  StatusModified,
+			wantChanges: []string{"~ spec.replicas: 1 -> 3"},
+		},
+		{
+			name:        "new resource",
+			desired:     deployment("default", 1),
+			live:        nil,
+			wantStatus:  StatusCreated,
+			wantChanges: []string{},
+		},
+		{
+			name:        "deleted resource",
+			desired:     nil,
+			live:        live,
+			wantStatus:  StatusDeleted,
+			wantChanges: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeDiff(tt.desired, tt.live)
+			if got.Status != tt.wantStatus {
+				t.Errorf("ComputeDiff() status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(got.Changes, tt.wantChanges) {
+				t.Errorf("ComputeDiff() changes = %v, want %v", got.Changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestComputeDiff_IgnoresLiveOnlyFields(t *testing.T) {
+	desired := deployment("default", 2)
+	live := deployment("default", int64(2))
+	live["spec"].(map[string]interface{})["strategy"] = map[string]interface{}{"type": "RollingUpdate"}
+	live["spec"].(map[string]interface{})["revisionHistoryLimit"] = int64(10)
+	live["metadata"].(map[string]interface{})["annotations"] = map[string]interface{}{
+		"deployment.kubernetes.io/revision": "3",
+	}
+
+	got := ComputeDiff(desired, live)
+	if got.Status != StatusUnchanged || len(got.Changes) != 0 {
+		t.Errorf("ComputeDiff() = %+v, want unchanged despite server defaulted fields", got)
+	}
+
+	// List items the desired list no longer has are still removed by an apply
+	desired["spec"].(map[string]interface{})["args"] = []interface{}{"--a"}
+	live["spec"].(map[string]interface{})["args"] = []interface{}{"--a", "--b"}
+	got = ComputeDiff(desired, live)
+	if want := []string{`- spec.args[1]: "--b"`}; !reflect.DeepEqual(got.Changes, want) {
+		t.Errorf("ComputeDiff() changes = %v, want %v", got.Changes, want)
+	}
+}
+
+func TestComputeDiff_AddedAndRemovedFields(t *testing.T) {
+	desired := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"ports": []interface{}{80, 443},
+		},
+	}
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"note": "x"},
+		},
+		"spec": map[string]interface{}{
+			"ports": []interface{}{int64(80)},
+		},
+	}
+
+	got := ComputeDiff(desired, live)
+	want := []string{
+		`+ metadata.labels: {app: "web"}`,
+		"+ spec.ports[1]: 443",
+	}
+	if got.Status != StatusModified {
+		t.Errorf("ComputeDiff() status = %q, want %q", got.Status, StatusModified)
+	}
+	if !reflect.DeepEqual(got.Changes, want) {
+		t.Errorf("ComputeDiff() changes = %v, want %v", got.Changes, want)
+	}
+}
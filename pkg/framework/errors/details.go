@@ -0,0 +1,57 @@
+package errors
+
+// DetailedError attaches machine-readable context to an error, reported as the details of API error responses
+type DetailedError struct {
+	Err     error
+	Details map[string]interface{}
+}
+
+func (e *DetailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DetailedError) Unwrap() error {
+	return e.Err
+}
+
+// WithDetails attaches details to err, returning err unchanged when it is nil or details is empty
+func WithDetails(err error, details map[string]interface{}) error {
+	if err == nil || len(details) == 0 {
+		return err
+	}
+	return &DetailedError{Err: err, Details: details}
+}
+
+// Details merges the details of every DetailedError in the chain of err, outer values taking
+// precedence; it returns nil when the chain carries no details
+func Details(err error) map[string]interface{} {
+	var merged map[string]interface{}
+	collectDetails(err, func(details map[string]interface{}) {
+		if merged == nil {
+			merged = make(map[string]interface{}, len(details))
+		}
+		for k, v := range details {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+	})
+	return merged
+}
+
+func collectDetails(err error, visit func(map[string]interface{})) {
+	if err == nil {
+		return
+	}
+	if detailed, ok := err.(*DetailedError); ok {
+		visit(detailed.Details)
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		collectDetails(u.Unwrap(), visit)
+	case interface{ Unwrap() []error }:
+		for _, inner := range u.Unwrap() {
+			collectDetails(inner, visit)
+		}
+	}
+}
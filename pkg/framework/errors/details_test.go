@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithDetails(t *testing.T) {
+	base := errors.New("apply failed")
+	err := WithDetails(WrapKubernetes(base, "apply deployment"), map[string]interface{}{"key": "default/Deployment/app"})
+
+	if !errors.Is(err, ErrKubernetes) || !errors.Is(err, base) {
+		t.Error("WithDetails() should preserve the wrapped chain")
+	}
+	if err.Error() != WrapKubernetes(base, "apply deployment").Error() {
+		t.Errorf("WithDetails() should not change the message, got %q", err.Error())
+	}
+	if WithDetails(nil, map[string]interface{}{"key": "x"}) != nil {
+		t.Error("WithDetails() should return nil for nil error")
+	}
+	if WithDetails(base, nil) != base {
+		t.Error("WithDetails() should return err unchanged without details")
+	}
+}
+
+func TestDetails(t *testing.T) {
+	inner := WithDetails(errors.New("boom"), map[string]interface{}{"key": "inner", "http_code": 422})
+	outer := WithDetails(fmt.Errorf("%w: reconcile: %w", ErrKubernetes, inner), map[string]interface{}{"key": "outer"})
+
+	details := Details(outer)
+	if details["key"] != "outer" {
+		t.Errorf("Details()[key] = %v, want outer", details["key"])
+	}
+	if details["http_code"] != 422 {
+		t.Errorf("Details()[http_code] = %v, want 422", details["http_code"])
+	}
+
+	if Details(errors.New("plain")) != nil {
+		t.Error("Details() should be nil when no error in the chain carries details")
+	}
+	if Details(nil) != nil {
+		t.Error("Details() should be nil for nil error")
+	}
+}
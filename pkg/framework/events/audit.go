@@ -0,0 +1,89 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// auditPrefix is the key prefix of the audit bucket
+const auditPrefix = "audit/"
+
+func (s *Storage) StoreAuditEntry(entry AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return apperrors.WrapStorage(err, "failed to marshal audit entry")
+	}
+
+	key := fmt.Sprintf("%s%020d/%s", auditPrefix, entry.Timestamp.UnixNano(), entry.ID)
+	if err := s.db.Set(key, data); err != nil {
+		return apperrors.WrapStorage(err, "failed to store audit entry")
+	}
+	return nil
+}
+
+// ListAuditEntries returns audit entries matching the filters, oldest first. Keys sort by
+// timestamp, so the scan starts at the since bound and stops at the until bound or the limit.
+func (s *Storage) ListAuditEntries(filters AuditFilters) ([]AuditEntry, error) {
+	start := ""
+	if !filters.Since.IsZero() {
+		start = fmt.Sprintf("%020d", filters.Since.UnixNano())
+	}
+
+	entries := []AuditEntry{}
+	err := s.db.Scan(auditPrefix, start, func(key string, data []byte) bool {
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			s.logger.Error(err, "failed to unmarshal audit entry", "key", key)
+			return true
+		}
+
+		if !filters.Until.IsZero() && entry.Timestamp.After(filters.Until) {
+			return false
+		}
+		if filters.Action != "" && entry.Action != filters.Action {
+			return true
+		}
+		entries = append(entries, entry)
+		return filters.Limit <= 0 || len(entries) < filters.Limit
+	})
+	if err != nil {
+		return nil, apperrors.WrapStorage(err, "failed to list audit entries")
+	}
+
+	return entries, nil
+}
+
+func (s *Storage) cleanupOldAuditEntries(before time.Time) (int, error) {
+	allItems, err := s.db.List(auditPrefix)
+	if err != nil {
+		return 0, apperrors.WrapStorage(err, "failed to list audit entries for cleanup")
+	}
+
+	var keysToDelete []string
+	for key, data := range allItems {
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.Timestamp.Before(before) {
+			keysToDelete = append(keysToDelete, key)
+		}
+	}
+
+	if len(keysToDelete) == 0 {
+		return 0, nil
+	}
+	if err := s.db.BatchDelete(keysToDelete); err != nil {
+		return 0, apperrors.WrapStorage(err, "failed to delete audit entries")
+	}
+	return len(keysToDelete), nil
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStorage_AuditEntries(t *testing.T) {
+	_, storage := setupTestEventDB(t)
+
+	now := time.Now()
+	entries := []AuditEntry{
+		{Timestamp: now.Add(-2 * time.Hour), Action: "POST /api/up", StatusCode: 200},
+		{Timestamp: now.Add(-1 * time.Hour), Action: "POST /api/down", StatusCode: 200},
+		{Timestamp: now, Action: "POST /api/up", StatusCode: 500},
+	}
+	for _, entry := range entries {
+		if err := storage.StoreAuditEntry(entry); err != nil {
+			t.Fatalf("StoreAuditEntry() error = %v", err)
+		}
+	}
+
+	all, err := storage.ListAuditEntries(AuditFilters{})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("ListAuditEntries() returned %d entries, want 3", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Timestamp.Before(all[i-1].Timestamp) {
+			t.Errorf("ListAuditEntries() not chronological at index %d", i)
+		}
+	}
+
+	ups, err := storage.ListAuditEntries(AuditFilters{Action: "POST /api/up"})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(ups) != 2 {
+		t.Errorf("ListAuditEntries(action) returned %d entries, want 2", len(ups))
+	}
+
+	recent, err := storage.ListAuditEntries(AuditFilters{Since: now.Add(-90 * time.Minute)})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(recent) != 2 {
+		t.Errorf("ListAuditEntries(since) returned %d entries, want 2", len(recent))
+	}
+
+	window, err := storage.ListAuditEntries(AuditFilters{Since: now.Add(-90 * time.Minute), Until: now.Add(-30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(window) != 1 || window[0].Action != "POST /api/down" {
+		t.Errorf("ListAuditEntries(since, until) = %+v, want only the down entry", window)
+	}
+
+	limited, err := storage.ListAuditEntries(AuditFilters{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(limited) != 2 || !limited[0].Timestamp.Equal(all[0].Timestamp) || !limited[1].Timestamp.Equal(all[1].Timestamp) {
+		t.Errorf("ListAuditEntries(limit) = %+v, want the two oldest entries", limited)
+	}
+
+	if err := storage.CleanupOldEvents(now.Add(-90 * time.Minute)); err != nil {
+		t.Fatalf("CleanupOldEvents() error = %v", err)
+	}
+	remaining, err := storage.ListAuditEntries(AuditFilters{})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() error = %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("ListAuditEntries() after cleanup returned %d entries, want 2", len(remaining))
+	}
+}
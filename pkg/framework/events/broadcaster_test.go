@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBroadcaster_SubscribeAndCancel(t *testing.T) {
+	b := NewBroadcaster()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := b.Subscribe(ctx)
+	b.Publish(Info("", "test", "hello"))
+
+	select {
+	case event := <-ch:
+		if event.Message != "hello" {
+			t.Errorf("event message = %q, want %q", event.Message, "hello")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber did not receive event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("channel delivered an event after cancel, want closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after cancel")
+	}
+
+	// Publishing after all subscribers left must not block or panic
+	b.Publish(Info("", "test", "nobody listening"))
+}
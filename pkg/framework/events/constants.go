@@ -3,3 +3,5 @@ package events
 // DefaultBatchSize is the default batch size for batch operations in event storage
 const DefaultBatchSize = 1000
 
+// DefaultMaxEventsPerResource is the number of events kept per resource key when no limit is configured
+const DefaultMaxEventsPerResource = 1000
@@ -1,6 +1,9 @@
 package events
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 func Success(resourceKey, operation, message string) Event {
 	return Event{
@@ -54,3 +57,35 @@ func Warning(resourceKey, operation, message string) Event {
 	}
 }
 
+// OperationRename is the operation of the event recorded when a manifest key is renamed
+const OperationRename = "rename"
+
+// OperationTrigger is the operation of the event recorded when a webhook triggers a deployment
+const OperationTrigger = "trigger"
+
+// DeploymentTriggered returns the event recorded when a push to branch of repository triggers a deployment
+func DeploymentTriggered(repository, branch, commit string) Event {
+	event := Info("", OperationTrigger, fmt.Sprintf("Deployment triggered by push to %s", branch))
+	event.Details["repository"] = repository
+	event.Details["branch"] = branch
+	event.Details["commit"] = commit
+	return event
+}
+
+// OperationRestore is the operation of the event recorded when a previous version of a manifest is restored
+const OperationRestore = "restore"
+
+// ManifestRestored returns the event recorded when the version of key replaced at timestamp is restored
+func ManifestRestored(key string, timestamp int64) Event {
+	event := Info(key, OperationRestore, fmt.Sprintf("Manifest restored to version %d", timestamp))
+	event.Details["timestamp"] = timestamp
+	return event
+}
+
+// ManifestRenamed returns the event that links the history of oldKey to the manifest now stored under newKey
+func ManifestRenamed(oldKey, newKey string) Event {
+	event := Info(newKey, OperationRename, fmt.Sprintf("Manifest renamed from %s to %s", oldKey, newKey))
+	event.Details["old_key"] = oldKey
+	event.Details["new_key"] = newKey
+	return event
+}
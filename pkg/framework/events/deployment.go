@@ -0,0 +1,172 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// deploymentIndexPrefix indexes events by the deployment that emitted them
+const deploymentIndexPrefix = "events/by-deployment/"
+
+// OperationDeployment is the operation of the events that open and close a deployment session
+const OperationDeployment = "deployment"
+
+const (
+	DeploymentStatusSuccess = "success"
+	DeploymentStatusPartial = "partial"
+	DeploymentStatusFailed  = "failed"
+)
+
+// DeploymentSession summarises the events emitted by one Up, Down or Update call
+type DeploymentSession struct {
+	ID           string    `json:"id"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	TriggeredBy  string    `json:"triggered_by"`
+	ServiceCount int       `json:"service_count"`
+	Status       string    `json:"status"`
+}
+
+type deploymentIDKey struct{}
+
+// WithDeploymentID returns a context whose events are stamped with deploymentID
+func WithDeploymentID(ctx context.Context, deploymentID string) context.Context {
+	return context.WithValue(ctx, deploymentIDKey{}, deploymentID)
+}
+
+// DeploymentIDFromContext returns the deployment ID set by WithDeploymentID, if any
+func DeploymentIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(deploymentIDKey{}).(string)
+	return id
+}
+
+// StoreEventSafeContext stamps event with the deployment and request IDs carried by ctx
+// and stores it like StoreEventSafe
+func StoreEventSafeContext(ctx context.Context, storage EventStorage, logger logr.Logger, event Event) {
+	if event.DeploymentID == "" {
+		event.DeploymentID = DeploymentIDFromContext(ctx)
+	}
+	if event.RequestID == "" {
+		event.RequestID = RequestIDFromContext(ctx)
+	}
+	StoreEventSafe(storage, logger, event)
+}
+
+// DeploymentStarted returns the event that opens a deployment session
+func DeploymentStarted(deploymentID, triggeredBy string, serviceCount int) Event {
+	event := Info("", OperationDeployment, fmt.Sprintf("Deployment started by %s", triggeredBy))
+	event.DeploymentID = deploymentID
+	event.Details["triggeredBy"] = triggeredBy
+	event.Details["serviceCount"] = serviceCount
+	return event
+}
+
+// ListDeploymentSessions groups events by deployment ID and returns one page of sessions,
+// newest first. Pages start at 1. A non-empty namespace keeps only sessions that touched
+// a resource in that namespace.
+func (s *Storage) ListDeploymentSessions(ctx context.Context, namespace string, page, pageSize int) ([]DeploymentSession, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	items, err := s.db.List(deploymentIndexPrefix)
+	if err != nil {
+		return nil, apperrors.WrapStorage(err, "failed to list deployment events")
+	}
+
+	grouped := make(map[string][]Event)
+	for key, data := range items {
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			s.logger.Error(err, "failed to unmarshal event", "key", key)
+			continue
+		}
+		grouped[event.DeploymentID] = append(grouped[event.DeploymentID], event)
+	}
+
+	sessions := make([]DeploymentSession, 0, len(grouped))
+	for id, sessionEvents := range grouped {
+		if namespace != "" && !touchesNamespace(sessionEvents, namespace) {
+			continue
+		}
+		sessions = append(sessions, summariseDeployment(id, sessionEvents))
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].StartedAt.Equal(sessions[j].StartedAt) {
+			return sessions[i].ID > sessions[j].ID
+		}
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+
+	// Checked before multiplying so a huge page cannot overflow the offset
+	if page > (len(sessions)+pageSize-1)/pageSize {
+		return []DeploymentSession{}, nil
+	}
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if end > len(sessions) {
+		end = len(sessions)
+	}
+	return sessions[start:end], nil
+}
+
+func touchesNamespace(sessionEvents []Event, namespace string) bool {
+	for _, event := range sessionEvents {
+		if strings.HasPrefix(event.ResourceKey, namespace+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// summariseDeployment derives a session from its events. The session failed when it
+// has errors and no resource succeeded, and is partial when it has both.
+func summariseDeployment(id string, sessionEvents []Event) DeploymentSession {
+	session := DeploymentSession{ID: id}
+	errorCount, resourceSuccesses := 0, 0
+
+	for _, event := range sessionEvents {
+		if session.StartedAt.IsZero() || event.Timestamp.Before(session.StartedAt) {
+			session.StartedAt = event.Timestamp
+		}
+		if event.Timestamp.After(session.FinishedAt) {
+			session.FinishedAt = event.Timestamp
+		}
+
+		if triggeredBy, ok := event.Details["triggeredBy"].(string); ok {
+			session.TriggeredBy = triggeredBy
+		}
+		if serviceCount, ok := event.Details["serviceCount"].(float64); ok {
+			session.ServiceCount = int(serviceCount)
+		}
+
+		switch {
+		case event.Type == EventTypeError:
+			errorCount++
+		case event.Type == EventTypeSuccess && event.ResourceKey != "":
+			resourceSuccesses++
+		}
+	}
+
+	switch {
+	case errorCount == 0:
+		session.Status = DeploymentStatusSuccess
+	case resourceSuccesses == 0:
+		session.Status = DeploymentStatusFailed
+	default:
+		session.Status = DeploymentStatusPartial
+	}
+	return session
+}
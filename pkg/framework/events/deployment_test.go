@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// storeDeployment stores a session started at start whose resources succeed or fail as given
+func storeDeployment(t *testing.T, storage EventStorage, id, triggeredBy string, start time.Time, results map[string]bool) {
+	t.Helper()
+	started := DeploymentStarted(id, triggeredBy, len(results))
+	started.Timestamp = start
+
+	batch := []Event{started}
+	offset := time.Second
+	for key, ok := range results {
+		event := Success(key, "apply", "Successfully applied manifest")
+		if !ok {
+			event = Error(key, "apply", "Failed to apply object", errors.New("boom"))
+		}
+		event.DeploymentID = id
+		event.Timestamp = start.Add(offset)
+		offset += time.Second
+		batch = append(batch, event)
+	}
+	if err := storage.StoreEventsBatch(batch); err != nil {
+		t.Fatalf("StoreEventsBatch() error = %v", err)
+	}
+}
+
+func TestStorage_ListDeploymentSessions(t *testing.T) {
+	_, storage := setupTestEventDB(t)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour)
+	storeDeployment(t, storage, "first", "up", base, map[string]bool{"default/Deployment/a": true})
+	storeDeployment(t, storage, "second", "update", base.Add(10*time.Minute), map[string]bool{"default/Deployment/a": true, "prod/Deployment/b": false})
+	storeDeployment(t, storage, "third", "down", base.Add(20*time.Minute), map[string]bool{"prod/Deployment/b": false})
+
+	// Events outside a deployment are not sessions
+	if err := storage.StoreEvent(Info("", "reconcile", "Reconciliation started")); err != nil {
+		t.Fatalf("StoreEvent() error = %v", err)
+	}
+
+	sessions, err := storage.ListDeploymentSessions(ctx, "", 1, 2)
+	if err != nil {
+		t.Fatalf("ListDeploymentSessions() error = %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "third" || sessions[1].ID != "second" {
+		t.Fatalf("ListDeploymentSessions() page 1 = %+v, want third then second", sessions)
+	}
+
+	third, second := sessions[0], sessions[1]
+	if third.TriggeredBy != "down" || third.Status != DeploymentStatusFailed || third.ServiceCount != 1 {
+		t.Errorf("third session = %+v, want down/failed/1 service", third)
+	}
+	if second.Status != DeploymentStatusPartial || second.ServiceCount != 2 {
+		t.Errorf("second session = %+v, want partial/2 services", second)
+	}
+	if !second.FinishedAt.After(second.StartedAt) {
+		t.Errorf("second session finished_at %v is not after started_at %v", second.FinishedAt, second.StartedAt)
+	}
+
+	sessions, err = storage.ListDeploymentSessions(ctx, "", 2, 2)
+	if err != nil {
+		t.Fatalf("ListDeploymentSessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "first" || sessions[0].Status != DeploymentStatusSuccess {
+		t.Fatalf("ListDeploymentSessions() page 2 = %+v, want only the successful first session", sessions)
+	}
+
+	sessions, err = storage.ListDeploymentSessions(ctx, "", 3, 2)
+	if err != nil {
+		t.Fatalf("ListDeploymentSessions() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("ListDeploymentSessions() page 3 = %+v, want empty", sessions)
+	}
+
+	// (page-1)*pageSize overflows int for this page
+	sessions, err = storage.ListDeploymentSessions(ctx, "", 36893488147419104, 500)
+	if err != nil {
+		t.Fatalf("ListDeploymentSessions() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("ListDeploymentSessions() huge page = %+v, want empty", sessions)
+	}
+
+	sessions, err = storage.ListDeploymentSessions(ctx, "prod", 1, 20)
+	if err != nil {
+		t.Fatalf("ListDeploymentSessions() error = %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "third" || sessions[1].ID != "second" {
+		t.Errorf("ListDeploymentSessions(prod) = %+v, want third and second", sessions)
+	}
+}
+
+func TestStoreEventSafeContext_StampsDeploymentID(t *testing.T) {
+	_, storage := setupTestEventDB(t)
+
+	ctx := WithDeploymentID(context.Background(), "abc")
+	StoreEventSafeContext(ctx, storage, logr.Discard(), Success("default/Service/a", "apply", "applied"))
+
+	stored, err := storage.GetEventsByResource("default/Service/a", EventFilters{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(stored) != 1 || stored[0].DeploymentID != "abc" {
+		t.Fatalf("stored events = %+v, want one event with deployment ID abc", stored)
+	}
+}
+
+func TestStoreEventSafeContext_StampsRequestID(t *testing.T) {
+	_, storage := setupTestEventDB(t)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	StoreEventSafeContext(ctx, storage, logr.Discard(), Success("default/Service/a", "apply", "applied"))
+
+	stored, err := storage.GetEventsByResource("default/Service/a", EventFilters{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(stored) != 1 || stored[0].RequestID != "req-1" {
+		t.Fatalf("stored events = %+v, want one event with request ID req-1", stored)
+	}
+}
@@ -1,6 +1,9 @@
 package events
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // EventStorage defines the interface for event storage operations.
 // This interface allows for better testability and reduced coupling.
@@ -14,17 +17,35 @@ type EventStorage interface {
 	// ListEvents lists events matching the provided filters
 	ListEvents(filters EventFilters) ([]Event, error)
 
-	// GetEventsByResource retrieves events for a specific resource key
-	GetEventsByResource(key string, limit int) ([]Event, error)
+	// GetEventsByResource retrieves events for a specific resource key matching the remaining filters
+	GetEventsByResource(key string, filters EventFilters) ([]Event, error)
 
-	// GetRecentErrors retrieves recent error events
-	GetRecentErrors(limit int) ([]Event, error)
+	// GetRecentErrors retrieves recent error events, or the events of filters.Severities when set
+	GetRecentErrors(filters EventFilters) ([]Event, error)
 
 	// CleanupOldEvents removes events older than the specified time
 	CleanupOldEvents(before time.Time) error
 
 	// DeleteEvent deletes a specific event by ID and timestamp
 	DeleteEvent(id string, timestamp time.Time) error
+
+	// TrimByResource deletes the oldest events of a resource until at most maxEvents remain
+	TrimByResource(key string, maxEvents int) (int, error)
+
+	// TrimAll trims the events of every resource to maxEvents, or to the configured limit when zero
+	TrimAll(maxEvents int) (int, error)
+
+	// Subscribe streams every event stored after the call until ctx is cancelled
+	Subscribe(ctx context.Context) <-chan Event
+
+	// StoreAuditEntry stores a single audit log entry
+	StoreAuditEntry(entry AuditEntry) error
+
+	// ListAuditEntries lists audit entries matching the provided filters in chronological order
+	ListAuditEntries(filters AuditFilters) ([]AuditEntry, error)
+
+	// ListDeploymentSessions returns one page of deployment sessions, newest first
+	ListDeploymentSessions(ctx context.Context, namespace string, page, pageSize int) ([]DeploymentSession, error)
 }
 
 // Ensure *Storage implements EventStorage interface
@@ -0,0 +1,16 @@
+package events
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose events are stamped with requestID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID set by WithRequestID, if any
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -1,9 +1,12 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -16,13 +19,46 @@ import (
 type Storage struct {
 	db     *database.DB
 	logger logr.Logger
+
+	broadcaster *Broadcaster
+
+	maxEventsPerResource int
+	maxTotalEvents       int
+
+	// limitMu serializes writes with limits and guards counts
+	limitMu sync.Mutex
+	counts  eventCounts
+}
+
+// StorageOption configures optional Storage behaviour
+type StorageOption func(*Storage)
+
+// WithMaxEventsPerResource caps the number of events kept per resource key.
+// Writing past the cap evicts the oldest events for that resource. Zero means unlimited.
+func WithMaxEventsPerResource(n int) StorageOption {
+	return func(s *Storage) {
+		s.maxEventsPerResource = n
+	}
+}
+
+// WithMaxTotalEvents caps the total number of events kept.
+// Writing past the cap evicts the oldest events overall. Zero means unlimited.
+func WithMaxTotalEvents(n int) StorageOption {
+	return func(s *Storage) {
+		s.maxTotalEvents = n
+	}
 }
 
-func NewStorage(db *database.DB, logger logr.Logger) EventStorage {
-	return &Storage{
-		db:     db,
-		logger: logger,
+func NewStorage(db *database.DB, logger logr.Logger, opts ...StorageOption) *Storage {
+	s := &Storage{
+		db:          db,
+		logger:      logger,
+		broadcaster: NewBroadcaster(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Storage) StoreEvent(event Event) error {
@@ -39,6 +75,15 @@ func (s *Storage) StoreEvent(event Event) error {
 	}
 
 	timestampKey := fmt.Sprintf("events/%020d/%s", event.Timestamp.UnixNano(), event.ID)
+
+	if s.hasLimits() {
+		if err := s.writeWithEviction(eventKeys(event, data), []Event{event}); err != nil {
+			return apperrors.WrapStorage(err, "failed to store event")
+		}
+		s.broadcaster.Publish(event)
+		return nil
+	}
+
 	if err := s.db.Set(timestampKey, data); err != nil {
 		return apperrors.WrapStorage(err, "failed to store event")
 	}
@@ -57,6 +102,14 @@ func (s *Storage) StoreEvent(event Event) error {
 
 	}
 
+	if event.DeploymentID != "" {
+		deploymentKey := fmt.Sprintf("%s%s/%020d/%s", deploymentIndexPrefix, event.DeploymentID, event.Timestamp.UnixNano(), event.ID)
+		if err := s.db.Set(deploymentKey, data); err != nil {
+			s.logger.Error(err, "failed to store deployment index", "key", deploymentKey)
+		}
+	}
+
+	s.broadcaster.Publish(event)
 	return nil
 }
 
@@ -66,6 +119,7 @@ func (s *Storage) StoreEventsBatch(events []Event) error {
 	}
 
 	batchItems := make(map[string][]byte)
+	stored := make([]Event, 0, len(events))
 
 	for _, event := range events {
 
@@ -92,15 +146,41 @@ func (s *Storage) StoreEventsBatch(events []Event) error {
 
 		typeKey := fmt.Sprintf("events/by-type/%s/%020d/%s", event.Type, event.Timestamp.UnixNano(), event.ID)
 		batchItems[typeKey] = data
+
+		if event.DeploymentID != "" {
+			deploymentKey := fmt.Sprintf("%s%s/%020d/%s", deploymentIndexPrefix, event.DeploymentID, event.Timestamp.UnixNano(), event.ID)
+			batchItems[deploymentKey] = data
+		}
+		stored = append(stored, event)
+	}
+
+	if s.hasLimits() {
+		if err := s.writeWithEviction(batchItems, stored); err != nil {
+			return apperrors.WrapStorage(err, "failed to store events batch")
+		}
+		s.publishAll(stored)
+		return nil
 	}
 
 	if err := s.db.BatchSet(batchItems); err != nil {
 		return apperrors.WrapStorage(err, "failed to store events batch")
 	}
 
+	s.publishAll(stored)
 	return nil
 }
 
+// Subscribe streams every event stored after the call until ctx is cancelled
+func (s *Storage) Subscribe(ctx context.Context) <-chan Event {
+	return s.broadcaster.Subscribe(ctx)
+}
+
+func (s *Storage) publishAll(events []Event) {
+	for _, event := range events {
+		s.broadcaster.Publish(event)
+	}
+}
+
 func (s *Storage) ListEvents(filters EventFilters) ([]Event, error) {
 	var prefix string
 
@@ -108,6 +188,8 @@ func (s *Storage) ListEvents(filters EventFilters) ([]Event, error) {
 		prefix = fmt.Sprintf("events/by-resource/%s/", filters.ResourceKey)
 	} else if filters.Type != "" {
 		prefix = fmt.Sprintf("events/by-type/%s/", filters.Type)
+	} else if len(filters.Severities) == 1 {
+		prefix = fmt.Sprintf("events/by-type/%s/", filters.Severities[0])
 	} else {
 		prefix = "events/"
 	}
@@ -118,7 +200,11 @@ func (s *Storage) ListEvents(filters EventFilters) ([]Event, error) {
 	}
 
 	var events []Event
-	for _, data := range allItems {
+	for key, data := range allItems {
+		if prefix == "events/" && isEventIndexKey(key) {
+			continue
+		}
+
 		var event Event
 		if err := json.Unmarshal(data, &event); err != nil {
 			s.logger.Error(err, "failed to unmarshal event", "key", prefix)
@@ -128,9 +214,15 @@ func (s *Storage) ListEvents(filters EventFilters) ([]Event, error) {
 		if filters.ResourceKey != "" && event.ResourceKey != filters.ResourceKey {
 			continue
 		}
+		if filters.ResourcePrefix != "" && !strings.HasPrefix(event.ResourceKey, filters.ResourcePrefix) {
+			continue
+		}
 		if filters.Type != "" && event.Type != filters.Type {
 			continue
 		}
+		if !filters.hasSeverity(event.Type) {
+			continue
+		}
 		if !filters.Since.IsZero() && event.Timestamp.Before(filters.Since) {
 			continue
 		}
@@ -167,18 +259,17 @@ func (s *Storage) ListEvents(filters EventFilters) ([]Event, error) {
 	return events, nil
 }
 
-func (s *Storage) GetEventsByResource(key string, limit int) ([]Event, error) {
-	filters := EventFilters{
-		ResourceKey: key,
-		Limit:       limit,
-	}
+// GetEventsByResource lists the events of the resource key that match the remaining filters
+func (s *Storage) GetEventsByResource(key string, filters EventFilters) ([]Event, error) {
+	filters.ResourceKey = key
 	return s.ListEvents(filters)
 }
 
-func (s *Storage) GetRecentErrors(limit int) ([]Event, error) {
-	filters := EventFilters{
-		Type:  EventTypeError,
-		Limit: limit,
+// GetRecentErrors lists the error events matching filters. Severities widens the
+// selection to other types, e.g. errors and warnings.
+func (s *Storage) GetRecentErrors(filters EventFilters) ([]Event, error) {
+	if filters.Type == "" && len(filters.Severities) == 0 {
+		filters.Severities = []EventType{EventTypeError}
 	}
 	return s.ListEvents(filters)
 }
@@ -3,7 +3,6 @@ package events
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
@@ -26,7 +25,7 @@ func (s *Storage) CleanupOldEvents(before time.Time) error {
 
 	for key, data := range allItems {
 
-		if strings.Contains(key, "/by-resource/") || strings.Contains(key, "/by-type/") {
+		if isEventIndexKey(key) {
 			continue
 		}
 
@@ -71,27 +70,35 @@ func (s *Storage) CleanupOldEvents(before time.Time) error {
 
 			typeKey := fmt.Sprintf("events/by-type/%s/%020d/%s", ek.event.Type, eventTimestamp, ek.event.ID)
 			keysToDelete = append(keysToDelete, typeKey)
+
+			if ek.event.DeploymentID != "" {
+				deploymentKey := fmt.Sprintf("%s%s/%020d/%s", deploymentIndexPrefix, ek.event.DeploymentID, eventTimestamp, ek.event.ID)
+				keysToDelete = append(keysToDelete, deploymentKey)
+			}
 		}
 
 		if len(keysToDelete) > 0 {
-			if err := s.db.BatchDelete(keysToDelete); err != nil {
-				s.logger.Error(err, "failed to batch delete events", "count", len(keysToDelete))
-
-				for _, key := range keysToDelete {
-					if err := s.db.Delete(key); err != nil {
-
-						if strings.Contains(key, "/by-resource/") || strings.Contains(key, "/by-type/") {
-							s.logger.V(1).Info("failed to delete event index entry (non-critical)", "key", key, "error", err)
+			s.deleteOutsideEviction(func() error {
+				if err := s.db.BatchDelete(keysToDelete); err != nil {
+					s.logger.Error(err, "failed to batch delete events", "count", len(keysToDelete))
+
+					for _, key := range keysToDelete {
+						if err := s.db.Delete(key); err != nil {
+
+							if isEventIndexKey(key) {
+								s.logger.V(1).Info("failed to delete event index entry (non-critical)", "key", key, "error", err)
+							} else {
+								s.logger.Error(err, "failed to delete event", "key", key)
+							}
 						} else {
-							s.logger.Error(err, "failed to delete event", "key", key)
+							deletedCount++
 						}
-					} else {
-						deletedCount++
 					}
+				} else {
+					deletedCount += len(keysToDelete)
 				}
-			} else {
-				deletedCount += len(keysToDelete)
-			}
+				return nil
+			})
 		}
 
 		if (i+DefaultBatchSize)%10000 == 0 || end == len(oldEvents) {
@@ -99,13 +106,20 @@ func (s *Storage) CleanupOldEvents(before time.Time) error {
 		}
 	}
 
-	s.logger.Info("Cleaned up old events", "deleted", deletedCount, "processed", totalProcessed, "before", before)
+	auditDeleted, err := s.cleanupOldAuditEntries(before)
+	if err != nil {
+		s.logger.Error(err, "failed to cleanup audit entries")
+	}
+
+	s.logger.Info("Cleaned up old events", "deleted", deletedCount, "processed", totalProcessed, "auditDeleted", auditDeleted, "before", before)
 	return nil
 }
 
 func (s *Storage) DeleteEvent(id string, timestamp time.Time) error {
 
 	timestampKey := fmt.Sprintf("events/%020d/%s", timestamp.UnixNano(), id)
-	return s.db.Delete(timestampKey)
+	return s.deleteOutsideEviction(func() error {
+		return s.db.Delete(timestampKey)
+	})
 }
 
@@ -0,0 +1,246 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+func (s *Storage) hasLimits() bool {
+	return s.maxEventsPerResource > 0 || s.maxTotalEvents > 0
+}
+
+// eventKeys returns the primary and index keys for an event, all holding data
+func eventKeys(event Event, data []byte) map[string][]byte {
+	ts := event.Timestamp.UnixNano()
+	items := map[string][]byte{
+		fmt.Sprintf("events/%020d/%s", ts, event.ID):                        data,
+		fmt.Sprintf("events/by-type/%s/%020d/%s", event.Type, ts, event.ID): data,
+	}
+	if event.ResourceKey != "" {
+		items[fmt.Sprintf("events/by-resource/%s/%020d/%s", event.ResourceKey, ts, event.ID)] = data
+	}
+	if event.DeploymentID != "" {
+		items[fmt.Sprintf("%s%s/%020d/%s", deploymentIndexPrefix, event.DeploymentID, ts, event.ID)] = data
+	}
+	return items
+}
+
+// isEventIndexKey reports whether key is a secondary index entry rather than a primary event key
+func isEventIndexKey(key string) bool {
+	return strings.HasPrefix(key, "events/by-")
+}
+
+// eventCounts tracks how many events are stored in total and per resource, so a write can
+// evict the events over the limits by reading only the oldest keys instead of listing every
+// event. The counts are loaded from the database by the first write with limits and reloaded
+// after events are deleted other than by eviction.
+type eventCounts struct {
+	loaded      bool
+	total       int
+	perResource map[string]int
+}
+
+// add adds delta to the counts of events
+func (c *eventCounts) add(events []Event, delta int) {
+	for _, event := range events {
+		c.total += delta
+		if event.ResourceKey != "" {
+			c.perResource[event.ResourceKey] += delta
+			if c.perResource[event.ResourceKey] <= 0 {
+				delete(c.perResource, event.ResourceKey)
+			}
+		}
+	}
+}
+
+// resourceEventPrefix is the prefix of the by-resource index entries of resourceKey
+func resourceEventPrefix(resourceKey string) string {
+	return fmt.Sprintf("events/by-resource/%s/", resourceKey)
+}
+
+// eventKeySuffix reports whether rest, a key with its prefix removed, is {timestamp}/{id}
+func eventKeySuffix(rest string) bool {
+	return strings.Count(rest, "/") == 1
+}
+
+// loadCounts counts the stored events from their keys. s.limitMu must be held.
+func (s *Storage) loadCounts() error {
+	keys, err := s.db.ListKeys("events/", 0)
+	if err != nil {
+		return apperrors.WrapStorage(err, "failed to count events")
+	}
+
+	counts := eventCounts{loaded: true, perResource: make(map[string]int)}
+	const byResource = "events/by-resource/"
+	for _, key := range keys {
+		switch {
+		case strings.HasPrefix(key, byResource):
+			// events/by-resource/{resourceKey}/{timestamp}/{id}
+			rest := strings.TrimPrefix(key, byResource)
+			end := strings.LastIndex(rest, "/")
+			if end > 0 {
+				end = strings.LastIndex(rest[:end], "/")
+			}
+			if end > 0 {
+				counts.perResource[rest[:end]]++
+			}
+		case !isEventIndexKey(key) && eventKeySuffix(strings.TrimPrefix(key, "events/")):
+			counts.total++
+		}
+	}
+	s.counts = counts
+	return nil
+}
+
+// deleteOutsideEviction runs fn, which deletes events other than by eviction, excluding writes
+// with limits, and makes the next of them reload the counts
+func (s *Storage) deleteOutsideEviction(fn func() error) error {
+	s.limitMu.Lock()
+	defer s.limitMu.Unlock()
+	s.counts.loaded = false
+	return fn()
+}
+
+// writeWithEviction stores items and evicts events over the configured limits in one transaction
+func (s *Storage) writeWithEviction(items map[string][]byte, incoming []Event) error {
+	s.limitMu.Lock()
+	defer s.limitMu.Unlock()
+
+	if !s.counts.loaded {
+		if err := s.loadCounts(); err != nil {
+			return err
+		}
+	}
+
+	var evicted []Event
+	err := s.db.Transaction(func(txn *database.DB) error {
+		var err error
+		evicted, err = s.evictOverLimits(txn, incoming)
+		if err != nil {
+			return err
+		}
+		return txn.BatchSet(items)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.counts.add(incoming, 1)
+	s.counts.add(evicted, -1)
+	if len(evicted) > 0 {
+		s.logger.V(1).Info("evicting events over limit", "count", len(evicted))
+	}
+	return nil
+}
+
+// evictOverLimits deletes the events that must go so that storing incoming keeps the
+// configured limits and returns them. Only existing events are evicted, oldest first.
+// s.limitMu must be held.
+func (s *Storage) evictOverLimits(db *database.DB, incoming []Event) ([]Event, error) {
+	evicted := make(map[string]bool)
+	var result []Event
+	collect := func(prefix string, limit, excess int) error {
+		events, err := evictOldest(db, prefix, limit, excess, evicted)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			evicted[event.ID] = true
+			result = append(result, event)
+		}
+		return nil
+	}
+
+	if s.maxEventsPerResource > 0 {
+		perResource := make(map[string]int)
+		for _, event := range incoming {
+			if event.ResourceKey != "" {
+				perResource[event.ResourceKey]++
+			}
+		}
+		for resourceKey, count := range perResource {
+			excess := s.counts.perResource[resourceKey] + count - s.maxEventsPerResource
+			if excess <= 0 {
+				continue
+			}
+			// The keys of one resource are bounded by the limit, so all of them are read
+			if err := collect(resourceEventPrefix(resourceKey), 0, excess); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if s.maxTotalEvents > 0 {
+		excess := s.counts.total - len(result) + len(incoming) - s.maxTotalEvents
+		if excess > 0 {
+			// Primary keys sort before the index entries, so the first keys are the oldest events
+			if err := collect("events/", excess+len(result), excess); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// evictOldest reads up to limit keys under prefix, oldest first, and deletes the first excess
+// events stored under them that are not in skip, with all their index entries. Index entries
+// are skipped unless prefix itself is an index prefix. It returns the deleted events.
+func evictOldest(db *database.DB, prefix string, limit, excess int, skip map[string]bool) ([]Event, error) {
+	keys, err := db.ListKeys(prefix, limit)
+	if err != nil {
+		return nil, apperrors.WrapStorage(err, "failed to list events for eviction")
+	}
+
+	indexPrefix := isEventIndexKey(prefix)
+	var result []Event
+	for _, key := range keys {
+		if len(result) == excess {
+			break
+		}
+		if (!indexPrefix && isEventIndexKey(key)) || !eventKeySuffix(strings.TrimPrefix(key, prefix)) {
+			continue
+		}
+		data, err := db.Get(key)
+		if err != nil {
+			return nil, apperrors.WrapStorage(err, "failed to read event for eviction")
+		}
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil || event.ID == "" || skip[event.ID] {
+			continue
+		}
+		result = append(result, event)
+	}
+
+	keys = make([]string, 0, len(result)*3)
+	for _, event := range result {
+		for key := range eventKeys(event, nil) {
+			keys = append(keys, key)
+		}
+	}
+	if err := db.BatchDelete(keys); err != nil {
+		return nil, apperrors.WrapStorage(err, "failed to delete events over limit")
+	}
+	return result, nil
+}
+
+// countEvents counts the events under prefix from their keys. Index entries are skipped
+// unless prefix itself is an index prefix.
+func countEvents(db *database.DB, prefix string) (int, error) {
+	keys, err := db.ListKeys(prefix, 0)
+	if err != nil {
+		return 0, apperrors.WrapStorage(err, "failed to count events")
+	}
+
+	indexPrefix := isEventIndexKey(prefix)
+	count := 0
+	for _, key := range keys {
+		if (indexPrefix || !isEventIndexKey(key)) && eventKeySuffix(strings.TrimPrefix(key, prefix)) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,259 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+)
+
+func setupLimitedStorage(t *testing.T, opts ...StorageOption) *Storage {
+	t.Helper()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	return NewStorage(db, logr.Discard(), opts...)
+}
+
+func storeResourceEvents(t *testing.T, storage *Storage, resourceKey string, n int, start time.Time) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		event := Info(resourceKey, "apply", fmt.Sprintf("event %d", i))
+		event.Timestamp = start.Add(time.Duration(i) * time.Second)
+		if err := storage.StoreEvent(event); err != nil {
+			t.Fatalf("StoreEvent() error = %v", err)
+		}
+	}
+}
+
+// countPrimaryEvents counts events by their primary keys, ignoring index entries
+func countPrimaryEvents(t *testing.T, storage *Storage) int {
+	t.Helper()
+	count, err := countEvents(storage.db, "events/")
+	if err != nil {
+		t.Fatalf("countEvents() error = %v", err)
+	}
+	return count
+}
+
+func TestStorage_MaxEventsPerResource(t *testing.T) {
+	const max = 5
+	storage := setupLimitedStorage(t, WithMaxEventsPerResource(max))
+	start := time.Now().Add(-time.Hour)
+
+	storeResourceEvents(t, storage, "default/Deployment/a", max+1, start)
+	storeResourceEvents(t, storage, "default/Deployment/b", 3, start)
+
+	events, err := storage.GetEventsByResource("default/Deployment/a", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(events) != max {
+		t.Fatalf("expected %d events for resource a, got %d", max, len(events))
+	}
+	// The oldest event is evicted; the newest is kept
+	for _, event := range events {
+		if event.Message == "event 0" {
+			t.Error("expected oldest event to be evicted")
+		}
+	}
+	if events[0].Message != fmt.Sprintf("event %d", max) {
+		t.Errorf("expected newest event first, got %q", events[0].Message)
+	}
+
+	others, err := storage.GetEventsByResource("default/Deployment/b", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(others) != 3 {
+		t.Errorf("expected 3 events for resource b, got %d", len(others))
+	}
+
+	// Evicted events are removed from the primary and type indexes too
+	if total := countPrimaryEvents(t, storage); total != max+3 {
+		t.Errorf("expected %d events in total, got %d", max+3, total)
+	}
+	byType, err := storage.ListEvents(EventFilters{Type: EventTypeInfo, Limit: 1000})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(byType) != max+3 {
+		t.Errorf("expected %d info events, got %d", max+3, len(byType))
+	}
+}
+
+func TestStorage_MaxEventsPerResource_Batch(t *testing.T) {
+	const max = 3
+	storage := setupLimitedStorage(t, WithMaxEventsPerResource(max))
+	start := time.Now().Add(-time.Hour)
+
+	storeResourceEvents(t, storage, "default/Service/a", max, start)
+
+	batch := []Event{
+		Info("default/Service/a", "apply", "batch 1"),
+		Info("default/Service/a", "apply", "batch 2"),
+	}
+	if err := storage.StoreEventsBatch(batch); err != nil {
+		t.Fatalf("StoreEventsBatch() error = %v", err)
+	}
+
+	events, err := storage.GetEventsByResource("default/Service/a", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(events) != max {
+		t.Errorf("expected %d events, got %d", max, len(events))
+	}
+}
+
+func TestStorage_MaxTotalEvents(t *testing.T) {
+	const max = 4
+	storage := setupLimitedStorage(t, WithMaxTotalEvents(max))
+	start := time.Now().Add(-time.Hour)
+
+	storeResourceEvents(t, storage, "default/Deployment/a", 3, start)
+	storeResourceEvents(t, storage, "default/Deployment/b", 3, start.Add(time.Minute))
+
+	if total := countPrimaryEvents(t, storage); total != max {
+		t.Fatalf("expected %d events in total, got %d", max, total)
+	}
+
+	remaining, err := storage.GetEventsByResource("default/Deployment/a", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(remaining) != 1 {
+		t.Errorf("expected 1 event left for the oldest resource, got %d", len(remaining))
+	}
+}
+
+func TestStorage_MaxTotalEvents_ConcurrentWrites(t *testing.T) {
+	const max = 20
+	storage := setupLimitedStorage(t, WithMaxTotalEvents(max))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*10)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				errs <- storage.StoreEvent(Info(fmt.Sprintf("default/Deployment/r%d", w), "apply", fmt.Sprintf("event %d", i)))
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	// Evictions are serialized, so concurrent writers never conflict
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("StoreEvent() error = %v", err)
+		}
+	}
+	if got := countPrimaryEvents(t, storage); got != max {
+		t.Errorf("expected %d events after concurrent writes, got %d", max, got)
+	}
+}
+
+func TestStorage_NoLimitsByDefault(t *testing.T) {
+	storage := setupLimitedStorage(t)
+	storeResourceEvents(t, storage, "default/Deployment/a", 20, time.Now().Add(-time.Hour))
+
+	events, err := storage.GetEventsByResource("default/Deployment/a", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(events) != 20 {
+		t.Errorf("expected 20 events, got %d", len(events))
+	}
+}
+
+func TestStorage_LimitsCountEventsStoredBefore(t *testing.T) {
+	const max = 3
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	start := time.Now().Add(-time.Hour)
+
+	// Events stored by an earlier process without limits are counted on the first write
+	storeResourceEvents(t, NewStorage(db, logr.Discard()), "default/Deployment/a", 5, start)
+	storage := NewStorage(db, logr.Discard(), WithMaxEventsPerResource(max))
+	event := Info("default/Deployment/a", "apply", "latest")
+	event.Timestamp = start.Add(time.Minute)
+	if err := storage.StoreEvent(event); err != nil {
+		t.Fatalf("StoreEvent() error = %v", err)
+	}
+
+	events, err := storage.GetEventsByResource("default/Deployment/a", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(events) != max {
+		t.Fatalf("expected %d events for resource a, got %d", max, len(events))
+	}
+}
+
+func TestStorage_LimitsRecountAfterDeletes(t *testing.T) {
+	const max = 3
+	storage := setupLimitedStorage(t, WithMaxEventsPerResource(max))
+	start := time.Now().Add(-time.Hour)
+	storeResourceEvents(t, storage, "default/Deployment/a", max, start)
+
+	// Trimming outside eviction leaves room that later writes use without evicting
+	if _, err := storage.TrimByResource("default/Deployment/a", 1); err != nil {
+		t.Fatalf("TrimByResource() error = %v", err)
+	}
+	storeResourceEvents(t, storage, "default/Deployment/a", max-1, start.Add(time.Minute))
+
+	events, err := storage.GetEventsByResource("default/Deployment/a", EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(events) != max {
+		t.Errorf("expected %d events for resource a, got %d", max, len(events))
+	}
+}
+
+func TestStorage_BothLimitsEvictInOneWrite(t *testing.T) {
+	storage := setupLimitedStorage(t, WithMaxEventsPerResource(2), WithMaxTotalEvents(3))
+	start := time.Now().Add(-time.Hour)
+
+	at := func(resourceKey, message string, offset int) Event {
+		event := Info(resourceKey, "apply", message)
+		event.Timestamp = start.Add(time.Duration(offset) * time.Second)
+		return event
+	}
+	for i, event := range []Event{at("default/Deployment/a", "a0", 0), at("default/Deployment/b", "b0", 1), at("default/Deployment/a", "a1", 2)} {
+		if err := storage.StoreEvent(event); err != nil {
+			t.Fatalf("StoreEvent(%d) error = %v", i, err)
+		}
+	}
+
+	// a0 goes for the per-resource limit, then b0 for the total limit
+	batch := []Event{at("default/Deployment/a", "a2", 3), at("default/Deployment/b", "b1", 4)}
+	if err := storage.StoreEventsBatch(batch); err != nil {
+		t.Fatalf("StoreEventsBatch() error = %v", err)
+	}
+
+	events, err := storage.ListEvents(EventFilters{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	var messages []string
+	for _, event := range events {
+		messages = append(messages, event.Message)
+	}
+	if want := []string{"b1", "a2", "a1"}; fmt.Sprint(messages) != fmt.Sprint(want) {
+		t.Errorf("ListEvents() = %v, want %v", messages, want)
+	}
+	if total := countPrimaryEvents(t, storage); total != 3 {
+		t.Errorf("expected 3 events in total, got %d", total)
+	}
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -216,7 +217,7 @@ func TestStorage_GetEventsByResource(t *testing.T) {
 	storage.StoreEvent(Success("resource1", "apply", "Event 2"))
 	storage.StoreEvent(Success("resource2", "apply", "Event 3"))
 
-	events, err := storage.GetEventsByResource("resource1", 10)
+	events, err := storage.GetEventsByResource("resource1", EventFilters{Limit: 10})
 	if err != nil {
 		t.Fatalf("GetEventsByResource() error = %v", err)
 	}
@@ -241,7 +242,7 @@ func TestStorage_GetRecentErrors(t *testing.T) {
 	storage.StoreEvent(Info("test/key", "reconcile", "Info"))
 	storage.StoreEvent(Error("test/key", "apply", "Error 2", nil))
 
-	events, err := storage.GetRecentErrors(10)
+	events, err := storage.GetRecentErrors(EventFilters{Limit: 10})
 	if err != nil {
 		t.Fatalf("GetRecentErrors() error = %v", err)
 	}
@@ -348,3 +349,77 @@ func TestStorage_CleanupOldEvents_BatchProcessing(t *testing.T) {
 	}
 }
 
+
+func TestStorage_ListEvents_Severities(t *testing.T) {
+	_, storage := setupTestEventDB(t)
+
+	now := time.Now()
+	stored := []Event{
+		{Timestamp: now.Add(-4 * time.Hour), Type: EventTypeInfo, ResourceKey: "default/Service/api", Message: "info"},
+		{Timestamp: now.Add(-3 * time.Hour), Type: EventTypeWarning, ResourceKey: "default/Service/api", Message: "warning"},
+		{Timestamp: now.Add(-2 * time.Hour), Type: EventTypeError, ResourceKey: "default/Service/api", Message: "error"},
+		{Timestamp: now.Add(-1 * time.Hour), Type: EventTypeError, ResourceKey: "apps/Service/web", Message: "old web error"},
+		{Timestamp: now, Type: EventTypeSuccess, ResourceKey: "apps/Service/web", Message: "success"},
+	}
+	for _, event := range stored {
+		if err := storage.StoreEvent(event); err != nil {
+			t.Fatalf("StoreEvent() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filters EventFilters
+		want    []string
+	}{
+		{name: "no filter", filters: EventFilters{}, want: []string{"success", "old web error", "error", "warning", "info"}},
+		{name: "single severity", filters: EventFilters{Severities: []EventType{EventTypeWarning}}, want: []string{"warning"}},
+		{name: "several severities", filters: EventFilters{Severities: []EventType{EventTypeError, EventTypeWarning}}, want: []string{"old web error", "error", "warning"}},
+		{name: "severity and resource prefix", filters: EventFilters{Severities: []EventType{EventTypeError}, ResourcePrefix: "default/"}, want: []string{"error"}},
+		{name: "severity and time range", filters: EventFilters{Severities: []EventType{EventTypeError, EventTypeInfo}, Since: now.Add(-150 * time.Minute), Until: now.Add(-30 * time.Minute)}, want: []string{"old web error", "error"}},
+		{name: "resource prefix", filters: EventFilters{ResourcePrefix: "apps/"}, want: []string{"success", "old web error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := storage.ListEvents(tt.filters)
+			if err != nil {
+				t.Fatalf("ListEvents() error = %v", err)
+			}
+			var got []string
+			for _, event := range events {
+				got = append(got, event.Message)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ListEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("by resource", func(t *testing.T) {
+		events, err := storage.GetEventsByResource("default/Service/api", EventFilters{Severities: []EventType{EventTypeInfo, EventTypeWarning}})
+		if err != nil {
+			t.Fatalf("GetEventsByResource() error = %v", err)
+		}
+		if len(events) != 2 || events[0].Message != "warning" || events[1].Message != "info" {
+			t.Errorf("GetEventsByResource() = %v, want the warning and info events of default/Service/api", events)
+		}
+	})
+
+	t.Run("recent errors", func(t *testing.T) {
+		errorsOnly, err := storage.GetRecentErrors(EventFilters{})
+		if err != nil {
+			t.Fatalf("GetRecentErrors() error = %v", err)
+		}
+		if len(errorsOnly) != 2 {
+			t.Errorf("GetRecentErrors() returned %d events, want the 2 errors", len(errorsOnly))
+		}
+		widened, err := storage.GetRecentErrors(EventFilters{Severities: []EventType{EventTypeError, EventTypeWarning}})
+		if err != nil {
+			t.Fatalf("GetRecentErrors() error = %v", err)
+		}
+		if len(widened) != 3 {
+			t.Errorf("GetRecentErrors() with warnings returned %d events, want 3", len(widened))
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// TrimByResource deletes the oldest events of resource key until at most maxEvents remain,
+// counting and deleting in one transaction. It returns the number of events deleted.
+func (s *Storage) TrimByResource(key string, maxEvents int) (int, error) {
+	if maxEvents < 1 {
+		return 0, fmt.Errorf("%w: maxEvents must be at least 1", apperrors.ErrInvalid)
+	}
+
+	trimmed := 0
+	err := s.deleteOutsideEviction(func() error {
+		return s.db.Transaction(func(txn *database.DB) error {
+			prefix := resourceEventPrefix(key)
+			count, err := countEvents(txn, prefix)
+			if err != nil || count <= maxEvents {
+				return err
+			}
+
+			evicted, err := evictOldest(txn, prefix, 0, count-maxEvents, nil)
+			if err != nil {
+				return err
+			}
+			trimmed = len(evicted)
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, apperrors.WrapStorage(err, "failed to trim events")
+	}
+
+	if trimmed > 0 {
+		s.logger.V(1).Info("trimmed events over limit", "resourceKey", key, "count", trimmed)
+	}
+	return trimmed, nil
+}
+
+// TrimAll runs TrimByResource for every resource with stored events. A maxEvents of zero
+// selects the configured per-resource limit, or DefaultMaxEventsPerResource when none is set.
+// It returns the total number of events deleted.
+func (s *Storage) TrimAll(maxEvents int) (int, error) {
+	if maxEvents == 0 {
+		maxEvents = s.maxEventsPerResource
+		if maxEvents == 0 {
+			maxEvents = DefaultMaxEventsPerResource
+		}
+	}
+
+	resourceKeys, err := s.resourceKeys()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, key := range resourceKeys {
+		trimmed, err := s.TrimByResource(key, maxEvents)
+		if err != nil {
+			return total, err
+		}
+		total += trimmed
+	}
+
+	s.logger.Info("Trimmed events", "deleted", total, "resources", len(resourceKeys), "maxEventsPerResource", maxEvents)
+	return total, nil
+}
+
+// resourceKeys returns the sorted resource keys that have events stored
+func (s *Storage) resourceKeys() ([]string, error) {
+	items, err := s.db.List("events/by-resource/")
+	if err != nil {
+		return nil, apperrors.WrapStorage(err, "failed to list events for trimming")
+	}
+
+	seen := make(map[string]bool)
+	for _, data := range items {
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil || event.ResourceKey == "" {
+			continue
+		}
+		seen[event.ResourceKey] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
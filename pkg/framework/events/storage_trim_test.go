@@ -0,0 +1,102 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+func TestStorage_TrimByResource(t *testing.T) {
+	storage := setupLimitedStorage(t)
+	start := time.Now().Add(-2 * time.Hour)
+	storeResourceEvents(t, storage, "default/Deployment/busy", 1100, start)
+	storeResourceEvents(t, storage, "default/Deployment/quiet", 10, start)
+
+	trimmed, err := storage.TrimByResource("default/Deployment/busy", 1000)
+	if err != nil {
+		t.Fatalf("TrimByResource() error = %v", err)
+	}
+	if trimmed != 100 {
+		t.Errorf("TrimByResource() trimmed %d events, want 100", trimmed)
+	}
+
+	events, err := storage.GetEventsByResource("default/Deployment/busy", EventFilters{Limit: 2000})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	if len(events) != 1000 {
+		t.Fatalf("GetEventsByResource() returned %d events, want 1000", len(events))
+	}
+	remaining := make(map[string]bool, len(events))
+	for _, event := range events {
+		remaining[event.Message] = true
+	}
+	for i := 0; i < 100; i++ {
+		if remaining[fmt.Sprintf("event %d", i)] {
+			t.Fatalf("event %d is one of the 100 oldest but was kept", i)
+		}
+	}
+	if !remaining["event 100"] || !remaining["event 1099"] {
+		t.Error("TrimByResource() deleted events newer than the 100 oldest")
+	}
+
+	// The primary keys and type index of trimmed events are gone too
+	if got := countPrimaryEvents(t, storage); got != 1010 {
+		t.Errorf("primary events = %d, want 1010", got)
+	}
+	infos, err := storage.ListEvents(EventFilters{Type: EventTypeInfo, Limit: 5000})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(infos) != 1010 {
+		t.Errorf("events in the type index = %d, want 1010", len(infos))
+	}
+}
+
+func TestStorage_TrimByResourceUnderLimit(t *testing.T) {
+	storage := setupLimitedStorage(t)
+	storeResourceEvents(t, storage, "default/Deployment/a", 3, time.Now().Add(-time.Hour))
+
+	trimmed, err := storage.TrimByResource("default/Deployment/a", 5)
+	if err != nil || trimmed != 0 {
+		t.Errorf("TrimByResource() = %d, %v, want 0, nil", trimmed, err)
+	}
+	if _, err := storage.TrimByResource("default/Deployment/a", 0); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("TrimByResource(0) error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestStorage_TrimAll(t *testing.T) {
+	storage := setupLimitedStorage(t)
+	start := time.Now().Add(-time.Hour)
+	storeResourceEvents(t, storage, "default/Deployment/a", 8, start)
+	storeResourceEvents(t, storage, "default/Deployment/b", 4, start)
+
+	trimmed, err := storage.TrimAll(5)
+	if err != nil {
+		t.Fatalf("TrimAll() error = %v", err)
+	}
+	if trimmed != 3 {
+		t.Errorf("TrimAll() trimmed %d events, want 3", trimmed)
+	}
+	if got := countPrimaryEvents(t, storage); got != 9 {
+		t.Errorf("primary events = %d, want 9", got)
+	}
+}
+
+func TestStorage_TrimAllUsesConfiguredLimit(t *testing.T) {
+	storage := setupLimitedStorage(t)
+	storeResourceEvents(t, storage, "default/Deployment/a", 6, time.Now().Add(-time.Hour))
+	storage.maxEventsPerResource = 2
+
+	trimmed, err := storage.TrimAll(0)
+	if err != nil {
+		t.Fatalf("TrimAll() error = %v", err)
+	}
+	if trimmed != 4 {
+		t.Errorf("TrimAll(0) trimmed %d events, want 4 with a limit of 2", trimmed)
+	}
+}
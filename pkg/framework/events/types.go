@@ -19,14 +19,53 @@ type Event struct {
 	Message     string                 `json:"message"`
 	Error       string                 `json:"error,omitempty"`
 	Details     map[string]interface{} `json:"details,omitempty"`
+	// DeploymentID groups the events emitted by one Up, Down or Update call
+	DeploymentID string `json:"deploymentId,omitempty"`
+	// RequestID is the X-Request-ID of the API request that caused the event
+	RequestID string `json:"requestId,omitempty"`
 }
 
 type EventFilters struct {
 	ResourceKey string
-	Type        EventType
-	Since       time.Time
-	Until       time.Time
-	Limit       int
-	Offset      int
+	// ResourcePrefix keeps the events whose resource key starts with the prefix, e.g. "default/"
+	ResourcePrefix string
+	Type           EventType
+	// Severities keeps the events of any of the listed types; empty keeps every type
+	Severities []EventType
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
 }
 
+// hasSeverity reports whether t passes the Severities filter
+func (f EventFilters) hasSeverity(t EventType) bool {
+	if len(f.Severities) == 0 {
+		return true
+	}
+	for _, severity := range f.Severities {
+		if severity == t {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditEntry records a single user-triggered API operation
+type AuditEntry struct {
+	ID         string    `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	User       string    `json:"user,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	Action     string    `json:"action"`
+	Resource   string    `json:"resource,omitempty"`
+	StatusCode int       `json:"statusCode"`
+}
+
+type AuditFilters struct {
+	Action string
+	Since  time.Time
+	Until  time.Time
+	// Limit caps the number of entries returned; zero returns every match
+	Limit int
+}
@@ -3,20 +3,38 @@ package framework
 import (
 	"context"
 	"embed"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/garunski/conductor-framework/pkg/framework/api"
 	"github.com/garunski/conductor-framework/pkg/framework/crd"
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
 	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/notifier"
 	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
 	"github.com/garunski/conductor-framework/pkg/framework/server"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+	"github.com/garunski/conductor-framework/pkg/framework/webhook"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 // Config holds all framework configuration
@@ -30,21 +48,183 @@ type Config struct {
 	ManifestRoot    string
 	CustomTemplateFS *embed.FS // Optional custom templates
 	TemplateFuncs   template.FuncMap // Optional custom template functions
+	// ValuesFile is the values file in ManifestFS, relative to ManifestRoot, whose contents are the
+	// default deployment parameters served until a DeploymentParameters instance exists
+	ValuesFile string
+	// KustomizeRoot, when set, is a kustomization directory in ManifestFS rendered in place of ManifestRoot
+	KustomizeRoot string
+
+	// HelmCharts are rendered and stored alongside the manifests loaded from ManifestFS
+	HelmCharts []HelmChartConfig
+
+	// TenantID scopes the manifests of this instance to one tenant, stored under
+	// {TenantID}/{namespace}/Kind/name; empty keeps the shared keyspace
+	TenantID string
+
+	// Git, when URL is set, clones a repository and loads its manifests in place of ManifestFS
+	Git GitConfig
+
+	// ManifestConfigMapNamespace and ManifestConfigMapName, when set, select a ConfigMap whose keys
+	// are manifest file names and whose values are their YAML. Its manifests replace those loaded
+	// from ManifestFS, Git or HelmCharts with the same key and are reloaded when it changes.
+	ManifestConfigMapNamespace string
+	// ManifestConfigMapName names the ConfigMap in ManifestConfigMapNamespace
+	ManifestConfigMapName string
 
 	// Storage configuration
 	DataPath string
 
 	// Server configuration
 	Port string
+	// StartupProbePort, when set, serves GET /startup on a separate listener
+	// from the very start of Run until full initialization completes
+	StartupProbePort string
+	// StartupProbe, when enabled, makes Run wait until the Kubernetes API server answers
+	// before loading manifests and starting the reconciler
+	StartupProbe StartupProbeConfig
 
 	// Logging configuration
 	LogRetentionDays  int
 	LogCleanupInterval time.Duration
+	// LogFormat selects JSON lines ("json") or human-readable console output ("text", the default)
+	LogFormat string
+	// LogLevel is the minimum level logged: "debug", "info", "warn" or "error". Empty selects
+	// debug for the text format and info for the json format.
+	LogLevel string
+	// MaxEventsPerResource keeps at most this many events per resource, evicting the oldest; zero is unlimited
+	MaxEventsPerResource int
+	// ManifestHistoryLimit keeps this many previous versions of every updated manifest; zero keeps none
+	ManifestHistoryLimit int
 
 	// CRD configuration
 	CRDGroup         string
 	CRDVersion       string
 	CRDResource      string
+	// AutoInstallCRD creates the DeploymentParameters CRD at startup when the cluster does not have it
+	AutoInstallCRD bool
+
+	// Kubernetes configuration
+	// KubernetesContext selects a kubeconfig context; empty uses in-cluster or the current context
+	KubernetesContext string
+	// Clusters names additional clusters that deployment and status endpoints select with
+	// ?cluster=<name>; an entry named "default" replaces KubernetesContext for the default cluster
+	Clusters map[string]ClusterConfig
+
+	// Deployment webhooks
+	// PreDeployWebhooks run in order before Up and Update deploy; a failing required hook aborts the deployment
+	PreDeployWebhooks []WebhookConfig
+	// PostDeployWebhooks run in order after Up and Update deploy successfully
+	PostDeployWebhooks []WebhookConfig
+	// WebhookNotifiers are notified in the background after Up, Down and Update succeed or fail
+	WebhookNotifiers []NotifierConfig
+
+	// Rate limiting, per client IP; a zero RequestsPerSecond disables a limit
+	RateLimit      RateLimitConfig // Read endpoints
+	WriteRateLimit RateLimitConfig // /api/up, /api/down, /api/update and /api/parameters writes
+
+	// Request body limits in bytes; larger bodies are rejected with 413 and zero disables a limit
+	MaxRequestBodyBytes     int64 // Every endpoint except the bulk uploads
+	MaxBulkRequestBodyBytes int64 // /api/manifests/bulk and /api/manifests/import
+
+	// Auth requires a bearer token on every request except /healthz, /readyz, the webhook trigger
+	// and the /static/ UI assets; browsers reach the UI pages through a proxy that adds the token
+	Auth AuthConfig
+
+	// WebhookTrigger lets CI/CD systems trigger a deployment with a signed push event sent to
+	// POST /api/webhooks/trigger; the endpoint is disabled while Secret is empty
+	WebhookTrigger WebhookTriggerConfig
+
+	// CORSAllowedOrigins lists the origins allowed to call the API from a browser, e.g.
+	// "https://app.example.com" or "https://*.example.com"; "*" allows every origin
+	CORSAllowedOrigins []string
+
+	// DefaultDeployTimeout bounds each resource apply when its manifest has no
+	// service.conductor.io/deploy-timeout annotation; zero means no bound
+	DefaultDeployTimeout time.Duration
+
+	// RollingUpdateTimeout bounds how long POST /api/update?strategy=rolling waits for each batch
+	// of StatefulSet pods to become Ready before giving up on the rollout; zero means no bound
+	RollingUpdateTimeout time.Duration
+
+	// ReconcilerBackoffBase is how long periodic reconciliation skips a resource after its apply
+	// fails; the delay doubles with each consecutive failure up to ReconcilerBackoffMax and is
+	// reset by a successful apply. Zero retries failing resources on every reconciliation.
+	ReconcilerBackoffBase time.Duration
+	ReconcilerBackoffMax  time.Duration
+
+	// ReconcilerWorkers is the number of manifests of a priority group applied concurrently;
+	// zero uses reconciler.DefaultWorkers
+	ReconcilerWorkers int
+
+	// RollbackRetention is the number of rollback snapshots kept for POST /api/rollback; a new
+	// snapshot is stored only when a reconciliation deploys different manifests than the last one
+	RollbackRetention int
+
+	// AutoCreateNamespace creates a manifest's namespace when an apply fails because it does not exist
+	AutoCreateNamespace bool
+
+	// UseFinalizers attaches the conductor.io/managed finalizer to every applied resource so that
+	// deleting one outside the framework leaves it terminating; the framework removes the
+	// finalizer itself before deleting orphans or tearing everything down
+	UseFinalizers bool
+
+	// SkipCapacityCheck disables the check that the Ready nodes can fit the resource requests
+	// of the Deployments and StatefulSets being deployed
+	SkipCapacityCheck bool
+
+	// SkipConfirmation lets POST /api/down delete right away instead of first returning a
+	// confirmation token that a second call must present, e.g. for automated pipelines
+	SkipConfirmation bool
+
+	// StrictKeyValidation rejects created and imported manifests whose key (namespace/Kind/name)
+	// does not match their metadata with 422 instead of the generic 400 validation error
+	StrictKeyValidation bool
+
+	// ServiceProbeTimeout bounds GET /api/services/{namespace}/{service}/probe requests
+	ServiceProbeTimeout time.Duration
+
+	// Database garbage collection
+	// AutoGCIntervalMinutes runs value log GC this often while the database exceeds
+	// AutoGCThresholdBytes; zero disables it. POST /api/admin/gc runs it on demand.
+	AutoGCIntervalMinutes int
+	AutoGCThresholdBytes  int64
+	// GCDiscardRatio is the fraction of stale data a value log file needs before GC rewrites it
+	GCDiscardRatio float64
+}
+
+// AuthConfig configures bearer token authentication with static tokens and an optional OIDC issuer
+type AuthConfig = api.AuthConfig
+
+// WebhookTriggerConfig configures the HMAC secret and branch filter of the deployment webhook trigger
+type WebhookTriggerConfig = api.WebhookTriggerConfig
+
+// RateLimitConfig configures a per-client-IP request rate and burst
+type RateLimitConfig = api.RateLimitConfig
+
+// WebhookConfig describes an external endpoint called before or after a deployment
+type WebhookConfig = webhook.Config
+
+// NotifierConfig describes an external endpoint notified about deployment lifecycle events
+type NotifierConfig = notifier.Config
+
+// HelmChartConfig describes a Helm chart rendered as an additional manifest source
+type HelmChartConfig = manifest.HelmChartConfig
+
+// GitConfig describes a git repository manifests are loaded from
+type GitConfig = manifest.GitConfig
+
+// ClusterConfig selects the kubeconfig file and context of a named cluster
+type ClusterConfig = server.ClusterConfig
+
+// StartupProbeConfig configures how long Run waits for the Kubernetes API server at startup
+type StartupProbeConfig struct {
+	Enabled bool
+	// MaxAttempts is how many times the server version is requested before Run fails
+	MaxAttempts int
+	// RetryInterval is the wait between failed attempts
+	RetryInterval time.Duration
+	// Timeout bounds each attempt; zero leaves it to the client defaults
+	Timeout time.Duration
 }
 
 // DefaultConfig returns a Config with default values
@@ -53,13 +233,77 @@ func DefaultConfig() Config {
 		AppName:            "conductor",
 		AppVersion:         getEnvOrDefault("VERSION", "dev"),
 		ManifestRoot:       "manifests",
+		ValuesFile:         getEnvOrDefault("VALUES_FILE", manifest.ValuesFileName),
+		KustomizeRoot:      getEnvOrDefault("KUSTOMIZE_ROOT", ""),
 		DataPath:           getEnvOrDefault("BADGER_DATA_PATH", "/data/badger"),
 		Port:               getEnvOrDefault("PORT", "8081"),
+		StartupProbePort:   getEnvOrDefault("STARTUP_PROBE_PORT", ""),
 		LogRetentionDays:   parseIntOrDefault("LOG_RETENTION_DAYS", 7),
 		LogCleanupInterval: parseDurationOrDefault("LOG_CLEANUP_INTERVAL", 1*time.Hour),
+		LogFormat:          getEnvOrDefault("LOG_FORMAT", LogFormatText),
+		LogLevel:           getEnvOrDefault("LOG_LEVEL", ""),
 		CRDGroup:           crd.DefaultCRDGroup,
 		CRDVersion:         crd.DefaultCRDVersion,
 		CRDResource:        crd.DefaultCRDResource,
+		AutoInstallCRD:     parseBoolOrDefault("AUTO_INSTALL_CRD", false),
+		KubernetesContext:  getEnvOrDefault("KUBERNETES_CONTEXT", ""),
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond: parseFloatOrDefault("RATE_LIMIT_RPS", 0),
+			BurstSize:         parseIntOrDefault("RATE_LIMIT_BURST", 0),
+		},
+		WriteRateLimit: RateLimitConfig{
+			RequestsPerSecond: parseFloatOrDefault("WRITE_RATE_LIMIT_RPS", 0),
+			BurstSize:         parseIntOrDefault("WRITE_RATE_LIMIT_BURST", 0),
+		},
+		MaxRequestBodyBytes:     int64(parseIntOrDefault("MAX_REQUEST_BODY_BYTES", 1<<20)),
+		MaxBulkRequestBodyBytes: int64(parseIntOrDefault("MAX_BULK_REQUEST_BODY_BYTES", 32<<20)),
+		Auth: AuthConfig{
+			Enabled:          parseBoolOrDefault("AUTH_ENABLED", false),
+			Tokens:           splitListOrDefault("AUTH_TOKENS", nil),
+			OIDCIssuerURL:    getEnvOrDefault("AUTH_OIDC_ISSUER_URL", ""),
+			OIDCAudience:     getEnvOrDefault("AUTH_OIDC_AUDIENCE", ""),
+			OIDCTenantsClaim: getEnvOrDefault("AUTH_OIDC_TENANTS_CLAIM", ""),
+			TrustedProxies:   splitListOrDefault("AUTH_TRUSTED_PROXIES", nil),
+		},
+		WebhookTrigger: WebhookTriggerConfig{
+			Secret:          getEnvOrDefault("WEBHOOK_SECRET", ""),
+			AllowedBranches: splitListOrDefault("WEBHOOK_ALLOWED_BRANCHES", nil),
+		},
+		CORSAllowedOrigins:    splitListOrDefault("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		DefaultDeployTimeout:  parseDurationOrDefault("DEFAULT_DEPLOY_TIMEOUT", 5*time.Minute),
+		RollingUpdateTimeout:  parseDurationOrDefault("ROLLING_UPDATE_TIMEOUT", reconciler.DefaultRollingUpdateTimeout),
+		ReconcilerBackoffBase: parseDurationOrDefault("RECONCILER_BACKOFF_BASE", reconciler.DefaultBackoffBase),
+		ReconcilerBackoffMax:  parseDurationOrDefault("RECONCILER_BACKOFF_MAX", reconciler.DefaultBackoffMax),
+		ReconcilerWorkers:     parseIntOrDefault("RECONCILER_WORKERS", reconciler.DefaultWorkers),
+		RollbackRetention:     parseIntOrDefault("ROLLBACK_RETENTION", reconciler.DefaultRollbackRetention),
+		AutoCreateNamespace:   parseBoolOrDefault("AUTO_CREATE_NAMESPACE", false),
+		UseFinalizers:         parseBoolOrDefault("USE_FINALIZERS", false),
+		SkipCapacityCheck:     parseBoolOrDefault("SKIP_CAPACITY_CHECK", false),
+		SkipConfirmation:      parseBoolOrDefault("SKIP_CONFIRMATION", false),
+		StrictKeyValidation:   parseBoolOrDefault("STRICT_KEY_VALIDATION", false),
+		ServiceProbeTimeout:   parseDurationOrDefault("SERVICE_PROBE_TIMEOUT", 5*time.Second),
+		AutoGCIntervalMinutes: parseIntOrDefault("AUTO_GC_INTERVAL_MINUTES", 0),
+		AutoGCThresholdBytes:  int64(parseIntOrDefault("AUTO_GC_THRESHOLD_BYTES", 1<<30)),
+		GCDiscardRatio:        parseFloatOrDefault("GC_DISCARD_RATIO", database.DefaultGCDiscardRatio),
+		MaxEventsPerResource:  parseIntOrDefault("MAX_EVENTS_PER_RESOURCE", events.DefaultMaxEventsPerResource),
+		ManifestHistoryLimit:  parseIntOrDefault("MANIFEST_HISTORY_RETENTION", store.DefaultHistoryRetention),
+		TenantID:              getEnvOrDefault("TENANT_ID", ""),
+		Git: GitConfig{
+			URL:          getEnvOrDefault("GIT_URL", ""),
+			Branch:       getEnvOrDefault("GIT_BRANCH", ""),
+			Tag:          getEnvOrDefault("GIT_TAG", ""),
+			Path:         getEnvOrDefault("GIT_PATH", ""),
+			SSHKeySecret: getEnvOrDefault("GIT_SSH_KEY_SECRET", ""),
+			PollInterval: parseDurationOrDefault("GIT_POLL_INTERVAL", 0),
+		},
+		StartupProbe: StartupProbeConfig{
+			Enabled:       parseBoolOrDefault("STARTUP_PROBE_ENABLED", false),
+			MaxAttempts:   parseIntOrDefault("STARTUP_PROBE_MAX_ATTEMPTS", 30),
+			RetryInterval: parseDurationOrDefault("STARTUP_PROBE_RETRY_INTERVAL", 2*time.Second),
+			Timeout:       parseDurationOrDefault("STARTUP_PROBE_TIMEOUT", 5*time.Second),
+		},
+		ManifestConfigMapNamespace: getEnvOrDefault("MANIFEST_CONFIGMAP_NAMESPACE", ""),
+		ManifestConfigMapName:      getEnvOrDefault("MANIFEST_CONFIGMAP_NAME", ""),
 	}
 }
 
@@ -74,21 +318,142 @@ func (c *Config) Validate() error {
 	if c.Port == "" {
 		return fmt.Errorf("Port cannot be empty")
 	}
+	if c.StartupProbePort != "" && c.StartupProbePort == c.Port {
+		return fmt.Errorf("StartupProbePort cannot be the same as Port")
+	}
+	if c.StartupProbe.Enabled && c.StartupProbe.MaxAttempts < 1 {
+		return fmt.Errorf("StartupProbe.MaxAttempts must be at least 1 when enabled")
+	}
+	if c.StartupProbe.RetryInterval < 0 || c.StartupProbe.Timeout < 0 {
+		return fmt.Errorf("StartupProbe.RetryInterval and StartupProbe.Timeout cannot be negative")
+	}
 	if c.LogRetentionDays < 0 {
 		return fmt.Errorf("LogRetentionDays cannot be negative")
 	}
 	if c.LogCleanupInterval <= 0 {
 		return fmt.Errorf("LogCleanupInterval must be positive")
 	}
+	if c.LogFormat != "" && c.LogFormat != LogFormatJSON && c.LogFormat != LogFormatText {
+		return fmt.Errorf("LogFormat must be %q or %q", LogFormatJSON, LogFormatText)
+	}
+	switch c.LogLevel {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("LogLevel must be one of debug, info, warn or error")
+	}
+	if c.MaxEventsPerResource < 0 {
+		return fmt.Errorf("MaxEventsPerResource cannot be negative")
+	}
+	if c.ManifestHistoryLimit < 0 {
+		return fmt.Errorf("ManifestHistoryLimit cannot be negative")
+	}
+	if c.RateLimit.RequestsPerSecond < 0 || c.RateLimit.BurstSize < 0 {
+		return fmt.Errorf("RateLimit cannot be negative")
+	}
+	if c.WriteRateLimit.RequestsPerSecond < 0 || c.WriteRateLimit.BurstSize < 0 {
+		return fmt.Errorf("WriteRateLimit cannot be negative")
+	}
+	if c.MaxRequestBodyBytes < 0 || c.MaxBulkRequestBodyBytes < 0 {
+		return fmt.Errorf("MaxRequestBodyBytes and MaxBulkRequestBodyBytes cannot be negative")
+	}
+	if c.DefaultDeployTimeout < 0 {
+		return fmt.Errorf("DefaultDeployTimeout cannot be negative")
+	}
+	if c.RollingUpdateTimeout < 0 {
+		return fmt.Errorf("RollingUpdateTimeout cannot be negative")
+	}
+	if c.ReconcilerBackoffBase < 0 || c.ReconcilerBackoffMax < 0 {
+		return fmt.Errorf("ReconcilerBackoffBase and ReconcilerBackoffMax cannot be negative")
+	}
+	if c.ReconcilerBackoffBase > 0 && c.ReconcilerBackoffMax < c.ReconcilerBackoffBase {
+		return fmt.Errorf("ReconcilerBackoffMax cannot be less than ReconcilerBackoffBase")
+	}
+	if c.ReconcilerWorkers < 0 {
+		return fmt.Errorf("ReconcilerWorkers cannot be negative")
+	}
+	if c.RollbackRetention < 0 {
+		return fmt.Errorf("RollbackRetention cannot be negative")
+	}
+	if c.ServiceProbeTimeout < 0 {
+		return fmt.Errorf("ServiceProbeTimeout cannot be negative")
+	}
+	if c.AutoGCIntervalMinutes < 0 || c.AutoGCThresholdBytes < 0 {
+		return fmt.Errorf("AutoGCIntervalMinutes and AutoGCThresholdBytes cannot be negative")
+	}
+	if c.GCDiscardRatio != 0 && (c.GCDiscardRatio < 0 || c.GCDiscardRatio >= 1) {
+		return fmt.Errorf("GCDiscardRatio must be between 0 and 1")
+	}
+	if c.TenantID != "" {
+		if err := store.ValidateTenantID(c.TenantID); err != nil {
+			return fmt.Errorf("invalid TenantID: %w", err)
+		}
+	}
+	if c.Git.URL != "" {
+		if err := c.Git.Validate(); err != nil {
+			return fmt.Errorf("invalid Git configuration: %w", err)
+		}
+		if c.KustomizeRoot != "" {
+			return fmt.Errorf("Git and KustomizeRoot cannot both be set")
+		}
+	}
+	if (c.ManifestConfigMapNamespace == "") != (c.ManifestConfigMapName == "") {
+		return fmt.Errorf("ManifestConfigMapNamespace and ManifestConfigMapName must be set together")
+	}
+	if c.Auth.Enabled && len(c.Auth.Tokens) == 0 && c.Auth.OIDCIssuerURL == "" {
+		return fmt.Errorf("Auth requires Tokens or OIDCIssuerURL when enabled")
+	}
+	if c.Auth.OIDCIssuerURL != "" && c.Auth.OIDCAudience == "" {
+		return fmt.Errorf("Auth.OIDCAudience is required with OIDCIssuerURL")
+	}
+	if _, err := api.ParseTrustedProxies(c.Auth.TrustedProxies); err != nil {
+		return fmt.Errorf("Auth.TrustedProxies: %w", err)
+	}
+	for i, hook := range c.PreDeployWebhooks {
+		if hook.URL == "" {
+			return fmt.Errorf("PreDeployWebhooks[%d].URL cannot be empty", i)
+		}
+	}
+	for i, hook := range c.PostDeployWebhooks {
+		if hook.URL == "" {
+			return fmt.Errorf("PostDeployWebhooks[%d].URL cannot be empty", i)
+		}
+	}
+	for i, n := range c.WebhookNotifiers {
+		if n.URL == "" {
+			return fmt.Errorf("WebhookNotifiers[%d].URL cannot be empty", i)
+		}
+	}
 	return nil
 }
 
-// setupLogger initializes and returns a logger
-func setupLogger() (logr.Logger, error) {
-	zapLog, err := zap.NewDevelopment()
+// Log formats accepted by Config.LogFormat
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// setupLogger returns a zap-backed logger in cfg.LogFormat at cfg.LogLevel, writing to stderr.
+// Every entry carries the app_name and app_version fields.
+func setupLogger(cfg Config) (logr.Logger, error) {
+	zapCfg := zap.NewDevelopmentConfig()
+	if cfg.LogFormat == LogFormatJSON {
+		zapCfg = zap.NewProductionConfig()
+	}
+	if cfg.LogLevel != "" {
+		level, err := zapcore.ParseLevel(cfg.LogLevel)
+		if err != nil {
+			return logr.Logger{}, fmt.Errorf("invalid log level: %w", err)
+		}
+		zapCfg.Level = zap.NewAtomicLevelAt(level)
+	}
+	zapCfg.InitialFields = map[string]interface{}{
+		"app_name":    cfg.AppName,
+		"app_version": cfg.AppVersion,
+	}
+
+	zapLog, err := zapCfg.Build()
 	if err != nil {
-		var zeroLogger logr.Logger
-		return zeroLogger, fmt.Errorf("failed to create logger: %w", err)
+		return logr.Logger{}, fmt.Errorf("failed to create logger: %w", err)
 	}
 	return zapr.NewLogger(zapLog), nil
 }
@@ -97,7 +462,7 @@ func setupLogger() (logr.Logger, error) {
 // Returns nil parameterGetter if Kubernetes is unavailable (for fallback behavior)
 func setupKubernetesClient(ctx context.Context, logger logr.Logger, cfg Config) (dynamic.Interface, manifest.ParameterGetter, error) {
 	logger.Info("Setting up Kubernetes client for manifest loading")
-	kubeConfig, err := reconciler.GetKubernetesConfig()
+	kubeConfig, err := reconciler.GetKubernetesConfigForContext(cfg.KubernetesContext)
 	if err != nil {
 		logger.Info("Kubernetes config not available, using default parameters for template rendering", "error", err)
 		return nil, nil, nil // Not an error, just fallback
@@ -111,6 +476,18 @@ func setupKubernetesClient(ctx context.Context, logger logr.Logger, cfg Config)
 
 	// Create temporary CRD client for manifest loading
 	parameterClient := crd.NewClient(dynamicClient, logger, cfg.CRDGroup, cfg.CRDVersion, cfg.CRDResource)
+	if cfg.AutoInstallCRD {
+		crdClient, err := apiextensionsclientset.NewForConfig(kubeConfig)
+		if err != nil {
+			logger.Error(err, "failed to create apiextensions client, not installing the DeploymentParameters CRD")
+		} else {
+			installParametersCRD(ctx, logger, parameterClient, crdClient)
+		}
+	}
+	// Bring instances written by an older version up to date before templates read them
+	if err := crd.RunMigrations(ctx, parameterClient, cfg.AppVersion); err != nil {
+		logger.Error(err, "failed to migrate DeploymentParameters", "version", cfg.AppVersion)
+	}
 	
 	// Create parameter getter function that returns full spec
 	defaultNamespace := "default"
@@ -121,59 +498,331 @@ func setupKubernetesClient(ctx context.Context, logger logr.Logger, cfg Config)
 	return dynamicClient, parameterGetter, nil
 }
 
-// loadManifests loads embedded manifests with optional parameter templating
-func loadManifests(ctx context.Context, cfg Config, parameterGetter manifest.ParameterGetter) (map[string][]byte, error) {
-	manifests, err := manifest.LoadEmbeddedManifests(cfg.ManifestFS, cfg.ManifestRoot, ctx, parameterGetter, cfg.TemplateFuncs)
+// installParametersCRD creates the DeploymentParameters CRD when the cluster does not have it.
+// Failures are logged, not returned: without the CRD, parameters fall back to their defaults.
+func installParametersCRD(ctx context.Context, logger logr.Logger, parameterClient *crd.Client, crdClient apiextensionsclientset.Interface) {
+	created, err := parameterClient.EnsureCRD(ctx, crdClient)
+	if err != nil {
+		logger.Error(err, "failed to auto-install the DeploymentParameters CRD", "crd", parameterClient.CRDName())
+		return
+	}
+	if created {
+		logger.Info("DeploymentParameters CRD not found in the cluster, installed it from the embedded definition", "crd", parameterClient.CRDName())
+	}
+}
+
+// startStartupProbe binds the startup probe listener and serves GET /startup in the background
+func startStartupProbe(logger logr.Logger, port string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on startup probe port %s: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /startup", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	startupServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Info("Starting startup probe server", "port", port)
+		if err := startupServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error(err, "startup probe server error")
+		}
+	}()
+
+	return startupServer, nil
+}
+
+// stopStartupProbe shuts down the startup probe listener
+func stopStartupProbe(logger logr.Logger, startupServer *http.Server) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := startupServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error(err, "failed to shutdown startup probe server")
+		return
+	}
+	logger.Info("Startup probe server stopped")
+}
+
+// waitForCluster requests the server version from the API server of cfg.KubernetesContext
+// until it answers or cfg.StartupProbe.MaxAttempts attempts have failed
+func waitForCluster(ctx context.Context, logger logr.Logger, cfg Config) error {
+	kubeConfig, err := reconciler.GetKubernetesConfigForContext(cfg.KubernetesContext)
+	if err != nil {
+		return fmt.Errorf("startup probe: %w", err)
+	}
+	kubeConfig = rest.CopyConfig(kubeConfig)
+	kubeConfig.Timeout = cfg.StartupProbe.Timeout
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("startup probe: failed to create Kubernetes client: %w", err)
+	}
+	return waitForServerVersion(ctx, logger, clientset.Discovery(), cfg.StartupProbe)
+}
+
+// waitForServerVersion calls ServerVersion up to probe.MaxAttempts times, waiting
+// probe.RetryInterval after each failure, and returns the last error if none succeeds
+func waitForServerVersion(ctx context.Context, logger logr.Logger, client discovery.ServerVersionInterface, probe StartupProbeConfig) error {
+	var lastErr error
+	for attempt := 1; attempt <= probe.MaxAttempts; attempt++ {
+		info, err := client.ServerVersion()
+		if err == nil {
+			logger.Info("Kubernetes API server is reachable", "version", info.GitVersion, "attempts", attempt)
+			return nil
+		}
+		lastErr = err
+		if attempt == probe.MaxAttempts {
+			break
+		}
+
+		logger.Info("Kubernetes API server not reachable, retrying", "attempt", attempt, "maxAttempts", probe.MaxAttempts, "retryInterval", probe.RetryInterval, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(probe.RetryInterval):
+		}
+	}
+	return fmt.Errorf("%w: Kubernetes API server not reachable after %d attempts: %w", apperrors.ErrKubernetes, probe.MaxAttempts, lastErr)
+}
+
+// loadDefaultParameters reads cfg.ValuesFile from the manifest root of cfg.ManifestFS.
+// A missing file yields an empty spec, which keeps the built-in defaults.
+func loadDefaultParameters(cfg Config) (map[string]interface{}, error) {
+	if cfg.ValuesFile == "" {
+		return map[string]interface{}{}, nil
+	}
+	values, err := manifest.LoadValuesFile(cfg.ManifestFS, filepath.Join(cfg.ManifestRoot, cfg.ValuesFile))
 	if err != nil {
-		return nil, fmt.Errorf("failed to load embedded manifests: %w", err)
+		return nil, fmt.Errorf("failed to load default parameters: %w", err)
+	}
+
+	// Round-trip through JSON so the spec only holds JSON types and can be stored in a CRD
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default parameters: %w", err)
+	}
+	spec := make(map[string]interface{})
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to load default parameters: %w", err)
+	}
+	return spec, nil
+}
+
+// loadManifestsFunc is the manifest loading step used by Run; tests may replace it
+var loadManifestsFunc = loadManifests
+
+// loadManifests loads embedded manifests, the manifests of gitLoader when it is not nil, or
+// the rendered kustomization when KustomizeRoot is set, with optional parameter templating,
+// adds the manifests rendered from HelmCharts and, when configMapLoader is not nil, overlays
+// the manifests of the ConfigMap. Embedded and kustomize manifests are rendered with renderOpts.
+func loadManifests(ctx context.Context, cfg Config, parameterGetter manifest.ParameterGetter, renderOpts manifest.RenderOptions, gitLoader *manifest.GitLoader, configMapLoader *manifest.ConfigMapLoader) (map[string][]byte, error) {
+	var manifests map[string][]byte
+	var err error
+	if gitLoader != nil {
+		manifests, err = gitLoader.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load git manifests: %w", err)
+		}
+	} else if cfg.KustomizeRoot != "" {
+		manifests, err = manifest.LoadKustomizeManifestsWithOptions(cfg.ManifestFS, cfg.KustomizeRoot, ctx, parameterGetter, renderOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kustomize manifests: %w", err)
+		}
+	} else {
+		manifests, err = manifest.LoadEmbeddedManifestsWithOptions(cfg.ManifestFS, cfg.ManifestRoot, ctx, parameterGetter, renderOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load embedded manifests: %w", err)
+		}
+	}
+
+	helmManifests, err := manifest.LoadHelmManifests(ctx, cfg.HelmCharts, parameterGetter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helm charts: %w", err)
+	}
+	for key, data := range helmManifests {
+		if _, duplicate := manifests[key]; duplicate {
+			return nil, fmt.Errorf("helm chart manifest %s is already defined by the embedded manifests", key)
+		}
+		manifests[key] = data
+	}
+
+	if configMapLoader != nil {
+		manifests, err = configMapLoader.Overlay(ctx, manifests)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ConfigMap manifests: %w", err)
+		}
 	}
 	return manifests, nil
 }
 
-// Run starts the framework with the given configuration
-// It handles the complete lifecycle: initialization, startup, and shutdown
-func Run(ctx context.Context, cfg Config) error {
-	// Initialize logger
-	logger, err := setupLogger()
+// newTemplateKubeClient returns the client secretValue and configValue read Secrets and
+// ConfigMaps with while manifests load, or nil when no cluster is configured
+func newTemplateKubeClient(logger logr.Logger, cfg Config) kubernetes.Interface {
+	kubeConfig, err := reconciler.GetKubernetesConfigForContext(cfg.KubernetesContext)
 	if err != nil {
-		return err
+		logger.Info("Kubernetes config not available, secretValue and configValue render empty", "error", err)
+		return nil
+	}
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		logger.Info("Failed to create Kubernetes client, secretValue and configValue render empty", "error", err)
+		return nil
 	}
+	return clientset
+}
+
+// newConfigMapLoader returns a loader for the ConfigMap of cfg, or nil when none is configured
+func newConfigMapLoader(cfg Config, parameterGetter manifest.ParameterGetter, logger logr.Logger) (*manifest.ConfigMapLoader, error) {
+	if cfg.ManifestConfigMapName == "" {
+		return nil, nil
+	}
+	kubeConfig, err := reconciler.GetKubernetesConfigForContext(cfg.KubernetesContext)
+	if err != nil {
+		return nil, fmt.Errorf("manifest ConfigMap: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("manifest ConfigMap: failed to create Kubernetes client: %w", err)
+	}
+	return manifest.NewConfigMapLoader(clientset, cfg.ManifestConfigMapNamespace, cfg.ManifestConfigMapName, parameterGetter, cfg.TemplateFuncs, logger)
+}
 
+// Run starts the framework with the given configuration
+// It handles the complete lifecycle: initialization, startup, and shutdown
+func Run(ctx context.Context, cfg Config) error {
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	// Initialize logger
+	logger, err := setupLogger(cfg)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Starting framework", "appName", cfg.AppName, "version", cfg.AppVersion)
 
+	// Serve the startup probe before any heavy initialization
+	var startupServer *http.Server
+	if cfg.StartupProbePort != "" {
+		startupServer, err = startStartupProbe(logger, cfg.StartupProbePort)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if startupServer != nil {
+				stopStartupProbe(logger, startupServer)
+			}
+		}()
+	}
+
 	// Load manifests with optional parameter templating
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// Wait for the API server so the first reconcile does not fail on a cluster that is still starting
+	if cfg.StartupProbe.Enabled {
+		if err := waitForCluster(ctx, logger, cfg); err != nil {
+			return err
+		}
+	}
+
 	// Setup Kubernetes client (may fail gracefully - returns nil parameterGetter)
 	_, parameterGetter, _ := setupKubernetesClient(ctx, logger, cfg)
+	templateKubeClient := newTemplateKubeClient(logger, cfg)
+	renderOpts := manifest.RenderOptions{
+		CustomFuncs:    cfg.TemplateFuncs,
+		ClusterVersion: server.ClusterVersion(templateKubeClient, logger),
+		KubeClient:     templateKubeClient,
+		Logger:         logger,
+	}
+
+	// Clone the manifest repository when one is configured
+	var gitLoader *manifest.GitLoader
+	if cfg.Git.URL != "" {
+		gitLoader, err = manifest.NewGitLoader(cfg.Git, parameterGetter, cfg.TemplateFuncs, logger)
+		if err != nil {
+			return err
+		}
+		gitLoader.SetKubeClient(renderOpts.KubeClient)
+		defer func() {
+			if err := gitLoader.Close(); err != nil {
+				logger.Error(err, "failed to remove git clone")
+			}
+		}()
+	}
+
+	configMapLoader, err := newConfigMapLoader(cfg, parameterGetter, logger)
+	if err != nil {
+		return err
+	}
 
 	// Load manifests
-	manifests, err := loadManifests(ctx, cfg, parameterGetter)
+	manifests, err := loadManifestsFunc(ctx, cfg, parameterGetter, renderOpts, gitLoader, configMapLoader)
 	if err != nil {
 		return err
 	}
 	logger.Info("Loaded manifests", "count", len(manifests))
 
+	defaultParameters, err := loadDefaultParameters(cfg)
+	if err != nil {
+		return err
+	}
+
 	// Convert Config to server.Config
 	serverCfg := &server.Config{
-		AppName:            cfg.AppName,
-		AppVersion:         cfg.AppVersion,
-		DataPath:           cfg.DataPath,
-		Port:               cfg.Port,
-		LogRetentionDays:   cfg.LogRetentionDays,
-		LogCleanupInterval: cfg.LogCleanupInterval,
-		CRDGroup:           cfg.CRDGroup,
-		CRDVersion:         cfg.CRDVersion,
-		CRDResource:        cfg.CRDResource,
-		CustomTemplateFS:   cfg.CustomTemplateFS,
-		ManifestFS:         cfg.ManifestFS,
-		ManifestRoot:       cfg.ManifestRoot,
+		AppName:              cfg.AppName,
+		AppVersion:           cfg.AppVersion,
+		DataPath:             cfg.DataPath,
+		Port:                 cfg.Port,
+		LogRetentionDays:     cfg.LogRetentionDays,
+		LogCleanupInterval:   cfg.LogCleanupInterval,
+		MaxEventsPerResource: cfg.MaxEventsPerResource,
+		ManifestHistoryLimit: cfg.ManifestHistoryLimit,
+		CRDGroup:             cfg.CRDGroup,
+		CRDVersion:           cfg.CRDVersion,
+		CRDResource:          cfg.CRDResource,
+		KubernetesContext:    cfg.KubernetesContext,
+		Clusters:             cfg.Clusters,
+		PreDeployWebhooks:    cfg.PreDeployWebhooks,
+		PostDeployWebhooks:   cfg.PostDeployWebhooks,
+		WebhookNotifiers:     cfg.WebhookNotifiers,
+		RateLimit:            cfg.RateLimit,
+		WriteRateLimit:       cfg.WriteRateLimit,
+		MaxBodyBytes:         cfg.MaxRequestBodyBytes,
+		MaxBulkBodyBytes:     cfg.MaxBulkRequestBodyBytes,
+		Auth:                 cfg.Auth,
+		WebhookTrigger:       cfg.WebhookTrigger,
+		CORSAllowedOrigins:   cfg.CORSAllowedOrigins,
+		DeployTimeout:        cfg.DefaultDeployTimeout,
+		RollingUpdateTimeout: cfg.RollingUpdateTimeout,
+		BackoffBase:          cfg.ReconcilerBackoffBase,
+		BackoffMax:           cfg.ReconcilerBackoffMax,
+		Workers:              cfg.ReconcilerWorkers,
+		RollbackRetention:    cfg.RollbackRetention,
+		AutoCreateNamespace:  cfg.AutoCreateNamespace,
+		UseFinalizers:        cfg.UseFinalizers,
+		SkipCapacityCheck:    cfg.SkipCapacityCheck,
+		SkipConfirmation:     cfg.SkipConfirmation,
+		StrictKeyValidation:  cfg.StrictKeyValidation,
+		ProbeTimeout:         cfg.ServiceProbeTimeout,
+		AutoGCInterval:       time.Duration(cfg.AutoGCIntervalMinutes) * time.Minute,
+		AutoGCThreshold:      cfg.AutoGCThresholdBytes,
+		GCDiscardRatio:       cfg.GCDiscardRatio,
+		CustomTemplateFS:     cfg.CustomTemplateFS,
+		ManifestFS:           cfg.ManifestFS,
+		ManifestRoot:         cfg.ManifestRoot,
+		TemplateFuncs:        cfg.TemplateFuncs,
+		DefaultParameters:    defaultParameters,
+		GitLoader:            gitLoader,
+		ConfigMapLoader:      configMapLoader,
+		TenantID:             cfg.TenantID,
 	}
 
 	// Create server with pre-loaded manifests
@@ -192,6 +841,12 @@ func Run(ctx context.Context, cfg Config) error {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 
+	// Full initialization is complete; the startup probe is no longer needed
+	if startupServer != nil {
+		stopStartupProbe(logger, startupServer)
+		startupServer = nil
+	}
+
 	// Wait for shutdown
 	if err := srv.WaitForShutdown(ctx); err != nil {
 		return fmt.Errorf("shutdown error: %w", err)
@@ -233,3 +888,35 @@ func parseIntOrDefault(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func parseFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+func parseBoolOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// splitListOrDefault reads a comma-separated list, dropping empty entries
+func splitListOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
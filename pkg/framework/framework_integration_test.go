@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
 )
 
 // Note: Full Run() integration tests are complex and require proper server lifecycle management
@@ -44,7 +46,7 @@ func TestLoadManifests_WithEmptyFS(t *testing.T) {
 		ManifestRoot: "manifests",
 	}
 
-	manifests, err := loadManifests(context.Background(), cfg, nil)
+	manifests, err := loadManifests(context.Background(), cfg, nil, manifest.RenderOptions{}, nil, nil)
 	if err != nil {
 		// Error is expected if manifests directory doesn't exist
 		t.Logf("loadManifests() with empty FS returned error (expected): %v", err)
@@ -70,7 +72,7 @@ func TestLoadManifests_WithContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	manifests, err := loadManifests(ctx, cfg, nil)
+	manifests, err := loadManifests(ctx, cfg, nil, manifest.RenderOptions{}, nil, nil)
 	// Should handle cancellation gracefully
 	if err != nil && err != context.Canceled {
 		t.Logf("loadManifests() with cancelled context returned error: %v", err)
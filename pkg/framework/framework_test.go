@@ -3,12 +3,23 @@ package framework
 import (
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/garunski/conductor-framework/pkg/framework/crd"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -39,6 +50,31 @@ func TestDefaultConfig(t *testing.T) {
 	}
 }
 
+func TestDefaultConfig_KubernetesContext(t *testing.T) {
+	t.Setenv("KUBERNETES_CONTEXT", "")
+	if cfg := DefaultConfig(); cfg.KubernetesContext != "" {
+		t.Errorf("DefaultConfig() KubernetesContext = %v, want empty", cfg.KubernetesContext)
+	}
+
+	t.Setenv("KUBERNETES_CONTEXT", "staging")
+	if cfg := DefaultConfig(); cfg.KubernetesContext != "staging" {
+		t.Errorf("DefaultConfig() KubernetesContext = %v, want staging", cfg.KubernetesContext)
+	}
+}
+
+func TestDefaultConfig_CORSAllowedOrigins(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	if cfg := DefaultConfig(); !reflect.DeepEqual(cfg.CORSAllowedOrigins, []string{"*"}) {
+		t.Errorf("DefaultConfig() CORSAllowedOrigins = %v, want [*]", cfg.CORSAllowedOrigins)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://*.example.com")
+	want := []string{"https://app.example.com", "https://*.example.com"}
+	if cfg := DefaultConfig(); !reflect.DeepEqual(cfg.CORSAllowedOrigins, want) {
+		t.Errorf("DefaultConfig() CORSAllowedOrigins = %v, want %v", cfg.CORSAllowedOrigins, want)
+	}
+}
+
 func TestConfigValidate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -122,6 +158,90 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "pre-deploy webhook without URL",
+			config: Config{
+				AppName:            "test",
+				DataPath:           "/tmp/test",
+				Port:               "8080",
+				LogRetentionDays:   7,
+				LogCleanupInterval: 1 * time.Hour,
+				PreDeployWebhooks:  []WebhookConfig{{Method: "POST"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook notifier without URL",
+			config: Config{
+				AppName:            "test",
+				DataPath:           "/tmp/test",
+				Port:               "8080",
+				LogRetentionDays:   7,
+				LogCleanupInterval: 1 * time.Hour,
+				WebhookNotifiers:   []NotifierConfig{{Events: []string{"deploy.success"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative deploy timeout",
+			config: Config{
+				AppName:              "test",
+				DataPath:             "/tmp/test",
+				Port:                 "8080",
+				LogRetentionDays:     7,
+				LogCleanupInterval:   1 * time.Hour,
+				DefaultDeployTimeout: -time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative rate limit",
+			config: Config{
+				AppName:            "test",
+				DataPath:           "/tmp/test",
+				Port:               "8080",
+				LogRetentionDays:   7,
+				LogCleanupInterval: 1 * time.Hour,
+				RateLimit:          RateLimitConfig{RequestsPerSecond: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "auth enabled without tokens or issuer",
+			config: Config{
+				AppName:            "test",
+				DataPath:           "/tmp/test",
+				Port:               "8080",
+				LogRetentionDays:   7,
+				LogCleanupInterval: 1 * time.Hour,
+				Auth:               AuthConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OIDC issuer without audience",
+			config: Config{
+				AppName:            "test",
+				DataPath:           "/tmp/test",
+				Port:               "8080",
+				LogRetentionDays:   7,
+				LogCleanupInterval: 1 * time.Hour,
+				Auth:               AuthConfig{Enabled: true, OIDCIssuerURL: "https://issuer.example.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid trusted proxy",
+			config: Config{
+				AppName:            "test",
+				DataPath:           "/tmp/test",
+				Port:               "8080",
+				LogRetentionDays:   7,
+				LogCleanupInterval: 1 * time.Hour,
+				Auth:               AuthConfig{TrustedProxies: []string{"10.0.0.0/33"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -253,7 +373,7 @@ func TestParseIntOrDefault(t *testing.T) {
 
 // TestSetupLogger tests the setupLogger function
 func TestSetupLogger(t *testing.T) {
-	logger, err := setupLogger()
+	logger, err := setupLogger(Config{})
 	if err != nil {
 		t.Fatalf("setupLogger() error = %v", err)
 	}
@@ -261,6 +381,109 @@ func TestSetupLogger(t *testing.T) {
 	_ = logger.Enabled()
 }
 
+// captureStderr returns what fn writes to os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() { os.Stderr = stderr }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+	fn()
+	writer.Close()
+	return <-output
+}
+
+func TestSetupLogger_JSON(t *testing.T) {
+	output := captureStderr(t, func() {
+		logger, err := setupLogger(Config{AppName: "conductor", AppVersion: "1.2.3", LogFormat: LogFormatJSON, LogLevel: "debug"})
+		if err != nil {
+			t.Fatalf("setupLogger() error = %v", err)
+		}
+		logger.Info("started", "port", "8081")
+		logger.V(1).Info("debug detail")
+		logger.Error(errors.New("boom"), "failed")
+	})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("setupLogger() wrote %d lines, want 3:\n%s", len(lines), output)
+	}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %v: %s", err, line)
+		}
+		if entry["app_name"] != "conductor" || entry["app_version"] != "1.2.3" {
+			t.Errorf("log line is missing the app fields: %s", line)
+		}
+	}
+}
+
+func TestSetupLogger_Level(t *testing.T) {
+	output := captureStderr(t, func() {
+		logger, err := setupLogger(Config{LogFormat: LogFormatText, LogLevel: "warn"})
+		if err != nil {
+			t.Fatalf("setupLogger() error = %v", err)
+		}
+		logger.Info("hidden info")
+		logger.Error(errors.New("boom"), "visible error")
+	})
+
+	if strings.Contains(output, "hidden info") {
+		t.Errorf("setupLogger() at warn level logged an info entry:\n%s", output)
+	}
+	if !strings.Contains(output, "visible error") {
+		t.Errorf("setupLogger() at warn level dropped an error entry:\n%s", output)
+	}
+}
+
+func TestConfigValidate_Logging(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.LogFormat, cfg.LogLevel = LogFormatJSON, "warn"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with json logging error = %v", err)
+	}
+
+	cfg.LogFormat = "xml"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with an unknown LogFormat should fail")
+	}
+
+	cfg.LogFormat, cfg.LogLevel = LogFormatText, "verbose"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with an unknown LogLevel should fail")
+	}
+}
+
+func TestInstallParametersCRD(t *testing.T) {
+	ctx := context.Background()
+	parameterClient := crd.NewClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), logr.Discard(), "", "", "")
+	crdClient := apiextensionsfake.NewSimpleClientset()
+
+	installParametersCRD(ctx, logr.Discard(), parameterClient, crdClient)
+	if _, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, parameterClient.CRDName(), metav1.GetOptions{}); err != nil {
+		t.Fatalf("installParametersCRD() did not create %s: %v", parameterClient.CRDName(), err)
+	}
+
+	crdClient.ClearActions()
+	installParametersCRD(ctx, logr.Discard(), parameterClient, crdClient)
+	for _, action := range crdClient.Actions() {
+		if action.GetVerb() == "create" {
+			t.Errorf("installParametersCRD() re-created the existing CRD: %v", action)
+		}
+	}
+}
+
 // TestSetupKubernetesClient tests the setupKubernetesClient function
 // This tests the fallback behavior when Kubernetes is unavailable
 func TestSetupKubernetesClient_NoKubernetes(t *testing.T) {
@@ -294,7 +517,7 @@ func TestLoadManifests(t *testing.T) {
 	}
 
 	// Test with nil parameterGetter (fallback behavior)
-	manifests, err := loadManifests(context.Background(), cfg, nil)
+	manifests, err := loadManifests(context.Background(), cfg, nil, manifest.RenderOptions{}, nil, nil)
 	if err != nil {
 		// This is expected if manifests directory doesn't exist
 		t.Logf("loadManifests() with empty FS returned error (expected): %v", err)
@@ -375,3 +598,198 @@ func TestRun_EmptyPort(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+// TestLoadManifests_HelmCharts tests that rendered Helm charts are added to the manifests
+func TestLoadManifests_HelmCharts(t *testing.T) {
+	var testFS embed.FS
+	cfg := Config{
+		ManifestFS:   testFS,
+		ManifestRoot: "manifests",
+		HelmCharts:   []HelmChartConfig{{RepoURL: "manifest/testdata/helm", ChartName: "webapp"}},
+	}
+
+	manifests, err := loadManifests(context.Background(), cfg, nil, manifest.RenderOptions{}, nil, nil)
+	if err != nil {
+		t.Fatalf("loadManifests() error = %v", err)
+	}
+	for _, key := range []string{"default/Deployment/webapp-webapp", "default/Service/webapp-webapp"} {
+		if _, ok := manifests[key]; !ok {
+			t.Errorf("loadManifests() is missing %s", key)
+		}
+	}
+}
+
+func TestConfigValidate_GarbageCollection(t *testing.T) {
+	base := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg := base
+	cfg.AutoGCIntervalMinutes, cfg.AutoGCThresholdBytes, cfg.GCDiscardRatio = 10, 1<<20, 0.5
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	cfg = base
+	cfg.AutoGCIntervalMinutes = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with a negative AutoGCIntervalMinutes should fail")
+	}
+
+	cfg = base
+	cfg.GCDiscardRatio = 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with GCDiscardRatio 1 should fail")
+	}
+}
+
+func TestConfigValidate_MaxEventsPerResource(t *testing.T) {
+	if got := DefaultConfig().MaxEventsPerResource; got != 1000 {
+		t.Errorf("DefaultConfig().MaxEventsPerResource = %d, want 1000", got)
+	}
+
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour, MaxEventsPerResource: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with a negative MaxEventsPerResource should fail")
+	}
+}
+
+func TestConfigValidate_Git(t *testing.T) {
+	base := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg := base
+	cfg.Git = GitConfig{URL: "https://example.com/manifests.git", Branch: "main"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with a git repository error = %v", err)
+	}
+
+	cfg.Git.Tag = "v1.0.0"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with both a git Branch and Tag should fail")
+	}
+
+	cfg = base
+	cfg.Git = GitConfig{URL: "https://example.com/manifests.git"}
+	cfg.KustomizeRoot = "overlays/prod"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with both Git and KustomizeRoot should fail")
+	}
+}
+
+func TestConfigValidate_TenantID(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.TenantID = "team-a"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with a tenant error = %v", err)
+	}
+
+	for _, tenantID := range []string{"Team_A", "events"} {
+		cfg.TenantID = tenantID
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() with TenantID %q should fail", tenantID)
+		}
+	}
+}
+
+func TestConfigValidate_StartupProbe(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.StartupProbe = StartupProbeConfig{Enabled: true, MaxAttempts: 3, RetryInterval: time.Second}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with a startup probe error = %v", err)
+	}
+
+	cfg.StartupProbe.MaxAttempts = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with an enabled startup probe and no attempts should fail")
+	}
+
+	cfg.StartupProbe = StartupProbeConfig{Enabled: true, MaxAttempts: 3, Timeout: -time.Second}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with a negative startup probe Timeout should fail")
+	}
+}
+
+func TestConfigValidate_ReconcilerBackoff(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.ReconcilerBackoffBase, cfg.ReconcilerBackoffMax = 5*time.Second, 5*time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with a reconciler backoff error = %v", err)
+	}
+
+	cfg.ReconcilerBackoffMax = time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with ReconcilerBackoffMax below ReconcilerBackoffBase should fail")
+	}
+
+	cfg.ReconcilerBackoffBase, cfg.ReconcilerBackoffMax = -time.Second, 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with a negative ReconcilerBackoffBase should fail")
+	}
+}
+
+func TestConfigValidate_ReconcilerWorkers(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.ReconcilerWorkers = 10
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with 10 reconciler workers error = %v", err)
+	}
+
+	cfg.ReconcilerWorkers = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with negative ReconcilerWorkers should fail")
+	}
+}
+
+func TestConfigValidate_RollbackRetention(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.RollbackRetention = 5
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with a rollback retention of 5 error = %v", err)
+	}
+
+	cfg.RollbackRetention = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with negative RollbackRetention should fail")
+	}
+}
+
+func TestConfigValidate_RequestBodyLimits(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.MaxRequestBodyBytes = 1 << 20
+	cfg.MaxBulkRequestBodyBytes = 32 << 20
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with body limits error = %v", err)
+	}
+
+	cfg.MaxBulkRequestBodyBytes = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with negative MaxBulkRequestBodyBytes should fail")
+	}
+}
+
+func TestConfigValidate_RollingUpdateTimeout(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.RollingUpdateTimeout = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with negative RollingUpdateTimeout should fail")
+	}
+}
+
+func TestConfigValidate_ManifestConfigMap(t *testing.T) {
+	cfg := Config{AppName: "test", DataPath: "/tmp/test", Port: "8080", LogCleanupInterval: time.Hour}
+
+	cfg.ManifestConfigMapName = "manifests"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() with ManifestConfigMapName but no namespace should fail")
+	}
+
+	cfg.ManifestConfigMapNamespace = "conductor"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with ManifestConfigMapNamespace and ManifestConfigMapName error = %v", err)
+	}
+}
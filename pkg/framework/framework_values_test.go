@@ -0,0 +1,50 @@
+package framework
+
+import (
+	"embed"
+	"testing"
+)
+
+//go:embed testdata/manifests
+var valuesTestFS embed.FS
+
+func TestLoadDefaultParameters(t *testing.T) {
+	cfg := Config{
+		ManifestFS:   valuesTestFS,
+		ManifestRoot: "testdata/manifests",
+		ValuesFile:   "values.yaml",
+	}
+
+	spec, err := loadDefaultParameters(cfg)
+	if err != nil {
+		t.Fatalf("loadDefaultParameters() error = %v", err)
+	}
+
+	global, ok := spec["global"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("global = %#v, want a map", spec["global"])
+	}
+	if global["namespace"] != "values-ns" {
+		t.Errorf("namespace = %v, want values-ns", global["namespace"])
+	}
+	// Numbers are normalized to JSON types so the spec can be stored in a CRD
+	if global["replicas"] != float64(2) {
+		t.Errorf("replicas = %#v, want float64(2)", global["replicas"])
+	}
+}
+
+func TestLoadDefaultParameters_MissingFile(t *testing.T) {
+	cfg := Config{
+		ManifestFS:   valuesTestFS,
+		ManifestRoot: "testdata/manifests",
+		ValuesFile:   "missing.yaml",
+	}
+
+	spec, err := loadDefaultParameters(cfg)
+	if err != nil {
+		t.Fatalf("loadDefaultParameters() error = %v", err)
+	}
+	if len(spec) != 0 {
+		t.Errorf("spec = %v, want empty", spec)
+	}
+}
@@ -1,17 +1,41 @@
 package index
 
 import (
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 type ManifestIndex struct {
 	mu        sync.RWMutex
 	manifests map[string][]byte
+
+	// byKind and byNamespace hold the keys of every namespace/Kind/name and
+	// tenant/namespace/Kind/name manifest grouped by kind and by namespace within its tenant
+	byKind      map[string]map[string]struct{}
+	byNamespace map[string]map[string]struct{}
+
+	// sorted holds every key in ascending order, so pages and prefix ranges are found by binary search
+	sorted []string
+
+	// modTimes holds when each manifest was last written, and version counts the writes, so
+	// callers can tell whether anything changed since they last looked
+	modTimes map[string]time.Time
+	version  uint64
+
+	// embedded holds the manifests passed to Merge that no database entry overrides, which
+	// RebuildTenant restores underneath the database entries
+	embedded map[string][]byte
 }
 
 func NewIndex() *ManifestIndex {
 	return &ManifestIndex{
-		manifests: make(map[string][]byte),
+		manifests:   make(map[string][]byte),
+		byKind:      make(map[string]map[string]struct{}),
+		byNamespace: make(map[string]map[string]struct{}),
+		modTimes:    make(map[string]time.Time),
+		embedded:    make(map[string][]byte),
 	}
 }
 
@@ -37,12 +61,59 @@ func (idx *ManifestIndex) Set(key string, value []byte) {
 	defer idx.mu.Unlock()
 
 	idx.manifests[key] = copyBytes(value)
+	idx.addSecondary(key)
+	idx.modTimes[key] = time.Now()
+	idx.version++
 }
 
 func (idx *ManifestIndex) Delete(key string) {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 	delete(idx.manifests, key)
+	idx.removeSecondary(key)
+	delete(idx.modTimes, key)
+	idx.version++
+}
+
+// Rename moves the manifest stored under oldKey to newKey under a single lock, so readers see
+// it under exactly one of the keys. It returns false when oldKey does not exist.
+func (idx *ManifestIndex) Rename(oldKey, newKey string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	val, ok := idx.manifests[oldKey]
+	if !ok {
+		return false
+	}
+	delete(idx.manifests, oldKey)
+	idx.removeSecondary(oldKey)
+	delete(idx.modTimes, oldKey)
+	idx.manifests[newKey] = val
+	idx.addSecondary(newKey)
+	idx.modTimes[newKey] = time.Now()
+	idx.version++
+	return true
+}
+
+// ModTime returns when the manifest stored under key was last written, or loaded by Merge
+func (idx *ManifestIndex) ModTime(key string) (time.Time, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	modTime, ok := idx.modTimes[key]
+	return modTime, ok
+}
+
+// Version returns a counter that changes whenever a manifest is written or deleted
+func (idx *ManifestIndex) Version() uint64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.version
+}
+
+func (idx *ManifestIndex) Count() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.manifests)
 }
 
 func (idx *ManifestIndex) List() map[string][]byte {
@@ -61,12 +132,227 @@ func (idx *ManifestIndex) Merge(embedded map[string][]byte, dbOverrides map[stri
 	defer idx.mu.Unlock()
 
 	idx.manifests = make(map[string][]byte)
+	idx.byKind = make(map[string]map[string]struct{})
+	idx.byNamespace = make(map[string]map[string]struct{})
+	idx.sorted = nil
+	idx.modTimes = make(map[string]time.Time)
+	idx.embedded = make(map[string][]byte, len(embedded))
+	loaded := time.Now()
 	for k, v := range embedded {
 		idx.manifests[k] = copyBytes(v)
+		idx.embedded[k] = copyBytes(v)
+		idx.addSecondary(k)
+		idx.modTimes[k] = loaded
 	}
 
 	for k, v := range dbOverrides {
 		idx.manifests[k] = copyBytes(v)
+		idx.addSecondary(k)
+		idx.modTimes[k] = loaded
+	}
+	idx.version++
+}
+
+// RebuildTenant replaces every manifest of tenant with the embedded manifests given to Merge,
+// overlaid with stored, the tenant's manifests as read from the database. Manifests whose
+// value does not change keep their modification time.
+func (idx *ManifestIndex) RebuildTenant(tenant string, stored map[string][]byte) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	rebuilt := make(map[string][]byte, len(stored))
+	for k, v := range idx.embedded {
+		if TenantOf(k) == tenant {
+			rebuilt[k] = v
+		}
+	}
+	for k, v := range stored {
+		rebuilt[k] = v
+	}
+
+	for k := range idx.manifests {
+		if _, keep := rebuilt[k]; !keep && TenantOf(k) == tenant {
+			delete(idx.manifests, k)
+			idx.removeSecondary(k)
+			delete(idx.modTimes, k)
+		}
+	}
+	rebuiltAt := time.Now()
+	for k, v := range rebuilt {
+		if current, ok := idx.manifests[k]; ok && string(current) == string(v) {
+			continue
+		}
+		idx.manifests[k] = copyBytes(v)
+		idx.addSecondary(k)
+		idx.modTimes[k] = rebuiltAt
+	}
+	idx.version++
+}
+
+// ListByKind returns the sorted keys of the manifests whose namespace/Kind/name key has kind
+func (idx *ManifestIndex) ListByKind(kind string) []string {
+	return idx.ListByTenantKind("", kind)
+}
+
+// ListByNamespace returns the sorted keys of the manifests whose namespace/Kind/name key has namespace
+func (idx *ManifestIndex) ListByNamespace(namespace string) []string {
+	return idx.ListByTenantNamespace("", namespace)
+}
+
+// ListByTenantKind returns the sorted keys of the manifests of tenant with kind. An empty
+// tenant selects the namespace/Kind/name keys that belong to no tenant.
+func (idx *ManifestIndex) ListByTenantKind(tenant, kind string) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return sortedKeys(idx.byKind[tenantGroup(tenant, kind)])
+}
+
+// ListByTenantNamespace returns the sorted keys of the manifests of tenant in namespace. An
+// empty tenant selects the namespace/Kind/name keys that belong to no tenant.
+func (idx *ManifestIndex) ListByTenantNamespace(tenant, namespace string) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return sortedKeys(idx.byNamespace[tenantGroup(tenant, namespace)])
+}
+
+// SortedKeys returns the keys of tenant that start with prefix in ascending order. An empty
+// tenant selects the keys that belong to no tenant; prefix includes the tenant segment of
+// tenant/namespace/Kind/name keys.
+func (idx *ManifestIndex) SortedKeys(tenant, prefix string) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var keys []string
+	for i := sort.SearchStrings(idx.sorted, prefix); i < len(idx.sorted); i++ {
+		key := idx.sorted[i]
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+		if TenantOf(key) == tenant {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ListByTenant returns the manifests whose key belongs to tenant. An empty tenant selects
+// every key that is not a tenant/namespace/Kind/name key.
+func (idx *ManifestIndex) ListByTenant(tenant string) map[string][]byte {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := make(map[string][]byte)
+	for k, v := range idx.manifests {
+		if TenantOf(k) == tenant {
+			result[k] = copyBytes(v)
+		}
+	}
+	return result
+}
+
+// CountByTenant returns the number of manifests whose key belongs to tenant
+func (idx *ManifestIndex) CountByTenant(tenant string) int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	count := 0
+	for k := range idx.manifests {
+		if TenantOf(k) == tenant {
+			count++
+		}
+	}
+	return count
+}
+
+// TenantOf returns the tenant of a tenant/namespace/Kind/name key, or "" for any other key
+func TenantOf(key string) string {
+	tenant, _, _, ok := splitKey(key)
+	if !ok {
+		return ""
+	}
+	return tenant
+}
+
+// splitKey returns the tenant, namespace and kind of a namespace/Kind/name or
+// tenant/namespace/Kind/name key
+func splitKey(key string) (tenant, namespace, kind string, ok bool) {
+	parts := strings.Split(key, "/")
+	switch len(parts) {
+	case 3:
+		return "", parts[0], parts[1], true
+	case 4:
+		return parts[0], parts[1], parts[2], true
+	default:
+		return "", "", "", false
+	}
+}
+
+// tenantGroup scopes a kind or namespace group of the secondary indexes to tenant
+func tenantGroup(tenant, group string) string {
+	if tenant == "" {
+		return group
+	}
+	return tenant + "/" + group
+}
+
+// addSecondary records key in the sorted key list and the kind and namespace indexes;
+// callers hold idx.mu
+func (idx *ManifestIndex) addSecondary(key string) {
+	if i := sort.SearchStrings(idx.sorted, key); i == len(idx.sorted) || idx.sorted[i] != key {
+		idx.sorted = append(idx.sorted, "")
+		copy(idx.sorted[i+1:], idx.sorted[i:])
+		idx.sorted[i] = key
+	}
+
+	tenant, namespace, kind, ok := splitKey(key)
+	if !ok {
+		return
+	}
+	addToSet(idx.byKind, tenantGroup(tenant, kind), key)
+	addToSet(idx.byNamespace, tenantGroup(tenant, namespace), key)
+}
+
+// removeSecondary drops key from the sorted key list and the kind and namespace indexes;
+// callers hold idx.mu
+func (idx *ManifestIndex) removeSecondary(key string) {
+	if i := sort.SearchStrings(idx.sorted, key); i < len(idx.sorted) && idx.sorted[i] == key {
+		idx.sorted = append(idx.sorted[:i], idx.sorted[i+1:]...)
+	}
+
+	tenant, namespace, kind, ok := splitKey(key)
+	if !ok {
+		return
+	}
+	removeFromSet(idx.byKind, tenantGroup(tenant, kind), key)
+	removeFromSet(idx.byNamespace, tenantGroup(tenant, namespace), key)
+}
+
+func addToSet(sets map[string]map[string]struct{}, group, key string) {
+	set, ok := sets[group]
+	if !ok {
+		set = make(map[string]struct{})
+		sets[group] = set
+	}
+	set[key] = struct{}{}
+}
+
+func removeFromSet(sets map[string]map[string]struct{}, group, key string) {
+	set, ok := sets[group]
+	if !ok {
+		return
+	}
+	delete(set, key)
+	if len(set) == 0 {
+		delete(sets, group)
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
 }
 
@@ -1,6 +1,7 @@
 package index
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -138,3 +139,157 @@ func TestIndexConcurrentAccess(t *testing.T) {
 	<-done
 }
 
+func TestIndexListByKindAndNamespace(t *testing.T) {
+	idx := NewIndex()
+	idx.Set("default/Deployment/web", []byte("web"))
+	idx.Set("default/Service/web", []byte("web"))
+	idx.Set("staging/Deployment/api", []byte("api"))
+	idx.Set("not-a-resource-key", []byte("ignored"))
+
+	if got, want := idx.ListByKind("Deployment"), []string{"default/Deployment/web", "staging/Deployment/api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByKind(Deployment) = %v, want %v", got, want)
+	}
+	if got, want := idx.ListByNamespace("default"), []string{"default/Deployment/web", "default/Service/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByNamespace(default) = %v, want %v", got, want)
+	}
+
+	idx.Delete("default/Deployment/web")
+	if got, want := idx.ListByKind("Deployment"), []string{"staging/Deployment/api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByKind(Deployment) after delete = %v, want %v", got, want)
+	}
+	if got, want := idx.ListByNamespace("default"), []string{"default/Service/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByNamespace(default) after delete = %v, want %v", got, want)
+	}
+	if got := idx.ListByKind("Secret"); len(got) != 0 {
+		t.Errorf("ListByKind(Secret) = %v, want empty", got)
+	}
+}
+
+func TestIndexMergeRebuildsSecondaryIndexes(t *testing.T) {
+	idx := NewIndex()
+	idx.Set("old/ConfigMap/stale", []byte("stale"))
+
+	idx.Merge(
+		map[string][]byte{"default/Deployment/web": []byte("embedded")},
+		map[string][]byte{"default/Service/web": []byte("override")},
+	)
+
+	if got := idx.ListByNamespace("old"); len(got) != 0 {
+		t.Errorf("ListByNamespace(old) = %v, want empty after Merge", got)
+	}
+	if got, want := idx.ListByNamespace("default"), []string{"default/Deployment/web", "default/Service/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByNamespace(default) = %v, want %v", got, want)
+	}
+}
+
+func TestIndexListByTenant(t *testing.T) {
+	idx := NewIndex()
+	idx.Set("default/Deployment/web", []byte("web"))
+	idx.Set("team-a/default/Deployment/web", []byte("team-a web"))
+	idx.Set("team-a/staging/Service/api", []byte("team-a api"))
+	idx.Set("team-b/default/Deployment/web", []byte("team-b web"))
+
+	if got, want := idx.ListByNamespace("default"), []string{"default/Deployment/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByNamespace(default) = %v, want %v", got, want)
+	}
+	if got, want := idx.ListByTenantNamespace("team-a", "default"), []string{"team-a/default/Deployment/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByTenantNamespace(team-a, default) = %v, want %v", got, want)
+	}
+	if got, want := idx.ListByTenantKind("team-b", "Deployment"), []string{"team-b/default/Deployment/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByTenantKind(team-b, Deployment) = %v, want %v", got, want)
+	}
+	if got := idx.ListByTenant("team-a"); len(got) != 2 {
+		t.Errorf("ListByTenant(team-a) = %v, want 2 manifests", got)
+	}
+	if got := idx.CountByTenant(""); got != 1 {
+		t.Errorf("CountByTenant(\"\") = %d, want 1", got)
+	}
+	if got := TenantOf("team-a/default/Deployment/web"); got != "team-a" {
+		t.Errorf("TenantOf() = %q, want team-a", got)
+	}
+}
+
+func TestIndexRename(t *testing.T) {
+	idx := NewIndex()
+	idx.Set("default/Deployment/web", []byte("web"))
+
+	if idx.Rename("default/Deployment/missing", "default/Deployment/other") {
+		t.Error("Rename() of a missing key = true, want false")
+	}
+	if !idx.Rename("default/Deployment/web", "staging/Deployment/web") {
+		t.Fatal("Rename() = false, want true")
+	}
+
+	if _, ok := idx.Get("default/Deployment/web"); ok {
+		t.Error("old key still found after Rename")
+	}
+	if got, ok := idx.Get("staging/Deployment/web"); !ok || string(got) != "web" {
+		t.Errorf("Get(new key) = %q, %v, want web", got, ok)
+	}
+	if got, want := idx.ListByNamespace("staging"), []string{"staging/Deployment/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListByNamespace(staging) = %v, want %v", got, want)
+	}
+	if got := idx.ListByNamespace("default"); len(got) != 0 {
+		t.Errorf("ListByNamespace(default) = %v, want empty", got)
+	}
+}
+
+func TestIndexRebuildTenant(t *testing.T) {
+	idx := NewIndex()
+	idx.Merge(map[string][]byte{
+		"default/ConfigMap/embedded":        []byte("embedded"),
+		"team-a/default/ConfigMap/embedded": []byte("team-a embedded"),
+	}, nil)
+	idx.Set("default/ConfigMap/stale", []byte("stale"))
+	idx.Set("default/ConfigMap/changed", []byte("old"))
+	idx.Set("team-a/default/ConfigMap/other", []byte("team-a other"))
+
+	idx.RebuildTenant("", map[string][]byte{
+		"default/ConfigMap/changed": []byte("new"),
+		"default/Secret/added":      []byte("added"),
+	})
+
+	want := map[string]string{
+		"default/ConfigMap/embedded": "embedded",
+		"default/ConfigMap/changed":  "new",
+		"default/Secret/added":       "added",
+	}
+	got := idx.ListByTenant("")
+	if len(got) != len(want) {
+		t.Errorf("ListByTenant(\"\") = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if string(got[key]) != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+	if got := idx.ListByKind("Secret"); !reflect.DeepEqual(got, []string{"default/Secret/added"}) {
+		t.Errorf("ListByKind(Secret) = %v, want the added manifest", got)
+	}
+	if got := idx.CountByTenant("team-a"); got != 2 {
+		t.Errorf("CountByTenant(team-a) = %d, want 2: other tenants are left alone", got)
+	}
+}
+
+func TestIndexSortedKeys(t *testing.T) {
+	idx := NewIndex()
+	idx.Merge(map[string][]byte{"default/Service/web": []byte("svc")}, nil)
+	idx.Set("default/Deployment/web", []byte("web"))
+	idx.Set("default/Deployment/api", []byte("api"))
+	idx.Set("default/Deployment/api", []byte("api v2"))
+	idx.Set("other/Deployment/web", []byte("other"))
+	idx.Set("team-a/default/Deployment/web", []byte("team-a"))
+	idx.Set("default/Deployment/gone", []byte("gone"))
+	idx.Delete("default/Deployment/gone")
+	idx.Rename("default/Deployment/web", "default/Deployment/worker")
+
+	if got, want := idx.SortedKeys("", ""), []string{"default/Deployment/api", "default/Deployment/worker", "default/Service/web", "other/Deployment/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys(\"\", \"\") = %v, want %v", got, want)
+	}
+	if got, want := idx.SortedKeys("", "default/Deployment/"), []string{"default/Deployment/api", "default/Deployment/worker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys(default/Deployment/) = %v, want %v", got, want)
+	}
+	if got, want := idx.SortedKeys("team-a", "team-a/"), []string{"team-a/default/Deployment/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys(team-a) = %v, want %v", got, want)
+	}
+}
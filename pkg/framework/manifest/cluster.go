@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// ClusterInfo describes the target cluster and is exposed to templates as .Cluster
+type ClusterInfo struct {
+	// Version is the Kubernetes server version, e.g. "v1.28.3"; empty when unknown
+	Version string
+}
+
+// IsVersionGTE reports whether the cluster version is at least v.
+// Returns false if either version is unset or cannot be parsed.
+func (c ClusterInfo) IsVersionGTE(v string) bool {
+	cluster, target, ok := c.parseVersions(v)
+	if !ok {
+		return false
+	}
+	return cluster.AtLeast(target)
+}
+
+// IsVersionLT reports whether the cluster version is lower than v.
+// Returns false if either version is unset or cannot be parsed.
+func (c ClusterInfo) IsVersionLT(v string) bool {
+	cluster, target, ok := c.parseVersions(v)
+	if !ok {
+		return false
+	}
+	return cluster.LessThan(target)
+}
+
+func (c ClusterInfo) parseVersions(v string) (*utilversion.Version, *utilversion.Version, bool) {
+	if c.Version == "" || v == "" {
+		return nil, nil, false
+	}
+	cluster, err := utilversion.ParseGeneric(c.Version)
+	if err != nil {
+		return nil, nil, false
+	}
+	target, err := utilversion.ParseGeneric(v)
+	if err != nil {
+		return nil, nil, false
+	}
+	return cluster, target, true
+}
@@ -0,0 +1,62 @@
+package manifest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClusterInfo_VersionComparisons(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster string
+		target  string
+		wantGTE bool
+		wantLT  bool
+	}{
+		{name: "newer cluster", cluster: "v1.28.3", target: "1.25", wantGTE: true, wantLT: false},
+		{name: "equal version", cluster: "1.25.0", target: "1.25", wantGTE: true, wantLT: false},
+		{name: "older cluster", cluster: "v1.24.1", target: "1.25", wantGTE: false, wantLT: true},
+		{name: "provider suffix", cluster: "v1.27.4-gke.900", target: "1.27", wantGTE: true, wantLT: false},
+		{name: "unparseable target", cluster: "v1.28.0", target: "latest", wantGTE: false, wantLT: false},
+		{name: "unparseable cluster", cluster: "unknown", target: "1.25", wantGTE: false, wantLT: false},
+		{name: "unset cluster version", cluster: "", target: "1.25", wantGTE: false, wantLT: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := ClusterInfo{Version: tt.cluster}
+			if got := info.IsVersionGTE(tt.target); got != tt.wantGTE {
+				t.Errorf("IsVersionGTE(%q) = %v, want %v", tt.target, got, tt.wantGTE)
+			}
+			if got := info.IsVersionLT(tt.target); got != tt.wantLT {
+				t.Errorf("IsVersionLT(%q) = %v, want %v", tt.target, got, tt.wantLT)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateWithOptions_Cluster(t *testing.T) {
+	tmpl := []byte(`{{ if .Cluster.IsVersionGTE "1.25" }}policy/v1{{ else }}policy/v1beta1{{ end }}`)
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "new cluster", version: "v1.29.0", want: "policy/v1"},
+		{name: "old cluster", version: "v1.21.0", want: "policy/v1beta1"},
+		{name: "unknown cluster", version: "", want: "policy/v1beta1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplateWithOptions(context.Background(), tmpl, "svc", nil, RenderOptions{ClusterVersion: tt.version})
+			if err != nil {
+				t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+			}
+			if string(result) != tt.want {
+				t.Errorf("RenderTemplateWithOptions() = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
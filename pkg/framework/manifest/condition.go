@@ -0,0 +1,55 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ConditionAnnotation holds a template deciding whether a manifest is applied, e.g.
+// "{{ .Spec.global.enableMonitoring }}". The manifest is skipped when it renders to false or 0.
+// Rendering a manifest leaves the annotation value as written, so the condition is evaluated
+// against the parameters current at each reconcile; the value must fit on one line.
+const ConditionAnnotation = "conductor.io/condition"
+
+// conditionLine matches an annotation line of ConditionAnnotation and captures its value
+var conditionLine = regexp.MustCompile(`(?m)^([ \t]*["']?` + regexp.QuoteMeta(ConditionAnnotation) + `["']?[ \t]*:[ \t]*)(.*)$`)
+
+// EvaluateCondition renders conditionTemplate against spec with the same functions as manifest
+// templates and reports whether the manifest it guards should be applied. It is false only
+// when the output, trimmed, is "false" (in any case) or "0"; any other output is true.
+func EvaluateCondition(conditionTemplate string, spec map[string]interface{}) (bool, error) {
+	output, err := RenderTemplateWithOptions(context.Background(), []byte(conditionTemplate), "", spec, RenderOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", conditionTemplate, err)
+	}
+
+	result := strings.TrimSpace(string(output))
+	return !strings.EqualFold(result, "false") && result != "0", nil
+}
+
+// protectConditions replaces the values of the condition annotations in a manifest template
+// with placeholders so rendering the template leaves them alone, and returns the values
+func protectConditions(data []byte) ([]byte, [][]byte) {
+	var values [][]byte
+	protected := conditionLine.ReplaceAllFunc(data, func(line []byte) []byte {
+		match := conditionLine.FindSubmatch(line)
+		values = append(values, append([]byte(nil), match[2]...))
+		return append(append([]byte(nil), match[1]...), conditionPlaceholder(len(values)-1)...)
+	})
+	return protected, values
+}
+
+// restoreConditions puts the values taken by protectConditions back into a rendered manifest
+func restoreConditions(data []byte, values [][]byte) []byte {
+	for i, value := range values {
+		data = bytes.ReplaceAll(data, conditionPlaceholder(i), value)
+	}
+	return data
+}
+
+func conditionPlaceholder(i int) []byte {
+	return []byte(fmt.Sprintf("__conductor_condition_%d__", i))
+}
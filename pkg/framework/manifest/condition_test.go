@@ -0,0 +1,76 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{
+			"enableMonitoring": false,
+			"enableTracing":    true,
+			"replicas":         0,
+			"environment":      "prod",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+	}{
+		{name: "false parameter", condition: "{{ .Spec.global.enableMonitoring }}", want: false},
+		{name: "true parameter", condition: "{{ .Spec.global.enableTracing }}", want: true},
+		{name: "zero parameter", condition: "{{ .Spec.global.replicas }}", want: false},
+		{name: "literal false", condition: " False ", want: false},
+		{name: "literal zero", condition: "0", want: false},
+		{name: "literal true", condition: "true", want: true},
+		{name: "sprig comparison", condition: `{{ eq .Spec.global.environment "prod" }}`, want: true},
+		{name: "param helper", condition: `{{ param "global.enableMonitoring" }}`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateCondition(tt.condition, spec)
+			if err != nil {
+				t.Fatalf("EvaluateCondition(%q) error = %v", tt.condition, err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateCondition(%q) = %v, want %v", tt.condition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition_InvalidTemplate(t *testing.T) {
+	if _, err := EvaluateCondition("{{ .Spec.global", nil); err == nil {
+		t.Error("EvaluateCondition() error = nil, want a parse error")
+	}
+}
+
+func TestRenderTemplate_KeepsConditionAnnotation(t *testing.T) {
+	source := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Spec.name }}
+  annotations:
+    conductor.io/condition: "{{ .Spec.global.enableMonitoring }}"
+`)
+	spec := map[string]interface{}{
+		"name":   "monitoring",
+		"global": map[string]interface{}{"enableMonitoring": true},
+	}
+
+	rendered, err := RenderTemplateWithOptions(context.Background(), source, "", spec, RenderOptions{})
+	if err != nil {
+		t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+	}
+	if !strings.Contains(string(rendered), "name: monitoring") {
+		t.Errorf("rendered manifest = %s, want the rest of the template rendered", rendered)
+	}
+	if !strings.Contains(string(rendered), `conductor.io/condition: "{{ .Spec.global.enableMonitoring }}"`) {
+		t.Errorf("rendered manifest = %s, want the condition left for apply time", rendered)
+	}
+}
@@ -0,0 +1,260 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// ConfigMapWatchRetryInterval is how long ConfigMapLoader.Watch waits before re-establishing
+// a watch that failed or was closed by the API server
+const ConfigMapWatchRetryInterval = 5 * time.Second
+
+// ConfigMapLoader loads manifests from a ConfigMap whose keys are manifest file names and whose
+// values are their YAML, so operators can change manifests without rebuilding the image.
+// Values are rendered the same way LoadEmbeddedManifests renders embedded manifests.
+type ConfigMapLoader struct {
+	clientset       kubernetes.Interface
+	namespace       string
+	name            string
+	parameterGetter ParameterGetter
+	templateFuncs   template.FuncMap
+	logger          logr.Logger
+
+	mu        sync.Mutex
+	base      map[string][]byte // Manifests the ConfigMap overrides
+	manifests map[string][]byte // Manifests of the last load
+	version   string            // ResourceVersion of the last load
+}
+
+// NewConfigMapLoader returns a loader for the ConfigMap namespace/name. Nothing is read until Overlay.
+func NewConfigMapLoader(clientset kubernetes.Interface, namespace, name string, parameterGetter ParameterGetter, templateFuncs template.FuncMap, logger logr.Logger) (*ConfigMapLoader, error) {
+	if namespace == "" || name == "" {
+		return nil, fmt.Errorf("%w: ConfigMap namespace and name cannot be empty", apperrors.ErrInvalid)
+	}
+	return &ConfigMapLoader{
+		clientset:       clientset,
+		namespace:       namespace,
+		name:            name,
+		parameterGetter: parameterGetter,
+		templateFuncs:   templateFuncs,
+		logger:          logger,
+	}, nil
+}
+
+// Overlay reads the ConfigMap and returns base with the ConfigMap manifests added, replacing
+// the base manifests with the same key. Watch restores the base manifest of a key whose
+// ConfigMap entry is removed.
+func (l *ConfigMapLoader) Overlay(ctx context.Context, base map[string][]byte) (map[string][]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	configMap, err := l.clientset.CoreV1().ConfigMaps(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get manifest ConfigMap %s/%s: %w", apperrors.ErrKubernetes, l.namespace, l.name, err)
+	}
+	manifests, err := l.render(ctx, configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	l.base = copyManifests(base)
+	l.manifests = manifests
+	l.version = configMap.ResourceVersion
+
+	merged := copyManifests(base)
+	for key, data := range manifests {
+		merged[key] = data
+	}
+	return merged, nil
+}
+
+// render renders the .yaml and .yml entries of configMap into manifests keyed by namespace/Kind/name
+func (l *ConfigMapLoader) render(ctx context.Context, configMap *corev1.ConfigMap) (map[string][]byte, error) {
+	fileNames := make([]string, 0, len(configMap.Data))
+	for fileName := range configMap.Data {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	spec := loadSpec(ctx, l.parameterGetter)
+	manifests := make(map[string][]byte)
+	for _, fileName := range fileNames {
+		if ext := filepath.Ext(fileName); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		// requirements.yaml and values.yaml are not Kubernetes manifests
+		if stem := strings.TrimSuffix(fileName, filepath.Ext(fileName)); stem == "requirements" || fileName == ValuesFileName {
+			continue
+		}
+
+		data, err := RenderTemplateWithOptions(ctx, []byte(configMap.Data[fileName]), extractServiceName(fileName, ""), spec, RenderOptions{
+			CustomFuncs: l.templateFuncs,
+			KubeClient:  l.clientset,
+			Logger:      l.logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render template for ConfigMap %s/%s key %s: %w", l.namespace, l.name, fileName, err)
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			continue
+		}
+
+		key, err := extractKeyFromYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract key from ConfigMap %s/%s key %s: %w", l.namespace, l.name, fileName, err)
+		}
+		manifests[key] = data
+	}
+	return manifests, nil
+}
+
+// Watch watches the ConfigMap until ctx is cancelled, writing the manifests that changed since
+// the previous load to store. A manifest whose entry is removed is reset to its base manifest,
+// or deleted when there is none. Each change is recorded as an info event. Deleting the
+// ConfigMap leaves the store unchanged.
+func (l *ConfigMapLoader) Watch(ctx context.Context, store ManifestWriter, eventStore events.EventStorage) {
+	for {
+		if err := l.watch(ctx, store, eventStore); err != nil {
+			l.logger.Error(err, "manifest ConfigMap watch failed", "namespace", l.namespace, "name", l.name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ConfigMapWatchRetryInterval):
+		}
+	}
+}
+
+// watch runs a single watch of the ConfigMap until it closes or ctx is cancelled. Changes made
+// before the watch was established are picked up by reading the ConfigMap once it is.
+func (l *ConfigMapLoader) watch(ctx context.Context, store ManifestWriter, eventStore events.EventStorage) error {
+	watcher, err := l.clientset.CoreV1().ConfigMaps(l.namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", l.name).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to watch manifest ConfigMap: %w", apperrors.ErrKubernetes, err)
+	}
+	defer watcher.Stop()
+
+	configMap, err := l.clientset.CoreV1().ConfigMaps(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
+	if err == nil {
+		if err := l.sync(ctx, store, eventStore, configMap); err != nil {
+			l.logger.Error(err, "failed to reload manifests from ConfigMap", "namespace", l.namespace, "name", l.name)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				configMap, ok := event.Object.(*corev1.ConfigMap)
+				if !ok || configMap.Name != l.name {
+					continue
+				}
+				if err := l.sync(ctx, store, eventStore, configMap); err != nil {
+					l.logger.Error(err, "failed to reload manifests from ConfigMap", "namespace", l.namespace, "name", l.name)
+				}
+			case watch.Deleted:
+				l.logger.Info("Manifest ConfigMap deleted, keeping the loaded manifests", "namespace", l.namespace, "name", l.name)
+			case watch.Error:
+				return fmt.Errorf("%w: manifest ConfigMap watch error: %v", apperrors.ErrKubernetes, event.Object)
+			}
+		}
+	}
+}
+
+// sync renders configMap and applies the difference to the previous load to store
+func (l *ConfigMapLoader) sync(ctx context.Context, store ManifestWriter, eventStore events.EventStorage, configMap *corev1.ConfigMap) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if configMap.ResourceVersion != "" && configMap.ResourceVersion == l.version {
+		return nil
+	}
+	previous := l.manifests
+	current, err := l.render(ctx, configMap)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string][]byte)
+	for key, data := range current {
+		if old, ok := previous[key]; !ok || !bytes.Equal(old, data) {
+			changed[key] = data
+		}
+	}
+	var removed []string
+	for key := range previous {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		if data, ok := l.base[key]; ok {
+			changed[key] = data
+			continue
+		}
+		removed = append(removed, key)
+	}
+	sort.Strings(removed)
+
+	if len(changed) > 0 {
+		if err := store.PutBatch(changed); err != nil {
+			return fmt.Errorf("failed to store manifests from ConfigMap: %w", err)
+		}
+	}
+	for _, key := range removed {
+		if err := store.Delete(key); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return fmt.Errorf("failed to delete manifest %s removed from ConfigMap: %w", key, err)
+		}
+	}
+
+	source := fmt.Sprintf("ConfigMap %s/%s", l.namespace, l.name)
+	keys := make([]string, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		action := "updated from"
+		if _, ok := previous[key]; !ok {
+			action = "added from"
+		} else if _, ok := current[key]; !ok {
+			action = "restored after its removal from"
+		}
+		events.StoreEventSafeContext(ctx, eventStore, l.logger, events.Info(key, "configmap-sync", fmt.Sprintf("Manifest %s %s", action, source)))
+	}
+	for _, key := range removed {
+		events.StoreEventSafeContext(ctx, eventStore, l.logger, events.Info(key, "configmap-sync", fmt.Sprintf("Manifest removed from %s", source)))
+	}
+
+	l.manifests = current
+	l.version = configMap.ResourceVersion
+	if len(changed) > 0 || len(removed) > 0 {
+		l.logger.Info("Reloaded manifests from ConfigMap", "namespace", l.namespace, "name", l.name, "changed", len(changed), "removed", len(removed))
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+const configMapTestManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: apps
+data:
+  level: debug
+`
+
+// lockedWriter is a ManifestWriter that Watch can write to while the test reads it
+type lockedWriter struct {
+	mu      sync.Mutex
+	writer  recordingWriter
+	written chan struct{}
+}
+
+func newLockedWriter(manifests map[string][]byte) *lockedWriter {
+	return &lockedWriter{writer: recordingWriter{manifests: manifests}, written: make(chan struct{}, 10)}
+}
+
+func (w *lockedWriter) PutBatch(entries map[string][]byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.writer.PutBatch(entries)
+	w.written <- struct{}{}
+	return err
+}
+
+func (w *lockedWriter) Delete(key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writer.Delete(key)
+}
+
+func (w *lockedWriter) get(key string) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, ok := w.writer.manifests[key]
+	return string(data), ok
+}
+
+func newManifestConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "manifests", Namespace: "conductor"},
+		Data:       data,
+	}
+}
+
+func TestNewConfigMapLoader_RequiresNamespaceAndName(t *testing.T) {
+	if _, err := NewConfigMapLoader(kubefake.NewSimpleClientset(), "conductor", "", nil, nil, logr.Discard()); err == nil {
+		t.Error("NewConfigMapLoader() without a name should return an error")
+	}
+}
+
+func TestConfigMapLoader_Overlay(t *testing.T) {
+	clientset := kubefake.NewSimpleClientset(newManifestConfigMap(map[string]string{
+		"app-config.yaml": configMapTestManifest,
+		"worker.yml":      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ printf \"%s-config\" \"worker\" }}\n  namespace: apps\n",
+		"README.md":       "not a manifest",
+	}))
+	loader, err := NewConfigMapLoader(clientset, "conductor", "manifests", nil, nil, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewConfigMapLoader() error = %v", err)
+	}
+
+	base := map[string][]byte{
+		"apps/ConfigMap/app-config": []byte("embedded"),
+		"apps/Service/web":          []byte("embedded service"),
+	}
+	manifests, err := loader.Overlay(context.Background(), base)
+	if err != nil {
+		t.Fatalf("Overlay() error = %v", err)
+	}
+
+	if len(manifests) != 3 {
+		t.Fatalf("Overlay() returned %d manifests, want 3: %v", len(manifests), manifests)
+	}
+	if got := string(manifests["apps/ConfigMap/app-config"]); got != configMapTestManifest {
+		t.Errorf("Overlay() app-config = %q, want the ConfigMap entry to take precedence", got)
+	}
+	if got := string(manifests["apps/Service/web"]); got != "embedded service" {
+		t.Errorf("Overlay() web = %q, want the embedded manifest", got)
+	}
+	if _, ok := manifests["apps/ConfigMap/worker-config"]; !ok {
+		t.Error("Overlay() is missing the rendered worker-config")
+	}
+	if string(base["apps/ConfigMap/app-config"]) != "embedded" {
+		t.Error("Overlay() modified the base manifests")
+	}
+}
+
+func TestConfigMapLoader_OverlayReadsSecretValues(t *testing.T) {
+	clientset := kubefake.NewSimpleClientset(
+		newManifestConfigMap(map[string]string{
+			"app-config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n  namespace: apps\ndata:\n  password: \"{{ secretValue \"apps\" \"db\" \"password\" }}\"\n",
+		}),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Data:       map[string][]byte{"password": []byte("s3cret")},
+		},
+	)
+	loader, err := NewConfigMapLoader(clientset, "conductor", "manifests", nil, nil, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewConfigMapLoader() error = %v", err)
+	}
+
+	manifests, err := loader.Overlay(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Overlay() error = %v", err)
+	}
+	if got := string(manifests["apps/ConfigMap/app-config"]); !strings.Contains(got, `password: "s3cret"`) {
+		t.Errorf("Overlay() app-config = %q, want the secret value rendered", got)
+	}
+}
+
+func TestConfigMapLoader_OverlayMissingConfigMap(t *testing.T) {
+	loader, err := NewConfigMapLoader(kubefake.NewSimpleClientset(), "conductor", "manifests", nil, nil, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewConfigMapLoader() error = %v", err)
+	}
+	if _, err := loader.Overlay(context.Background(), nil); err == nil {
+		t.Error("Overlay() of a missing ConfigMap should return an error")
+	}
+}
+
+func TestConfigMapLoader_WatchDetectsUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configMap := newManifestConfigMap(map[string]string{
+		"app-config.yaml": configMapTestManifest,
+		"cache.yaml":      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cache-config\n  namespace: apps\n",
+	})
+	clientset := kubefake.NewSimpleClientset(configMap)
+	loader, err := NewConfigMapLoader(clientset, "conductor", "manifests", nil, nil, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewConfigMapLoader() error = %v", err)
+	}
+	initial, err := loader.Overlay(ctx, map[string][]byte{"apps/ConfigMap/app-config": []byte("embedded")})
+	if err != nil {
+		t.Fatalf("Overlay() error = %v", err)
+	}
+	store := newLockedWriter(initial)
+
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	eventStore := events.NewStorage(db, logr.Discard())
+	go loader.Watch(ctx, store, eventStore)
+
+	// Change app-config and remove cache; app-config is restored to the embedded manifest
+	// once its entry is removed as well
+	updated := configMap.DeepCopy()
+	updated.Data = map[string]string{
+		"app-config.yaml": configMapTestManifest + "  format: json\n",
+	}
+	if _, err := clientset.CoreV1().ConfigMaps("conductor").Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+
+	waitFor := func(description string, condition func() bool) {
+		t.Helper()
+		deadline := time.After(5 * time.Second)
+		for !condition() {
+			select {
+			case <-store.written:
+			case <-time.After(50 * time.Millisecond):
+			case <-deadline:
+				t.Fatalf("timed out waiting for %s", description)
+			}
+		}
+	}
+	waitFor("the updated app-config", func() bool {
+		got, _ := store.get("apps/ConfigMap/app-config")
+		return got == configMapTestManifest+"  format: json\n"
+	})
+	waitFor("cache-config to be deleted", func() bool {
+		_, ok := store.get("apps/ConfigMap/cache-config")
+		return !ok
+	})
+
+	updated.Data = map[string]string{}
+	if _, err := clientset.CoreV1().ConfigMaps("conductor").Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	waitFor("app-config to be restored to the embedded manifest", func() bool {
+		got, _ := store.get("apps/ConfigMap/app-config")
+		return got == "embedded"
+	})
+	waitFor("an event per change", func() bool {
+		stored, err := eventStore.ListEvents(events.EventFilters{})
+		return err == nil && len(stored) == 3
+	})
+}
@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DependsOnAnnotation lists the manifest keys (namespace/Kind/name) or service names,
+// comma-separated, that a manifest depends on
+const DependsOnAnnotation = "conductor.io/depends-on"
+
+// ParseDependencies returns the entries listed in the depends-on annotation
+// of a manifest. A manifest without the annotation has no dependencies.
+func ParseDependencies(manifestBytes []byte) ([]string, error) {
+	var obj struct {
+		Metadata struct {
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal(manifestBytes, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	value := obj.Metadata.Annotations[DependsOnAnnotation]
+	var deps []string
+	for _, dep := range strings.Split(value, ",") {
+		dep = strings.TrimSpace(dep)
+		if dep != "" {
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "comma-separated keys",
+			yaml: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    conductor.io/depends-on: "default/Service/db, default/ConfigMap/config"
+`,
+			want: []string{"default/Service/db", "default/ConfigMap/config"},
+		},
+		{
+			name: "no annotation",
+			yaml: `apiVersion: v1
+kind: Service
+metadata:
+  name: db
+`,
+			want: nil,
+		},
+		{
+			name:    "invalid yaml",
+			yaml:    "metadata: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDependencies([]byte(tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDependencies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
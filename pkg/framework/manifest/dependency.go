@@ -0,0 +1,137 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serviceSuffixes are stripped from resource names to find the service a resource belongs to
+var serviceSuffixes = []string{"-backend", "-pvc", "-secrets", "-config"}
+
+// ServiceForKey returns the service a manifest key (namespace/Kind/name) belongs to,
+// derived from the resource name with any well-known suffix removed
+func ServiceForKey(key string) string {
+	parts := strings.Split(key, "/")
+	name := parts[len(parts)-1]
+	for _, suffix := range serviceSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
+
+// DepGraph groups manifests by service and records which services depend on which
+type DepGraph struct {
+	keys map[string][]string
+	deps map[string]map[string]bool
+}
+
+// BuildDependencyGraph groups manifests by service and reads their depends-on annotations.
+// Annotation entries may name a service or a manifest key; dependencies on services
+// without manifests are ignored.
+func BuildDependencyGraph(manifests map[string][]byte) (*DepGraph, error) {
+	g := &DepGraph{
+		keys: make(map[string][]string),
+		deps: make(map[string]map[string]bool),
+	}
+
+	for key := range manifests {
+		service := ServiceForKey(key)
+		g.keys[service] = append(g.keys[service], key)
+	}
+	for service := range g.keys {
+		sort.Strings(g.keys[service])
+	}
+
+	for key, manifestBytes := range manifests {
+		deps, err := ParseDependencies(manifestBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dependencies of %s: %w", key, err)
+		}
+
+		service := ServiceForKey(key)
+		for _, dep := range deps {
+			depService := ServiceForKey(dep)
+			if depService == service {
+				continue
+			}
+			if _, ok := g.keys[depService]; !ok {
+				continue
+			}
+			if g.deps[service] == nil {
+				g.deps[service] = make(map[string]bool)
+			}
+			g.deps[service][depService] = true
+		}
+	}
+
+	return g, nil
+}
+
+// HasDependencies reports whether any service depends on another
+func (g *DepGraph) HasDependencies() bool {
+	return len(g.deps) > 0
+}
+
+// Keys returns the sorted manifest keys belonging to service
+func (g *DepGraph) Keys(service string) []string {
+	return g.keys[service]
+}
+
+// Levels groups services so that every service depends only on services in earlier levels.
+// Services within a level are sorted by name. A dependency cycle returns an error.
+func (g *DepGraph) Levels() ([][]string, error) {
+	remaining := make(map[string]int, len(g.keys))
+	for service := range g.keys {
+		remaining[service] = len(g.deps[service])
+	}
+
+	var levels [][]string
+	for len(remaining) > 0 {
+		var level []string
+		for service, pending := range remaining {
+			if pending == 0 {
+				level = append(level, service)
+			}
+		}
+		if len(level) == 0 {
+			cycle := make([]string, 0, len(remaining))
+			for service := range remaining {
+				cycle = append(cycle, service)
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("dependency cycle between services: %s", strings.Join(cycle, ", "))
+		}
+		sort.Strings(level)
+
+		for _, service := range level {
+			delete(remaining, service)
+		}
+		for service := range remaining {
+			for _, done := range level {
+				if g.deps[service][done] {
+					remaining[service]--
+				}
+			}
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// TopologicalOrder returns the services ordered so that each follows all of its dependencies
+func (g *DepGraph) TopologicalOrder() ([]string, error) {
+	levels, err := g.Levels()
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	for _, level := range levels {
+		order = append(order, level...)
+	}
+	return order, nil
+}
@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func deploymentWithDeps(name, deps string) []byte {
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: " + name + "\n"
+	if deps != "" {
+		manifest += "  annotations:\n    conductor.io/depends-on: \"" + deps + "\"\n"
+	}
+	return []byte(manifest)
+}
+
+func TestServiceForKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"default/Deployment/postgres", "postgres"},
+		{"default/ConfigMap/postgres-config", "postgres"},
+		{"default/PersistentVolumeClaim/postgres-pvc", "postgres"},
+		{"postgres", "postgres"},
+	}
+	for _, tt := range tests {
+		if got := ServiceForKey(tt.key); got != tt.want {
+			t.Errorf("ServiceForKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestDepGraph_TopologicalOrder(t *testing.T) {
+	manifests := map[string][]byte{
+		"default/Deployment/app":            deploymentWithDeps("app", "postgres,redis"),
+		"default/Deployment/redis":          deploymentWithDeps("redis", "default/Deployment/postgres"),
+		"default/Deployment/postgres":       deploymentWithDeps("postgres", ""),
+		"default/ConfigMap/postgres-config": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: postgres-config\n"),
+	}
+
+	g, err := BuildDependencyGraph(manifests)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+	if !g.HasDependencies() {
+		t.Error("HasDependencies() = false, want true")
+	}
+
+	order, err := g.TopologicalOrder()
+	if err != nil {
+		t.Fatalf("TopologicalOrder() error = %v", err)
+	}
+	if want := []string{"postgres", "redis", "app"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("TopologicalOrder() = %v, want %v", order, want)
+	}
+
+	if want := []string{"default/ConfigMap/postgres-config", "default/Deployment/postgres"}; !reflect.DeepEqual(g.Keys("postgres"), want) {
+		t.Errorf("Keys(postgres) = %v, want %v", g.Keys("postgres"), want)
+	}
+}
+
+func TestDepGraph_Levels(t *testing.T) {
+	manifests := map[string][]byte{
+		"default/Deployment/api":    deploymentWithDeps("api", "db"),
+		"default/Deployment/worker": deploymentWithDeps("worker", "db,external"),
+		"default/Deployment/db":     deploymentWithDeps("db", ""),
+	}
+
+	g, err := BuildDependencyGraph(manifests)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	levels, err := g.Levels()
+	if err != nil {
+		t.Fatalf("Levels() error = %v", err)
+	}
+	if want := [][]string{{"db"}, {"api", "worker"}}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("Levels() = %v, want %v", levels, want)
+	}
+}
+
+func TestDepGraph_Cycle(t *testing.T) {
+	manifests := map[string][]byte{
+		"default/Deployment/a": deploymentWithDeps("a", "b"),
+		"default/Deployment/b": deploymentWithDeps("b", "c"),
+		"default/Deployment/c": deploymentWithDeps("c", "a"),
+	}
+
+	g, err := BuildDependencyGraph(manifests)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	_, err = g.TopologicalOrder()
+	if err == nil {
+		t.Fatal("TopologicalOrder() error = nil, want cycle error")
+	}
+	if !strings.Contains(err.Error(), "a, b, c") {
+		t.Errorf("TopologicalOrder() error = %v, want it to name the cycle", err)
+	}
+}
+
+func TestBuildDependencyGraph_NoDependencies(t *testing.T) {
+	g, err := BuildDependencyGraph(map[string][]byte{
+		"default/Deployment/a": deploymentWithDeps("a", ""),
+	})
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+	if g.HasDependencies() {
+		t.Error("HasDependencies() = true, want false")
+	}
+}
@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"embed"
+	"reflect"
+	"testing"
+)
+
+//go:embed testdata/files
+var filesTestFS embed.FS
+
+func TestFileSystem_ListAll(t *testing.T) {
+	fileSystem := NewFileSystem(filesTestFS, "testdata/files")
+
+	paths, err := fileSystem.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+
+	want := []string{"configmap.yaml", "redis/config.txt", "redis/service.yaml"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("ListAll() = %v, want %v", paths, want)
+	}
+}
+
+func TestFileSystem_ListAll_MissingRoot(t *testing.T) {
+	fileSystem := NewFileSystem(filesTestFS, "testdata/missing")
+
+	paths, err := fileSystem.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("ListAll() = %v, want empty list", paths)
+	}
+}
+
+func TestFileSystem_ListAll_Nil(t *testing.T) {
+	var fileSystem *FileSystem
+
+	paths, err := fileSystem.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if paths == nil || len(paths) != 0 {
+		t.Errorf("ListAll() = %v, want empty non-nil list", paths)
+	}
+}
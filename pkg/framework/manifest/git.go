@@ -0,0 +1,331 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/kubernetes"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// GitConfig describes a git repository manifests are loaded from in place of the embedded manifests
+type GitConfig struct {
+	// URL is the repository to clone, e.g. https://github.com/org/repo.git or git@github.com:org/repo.git
+	URL string
+	// Branch to check out; empty selects the default branch of the remote
+	Branch string
+	// Tag to check out instead of a branch; Branch and Tag are mutually exclusive
+	Tag string
+	// Path is the directory within the repository that holds the manifests; empty selects the root
+	Path string
+	// SSHKeySecret is the path of an SSH private key used for ssh:// and scp-style URLs,
+	// typically a mounted Kubernetes Secret
+	SSHKeySecret string
+	// PollInterval pulls the repository this often and reloads changed manifests; zero disables polling
+	PollInterval time.Duration
+}
+
+// Validate checks that the configuration selects a repository and at most one ref
+func (c GitConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("%w: git URL cannot be empty", apperrors.ErrInvalid)
+	}
+	if c.Branch != "" && c.Tag != "" {
+		return fmt.Errorf("%w: git Branch and Tag cannot both be set", apperrors.ErrInvalid)
+	}
+	if c.PollInterval < 0 {
+		return fmt.Errorf("%w: git PollInterval cannot be negative", apperrors.ErrInvalid)
+	}
+	return nil
+}
+
+// ref returns the branch or tag to fetch, or HEAD for the default branch
+func (c GitConfig) ref() string {
+	switch {
+	case c.Tag != "":
+		return c.Tag
+	case c.Branch != "":
+		return c.Branch
+	default:
+		return "HEAD"
+	}
+}
+
+// ManifestWriter is the part of store.ManifestStore that GitLoader.Watch writes reloaded manifests to
+type ManifestWriter interface {
+	PutBatch(entries map[string][]byte) error
+	Delete(key string) error
+}
+
+// GitLoader clones a git repository into a temporary directory and renders the manifests
+// under GitConfig.Path the same way LoadEmbeddedManifests renders embedded manifests.
+// It runs the git command line client, which must be on the PATH.
+type GitLoader struct {
+	config          GitConfig
+	parameterGetter ParameterGetter
+	templateFuncs   template.FuncMap
+	logger          logr.Logger
+
+	mu         sync.Mutex
+	kubeClient kubernetes.Interface // Backs secretValue and configValue; nil renders them as ""
+	dir        string               // Clone directory; empty until the first Load
+	revision   string               // Commit of the last Load
+	manifests  map[string][]byte    // Manifests of the last Load
+}
+
+// NewGitLoader returns a loader for the repository in config. Nothing is cloned until Load.
+func NewGitLoader(config GitConfig, parameterGetter ParameterGetter, templateFuncs template.FuncMap, logger logr.Logger) (*GitLoader, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &GitLoader{
+		config:          config,
+		parameterGetter: parameterGetter,
+		templateFuncs:   templateFuncs,
+		logger:          logger,
+	}, nil
+}
+
+// SetKubeClient sets the client secretValue and configValue read Secrets and ConfigMaps with
+func (l *GitLoader) SetKubeClient(client kubernetes.Interface) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.kubeClient = client
+}
+
+// PollInterval returns how often Watch pulls the repository
+func (l *GitLoader) PollInterval() time.Duration {
+	return l.config.PollInterval
+}
+
+// Revision returns the commit the last Load rendered, or "" before the first Load
+func (l *GitLoader) Revision() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.revision
+}
+
+// Load clones the repository on the first call and pulls it on later calls, then renders
+// the manifests under GitConfig.Path
+func (l *GitLoader) Load(ctx context.Context) (map[string][]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	manifests, err := l.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return copyManifests(manifests), nil
+}
+
+func (l *GitLoader) load(ctx context.Context) (map[string][]byte, error) {
+	if l.dir == "" {
+		if err := l.clone(ctx); err != nil {
+			return nil, err
+		}
+	} else if err := l.pull(ctx); err != nil {
+		return nil, err
+	}
+
+	revision, err := l.git(ctx, l.dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Clean(l.config.Path)
+	if filepath.IsAbs(root) || root == ".." || strings.HasPrefix(root, "../") {
+		return nil, fmt.Errorf("%w: git Path %q must be relative to the repository root", apperrors.ErrInvalid, l.config.Path)
+	}
+	manifests, err := loadManifestDir(os.DirFS(l.dir), root, ctx, l.parameterGetter, RenderOptions{
+		CustomFuncs: l.templateFuncs,
+		KubeClient:  l.kubeClient,
+		Logger:      l.logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifests from %s at %s: %w", redactGitURL(l.config.URL), revision, err)
+	}
+
+	l.revision = revision
+	l.manifests = manifests
+	return manifests, nil
+}
+
+func (l *GitLoader) clone(ctx context.Context) error {
+	dir, err := os.MkdirTemp("", "conductor-git-")
+	if err != nil {
+		return fmt.Errorf("failed to create clone directory: %w", err)
+	}
+
+	args := []string{"clone", "--depth", "1"}
+	if l.config.Tag != "" || l.config.Branch != "" {
+		args = append(args, "--branch", l.config.ref())
+	}
+	args = append(args, l.config.URL, dir)
+	if _, err := l.git(ctx, "", args...); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+
+	l.dir = dir
+	l.logger.Info("Cloned manifest repository", "url", redactGitURL(l.config.URL), "ref", l.config.ref())
+	return nil
+}
+
+func (l *GitLoader) pull(ctx context.Context) error {
+	if _, err := l.git(ctx, l.dir, "fetch", "--depth", "1", "origin", l.config.ref()); err != nil {
+		return err
+	}
+	_, err := l.git(ctx, l.dir, "reset", "--hard", "FETCH_HEAD")
+	return err
+}
+
+// git runs a git command in dir and returns its trimmed standard output
+func (l *GitLoader) git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if l.config.SSHKeySecret != "" {
+		// git runs GIT_SSH_COMMAND through the shell, so the key path is quoted
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -i "+shellQuote(l.config.SSHKeySecret)+" -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		output := strings.ReplaceAll(strings.TrimSpace(stderr.String()), l.config.URL, redactGitURL(l.config.URL))
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, output)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// shellQuote quotes s as a single word for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// redactGitURL removes the user information of a repository URL, which may hold an access
+// token, so it can be logged. scp-style URLs such as git@github.com:org/repo.git only name a
+// user and are returned unchanged.
+func redactGitURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.User == nil {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
+
+// Watch pulls the repository every PollInterval until ctx is cancelled, writing the manifests
+// that changed since the previous load to store and deleting those that were removed.
+// Each change is recorded as an info event.
+func (l *GitLoader) Watch(ctx context.Context, store ManifestWriter, eventStore events.EventStorage) {
+	if l.config.PollInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(l.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.sync(ctx, store, eventStore); err != nil {
+				l.logger.Error(err, "failed to reload manifests from git", "url", redactGitURL(l.config.URL))
+			}
+		}
+	}
+}
+
+// sync reloads the repository and applies the difference to the previous load to store
+func (l *GitLoader) sync(ctx context.Context, store ManifestWriter, eventStore events.EventStorage) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous := l.manifests
+	current, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string][]byte)
+	for key, data := range current {
+		if old, ok := previous[key]; !ok || !bytes.Equal(old, data) {
+			changed[key] = data
+		}
+	}
+	var removed []string
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+
+	if len(changed) > 0 {
+		if err := store.PutBatch(changed); err != nil {
+			return fmt.Errorf("failed to store manifests from git: %w", err)
+		}
+	}
+	for _, key := range removed {
+		if err := store.Delete(key); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return fmt.Errorf("failed to delete manifest %s removed from git: %w", key, err)
+		}
+	}
+
+	keys := make([]string, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		action := "updated"
+		if _, ok := previous[key]; !ok {
+			action = "added"
+		}
+		events.StoreEventSafeContext(ctx, eventStore, l.logger, events.Info(key, "git-sync", fmt.Sprintf("Manifest %s from git revision %s", action, l.revision)))
+	}
+	for _, key := range removed {
+		events.StoreEventSafeContext(ctx, eventStore, l.logger, events.Info(key, "git-sync", fmt.Sprintf("Manifest removed from git revision %s", l.revision)))
+	}
+
+	if len(changed) > 0 || len(removed) > 0 {
+		l.logger.Info("Reloaded manifests from git", "revision", l.revision, "changed", len(changed), "removed", len(removed))
+	}
+	return nil
+}
+
+// Close removes the clone directory
+func (l *GitLoader) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(l.dir)
+	l.dir = ""
+	return err
+}
+
+func copyManifests(manifests map[string][]byte) map[string][]byte {
+	result := make(map[string][]byte, len(manifests))
+	for key, data := range manifests {
+		result[key] = data
+	}
+	return result
+}
@@ -0,0 +1,650 @@
+package manifest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// HelmChartConfig describes a Helm chart rendered as an additional manifest source
+type HelmChartConfig struct {
+	// RepoURL is either a chart repository served over http(s), or a local directory
+	// (optionally prefixed with file://) that contains the chart directory ChartName
+	RepoURL string
+	// ChartName is the chart to render; it is also the release name and the services.<name>
+	// key of the DeploymentParameters spec whose values override the chart values
+	ChartName string
+	// Version selects the chart version in a remote repository; empty selects the latest.
+	// A local chart must declare this version in Chart.yaml when it is set.
+	Version string
+	// ValuesFS holds YAML values files, merged in path order over the chart's values.yaml
+	ValuesFS embed.FS
+}
+
+// helmKubeVersion is reported as .Capabilities.KubeVersion to chart templates
+const helmKubeVersion = "v1.34.0"
+
+// helmHTTPClient downloads repository indexes and chart archives
+var helmHTTPClient = &http.Client{Timeout: time.Minute}
+
+// helmChartMetadata is the subset of Chart.yaml exposed to templates as .Chart
+type helmChartMetadata struct {
+	APIVersion   string        `yaml:"apiVersion"`
+	Name         string        `yaml:"name"`
+	Version      string        `yaml:"version"`
+	AppVersion   string        `yaml:"appVersion"`
+	Description  string        `yaml:"description"`
+	Type         string        `yaml:"type"`
+	Dependencies []interface{} `yaml:"dependencies"`
+}
+
+// helmChart is a chart loaded into memory; files are keyed by their path within the chart
+type helmChart struct {
+	metadata helmChartMetadata
+	files    map[string][]byte
+}
+
+// hasSubcharts reports whether the chart vendors subcharts under charts/
+func (c *helmChart) hasSubcharts() bool {
+	for name := range c.files {
+		if strings.HasPrefix(name, "charts/") {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadHelmManifests renders every chart in charts with RenderHelmChart and returns the merged
+// manifests. Each chart's ValuesFS values are overridden by the global and services.<ChartName>
+// sections of the spec.
+func LoadHelmManifests(ctx context.Context, charts []HelmChartConfig, parameterGetter ParameterGetter) (map[string][]byte, error) {
+	manifests := make(map[string][]byte)
+	if len(charts) == 0 {
+		return manifests, nil
+	}
+
+	spec := loadSpec(ctx, parameterGetter)
+	for _, chart := range charts {
+		values, err := helmValues(chart, spec)
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := RenderHelmChart(ctx, chart, values)
+		if err != nil {
+			return nil, err
+		}
+		for key, data := range rendered {
+			if _, duplicate := manifests[key]; duplicate {
+				return nil, fmt.Errorf("helm chart %s renders %s, which another chart already renders", chart.ChartName, key)
+			}
+			manifests[key] = data
+		}
+	}
+	return manifests, nil
+}
+
+// helmValues merges the YAML files of cfg.ValuesFS in path order and overrides them with the
+// global section of spec, available as .Values.global, and the services.<ChartName> section
+func helmValues(cfg HelmChartConfig, spec map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	err := iofs.WalkDir(cfg.ValuesFS, ".", func(p string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (!strings.HasSuffix(p, ".yaml") && !strings.HasSuffix(p, ".yml")) {
+			return nil
+		}
+		data, err := cfg.ValuesFS.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		var fileValues map[string]interface{}
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+		values = mergeValues(values, fileValues)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load values for helm chart %s: %w", cfg.ChartName, err)
+	}
+
+	if global, ok := spec["global"].(map[string]interface{}); ok {
+		values = mergeValues(values, map[string]interface{}{"global": global})
+	}
+	if services, ok := spec["services"].(map[string]interface{}); ok {
+		if overrides, ok := services[cfg.ChartName].(map[string]interface{}); ok {
+			values = mergeValues(values, overrides)
+		}
+	}
+	return values, nil
+}
+
+// RenderHelmChart renders the templates of the chart described by cfg with values merged over
+// the chart's values.yaml, and returns the resulting objects keyed by namespace/Kind/name.
+// Templates see .Values, .Release, .Chart, .Capabilities, .Template and .Files, and may use
+// Sprig plus Helm's include, tpl, required, toYaml, fromYaml, toJson and fromJson. Objects
+// without a namespace are placed in the release namespace, which is global.namespace of the
+// values or "default". Charts with dependencies are rejected.
+func RenderHelmChart(ctx context.Context, cfg HelmChartConfig, values map[string]interface{}) (map[string][]byte, error) {
+	if cfg.ChartName == "" {
+		return nil, fmt.Errorf("helm chart name is required")
+	}
+
+	chart, err := loadHelmChart(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helm chart %s: %w", cfg.ChartName, err)
+	}
+	if len(chart.metadata.Dependencies) > 0 || chart.hasSubcharts() {
+		return nil, fmt.Errorf("helm chart %s declares dependencies, which are not supported", cfg.ChartName)
+	}
+
+	chartValues := make(map[string]interface{})
+	if data, ok := chart.files["values.yaml"]; ok {
+		if err := yaml.Unmarshal(data, &chartValues); err != nil {
+			return nil, fmt.Errorf("failed to parse values.yaml of helm chart %s: %w", cfg.ChartName, err)
+		}
+		if chartValues == nil {
+			chartValues = make(map[string]interface{})
+		}
+	}
+	chartValues = mergeValues(chartValues, values)
+
+	namespace := "default"
+	if global, ok := chartValues["global"].(map[string]interface{}); ok {
+		if ns, ok := global["namespace"].(string); ok && ns != "" {
+			namespace = ns
+		}
+	}
+
+	rendered, err := executeHelmTemplates(ctx, chart, chartValues, cfg.ChartName, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm chart %s: %w", cfg.ChartName, err)
+	}
+
+	manifests := make(map[string][]byte)
+	for _, name := range sortedKeys(rendered) {
+		docs, err := SplitMultiDoc([]byte(rendered[name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to split %s: %w", name, err)
+		}
+		for _, doc := range docs {
+			doc, err = defaultHelmNamespace(doc, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			if doc == nil {
+				continue
+			}
+			key, err := extractKeyFromYAML(doc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract key from %s: %w", name, err)
+			}
+			if _, duplicate := manifests[key]; duplicate {
+				return nil, fmt.Errorf("helm chart %s renders %s more than once", cfg.ChartName, key)
+			}
+			manifests[key] = doc
+		}
+	}
+	return manifests, nil
+}
+
+// executeHelmTemplates parses every file under templates/ into one template set and executes
+// each non-partial template, returning the output keyed by template name
+func executeHelmTemplates(ctx context.Context, chart *helmChart, values map[string]interface{}, releaseName, namespace string) (map[string]string, error) {
+	var root *template.Template
+	funcs := helmFuncMap(func() *template.Template { return root })
+	root = template.New(chart.metadata.Name).Funcs(funcs).Option("missingkey=zero")
+
+	var names []string
+	for _, name := range sortedKeys(chart.files) {
+		if !strings.HasPrefix(name, "templates/") {
+			continue
+		}
+		fullName := path.Join(chart.metadata.Name, name)
+		if _, err := root.New(fullName).Parse(string(chart.files[name])); err != nil {
+			return nil, err
+		}
+		base := path.Base(name)
+		if strings.HasPrefix(base, "_") || strings.EqualFold(base, "NOTES.txt") {
+			continue
+		}
+		names = append(names, fullName)
+	}
+
+	data := map[string]interface{}{
+		"Values": values,
+		"Release": map[string]interface{}{
+			"Name":      releaseName,
+			"Namespace": namespace,
+			"Service":   "Helm",
+			"IsInstall": true,
+			"IsUpgrade": false,
+			"Revision":  1,
+		},
+		"Chart": map[string]interface{}{
+			"Name":        chart.metadata.Name,
+			"Version":     chart.metadata.Version,
+			"AppVersion":  chart.metadata.AppVersion,
+			"Description": chart.metadata.Description,
+			"Type":        chart.metadata.Type,
+		},
+		"Capabilities": helmCapabilities{KubeVersion: newHelmKubeVersion(helmKubeVersion)},
+		"Files":        helmFiles(chart.files),
+	}
+
+	output := make(map[string]string, len(names))
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data["Template"] = map[string]interface{}{
+			"Name":     name,
+			"BasePath": path.Join(chart.metadata.Name, "templates"),
+		}
+		var buf bytes.Buffer
+		if err := root.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, err
+		}
+		output[name] = strings.ReplaceAll(buf.String(), "<no value>", "")
+	}
+	return output, nil
+}
+
+// helmFuncMap returns Sprig and the Helm template functions. root returns the template set
+// that include and tpl resolve named templates against.
+func helmFuncMap(root func() *template.Template) template.FuncMap {
+	funcs := TemplateFuncs(nil)
+	funcs["toYaml"] = func(v interface{}) string {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return ""
+		}
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+	funcs["fromYaml"] = func(s string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	funcs["fromJson"] = func(s string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	funcs["required"] = func(message string, value interface{}) (interface{}, error) {
+		if value == nil {
+			return nil, errors.New(message)
+		}
+		if s, ok := value.(string); ok && s == "" {
+			return nil, errors.New(message)
+		}
+		return value, nil
+	}
+	funcs["include"] = func(name string, data interface{}) (string, error) {
+		var buf bytes.Buffer
+		if err := root().ExecuteTemplate(&buf, name, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	funcs["tpl"] = func(text string, data interface{}) (string, error) {
+		// A template set cannot be cloned once executed, so share its parse trees instead
+		t := template.New("tpl").Funcs(funcs).Option("missingkey=zero")
+		for _, defined := range root().Templates() {
+			if defined.Tree == nil {
+				continue
+			}
+			if _, err := t.AddParseTree(defined.Name(), defined.Tree); err != nil {
+				return "", err
+			}
+		}
+		if _, err := t.New("tpl-text").Parse(text); err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, "tpl-text", data); err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+	}
+	// lookup needs a live cluster; like helm template, it finds nothing
+	funcs["lookup"] = func(apiVersion, kind, namespace, name string) map[string]interface{} {
+		return map[string]interface{}{}
+	}
+	return funcs
+}
+
+// helmCapabilities is exposed to chart templates as .Capabilities
+type helmCapabilities struct {
+	KubeVersion helmKubeVersionInfo
+	APIVersions helmAPIVersions
+}
+
+type helmKubeVersionInfo struct {
+	Version string
+	Major   string
+	Minor   string
+}
+
+func newHelmKubeVersion(version string) helmKubeVersionInfo {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	info := helmKubeVersionInfo{Version: version}
+	if len(parts) >= 2 {
+		info.Major, info.Minor = parts[0], parts[1]
+	}
+	return info
+}
+
+// String lets templates print .Capabilities.KubeVersion directly
+func (v helmKubeVersionInfo) String() string {
+	return v.Version
+}
+
+// GitVersion is the deprecated alias Helm keeps for Version
+func (v helmKubeVersionInfo) GitVersion() string {
+	return v.Version
+}
+
+// helmAPIVersions answers .Capabilities.APIVersions.Has from the built-in Kubernetes types
+type helmAPIVersions struct{}
+
+// Has reports whether apiVersion ("apps/v1") or apiVersion/Kind ("apps/v1/Deployment") is a built-in type
+func (helmAPIVersions) Has(apiVersion string) bool {
+	if i := strings.LastIndex(apiVersion, "/"); i > 0 {
+		if gv, err := schema.ParseGroupVersion(apiVersion[:i]); err == nil && scheme.Scheme.Recognizes(gv.WithKind(apiVersion[i+1:])) {
+			return true
+		}
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	return err == nil && scheme.Scheme.IsVersionRegistered(gv)
+}
+
+// helmFiles is exposed to chart templates as .Files
+type helmFiles map[string][]byte
+
+// Get returns the contents of a file in the chart, or "" if it does not exist
+func (f helmFiles) Get(name string) string {
+	return string(f[name])
+}
+
+// GetBytes returns the contents of a file in the chart, or nil if it does not exist
+func (f helmFiles) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// defaultHelmNamespace sets metadata.namespace of a namespaced object that has none. It returns
+// nil for documents that hold no object, such as those emptied by a conditional.
+func defaultHelmNamespace(doc []byte, namespace string) ([]byte, error) {
+	var object map[string]interface{}
+	if err := yaml.Unmarshal(doc, &object); err != nil {
+		return nil, err
+	}
+	if len(object) == 0 {
+		return nil, nil
+	}
+
+	kind, _ := object["kind"].(string)
+	if clusterScopedKinds[kind] || stringAt(object, "metadata", "namespace") != "" {
+		return doc, nil
+	}
+	ensureMap(object, "metadata")["namespace"] = namespace
+	return yaml.Marshal(object)
+}
+
+// loadHelmChart reads the chart from a local directory or downloads it from a chart repository
+func loadHelmChart(ctx context.Context, cfg HelmChartConfig) (*helmChart, error) {
+	var files map[string][]byte
+	var err error
+	if strings.HasPrefix(cfg.RepoURL, "http://") || strings.HasPrefix(cfg.RepoURL, "https://") {
+		files, err = downloadHelmChart(ctx, cfg)
+	} else {
+		dir := path.Join(strings.TrimPrefix(cfg.RepoURL, "file://"), cfg.ChartName)
+		files, err = readHelmChartDir(os.DirFS(dir))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := files["Chart.yaml"]
+	if !ok {
+		return nil, fmt.Errorf("Chart.yaml not found")
+	}
+	chart := &helmChart{files: files}
+	if err := yaml.Unmarshal(data, &chart.metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+	if chart.metadata.Name == "" {
+		chart.metadata.Name = cfg.ChartName
+	}
+	if cfg.Version != "" && chart.metadata.Version != cfg.Version {
+		return nil, fmt.Errorf("chart version is %s, want %s", chart.metadata.Version, cfg.Version)
+	}
+	return chart, nil
+}
+
+// readHelmChartDir reads every file of a chart directory
+func readHelmChartDir(dir iofs.FS) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := iofs.WalkDir(dir, ".", func(p string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := iofs.ReadFile(dir, p)
+		if err != nil {
+			return err
+		}
+		files[p] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// helmRepoIndex is the subset of a chart repository index.yaml used to locate a chart archive
+type helmRepoIndex struct {
+	Entries map[string][]struct {
+		Version string   `yaml:"version"`
+		URLs    []string `yaml:"urls"`
+	} `yaml:"entries"`
+}
+
+// downloadHelmChart resolves the chart version in the repository index and extracts its archive.
+// Repositories list versions newest first, so an empty Version selects the first entry.
+func downloadHelmChart(ctx context.Context, cfg HelmChartConfig) (map[string][]byte, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.RepoURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL %s: %w", cfg.RepoURL, err)
+	}
+
+	data, err := httpGet(ctx, base.ResolveReference(&url.URL{Path: "index.yaml"}).String())
+	if err != nil {
+		return nil, err
+	}
+	var index helmRepoIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse repository index: %w", err)
+	}
+
+	var archiveURL string
+	for _, entry := range index.Entries[cfg.ChartName] {
+		if (cfg.Version == "" || entry.Version == cfg.Version) && len(entry.URLs) > 0 {
+			archiveURL = entry.URLs[0]
+			break
+		}
+	}
+	if archiveURL == "" {
+		return nil, fmt.Errorf("chart %s version %q not found in %s", cfg.ChartName, cfg.Version, cfg.RepoURL)
+	}
+	ref, err := url.Parse(archiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart URL %s: %w", archiveURL, err)
+	}
+
+	archive, err := httpGet(ctx, base.ResolveReference(ref).String())
+	if err != nil {
+		return nil, err
+	}
+	return extractHelmArchive(archive)
+}
+
+func httpGet(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := helmHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", target, resp.Status)
+	}
+	data, err := readLimited(resp.Body, maxHelmDownloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", target, err)
+	}
+	return data, nil
+}
+
+// Limits on downloaded charts, so a hostile or broken repository cannot exhaust memory
+const (
+	// maxHelmDownloadBytes bounds a repository index or a compressed chart archive
+	maxHelmDownloadBytes = 32 << 20
+	// maxHelmFileBytes bounds one decompressed file of a chart archive
+	maxHelmFileBytes = 4 << 20
+	// maxHelmChartBytes bounds all decompressed files of a chart archive together
+	maxHelmChartBytes = 64 << 20
+	// maxHelmChartEntries bounds the entries of a chart archive
+	maxHelmChartEntries = 10000
+)
+
+// readLimited reads r to the end, failing when it holds more than limit bytes
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("exceeds the limit of %d bytes", limit)
+	}
+	return data, nil
+}
+
+// extractHelmArchive reads a packaged chart, dropping the top-level chart directory from each path
+func extractHelmArchive(archive []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	var entries int
+	var total int64
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chart archive: %w", err)
+		}
+		if entries++; entries > maxHelmChartEntries {
+			return nil, fmt.Errorf("chart archive has more than %d entries", maxHelmChartEntries)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		i := strings.Index(name, "/")
+		if i < 0 || strings.HasPrefix(name, "../") {
+			continue
+		}
+		data, err := readLimited(tr, maxHelmFileBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from chart archive: %w", header.Name, err)
+		}
+		if total += int64(len(data)); total > maxHelmChartBytes {
+			return nil, fmt.Errorf("chart archive exceeds %d bytes uncompressed", maxHelmChartBytes)
+		}
+		files[name[i+1:]] = data
+	}
+	return files, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// clusterScopedKinds are left without a namespace when a chart namespace is applied
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"PersistentVolume":               true,
+	"StorageClass":                   true,
+	"PriorityClass":                  true,
+	"MutatingWebhookConfiguration":   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+func ensureMap(object map[string]interface{}, field string) map[string]interface{} {
+	if m, ok := object[field].(map[string]interface{}); ok {
+		return m
+	}
+	m := make(map[string]interface{})
+	object[field] = m
+	return m
+}
+
+func stringAt(object map[string]interface{}, fields ...string) string {
+	var current interface{} = object
+	for _, field := range fields {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[field]
+	}
+	value, _ := current.(string)
+	return value
+}
@@ -0,0 +1,234 @@
+package manifest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed testdata/helm-values
+var helmValuesTestFS embed.FS
+
+// helmDeployment is the subset of the rendered webapp Deployment the tests inspect
+type helmDeployment struct {
+	Metadata struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Replicas int `yaml:"replicas"`
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image     string                 `yaml:"image"`
+					Resources map[string]interface{} `yaml:"resources"`
+				} `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+func decodeHelmDeployment(t *testing.T, manifests map[string][]byte, key string) helmDeployment {
+	t.Helper()
+	data, ok := manifests[key]
+	if !ok {
+		t.Fatalf("manifests keys = %v, want %s", keysOf(manifests), key)
+	}
+	var deployment helmDeployment
+	if err := yaml.Unmarshal(data, &deployment); err != nil {
+		t.Fatalf("failed to parse %s: %v", key, err)
+	}
+	return deployment
+}
+
+func TestRenderHelmChart_Defaults(t *testing.T) {
+	cfg := HelmChartConfig{RepoURL: "testdata/helm", ChartName: "webapp"}
+	manifests, err := RenderHelmChart(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("RenderHelmChart() error = %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("RenderHelmChart() keys = %v, want a Deployment and a Service", keysOf(manifests))
+	}
+	if _, ok := manifests["default/Service/webapp-webapp"]; !ok {
+		t.Errorf("RenderHelmChart() keys = %v, want default/Service/webapp-webapp", keysOf(manifests))
+	}
+
+	deployment := decodeHelmDeployment(t, manifests, "default/Deployment/webapp-webapp")
+	if deployment.Spec.Replicas != 1 {
+		t.Errorf("replicas = %d, want 1", deployment.Spec.Replicas)
+	}
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "nginx:1.25" {
+		t.Errorf("image = %q, want nginx:1.25 from the chart appVersion", image)
+	}
+	if deployment.Metadata.Namespace != "default" {
+		t.Errorf("namespace = %q, want default", deployment.Metadata.Namespace)
+	}
+	if deployment.Metadata.Labels["app.kubernetes.io/managed-by"] != "Helm" {
+		t.Errorf("labels = %v, want the helper labels", deployment.Metadata.Labels)
+	}
+}
+
+func TestLoadHelmManifests_MergesValues(t *testing.T) {
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{"namespace": "apps"},
+		"services": map[string]interface{}{
+			"webapp": map[string]interface{}{
+				"replicaCount": 3,
+				"service":      map[string]interface{}{"enabled": false},
+			},
+		},
+	}
+	charts := []HelmChartConfig{{RepoURL: "file://testdata/helm", ChartName: "webapp", Version: "0.1.0", ValuesFS: helmValuesTestFS}}
+	getter := func(ctx context.Context) (map[string]interface{}, error) { return spec, nil }
+
+	manifests, err := LoadHelmManifests(context.Background(), charts, getter)
+	if err != nil {
+		t.Fatalf("LoadHelmManifests() error = %v", err)
+	}
+	if len(manifests) != 1 {
+		t.Fatalf("LoadHelmManifests() keys = %v, want only the Deployment once the Service is disabled", keysOf(manifests))
+	}
+
+	deployment := decodeHelmDeployment(t, manifests, "apps/Deployment/webapp-webapp")
+	if deployment.Spec.Replicas != 3 {
+		t.Errorf("replicas = %d, want 3 from the DeploymentParameters spec", deployment.Spec.Replicas)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if container.Image != "nginx:1.27" {
+		t.Errorf("image = %q, want nginx:1.27 from ValuesFS", container.Image)
+	}
+	if limits, _ := container.Resources["limits"].(map[string]interface{}); limits["memory"] != "128Mi" {
+		t.Errorf("resources = %v, want the ValuesFS limits rendered by toYaml", container.Resources)
+	}
+}
+
+func TestRenderHelmChart_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  HelmChartConfig
+	}{
+		{"missing chart name", HelmChartConfig{RepoURL: "testdata/helm"}},
+		{"missing chart", HelmChartConfig{RepoURL: "testdata/helm", ChartName: "missing"}},
+		{"version mismatch", HelmChartConfig{RepoURL: "testdata/helm", ChartName: "webapp", Version: "9.9.9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RenderHelmChart(context.Background(), tt.cfg, nil); err == nil {
+				t.Error("RenderHelmChart() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestRenderHelmChart_RemoteRepository(t *testing.T) {
+	archive := packageHelmChart(t, "testdata/helm/webapp", "webapp")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			fmt.Fprint(w, "apiVersion: v1\nentries:\n  webapp:\n    - version: 0.1.0\n      urls:\n        - webapp-0.1.0.tgz\n")
+		case "/charts/webapp-0.1.0.tgz":
+			w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := HelmChartConfig{RepoURL: server.URL + "/charts", ChartName: "webapp", Version: "0.1.0"}
+	manifests, err := RenderHelmChart(context.Background(), cfg, map[string]interface{}{"replicaCount": 4})
+	if err != nil {
+		t.Fatalf("RenderHelmChart() error = %v", err)
+	}
+	if deployment := decodeHelmDeployment(t, manifests, "default/Deployment/webapp-webapp"); deployment.Spec.Replicas != 4 {
+		t.Errorf("replicas = %d, want 4", deployment.Spec.Replicas)
+	}
+
+	cfg.Version = "0.2.0"
+	if _, err := RenderHelmChart(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("RenderHelmChart() with an unknown version error = %v, want not found", err)
+	}
+}
+
+// packageHelmChart builds a chart archive the way helm package lays it out
+func packageHelmChart(t *testing.T, dir, name string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	err := fs.WalkDir(os.DirFS(dir), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path.Join(dir, p))
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Name: path.Join(name, p), Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to package chart: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to package chart: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to package chart: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractHelmArchive_Limits(t *testing.T) {
+	archive := func(sizes ...int) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for i, size := range sizes {
+			header := &tar.Header{Name: fmt.Sprintf("chart/templates/%d.yaml", i), Mode: 0o644, Size: int64(size), Typeflag: tar.TypeReg}
+			if err := tw.WriteHeader(header); err != nil {
+				t.Fatalf("failed to write archive: %v", err)
+			}
+			if _, err := tw.Write(make([]byte, size)); err != nil {
+				t.Fatalf("failed to write archive: %v", err)
+			}
+		}
+		tw.Close()
+		gz.Close()
+		return buf.Bytes()
+	}
+
+	if _, err := extractHelmArchive(archive(maxHelmFileBytes + 1)); err == nil {
+		t.Error("extractHelmArchive() with an oversized file error = nil, want an error")
+	}
+	sizes := make([]int, maxHelmChartBytes/maxHelmFileBytes+1)
+	for i := range sizes {
+		sizes[i] = maxHelmFileBytes
+	}
+	if _, err := extractHelmArchive(archive(sizes...)); err == nil {
+		t.Error("extractHelmArchive() beyond the total size error = nil, want an error")
+	}
+	if _, err := extractHelmArchive(archive(make([]int, maxHelmChartEntries+1)...)); err == nil {
+		t.Error("extractHelmArchive() with too many entries error = nil, want an error")
+	}
+	if files, err := extractHelmArchive(archive(10)); err != nil || len(files) != 1 {
+		t.Errorf("extractHelmArchive() = %d files, %v, want 1 file", len(files), err)
+	}
+}
@@ -0,0 +1,83 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyImageOverrides sets the image tag of the containers of a Deployment or StatefulSet
+// whose name is a key of overrides. Containers without an override and manifests of other
+// kinds are returned unchanged.
+func ApplyImageOverrides(manifestYAML []byte, overrides map[string]string) ([]byte, error) {
+	if len(overrides) == 0 {
+		return manifestYAML, nil
+	}
+
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(manifestYAML, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if kind, _ := obj["kind"].(string); kind != "Deployment" && kind != "StatefulSet" {
+		return manifestYAML, nil
+	}
+
+	podSpec, ok := nestedMap(obj, "spec", "template", "spec")
+	if !ok {
+		return manifestYAML, nil
+	}
+	containers, _ := podSpec["containers"].([]interface{})
+
+	changed := false
+	for _, item := range containers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := container["name"].(string)
+		tag, ok := overrides[name]
+		if !ok {
+			continue
+		}
+		image, _ := container["image"].(string)
+		if updated := imageWithTag(image, tag); updated != image {
+			container["image"] = updated
+			changed = true
+		}
+	}
+	if !changed {
+		return manifestYAML, nil
+	}
+
+	result, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return result, nil
+}
+
+// imageWithTag replaces the tag or digest of image with tag. A colon before the last "/"
+// belongs to a registry port, not a tag.
+func imageWithTag(image, tag string) string {
+	repository := image
+	if at := strings.Index(repository, "@"); at != -1 {
+		repository = repository[:at]
+	}
+	if colon := strings.LastIndex(repository, ":"); colon > strings.LastIndex(repository, "/") {
+		repository = repository[:colon]
+	}
+	return repository + ":" + tag
+}
+
+func nestedMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool) {
+	current := obj
+	for _, field := range fields {
+		next, ok := current[field].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const multiContainerDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: registry.example.com:5000/web/app:1.0
+      - name: sidecar
+        image: envoyproxy/envoy@sha256:abcdef
+      - name: metrics
+        image: prom/exporter:0.9
+`
+
+func containerImages(t *testing.T, manifestYAML []byte) map[string]string {
+	t.Helper()
+	var obj struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Name  string `yaml:"name"`
+						Image string `yaml:"image"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(manifestYAML, &obj); err != nil {
+		t.Fatalf("failed to parse manifest: %v", err)
+	}
+	images := make(map[string]string)
+	for _, container := range obj.Spec.Template.Spec.Containers {
+		images[container.Name] = container.Image
+	}
+	return images
+}
+
+func TestApplyImageOverrides(t *testing.T) {
+	result, err := ApplyImageOverrides([]byte(multiContainerDeployment), map[string]string{
+		"app":     "2.0",
+		"sidecar": "v1.29",
+		"missing": "3.0",
+	})
+	if err != nil {
+		t.Fatalf("ApplyImageOverrides() error = %v", err)
+	}
+
+	want := map[string]string{
+		"app":     "registry.example.com:5000/web/app:2.0",
+		"sidecar": "envoyproxy/envoy:v1.29",
+		"metrics": "prom/exporter:0.9",
+	}
+	got := containerImages(t, result)
+	for name, image := range want {
+		if got[name] != image {
+			t.Errorf("container %s image = %q, want %q", name, got[name], image)
+		}
+	}
+}
+
+func TestApplyImageOverrides_Unchanged(t *testing.T) {
+	configMap := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n")
+	tests := []struct {
+		name      string
+		manifest  []byte
+		overrides map[string]string
+	}{
+		{name: "no overrides", manifest: []byte(multiContainerDeployment)},
+		{name: "no matching container", manifest: []byte(multiContainerDeployment), overrides: map[string]string{"worker": "2.0"}},
+		{name: "other kind", manifest: configMap, overrides: map[string]string{"app": "2.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyImageOverrides(tt.manifest, tt.overrides)
+			if err != nil {
+				t.Fatalf("ApplyImageOverrides() error = %v", err)
+			}
+			if string(result) != string(tt.manifest) {
+				t.Errorf("ApplyImageOverrides() changed the manifest:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestImageWithTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "nginx", want: "nginx:1.27"},
+		{image: "nginx:1.25", want: "nginx:1.27"},
+		{image: "localhost:5000/nginx", want: "localhost:5000/nginx:1.27"},
+		{image: "localhost:5000/nginx:1.25", want: "localhost:5000/nginx:1.27"},
+		{image: "nginx@sha256:abcdef", want: "nginx:1.27"},
+	}
+
+	for _, tt := range tests {
+		if got := imageWithTag(tt.image, "1.27"); got != tt.want {
+			t.Errorf("imageWithTag(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package manifest
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// RenderKustomize renders the kustomization directory root of fs with kustomize and returns
+// the resulting objects keyed by namespace/Kind/name. Everything kustomize builds without
+// plugins is supported, including patches, components and generators; the kustomization may
+// refer to any other directory of fs, such as a base above it.
+func RenderKustomize(ctx context.Context, root string, fs embed.FS) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	memFS, err := copyToMemFS(fs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kustomization %s: %w", root, err)
+	}
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(memFS, path.Join("/", root))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kustomization %s: %w", root, err)
+	}
+
+	manifests := make(map[string][]byte, resources.Size())
+	for _, resource := range resources.Resources() {
+		data, err := resource.AsYAML()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", resource.CurId(), err)
+		}
+		key, err := extractKeyFromYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract key from rendered resource %s: %w", resource.CurId(), err)
+		}
+		if _, duplicate := manifests[key]; duplicate {
+			return nil, fmt.Errorf("kustomization %s renders %s more than once", root, key)
+		}
+		manifests[key] = data
+	}
+	return manifests, nil
+}
+
+// copyToMemFS copies files into an in-memory filesystem rooted at "/", which is what
+// kustomize reads from
+func copyToMemFS(files fs.FS) (filesys.FileSystem, error) {
+	memFS := filesys.MakeFsInMemory()
+	err := fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return memFS.MkdirAll(path.Join("/", name))
+		}
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		return memFS.WriteFile(path.Join("/", name), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return memFS, nil
+}
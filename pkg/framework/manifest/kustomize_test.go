@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"context"
+	"embed"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed testdata/kustomize
+var kustomizeTestFS embed.FS
+
+func TestRenderKustomize_Overlay(t *testing.T) {
+	manifests, err := RenderKustomize(context.Background(), "testdata/kustomize/overlays/prod", kustomizeTestFS)
+	if err != nil {
+		t.Fatalf("RenderKustomize() error = %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("RenderKustomize() returned %d manifests, want 2: %v", len(manifests), manifests)
+	}
+
+	data, ok := manifests["prod/Deployment/prod-web"]
+	if !ok {
+		t.Fatalf("RenderKustomize() keys = %v, want prod/Deployment/prod-web", keysOf(manifests))
+	}
+	if _, ok := manifests["prod/Service/prod-web"]; !ok {
+		t.Errorf("RenderKustomize() keys = %v, want prod/Service/prod-web", keysOf(manifests))
+	}
+
+	var deployment struct {
+		Metadata struct {
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Replicas int `yaml:"replicas"`
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Name      string                 `yaml:"name"`
+						Image     string                 `yaml:"image"`
+						Resources map[string]interface{} `yaml:"resources"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(data, &deployment); err != nil {
+		t.Fatalf("rendered Deployment is not valid YAML: %v", err)
+	}
+
+	if deployment.Spec.Replicas != 3 {
+		t.Errorf("replicas = %d, want the patched value 3", deployment.Spec.Replicas)
+	}
+	if deployment.Metadata.Annotations["team"] != "platform" {
+		t.Errorf("annotations = %v, want team=platform", deployment.Metadata.Annotations)
+	}
+	containers := deployment.Spec.Template.Spec.Containers
+	if len(containers) != 2 {
+		t.Fatalf("containers = %+v, want the patch merged into the web container by name", containers)
+	}
+	if containers[0].Image != "nginx:{{ .Values.imageTag }}" || containers[0].Resources == nil {
+		t.Errorf("web container = %+v, want the base image and the patched resources", containers[0])
+	}
+}
+
+func TestRenderKustomize_Base(t *testing.T) {
+	manifests, err := RenderKustomize(context.Background(), "testdata/kustomize/base", kustomizeTestFS)
+	if err != nil {
+		t.Fatalf("RenderKustomize() error = %v", err)
+	}
+	if !strings.Contains(string(manifests["default/Deployment/web"]), "replicas: 1") {
+		t.Errorf("base Deployment = %s, want replicas: 1", manifests["default/Deployment/web"])
+	}
+}
+
+func TestRenderKustomize_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+	}{
+		{name: "missing kustomization", root: "testdata/kustomize"},
+		{name: "missing directory", root: "testdata/kustomize/overlays/staging"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RenderKustomize(context.Background(), tt.root, kustomizeTestFS); err == nil {
+				t.Errorf("RenderKustomize(%q) error = nil, want an error", tt.root)
+			}
+		})
+	}
+}
+
+func TestLoadKustomizeManifests_RendersTemplates(t *testing.T) {
+	manifests, err := LoadKustomizeManifests(kustomizeTestFS, "testdata/kustomize/overlays/prod", context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("LoadKustomizeManifests() error = %v", err)
+	}
+
+	data := string(manifests["prod/Deployment/prod-web"])
+	if !strings.Contains(data, "image: nginx:1.27") {
+		t.Errorf("LoadKustomizeManifests() Deployment = %s, want the image tag from values.yaml", data)
+	}
+	if !strings.Contains(data, "replicas: 3") {
+		t.Errorf("LoadKustomizeManifests() Deployment = %s, want replicas: 3", data)
+	}
+}
+
+func TestRenderKustomize_GeneratorsAndComponents(t *testing.T) {
+	manifests, err := RenderKustomize(context.Background(), "testdata/kustomize/overlays/staging-generated", kustomizeTestFS)
+	if err != nil {
+		t.Fatalf("RenderKustomize() error = %v", err)
+	}
+
+	var configMapKey string
+	for key := range manifests {
+		if strings.HasPrefix(key, "staging/ConfigMap/web-settings-") {
+			configMapKey = key
+		}
+	}
+	if configMapKey == "" {
+		t.Fatalf("RenderKustomize() keys = %v, want a generated ConfigMap with a hash suffix", keysOf(manifests))
+	}
+	if !strings.Contains(string(manifests[configMapKey]), "LOG_LEVEL: debug") {
+		t.Errorf("generated ConfigMap = %s, want the literal from configMapGenerator", manifests[configMapKey])
+	}
+
+	// References to the generated ConfigMap are rewritten to its hashed name, and the
+	// component's label is added to every resource
+	deployment := string(manifests["staging/Deployment/web"])
+	name := configMapKey[len("staging/ConfigMap/"):]
+	if !strings.Contains(deployment, "name: "+name) {
+		t.Errorf("Deployment = %s, want the envFrom reference rewritten to %s", deployment, name)
+	}
+	if !strings.Contains(deployment, "tier: frontend") {
+		t.Errorf("Deployment = %s, want the label added by the component", deployment)
+	}
+}
+
+func keysOf(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
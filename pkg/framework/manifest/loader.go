@@ -20,12 +20,24 @@ type ParameterGetter func(ctx context.Context) (map[string]interface{}, error)
 // If templateFuncs is nil, default functions (Sprig + built-ins) are used
 // rootPath specifies the root directory path in the embedded filesystem (e.g., "manifests" or "")
 func LoadEmbeddedManifests(files embed.FS, rootPath string, ctx context.Context, parameterGetter ParameterGetter, templateFuncs template.FuncMap) (map[string][]byte, error) {
-	manifests := make(map[string][]byte)
+	return LoadEmbeddedManifestsWithOptions(files, rootPath, ctx, parameterGetter, RenderOptions{CustomFuncs: templateFuncs})
+}
 
+// LoadEmbeddedManifestsWithOptions loads embedded manifests like LoadEmbeddedManifests and
+// renders them with opts, e.g. to give secretValue and configValue a Kubernetes client.
+// Files and Values of opts are set from the embedded filesystem.
+func LoadEmbeddedManifestsWithOptions(files embed.FS, rootPath string, ctx context.Context, parameterGetter ParameterGetter, opts RenderOptions) (map[string][]byte, error) {
 	// Default rootPath to "manifests" if empty for backward compatibility
 	if rootPath == "" {
 		rootPath = "manifests"
 	}
+	return loadManifestDir(files, rootPath, ctx, parameterGetter, opts)
+}
+
+// loadManifestDir renders the manifests under rootPath in files, as LoadEmbeddedManifests does
+// for an embedded filesystem
+func loadManifestDir(files fs.FS, rootPath string, ctx context.Context, parameterGetter ParameterGetter, opts RenderOptions) (map[string][]byte, error) {
+	manifests := make(map[string][]byte)
 
 	// Check if rootPath exists in the filesystem
 	_, err := fs.Stat(files, rootPath)
@@ -34,25 +46,18 @@ func LoadEmbeddedManifests(files embed.FS, rootPath string, ctx context.Context,
 		return manifests, nil
 	}
 
-	// Get full spec once at the start (not per-service)
-	var spec map[string]interface{}
-	if parameterGetter != nil {
-		var err error
-		spec, err = parameterGetter(ctx)
-		if err != nil {
-			// If parameter getter fails (e.g., no Kubernetes connection),
-			// fall back to empty spec which will use defaults in templates
-			spec = make(map[string]interface{})
-		}
-	} else {
-		spec = make(map[string]interface{})
+	// Load values.yaml defaults once, failing early on an invalid file
+	values, err := loadValues(files, rootPath)
+	if err != nil {
+		return nil, err
 	}
 
+	// Get full spec once at the start (not per-service)
+	spec := loadSpec(ctx, parameterGetter)
+
 	// Create FileSystem instance for .Files.Get() support
-	fileSystem := &FileSystem{
-		fs:       files,
-		rootPath: rootPath,
-	}
+	opts.Files = &FileSystem{fs: files, rootPath: rootPath}
+	opts.Values = values
 
 	err = fs.WalkDir(files, rootPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -60,6 +65,10 @@ func LoadEmbeddedManifests(files embed.FS, rootPath string, ctx context.Context,
 		}
 
 		if d.IsDir() {
+			// A cloned repository keeps its metadata next to the manifests
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
 			return nil
 		}
 
@@ -73,7 +82,12 @@ func LoadEmbeddedManifests(files embed.FS, rootPath string, ctx context.Context,
 			return nil
 		}
 
-		data, err := files.ReadFile(path)
+		// Skip the root values.yaml - it holds template defaults, not a manifest
+		if path == filepath.Join(rootPath, ValuesFileName) {
+			return nil
+		}
+
+		data, err := fs.ReadFile(files, path)
 		if err != nil {
 			return fmt.Errorf("failed to read %s: %w", path, err)
 		}
@@ -82,7 +96,7 @@ func LoadEmbeddedManifests(files embed.FS, rootPath string, ctx context.Context,
 		serviceName := extractServiceName(path, rootPath)
 
 		// Render template with full spec and filesystem
-		rendered, err := RenderTemplate(ctx, data, serviceName, spec, fileSystem, templateFuncs)
+		rendered, err := RenderTemplateWithOptions(ctx, data, serviceName, spec, opts)
 		if err != nil {
 			return fmt.Errorf("failed to render template for %s: %w", path, err)
 		}
@@ -103,12 +117,70 @@ func LoadEmbeddedManifests(files embed.FS, rootPath string, ctx context.Context,
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to walk embedded manifests: %w", err)
+		return nil, fmt.Errorf("failed to walk manifests under %s: %w", rootPath, err)
 	}
 
 	return manifests, nil
 }
 
+// LoadKustomizeManifests renders the kustomization directory root with RenderKustomize and
+// passes every resulting manifest through the same template rendering as LoadEmbeddedManifests.
+// Template actions must sit inside YAML strings so the kustomization can be parsed first.
+func LoadKustomizeManifests(files embed.FS, root string, ctx context.Context, parameterGetter ParameterGetter, templateFuncs template.FuncMap) (map[string][]byte, error) {
+	return LoadKustomizeManifestsWithOptions(files, root, ctx, parameterGetter, RenderOptions{CustomFuncs: templateFuncs})
+}
+
+// LoadKustomizeManifestsWithOptions loads a kustomization like LoadKustomizeManifests and
+// renders its manifests with opts. Files and Values of opts are set from the kustomization.
+func LoadKustomizeManifestsWithOptions(files embed.FS, root string, ctx context.Context, parameterGetter ParameterGetter, opts RenderOptions) (map[string][]byte, error) {
+	rendered, err := RenderKustomize(ctx, root, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kustomization %s: %w", root, err)
+	}
+
+	values, err := LoadValues(files, root)
+	if err != nil {
+		return nil, err
+	}
+	spec := loadSpec(ctx, parameterGetter)
+	opts.Files = NewFileSystem(files, root)
+	opts.Values = values
+
+	manifests := make(map[string][]byte, len(rendered))
+	for renderedKey, data := range rendered {
+		serviceName := renderedKey[strings.LastIndex(renderedKey, "/")+1:]
+		data, err := RenderTemplateWithOptions(ctx, data, serviceName, spec, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render template for %s: %w", renderedKey, err)
+		}
+
+		if strings.TrimSpace(string(data)) == "" {
+			continue
+		}
+
+		key, err := extractKeyFromYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract key from %s: %w", renderedKey, err)
+		}
+		manifests[key] = data
+	}
+
+	return manifests, nil
+}
+
+// loadSpec returns the CRD spec from parameterGetter, or an empty spec when there is no
+// getter or it fails (e.g., no Kubernetes connection) so templates fall back to their defaults
+func loadSpec(ctx context.Context, parameterGetter ParameterGetter) map[string]interface{} {
+	if parameterGetter == nil {
+		return make(map[string]interface{})
+	}
+	spec, err := parameterGetter(ctx)
+	if err != nil || spec == nil {
+		return make(map[string]interface{})
+	}
+	return spec
+}
+
 // extractServiceName extracts the service name from a manifest file path
 // e.g., "manifests/redis/deployment.yaml" with rootPath "manifests" -> "redis"
 func extractServiceName(path string, rootPath string) string {
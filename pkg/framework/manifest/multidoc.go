@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DocumentSeparator separates the documents of a multi-document YAML stream
+const DocumentSeparator = "---"
+
+// SplitMultiDoc splits a YAML stream on "---" separator lines into its documents.
+// Documents that hold only whitespace or comments are dropped; every other document
+// must parse as YAML. A stream without separators yields a single document.
+func SplitMultiDoc(yamlBytes []byte) ([][]byte, error) {
+	var docs [][]byte
+	var current bytes.Buffer
+
+	flush := func() error {
+		doc := current.Bytes()
+		current.Reset()
+
+		var content interface{}
+		if err := yaml.Unmarshal(doc, &content); err != nil {
+			return fmt.Errorf("failed to parse YAML document %d: %w", len(docs)+1, err)
+		}
+		if content == nil {
+			return nil
+		}
+		docs = append(docs, append([]byte(nil), doc...))
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(yamlBytes))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(yamlBytes)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if isDocumentSeparator(line) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read YAML: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// HasDocumentSeparator reports whether yamlBytes contains a "---" separator line, that is,
+// whether it is meant as a YAML stream rather than a single document
+func HasDocumentSeparator(yamlBytes []byte) bool {
+	for _, line := range strings.Split(string(yamlBytes), "\n") {
+		if isDocumentSeparator(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinMultiDoc joins documents into a single multi-document YAML stream
+func JoinMultiDoc(docs [][]byte) []byte {
+	var buf bytes.Buffer
+	for i, doc := range docs {
+		if i > 0 {
+			buf.WriteString(DocumentSeparator + "\n")
+		}
+		buf.Write(doc)
+		if len(doc) > 0 && doc[len(doc)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+// DocumentKey returns the namespace/Kind/name key of a single YAML document
+func DocumentKey(doc []byte) (string, error) {
+	return extractKeyFromYAML(doc)
+}
+
+func isDocumentSeparator(line string) bool {
+	line = strings.TrimRight(line, " \t\r")
+	return line == DocumentSeparator || strings.HasPrefix(line, DocumentSeparator+" ")
+}
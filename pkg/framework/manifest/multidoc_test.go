@@ -0,0 +1,59 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitMultiDoc(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		wantDocs int
+		wantErr  bool
+	}{
+		{name: "single document", yaml: "kind: ConfigMap\n", wantDocs: 1},
+		{name: "leading separator", yaml: "---\nkind: ConfigMap\n", wantDocs: 1},
+		{name: "three documents", yaml: "kind: A\n---\nkind: B\n--- # comment\nkind: C\n", wantDocs: 3},
+		{name: "empty and comment documents dropped", yaml: "kind: A\n---\n\n---\n# only a comment\n---\nkind: B\n", wantDocs: 2},
+		{name: "separator inside block scalar", yaml: "data: |\n  ---x\nkind: A\n", wantDocs: 1},
+		{name: "invalid document", yaml: "kind: A\n---\nkind: [B\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := SplitMultiDoc([]byte(tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitMultiDoc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(docs) != tt.wantDocs {
+				t.Errorf("SplitMultiDoc() returned %d documents, want %d: %q", len(docs), tt.wantDocs, docs)
+			}
+		})
+	}
+}
+
+func TestHasDocumentSeparator(t *testing.T) {
+	if HasDocumentSeparator([]byte("kind: A\ndata: |\n  ---x\n")) {
+		t.Error("HasDocumentSeparator() = true for a single document")
+	}
+	if !HasDocumentSeparator([]byte("kind: A\n--- # next\nkind: B\n")) {
+		t.Error("HasDocumentSeparator() = false for a stream")
+	}
+}
+
+func TestJoinMultiDoc_RoundTrip(t *testing.T) {
+	input := "kind: A\n---\nkind: B\n---\nkind: C"
+	docs, err := SplitMultiDoc([]byte(input))
+	if err != nil {
+		t.Fatalf("SplitMultiDoc() error = %v", err)
+	}
+
+	joined := string(JoinMultiDoc(docs))
+	if joined != input+"\n" {
+		t.Errorf("JoinMultiDoc() = %q, want %q", joined, input+"\n")
+	}
+	if strings.Count(joined, "---") != 2 {
+		t.Errorf("JoinMultiDoc() = %q, want 2 separators", joined)
+	}
+}
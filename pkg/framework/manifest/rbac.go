@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// RBACRulesAnnotation on a Deployment or StatefulSet holds the rules of the Role GenerateRBAC
+// creates for its service account, as a JSON or YAML list of
+// {apiGroups, resources, resourceNames, verbs} objects
+const RBACRulesAnnotation = "rbac.conductor.io/rules"
+
+// PolicyRule is a rule of a generated Role. APIGroups defaults to the core group.
+type PolicyRule struct {
+	APIGroups     []string `yaml:"apiGroups"`
+	Resources     []string `yaml:"resources"`
+	ResourceNames []string `yaml:"resourceNames,omitempty"`
+	Verbs         []string `yaml:"verbs"`
+}
+
+// rbacWorkload is the subset of a Deployment or StatefulSet manifest GenerateRBAC reads
+type rbacWorkload struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name        string            `yaml:"name"`
+		Namespace   string            `yaml:"namespace"`
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				ServiceAccountName string `yaml:"serviceAccountName"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// GenerateRBAC returns a ServiceAccount, Role and RoleBinding for the serviceAccountName of a
+// Deployment or StatefulSet, keyed by namespace/kind/name. All three are named after the
+// service account; the Role has the rules of the RBACRulesAnnotation, or none without it.
+func GenerateRBAC(workloadYAML []byte) (map[string][]byte, error) {
+	var workload rbacWorkload
+	if err := yaml.Unmarshal(workloadYAML, &workload); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse manifest: %w", apperrors.ErrInvalidYAML, err)
+	}
+	if workload.Kind != "Deployment" && workload.Kind != "StatefulSet" {
+		return nil, fmt.Errorf("%w: %s is not a Deployment or StatefulSet", apperrors.ErrInvalid, workload.Kind)
+	}
+	account := workload.Spec.Template.Spec.ServiceAccountName
+	if account == "" {
+		return nil, fmt.Errorf("%w: %s %s sets no spec.template.spec.serviceAccountName", apperrors.ErrInvalid, workload.Kind, workload.Metadata.Name)
+	}
+	namespace := workload.Metadata.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	rules, err := parseRBACRules(workload.Metadata.Annotations[RBACRulesAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{"name": account, "namespace": namespace}
+	objects := map[string]map[string]interface{}{
+		"ServiceAccount": {
+			"apiVersion": "v1",
+			"kind":       "ServiceAccount",
+			"metadata":   metadata,
+		},
+		"Role": {
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "Role",
+			"metadata":   metadata,
+			"rules":      rules,
+		},
+		"RoleBinding": {
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "RoleBinding",
+			"metadata":   metadata,
+			"roleRef": map[string]interface{}{
+				"apiGroup": "rbac.authorization.k8s.io",
+				"kind":     "Role",
+				"name":     account,
+			},
+			"subjects": []interface{}{
+				map[string]interface{}{"kind": "ServiceAccount", "name": account, "namespace": namespace},
+			},
+		},
+	}
+
+	generated := make(map[string][]byte, len(objects))
+	for kind, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", kind, err)
+		}
+		generated[fmt.Sprintf("%s/%s/%s", namespace, kind, account)] = data
+	}
+	return generated, nil
+}
+
+// parseRBACRules parses the RBACRulesAnnotation, requiring resources and verbs in every rule
+func parseRBACRules(value string) ([]PolicyRule, error) {
+	rules := []PolicyRule{}
+	if value == "" {
+		return rules, nil
+	}
+	if err := yaml.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s annotation: %w", apperrors.ErrInvalid, RBACRulesAnnotation, err)
+	}
+	for i := range rules {
+		if len(rules[i].Resources) == 0 || len(rules[i].Verbs) == 0 {
+			return nil, fmt.Errorf("%w: rule %d of the %s annotation needs resources and verbs", apperrors.ErrInvalid, i, RBACRulesAnnotation)
+		}
+		if len(rules[i].APIGroups) == 0 {
+			rules[i].APIGroups = []string{""}
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,114 @@
+package manifest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+const rbacDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: apps
+  annotations:
+    rbac.conductor.io/rules: '[{"resources": ["configmaps"], "verbs": ["get", "list", "watch"]}, {"apiGroups": ["apps"], "resources": ["deployments"], "resourceNames": ["api"], "verbs": ["get"]}]'
+spec:
+  template:
+    spec:
+      serviceAccountName: api-reader
+      containers:
+        - name: api
+          image: example/api:v1
+`
+
+func TestGenerateRBAC(t *testing.T) {
+	generated, err := GenerateRBAC([]byte(rbacDeployment))
+	if err != nil {
+		t.Fatalf("GenerateRBAC() error = %v", err)
+	}
+
+	keys := []string{"apps/Role/api-reader", "apps/RoleBinding/api-reader", "apps/ServiceAccount/api-reader"}
+	for _, key := range keys {
+		if _, ok := generated[key]; !ok {
+			t.Fatalf("GenerateRBAC() keys = %v, want %v", generated, keys)
+		}
+		if errs, err := ValidateManifest(generated[key], key); err != nil || len(errs) > 0 {
+			t.Errorf("GenerateRBAC() %s does not match its key: %v %v\n%s", key, errs, err, generated[key])
+		}
+	}
+	if len(generated) != len(keys) {
+		t.Errorf("GenerateRBAC() generated %d manifests, want %d", len(generated), len(keys))
+	}
+
+	var role struct {
+		APIVersion string       `yaml:"apiVersion"`
+		Rules      []PolicyRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(generated["apps/Role/api-reader"], &role); err != nil {
+		t.Fatalf("generated Role is not valid YAML: %v", err)
+	}
+	wantRules := []PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"api"}, Verbs: []string{"get"}},
+	}
+	if role.APIVersion != "rbac.authorization.k8s.io/v1" || !reflect.DeepEqual(role.Rules, wantRules) {
+		t.Errorf("generated Role = %+v, want rules %+v", role, wantRules)
+	}
+
+	var binding struct {
+		RoleRef  map[string]string   `yaml:"roleRef"`
+		Subjects []map[string]string `yaml:"subjects"`
+	}
+	if err := yaml.Unmarshal(generated["apps/RoleBinding/api-reader"], &binding); err != nil {
+		t.Fatalf("generated RoleBinding is not valid YAML: %v", err)
+	}
+	if binding.RoleRef["kind"] != "Role" || binding.RoleRef["name"] != "api-reader" {
+		t.Errorf("generated RoleBinding roleRef = %v, want Role api-reader", binding.RoleRef)
+	}
+	wantSubject := map[string]string{"kind": "ServiceAccount", "name": "api-reader", "namespace": "apps"}
+	if len(binding.Subjects) != 1 || !reflect.DeepEqual(binding.Subjects[0], wantSubject) {
+		t.Errorf("generated RoleBinding subjects = %v, want %v", binding.Subjects, wantSubject)
+	}
+}
+
+func TestGenerateRBAC_NoRulesAnnotation(t *testing.T) {
+	statefulSet := "apiVersion: apps/v1\nkind: StatefulSet\nmetadata:\n  name: db\nspec:\n  template:\n    spec:\n      serviceAccountName: db\n"
+
+	generated, err := GenerateRBAC([]byte(statefulSet))
+	if err != nil {
+		t.Fatalf("GenerateRBAC() error = %v", err)
+	}
+	var role struct {
+		Rules []PolicyRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(generated["default/Role/db"], &role); err != nil {
+		t.Fatalf("generated Role is not valid YAML: %v\n%s", err, generated["default/Role/db"])
+	}
+	if role.Rules == nil || len(role.Rules) != 0 {
+		t.Errorf("generated Role rules = %v, want an empty list", role.Rules)
+	}
+}
+
+func TestGenerateRBAC_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "not a workload", manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n"},
+		{name: "no service account", manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  template:\n    spec: {}\n"},
+		{name: "rule without verbs", manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n  annotations:\n    rbac.conductor.io/rules: '[{\"resources\": [\"pods\"]}]'\nspec:\n  template:\n    spec:\n      serviceAccountName: api\n"},
+		{name: "unparseable rules", manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n  annotations:\n    rbac.conductor.io/rules: 'not a list'\nspec:\n  template:\n    spec:\n      serviceAccountName: api\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateRBAC([]byte(tt.manifest)); !errors.Is(err, apperrors.ErrInvalid) {
+				t.Errorf("GenerateRBAC() error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
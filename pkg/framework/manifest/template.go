@@ -5,26 +5,107 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	iofs "io/fs"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/go-logr/logr"
 	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/kubernetes"
 )
 
 // TemplateContext represents the context passed to Go templates
 type TemplateContext struct {
-	Spec  map[string]interface{} // Full CRD spec: .Spec.Global, .Spec.Services
-	Files *FileSystem             // For .Files.Get() support
+	Spec         map[string]interface{} // Full CRD spec: .Spec.Global, .Spec.Services
+	Values       map[string]interface{} // values.yaml defaults overridden by the spec
+	Files        *FileSystem            // For .Files.Get() support
+	Cluster      ClusterInfo            // For .Cluster.IsVersionGTE / .Cluster.IsVersionLT
+	ServiceNames []string               // Sorted names of all Service manifests in the store
+	// GlobalLabels and GlobalAnnotations hold spec.global.labels and spec.global.annotations,
+	// for injection with {{ toYAML .GlobalLabels | indent 4 }}
+	GlobalLabels      map[string]string
+	GlobalAnnotations map[string]string
+
+	manifests ManifestReader         // For servicePort lookups
+	kube      *kubeLookup            // For secretValue and configValue lookups
+	params    map[string]interface{} // For param and paramOr lookups
+}
+
+// ManifestReader provides read access to stored manifests keyed by namespace/kind/name.
+// store.ManifestStore satisfies this interface.
+type ManifestReader interface {
+	Get(key string) ([]byte, bool)
+	List() map[string][]byte
+	ListByKind(kind string) map[string][]byte
+}
+
+// RenderOptions holds the optional inputs for RenderTemplateWithOptions
+type RenderOptions struct {
+	Files       *FileSystem      // For .Files.Get() support
+	CustomFuncs template.FuncMap // Merged over built-in and Sprig functions
+	Manifests   ManifestReader   // Used by servicePort and .ServiceNames; the rendered manifest endpoint passes the store
+	// Values holds pre-loaded values.yaml defaults. When nil, they are read from Files.
+	Values map[string]interface{}
+	// ClusterVersion is the Kubernetes server version exposed as .Cluster.Version
+	ClusterVersion string
+	// KubeClient backs secretValue and configValue. When nil, both return "".
+	KubeClient kubernetes.Interface
+	// Logger receives warnings about Secrets and ConfigMaps that could not be read
+	Logger logr.Logger
 }
 
 // FileSystem provides access to embedded files for templates
 type FileSystem struct {
-	fs       embed.FS
+	fs       iofs.FS
 	rootPath string
 }
 
+// NewFileSystem creates a FileSystem rooted at rootPath within files
+func NewFileSystem(files embed.FS, rootPath string) *FileSystem {
+	return &FileSystem{
+		fs:       files,
+		rootPath: rootPath,
+	}
+}
+
+// ListAll returns the paths of all files under rootPath, relative to rootPath and sorted.
+// A missing rootPath yields an empty list.
+func (fs *FileSystem) ListAll() ([]string, error) {
+	paths := []string{}
+	if fs == nil {
+		return paths, nil
+	}
+
+	if _, err := iofs.Stat(fs.fs, fs.rootPath); err != nil {
+		return paths, nil
+	}
+
+	err := iofs.WalkDir(fs.fs, fs.rootPath, func(path string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(fs.rootPath, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files under %s: %w", fs.rootPath, err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
 // Get reads a file from the embedded filesystem relative to rootPath
 func (fs *FileSystem) Get(path string) string {
 	if fs == nil {
@@ -34,7 +115,7 @@ func (fs *FileSystem) Get(path string) string {
 	// Join rootPath with the requested path
 	fullPath := filepath.Join(fs.rootPath, path)
 	
-	data, err := fs.fs.ReadFile(fullPath)
+	data, err := iofs.ReadFile(fs.fs, fullPath)
 	if err != nil {
 		return ""
 	}
@@ -47,7 +128,10 @@ func (fs *FileSystem) Get(path string) string {
 // 2. Sprig functions (excluding env/expandenv for security)
 // 3. Custom uuidv5 function
 // 4. getService helper for hyphenated service names
-// 5. User-provided custom functions (highest priority, can override)
+// 5. servicePort helper for resolving named Service ports
+// 6. secretValue and configValue helpers for reading Secrets and ConfigMaps
+// 7. param and paramOr helpers for dot-separated parameter paths
+// 8. User-provided custom functions (highest priority, can override)
 func buildTemplateFuncMap(ctx *TemplateContext, customFuncs template.FuncMap) template.FuncMap {
 	// Start with existing built-in functions
 	funcMap := template.FuncMap{
@@ -67,12 +151,13 @@ func buildTemplateFuncMap(ctx *TemplateContext, customFuncs template.FuncMap) te
 			return prefix != ""
 		},
 		"toYAML": func(v interface{}) string {
-			// Simple YAML conversion for maps
+			// Simple YAML conversion for maps, sorted by key so renders are stable
 			if m, ok := v.(map[string]string); ok {
 				var parts []string
 				for k, val := range m {
 					parts = append(parts, fmt.Sprintf("%s: %s", k, val))
 				}
+				sort.Strings(parts)
 				return strings.Join(parts, "\n")
 			}
 			return ""
@@ -88,6 +173,47 @@ func buildTemplateFuncMap(ctx *TemplateContext, customFuncs template.FuncMap) te
 			}
 			return services[serviceName]
 		},
+		// servicePort returns the port number of a named port on a Service manifest
+		"servicePort": func(name, portName string) int32 {
+			if ctx == nil {
+				return 0
+			}
+			return lookupServicePort(ctx.manifests, name, portName)
+		},
+		// secretValue returns a key of a Secret read from the cluster, or "" if it does not exist
+		"secretValue": func(namespace, name, key string) string {
+			if ctx == nil {
+				return ""
+			}
+			return ctx.kube.secretValue(namespace, name, key)
+		},
+		// configValue returns a key of a ConfigMap read from the cluster, or "" if it does not exist
+		"configValue": func(namespace, name, key string) string {
+			if ctx == nil {
+				return ""
+			}
+			return ctx.kube.configValue(namespace, name, key)
+		},
+		// param returns the parameter at a dot-separated path such as "global.namespace", or "" if it is not set
+		"param": func(path string) interface{} {
+			if ctx == nil {
+				return ""
+			}
+			if value := resolveParamPath(ctx.params, path); value != nil {
+				return value
+			}
+			return ""
+		},
+		// paramOr returns the parameter at a dot-separated path, or defaultValue if it is not set
+		"paramOr": func(path string, defaultValue interface{}) interface{} {
+			if ctx == nil {
+				return defaultValue
+			}
+			if value := resolveParamPath(ctx.params, path); value != nil {
+				return value
+			}
+			return defaultValue
+		},
 	}
 
 	// Add Sprig functions, but exclude env and expandenv for security
@@ -127,10 +253,33 @@ func buildTemplateFuncMap(ctx *TemplateContext, customFuncs template.FuncMap) te
 	return funcMap
 }
 
+// TemplateFuncs returns the built-in, Sprig and uuidv5 functions available to manifest
+// templates, merged with customFuncs, for rendering templates outside of manifests.
+// Helpers that need a manifest context, such as getService, servicePort and secretValue, return zero values.
+func TemplateFuncs(customFuncs template.FuncMap) template.FuncMap {
+	return buildTemplateFuncMap(nil, customFuncs)
+}
+
 // RenderTemplate renders a manifest YAML template with the given spec and filesystem
 // If customFuncs is provided, it will be merged with built-in and Sprig functions
 // Context is used for cancellation and timeout handling during template rendering
 func RenderTemplate(ctx context.Context, manifestBytes []byte, serviceName string, spec map[string]interface{}, files *FileSystem, customFuncs template.FuncMap) ([]byte, error) {
+	return RenderTemplateWithContext(ctx, manifestBytes, serviceName, RenderContext{Spec: spec}, files, customFuncs)
+}
+
+// RenderTemplateWithContext renders a manifest YAML template like RenderTemplate, with the
+// Kubernetes client and logger of renderCtx backing secretValue and configValue
+func RenderTemplateWithContext(ctx context.Context, manifestBytes []byte, serviceName string, renderCtx RenderContext, files *FileSystem, customFuncs template.FuncMap) ([]byte, error) {
+	return RenderTemplateWithOptions(ctx, manifestBytes, serviceName, renderCtx.Spec, RenderOptions{
+		Files:       files,
+		CustomFuncs: customFuncs,
+		KubeClient:  renderCtx.KubeClient,
+		Logger:      renderCtx.Logger,
+	})
+}
+
+// RenderTemplateWithOptions renders a manifest YAML template with the given spec and options
+func RenderTemplateWithOptions(ctx context.Context, manifestBytes []byte, serviceName string, spec map[string]interface{}, opts RenderOptions) ([]byte, error) {
 	// Check for context cancellation before starting
 	select {
 	case <-ctx.Done():
@@ -143,14 +292,36 @@ func RenderTemplate(ctx context.Context, manifestBytes []byte, serviceName strin
 		spec = make(map[string]interface{})
 	}
 
-	// Build template context
+	// Load values.yaml defaults unless the caller already did
+	values := opts.Values
+	if values == nil {
+		var err error
+		values, err = opts.Files.Values()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Build template context; the spec takes precedence over values.yaml
 	templateCtx := &TemplateContext{
-		Spec:  spec,
-		Files: files,
+		Spec:      spec,
+		Values:    mergeValues(values, spec),
+		Files:     opts.Files,
+		Cluster:      ClusterInfo{Version: opts.ClusterVersion},
+		ServiceNames: serviceNames(opts.Manifests),
+		manifests:    opts.Manifests,
+		kube:         newKubeLookup(ctx, opts.KubeClient, opts.Logger),
+		params:       templateParams(spec, serviceName),
+
+		GlobalLabels:      globalStringMap(spec, "labels"),
+		GlobalAnnotations: globalStringMap(spec, "annotations"),
 	}
 
 	// Build complete function map
-	funcMap := buildTemplateFuncMap(templateCtx, customFuncs)
+	funcMap := buildTemplateFuncMap(templateCtx, opts.CustomFuncs)
+
+	// Conditions are evaluated at apply time against the parameters current then
+	manifestBytes, conditions := protectConditions(manifestBytes)
 
 	// Create template with merged functions
 	tmpl, err := template.New("manifest").Funcs(funcMap).Parse(string(manifestBytes))
@@ -170,6 +341,116 @@ func RenderTemplate(ctx context.Context, manifestBytes []byte, serviceName strin
 		return nil, fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	return buf.Bytes(), nil
+	return restoreConditions(buf.Bytes(), conditions), nil
+}
+
+// templateParams returns the spec with the global parameters, overridden by those of
+// serviceName, merged in at the top level, so param accepts both "global.namespace" and "namespace"
+func templateParams(spec map[string]interface{}, serviceName string) map[string]interface{} {
+	global, _ := spec["global"].(map[string]interface{})
+	var service map[string]interface{}
+	if services, ok := spec["services"].(map[string]interface{}); ok {
+		service, _ = services[serviceName].(map[string]interface{})
+	}
+	return mergeValues(mergeValues(global, service), spec)
+}
+
+// globalStringMap returns spec.global.<field> with its values formatted as strings, or an
+// empty map when it is not set
+func globalStringMap(spec map[string]interface{}, field string) map[string]string {
+	result := map[string]string{}
+	global, _ := spec["global"].(map[string]interface{})
+	values, _ := global[field].(map[string]interface{})
+	for key, value := range values {
+		result[key] = fmt.Sprint(value)
+	}
+	return result
+}
+
+// resolveParamPath returns the value at a dot-separated path in spec, or nil if any
+// segment of the path does not exist
+func resolveParamPath(spec map[string]interface{}, path string) interface{} {
+	if path == "" {
+		return nil
+	}
+	var current interface{} = spec
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if current, ok = m[segment]; !ok {
+			return nil
+		}
+	}
+	return current
+}
+
+// serviceNames returns the sorted, unique names of all Service manifests in manifests
+func serviceNames(manifests ManifestReader) []string {
+	names := []string{}
+	if manifests == nil {
+		return names
+	}
+
+	seen := make(map[string]bool)
+	for key := range manifests.ListByKind("Service") {
+		parts := strings.Split(key, "/")
+		name := parts[len(parts)-1]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
+// lookupServicePort finds the Service named name (or namespace/name) and returns the
+// port number of its spec.ports entry called portName. Returns 0 if not found.
+func lookupServicePort(manifests ManifestReader, name, portName string) int32 {
+	if manifests == nil || name == "" || portName == "" {
+		return 0
+	}
+
+	var data []byte
+	if strings.Contains(name, "/") {
+		parts := strings.SplitN(name, "/", 2)
+		data, _ = manifests.Get(fmt.Sprintf("%s/Service/%s", parts[0], parts[1]))
+	} else {
+		var keys []string
+		all := manifests.List()
+		for key := range all {
+			parts := strings.Split(key, "/")
+			if len(parts) == 3 && parts[1] == "Service" && parts[2] == name {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			sort.Strings(keys)
+			data = all[keys[0]]
+		}
+	}
+	if data == nil {
+		return 0
+	}
+
+	var svc struct {
+		Spec struct {
+			Ports []struct {
+				Name string `yaml:"name"`
+				Port int32  `yaml:"port"`
+			} `yaml:"ports"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(data, &svc); err != nil {
+		return 0
+	}
+
+	for _, port := range svc.Spec.Ports {
+		if port.Name == portName {
+			return port.Port
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const globalLabelsDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  labels:
+    app: api
+    {{- with .GlobalLabels }}
+{{ toYAML . | indent 4 }}
+    {{- end }}
+  annotations:
+{{ toYAML .GlobalAnnotations | indent 4 }}
+spec:
+  template:
+    metadata:
+      labels:
+        app: api
+        {{- range $key, $value := .GlobalLabels }}
+        {{ $key }}: {{ $value | quote }}
+        {{- end }}
+`
+
+func TestRenderTemplate_GlobalLabels(t *testing.T) {
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{
+			"labels":      map[string]interface{}{"env": "prod", "team": "platform"},
+			"annotations": map[string]interface{}{"owner": "ops@example.com"},
+		},
+	}
+
+	result, err := RenderTemplateWithOptions(context.Background(), []byte(globalLabelsDeployment), "api", spec, RenderOptions{})
+	if err != nil {
+		t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+	}
+
+	var deployment struct {
+		Metadata struct {
+			Labels      map[string]string `yaml:"labels"`
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Labels map[string]string `yaml:"labels"`
+				} `yaml:"metadata"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(result, &deployment); err != nil {
+		t.Fatalf("rendered manifest is not valid YAML: %v\n%s", err, result)
+	}
+
+	want := map[string]string{"app": "api", "env": "prod", "team": "platform"}
+	for key, value := range want {
+		if deployment.Metadata.Labels[key] != value {
+			t.Errorf("metadata.labels[%s] = %q, want %q\n%s", key, deployment.Metadata.Labels[key], value, result)
+		}
+		if deployment.Spec.Template.Metadata.Labels[key] != value {
+			t.Errorf("spec.template.metadata.labels[%s] = %q, want %q\n%s", key, deployment.Spec.Template.Metadata.Labels[key], value, result)
+		}
+	}
+	if deployment.Metadata.Annotations["owner"] != "ops@example.com" {
+		t.Errorf("metadata.annotations = %v, want owner ops@example.com\n%s", deployment.Metadata.Annotations, result)
+	}
+}
+
+func TestRenderTemplate_GlobalLabelsUnset(t *testing.T) {
+	template := `labels: {{ len .GlobalLabels }}/{{ len .GlobalAnnotations }}{{ toYAML .GlobalLabels }}`
+
+	result, err := RenderTemplateWithOptions(context.Background(), []byte(template), "api", map[string]interface{}{}, RenderOptions{})
+	if err != nil {
+		t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+	}
+	if strings.TrimSpace(string(result)) != "labels: 0/0" {
+		t.Errorf("RenderTemplateWithOptions() = %q, want %q", result, "labels: 0/0")
+	}
+}
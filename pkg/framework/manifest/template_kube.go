@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RenderContext holds the inputs RenderTemplateWithContext exposes to a template
+type RenderContext struct {
+	Spec map[string]interface{} // Full CRD spec: .Spec.Global, .Spec.Services
+	// KubeClient backs secretValue and configValue. When nil, both return "".
+	KubeClient kubernetes.Interface
+	Logger     logr.Logger
+}
+
+// kubeLookup reads Secret and ConfigMap data for secretValue and configValue.
+// Objects are fetched at most once per render; missing objects are cached as nil.
+type kubeLookup struct {
+	ctx    context.Context
+	client kubernetes.Interface
+	logger logr.Logger
+	cache  map[string]map[string]string
+}
+
+func newKubeLookup(ctx context.Context, client kubernetes.Interface, logger logr.Logger) *kubeLookup {
+	return &kubeLookup{
+		ctx:    ctx,
+		client: client,
+		logger: logger,
+		cache:  make(map[string]map[string]string),
+	}
+}
+
+// secretValue returns the decoded value of key in the Secret namespace/name
+func (l *kubeLookup) secretValue(namespace, name, key string) string {
+	data := l.lookup("Secret", namespace, name, func() (map[string]string, error) {
+		secret, err := l.client.CoreV1().Secrets(namespace).Get(l.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]string, len(secret.Data)+len(secret.StringData))
+		for k, v := range secret.Data {
+			values[k] = string(v)
+		}
+		for k, v := range secret.StringData {
+			values[k] = v
+		}
+		return values, nil
+	})
+	return l.value(data, "Secret", namespace, name, key)
+}
+
+// configValue returns the value of key in the ConfigMap namespace/name
+func (l *kubeLookup) configValue(namespace, name, key string) string {
+	data := l.lookup("ConfigMap", namespace, name, func() (map[string]string, error) {
+		configMap, err := l.client.CoreV1().ConfigMaps(namespace).Get(l.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]string, len(configMap.Data)+len(configMap.BinaryData))
+		for k, v := range configMap.BinaryData {
+			values[k] = string(v)
+		}
+		for k, v := range configMap.Data {
+			values[k] = v
+		}
+		return values, nil
+	})
+	return l.value(data, "ConfigMap", namespace, name, key)
+}
+
+func (l *kubeLookup) lookup(kind, namespace, name string, fetch func() (map[string]string, error)) map[string]string {
+	if l == nil || l.client == nil {
+		return nil
+	}
+
+	cacheKey := fmt.Sprintf("%s/%s/%s", namespace, kind, name)
+	if data, ok := l.cache[cacheKey]; ok {
+		return data
+	}
+
+	data, err := fetch()
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			l.logger.Info("Template lookup target not found", "kind", kind, "namespace", namespace, "name", name)
+		} else {
+			l.logger.Error(err, "Template lookup failed", "kind", kind, "namespace", namespace, "name", name)
+		}
+		data = nil
+	}
+	l.cache[cacheKey] = data
+	return data
+}
+
+func (l *kubeLookup) value(data map[string]string, kind, namespace, name, key string) string {
+	if data == nil {
+		return ""
+	}
+	value, ok := data[key]
+	if !ok {
+		l.logger.Info("Template lookup key not found", "kind", kind, "namespace", namespace, "name", name, "key", key)
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTemplateKubeClient() *kubefake.Clientset {
+	return kubefake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte("s3cr3t")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-settings", Namespace: "default"},
+			Data:       map[string]string{"logLevel": "debug"},
+		},
+	)
+}
+
+func TestRenderTemplate_SecretAndConfigValue(t *testing.T) {
+	client := newTemplateKubeClient()
+	manifestBytes := []byte(`password: {{ secretValue "default" "db-credentials" "password" }}
+logLevel: {{ configValue "default" "app-settings" "logLevel" }}`)
+
+	result, err := RenderTemplateWithContext(context.Background(), manifestBytes, "test", RenderContext{KubeClient: client}, nil, nil)
+	if err != nil {
+		t.Fatalf("RenderTemplateWithContext() error = %v", err)
+	}
+
+	resultStr := string(result)
+	if !strings.Contains(resultStr, "password: s3cr3t") {
+		t.Errorf("RenderTemplateWithContext() = %q, want it to contain the secret value", resultStr)
+	}
+	if !strings.Contains(resultStr, "logLevel: debug") {
+		t.Errorf("RenderTemplateWithContext() = %q, want it to contain the config value", resultStr)
+	}
+}
+
+func TestRenderTemplate_SecretValueNotFound(t *testing.T) {
+	client := newTemplateKubeClient()
+	manifestBytes := []byte(`a: "{{ secretValue "default" "missing" "password" }}"
+b: "{{ secretValue "default" "db-credentials" "missing" }}"
+c: "{{ configValue "other" "app-settings" "logLevel" }}"`)
+
+	result, err := RenderTemplateWithContext(context.Background(), manifestBytes, "test", RenderContext{KubeClient: client}, nil, nil)
+	if err != nil {
+		t.Fatalf("RenderTemplateWithContext() error = %v", err)
+	}
+
+	expected := "a: \"\"\nb: \"\"\nc: \"\""
+	if string(result) != expected {
+		t.Errorf("RenderTemplateWithContext() = %q, want %q", string(result), expected)
+	}
+}
+
+func TestRenderTemplate_SecretValueCachedPerRender(t *testing.T) {
+	client := newTemplateKubeClient()
+	gets := 0
+	client.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+
+	manifestBytes := []byte(`{{ secretValue "default" "db-credentials" "password" }}{{ secretValue "default" "db-credentials" "password" }}`)
+	renderCtx := RenderContext{KubeClient: client}
+
+	if _, err := RenderTemplateWithContext(context.Background(), manifestBytes, "test", renderCtx, nil, nil); err != nil {
+		t.Fatalf("RenderTemplateWithContext() error = %v", err)
+	}
+	if gets != 1 {
+		t.Errorf("secret fetched %d times in one render, want 1", gets)
+	}
+
+	if _, err := RenderTemplateWithContext(context.Background(), manifestBytes, "test", renderCtx, nil, nil); err != nil {
+		t.Fatalf("RenderTemplateWithContext() error = %v", err)
+	}
+	if gets != 2 {
+		t.Errorf("secret fetched %d times across two renders, want 2", gets)
+	}
+}
+
+func TestRenderTemplate_SecretValueWithoutClient(t *testing.T) {
+	result, err := RenderTemplate(context.Background(), []byte(`value: "{{ secretValue "default" "db-credentials" "password" }}"`), "test", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("RenderTemplate() error = %v", err)
+	}
+	if string(result) != `value: ""` {
+		t.Errorf("RenderTemplate() = %q, want an empty value", string(result))
+	}
+}
@@ -0,0 +1,93 @@
+package manifest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveParamPath(t *testing.T) {
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace": "prod",
+			"replicas":  3,
+			"database": map[string]interface{}{
+				"host": "db.internal",
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want interface{}
+	}{
+		{name: "top-level key", path: "global", want: spec["global"]},
+		{name: "nested key", path: "global.namespace", want: "prod"},
+		{name: "deeply nested key", path: "global.database.host", want: "db.internal"},
+		{name: "numeric value", path: "global.replicas", want: 3},
+		{name: "missing key", path: "global.missing", want: nil},
+		{name: "path through a scalar", path: "global.namespace.name", want: nil},
+		{name: "empty path", path: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveParamPath(spec, tt.path)
+			if m, ok := tt.want.(map[string]interface{}); ok {
+				if gotMap, ok := got.(map[string]interface{}); !ok || len(gotMap) != len(m) {
+					t.Errorf("resolveParamPath(%q) = %v, want %v", tt.path, got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveParamPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate_Param(t *testing.T) {
+	spec := map[string]interface{}{
+		"global": map[string]interface{}{
+			"namespace": "prod",
+			"replicas":  3,
+			"image": map[string]interface{}{
+				"registry": "registry.example.com",
+			},
+		},
+		"services": map[string]interface{}{
+			"api": map[string]interface{}{
+				"replicas": 5,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		service  string
+		want     string
+	}{
+		{name: "top-level key", template: `{{ param "global.namespace" }}`, service: "api", want: "prod"},
+		{name: "nested path", template: `{{ param "global.image.registry" }}`, service: "api", want: "registry.example.com"},
+		{name: "numeric value", template: `{{ param "global.replicas" }}`, service: "web", want: "3"},
+		{name: "service overrides global", template: `{{ param "replicas" }}`, service: "api", want: "5"},
+		{name: "global without service override", template: `{{ param "replicas" }}`, service: "web", want: "3"},
+		{name: "missing path", template: `{{ param "global.missing" }}`, service: "api", want: ""},
+		{name: "missing path with default", template: `{{ paramOr "global.missing" "fallback" }}`, service: "api", want: "fallback"},
+		{name: "present path ignores default", template: `{{ paramOr "global.namespace" "fallback" }}`, service: "api", want: "prod"},
+		{name: "numeric default", template: `{{ paramOr "global.port" 8080 }}`, service: "api", want: "8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplateWithOptions(context.Background(), []byte(tt.template), tt.service, spec, RenderOptions{})
+			if err != nil {
+				t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+			}
+			if string(result) != tt.want {
+				t.Errorf("RenderTemplateWithOptions() = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
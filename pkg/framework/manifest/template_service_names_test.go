@@ -0,0 +1,61 @@
+package manifest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRenderTemplate_ServiceNames(t *testing.T) {
+	tmpl := []byte(`{{- range .ServiceNames }}
+- host: {{ . }}.example.com
+{{- end }}`)
+
+	tests := []struct {
+		name      string
+		manifests ManifestReader
+		want      string
+	}{
+		{
+			name: "sorted services",
+			manifests: mapManifestReader{
+				"default/Service/web":        []byte("kind: Service"),
+				"default/Service/api":        []byte("kind: Service"),
+				"default/Deployment/web":     []byte("kind: Deployment"),
+				"monitoring/Service/grafana": []byte("kind: Service"),
+			},
+			want: "\n- host: api.example.com\n- host: grafana.example.com\n- host: web.example.com",
+		},
+		{
+			name: "same name in two namespaces is listed once",
+			manifests: mapManifestReader{
+				"default/Service/api": []byte("kind: Service"),
+				"staging/Service/api": []byte("kind: Service"),
+			},
+			want: "\n- host: api.example.com",
+		},
+		{
+			name: "no services",
+			manifests: mapManifestReader{
+				"default/Deployment/web": []byte("kind: Deployment"),
+			},
+			want: "",
+		},
+		{
+			name:      "no manifest reader",
+			manifests: nil,
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplateWithOptions(context.Background(), tmpl, "ingress", nil, RenderOptions{Manifests: tt.manifests})
+			if err != nil {
+				t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+			}
+			if string(result) != tt.want {
+				t.Errorf("RenderTemplateWithOptions() = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
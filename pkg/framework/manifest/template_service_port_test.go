@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// mapManifestReader is a ManifestReader backed by a plain map
+type mapManifestReader map[string][]byte
+
+func (m mapManifestReader) Get(key string) ([]byte, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m mapManifestReader) List() map[string][]byte {
+	return m
+}
+
+func (m mapManifestReader) ListByKind(kind string) map[string][]byte {
+	result := make(map[string][]byte)
+	for key, value := range m {
+		parts := strings.Split(key, "/")
+		if len(parts) == 3 && parts[1] == kind {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func TestRenderTemplate_ServicePort(t *testing.T) {
+	manifests := mapManifestReader{
+		"default/Service/my-service": []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: my-service
+spec:
+  ports:
+    - name: http
+      port: 8080
+      targetPort: 80
+    - name: metrics
+      port: 9090
+`),
+		"default/Service/unnamed": []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: unnamed
+spec:
+  ports:
+    - port: 6379
+`),
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "named port",
+			template: `{{ servicePort "my-service" "http" }}`,
+			expected: "8080",
+		},
+		{
+			name:     "second named port",
+			template: `{{ servicePort "my-service" "metrics" }}`,
+			expected: "9090",
+		},
+		{
+			name:     "namespaced lookup",
+			template: `{{ servicePort "default/my-service" "http" }}`,
+			expected: "8080",
+		},
+		{
+			name:     "unnamed port does not match by name",
+			template: `{{ servicePort "unnamed" "redis" }}`,
+			expected: "0",
+		},
+		{
+			name:     "missing service",
+			template: `{{ servicePort "missing" "http" }}`,
+			expected: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplateWithOptions(context.Background(), []byte(tt.template), "test", nil, RenderOptions{Manifests: manifests})
+			if err != nil {
+				t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+			}
+			if got := strings.TrimSpace(string(result)); got != tt.expected {
+				t.Errorf("servicePort = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate_ServicePort_NoManifests(t *testing.T) {
+	result, err := RenderTemplate(context.Background(), []byte(`{{ servicePort "my-service" "http" }}`), "test", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("RenderTemplate() error = %v", err)
+	}
+	if got := strings.TrimSpace(string(result)); got != "0" {
+		t.Errorf("servicePort without manifests = %v, want 0", got)
+	}
+}
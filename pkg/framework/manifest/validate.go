@@ -0,0 +1,114 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// ValidationError describes a problem with one field of a manifest
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateManifest checks that yamlBytes is a Kubernetes object with apiVersion, kind and
+// metadata.name, and, when key is non-empty, that key (namespace/Kind/name) names the same
+// object. Field problems are returned as ValidationErrors; the error is only set when the
+// YAML cannot be parsed at all.
+func ValidateManifest(yamlBytes []byte, key string) ([]ValidationError, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(yamlBytes, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	var errs []ValidationError
+	apiVersion, _ := obj["apiVersion"].(string)
+	if apiVersion == "" {
+		errs = append(errs, ValidationError{Field: "apiVersion", Message: "is required"})
+	}
+	kind, _ := obj["kind"].(string)
+	if kind == "" {
+		errs = append(errs, ValidationError{Field: "kind", Message: "is required"})
+	}
+
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return append(errs, ValidationError{Field: "metadata", Message: "is required"}), nil
+	}
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		errs = append(errs, ValidationError{Field: "metadata.name", Message: "is required"})
+	}
+	namespace, _ := metadata["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	if key == "" {
+		return errs, nil
+	}
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return append(errs, ValidationError{Field: "key", Message: fmt.Sprintf("%q must have the form namespace/Kind/name", key)}), nil
+	}
+	if parts[0] != namespace {
+		errs = append(errs, ValidationError{Field: "metadata.namespace", Message: fmt.Sprintf("%q does not match key namespace %q", namespace, parts[0])})
+	}
+	if kind != "" && parts[1] != kind {
+		errs = append(errs, ValidationError{Field: "kind", Message: fmt.Sprintf("%q does not match key kind %q", kind, parts[1])})
+	}
+	if name != "" && parts[2] != name {
+		errs = append(errs, ValidationError{Field: "metadata.name", Message: fmt.Sprintf("%q does not match key name %q", name, parts[2])})
+	}
+
+	return errs, nil
+}
+
+// ValidateKey checks that key (namespace/Kind/name) matches metadata.namespace, kind and
+// metadata.name of the manifest in yamlBytes. A manifest without metadata.namespace matches
+// a key whose namespace is empty or "default". Mismatches are reported as ErrInvalid.
+func ValidateKey(key string, yamlBytes []byte) error {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: key %q must have the form namespace/Kind/name", apperrors.ErrInvalid, key)
+	}
+
+	var obj struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Name      string `yaml:"name"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal(yamlBytes, &obj); err != nil {
+		return fmt.Errorf("%w: failed to parse YAML: %w", apperrors.ErrInvalidYAML, err)
+	}
+
+	var mismatches []string
+	namespace := obj.Metadata.Namespace
+	if namespace == "" {
+		if parts[0] != "" && parts[0] != "default" {
+			mismatches = append(mismatches, fmt.Sprintf("key namespace %q does not match the default namespace of a manifest without metadata.namespace", parts[0]))
+		}
+	} else if parts[0] != namespace {
+		mismatches = append(mismatches, fmt.Sprintf("key namespace %q does not match metadata.namespace %q", parts[0], namespace))
+	}
+	if parts[1] != obj.Kind {
+		mismatches = append(mismatches, fmt.Sprintf("key kind %q does not match kind %q", parts[1], obj.Kind))
+	}
+	if parts[2] != obj.Metadata.Name {
+		mismatches = append(mismatches, fmt.Sprintf("key name %q does not match metadata.name %q", parts[2], obj.Metadata.Name))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalid, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
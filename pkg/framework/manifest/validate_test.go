@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		key        string
+		wantFields []string
+	}{
+		{
+			name: "valid manifest",
+			yaml: "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: prod\n",
+			key:  "prod/Service/web",
+		},
+		{
+			name: "default namespace",
+			yaml: "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+			key:  "default/Service/web",
+		},
+		{
+			name: "no key skips key checks",
+			yaml: "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+		},
+		{
+			name:       "missing fields",
+			yaml:       "metadata:\n  namespace: default\n",
+			wantFields: []string{"apiVersion", "kind", "metadata.name"},
+		},
+		{
+			name:       "missing metadata",
+			yaml:       "apiVersion: v1\nkind: Service\n",
+			wantFields: []string{"metadata"},
+		},
+		{
+			name:       "key does not match metadata",
+			yaml:       "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+			key:        "prod/Deployment/api",
+			wantFields: []string{"metadata.namespace", "kind", "metadata.name"},
+		},
+		{
+			name:       "malformed key",
+			yaml:       "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+			key:        "web",
+			wantFields: []string{"key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := ValidateManifest([]byte(tt.yaml), tt.key)
+			if err != nil {
+				t.Fatalf("ValidateManifest() error = %v", err)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("ValidateManifest() error fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidateManifest_InvalidYAML(t *testing.T) {
+	if _, err := ValidateManifest([]byte("invalid: yaml: ["), ""); err == nil {
+		t.Error("ValidateManifest() error = nil, want parse error")
+	}
+}
+
+func TestValidateKey(t *testing.T) {
+	const service = "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: prod\n"
+	const defaultService = "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"
+
+	tests := []struct {
+		name    string
+		key     string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "matching key", key: "prod/Service/web", yaml: service},
+		{name: "mismatched name", key: "prod/Service/api", yaml: service, wantErr: true},
+		{name: "mismatched kind", key: "prod/Deployment/web", yaml: service, wantErr: true},
+		{name: "mismatched namespace", key: "default/Service/web", yaml: service, wantErr: true},
+		{name: "empty namespace with default key", key: "default/Service/web", yaml: defaultService},
+		{name: "empty namespace with empty key namespace", key: "/Service/web", yaml: defaultService},
+		{name: "empty namespace with other key namespace", key: "prod/Service/web", yaml: defaultService, wantErr: true},
+		{name: "malformed key", key: "web", yaml: service, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKey(tt.key, []byte(tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, apperrors.ErrInvalid) {
+				t.Errorf("ValidateKey() error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package manifest
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValuesFileName is the name of the defaults file read from the manifest root
+const ValuesFileName = "values.yaml"
+
+// LoadValues reads and parses rootPath/values.yaml from files.
+// A missing file yields an empty map; an unparseable file is an error.
+func LoadValues(files embed.FS, rootPath string) (map[string]interface{}, error) {
+	return loadValues(files, rootPath)
+}
+
+func loadValues(files fs.FS, rootPath string) (map[string]interface{}, error) {
+	return LoadValuesFile(files, filepath.Join(rootPath, ValuesFileName))
+}
+
+// LoadValuesFile reads and parses the values file at path in files.
+// A missing file yields an empty map; an unparseable file is an error.
+func LoadValuesFile(files fs.FS, path string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	data, err := fs.ReadFile(files, path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return values, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, nil
+}
+
+// Values returns the parsed values.yaml at the root of the filesystem
+func (fs *FileSystem) Values() (map[string]interface{}, error) {
+	if fs == nil {
+		return make(map[string]interface{}), nil
+	}
+	return loadValues(fs.fs, fs.rootPath)
+}
+
+// mergeValues returns a new map with override deep-merged over base.
+// Nested maps are merged key by key; any other override value replaces the base value.
+func mergeValues(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		overrideMap, overrideIsMap := v.(map[string]interface{})
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		if overrideIsMap && baseIsMap {
+			merged[k] = mergeValues(baseMap, overrideMap)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
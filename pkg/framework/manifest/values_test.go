@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"context"
+	"embed"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/values
+var valuesTestFS embed.FS
+
+func TestLoadEmbeddedManifests_ValuesDefault(t *testing.T) {
+	manifests, err := LoadEmbeddedManifests(valuesTestFS, "testdata/values/valid", context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("LoadEmbeddedManifests() error = %v", err)
+	}
+	if len(manifests) != 1 {
+		t.Fatalf("LoadEmbeddedManifests() returned %d manifests, want 1 (values.yaml must be skipped)", len(manifests))
+	}
+
+	data := string(manifests["default/Deployment/redis"])
+	if !strings.Contains(data, "replicas: 2") {
+		t.Errorf("expected default replicas from values.yaml, got:\n%s", data)
+	}
+	if !strings.Contains(data, "image: redis:7.0") {
+		t.Errorf("expected default image from values.yaml, got:\n%s", data)
+	}
+}
+
+func TestLoadEmbeddedManifests_SpecOverridesValues(t *testing.T) {
+	getter := func(ctx context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"replicas": 5,
+			"image":    map[string]interface{}{"tag": "7.2"},
+		}, nil
+	}
+
+	manifests, err := LoadEmbeddedManifests(valuesTestFS, "testdata/values/valid", context.Background(), getter, nil)
+	if err != nil {
+		t.Fatalf("LoadEmbeddedManifests() error = %v", err)
+	}
+
+	data := string(manifests["default/Deployment/redis"])
+	if !strings.Contains(data, "replicas: 5") {
+		t.Errorf("expected spec to override replicas, got:\n%s", data)
+	}
+	// Nested keys not set in the spec keep their values.yaml defaults
+	if !strings.Contains(data, "image: redis:7.2") {
+		t.Errorf("expected spec to override only image.tag, got:\n%s", data)
+	}
+}
+
+func TestLoadEmbeddedManifests_MissingValuesFile(t *testing.T) {
+	manifests, err := LoadEmbeddedManifests(valuesTestFS, "testdata/values/missing", context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("LoadEmbeddedManifests() error = %v", err)
+	}
+
+	data := string(manifests["default/ConfigMap/values-check"])
+	if !strings.Contains(data, `count: "0"`) {
+		t.Errorf("expected empty .Values without values.yaml, got:\n%s", data)
+	}
+}
+
+func TestLoadEmbeddedManifests_InvalidValuesFile(t *testing.T) {
+	_, err := LoadEmbeddedManifests(valuesTestFS, "testdata/values/invalid", context.Background(), nil, nil)
+	if err == nil {
+		t.Fatal("LoadEmbeddedManifests() should fail on an invalid values.yaml")
+	}
+	if !strings.Contains(err.Error(), ValuesFileName) {
+		t.Errorf("error should mention %s, got: %v", ValuesFileName, err)
+	}
+}
+
+func TestRenderTemplateWithOptions_Values(t *testing.T) {
+	tmpl := []byte(`level: {{ .Values.log.level }}`)
+	opts := RenderOptions{
+		Values: map[string]interface{}{
+			"log": map[string]interface{}{"level": "info", "format": "json"},
+		},
+	}
+
+	result, err := RenderTemplateWithOptions(context.Background(), tmpl, "svc", nil, opts)
+	if err != nil {
+		t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+	}
+	if string(result) != "level: info" {
+		t.Errorf("RenderTemplateWithOptions() = %q, want %q", result, "level: info")
+	}
+
+	spec := map[string]interface{}{"log": map[string]interface{}{"level": "debug"}}
+	result, err = RenderTemplateWithOptions(context.Background(), tmpl, "svc", spec, opts)
+	if err != nil {
+		t.Fatalf("RenderTemplateWithOptions() error = %v", err)
+	}
+	if string(result) != "level: debug" {
+		t.Errorf("RenderTemplateWithOptions() = %q, want %q", result, "level: debug")
+	}
+}
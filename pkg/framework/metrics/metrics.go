@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// Metrics holds the Prometheus collectors for reconciler operations.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	ApplyTotal        *prometheus.CounterVec
+	DeleteTotal       *prometheus.CounterVec
+	ReconcileDuration prometheus.Histogram
+	ManagedResources  prometheus.Gauge
+}
+
+// New creates the reconciler metrics and registers them with registerer.
+// Pass a fresh prometheus.NewRegistry() to keep them out of the global registry.
+func New(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		ApplyTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "conductor_apply_total",
+			Help: "Number of resource applies by result.",
+		}, []string{"result"}),
+		DeleteTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "conductor_delete_total",
+			Help: "Number of resource deletes by result.",
+		}, []string{"result"}),
+		ReconcileDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "conductor_reconcile_duration_seconds",
+			Help:    "Duration of reconciliation cycles in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		ManagedResources: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "conductor_managed_resources",
+			Help: "Number of resources managed after the last full reconciliation.",
+		}),
+	}
+	registerer.MustRegister(m.ApplyTotal, m.DeleteTotal, m.ReconcileDuration, m.ManagedResources)
+	return m
+}
+
+// ObserveApply counts an apply as a success when err is nil and a failure otherwise
+func (m *Metrics) ObserveApply(err error) {
+	if m == nil {
+		return
+	}
+	m.ApplyTotal.WithLabelValues(result(err)).Inc()
+}
+
+// ObserveDelete counts a delete as a success when err is nil and a failure otherwise
+func (m *Metrics) ObserveDelete(err error) {
+	if m == nil {
+		return
+	}
+	m.DeleteTotal.WithLabelValues(result(err)).Inc()
+}
+
+// ObserveReconcile records the duration of a reconciliation cycle
+func (m *Metrics) ObserveReconcile(duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.ReconcileDuration.Observe(duration.Seconds())
+}
+
+// SetManagedResources sets the number of managed resources
+func (m *Metrics) SetManagedResources(count int) {
+	if m == nil {
+		return
+	}
+	m.ManagedResources.Set(float64(count))
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
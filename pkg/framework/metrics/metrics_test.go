@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := New(registry)
+
+	m.ObserveApply(nil)
+	m.ObserveApply(nil)
+	m.ObserveApply(errors.New("apply failed"))
+	m.ObserveDelete(nil)
+	m.ObserveReconcile(250 * time.Millisecond)
+	m.SetManagedResources(4)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				got[name] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				got[name] = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				got[name] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"conductor_apply_total/success":        2,
+		"conductor_apply_total/failure":        1,
+		"conductor_delete_total/success":       1,
+		"conductor_reconcile_duration_seconds": 1,
+		"conductor_managed_resources":          4,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+}
+
+func TestMetrics_NilIsNoop(t *testing.T) {
+	var m *Metrics
+	m.ObserveApply(nil)
+	m.ObserveDelete(errors.New("delete failed"))
+	m.ObserveReconcile(time.Second)
+	m.SetManagedResources(1)
+}
+
+func TestStoreMetrics_NilIsNoop(t *testing.T) {
+	var m *StoreMetrics
+	m.ObserveOperation(OperationGet, time.Now(), nil)
+	m.SetKeys(1)
+	m.SetDBSize(1024)
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Manifest store operations recorded by StoreMetrics
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationGet    = "get"
+	OperationList   = "list"
+	OperationRename = "rename"
+)
+
+// StoreMetrics holds the Prometheus collectors for manifest store operations.
+// A nil *StoreMetrics is valid and records nothing.
+type StoreMetrics struct {
+	OperationsTotal   *prometheus.CounterVec
+	OperationDuration *prometheus.HistogramVec
+	KeysTotal         prometheus.Gauge
+	DBSizeBytes       prometheus.Gauge
+}
+
+// NewStoreMetrics creates the manifest store metrics and registers them with registerer
+func NewStoreMetrics(registerer prometheus.Registerer) *StoreMetrics {
+	m := &StoreMetrics{
+		OperationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "conductor_store_operations_total",
+			Help: "Number of manifest store operations by operation and result.",
+		}, []string{"operation", "result"}),
+		OperationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "conductor_store_operation_duration_seconds",
+			Help:    "Duration of manifest store operations in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		KeysTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "conductor_store_keys_total",
+			Help: "Number of manifests in the index after the last write.",
+		}),
+		DBSizeBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "conductor_db_size_bytes",
+			Help: "On-disk size of the database, LSM tree and value log, in bytes.",
+		}),
+	}
+	registerer.MustRegister(m.OperationsTotal, m.OperationDuration, m.KeysTotal, m.DBSizeBytes)
+	return m
+}
+
+// ObserveOperation counts a store operation that started at start as a success when err is
+// nil and a failure otherwise, and records its duration
+func (m *StoreMetrics) ObserveOperation(operation string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.OperationsTotal.WithLabelValues(operation, result(err)).Inc()
+	m.OperationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// SetKeys sets the number of manifests in the index
+func (m *StoreMetrics) SetKeys(count int) {
+	if m == nil {
+		return
+	}
+	m.KeysTotal.Set(float64(count))
+}
+
+// SetDBSize sets the on-disk size of the database
+func (m *StoreMetrics) SetDBSize(bytes int64) {
+	if m == nil {
+		return
+	}
+	m.DBSizeBytes.Set(float64(bytes))
+}
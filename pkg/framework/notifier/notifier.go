@@ -0,0 +1,213 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// DefaultTimeout bounds a single notification request made by an HTTPNotifier
+const DefaultTimeout = 10 * time.Second
+
+// MaxAttempts is how many times an HTTPNotifier tries to deliver one notification
+const MaxAttempts = 3
+
+// DefaultInitialBackoff is the delay before the first retry; each further retry doubles it
+const DefaultInitialBackoff = 500 * time.Millisecond
+
+// Deployment lifecycle event types reported in DeploymentEvent.Type
+const (
+	EventDeploySuccess = "deploy.success"
+	EventDeployFailed  = "deploy.failed"
+	EventDeleteSuccess = "delete.success"
+	EventDeleteFailed  = "delete.failed"
+	EventUpdateSuccess = "update.success"
+	EventUpdateFailed  = "update.failed"
+)
+
+// Config describes an external endpoint notified about deployment lifecycle events
+type Config struct {
+	URL     string
+	Method  string // Defaults to POST
+	Headers map[string]string
+	// BodyTemplate is rendered with the manifest template functions and a DeploymentEvent as data.
+	// When empty the event is sent as JSON.
+	BodyTemplate string
+	// Events lists the event types sent to this endpoint, e.g. "deploy.success"; empty means all
+	Events []string
+}
+
+// Accepts reports whether the endpoint subscribes to eventType
+func (c Config) Accepts(eventType string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, event := range c.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DeploymentEvent describes how an Up, Down or Update call finished
+type DeploymentEvent struct {
+	Type      string    `json:"type"`     // One of the Event* constants
+	Action    string    `json:"action"`   // "up", "down" or "update"
+	Services  []string  `json:"services"` // Selected services; empty means all
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewDeploymentEvent builds the event for action finishing with err
+func NewDeploymentEvent(action string, services []string, err error) DeploymentEvent {
+	prefix := action
+	switch action {
+	case "up":
+		prefix = "deploy"
+	case "down":
+		prefix = "delete"
+	}
+
+	event := DeploymentEvent{
+		Type:      prefix + ".success",
+		Action:    action,
+		Services:  services,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		event.Type = prefix + ".failed"
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// Notifier delivers deployment lifecycle events to external systems
+type Notifier interface {
+	Notify(ctx context.Context, event DeploymentEvent) error
+}
+
+// HTTPNotifier sends events over HTTP to every configured endpoint that subscribes to them.
+// Network errors, 429 and 5xx responses are retried with exponential backoff.
+type HTTPNotifier struct {
+	client         *http.Client
+	configs        []Config
+	initialBackoff time.Duration
+}
+
+// Ensure *HTTPNotifier implements Notifier interface
+var _ Notifier = (*HTTPNotifier)(nil)
+
+// NewHTTPNotifier creates an HTTPNotifier for configs using client, or a client with DefaultTimeout if nil
+func NewHTTPNotifier(client *http.Client, configs []Config) *HTTPNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &HTTPNotifier{
+		client:         client,
+		configs:        configs,
+		initialBackoff: DefaultInitialBackoff,
+	}
+}
+
+// Notify delivers event to every subscribed endpoint and returns the failures joined together
+func (n *HTTPNotifier) Notify(ctx context.Context, event DeploymentEvent) error {
+	var errs []error
+	for _, cfg := range n.configs {
+		if !cfg.Accepts(event.Type) {
+			continue
+		}
+		if err := n.deliver(ctx, cfg, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *HTTPNotifier) deliver(ctx context.Context, cfg Config, event DeploymentEvent) error {
+	body, err := RenderBody(cfg.BodyTemplate, event)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.initialBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.send(ctx, cfg, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == MaxAttempts {
+			return fmt.Errorf("notification to %s failed after %d attempt(s): %w", cfg.URL, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("notification to %s cancelled: %w", cfg.URL, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send makes one request and reports whether a failure is worth retrying
+func (n *HTTPNotifier) send(ctx context.Context, cfg Config, body []byte) (bool, error) {
+	method := cfg.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification request for %s: %w", cfg.URL, err)
+	}
+	if cfg.BodyTemplate == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("notification %s %s failed: %w", method, cfg.URL, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("notification %s %s returned status %d", method, cfg.URL, resp.StatusCode)
+	}
+	return false, nil
+}
+
+// RenderBody renders a notification body template with the manifest template functions,
+// or encodes event as JSON when bodyTemplate is empty
+func RenderBody(bodyTemplate string, event DeploymentEvent) ([]byte, error) {
+	if bodyTemplate == "" {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode notification event: %w", err)
+		}
+		return body, nil
+	}
+
+	tmpl, err := template.New("notification").Funcs(manifest.TemplateFuncs(nil)).Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse notification body template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("failed to execute notification body template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,155 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestNotifier(configs ...Config) *HTTPNotifier {
+	n := NewHTTPNotifier(nil, configs)
+	n.initialBackoff = time.Millisecond
+	return n
+}
+
+func TestHTTPNotifier_Notify(t *testing.T) {
+	var gotMethod, gotHeader, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header.Get("X-Token")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(Config{
+		URL:          server.URL,
+		Method:       http.MethodPut,
+		Headers:      map[string]string{"X-Token": "secret"},
+		BodyTemplate: `{"text":"{{ .Action }} {{ .Type }}: {{ join "," .Services }}"}`,
+		Events:       []string{EventDeploySuccess},
+	})
+	event := NewDeploymentEvent("up", []string{"redis", "postgres"}, nil)
+
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if gotMethod != http.MethodPut {
+		t.Errorf("method = %q, want %q", gotMethod, http.MethodPut)
+	}
+	if gotHeader != "secret" {
+		t.Errorf("X-Token header = %q, want %q", gotHeader, "secret")
+	}
+	if want := `{"text":"up deploy.success: redis,postgres"}`; gotBody != want {
+		t.Errorf("body = %q, want %q", gotBody, want)
+	}
+}
+
+func TestHTTPNotifier_FiltersEvents(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(Config{URL: server.URL, Events: []string{EventDeployFailed}})
+
+	if err := n.Notify(context.Background(), NewDeploymentEvent("up", nil, nil)); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("endpoint called %d times for unsubscribed event, want 0", got)
+	}
+
+	if err := n.Notify(context.Background(), NewDeploymentEvent("up", nil, errors.New("boom"))); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("endpoint called %d times for subscribed event, want 1", got)
+	}
+}
+
+func TestHTTPNotifier_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(Config{URL: server.URL})
+	if err := n.Notify(context.Background(), NewDeploymentEvent("update", nil, nil)); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("endpoint called %d times, want 3", got)
+	}
+}
+
+func TestHTTPNotifier_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(Config{URL: server.URL})
+	if err := n.Notify(context.Background(), NewDeploymentEvent("down", nil, nil)); err == nil {
+		t.Error("Notify() error = nil, want error after repeated 502s")
+	}
+	if got := calls.Load(); got != MaxAttempts {
+		t.Errorf("endpoint called %d times, want %d", got, MaxAttempts)
+	}
+}
+
+func TestHTTPNotifier_NoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(Config{URL: server.URL})
+	if err := n.Notify(context.Background(), NewDeploymentEvent("up", nil, nil)); err == nil {
+		t.Error("Notify() error = nil, want error for status 400")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("endpoint called %d times, want 1", got)
+	}
+}
+
+func TestNewDeploymentEvent(t *testing.T) {
+	tests := []struct {
+		action string
+		err    error
+		want   string
+	}{
+		{action: "up", want: EventDeploySuccess},
+		{action: "up", err: errors.New("boom"), want: EventDeployFailed},
+		{action: "down", want: EventDeleteSuccess},
+		{action: "down", err: errors.New("boom"), want: EventDeleteFailed},
+		{action: "update", want: EventUpdateSuccess},
+		{action: "update", err: errors.New("boom"), want: EventUpdateFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			event := NewDeploymentEvent(tt.action, nil, tt.err)
+			if event.Type != tt.want {
+				t.Errorf("NewDeploymentEvent(%q) type = %q, want %q", tt.action, event.Type, tt.want)
+			}
+			if (tt.err != nil) != (event.Error != "") {
+				t.Errorf("NewDeploymentEvent(%q) error = %q, want set only on failure", tt.action, event.Error)
+			}
+		})
+	}
+}
@@ -1,5 +1,9 @@
 package reconciler
 
 // MaxConcurrency defines the maximum number of concurrent reconciliation operations
+//
+// Deprecated: the number of concurrent applies is set with WithWorkers and defaults to DefaultWorkers.
 const MaxConcurrency = 10
 
+// DefaultWorkers is the default number of workers applying the manifests of a priority group concurrently
+const DefaultWorkers = 5
@@ -0,0 +1,178 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// DefaultGVKCacheTTL is how long discovered resources are trusted before a cache miss triggers rediscovery
+const DefaultGVKCacheTTL = 30 * time.Second
+
+// PreferredResourcesDiscoverer is the part of discovery.DiscoveryInterface used by GVKCache
+type PreferredResourcesDiscoverer interface {
+	ServerPreferredResources() ([]*metav1.APIResourceList, error)
+}
+
+type gvkEntry struct {
+	gvk      schema.GroupVersionKind
+	resource string
+}
+
+// builtinGVKs resolve the core kinds when the API server cannot be discovered
+var builtinGVKs = map[string]gvkEntry{
+	"Deployment":            {schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, "deployments"},
+	"StatefulSet":           {schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, "statefulsets"},
+	"Service":               {schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Service"}, "services"},
+	"ConfigMap":             {schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}, "configmaps"},
+	"Secret":                {schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}, "secrets"},
+	"Namespace":             {schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Namespace"}, "namespaces"},
+	"PersistentVolumeClaim": {schema.GroupVersionKind{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"}, "persistentvolumeclaims"},
+}
+
+// GVKCache resolves kinds to their preferred GroupVersionKind and plural resource name
+// using the API server's discovery endpoint. Discovered resources are kept for the TTL;
+// a lookup for an unknown kind after the TTL has passed rediscovers them.
+type GVKCache struct {
+	discovery PreferredResourcesDiscoverer
+	logger    logr.Logger
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]gvkEntry
+	lastRefresh time.Time
+	// refreshing is closed when the discovery in flight finishes; nil when none is running
+	refreshing chan struct{}
+}
+
+// NewGVKCache creates a GVKCache backed by discovery. A ttl of zero uses DefaultGVKCacheTTL.
+func NewGVKCache(discovery PreferredResourcesDiscoverer, logger logr.Logger, ttl time.Duration) *GVKCache {
+	if ttl <= 0 {
+		ttl = DefaultGVKCacheTTL
+	}
+	return &GVKCache{
+		discovery: discovery,
+		logger:    logger,
+		ttl:       ttl,
+		now:       time.Now,
+		entries:   make(map[string]gvkEntry),
+	}
+}
+
+// Resolve returns the preferred GroupVersionKind of kind and its plural resource name
+func (c *GVKCache) Resolve(kind string) (schema.GroupVersionKind, string, error) {
+	entry, ok, expired := c.lookup(kind)
+	if ok {
+		return entry.gvk, entry.resource, nil
+	}
+
+	if expired {
+		if err := c.refresh(); err != nil {
+			c.logger.V(1).Info("failed to discover resources", "kind", kind, "error", err)
+		}
+		if entry, ok, _ := c.lookup(kind); ok {
+			return entry.gvk, entry.resource, nil
+		}
+	}
+
+	if entry, ok := builtinGVKs[kind]; ok {
+		return entry.gvk, entry.resource, nil
+	}
+	return schema.GroupVersionKind{}, "", fmt.Errorf("%w: kind %s is not served by the API server", apperrors.ErrNotFound, kind)
+}
+
+// RebuildCache replaces the cached resources with a fresh discovery of the API server
+func (c *GVKCache) RebuildCache(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return c.refresh()
+}
+
+// lookup returns the cached entry of kind and whether the cached resources have expired
+func (c *GVKCache) lookup(kind string) (gvkEntry, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[kind]
+	return entry, ok, c.now().Sub(c.lastRefresh) >= c.ttl
+}
+
+// refresh rediscovers the resources without holding c.mu, so lookups of cached kinds never
+// wait on the API server. Callers arriving while a discovery is in flight wait for it instead
+// of starting another.
+func (c *GVKCache) refresh() error {
+	c.mu.Lock()
+	if done := c.refreshing; done != nil {
+		c.mu.Unlock()
+		<-done
+		return nil
+	}
+	done := make(chan struct{})
+	c.refreshing = done
+	c.mu.Unlock()
+
+	entries, err := c.discover()
+
+	c.mu.Lock()
+	c.lastRefresh = c.now()
+	if entries != nil {
+		c.entries = entries
+	}
+	c.refreshing = nil
+	c.mu.Unlock()
+	close(done)
+	return err
+}
+
+// discover reads the preferred resources of the API server. It returns nil entries when
+// nothing could be discovered, leaving the cached resources in place.
+func (c *GVKCache) discover() (map[string]gvkEntry, error) {
+	if c.discovery == nil {
+		return nil, nil
+	}
+
+	// Discovery returns the groups it could read alongside an error for the ones it could not
+	apiResourceLists, err := c.discovery.ServerPreferredResources()
+	if err != nil && len(apiResourceLists) == 0 {
+		return nil, apperrors.WrapKubernetes(err, "discover server preferred resources")
+	}
+
+	entries := make(map[string]gvkEntry)
+	for _, apiResourceList := range apiResourceLists {
+		if apiResourceList == nil {
+			continue
+		}
+		gv, parseErr := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if parseErr != nil {
+			continue
+		}
+		for _, apiResource := range apiResourceList.APIResources {
+			// Subresources such as deployments/status share the kind of their parent
+			if strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+			if _, exists := entries[apiResource.Kind]; exists {
+				continue
+			}
+			entries[apiResource.Kind] = gvkEntry{
+				gvk:      gv.WithKind(apiResource.Kind),
+				resource: apiResource.Name,
+			}
+		}
+	}
+
+	if err != nil {
+		return entries, apperrors.WrapKubernetes(err, "discover server preferred resources")
+	}
+	return entries, nil
+}
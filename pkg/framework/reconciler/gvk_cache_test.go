@@ -0,0 +1,209 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// fakeDiscoverer serves a fixed resource list and counts discovery calls
+type fakeDiscoverer struct {
+	lists []*metav1.APIResourceList
+	err   error
+	calls int
+}
+
+func (f *fakeDiscoverer) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	f.calls++
+	return f.lists, f.err
+}
+
+// blockingDiscoverer holds discovery calls until release is closed, once block is set
+type blockingDiscoverer struct {
+	lists   []*metav1.APIResourceList
+	block   bool
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (b *blockingDiscoverer) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	b.calls.Add(1)
+	if b.block {
+		b.started <- struct{}{}
+		<-b.release
+	}
+	return b.lists, nil
+}
+
+func certManagerResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "services", Kind: "Service"}},
+		},
+		{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "certificaterequests", Kind: "CertificateRequest"},
+				{Name: "certificaterequests/status", Kind: "CertificateRequest"},
+			},
+		},
+	}
+}
+
+// newTestGVKCache returns a cache whose clock only moves when the returned function is called
+func newTestGVKCache(discoverer PreferredResourcesDiscoverer) (*GVKCache, func(time.Duration)) {
+	cache := NewGVKCache(discoverer, logr.Discard(), 30*time.Second)
+	now := time.Unix(0, 0)
+	cache.now = func() time.Time { return now }
+	return cache, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestGVKCache_ResolveHit(t *testing.T) {
+	discoverer := &fakeDiscoverer{lists: certManagerResources()}
+	cache, _ := newTestGVKCache(discoverer)
+
+	if err := cache.RebuildCache(context.Background()); err != nil {
+		t.Fatalf("RebuildCache() error = %v", err)
+	}
+
+	gvk, resource, err := cache.Resolve("CertificateRequest")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "CertificateRequest"}
+	if gvk != want {
+		t.Errorf("Resolve() GVK = %v, want %v", gvk, want)
+	}
+	if resource != "certificaterequests" {
+		t.Errorf("Resolve() resource = %v, want certificaterequests", resource)
+	}
+
+	if _, _, err := cache.Resolve("Service"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if discoverer.calls != 1 {
+		t.Errorf("discovery calls = %d, want 1", discoverer.calls)
+	}
+}
+
+func TestGVKCache_ResolveMiss(t *testing.T) {
+	discoverer := &fakeDiscoverer{lists: certManagerResources()}
+	cache, advance := newTestGVKCache(discoverer)
+
+	if err := cache.RebuildCache(context.Background()); err != nil {
+		t.Fatalf("RebuildCache() error = %v", err)
+	}
+
+	// A miss within the TTL does not rediscover
+	if _, _, err := cache.Resolve("VirtualService"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Resolve() error = %v, want ErrNotFound", err)
+	}
+	if discoverer.calls != 1 {
+		t.Errorf("discovery calls = %d, want 1", discoverer.calls)
+	}
+
+	// Built-in kinds resolve even when discovery does not list them
+	advance(time.Second)
+	gvk, resource, err := cache.Resolve("Deployment")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if gvk.Group != "apps" || resource != "deployments" {
+		t.Errorf("Resolve() = %v, %v, want apps Deployment, deployments", gvk, resource)
+	}
+}
+
+func TestGVKCache_RefreshAfterTTL(t *testing.T) {
+	discoverer := &fakeDiscoverer{lists: certManagerResources()}
+	cache, advance := newTestGVKCache(discoverer)
+
+	if err := cache.RebuildCache(context.Background()); err != nil {
+		t.Fatalf("RebuildCache() error = %v", err)
+	}
+
+	// A CRD installed after startup is found once the TTL has passed
+	discoverer.lists = append(discoverer.lists, &metav1.APIResourceList{
+		GroupVersion: "networking.istio.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "virtualservices", Kind: "VirtualService"}},
+	})
+
+	if _, _, err := cache.Resolve("VirtualService"); err == nil {
+		t.Fatal("Resolve() found VirtualService before the TTL expired")
+	}
+
+	advance(31 * time.Second)
+	gvk, resource, err := cache.Resolve("VirtualService")
+	if err != nil {
+		t.Fatalf("Resolve() after TTL error = %v", err)
+	}
+	if gvk.Group != "networking.istio.io" || resource != "virtualservices" {
+		t.Errorf("Resolve() = %v, %v, want networking.istio.io VirtualService, virtualservices", gvk, resource)
+	}
+	if discoverer.calls != 2 {
+		t.Errorf("discovery calls = %d, want 2", discoverer.calls)
+	}
+}
+
+func TestGVKCache_RebuildCacheError(t *testing.T) {
+	discoverer := &fakeDiscoverer{err: errors.New("connection refused")}
+	cache, _ := newTestGVKCache(discoverer)
+
+	if err := cache.RebuildCache(context.Background()); !errors.Is(err, apperrors.ErrKubernetes) {
+		t.Errorf("RebuildCache() error = %v, want ErrKubernetes", err)
+	}
+	if _, _, err := cache.Resolve("Service"); err != nil {
+		t.Errorf("Resolve() of a built-in kind error = %v", err)
+	}
+}
+
+func TestGVKCache_DiscoveryDoesNotBlockLookups(t *testing.T) {
+	discoverer := &blockingDiscoverer{lists: certManagerResources()}
+	cache, advance := newTestGVKCache(discoverer)
+
+	if err := cache.RebuildCache(context.Background()); err != nil {
+		t.Fatalf("RebuildCache() error = %v", err)
+	}
+
+	discoverer.lists = append(discoverer.lists, &metav1.APIResourceList{
+		GroupVersion: "networking.istio.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "virtualservices", Kind: "VirtualService"}},
+	})
+	discoverer.block = true
+	discoverer.started = make(chan struct{}, 1)
+	discoverer.release = make(chan struct{})
+	advance(31 * time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := cache.Resolve("VirtualService"); err != nil {
+				t.Errorf("Resolve() error = %v", err)
+			}
+		}()
+	}
+	<-discoverer.started
+
+	// Cached kinds resolve while discovery is in flight
+	if _, resource, err := cache.Resolve("CertificateRequest"); err != nil || resource != "certificaterequests" {
+		t.Errorf("Resolve() during discovery = %v, %v", resource, err)
+	}
+
+	close(discoverer.release)
+	wg.Wait()
+	if calls := discoverer.calls.Load(); calls != 2 {
+		t.Errorf("discovery calls = %d, want 2", calls)
+	}
+}
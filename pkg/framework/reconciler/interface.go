@@ -2,7 +2,10 @@ package reconciler
 
 import (
 	"context"
+	"time"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -18,14 +21,61 @@ type Reconciler interface {
 	// SetReady sets the ready state of the reconciler
 	SetReady(ready bool)
 
+	// SetPaused pauses or resumes periodic reconciliation
+	SetPaused(paused bool)
+
+	// IsPaused returns whether periodic reconciliation is paused
+	IsPaused() bool
+
+	// ReconcileInterval returns the periodic reconciliation interval
+	ReconcileInterval() time.Duration
+
+	// SetReconcileInterval changes and persists the periodic reconciliation interval
+	SetReconcileInterval(interval time.Duration) error
+
 	// ReconcileKey reconciles a single manifest by key
 	ReconcileKey(ctx context.Context, key string) error
 
+	// ReconcileSelected reconciles only the given keys without deleting unselected resources
+	ReconcileSelected(ctx context.Context, keys []string) error
+
+	// ApplyObjectWithOwner applies obj with an ownerReference to the live object of the manifest at ownerKey
+	ApplyObjectWithOwner(ctx context.Context, obj runtime.Object, ownerKey string) error
+
+	// LoadManagedKeysFromDB restores the managed keys persisted by an earlier process
+	LoadManagedKeysFromDB(ctx context.Context) error
+
+	// ManagedKeys returns the sorted keys of the resources the reconciler currently manages
+	ManagedKeys(ctx context.Context) []string
+
+	// RenameKey moves oldKey to newKey in the managed keys; it does nothing when oldKey is not managed
+	RenameKey(oldKey, newKey string) error
+
+	// BackoffUntil returns when periodic reconciliation retries a resource whose apply failed,
+	// and false when the resource has not failed since its last successful apply
+	BackoffUntil(key string) (time.Time, bool)
+
+	// GetLiveObject fetches the cluster object for a manifest key, returning ErrNotFound if it does not exist
+	GetLiveObject(ctx context.Context, key string) (*unstructured.Unstructured, error)
+
+	// GetManagedResourceStatus fetches the live status of every managed resource, keyed by manifest key
+	GetManagedResourceStatus(ctx context.Context) (map[string]ManagedResourceStatus, error)
+
 	// DeployManifests deploys the provided manifests to the cluster
-	DeployManifests(ctx context.Context, manifests map[string][]byte) error
+	DeployManifests(ctx context.Context, manifests map[string][]byte) (ReconciliationResult, error)
 
 	// UpdateManifests updates the provided manifests in the cluster
-	UpdateManifests(ctx context.Context, manifests map[string][]byte) error
+	UpdateManifests(ctx context.Context, manifests map[string][]byte) (ReconciliationResult, error)
+
+	// StartRollingUpdate updates the provided manifests, releasing only the first batch of pods of
+	// each StatefulSet, and returns the keys of the StatefulSets whose rollout has to be completed
+	StartRollingUpdate(ctx context.Context, manifests map[string][]byte, batchSize int) (ReconciliationResult, []string, error)
+
+	// CompleteRollingUpdate waits for each remaining batch of a StatefulSet rollout and releases the next
+	CompleteRollingUpdate(ctx context.Context, key string) error
+
+	// ResumeRollingUpdates completes the StatefulSet rollouts interrupted by a restart
+	ResumeRollingUpdates(ctx context.Context) error
 
 	// DeleteManifests deletes the provided manifests from the cluster
 	DeleteManifests(ctx context.Context, manifests map[string][]byte) error
@@ -33,6 +83,15 @@ type Reconciler interface {
 	// DeleteAll deletes all managed resources
 	DeleteAll(ctx context.Context) error
 
+	// DeleteAllKeys returns the keys DeleteAll would delete, in the order it deletes them
+	DeleteAllKeys(ctx context.Context) []string
+
+	// ListRollbackSnapshots returns the stored rollback snapshots ordered by version
+	ListRollbackSnapshots(ctx context.Context) ([]RollbackSnapshot, error)
+
+	// GetRollbackSnapshot returns the snapshot with the given version, or the latest if version is 0
+	GetRollbackSnapshot(ctx context.Context, version int) (*RollbackSnapshot, error)
+
 	// WaitForFirstReconciliation waits for the first reconciliation to complete
 	WaitForFirstReconciliation(ctx context.Context) error
 }
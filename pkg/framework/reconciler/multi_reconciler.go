@@ -0,0 +1,72 @@
+package reconciler
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// DefaultClusterName names the cluster the framework connects to by default
+const DefaultClusterName = "default"
+
+// MultiReconciler holds one Reconciler per named cluster
+type MultiReconciler struct {
+	reconcilers map[string]Reconciler
+}
+
+// NewMultiReconciler returns a MultiReconciler for reconcilers keyed by cluster name.
+// The map must contain DefaultClusterName.
+func NewMultiReconciler(reconcilers map[string]Reconciler) (*MultiReconciler, error) {
+	if reconcilers[DefaultClusterName] == nil {
+		return nil, fmt.Errorf("%w: a reconciler for the %q cluster is required", apperrors.ErrInvalid, DefaultClusterName)
+	}
+	copied := make(map[string]Reconciler, len(reconcilers))
+	for name, rec := range reconcilers {
+		copied[name] = rec
+	}
+	return &MultiReconciler{reconcilers: copied}, nil
+}
+
+// Get returns the reconciler of the named cluster; an empty name selects the default cluster
+func (m *MultiReconciler) Get(name string) (Reconciler, bool) {
+	if name == "" {
+		name = DefaultClusterName
+	}
+	rec, ok := m.reconcilers[name]
+	return rec, ok
+}
+
+// Default returns the reconciler of the default cluster
+func (m *MultiReconciler) Default() Reconciler {
+	return m.reconcilers[DefaultClusterName]
+}
+
+// Names returns the sorted cluster names
+func (m *MultiReconciler) Names() []string {
+	names := make([]string, 0, len(m.reconcilers))
+	for name := range m.reconcilers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetKubernetesConfigForCluster returns a config for contextName in the kubeconfig file at
+// kubeconfigPath. An empty kubeconfigPath falls back to GetKubernetesConfigForContext.
+func GetKubernetesConfigForCluster(kubeconfigPath, contextName string) (*rest.Config, error) {
+	if kubeconfigPath == "" {
+		return GetKubernetesConfigForContext(contextName)
+	}
+
+	rules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	config, err := newClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: kubernetes get config: failed to get Kubernetes config from %s: %w", apperrors.ErrKubernetes, kubeconfigPath, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,87 @@
+package reconciler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+func TestNewMultiReconciler_RequiresDefault(t *testing.T) {
+	_, err := NewMultiReconciler(map[string]Reconciler{"east": &reconcilerImpl{}})
+	if !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("NewMultiReconciler() error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestMultiReconciler_Get(t *testing.T) {
+	defaultRec, eastRec := &reconcilerImpl{appName: "default"}, &reconcilerImpl{appName: "east"}
+	multi, err := NewMultiReconciler(map[string]Reconciler{DefaultClusterName: defaultRec, "east": eastRec})
+	if err != nil {
+		t.Fatalf("NewMultiReconciler() error = %v", err)
+	}
+
+	if got, ok := multi.Get(""); !ok || got != defaultRec {
+		t.Errorf("Get(\"\") = %v, %v, want the default reconciler", got, ok)
+	}
+	if got, ok := multi.Get("east"); !ok || got != eastRec {
+		t.Errorf("Get(\"east\") = %v, %v, want the east reconciler", got, ok)
+	}
+	if _, ok := multi.Get("west"); ok {
+		t.Error("Get(\"west\") ok = true, want false")
+	}
+	if multi.Default() != defaultRec {
+		t.Error("Default() did not return the default reconciler")
+	}
+	if got, want := multi.Names(), []string{"default", "east"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestGetKubernetesConfigForCluster(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: first
+clusters:
+- name: first
+  cluster:
+    server: https://first.example.com
+- name: second
+  cluster:
+    server: https://second.example.com
+contexts:
+- name: first
+  context:
+    cluster: first
+    user: admin
+- name: second
+  context:
+    cluster: second
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret
+`
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	for contextName, wantHost := range map[string]string{"": "https://first.example.com", "second": "https://second.example.com"} {
+		config, err := GetKubernetesConfigForCluster(path, contextName)
+		if err != nil {
+			t.Fatalf("GetKubernetesConfigForCluster(%q) error = %v", contextName, err)
+		}
+		if config.Host != wantHost {
+			t.Errorf("GetKubernetesConfigForCluster(%q) host = %q, want %q", contextName, config.Host, wantHost)
+		}
+	}
+
+	if _, err := GetKubernetesConfigForCluster(path, "missing"); !errors.Is(err, apperrors.ErrKubernetes) {
+		t.Errorf("GetKubernetesConfigForCluster(missing) error = %v, want ErrKubernetes", err)
+	}
+}
@@ -1,15 +1,15 @@
 package reconciler
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -17,27 +17,65 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/go-logr/logr"
+	"github.com/garunski/conductor-framework/pkg/framework/database"
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
 	"github.com/garunski/conductor-framework/pkg/framework/store"
 )
 
 type reconcilerImpl struct {
-	clientset         kubernetes.Interface
-	dynamicClient     dynamic.Interface
-	store             store.ManifestStore
-	logger            logr.Logger
-	scheme            *runtime.Scheme
-	ready             bool
-	managedKeys       sync.Map
-	eventStore        events.EventStorage
-	discoveryClient   discovery.DiscoveryInterface
-	gvkCache          map[string]schema.GroupVersionKind
-	resourceNameCache map[string]string
-	cacheMu           sync.RWMutex
-	firstReconcileCh  chan struct{}
-	firstReconcileMu  sync.Mutex
-	appName           string
+	clientset        kubernetes.Interface
+	dynamicClient    dynamic.Interface
+	store            store.ManifestStore
+	logger           logr.Logger
+	scheme           *runtime.Scheme
+	ready            bool
+	managedKeys      sync.Map
+	managedKeysDB    *database.DB
+	eventStore       events.EventStorage
+	gvkCache         *GVKCache
+	firstReconcileCh chan struct{}
+	firstReconcileMu sync.Mutex
+	appName          string
+	rollbackDB       *database.DB
+	readinessTimeout time.Duration
+	deployTimeout    time.Duration
+	metrics          *metrics.Metrics
+	paused           int32
+
+	// reconcileInterval is the periodic reconciliation interval in nanoseconds; intervalChanged
+	// wakes StartPeriodicReconciliation when it changes
+	reconcileInterval int64
+	intervalChanged   chan struct{}
+	intervalPersisted bool
+	settingsDB        *database.DB
+
+	// autoCreateNamespace creates missing namespaces on apply
+	autoCreateNamespace bool
+
+	// useFinalizers attaches ManagedFinalizer on apply and removes it before delete
+	useFinalizers bool
+
+	// rollouts drives partitioned StatefulSet rolling updates; nil unless a rollout DB is set
+	rolloutDB            *database.DB
+	rollingUpdateTimeout time.Duration
+	rollouts             *RollingUpdater
+
+	// backoffMap holds the backoffState of each resource whose last apply failed
+	backoffMap  sync.Map
+	backoffBase time.Duration
+	backoffMax  time.Duration
+
+	// workers is the number of manifests of a priority group applied concurrently
+	workers int
+
+	// rollbackRetention is the number of rollback snapshots kept
+	rollbackRetention int
+
+	// parameterGetter supplies the spec conductor.io/condition annotations are evaluated against
+	parameterGetter manifest.ParameterGetter
 }
 
 func (r *reconcilerImpl) GetClientset() kubernetes.Interface {
@@ -48,15 +86,26 @@ func (r *reconcilerImpl) SetReady(ready bool) {
 	r.ready = ready
 }
 
+// IsReady reports whether the first reconciliation has completed and the reconciler is not paused
 func (r *reconcilerImpl) IsReady() bool {
-	return r.ready
+	return r.ready && !r.IsPaused()
 }
 
 type ReconciliationResult struct {
 	AppliedCount int
-	FailedCount  int
+	FailedCount  int // Includes the applies counted in TimedOutCount
 	DeletedCount int
+	// TimedOutCount is the number of applies that exceeded their deploy timeout
+	TimedOutCount int
+	// BackedOffCount is the number of failing resources periodic reconciliation skipped
+	// because their failure backoff had not expired
+	BackedOffCount int
+	// SkippedCount is the number of resources not applied because their conductor.io/condition
+	// annotation evaluated to false
+	SkippedCount int
 	ManagedKeys  map[string]bool
+
+	skippedKeys []string
 }
 
 func GetKubernetesConfig() (*rest.Config, error) {
@@ -75,9 +124,30 @@ func GetKubernetesConfig() (*rest.Config, error) {
 	return config, nil
 }
 
+// newClientConfig builds a kubeconfig loader; tests replace it to observe the overrides
+var newClientConfig = func(rules clientcmd.ClientConfigLoader, overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// GetKubernetesConfigForContext returns a config for the named kubeconfig context.
+// An empty contextName falls back to GetKubernetesConfig (in-cluster, then default context).
+func GetKubernetesConfigForContext(contextName string) (*rest.Config, error) {
+	if contextName == "" {
+		return GetKubernetesConfig()
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	config, err := newClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: kubernetes get config: failed to get Kubernetes config for context %s: %w", apperrors.ErrKubernetes, contextName, err)
+	}
+	return config, nil
+}
+
 // NewReconciler creates a new Reconciler instance
 // If appName is empty, it defaults to "conductor"
-func NewReconciler(clientset kubernetes.Interface, dynamicClient dynamic.Interface, store store.ManifestStore, logger logr.Logger, eventStore events.EventStorage, appName string) (Reconciler, error) {
+func NewReconciler(clientset kubernetes.Interface, dynamicClient dynamic.Interface, store store.ManifestStore, logger logr.Logger, eventStore events.EventStorage, appName string, opts ...Option) (Reconciler, error) {
 	// Default appName to "conductor" if not provided
 	if appName == "" {
 		appName = "conductor"
@@ -94,29 +164,42 @@ func NewReconciler(clientset kubernetes.Interface, dynamicClient dynamic.Interfa
 		return nil, fmt.Errorf("%w: kubernetes add standard scheme: failed to add standard scheme: %w", apperrors.ErrKubernetes, err)
 	}
 
-	defaultGVKMap := map[string]schema.GroupVersionKind{
-		"Deployment":            {Group: "apps", Version: "v1", Kind: "Deployment"},
-		"StatefulSet":           {Group: "apps", Version: "v1", Kind: "StatefulSet"},
-		"Service":               {Group: "", Version: "v1", Kind: "Service"},
-		"ConfigMap":             {Group: "", Version: "v1", Kind: "ConfigMap"},
-		"Secret":                {Group: "", Version: "v1", Kind: "Secret"},
-		"Namespace":             {Group: "", Version: "v1", Kind: "Namespace"},
-		"PersistentVolumeClaim": {Group: "", Version: "v1", Kind: "PersistentVolumeClaim"},
+	rec := &reconcilerImpl{
+		clientset:        clientset,
+		dynamicClient:    dynamicClient,
+		store:            store,
+		logger:           logger,
+		scheme:           kubeScheme,
+		ready:            false,
+		eventStore:       eventStore,
+		gvkCache:         NewGVKCache(clientset.Discovery(), logger, DefaultGVKCacheTTL),
+		firstReconcileCh: make(chan struct{}, 1),
+		appName:          appName,
+		readinessTimeout: DefaultReadinessTimeout,
+		backoffBase:      DefaultBackoffBase,
+		backoffMax:       DefaultBackoffMax,
+		workers:          DefaultWorkers,
+
+		rollbackRetention: DefaultRollbackRetention,
+
+		rollingUpdateTimeout: DefaultRollingUpdateTimeout,
+
+		reconcileInterval: int64(DefaultReconcileInterval),
+		intervalChanged:   make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(rec)
+	}
+	if rec.rolloutDB != nil {
+		rec.rollouts = NewRollingUpdater(clientset, rec.rolloutDB, rec.rollingUpdateTimeout, logger)
 	}
 
-	rec := &reconcilerImpl{
-		clientset:         clientset,
-		dynamicClient:     dynamicClient,
-		store:             store,
-		logger:            logger,
-		scheme:            kubeScheme,
-		ready:             false,
-		eventStore:        eventStore,
-		discoveryClient:   clientset.Discovery(),
-		firstReconcileCh:  make(chan struct{}, 1),
-		gvkCache:          defaultGVKMap,
-		resourceNameCache: make(map[string]string),
-		appName:           appName,
+	if err := rec.loadReconcileInterval(); err != nil {
+		return nil, fmt.Errorf("failed to restore reconcile interval: %w", err)
+	}
+
+	if err := rec.LoadManagedKeysFromDB(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to restore managed keys: %w", err)
 	}
 
 	return rec, nil
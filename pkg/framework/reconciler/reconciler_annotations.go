@@ -0,0 +1,68 @@
+package reconciler
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ManagedByAnnotation names the application that applies a resource
+	ManagedByAnnotation = "conductor.io/managed-by"
+	// AppliedAtAnnotation records when the framework last applied a resource, in RFC 3339
+	AppliedAtAnnotation = "conductor.io/applied-at"
+	// AppliedByAnnotation names the framework instance that last applied a resource
+	AppliedByAnnotation = "conductor.io/applied-by"
+	// ApplyGenerationAnnotation counts how many times the framework applied a resource
+	ApplyGenerationAnnotation = "conductor.io/generation"
+)
+
+// appliedBy identifies this process in AppliedByAnnotation; in a pod the hostname is the pod name
+var appliedBy = instanceName()
+
+// now returns the time recorded in AppliedAtAnnotation; tests replace it
+var now = time.Now
+
+func instanceName() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "unknown"
+}
+
+// injectManagedAnnotations adds the managed-by and applied-by annotations to obj unless the
+// manifest already sets them, and always sets applied-at to the current time
+func injectManagedAnnotations(obj *unstructured.Unstructured, appName string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if _, ok := annotations[ManagedByAnnotation]; !ok {
+		annotations[ManagedByAnnotation] = appName
+	}
+	if _, ok := annotations[AppliedByAnnotation]; !ok {
+		annotations[AppliedByAnnotation] = appliedBy
+	}
+	annotations[AppliedAtAnnotation] = now().UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+// setApplyGeneration sets ApplyGenerationAnnotation on obj to one more than the count on live,
+// the object in the cluster, starting at 1 when live is nil
+func setApplyGeneration(obj, live *unstructured.Unstructured) {
+	generation := 1
+	if live != nil {
+		if previous, err := strconv.Atoi(live.GetAnnotations()[ApplyGenerationAnnotation]); err == nil && previous > 0 {
+			generation = previous + 1
+		}
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[ApplyGenerationAnnotation] = strconv.Itoa(generation)
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,115 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func annotationTestConfigMap(annotations map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{"name": "settings", "namespace": "default"}
+	if annotations != nil {
+		metadata["annotations"] = annotations
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   metadata,
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+}
+
+// recordApplies makes server-side applies of ConfigMaps on client replace the object returned
+// by later gets, since the fake tracker cannot apply objects that do not exist yet
+func recordApplies(t *testing.T, client *dynamicfake.FakeDynamicClient) func() *unstructured.Unstructured {
+	t.Helper()
+	var live *unstructured.Unstructured
+	client.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		applied := &unstructured.Unstructured{}
+		if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &applied.Object); err != nil {
+			return true, nil, err
+		}
+		live = applied
+		return true, applied, nil
+	})
+	client.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if live == nil {
+			return true, nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "settings")
+		}
+		return true, live.DeepCopy(), nil
+	})
+	return func() *unstructured.Unstructured { return live }
+}
+
+func TestApplyObject_InjectsManagedAnnotations(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	ctx := context.Background()
+	live := recordApplies(t, rec.dynamicClient.(*dynamicfake.FakeDynamicClient))
+
+	original := now
+	defer func() { now = original }()
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return first }
+
+	manifest := annotationTestConfigMap(map[string]interface{}{"team": "payments", ManagedByAnnotation: "platform"})
+	if err := rec.applyObject(ctx, manifest, "default/ConfigMap/settings"); err != nil {
+		t.Fatalf("applyObject() error = %v", err)
+	}
+	if _, ok := manifest.GetAnnotations()[AppliedAtAnnotation]; ok {
+		t.Error("applyObject() annotated the caller's object")
+	}
+
+	annotations := live().GetAnnotations()
+	want := map[string]string{
+		"team":                    "payments",
+		ManagedByAnnotation:       "platform",
+		AppliedByAnnotation:       appliedBy,
+		AppliedAtAnnotation:       "2026-01-02T03:04:05Z",
+		ApplyGenerationAnnotation: "1",
+	}
+	for key, value := range want {
+		if annotations[key] != value {
+			t.Errorf("annotation %s = %q, want %q", key, annotations[key], value)
+		}
+	}
+
+	second := first.Add(time.Hour)
+	now = func() time.Time { return second }
+	if err := rec.applyObject(ctx, annotationTestConfigMap(map[string]interface{}{"team": "payments", ManagedByAnnotation: "platform"}), "default/ConfigMap/settings"); err != nil {
+		t.Fatalf("second applyObject() error = %v", err)
+	}
+	annotations = live().GetAnnotations()
+	if got := annotations[AppliedAtAnnotation]; got != "2026-01-02T04:04:05Z" {
+		t.Errorf("applied-at after second apply = %q, want it updated to 2026-01-02T04:04:05Z", got)
+	}
+	if got := annotations[ApplyGenerationAnnotation]; got != "2" {
+		t.Errorf("generation after second apply = %q, want 2", got)
+	}
+	if annotations["team"] != "payments" || annotations[ManagedByAnnotation] != "platform" {
+		t.Errorf("user annotations after second apply = %v, want them preserved", annotations)
+	}
+}
+
+func TestInjectManagedAnnotations_Defaults(t *testing.T) {
+	obj := annotationTestConfigMap(nil)
+	injectManagedAnnotations(obj, "test-app")
+
+	annotations := obj.GetAnnotations()
+	if annotations[ManagedByAnnotation] != "test-app" {
+		t.Errorf("managed-by = %q, want test-app", annotations[ManagedByAnnotation])
+	}
+	if annotations[AppliedByAnnotation] == "" {
+		t.Error("applied-by is empty")
+	}
+	if _, err := time.Parse(time.RFC3339, annotations[AppliedAtAnnotation]); err != nil {
+		t.Errorf("applied-at = %q is not RFC 3339: %v", annotations[AppliedAtAnnotation], err)
+	}
+}
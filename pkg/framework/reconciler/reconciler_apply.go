@@ -2,8 +2,10 @@ package reconciler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -23,7 +25,7 @@ func (r *reconcilerImpl) applyObject(ctx context.Context, obj runtime.Object, re
 		gvks, _, err := r.scheme.ObjectKinds(obj)
 		if err != nil || len(gvks) == 0 {
 			err := fmt.Errorf("%w: kubernetes convert to unstructured %s: failed to get object kinds: %w", apperrors.ErrKubernetes, resourceKey, err)
-			events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
 			return err
 		}
 		
@@ -36,14 +38,14 @@ func (r *reconcilerImpl) applyObject(ctx context.Context, obj runtime.Object, re
 		data, err := runtime.Encode(codec, obj)
 		if err != nil {
 			err := fmt.Errorf("%w: kubernetes encode object %s: failed to encode object: %w", apperrors.ErrKubernetes, resourceKey, err)
-			events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
 			return err
 		}
 
 		_, _, err = serializer.NewCodecFactory(r.scheme).UniversalDeserializer().Decode(data, nil, unstructuredObj)
 		if err != nil {
 			err := fmt.Errorf("%w: kubernetes convert to unstructured %s: failed to convert object to unstructured: %w", apperrors.ErrKubernetes, resourceKey, err)
-			events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
 			return err
 		}
 	}
@@ -51,7 +53,7 @@ func (r *reconcilerImpl) applyObject(ctx context.Context, obj runtime.Object, re
 	gvk := unstructuredObj.GroupVersionKind()
 	if gvk.Kind == "" {
 		err := fmt.Errorf("%w: object missing kind for resource %s", apperrors.ErrInvalid, resourceKey)
-		events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply object", err))
 		return err
 	}
 
@@ -70,13 +72,59 @@ func (r *reconcilerImpl) applyObject(ctx context.Context, obj runtime.Object, re
 		resourceInterface = r.dynamicClient.Resource(gvr)
 	}
 
-	_, err := resourceInterface.Apply(ctx, unstructuredObj.GetName(), unstructuredObj, metav1.ApplyOptions{FieldManager: r.appName, Force: true})
+	// Only a missing object is treated as absent; any other error would make the apply run on
+	// a wrong view of the cluster
+	live, err := resourceInterface.Get(ctx, unstructuredObj.GetName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to get live object", err))
+		getErr := fmt.Errorf("%w: kubernetes get %s: failed to get live resource: %w", apperrors.ErrKubernetes, resourceKey, err)
+		return apperrors.WithDetails(getErr, applyErrorDetails(resourceKey, err))
+	}
+	if live != nil && live.GetDeletionTimestamp() != nil {
+		return r.releaseTerminating(ctx, resourceInterface, live, resourceKey)
+	}
+
+	// Annotate a copy so the caller's object stays as it was parsed from the manifest
+	unstructuredObj = unstructuredObj.DeepCopy()
+	injectManagedAnnotations(unstructuredObj, r.appName)
+	if r.useFinalizers {
+		AddFinalizer(unstructuredObj)
+	}
+	r.injectRolloutPartition(unstructuredObj, resourceKey)
+	setApplyGeneration(unstructuredObj, live)
+
+	applyOptions := metav1.ApplyOptions{FieldManager: r.appName, Force: true}
+	_, err = resourceInterface.Apply(ctx, unstructuredObj.GetName(), unstructuredObj, applyOptions)
+	if err != nil && r.autoCreateNamespace && isNamespaceNotFound(err, unstructuredObj.GetNamespace()) {
+		if nsErr := r.ensureNamespace(ctx, unstructuredObj.GetNamespace()); nsErr == nil {
+			_, err = resourceInterface.Apply(ctx, unstructuredObj.GetName(), unstructuredObj, applyOptions)
+		}
+	}
 	if err != nil {
-		events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply manifest to cluster", err))
-		return fmt.Errorf("%w: kubernetes apply %s: failed to apply resource: %w", apperrors.ErrKubernetes, resourceKey, err)
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to apply manifest to cluster", err))
+		applyErr := fmt.Errorf("%w: kubernetes apply %s: failed to apply resource: %w", apperrors.ErrKubernetes, resourceKey, err)
+		return apperrors.WithDetails(applyErr, applyErrorDetails(resourceKey, err))
 	}
 
-	events.StoreEventSafe(r.eventStore, r.logger, events.Success(resourceKey, "apply", "Successfully applied manifest"))
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Success(resourceKey, "apply", "Successfully applied manifest"))
 	return nil
 }
 
+// applyErrorDetails describes a failed apply: the resource key plus the API server's reason and status code when it returned one
+func applyErrorDetails(resourceKey string, err error) map[string]interface{} {
+	details := map[string]interface{}{"key": resourceKey}
+	var status k8serrors.APIStatus
+	if errors.As(err, &status) {
+		s := status.Status()
+		if s.Reason != "" {
+			details["reason"] = string(s.Reason)
+		}
+		if s.Code != 0 {
+			details["http_code"] = int(s.Code)
+		}
+	}
+	return details
+}
+
@@ -5,8 +5,16 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 )
 
 // Test applyObject with unstructured object
@@ -130,3 +138,80 @@ func TestReconciler_applyObject_MissingKind(t *testing.T) {
 		t.Error("applyObject() expected error for missing kind, got nil")
 	}
 }
+
+// Test that a rejected apply reports the resource key, reason and status code as error details
+func TestReconciler_applyObject_ErrorDetails(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl, ok := rec.(*reconcilerImpl)
+	if !ok {
+		t.Fatal("rec is not *reconcilerImpl")
+	}
+	impl.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "test-configmap",
+			field.ErrorList{field.Invalid(field.NewPath("data"), "x", "admission webhook rejected")})
+	})
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "test-configmap",
+				"namespace": "default",
+			},
+		},
+	}
+	key := "default/ConfigMap/test-configmap"
+
+	err := impl.applyObject(context.Background(), obj, key)
+	if err == nil {
+		t.Fatal("applyObject() expected error for rejected apply, got nil")
+	}
+	details := apperrors.Details(err)
+	if details["key"] != key {
+		t.Errorf("details[key] = %v, want %s", details["key"], key)
+	}
+	if details["reason"] != string(metav1.StatusReasonInvalid) {
+		t.Errorf("details[reason] = %v, want %s", details["reason"], metav1.StatusReasonInvalid)
+	}
+	if details["http_code"] != 422 {
+		t.Errorf("details[http_code] = %v, want 422", details["http_code"])
+	}
+}
+
+// Test that an error reading the live object fails the apply instead of treating the object as absent
+func TestReconciler_applyObject_GetError(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl, ok := rec.(*reconcilerImpl)
+	if !ok {
+		t.Fatal("rec is not *reconcilerImpl")
+	}
+	client := impl.dynamicClient.(*dynamicfake.FakeDynamicClient)
+	client.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "test-configmap", nil)
+	})
+	applied := false
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		applied = true
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "test-configmap",
+				"namespace": "default",
+			},
+		},
+	}
+
+	err := impl.applyObject(context.Background(), obj, "default/ConfigMap/test-configmap")
+	if !k8serrors.IsForbidden(err) {
+		t.Errorf("applyObject() error = %v, want the forbidden get error", err)
+	}
+	if applied {
+		t.Error("applyObject() applied the object after failing to read it")
+	}
+}
@@ -0,0 +1,90 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultBackoffBase is the delay before a failing resource is retried after its first failure
+	DefaultBackoffBase = 5 * time.Second
+	// DefaultBackoffMax caps the delay between retries of a failing resource
+	DefaultBackoffMax = 5 * time.Minute
+)
+
+// backoffState tracks the consecutive apply failures of one resource
+type backoffState struct {
+	failures int
+	until    time.Time
+}
+
+type backoffContextKey struct{}
+
+// WithBackoff makes periodic reconciliation skip a resource whose apply failed for
+// min(base * 2^(n-1), max) after its n-th consecutive failure. A zero base disables backoff.
+func WithBackoff(base, max time.Duration) Option {
+	return func(r *reconcilerImpl) {
+		r.backoffBase = base
+		r.backoffMax = max
+	}
+}
+
+// withBackoffSkip marks ctx as a periodic reconciliation, which skips resources in backoff
+func withBackoffSkip(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backoffContextKey{}, true)
+}
+
+func skipsBackedOff(ctx context.Context) bool {
+	skip, _ := ctx.Value(backoffContextKey{}).(bool)
+	return skip
+}
+
+// backoffDelay returns the delay after the given number of consecutive failures
+func (r *reconcilerImpl) backoffDelay(failures int) time.Duration {
+	delay := r.backoffBase
+	for i := 1; i < failures; i++ {
+		if r.backoffMax > 0 && delay >= r.backoffMax {
+			break
+		}
+		delay *= 2
+	}
+	if r.backoffMax > 0 && delay > r.backoffMax {
+		delay = r.backoffMax
+	}
+	return delay
+}
+
+// recordFailure extends the backoff of key after a failed apply and returns when it expires
+func (r *reconcilerImpl) recordFailure(key string) time.Time {
+	if r.backoffBase <= 0 {
+		return time.Time{}
+	}
+	state := backoffState{failures: 1}
+	if previous, ok := r.backoffMap.Load(key); ok {
+		state.failures = previous.(backoffState).failures + 1
+	}
+	state.until = time.Now().Add(r.backoffDelay(state.failures))
+	r.backoffMap.Store(key, state)
+	return state.until
+}
+
+// resetBackoff clears the backoff of key after a successful apply
+func (r *reconcilerImpl) resetBackoff(key string) {
+	r.backoffMap.Delete(key)
+}
+
+// inBackoff reports whether key is still waiting for its backoff to expire
+func (r *reconcilerImpl) inBackoff(key string) bool {
+	until, ok := r.BackoffUntil(key)
+	return ok && time.Now().Before(until)
+}
+
+// BackoffUntil returns when periodic reconciliation retries key after its last failed
+// apply, and false when key has no failures since its last successful apply
+func (r *reconcilerImpl) BackoffUntil(key string) (time.Time, bool) {
+	state, ok := r.backoffMap.Load(key)
+	if !ok {
+		return time.Time{}, false
+	}
+	return state.(backoffState).until, true
+}
@@ -0,0 +1,94 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReconciler_BackoffDelay(t *testing.T) {
+	impl := &reconcilerImpl{backoffBase: time.Second, backoffMax: 10 * time.Second}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, expected := range want {
+		if got := impl.backoffDelay(i + 1); got != expected {
+			t.Errorf("backoffDelay(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+}
+
+func TestReconciler_RecordFailureIncreasesBackoff(t *testing.T) {
+	impl := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	impl.backoffBase = time.Minute
+	impl.backoffMax = time.Hour
+	key := "default/ConfigMap/broken"
+
+	var previous time.Duration
+	for i := 0; i < 4; i++ {
+		delay := time.Until(impl.recordFailure(key))
+		if i > 0 && (delay < 2*previous-time.Second || delay > 2*previous+time.Second) {
+			t.Errorf("backoff after failure %d = %v, want about twice %v", i+1, delay, previous)
+		}
+		previous = delay
+	}
+
+	impl.resetBackoff(key)
+	if _, ok := impl.BackoffUntil(key); ok {
+		t.Error("BackoffUntil() reports a backoff after resetBackoff()")
+	}
+}
+
+func TestReconciler_BackoffSkipsFailingResources(t *testing.T) {
+	impl := setupSlowTestReconciler(t, 0)
+	impl.backoffBase = time.Hour
+	impl.backoffMax = 4 * time.Hour
+	ctx := context.Background()
+	key := "default/ConfigMap/broken"
+
+	manifests := map[string][]byte{
+		key:                     []byte("invalid: yaml: content"),
+		"default/ConfigMap/cm1": timeoutConfigMap("cm1", ""),
+	}
+
+	result, err := impl.reconcile(withBackoffSkip(ctx), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.FailedCount != 1 || result.BackedOffCount != 0 {
+		t.Fatalf("first reconcile FailedCount = %d, BackedOffCount = %d, want 1 and 0", result.FailedCount, result.BackedOffCount)
+	}
+	firstUntil, ok := impl.BackoffUntil(key)
+	if !ok {
+		t.Fatal("BackoffUntil() reports no backoff after a failed apply")
+	}
+
+	// Periodic reconciliation skips the resource until the backoff expires
+	result, err = impl.reconcile(withBackoffSkip(ctx), manifests, result.ManagedKeys)
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.FailedCount != 0 || result.BackedOffCount != 1 || result.AppliedCount != 1 {
+		t.Errorf("backed off reconcile = %+v, want 1 applied, 0 failed and 1 backed off", result)
+	}
+	if !result.ManagedKeys[key] {
+		t.Error("a backed off resource was dropped from the managed keys")
+	}
+
+	// Explicit deployments still retry it, doubling the backoff on failure
+	if _, err := impl.DeployManifests(ctx, manifests); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	secondUntil, _ := impl.BackoffUntil(key)
+	if delay := secondUntil.Sub(firstUntil); delay < 50*time.Minute {
+		t.Errorf("second failure extended the backoff by %v, want about an hour more", delay)
+	}
+
+	// A successful apply resets the backoff
+	manifests[key] = timeoutConfigMap("broken", "")
+	if _, err := impl.DeployManifests(ctx, manifests); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	if _, ok := impl.BackoffUntil(key); ok {
+		t.Error("BackoffUntil() reports a backoff after a successful apply")
+	}
+}
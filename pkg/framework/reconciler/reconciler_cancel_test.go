@@ -0,0 +1,95 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// setupCancelTestReconciler returns a single worker reconciler whose first apply cancels the
+// returned context
+func setupCancelTestReconciler(t *testing.T) (*reconcilerImpl, *applyTracker, context.Context) {
+	t.Helper()
+	impl, tracker := setupWorkerTestReconciler(t, 1, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	tracker.onApply = cancel
+
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("config-%d", i)
+		if err := impl.store.Create("default/ConfigMap/"+name, timeoutConfigMap(name, "")); err != nil {
+			t.Fatalf("failed to create manifest: %v", err)
+		}
+	}
+	return impl, tracker, ctx
+}
+
+func TestReconciler_reconcileStopsOnCancel(t *testing.T) {
+	impl, tracker, ctx := setupCancelTestReconciler(t)
+
+	result, err := impl.reconcile(ctx, impl.store.List(), map[string]bool{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("reconcile() error = %v, want context.Canceled", err)
+	}
+
+	// The apply in progress at cancellation completes; no further apply starts
+	if len(tracker.order) != 1 {
+		t.Errorf("reconcile() applied %v after cancellation, want only the in-progress apply", tracker.order)
+	}
+	if result.AppliedCount != 1 || result.FailedCount != 0 {
+		t.Errorf("reconcile() applied %d and failed %d, want the in-progress apply to succeed", result.AppliedCount, result.FailedCount)
+	}
+	if _, inBackoff := impl.BackoffUntil("default/ConfigMap/" + tracker.order[0]); inBackoff {
+		t.Error("reconcile() started a backoff for the apply in progress at cancellation")
+	}
+}
+
+func TestReconciler_reconcileAllRecordsCancellation(t *testing.T) {
+	impl, _, ctx := setupCancelTestReconciler(t)
+
+	impl.reconcileAll(ctx)
+
+	infos, err := impl.eventStore.ListEvents(events.EventFilters{Type: events.EventTypeInfo, Limit: 100})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	found := false
+	for _, event := range infos {
+		if event.Message == "reconciliation canceled after 1 resources" {
+			found = true
+		}
+		if event.Message == "Reconciliation complete" {
+			t.Error("reconcileAll() recorded completion of a canceled reconciliation")
+		}
+	}
+	if !found {
+		t.Error("reconcileAll() did not record the cancellation event")
+	}
+	if impl.ready {
+		t.Error("reconcileAll() marked the reconciler ready after cancellation")
+	}
+}
+
+func TestReconciler_StartPeriodicReconciliationExitsOnCancel(t *testing.T) {
+	impl, tracker, ctx := setupCancelTestReconciler(t)
+
+	done := make(chan struct{})
+	go func() {
+		impl.StartPeriodicReconciliation(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartPeriodicReconciliation() did not exit after cancellation")
+	}
+
+	if len(tracker.order) != 1 {
+		t.Errorf("StartPeriodicReconciliation() applied %v, want only the in-progress apply", tracker.order)
+	}
+}
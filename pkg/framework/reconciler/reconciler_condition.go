@@ -0,0 +1,71 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// WithParameterGetter sets where the DeploymentParameters spec that conductor.io/condition
+// annotations are evaluated against comes from. Without it conditions see an empty spec.
+func WithParameterGetter(getter manifest.ParameterGetter) Option {
+	return func(r *reconcilerImpl) {
+		r.parameterGetter = getter
+	}
+}
+
+// conditionParams fetches the spec conditions are evaluated against at most once per reconcile,
+// and only when a manifest has a condition
+type conditionParams struct {
+	getter manifest.ParameterGetter
+
+	once sync.Once
+	spec map[string]interface{}
+	err  error
+}
+
+func (r *reconcilerImpl) newConditionParams() *conditionParams {
+	return &conditionParams{getter: r.parameterGetter}
+}
+
+func (p *conditionParams) get(ctx context.Context) (map[string]interface{}, error) {
+	p.once.Do(func() {
+		p.spec = make(map[string]interface{})
+		if p.getter == nil {
+			return
+		}
+		spec, err := p.getter(ctx)
+		if err != nil {
+			p.err = fmt.Errorf("failed to get parameters for condition: %w", err)
+			return
+		}
+		if spec != nil {
+			p.spec = spec
+		}
+	})
+	return p.spec, p.err
+}
+
+// conditionMet evaluates the conductor.io/condition annotation of obj against params and
+// reports whether obj should be applied. Objects without the annotation are always applied.
+func conditionMet(ctx context.Context, obj runtime.Object, params *conditionParams) (bool, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return true, nil
+	}
+	condition, ok := accessor.GetAnnotations()[manifest.ConditionAnnotation]
+	if !ok {
+		return true, nil
+	}
+
+	spec, err := params.get(ctx)
+	if err != nil {
+		return false, err
+	}
+	return manifest.EvaluateCondition(condition, spec)
+}
@@ -0,0 +1,115 @@
+package reconciler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func conditionConfigMap(name, condition string) []byte {
+	return []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: default\n" +
+		"  annotations:\n    conductor.io/condition: \"" + condition + "\"\n")
+}
+
+func TestReconciler_reconcileSkipsFalseCondition(t *testing.T) {
+	impl, tracker := setupWorkerTestReconciler(t, 1, 0)
+	impl.parameterGetter = func(ctx context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"global": map[string]interface{}{"enableMonitoring": false, "enableTracing": true},
+		}, nil
+	}
+
+	manifests := map[string][]byte{
+		"default/ConfigMap/monitoring": conditionConfigMap("monitoring", "{{ .Spec.global.enableMonitoring }}"),
+		"default/ConfigMap/tracing":    conditionConfigMap("tracing", "{{ .Spec.global.enableTracing }}"),
+		"default/ConfigMap/always":     timeoutConfigMap("always", ""),
+	}
+
+	result, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.AppliedCount != 2 || result.SkippedCount != 1 || result.FailedCount != 0 {
+		t.Errorf("reconcile() applied %d, skipped %d, failed %d, want 2, 1, 0", result.AppliedCount, result.SkippedCount, result.FailedCount)
+	}
+	for _, name := range tracker.order {
+		if name == "monitoring" {
+			t.Error("reconcile() applied monitoring, whose condition is false")
+		}
+	}
+	if result.ManagedKeys["default/ConfigMap/monitoring"] {
+		t.Error("reconcile() kept the skipped resource in ManagedKeys")
+	}
+	if !result.ManagedKeys["default/ConfigMap/tracing"] {
+		t.Error("reconcile() ManagedKeys missing tracing, whose condition is true")
+	}
+}
+
+func TestReconciler_reconcileDeletesResourceWhoseConditionTurnedFalse(t *testing.T) {
+	impl, _ := setupWorkerTestReconciler(t, 1, 0)
+	enabled := true
+	impl.parameterGetter = func(ctx context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"enabled": enabled}, nil
+	}
+
+	manifests := map[string][]byte{
+		"default/ConfigMap/optional": conditionConfigMap("optional", "{{ .Spec.enabled }}"),
+	}
+	result, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if err != nil || result.AppliedCount != 1 {
+		t.Fatalf("reconcile() applied %d, error = %v, want 1 applied", result.AppliedCount, err)
+	}
+
+	enabled = false
+	result, err = impl.reconcile(context.Background(), manifests, result.ManagedKeys)
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.SkippedCount != 1 || result.DeletedCount != 1 {
+		t.Errorf("reconcile() skipped %d, deleted %d, want 1, 1", result.SkippedCount, result.DeletedCount)
+	}
+}
+
+func TestReconciler_reconcileFailsInvalidCondition(t *testing.T) {
+	impl, tracker := setupWorkerTestReconciler(t, 1, 0)
+
+	manifests := map[string][]byte{
+		"default/ConfigMap/broken": conditionConfigMap("broken", "{{ .Spec.global"),
+	}
+	result, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.FailedCount != 1 || len(tracker.order) != 0 {
+		t.Errorf("reconcile() failed %d and applied %v, want 1 failure and no apply", result.FailedCount, tracker.order)
+	}
+}
+
+func TestReconciler_reconcileGetsParametersOncePerReconcile(t *testing.T) {
+	impl, _ := setupWorkerTestReconciler(t, 2, 0)
+	var calls int32
+	impl.parameterGetter = func(ctx context.Context) (map[string]interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return map[string]interface{}{"enabled": true}, nil
+	}
+
+	manifests := make(map[string][]byte)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		manifests["default/ConfigMap/"+name] = conditionConfigMap(name, "{{ .Spec.enabled }}")
+	}
+	if _, err := impl.reconcile(context.Background(), manifests, map[string]bool{}); err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("parameterGetter called %d times, want once per reconcile", calls)
+	}
+
+	// Without conditions the parameters are not fetched at all
+	calls = 0
+	if _, err := impl.reconcile(context.Background(), map[string][]byte{"default/ConfigMap/plain": timeoutConfigMap("plain", "")}, map[string]bool{}); err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("parameterGetter called %d times without conditions, want 0", calls)
+	}
+}
@@ -0,0 +1,121 @@
+package reconciler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: default-ctx
+clusters:
+- name: default-cluster
+  cluster:
+    server: https://default.example:6443
+- name: other-cluster
+  cluster:
+    server: https://other.example:6443
+contexts:
+- name: default-ctx
+  context:
+    cluster: default-cluster
+    user: test
+- name: other-ctx
+  context:
+    cluster: other-cluster
+    user: test
+users:
+- name: test
+  user:
+    token: test-token
+`
+
+// writeTestKubeconfig points KUBECONFIG at a kubeconfig with two contexts
+func writeTestKubeconfig(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	t.Setenv("KUBECONFIG", path)
+	// Ensure in-cluster config is not picked up
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+}
+
+// baseClientConfig lets stubClientConfig embed the interface without a name clash
+type baseClientConfig = clientcmd.ClientConfig
+
+// stubClientConfig returns a fixed rest.Config in place of a kubeconfig lookup
+type stubClientConfig struct {
+	baseClientConfig
+	host string
+}
+
+func (s stubClientConfig) ClientConfig() (*rest.Config, error) {
+	return &rest.Config{Host: s.host}, nil
+}
+
+func TestGetKubernetesConfigForContext_PassesContextToLoader(t *testing.T) {
+	var gotContext string
+	original := newClientConfig
+	newClientConfig = func(rules clientcmd.ClientConfigLoader, overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+		gotContext = overrides.CurrentContext
+		return stubClientConfig{host: "https://stub.example"}
+	}
+	defer func() { newClientConfig = original }()
+
+	config, err := GetKubernetesConfigForContext("staging")
+	if err != nil {
+		t.Fatalf("GetKubernetesConfigForContext() error = %v", err)
+	}
+	if gotContext != "staging" {
+		t.Errorf("loader CurrentContext = %q, want staging", gotContext)
+	}
+	if config.Host != "https://stub.example" {
+		t.Errorf("GetKubernetesConfigForContext() Host = %q, want stub host", config.Host)
+	}
+}
+
+func TestGetKubernetesConfigForContext_EmptyUsesDefault(t *testing.T) {
+	writeTestKubeconfig(t)
+
+	called := false
+	original := newClientConfig
+	newClientConfig = func(rules clientcmd.ClientConfigLoader, overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+		called = true
+		return original(rules, overrides)
+	}
+	defer func() { newClientConfig = original }()
+
+	config, err := GetKubernetesConfigForContext("")
+	if err != nil {
+		t.Fatalf("GetKubernetesConfigForContext() error = %v", err)
+	}
+	if called {
+		t.Error("GetKubernetesConfigForContext(\"\") should not override the context")
+	}
+	if config.Host != "https://default.example:6443" {
+		t.Errorf("GetKubernetesConfigForContext() Host = %q, want default context server", config.Host)
+	}
+}
+
+func TestGetKubernetesConfigForContext_SelectsContext(t *testing.T) {
+	writeTestKubeconfig(t)
+
+	config, err := GetKubernetesConfigForContext("other-ctx")
+	if err != nil {
+		t.Fatalf("GetKubernetesConfigForContext() error = %v", err)
+	}
+	if config.Host != "https://other.example:6443" {
+		t.Errorf("GetKubernetesConfigForContext() Host = %q, want other context server", config.Host)
+	}
+
+	if _, err := GetKubernetesConfigForContext("missing-ctx"); err == nil {
+		t.Error("GetKubernetesConfigForContext() with unknown context should return error")
+	}
+}
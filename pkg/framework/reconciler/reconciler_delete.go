@@ -35,19 +35,26 @@ func (r *reconcilerImpl) deleteObject(ctx context.Context, obj *unstructured.Uns
 		resourceInterface = r.dynamicClient.Resource(gvr)
 	}
 
+	// Always release ManagedFinalizer: the resource may have been applied while finalizers
+	// were enabled, and the delete would otherwise never complete
+	if err := removeFinalizer(ctx, resourceInterface, obj.GetName(), resourceKey); err != nil {
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "delete", "Failed to remove finalizer before delete", err))
+		return err
+	}
+
 	err := resourceInterface.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
 		err = fmt.Errorf("%w: kubernetes delete %s: failed to delete resource: %w", apperrors.ErrKubernetes, resourceKey, err)
-		events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "delete", "Failed to delete resource from cluster", err))
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "delete", "Failed to delete resource from cluster", err))
 		return err
 	}
 
 	if k8serrors.IsNotFound(err) {
 		r.logger.Info("Resource already deleted from cluster", "key", resourceKey)
-		events.StoreEventSafe(r.eventStore, r.logger, events.Info(resourceKey, "delete", "Resource already deleted from cluster"))
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Info(resourceKey, "delete", "Resource already deleted from cluster"))
 	} else {
 		r.logger.Info("Successfully deleted resource", "key", resourceKey)
-		events.StoreEventSafe(r.eventStore, r.logger, events.Success(resourceKey, "delete", "Successfully deleted resource"))
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Success(resourceKey, "delete", "Successfully deleted resource"))
 	}
 	return nil
 }
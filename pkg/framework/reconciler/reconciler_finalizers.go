@@ -0,0 +1,113 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// ManagedFinalizer is attached to applied resources when finalizers are enabled, so that a
+// delete from outside the framework leaves the resource terminating instead of removing it
+const ManagedFinalizer = "conductor.io/managed"
+
+// WithFinalizers attaches ManagedFinalizer to every applied resource. The reconciler removes
+// the finalizer before every delete whether or not this is enabled, and releases it when a
+// resource deleted from outside the framework is found terminating.
+func WithFinalizers(enabled bool) Option {
+	return func(r *reconcilerImpl) {
+		r.useFinalizers = enabled
+	}
+}
+
+// AddFinalizer adds ManagedFinalizer to obj unless it is already present
+func AddFinalizer(obj *unstructured.Unstructured) {
+	finalizers := obj.GetFinalizers()
+	for _, f := range finalizers {
+		if f == ManagedFinalizer {
+			return
+		}
+	}
+	obj.SetFinalizers(append(finalizers, ManagedFinalizer))
+}
+
+// RemoveFinalizer patches ManagedFinalizer off the live resource of obj. A resource that does
+// not exist or does not carry the finalizer is left alone.
+func (r *reconcilerImpl) RemoveFinalizer(ctx context.Context, obj *unstructured.Unstructured, resourceKey string) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" {
+		return fmt.Errorf("%w: object missing kind for resource %s", apperrors.ErrInvalid, resourceKey)
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Resource: r.resolveResourceName(gvk),
+	}
+
+	var resourceInterface dynamic.ResourceInterface
+	if obj.GetNamespace() != "" {
+		resourceInterface = r.dynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+	} else {
+		resourceInterface = r.dynamicClient.Resource(gvr)
+	}
+	return removeFinalizer(ctx, resourceInterface, obj.GetName(), resourceKey)
+}
+
+// releaseTerminating handles a live resource that was deleted from outside the framework and
+// is held by ManagedFinalizer: it removes the finalizer so the delete completes and returns an
+// error, so the resource is recreated by the next reconcile instead of being applied to an
+// object that is going away
+func (r *reconcilerImpl) releaseTerminating(ctx context.Context, resourceInterface dynamic.ResourceInterface, live *unstructured.Unstructured, resourceKey string) error {
+	if err := removeFinalizer(ctx, resourceInterface, live.GetName(), resourceKey); err != nil {
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "apply", "Failed to release finalizer of a terminating resource", err))
+		return err
+	}
+	err := fmt.Errorf("%w: resource %s is being deleted; it is recreated by the next reconcile", apperrors.ErrReconciliation, resourceKey)
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Warning(resourceKey, "apply", "Resource is being deleted outside the framework; released its finalizer"))
+	return err
+}
+
+func removeFinalizer(ctx context.Context, resourceInterface dynamic.ResourceInterface, name, resourceKey string) error {
+	live, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: kubernetes get %s: failed to read finalizers: %w", apperrors.ErrKubernetes, resourceKey, err)
+	}
+
+	current := live.GetFinalizers()
+	remaining := make([]string, 0, len(current))
+	for _, f := range current {
+		if f != ManagedFinalizer {
+			remaining = append(remaining, f)
+		}
+	}
+	if len(remaining) == len(current) {
+		return nil
+	}
+
+	// The test operation makes the patch fail instead of dropping a finalizer another
+	// controller added since the read
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/finalizers", "value": current},
+		{"op": "replace", "path": "/metadata/finalizers", "value": remaining},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to build finalizer patch for %s: %w", apperrors.ErrKubernetes, resourceKey, err)
+	}
+	if _, err := resourceInterface.Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("%w: kubernetes patch %s: failed to remove finalizer: %w", apperrors.ErrKubernetes, resourceKey, err)
+	}
+	return nil
+}
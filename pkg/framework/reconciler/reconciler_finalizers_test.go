@@ -0,0 +1,138 @@
+package reconciler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func TestAddFinalizer(t *testing.T) {
+	obj := annotationTestConfigMap(nil)
+	obj.SetFinalizers([]string{"example.com/keep"})
+
+	AddFinalizer(obj)
+	AddFinalizer(obj)
+
+	want := []string{"example.com/keep", ManagedFinalizer}
+	if got := obj.GetFinalizers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AddFinalizer() finalizers = %v, want %v", got, want)
+	}
+}
+
+func TestApplyObject_AddsFinalizer(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	live := recordApplies(t, rec.dynamicClient.(*dynamicfake.FakeDynamicClient))
+
+	rec.useFinalizers = true
+	if err := rec.applyObject(context.Background(), annotationTestConfigMap(nil), "default/ConfigMap/settings"); err != nil {
+		t.Fatalf("applyObject() error = %v", err)
+	}
+	if got := live().GetFinalizers(); !reflect.DeepEqual(got, []string{ManagedFinalizer}) {
+		t.Errorf("applied finalizers = %v, want [%s]", got, ManagedFinalizer)
+	}
+}
+
+func TestApplyObject_NoFinalizerByDefault(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	live := recordApplies(t, rec.dynamicClient.(*dynamicfake.FakeDynamicClient))
+
+	if err := rec.applyObject(context.Background(), annotationTestConfigMap(nil), "default/ConfigMap/settings"); err != nil {
+		t.Fatalf("applyObject() error = %v", err)
+	}
+	if got := live().GetFinalizers(); len(got) != 0 {
+		t.Errorf("applied finalizers = %v, want none", got)
+	}
+}
+
+func TestDeleteObject_RemovesFinalizerFirst(t *testing.T) {
+	// Finalizers are off, as after disabling them with resources already applied
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	ctx := context.Background()
+	client := rec.dynamicClient.(*dynamicfake.FakeDynamicClient)
+
+	obj := annotationTestConfigMap(nil)
+	created := obj.DeepCopy()
+	created.SetFinalizers([]string{ManagedFinalizer, "example.com/keep"})
+	if _, err := client.Resource(configMapsGVR).Namespace("default").Create(ctx, created, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create resource: %v", err)
+	}
+
+	// Record the finalizers the resource carries when the delete arrives
+	var atDelete []string
+	deleted := false
+	client.PrependReactor("delete", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		current, err := client.Tracker().Get(configMapsGVR, "default", "settings")
+		if err != nil {
+			return true, nil, err
+		}
+		atDelete = current.(*unstructured.Unstructured).GetFinalizers()
+		deleted = true
+		return false, nil, nil
+	})
+
+	if err := rec.deleteObject(ctx, obj, "default/ConfigMap/settings"); err != nil {
+		t.Fatalf("deleteObject() error = %v", err)
+	}
+	if !deleted {
+		t.Fatal("deleteObject() did not delete the resource")
+	}
+	if !reflect.DeepEqual(atDelete, []string{"example.com/keep"}) {
+		t.Errorf("finalizers at delete = %v, want only example.com/keep", atDelete)
+	}
+	if _, err := client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("Get() after delete error = %v, want not found", err)
+	}
+}
+
+func TestRemoveFinalizer_MissingResource(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+
+	if err := rec.RemoveFinalizer(context.Background(), annotationTestConfigMap(nil), "default/ConfigMap/settings"); err != nil {
+		t.Errorf("RemoveFinalizer() error = %v, want nil for a missing resource", err)
+	}
+}
+
+func TestApplyObject_ReleasesTerminatingResource(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	rec.useFinalizers = true
+	ctx := context.Background()
+	client := rec.dynamicClient.(*dynamicfake.FakeDynamicClient)
+
+	terminating := annotationTestConfigMap(nil)
+	terminating.SetFinalizers([]string{ManagedFinalizer, "example.com/keep"})
+	deletedAt := metav1.Now()
+	terminating.SetDeletionTimestamp(&deletedAt)
+	if err := client.Tracker().Add(terminating); err != nil {
+		t.Fatalf("failed to add resource: %v", err)
+	}
+	applied := false
+	client.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		applied = applied || action.(k8stesting.PatchAction).GetPatchType() == types.ApplyPatchType
+		return false, nil, nil
+	})
+
+	if err := rec.applyObject(ctx, annotationTestConfigMap(nil), "default/ConfigMap/settings"); err == nil {
+		t.Error("applyObject() error = nil, want an error so the resource is recreated later")
+	}
+	if applied {
+		t.Error("applyObject() applied to a terminating resource")
+	}
+	live, err := client.Tracker().Get(configMapsGVR, "default", "settings")
+	if err != nil {
+		t.Fatalf("failed to get resource: %v", err)
+	}
+	if got := live.(*unstructured.Unstructured).GetFinalizers(); !reflect.DeepEqual(got, []string{"example.com/keep"}) {
+		t.Errorf("finalizers after apply = %v, want only example.com/keep", got)
+	}
+}
@@ -1,9 +1,12 @@
 package reconciler
 
 import (
+	"errors"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 )
 
 func TestReconciler_ResolveGVK(t *testing.T) {
@@ -28,14 +31,18 @@ func TestReconciler_ResolveGVK(t *testing.T) {
 		t.Errorf("resolveGVK() Version = %v, want v1", gvk.Version)
 	}
 
-	// Test with unknown kind (should return generic)
-	gvk, err = impl.resolveGVK("UnknownKind")
-	if err != nil {
-		t.Fatalf("resolveGVK() error = %v", err)
+	// Test with unknown kind (not served by the API server)
+	if _, err = impl.resolveGVK("UnknownKind"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("resolveGVK() error = %v, want ErrNotFound", err)
 	}
 
-	if gvk.Kind != "UnknownKind" {
-		t.Errorf("resolveGVK() Kind = %v, want UnknownKind", gvk.Kind)
+	// parseKey falls back to a generic GVK for unknown kinds
+	obj, err := impl.parseKey("default/UnknownKind/test")
+	if err != nil {
+		t.Fatalf("parseKey() error = %v", err)
+	}
+	if obj.GetKind() != "UnknownKind" {
+		t.Errorf("parseKey() Kind = %v, want UnknownKind", obj.GetKind())
 	}
 }
 
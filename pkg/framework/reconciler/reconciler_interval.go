@@ -0,0 +1,81 @@
+package reconciler
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// ReconcileIntervalKey is the database key that persists the periodic reconciliation interval
+const ReconcileIntervalKey = "config/reconcile_interval"
+
+// DefaultReconcileInterval is the periodic reconciliation interval used until one is configured
+const DefaultReconcileInterval = time.Minute
+
+// MinReconcileInterval is the shortest interval SetReconcileInterval accepts
+const MinReconcileInterval = time.Second
+
+// WithSettingsDB persists runtime settings such as the reconcile interval in db so they survive a restart
+func WithSettingsDB(db *database.DB) Option {
+	return func(r *reconcilerImpl) {
+		r.settingsDB = db
+	}
+}
+
+// ReconcileInterval returns the current periodic reconciliation interval
+func (r *reconcilerImpl) ReconcileInterval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&r.reconcileInterval))
+}
+
+// SetReconcileInterval changes the periodic reconciliation interval, persisting it when a
+// settings database is configured. A running StartPeriodicReconciliation picks it up at once.
+func (r *reconcilerImpl) SetReconcileInterval(interval time.Duration) error {
+	if interval < MinReconcileInterval {
+		return fmt.Errorf("%w: reconcile interval must be at least %s", apperrors.ErrInvalid, MinReconcileInterval)
+	}
+
+	if r.settingsDB != nil {
+		if err := r.settingsDB.Set(ReconcileIntervalKey, []byte(interval.String())); err != nil {
+			return fmt.Errorf("%w: failed to persist reconcile interval: %w", apperrors.ErrStorage, err)
+		}
+	}
+
+	r.storeReconcileInterval(interval)
+	select {
+	case r.intervalChanged <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// loadReconcileInterval restores the interval persisted by an earlier process, if any
+func (r *reconcilerImpl) loadReconcileInterval() error {
+	if r.settingsDB == nil {
+		return nil
+	}
+
+	value, err := r.settingsDB.Get(ReconcileIntervalKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(string(value))
+	if err != nil || interval < MinReconcileInterval {
+		r.logger.Info("ignoring invalid persisted reconcile interval", "value", string(value))
+		return nil
+	}
+	r.storeReconcileInterval(interval)
+	r.intervalPersisted = true
+	return nil
+}
+
+func (r *reconcilerImpl) storeReconcileInterval(interval time.Duration) {
+	atomic.StoreInt64(&r.reconcileInterval, int64(interval))
+}
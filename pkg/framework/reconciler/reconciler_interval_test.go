@@ -0,0 +1,93 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+func newIntervalTestReconciler(t *testing.T, db *database.DB) *reconcilerImpl {
+	t.Helper()
+	logger := logr.Discard()
+	rec, err := NewReconciler(kubefake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		store.NewManifestStore(db, index.NewIndex(), logger), logger, events.NewStorage(db, logger), "test-app", WithSettingsDB(db))
+	if err != nil {
+		t.Fatalf("NewReconciler() error = %v", err)
+	}
+	return getReconcilerImpl(t, rec)
+}
+
+func TestReconciler_ReconcileIntervalPersistsAcrossRestart(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+
+	first := newIntervalTestReconciler(t, db)
+	if got := first.ReconcileInterval(); got != DefaultReconcileInterval {
+		t.Errorf("ReconcileInterval() = %v, want default %v", got, DefaultReconcileInterval)
+	}
+	if err := first.SetReconcileInterval(30 * time.Second); err != nil {
+		t.Fatalf("SetReconcileInterval() error = %v", err)
+	}
+
+	restored := newIntervalTestReconciler(t, db)
+	if got := restored.ReconcileInterval(); got != 30*time.Second {
+		t.Errorf("ReconcileInterval() after restart = %v, want 30s", got)
+	}
+	if value, err := db.Get(ReconcileIntervalKey); err != nil || string(value) != "30s" {
+		t.Errorf("persisted interval = %q, %v, want \"30s\"", value, err)
+	}
+}
+
+func TestReconciler_SetReconcileIntervalRejectsShortIntervals(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	rec := newIntervalTestReconciler(t, db)
+
+	if err := rec.SetReconcileInterval(time.Millisecond); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("SetReconcileInterval(1ms) error = %v, want ErrInvalid", err)
+	}
+	if got := rec.ReconcileInterval(); got != DefaultReconcileInterval {
+		t.Errorf("ReconcileInterval() = %v, want unchanged %v", got, DefaultReconcileInterval)
+	}
+}
+
+func TestReconciler_PersistedIntervalOverridesStartInterval(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	if err := newIntervalTestReconciler(t, db).SetReconcileInterval(45 * time.Second); err != nil {
+		t.Fatalf("SetReconcileInterval() error = %v", err)
+	}
+
+	rec := newIntervalTestReconciler(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		rec.StartPeriodicReconciliation(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	if got := rec.ReconcileInterval(); got != 45*time.Second {
+		t.Errorf("ReconcileInterval() = %v, want persisted 45s", got)
+	}
+}
@@ -0,0 +1,46 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// GetLiveObject fetches the cluster object for the resource key via the dynamic client.
+// It returns an ErrNotFound error when the object does not exist in the cluster.
+func (r *reconcilerImpl) GetLiveObject(ctx context.Context, key string) (*unstructured.Unstructured, error) {
+	obj, err := r.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := obj.GroupVersionKind()
+	gvr := schema.GroupVersionResource{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Resource: r.resolveResourceName(gvk),
+	}
+
+	var resourceInterface dynamic.ResourceInterface
+	if obj.GetNamespace() != "" {
+		resourceInterface = r.dynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+	} else {
+		resourceInterface = r.dynamicClient.Resource(gvr)
+	}
+
+	live, err := resourceInterface.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s not found in cluster", apperrors.ErrNotFound, key)
+		}
+		return nil, fmt.Errorf("%w: kubernetes get %s: %w", apperrors.ErrKubernetes, key, err)
+	}
+	return live, nil
+}
@@ -2,8 +2,44 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
 )
 
+// ManagedKeyPrefix prefixes the database keys that persist the managed resource keys
+const ManagedKeyPrefix = "managed/"
+
+// WithManagedKeysDB persists the managed keys in db so they survive a restart
+func WithManagedKeysDB(db *database.DB) Option {
+	return func(r *reconcilerImpl) {
+		r.managedKeysDB = db
+	}
+}
+
+// LoadManagedKeysFromDB replaces the in-memory managed keys with the ones persisted in the database
+func (r *reconcilerImpl) LoadManagedKeysFromDB(ctx context.Context) error {
+	if r.managedKeysDB == nil {
+		return nil
+	}
+
+	items, err := r.managedKeysDB.List(ManagedKeyPrefix)
+	if err != nil {
+		return err
+	}
+
+	r.managedKeys.Range(func(key, value interface{}) bool {
+		r.managedKeys.Delete(key)
+		return true
+	})
+	for dbKey := range items {
+		r.managedKeys.Store(strings.TrimPrefix(dbKey, ManagedKeyPrefix), true)
+	}
+	return nil
+}
+
 // isManaged checks if a key is managed
 func (r *reconcilerImpl) isManaged(key string) bool {
 	_, ok := r.managedKeys.Load(key)
@@ -13,11 +49,38 @@ func (r *reconcilerImpl) isManaged(key string) bool {
 // setManaged marks a key as managed
 func (r *reconcilerImpl) setManaged(key string) {
 	r.managedKeys.Store(key, true)
+	if r.managedKeysDB != nil {
+		if err := r.managedKeysDB.Set(ManagedKeyPrefix+key, nil); err != nil {
+			r.logger.Error(err, "failed to persist managed key", "key", key)
+		}
+	}
 }
 
 // removeManaged removes a key from managed keys
 func (r *reconcilerImpl) removeManaged(key string) {
 	r.managedKeys.Delete(key)
+	if r.managedKeysDB != nil {
+		if err := r.managedKeysDB.Delete(ManagedKeyPrefix + key); err != nil {
+			r.logger.Error(err, "failed to delete persisted managed key", "key", key)
+		}
+	}
+}
+
+// RenameKey replaces oldKey with newKey in the managed keys after the manifest stored under
+// oldKey was renamed, so the resource stays managed under its new key
+func (r *reconcilerImpl) RenameKey(oldKey, newKey string) error {
+	if !r.isManaged(oldKey) {
+		return nil
+	}
+
+	r.managedKeys.Delete(oldKey)
+	r.managedKeys.Store(newKey, true)
+	if r.managedKeysDB != nil {
+		if err := r.managedKeysDB.BatchWrite(map[string][]byte{ManagedKeyPrefix + newKey: nil}, []string{ManagedKeyPrefix + oldKey}); err != nil {
+			return fmt.Errorf("failed to persist renamed managed key: %w", err)
+		}
+	}
+	return nil
 }
 
 // getAllManagedKeys returns all managed keys as a map
@@ -32,23 +95,55 @@ func (r *reconcilerImpl) getAllManagedKeys(ctx context.Context) map[string]bool
 	return result
 }
 
+// ManagedKeys returns the sorted keys of all managed resources
+func (r *reconcilerImpl) ManagedKeys(ctx context.Context) []string {
+	managed := r.getAllManagedKeys(ctx)
+	keys := make([]string, 0, len(managed))
+	for key := range managed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // setAllManagedKeys replaces all managed keys with the given set
 func (r *reconcilerImpl) setAllManagedKeys(ctx context.Context, keys map[string]bool) {
+	var removed []string
 	r.managedKeys.Range(func(key, value interface{}) bool {
+		if strKey, ok := key.(string); ok && !keys[strKey] {
+			removed = append(removed, ManagedKeyPrefix+strKey)
+		}
 		r.managedKeys.Delete(key)
 		return true
 	})
 
+	items := make(map[string][]byte, len(keys))
 	for key := range keys {
 		r.managedKeys.Store(key, true)
+		items[ManagedKeyPrefix+key] = nil
+	}
+
+	if r.managedKeysDB != nil {
+		if err := r.managedKeysDB.BatchWrite(items, removed); err != nil {
+			r.logger.Error(err, "failed to persist managed keys")
+		}
 	}
 }
 
 // clearManagedKeys removes all managed keys
 func (r *reconcilerImpl) clearManagedKeys(ctx context.Context) {
+	var removed []string
 	r.managedKeys.Range(func(key, value interface{}) bool {
+		if strKey, ok := key.(string); ok {
+			removed = append(removed, ManagedKeyPrefix+strKey)
+		}
 		r.managedKeys.Delete(key)
 		return true
 	})
-}
 
+	if r.managedKeysDB != nil {
+		if err := r.managedKeysDB.BatchDelete(removed); err != nil {
+			r.logger.Error(err, "failed to delete persisted managed keys")
+		}
+	}
+}
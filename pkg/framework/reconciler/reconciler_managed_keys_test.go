@@ -2,7 +2,18 @@ package reconciler
 
 import (
 	"context"
+	"reflect"
 	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
 )
 
 func TestReconciler_ManagedKeys(t *testing.T) {
@@ -59,3 +70,94 @@ func TestReconciler_ManagedKeys(t *testing.T) {
 		t.Errorf("clearManagedKeys() did not clear keys, got %d keys", len(keys))
 	}
 }
+
+func TestReconciler_ManagedKeysPersistAcrossRestart(t *testing.T) {
+	logger := logr.Discard()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	newReconciler := func() *reconcilerImpl {
+		rec, err := NewReconciler(kubefake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+			store.NewManifestStore(db, index.NewIndex(), logger), logger, events.NewStorage(db, logger), "test-app", WithManagedKeysDB(db))
+		if err != nil {
+			t.Fatalf("NewReconciler() error = %v", err)
+		}
+		return getReconcilerImpl(t, rec)
+	}
+	ctx := context.Background()
+
+	first := newReconciler()
+	first.setManaged("default/ConfigMap/a")
+	first.setManaged("default/ConfigMap/b")
+	first.setManaged("default/ConfigMap/c")
+	first.removeManaged("default/ConfigMap/b")
+
+	restored := newReconciler()
+	want := []string{"default/ConfigMap/a", "default/ConfigMap/c"}
+	if got := restored.ManagedKeys(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("ManagedKeys() after restart = %v, want %v", got, want)
+	}
+
+	restored.setAllManagedKeys(ctx, map[string]bool{"default/ConfigMap/c": true, "default/ConfigMap/d": true})
+	want = []string{"default/ConfigMap/c", "default/ConfigMap/d"}
+	if got := newReconciler().ManagedKeys(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("ManagedKeys() after setAllManagedKeys and restart = %v, want %v", got, want)
+	}
+
+	restored.clearManagedKeys(ctx)
+	if got := newReconciler().ManagedKeys(ctx); len(got) != 0 {
+		t.Errorf("ManagedKeys() after clearManagedKeys and restart = %v, want none", got)
+	}
+}
+
+func TestReconciler_ManagedKeysWithoutDB(t *testing.T) {
+	impl := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	impl.setManaged("default/ConfigMap/a")
+
+	if err := impl.LoadManagedKeysFromDB(context.Background()); err != nil {
+		t.Fatalf("LoadManagedKeysFromDB() error = %v", err)
+	}
+	if !impl.isManaged("default/ConfigMap/a") {
+		t.Error("LoadManagedKeysFromDB() without a DB dropped the in-memory keys")
+	}
+}
+
+func TestReconciler_RenameKey(t *testing.T) {
+	logger := logr.Discard()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	newReconciler := func() *reconcilerImpl {
+		rec, err := NewReconciler(kubefake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+			store.NewManifestStore(db, index.NewIndex(), logger), logger, events.NewStorage(db, logger), "test-app", WithManagedKeysDB(db))
+		if err != nil {
+			t.Fatalf("NewReconciler() error = %v", err)
+		}
+		return getReconcilerImpl(t, rec)
+	}
+	ctx := context.Background()
+
+	rec := newReconciler()
+	rec.setManaged("default/ConfigMap/a")
+
+	// Renaming a key that is not managed leaves the managed keys alone
+	if err := rec.RenameKey("default/ConfigMap/other", "default/ConfigMap/b"); err != nil {
+		t.Fatalf("RenameKey() error = %v", err)
+	}
+	if got, want := rec.ManagedKeys(ctx), []string{"default/ConfigMap/a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ManagedKeys() = %v, want %v", got, want)
+	}
+
+	if err := rec.RenameKey("default/ConfigMap/a", "staging/ConfigMap/a"); err != nil {
+		t.Fatalf("RenameKey() error = %v", err)
+	}
+	want := []string{"staging/ConfigMap/a"}
+	if got := rec.ManagedKeys(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("ManagedKeys() after RenameKey = %v, want %v", got, want)
+	}
+	if got := newReconciler().ManagedKeys(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("ManagedKeys() after RenameKey and restart = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// ManagedStatusTimeout bounds how long GetManagedResourceStatus waits for the live object of a single key
+const ManagedStatusTimeout = 2 * time.Second
+
+// ManagedStatusWorkers is the number of live objects GetManagedResourceStatus fetches concurrently
+const ManagedStatusWorkers = 10
+
+// Phases reported in ManagedResourceStatus.Phase
+const (
+	PhaseRunning = "Running"
+	PhasePending = "Pending"
+	PhaseFailed  = "Failed"
+)
+
+// ManagedResourceStatus is the live cluster status of one managed resource
+type ManagedResourceStatus struct {
+	Exists bool `json:"exists"`
+	// Phase is Running, Pending or Failed; it is empty for resources that do not exist
+	Phase         string             `json:"phase,omitempty"`
+	ReadyReplicas *int32             `json:"readyReplicas,omitempty"`
+	LastUpdated   *metav1.Time       `json:"lastUpdated,omitempty"`
+	Conditions    []metav1.Condition `json:"conditions,omitempty"`
+	// Error is set when the live object could not be fetched
+	Error string `json:"error,omitempty"`
+}
+
+// GetManagedResourceStatus fetches the live object of every managed key with a pool of
+// ManagedStatusWorkers workers and summarizes its status. Each fetch is bounded by
+// ManagedStatusTimeout; a key whose fetch fails, or that is not fetched before ctx is done, is
+// reported with Error set instead of failing the whole call.
+func (r *reconcilerImpl) GetManagedResourceStatus(ctx context.Context) (map[string]ManagedResourceStatus, error) {
+	keys := r.ManagedKeys(ctx)
+	queue := make(chan string, len(keys))
+	for _, key := range keys {
+		queue <- key
+	}
+	close(queue)
+
+	statuses := make(map[string]ManagedResourceStatus, len(keys))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < ManagedStatusWorkers && i < len(keys); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				status := r.managedResourceStatusOf(ctx, key)
+				mu.Lock()
+				statuses[key] = status
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return statuses, nil
+}
+
+// managedResourceStatusOf fetches the live object of key and summarizes its status
+func (r *reconcilerImpl) managedResourceStatusOf(ctx context.Context, key string) ManagedResourceStatus {
+	if err := ctx.Err(); err != nil {
+		return ManagedResourceStatus{Error: err.Error()}
+	}
+
+	keyCtx, cancel := context.WithTimeout(ctx, ManagedStatusTimeout)
+	defer cancel()
+	live, err := r.GetLiveObject(keyCtx, key)
+	switch {
+	case err == nil:
+		return managedResourceStatus(live)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return ManagedResourceStatus{}
+	default:
+		r.logger.V(1).Info("failed to get live object", "key", key, "error", err)
+		return ManagedResourceStatus{Error: err.Error()}
+	}
+}
+
+// managedResourceStatus summarizes the .status of a live object. The phase is mapped from
+// .status.phase when the object has one; otherwise it is Failed when a ReplicaFailure
+// condition is True or progress stalled, Running when a Ready or Available condition is
+// True or .status.readyReplicas reaches .spec.replicas, and Pending before that.
+func managedResourceStatus(obj *unstructured.Unstructured) ManagedResourceStatus {
+	status := ManagedResourceStatus{Exists: true}
+
+	readyReplicas, hasReadyReplicas, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	if hasReadyReplicas {
+		ready := int32(readyReplicas)
+		status.ReadyReplicas = &ready
+	}
+
+	failed := false
+	readyCondition := ""
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, raw := range conditions {
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := metav1.Condition{
+			Type:    stringField(fields, "type"),
+			Status:  metav1.ConditionStatus(stringField(fields, "status")),
+			Reason:  stringField(fields, "reason"),
+			Message: stringField(fields, "message"),
+		}
+		condition.ObservedGeneration, _, _ = unstructured.NestedInt64(fields, "observedGeneration")
+		for _, field := range []string{"lastTransitionTime", "lastUpdateTime"} {
+			updated, err := time.Parse(time.RFC3339, stringField(fields, field))
+			if err != nil {
+				continue
+			}
+			if field == "lastTransitionTime" {
+				condition.LastTransitionTime = metav1.NewTime(updated)
+			}
+			if status.LastUpdated == nil || updated.After(status.LastUpdated.Time) {
+				lastUpdated := metav1.NewTime(updated)
+				status.LastUpdated = &lastUpdated
+			}
+		}
+		status.Conditions = append(status.Conditions, condition)
+
+		switch {
+		case condition.Type == "ReplicaFailure" && condition.Status == metav1.ConditionTrue,
+			condition.Type == "Progressing" && condition.Reason == "ProgressDeadlineExceeded":
+			failed = true
+		case (condition.Type == "Ready" || condition.Type == "Available") && readyCondition != string(metav1.ConditionTrue):
+			readyCondition = string(condition.Status)
+		}
+	}
+
+	if phase, ok, _ := unstructured.NestedString(obj.Object, "status", "phase"); ok && phase != "" {
+		status.Phase = mapPhase(phase)
+		return status
+	}
+
+	switch {
+	case failed:
+		status.Phase = PhaseFailed
+	case readyCondition != "":
+		status.Phase = phaseFor(readyCondition == string(metav1.ConditionTrue))
+	default:
+		replicas, hasReplicas, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !hasReplicas {
+			replicas = 1
+		}
+		if hasReadyReplicas || hasReplicas {
+			status.Phase = phaseFor(readyReplicas >= replicas)
+		} else {
+			status.Phase = PhaseRunning
+		}
+	}
+	return status
+}
+
+// mapPhase maps the .status.phase of a built-in kind, such as a Pod, Namespace or
+// PersistentVolumeClaim, to Running, Pending or Failed
+func mapPhase(phase string) string {
+	switch phase {
+	case "Running", "Succeeded", "Active", "Bound", "Available":
+		return PhaseRunning
+	case "Failed", "Lost":
+		return PhaseFailed
+	default:
+		// Pending, Terminating, Released, Unknown and phases of custom resources
+		return PhasePending
+	}
+}
+
+func phaseFor(ready bool) string {
+	if ready {
+		return PhaseRunning
+	}
+	return PhasePending
+}
+
+func stringField(m map[string]interface{}, field string) string {
+	value, _ := m[field].(string)
+	return value
+}
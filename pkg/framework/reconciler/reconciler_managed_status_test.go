@@ -0,0 +1,259 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestReconciler_GetManagedResourceStatus(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl := getReconcilerImpl(t, rec)
+	ctx := context.Background()
+
+	dynamicClient := impl.dynamicClient.(*dynamicfake.FakeDynamicClient)
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec":       map[string]interface{}{"replicas": int64(3)},
+		"status": map[string]interface{}{
+			"readyReplicas": int64(1),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "True", "reason": "ReplicaSetUpdated", "lastTransitionTime": "2024-05-01T10:00:00Z"},
+				map[string]interface{}{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable", "lastTransitionTime": "2024-05-01T10:05:00Z"},
+			},
+		},
+	}}
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "default"},
+	}}
+	deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	configMapGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if _, err := dynamicClient.Resource(deploymentGVR).Namespace("default").Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create Deployment: %v", err)
+	}
+	if _, err := dynamicClient.Resource(configMapGVR).Namespace("default").Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create ConfigMap: %v", err)
+	}
+
+	impl.setManaged("default/Deployment/web")
+	impl.setManaged("default/ConfigMap/settings")
+	impl.setManaged("default/Service/missing")
+
+	statuses, err := rec.GetManagedResourceStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetManagedResourceStatus() error = %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("GetManagedResourceStatus() returned %d statuses, want 3: %v", len(statuses), statuses)
+	}
+
+	web := statuses["default/Deployment/web"]
+	if !web.Exists {
+		t.Fatal("Deployment exists = false, want true")
+	}
+	if web.Phase != PhasePending {
+		t.Errorf("Deployment phase = %q, want %q", web.Phase, PhasePending)
+	}
+	if web.ReadyReplicas == nil || *web.ReadyReplicas != 1 {
+		t.Errorf("Deployment readyReplicas = %v, want 1", web.ReadyReplicas)
+	}
+	if len(web.Conditions) != 2 || web.Conditions[1].Type != "Available" || web.Conditions[1].Status != metav1.ConditionFalse {
+		t.Errorf("Deployment conditions = %+v, want Progressing and Available=False", web.Conditions)
+	}
+	if web.LastUpdated == nil || web.LastUpdated.UTC().Format("15:04") != "10:05" {
+		t.Errorf("Deployment lastUpdated = %v, want the latest condition transition", web.LastUpdated)
+	}
+
+	settings := statuses["default/ConfigMap/settings"]
+	if !settings.Exists || settings.Phase != PhaseRunning {
+		t.Errorf("ConfigMap status = %+v, want exists with phase %q", settings, PhaseRunning)
+	}
+
+	if missing := statuses["default/Service/missing"]; missing.Exists || missing.Phase != "" || missing.Error != "" {
+		t.Errorf("missing Service status = %+v, want exists=false", missing)
+	}
+}
+
+func TestReconciler_GetManagedResourceStatusCanceled(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	getReconcilerImpl(t, rec).setManaged("default/ConfigMap/settings")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	statuses, err := rec.GetManagedResourceStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetManagedResourceStatus() error = %v", err)
+	}
+	if status := statuses["default/ConfigMap/settings"]; status.Error != context.Canceled.Error() {
+		t.Errorf("status of a key not fetched before cancellation = %+v, want Error %q", status, context.Canceled)
+	}
+}
+
+// slowGetClient wraps a dynamic client so namespaced Gets take delay and records how many overlap
+type slowGetClient struct {
+	dynamic.Interface
+	delay time.Duration
+
+	mu     sync.Mutex
+	active int
+	peak   int
+}
+
+func (c *slowGetClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &slowGetResource{NamespaceableResourceInterface: c.Interface.Resource(gvr), client: c}
+}
+
+type slowGetResource struct {
+	dynamic.NamespaceableResourceInterface
+	client *slowGetClient
+}
+
+func (r *slowGetResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &slowGetNamespacedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(namespace), client: r.client}
+}
+
+type slowGetNamespacedResource struct {
+	dynamic.ResourceInterface
+	client *slowGetClient
+}
+
+func (r *slowGetNamespacedResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	r.client.mu.Lock()
+	r.client.active++
+	if r.client.active > r.client.peak {
+		r.client.peak = r.client.active
+	}
+	r.client.mu.Unlock()
+
+	select {
+	case <-time.After(r.client.delay):
+	case <-ctx.Done():
+	}
+
+	r.client.mu.Lock()
+	r.client.active--
+	r.client.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.Get(ctx, name, options, subresources...)
+}
+
+func TestReconciler_GetManagedResourceStatusConcurrent(t *testing.T) {
+	impl := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	client := &slowGetClient{Interface: impl.dynamicClient, delay: 50 * time.Millisecond}
+	impl.dynamicClient = client
+	count := 3 * ManagedStatusWorkers
+	for i := 0; i < count; i++ {
+		impl.setManaged(fmt.Sprintf("default/ConfigMap/cm-%02d", i))
+	}
+
+	statuses, err := impl.GetManagedResourceStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetManagedResourceStatus() error = %v", err)
+	}
+	if len(statuses) != count {
+		t.Errorf("GetManagedResourceStatus() returned %d statuses, want %d", len(statuses), count)
+	}
+	if client.peak < 2 || client.peak > ManagedStatusWorkers {
+		t.Errorf("peak concurrent fetches = %d, want between 2 and %d", client.peak, ManagedStatusWorkers)
+	}
+}
+
+func TestReconciler_GetManagedResourceStatusPartial(t *testing.T) {
+	impl := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	impl.dynamicClient = &slowGetClient{Interface: impl.dynamicClient, delay: time.Second}
+	impl.setManaged("default/ConfigMap/slow")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	statuses, err := impl.GetManagedResourceStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetManagedResourceStatus() error = %v", err)
+	}
+	if status := statuses["default/ConfigMap/slow"]; status.Error == "" {
+		t.Errorf("status of a key whose fetch outlived ctx = %+v, want Error set", status)
+	}
+}
+
+func TestManagedResourceStatus_Phase(t *testing.T) {
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "succeeded pod",
+			status: map[string]interface{}{"phase": "Succeeded"},
+			want:   PhaseRunning,
+		},
+		{
+			name:   "active namespace",
+			status: map[string]interface{}{"phase": "Active"},
+			want:   PhaseRunning,
+		},
+		{
+			name:   "bound claim",
+			status: map[string]interface{}{"phase": "Bound"},
+			want:   PhaseRunning,
+		},
+		{
+			name:   "lost claim",
+			status: map[string]interface{}{"phase": "Lost"},
+			want:   PhaseFailed,
+		},
+		{
+			name:   "terminating namespace",
+			status: map[string]interface{}{"phase": "Terminating"},
+			want:   PhasePending,
+		},
+		{
+			name: "ready condition",
+			status: map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			}},
+			want: PhaseRunning,
+		},
+		{
+			name: "replica failure",
+			status: map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "ReplicaFailure", "status": "True", "reason": "FailedCreate"},
+			}},
+			want: PhaseFailed,
+		},
+		{
+			name: "progress deadline exceeded",
+			status: map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"},
+			}},
+			want: PhaseFailed,
+		},
+		{
+			name:   "all replicas ready",
+			status: map[string]interface{}{"readyReplicas": int64(1)},
+			want:   PhaseRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": tt.status}}
+			if got := managedResourceStatus(obj).Phase; got != tt.want {
+				t.Errorf("managedResourceStatus() phase = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
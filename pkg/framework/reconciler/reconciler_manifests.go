@@ -8,22 +8,27 @@ import (
 	"github.com/garunski/conductor-framework/pkg/framework/events"
 )
 
-// DeployManifests deploys the provided manifests
-func (r *reconcilerImpl) DeployManifests(ctx context.Context, manifests map[string][]byte) error {
+// DeployManifests deploys the provided manifests and returns the outcome of the reconciliation
+func (r *reconcilerImpl) DeployManifests(ctx context.Context, manifests map[string][]byte) (ReconciliationResult, error) {
 	r.logger.Info("Deploying selected manifests", "count", len(manifests))
 
-	events.StoreEventSafe(r.eventStore, r.logger, events.Info("", "reconcile", "Reconciliation started"))
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Info("", "reconcile", "Reconciliation started"))
 
 	previousKeys := r.getAllManagedKeys(ctx)
 
 	result, err := r.reconcile(ctx, manifests, previousKeys)
 	if err != nil {
 		r.logger.Error(err, "reconciliation failed")
-		events.StoreEventSafe(r.eventStore, r.logger, events.Error("", "reconcile", "Reconciliation failed", err))
-		return err
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error("", "reconcile", "Reconciliation failed", err))
+		return ReconciliationResult{}, err
 	}
 
-	// Update managed keys - add new ones but don't remove ones not in the filtered set
+	// Update managed keys - add new ones and drop the orphans reconcile deleted
+	for key := range previousKeys {
+		if !result.ManagedKeys[key] {
+			r.removeManaged(key)
+		}
+	}
 	for key := range result.ManagedKeys {
 		r.setManaged(key)
 	}
@@ -32,22 +37,24 @@ func (r *reconcilerImpl) DeployManifests(ctx context.Context, manifests map[stri
 	event.Details["total"] = len(manifests)
 	event.Details["applied"] = result.AppliedCount
 	event.Details["failed"] = result.FailedCount
+	event.Details["timedOut"] = result.TimedOutCount
 	event.Details["deleted"] = result.DeletedCount
 	event.Details["managed"] = len(result.ManagedKeys)
-	events.StoreEventSafe(r.eventStore, r.logger, event)
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, event)
 
 	r.logger.Info("Reconciliation complete",
 		"total", len(manifests),
 		"applied", result.AppliedCount,
 		"failed", result.FailedCount,
+		"timedOut", result.TimedOutCount,
 		"deleted", result.DeletedCount,
 		"managed", len(result.ManagedKeys))
 
-	return nil
+	return result, nil
 }
 
-// UpdateManifests updates the provided manifests
-func (r *reconcilerImpl) UpdateManifests(ctx context.Context, manifests map[string][]byte) error {
+// UpdateManifests updates the provided manifests and returns the outcome of the reconciliation
+func (r *reconcilerImpl) UpdateManifests(ctx context.Context, manifests map[string][]byte) (ReconciliationResult, error) {
 	r.logger.Info("Updating selected manifests", "count", len(manifests))
 	return r.DeployManifests(ctx, manifests)
 }
@@ -60,6 +67,7 @@ func (r *reconcilerImpl) DeleteManifests(ctx context.Context, manifests map[stri
 	for key := range manifests {
 		keys = append(keys, key)
 	}
+	sortForDelete(keys)
 
 	deletedCount := 0
 	failedCount := 0
@@ -67,7 +75,7 @@ func (r *reconcilerImpl) DeleteManifests(ctx context.Context, manifests map[stri
 		obj, err := r.parseKey(key)
 		if err != nil {
 			r.logger.Error(err, "failed to parse key for deletion", "key", key)
-			events.StoreEventSafe(r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
 			failedCount++
 			continue
 		}
@@ -0,0 +1,12 @@
+package reconciler
+
+import (
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
+)
+
+// WithMetrics records apply, delete and reconcile metrics in m
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(r *reconcilerImpl) {
+		r.metrics = m
+	}
+}
@@ -0,0 +1,105 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+func TestReconciler_MetricsAfterReconcileCycle(t *testing.T) {
+	logger := logr.Discard()
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme)
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		if patchAction.GetName() == "broken" {
+			return true, nil, errors.New("apply rejected")
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetName(patchAction.GetName())
+		return true, obj, nil
+	})
+	dynamicClient.PrependReactor("delete", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("NewTestDB() error = %v", err)
+	}
+	manifestStore := store.NewManifestStore(db, index.NewIndex(), logger)
+	for _, name := range []string{"cm1", "cm2", "broken"} {
+		if err := manifestStore.Create("default/ConfigMap/"+name, []byte(testConfigMapYAML(name))); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	registry := prometheus.NewRegistry()
+	rec, err := NewReconciler(kubefake.NewSimpleClientset(), dynamicClient, manifestStore, logger, events.NewStorage(db, logger), "test-app", WithMetrics(metrics.New(registry)))
+	if err != nil {
+		t.Fatalf("NewReconciler() error = %v", err)
+	}
+	impl := getReconcilerImpl(t, rec)
+	impl.setManaged("default/ConfigMap/orphan")
+
+	impl.reconcileAll(context.Background())
+
+	want := map[string]float64{
+		"conductor_apply_total/success":        2,
+		"conductor_apply_total/failure":        1,
+		"conductor_delete_total/success":       1,
+		"conductor_reconcile_duration_seconds": 1,
+		"conductor_managed_resources":          3,
+	}
+	got := gatherMetrics(t, registry)
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+}
+
+// gatherMetrics returns every sample in registry keyed by metric name and label values;
+// histograms report their sample count
+func gatherMetrics(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	samples := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				samples[name] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				samples[name] = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				samples[name] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return samples
+}
@@ -0,0 +1,73 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// ManagedByLabel marks namespaces the reconciler created on demand
+const ManagedByLabel = "conductor.io/managed-by"
+
+// autoNamespaceKeyPrefix prefixes the managed keys of created namespaces. The empty
+// namespace part makes parseKey build a cluster-scoped object.
+const autoNamespaceKeyPrefix = "/Namespace/"
+
+// WithAutoCreateNamespace makes applies that fail because their namespace does not exist
+// create the namespace and retry. Created namespaces are deleted last by DeleteAll.
+func WithAutoCreateNamespace(enabled bool) Option {
+	return func(r *reconcilerImpl) {
+		r.autoCreateNamespace = enabled
+	}
+}
+
+func autoNamespaceKey(namespace string) string {
+	return autoNamespaceKeyPrefix + namespace
+}
+
+func isAutoNamespaceKey(key string) bool {
+	return strings.HasPrefix(key, autoNamespaceKeyPrefix)
+}
+
+// isNamespaceNotFound reports whether err says that namespace does not exist
+func isNamespaceNotFound(err error, namespace string) bool {
+	var status k8serrors.APIStatus
+	if !k8serrors.IsNotFound(err) || !errors.As(err, &status) {
+		return false
+	}
+	details := status.Status().Details
+	return details != nil && details.Kind == "namespaces" && details.Name == namespace
+}
+
+// ensureNamespace creates namespace with the managed-by label and tracks it as managed
+func (r *reconcilerImpl) ensureNamespace(ctx context.Context, namespace string) error {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{ManagedByLabel: r.appName},
+		},
+	}
+
+	key := autoNamespaceKey(namespace)
+	if _, err := r.clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			err = fmt.Errorf("%w: kubernetes create namespace %s: %w", apperrors.ErrKubernetes, namespace, err)
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "apply", "Failed to create namespace", err))
+			return err
+		}
+		return nil
+	}
+
+	r.setManaged(key)
+	r.logger.Info("Created missing namespace", "namespace", namespace)
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Success(key, "apply", "Created missing namespace"))
+	return nil
+}
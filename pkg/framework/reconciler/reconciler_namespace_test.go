@@ -0,0 +1,168 @@
+package reconciler
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// setupNamespaceTestReconciler returns a reconciler whose applies fail until their namespace
+// exists in the clientset, and a function listing the applied and deleted objects in order
+func setupNamespaceTestReconciler(t *testing.T) (*reconcilerImpl, *kubefake.Clientset, func() []string) {
+	t.Helper()
+	logger := logr.Discard()
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme)
+	clientset := kubefake.NewSimpleClientset()
+
+	var mu sync.Mutex
+	var operations []string
+	record := func(op string) {
+		mu.Lock()
+		defer mu.Unlock()
+		operations = append(operations, op)
+	}
+
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		namespace := patchAction.GetNamespace()
+		if _, err := clientset.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{}); err != nil {
+			return true, nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, namespace)
+		}
+		record("apply " + namespace + "/" + patchAction.GetName())
+		obj := &unstructured.Unstructured{}
+		obj.SetName(patchAction.GetName())
+		return true, obj, nil
+	})
+	dynamicClient.PrependReactor("delete", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deleteAction := action.(k8stesting.DeleteAction)
+		record("delete " + deleteAction.GetResource().Resource + "/" + deleteAction.GetName())
+		return true, nil, nil
+	})
+
+	testDB, err := database.NewDB(filepath.Join(t.TempDir(), "test-namespace-db"), logger)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	t.Cleanup(func() { testDB.Close() })
+
+	manifestStore := store.NewManifestStore(testDB, index.NewIndex(), logger)
+	rec, err := NewReconciler(clientset, dynamicClient, manifestStore, logger, events.NewStorage(testDB, logger), "test-app", WithAutoCreateNamespace(true))
+	if err != nil {
+		t.Fatalf("failed to create reconciler: %v", err)
+	}
+
+	return getReconcilerImpl(t, rec), clientset, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, operations...)
+	}
+}
+
+func TestReconciler_AutoCreateNamespace(t *testing.T) {
+	impl, clientset, operations := setupNamespaceTestReconciler(t)
+	ctx := context.Background()
+
+	manifests := map[string][]byte{
+		"team-a/ConfigMap/settings": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: team-a\n"),
+	}
+	result, err := impl.DeployManifests(ctx, manifests)
+	if err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	if result.AppliedCount != 1 {
+		t.Errorf("AppliedCount = %d, want 1", result.AppliedCount)
+	}
+
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, "team-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("namespace was not created: %v", err)
+	}
+	if ns.Labels[ManagedByLabel] != "test-app" {
+		t.Errorf("namespace label %s = %q, want test-app", ManagedByLabel, ns.Labels[ManagedByLabel])
+	}
+	if got := operations(); len(got) != 1 || got[0] != "apply team-a/settings" {
+		t.Errorf("operations = %v, want the ConfigMap applied once after the namespace exists", got)
+	}
+	if !impl.isManaged("/Namespace/team-a") {
+		t.Error("created namespace is not a managed key")
+	}
+
+	// Redeploying keeps the namespace managed instead of deleting it as an orphan
+	if _, err := impl.DeployManifests(ctx, manifests); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	if !impl.isManaged("/Namespace/team-a") {
+		t.Error("created namespace is no longer managed after redeploy")
+	}
+}
+
+func TestReconciler_AutoCreateNamespaceDisabled(t *testing.T) {
+	impl, clientset, _ := setupNamespaceTestReconciler(t)
+	impl.autoCreateNamespace = false
+
+	manifests := map[string][]byte{
+		"team-a/ConfigMap/settings": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: team-a\n"),
+	}
+	result, err := impl.DeployManifests(context.Background(), manifests)
+	if err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	if result.FailedCount != 1 {
+		t.Errorf("FailedCount = %d, want 1", result.FailedCount)
+	}
+	if _, err := clientset.CoreV1().Namespaces().Get(context.Background(), "team-a", metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("namespace created with auto-creation disabled: %v", err)
+	}
+}
+
+func TestReconciler_DeleteAllDeletesCreatedNamespaceLast(t *testing.T) {
+	impl, _, operations := setupNamespaceTestReconciler(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"one", "two"} {
+		manifest := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: team-a\n")
+		if err := impl.store.Create("team-a/ConfigMap/"+name, manifest); err != nil {
+			t.Fatalf("failed to create manifest: %v", err)
+		}
+	}
+	if _, err := impl.DeployManifests(ctx, impl.store.List()); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+
+	if err := impl.DeleteAll(ctx); err != nil {
+		t.Fatalf("DeleteAll() error = %v", err)
+	}
+
+	var deletes []string
+	for _, op := range operations() {
+		if strings.HasPrefix(op, "delete ") {
+			deletes = append(deletes, op)
+		}
+	}
+	if len(deletes) != 3 {
+		t.Fatalf("deletes = %v, want 3", deletes)
+	}
+	if deletes[2] != "delete namespaces/team-a" {
+		t.Errorf("deletes = %v, want the namespace deleted last", deletes)
+	}
+}
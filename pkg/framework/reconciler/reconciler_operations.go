@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -21,67 +22,137 @@ func (r *reconcilerImpl) ReconcileKey(ctx context.Context, key string) error {
 			obj, err := r.parseKey(key)
 			if err != nil {
 				err = fmt.Errorf("%w: reconciliation failed for resource %s: failed to parse key for deletion: %w", apperrors.ErrReconciliation, key, err)
-				events.StoreEventSafe(r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
+				events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
 				return err
 			}
 
 			if err := r.deleteObject(ctx, obj, key); err != nil && !k8serrors.IsNotFound(err) {
 				err = fmt.Errorf("%w: reconciliation failed for resource %s: failed to delete resource: %w", apperrors.ErrReconciliation, key, err)
-				events.StoreEventSafe(r.eventStore, r.logger, events.Error(key, "delete", "Failed to delete resource", err))
+				events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "delete", "Failed to delete resource", err))
 				return err
 			}
 
 			r.removeManaged(key)
+			r.resetBackoff(key)
 
 			r.logger.Info("Deleted resource", "key", key)
-			events.StoreEventSafe(r.eventStore, r.logger, events.Success(key, "delete", "Deleted resource"))
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Success(key, "delete", "Deleted resource"))
 		}
 		return nil
 	}
 
-	obj, err := r.parseYAML(yamlData, key)
+	obj, err := r.parseYAML(ctx, yamlData, key)
 	if err != nil {
+		r.recordFailure(key)
 		return fmt.Errorf("%w: reconciliation failed for manifest %s: failed to parse YAML: %w", apperrors.ErrReconciliation, key, err)
 	}
 
 	if err := r.applyObject(ctx, obj, key); err != nil {
+		r.recordFailure(key)
 		return err
 	}
 
 	r.setManaged(key)
+	r.resetBackoff(key)
 
 	return nil
 }
 
+// ReconcileSelected reconciles only the given keys. Selected keys missing from the store
+// are deleted if they were managed; resources outside the selection are left untouched.
+func (r *reconcilerImpl) ReconcileSelected(ctx context.Context, keys []string) error {
+	manifests := make(map[string][]byte, len(keys))
+	previousKeys := make(map[string]bool)
+	for _, key := range keys {
+		if yamlData, ok := r.store.Get(key); ok {
+			manifests[key] = yamlData
+		}
+		if r.isManaged(key) {
+			previousKeys[key] = true
+		}
+	}
+
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Info("", "reconcile", "Selective reconciliation started"))
+
+	result, err := r.reconcile(ctx, manifests, previousKeys)
+	if err != nil {
+		err = fmt.Errorf("%w: selective reconciliation failed: %w", apperrors.ErrReconciliation, err)
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error("", "reconcile", "Selective reconciliation failed", err))
+		return err
+	}
+
+	// Update managed keys for the selection only
+	for key := range previousKeys {
+		if !result.ManagedKeys[key] {
+			r.removeManaged(key)
+		}
+	}
+	for key := range result.ManagedKeys {
+		r.setManaged(key)
+	}
+
+	event := events.Info("", "reconcile", "Selective reconciliation complete")
+	event.Details["selected"] = len(keys)
+	event.Details["applied"] = result.AppliedCount
+	event.Details["failed"] = result.FailedCount
+	event.Details["deleted"] = result.DeletedCount
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, event)
+
+	r.logger.Info("Selective reconciliation complete",
+		"selected", len(keys),
+		"applied", result.AppliedCount,
+		"failed", result.FailedCount,
+		"deleted", result.DeletedCount)
+
+	if result.FailedCount > 0 {
+		return fmt.Errorf("%w: %d of %d selected manifests failed to apply", apperrors.ErrReconciliation, result.FailedCount, len(manifests))
+	}
+	return nil
+}
+
 func (r *reconcilerImpl) DeployAll(ctx context.Context) error {
 	r.logger.Info("Deploying all manifests")
 	r.reconcileAll(ctx)
 	return nil
 }
 
-func (r *reconcilerImpl) DeleteAll(ctx context.Context) error {
-	r.logger.Info("Deleting all managed resources")
-
+// DeleteAllKeys returns the keys DeleteAll deletes, in the order it deletes them: the stored
+// manifests and the managed resources, followed by the namespaces created on demand
+func (r *reconcilerImpl) DeleteAllKeys(ctx context.Context) []string {
 	manifests := r.store.List()
 	keys := make([]string, 0, len(manifests))
 	for key := range manifests {
 		keys = append(keys, key)
 	}
 
+	// Namespaces created on demand are deleted after everything that may live in them
+	var namespaceKeys []string
 	managedKeys := r.getAllManagedKeys(ctx)
 	for key := range managedKeys {
+		if isAutoNamespaceKey(key) {
+			namespaceKeys = append(namespaceKeys, key)
+			continue
+		}
 		if _, exists := manifests[key]; !exists {
 			keys = append(keys, key)
 		}
 	}
+	sortForDelete(keys)
+	sort.Strings(namespaceKeys)
+	return append(keys, namespaceKeys...)
+}
+
+func (r *reconcilerImpl) DeleteAll(ctx context.Context) error {
+	r.logger.Info("Deleting all managed resources")
 
+	keys := r.DeleteAllKeys(ctx)
 	deletedCount := 0
 	failedCount := 0
 	for _, key := range keys {
 		obj, err := r.parseKey(key)
 		if err != nil {
 			r.logger.Error(err, "failed to parse key for deletion", "key", key)
-			events.StoreEventSafe(r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
 			failedCount++
 			continue
 		}
@@ -8,6 +8,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/garunski/conductor-framework/pkg/framework/events"
 )
 
 // Test DeployAll
@@ -133,7 +135,7 @@ metadata:
   namespace: default`),
 	}
 
-	err := rec.DeployManifests(ctx, manifests)
+	_, err := rec.DeployManifests(ctx, manifests)
 	// May have errors due to fake client limitations
 	if err != nil {
 		t.Logf("DeployManifests() returned error (may be due to fake client limitations): %v", err)
@@ -156,7 +158,7 @@ metadata:
   namespace: default`),
 	}
 
-	err := rec.UpdateManifests(ctx, manifests)
+	_, err := rec.UpdateManifests(ctx, manifests)
 	if err != nil {
 		t.Fatalf("UpdateManifests() error = %v", err)
 	}
@@ -276,3 +278,54 @@ metadata:
 	// The important part is that StartPeriodicReconciliation executed and stopped correctly
 	// Resource creation may have fake client limitations
 }
+
+func TestReconciler_PeriodicReconciliationSkipsWhilePaused(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl := getReconcilerImpl(t, rec)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reconcileRuns := func() int {
+		infos, err := impl.eventStore.ListEvents(events.EventFilters{Type: events.EventTypeInfo, Limit: 1000})
+		if err != nil {
+			t.Fatalf("ListEvents() error = %v", err)
+		}
+		runs := 0
+		for _, event := range infos {
+			if event.Message == "Reconciliation started" {
+				runs++
+			}
+		}
+		return runs
+	}
+
+	rec.SetPaused(true)
+	if !rec.IsPaused() {
+		t.Fatal("IsPaused() = false after SetPaused(true)")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		impl.StartPeriodicReconciliation(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// Only the initial reconciliation runs while paused
+	time.Sleep(100 * time.Millisecond)
+	if got := reconcileRuns(); got != 1 {
+		t.Errorf("reconciliations while paused = %d, want 1", got)
+	}
+
+	rec.SetPaused(false)
+	time.Sleep(100 * time.Millisecond)
+	if got := reconcileRuns(); got < 2 {
+		t.Errorf("reconciliations after resume = %d, want more than 1", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("StartPeriodicReconciliation() did not stop after context cancel")
+	}
+}
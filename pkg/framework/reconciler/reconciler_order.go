@@ -0,0 +1,163 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// DefaultReadinessTimeout is how long reconcile waits for a dependency's pods to become Ready
+const DefaultReadinessTimeout = 5 * time.Minute
+
+// readinessPollInterval is how often pod readiness is checked; tests shorten it
+var readinessPollInterval = 2 * time.Second
+
+// WithReadinessTimeout sets how long reconcile waits for the pods of a dependency level
+// to become Ready before applying the services that depend on them. When the wait times
+// out the dependents are counted as failed and left for the next reconcile; periodic
+// reconciliation applies the levels in order without waiting.
+func WithReadinessTimeout(timeout time.Duration) Option {
+	return func(r *reconcilerImpl) {
+		r.readinessTimeout = timeout
+	}
+}
+
+// applyOrder splits the manifest keys into batches to apply in sequence, following the
+// depends-on annotations between services. Without dependencies everything is one batch.
+func (r *reconcilerImpl) applyOrder(manifests map[string][]byte) ([][]string, error) {
+	allKeys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		allKeys = append(allKeys, key)
+	}
+	sort.Strings(allKeys)
+
+	graph, err := manifest.BuildDependencyGraph(manifests)
+	if err != nil {
+		r.logger.V(1).Info("failed to build dependency graph, applying without ordering", "error", err)
+		return [][]string{allKeys}, nil
+	}
+	if !graph.HasDependencies() {
+		return [][]string{allKeys}, nil
+	}
+
+	levels, err := graph.Levels()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrReconciliation, err)
+	}
+
+	batches := make([][]string, 0, len(levels))
+	for _, level := range levels {
+		var batch []string
+		for _, service := range level {
+			batch = append(batch, graph.Keys(service)...)
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// workloadSelector holds the namespace and pod labels of a Deployment, StatefulSet or DaemonSet
+type workloadSelector struct {
+	namespace   string
+	matchLabels map[string]string
+}
+
+func workloadSelectors(manifests map[string][]byte, keys []string) []workloadSelector {
+	var selectors []workloadSelector
+	for _, key := range keys {
+		var obj struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Selector struct {
+					MatchLabels map[string]string `yaml:"matchLabels"`
+				} `yaml:"selector"`
+			} `yaml:"spec"`
+		}
+		if err := yaml.Unmarshal(manifests[key], &obj); err != nil {
+			continue
+		}
+		if obj.Kind != "Deployment" && obj.Kind != "StatefulSet" && obj.Kind != "DaemonSet" {
+			continue
+		}
+		if len(obj.Spec.Selector.MatchLabels) == 0 {
+			continue
+		}
+		namespace := obj.Metadata.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		selectors = append(selectors, workloadSelector{namespace: namespace, matchLabels: obj.Spec.Selector.MatchLabels})
+	}
+	return selectors
+}
+
+// waitForPodsReady polls until every workload in keys has at least one pod and all its pods are Ready
+func (r *reconcilerImpl) waitForPodsReady(ctx context.Context, manifests map[string][]byte, keys []string) error {
+	selectors := workloadSelectors(manifests, keys)
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	timeout := r.readinessTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadinessTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if r.podsReady(waitCtx, selectors) {
+			return nil
+		}
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("%w: timed out after %s waiting for pods to become ready", apperrors.ErrReconciliation, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *reconcilerImpl) podsReady(ctx context.Context, selectors []workloadSelector) bool {
+	for _, selector := range selectors {
+		pods, err := r.clientset.CoreV1().Pods(selector.namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(selector.matchLabels).String(),
+		})
+		if err != nil {
+			r.logger.V(1).Info("failed to list pods for readiness check", "namespace", selector.namespace, "error", err)
+			return false
+		}
+		if len(pods.Items) == 0 {
+			return false
+		}
+		for _, pod := range pods.Items {
+			if !isPodReady(pod) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+func orderedDeployment(name, deps string) []byte {
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: " + name + "\n  namespace: default\n"
+	if deps != "" {
+		manifest += "  annotations:\n    conductor.io/depends-on: \"" + deps + "\"\n"
+	}
+	manifest += "spec:\n  selector:\n    matchLabels:\n      app: " + name + "\n"
+	return []byte(manifest)
+}
+
+func readyPod(name, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// setupOrderTestReconciler returns a reconciler whose applies succeed and are recorded in order
+func setupOrderTestReconciler(t *testing.T, pods ...runtime.Object) (*reconcilerImpl, func() []string) {
+	t.Helper()
+	logger := logr.Discard()
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme)
+
+	var mu sync.Mutex
+	var applied []string
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		mu.Lock()
+		applied = append(applied, patchAction.GetName())
+		mu.Unlock()
+		obj := &unstructured.Unstructured{}
+		obj.SetName(patchAction.GetName())
+		return true, obj, nil
+	})
+
+	testDB, err := database.NewDB(filepath.Join(t.TempDir(), "test-order-db"), logger)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	t.Cleanup(func() { testDB.Close() })
+
+	manifestStore := store.NewManifestStore(testDB, index.NewIndex(), logger)
+	rec, err := NewReconciler(kubefake.NewSimpleClientset(pods...), dynamicClient, manifestStore, logger, events.NewStorage(testDB, logger), "test-app", WithReadinessTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("failed to create reconciler: %v", err)
+	}
+
+	return getReconcilerImpl(t, rec), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, applied...)
+	}
+}
+
+func TestReconciler_ReconcileFollowsDependencyOrder(t *testing.T) {
+	previous := readinessPollInterval
+	readinessPollInterval = 10 * time.Millisecond
+	defer func() { readinessPollInterval = previous }()
+
+	impl, appliedOrder := setupOrderTestReconciler(t, readyPod("postgres-0", "postgres"), readyPod("redis-0", "redis"))
+
+	manifests := map[string][]byte{
+		"default/Deployment/app":      orderedDeployment("app", "postgres,redis"),
+		"default/Deployment/redis":    orderedDeployment("redis", "postgres"),
+		"default/Deployment/postgres": orderedDeployment("postgres", ""),
+	}
+
+	result, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.AppliedCount != 3 {
+		t.Errorf("reconcile() applied = %d, want 3", result.AppliedCount)
+	}
+
+	got := appliedOrder()
+	want := []string{"postgres", "redis", "app"}
+	if len(got) != len(want) {
+		t.Fatalf("applied order = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("applied order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReconciler_ReconcileDependenciesWinOverPriority(t *testing.T) {
+	previous := readinessPollInterval
+	readinessPollInterval = 10 * time.Millisecond
+	defer func() { readinessPollInterval = previous }()
+
+	impl, appliedOrder := setupOrderTestReconciler(t, readyPod("postgres-0", "postgres"))
+
+	appConfig := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n  namespace: default\n"
+	manifests := map[string][]byte{
+		"default/Deployment/app":       orderedDeployment("app", "postgres"),
+		"default/ConfigMap/app-config": []byte(appConfig),
+		"default/Deployment/postgres":  orderedDeployment("postgres", ""),
+	}
+
+	if _, err := impl.reconcile(context.Background(), manifests, map[string]bool{}); err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+
+	// The ConfigMap has a higher priority than any Deployment but belongs to app, which
+	// depends on postgres
+	got := appliedOrder()
+	want := []string{"postgres", "app-config", "app"}
+	if len(got) != len(want) {
+		t.Fatalf("applied order = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("applied order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReconciler_ReconcileDependencyCycle(t *testing.T) {
+	impl, appliedOrder := setupOrderTestReconciler(t)
+
+	manifests := map[string][]byte{
+		"default/Deployment/a": orderedDeployment("a", "b"),
+		"default/Deployment/b": orderedDeployment("b", "a"),
+	}
+
+	_, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if !errors.Is(err, apperrors.ErrReconciliation) {
+		t.Fatalf("reconcile() error = %v, want ErrReconciliation", err)
+	}
+	if got := appliedOrder(); len(got) != 0 {
+		t.Errorf("reconcile() applied %v despite cycle, want nothing", got)
+	}
+}
+
+func TestReconciler_ReconcileReadinessTimeout(t *testing.T) {
+	previous := readinessPollInterval
+	readinessPollInterval = 10 * time.Millisecond
+	defer func() { readinessPollInterval = previous }()
+
+	impl, appliedOrder := setupOrderTestReconciler(t)
+	impl.readinessTimeout = 50 * time.Millisecond
+
+	manifests := map[string][]byte{
+		"default/Deployment/app":      orderedDeployment("app", "postgres"),
+		"default/Deployment/postgres": orderedDeployment("postgres", ""),
+	}
+
+	previousKeys := map[string]bool{"default/Deployment/app": true, "default/ConfigMap/removed": true}
+	result, err := impl.reconcile(context.Background(), manifests, previousKeys)
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if got := appliedOrder(); len(got) != 1 || got[0] != "postgres" {
+		t.Errorf("applied = %v, want only postgres before timeout", got)
+	}
+	if result.FailedCount != 1 {
+		t.Errorf("reconcile() failed = %d, want the dependent counted as failed", result.FailedCount)
+	}
+	// Orphans are still cleaned up, while the dependent that was not applied stays managed
+	if result.DeletedCount != 1 {
+		t.Errorf("reconcile() deleted = %d, want 1", result.DeletedCount)
+	}
+	if !result.ManagedKeys["default/Deployment/app"] {
+		t.Error("reconcile() dropped the dependent from ManagedKeys")
+	}
+}
+
+func TestReconciler_PeriodicReconcileDoesNotWaitForReadiness(t *testing.T) {
+	impl, appliedOrder := setupOrderTestReconciler(t)
+	impl.readinessTimeout = time.Minute
+
+	manifests := map[string][]byte{
+		"default/Deployment/app":      orderedDeployment("app", "postgres"),
+		"default/Deployment/postgres": orderedDeployment("postgres", ""),
+	}
+
+	start := time.Now()
+	result, err := impl.reconcile(withBackoffSkip(context.Background()), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("periodic reconcile took %s, want no wait for readiness", elapsed)
+	}
+	if result.AppliedCount != 2 {
+		t.Errorf("reconcile() applied = %d, want 2", result.AppliedCount)
+	}
+	if got := appliedOrder(); len(got) != 2 || got[0] != "postgres" || got[1] != "app" {
+		t.Errorf("applied order = %v, want [postgres app]", got)
+	}
+}
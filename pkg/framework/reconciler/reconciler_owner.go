@@ -0,0 +1,85 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// ApplyObjectWithOwner applies obj with an ownerReference to the live cluster object
+// of the manifest stored under ownerKey, so that deleting the owner cascades to obj.
+func (r *reconcilerImpl) ApplyObjectWithOwner(ctx context.Context, obj runtime.Object, ownerKey string) error {
+	resourceKey, err := r.setOwnerReference(ctx, obj, ownerKey)
+	if err != nil {
+		return err
+	}
+	return r.applyObject(ctx, obj, resourceKey)
+}
+
+// setOwnerReference injects an ownerReference for ownerKey into obj and returns obj's resource key
+func (r *reconcilerImpl) setOwnerReference(ctx context.Context, obj runtime.Object, ownerKey string) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", fmt.Errorf("%w: object has no metadata: %w", apperrors.ErrInvalid, err)
+	}
+
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvks, _, err := r.scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+			kind = gvks[0].Kind
+		}
+	}
+	namespace := accessor.GetNamespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	resourceKey := fmt.Sprintf("%s/%s/%s", namespace, kind, accessor.GetName())
+
+	if _, ok := r.store.Get(ownerKey); !ok {
+		return "", fmt.Errorf("%w: owner manifest %s", apperrors.ErrNotFound, ownerKey)
+	}
+
+	owner, err := r.parseKey(ownerKey)
+	if err != nil {
+		return "", err
+	}
+	if owner.GetNamespace() != accessor.GetNamespace() && accessor.GetNamespace() != "" {
+		return "", fmt.Errorf("%w: owner %s must be in the same namespace as %s", apperrors.ErrInvalid, ownerKey, resourceKey)
+	}
+
+	live, err := r.GetLiveObject(ctx, ownerKey)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return "", fmt.Errorf("%w: owner %s not found in cluster", apperrors.ErrNotFound, ownerKey)
+		}
+		return "", err
+	}
+
+	ref := metav1.OwnerReference{
+		APIVersion: live.GetAPIVersion(),
+		Kind:       live.GetKind(),
+		Name:       live.GetName(),
+		UID:        live.GetUID(),
+	}
+
+	refs := accessor.GetOwnerReferences()
+	replaced := false
+	for i := range refs {
+		if refs[i].UID == ref.UID {
+			refs[i] = ref
+			replaced = true
+		}
+	}
+	if !replaced {
+		refs = append(refs, ref)
+	}
+	accessor.SetOwnerReferences(refs)
+
+	return resourceKey, nil
+}
@@ -0,0 +1,119 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+func newOwnedConfigMap(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+		},
+	}
+}
+
+// createOwner stores the owner manifest and creates its live object with a fixed UID
+func createOwner(t *testing.T, impl *reconcilerImpl, uid types.UID) {
+	t.Helper()
+	ctx := context.Background()
+
+	manifest := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: owner\n  namespace: default\n")
+	if err := impl.store.Create("default/ConfigMap/owner", manifest); err != nil {
+		t.Fatalf("Failed to create manifest: %v", err)
+	}
+
+	owner := newOwnedConfigMap("owner")
+	owner.SetUID(uid)
+	gvr := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
+	if _, err := impl.dynamicClient.Resource(gvr).Namespace("default").Create(ctx, owner, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+}
+
+func TestReconciler_setOwnerReference(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl := getReconcilerImpl(t, rec)
+	createOwner(t, impl, "owner-uid-123")
+
+	obj := newOwnedConfigMap("child")
+	key, err := impl.setOwnerReference(context.Background(), obj, "default/ConfigMap/owner")
+	if err != nil {
+		t.Fatalf("setOwnerReference() error = %v", err)
+	}
+	if key != "default/ConfigMap/child" {
+		t.Errorf("setOwnerReference() key = %v, want default/ConfigMap/child", key)
+	}
+
+	refs := obj.GetOwnerReferences()
+	if len(refs) != 1 {
+		t.Fatalf("expected 1 ownerReference, got %d", len(refs))
+	}
+	if refs[0].UID != "owner-uid-123" {
+		t.Errorf("ownerReference UID = %v, want owner-uid-123", refs[0].UID)
+	}
+	if refs[0].Kind != "ConfigMap" || refs[0].Name != "owner" || refs[0].APIVersion != "v1" {
+		t.Errorf("unexpected ownerReference: %+v", refs[0])
+	}
+
+	// Setting the same owner again does not duplicate the reference
+	if _, err := impl.setOwnerReference(context.Background(), obj, "default/ConfigMap/owner"); err != nil {
+		t.Fatalf("setOwnerReference() error = %v", err)
+	}
+	if len(obj.GetOwnerReferences()) != 1 {
+		t.Errorf("expected 1 ownerReference after re-apply, got %d", len(obj.GetOwnerReferences()))
+	}
+}
+
+func TestReconciler_ApplyObjectWithOwner(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl := getReconcilerImpl(t, rec)
+	createOwner(t, impl, "owner-uid-456")
+
+	obj := newOwnedConfigMap("child")
+	err := rec.ApplyObjectWithOwner(context.Background(), obj, "default/ConfigMap/owner")
+	if err != nil {
+		// Apply may fail due to fake client limitations; the ownerReference is still injected
+		t.Logf("ApplyObjectWithOwner() returned error (may be due to fake client limitations): %v", err)
+	}
+
+	refs := obj.GetOwnerReferences()
+	if len(refs) != 1 || refs[0].UID != "owner-uid-456" {
+		t.Errorf("ApplyObjectWithOwner() ownerReferences = %+v, want UID owner-uid-456", refs)
+	}
+}
+
+func TestReconciler_ApplyObjectWithOwner_OwnerMissing(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl := getReconcilerImpl(t, rec)
+	ctx := context.Background()
+
+	// Owner not in the store
+	err := rec.ApplyObjectWithOwner(ctx, newOwnedConfigMap("child"), "default/ConfigMap/owner")
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("ApplyObjectWithOwner() error = %v, want ErrNotFound", err)
+	}
+
+	// Owner in the store but not in the cluster
+	manifest := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: owner\n  namespace: default\n")
+	if err := impl.store.Create("default/ConfigMap/owner", manifest); err != nil {
+		t.Fatalf("Failed to create manifest: %v", err)
+	}
+	err = rec.ApplyObjectWithOwner(ctx, newOwnedConfigMap("child"), "default/ConfigMap/owner")
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("ApplyObjectWithOwner() error = %v, want ErrNotFound", err)
+	}
+}
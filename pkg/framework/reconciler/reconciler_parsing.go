@@ -1,6 +1,7 @@
 package reconciler
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -14,11 +15,11 @@ import (
 )
 
 // parseYAML parses YAML data into a runtime.Object
-func (r *reconcilerImpl) parseYAML(yamlData []byte, resourceKey string) (runtime.Object, error) {
+func (r *reconcilerImpl) parseYAML(ctx context.Context, yamlData []byte, resourceKey string) (runtime.Object, error) {
 	decoder := serializer.NewCodecFactory(r.scheme).UniversalDeserializer()
 	obj, _, err := decoder.Decode(yamlData, nil, nil)
 	if err != nil {
-		events.StoreEventSafe(r.eventStore, r.logger, events.Error(resourceKey, "parse", "Failed to parse manifest YAML", err))
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(resourceKey, "parse", "Failed to parse manifest YAML", err))
 		return nil, fmt.Errorf("%w: failed to decode YAML for resource %s: %w", apperrors.ErrInvalidYAML, resourceKey, err)
 	}
 	return obj, nil
@@ -1,6 +1,7 @@
 package reconciler
 
 import (
+	"context"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -17,7 +18,7 @@ metadata:
   namespace: default
 spec: {}`)
 
-	obj, err := impl.parseYAML(yamlData, "default/Service/test-service")
+	obj, err := impl.parseYAML(context.Background(), yamlData, "default/Service/test-service")
 	if err != nil {
 		t.Fatalf("parseYAML() error = %v", err)
 	}
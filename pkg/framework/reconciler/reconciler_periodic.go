@@ -2,12 +2,24 @@ package reconciler
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 )
 
+// StartPeriodicReconciliation reconciles immediately and then every ReconcileInterval until ctx
+// is done. interval becomes the reconcile interval unless one was persisted by an earlier process;
+// zero keeps the current interval. Ticks that fire while the reconciler is paused are skipped.
 func (r *reconcilerImpl) StartPeriodicReconciliation(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	if interval > 0 && !r.intervalPersisted {
+		r.storeReconcileInterval(interval)
+	}
+
+	timer := time.NewTimer(r.ReconcileInterval())
+	defer timer.Stop()
+
+	if err := r.gvkCache.RebuildCache(ctx); err != nil {
+		r.logger.Error(err, "failed to discover API resources, falling back to built-in kinds")
+	}
 
 	// Do initial reconciliation immediately
 	r.reconcileAll(ctx)
@@ -16,9 +28,22 @@ func (r *reconcilerImpl) StartPeriodicReconciliation(ctx context.Context, interv
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			r.reconcileAll(ctx)
+		case <-r.intervalChanged:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			r.logger.Info("reconcile interval changed", "interval", r.ReconcileInterval().String())
+		case <-timer.C:
+			if r.IsPaused() {
+				r.logger.V(1).Info("reconciler paused, skipping periodic reconciliation")
+			} else {
+				r.reconcileAll(ctx)
+			}
 		}
+		timer.Reset(r.ReconcileInterval())
 	}
 }
 
@@ -33,3 +58,16 @@ func (r *reconcilerImpl) WaitForFirstReconciliation(ctx context.Context) error {
 	}
 }
 
+// SetPaused pauses or resumes periodic reconciliation without stopping its goroutine
+func (r *reconcilerImpl) SetPaused(paused bool) {
+	var value int32
+	if paused {
+		value = 1
+	}
+	atomic.StoreInt32(&r.paused, value)
+}
+
+// IsPaused reports whether periodic reconciliation is paused
+func (r *reconcilerImpl) IsPaused() bool {
+	return atomic.LoadInt32(&r.paused) == 1
+}
@@ -0,0 +1,89 @@
+package reconciler
+
+import (
+	"sort"
+	"strings"
+)
+
+// Apply priorities returned by ResourcePriority; lower values are applied first
+const (
+	PriorityNamespace = 0
+	PriorityCRD       = 1
+	PriorityRBAC      = 2
+	PriorityConfig    = 3
+	PriorityDefault   = 10
+	PriorityWorkload  = 20
+)
+
+// ResourcePriority returns the apply priority of kind. Namespaces come first so that
+// namespaced resources have somewhere to live, CRDs before the custom resources that use
+// them, RBAC and configuration before the workloads that reference them, and Deployments
+// and StatefulSets last. Deletion runs in the reverse order.
+//
+// Priority only orders the resources within one dependency level. When services declare
+// conductor.io/depends-on, dependencies win: every resource of a level, whatever its kind,
+// is applied after all the resources of the levels it depends on, so a Namespace or CRD a
+// dependent needs before its dependencies must be part of a dependency level of its own.
+func ResourcePriority(kind string) int {
+	switch kind {
+	case "Namespace":
+		return PriorityNamespace
+	case "CustomResourceDefinition":
+		return PriorityCRD
+	case "ServiceAccount", "Role", "ClusterRole", "RoleBinding", "ClusterRoleBinding":
+		return PriorityRBAC
+	case "ConfigMap", "Secret":
+		return PriorityConfig
+	case "Deployment", "StatefulSet":
+		return PriorityWorkload
+	default:
+		return PriorityDefault
+	}
+}
+
+// keyPriority returns the ResourcePriority of the kind in a namespace/kind/name key
+func keyPriority(key string) int {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return PriorityDefault
+	}
+	return ResourcePriority(parts[1])
+}
+
+// sortForApply orders keys by ascending priority, then by key
+func sortForApply(keys []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		pi, pj := keyPriority(keys[i]), keyPriority(keys[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// sortForDelete orders keys by descending priority, then by key
+func sortForDelete(keys []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		pi, pj := keyPriority(keys[i]), keyPriority(keys[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// priorityGroups sorts keys for apply and splits them into runs of equal priority,
+// so each group can be applied concurrently once the previous one has finished
+func priorityGroups(keys []string) [][]string {
+	sorted := append([]string(nil), keys...)
+	sortForApply(sorted)
+
+	var groups [][]string
+	for i, key := range sorted {
+		if i == 0 || keyPriority(key) != keyPriority(sorted[i-1]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], key)
+	}
+	return groups
+}
@@ -0,0 +1,138 @@
+package reconciler
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestResourcePriority(t *testing.T) {
+	tests := []struct {
+		kind string
+		want int
+	}{
+		{kind: "Namespace", want: PriorityNamespace},
+		{kind: "CustomResourceDefinition", want: PriorityCRD},
+		{kind: "ClusterRoleBinding", want: PriorityRBAC},
+		{kind: "ServiceAccount", want: PriorityRBAC},
+		{kind: "ConfigMap", want: PriorityConfig},
+		{kind: "Secret", want: PriorityConfig},
+		{kind: "Service", want: PriorityDefault},
+		{kind: "Deployment", want: PriorityWorkload},
+		{kind: "StatefulSet", want: PriorityWorkload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if got := ResourcePriority(tt.kind); got != tt.want {
+				t.Errorf("ResourcePriority(%q) = %d, want %d", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
+
+var mixedPriorityKeys = []string{
+	"default/Deployment/web",
+	"default/Service/web",
+	"default/ConfigMap/web-config",
+	"/Namespace/team",
+	"default/StatefulSet/db",
+	"/CustomResourceDefinition/widgets.example.com",
+	"default/Widget/main",
+	"default/Role/reader",
+	"default/Secret/web-secret",
+}
+
+func TestSortForApply(t *testing.T) {
+	keys := append([]string(nil), mixedPriorityKeys...)
+	sortForApply(keys)
+
+	want := []string{
+		"/Namespace/team",
+		"/CustomResourceDefinition/widgets.example.com",
+		"default/Role/reader",
+		"default/ConfigMap/web-config",
+		"default/Secret/web-secret",
+		"default/Service/web",
+		"default/Widget/main",
+		"default/Deployment/web",
+		"default/StatefulSet/db",
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("sortForApply() = %v, want %v", keys, want)
+	}
+}
+
+func TestSortForDelete(t *testing.T) {
+	keys := append([]string(nil), mixedPriorityKeys...)
+	sortForDelete(keys)
+
+	want := []string{
+		"default/Deployment/web",
+		"default/StatefulSet/db",
+		"default/Service/web",
+		"default/Widget/main",
+		"default/ConfigMap/web-config",
+		"default/Secret/web-secret",
+		"default/Role/reader",
+		"/CustomResourceDefinition/widgets.example.com",
+		"/Namespace/team",
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("sortForDelete() = %v, want %v", keys, want)
+	}
+}
+
+func TestReconciler_ReconcileAppliesByPriority(t *testing.T) {
+	impl, appliedOrder := setupOrderTestReconciler(t)
+
+	manifests := map[string][]byte{
+		"default/Deployment/web":    orderedDeployment("web", ""),
+		"default/Service/web-svc":   []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: web-svc\n  namespace: default\n"),
+		"default/ConfigMap/web-cfg": []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-cfg\n  namespace: default\n"),
+		"/Namespace/team":           []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team\n"),
+		"default/Role/web-reader":   []byte("apiVersion: rbac.authorization.k8s.io/v1\nkind: Role\nmetadata:\n  name: web-reader\n  namespace: default\n"),
+	}
+
+	if _, err := impl.reconcile(context.Background(), manifests, map[string]bool{}); err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+
+	want := []string{"team", "web-reader", "web-cfg", "web-svc", "web"}
+	if got := appliedOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("applied order = %v, want %v", got, want)
+	}
+}
+
+func TestReconciler_DeleteManifestsReversesPriority(t *testing.T) {
+	impl, _ := setupOrderTestReconciler(t)
+
+	var mu sync.Mutex
+	var deleted []string
+	impl.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("delete", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		deleted = append(deleted, action.(k8stesting.DeleteAction).GetName())
+		mu.Unlock()
+		return true, nil, nil
+	})
+
+	manifests := map[string][]byte{
+		"default/Deployment/web":    nil,
+		"default/ConfigMap/web-cfg": nil,
+		"default/Service/web-svc":   nil,
+		"/Namespace/team":           nil,
+	}
+	if err := impl.DeleteManifests(context.Background(), manifests); err != nil {
+		t.Fatalf("DeleteManifests() error = %v", err)
+	}
+
+	want := []string{"web", "web-svc", "web-cfg", "team"}
+	if !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deletion order = %v, want %v", deleted, want)
+	}
+}
@@ -2,7 +2,10 @@ package reconciler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -10,82 +13,291 @@ import (
 )
 
 func (r *reconcilerImpl) reconcile(ctx context.Context, manifests map[string][]byte, previousKeys map[string]bool) (ReconciliationResult, error) {
+	start := time.Now()
+	defer func() { r.metrics.ObserveReconcile(time.Since(start)) }()
+
 	currentKeys := make(map[string]bool)
 	appliedCount := 0
 	failedCount := 0
+	timedOutCount := 0
+	backedOffCount := 0
+	skippedCount := 0
 
 	for key := range manifests {
 		currentKeys[key] = true
 	}
 
-	semaphore := make(chan struct{}, MaxConcurrency)
-	var wg sync.WaitGroup
-	var mu sync.Mutex
+	batches, err := r.applyOrder(manifests)
+	if err != nil {
+		return ReconciliationResult{}, err
+	}
+	params := r.newConditionParams()
+	dependenciesReady := true
 
-	for key, yamlData := range manifests {
-		wg.Add(1)
-		go func(key string, yamlData []byte) {
-			defer wg.Done()
+	for i, batch := range batches {
+		if !dependenciesReady {
+			// Dependents of pods that never became Ready are left for the next reconcile;
+			// they stay managed, so they are not deleted as orphans below
+			failedCount += len(batch)
+			continue
+		}
 
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+		// Within a dependency level, namespaces, CRDs and configuration go before workloads;
+		// each priority group is applied in full before the next one starts. Priority does
+		// not reach across levels: a later level's Namespace follows an earlier level's Deployment.
+		for _, group := range priorityGroups(batch) {
+			applied := r.applyBatch(ctx, manifests, group, params)
+			appliedCount += applied.AppliedCount
+			failedCount += applied.FailedCount
+			timedOutCount += applied.TimedOutCount
+			backedOffCount += applied.BackedOffCount
+			skippedCount += applied.SkippedCount
+			// A skipped resource is no longer managed, so one applied before its condition
+			// turned false is deleted below
+			for _, key := range applied.skippedKeys {
+				delete(currentKeys, key)
+			}
 
-			obj, err := r.parseYAML(yamlData, key)
-			if err != nil {
-				r.logger.Error(err, "failed to parse manifest YAML", "key", key, "error", err.Error())
-				mu.Lock()
-				failedCount++
-				mu.Unlock()
-				return
+			// Stop at the first group boundary after cancellation; the applies already
+			// started have completed
+			if ctx.Err() != nil {
+				return ReconciliationResult{
+					AppliedCount:   appliedCount,
+					FailedCount:    failedCount,
+					TimedOutCount:  timedOutCount,
+					BackedOffCount: backedOffCount,
+					SkippedCount:   skippedCount,
+				}, ctx.Err()
 			}
+		}
 
-			if err := r.applyObject(ctx, obj, key); err != nil {
-				r.logger.Error(err, "failed to apply manifest to cluster", "key", key, "error", err.Error())
-				mu.Lock()
-				failedCount++
-				mu.Unlock()
-			} else {
-				mu.Lock()
-				currentKeys[key] = true
-				appliedCount++
-				mu.Unlock()
+		// Dependents are only applied once the pods of this batch are Ready; skipped
+		// resources have no pods to wait for. Periodic reconciliation applies the levels
+		// in order without waiting, so a dependency that never becomes Ready does not hold
+		// up every tick.
+		if i < len(batches)-1 && !skipsBackedOff(ctx) {
+			applied := make([]string, 0, len(batch))
+			for _, key := range batch {
+				if currentKeys[key] {
+					applied = append(applied, key)
+				}
 			}
-		}(key, yamlData)
+			if err := r.waitForPodsReady(ctx, manifests, applied); err != nil {
+				if ctx.Err() != nil {
+					return ReconciliationResult{
+						AppliedCount:   appliedCount,
+						FailedCount:    failedCount,
+						TimedOutCount:  timedOutCount,
+						BackedOffCount: backedOffCount,
+						SkippedCount:   skippedCount,
+					}, ctx.Err()
+				}
+				r.logger.Error(err, "dependencies not ready, skipping the services that depend on them")
+				events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error("", "reconcile", "Dependencies not ready", err))
+				dependenciesReady = false
+			}
+		}
 	}
 
-	wg.Wait()
+	// Namespaces created on demand outlive the manifests that needed them until DeleteAll
+	for key := range r.getAllManagedKeys(ctx) {
+		if isAutoNamespaceKey(key) {
+			currentKeys[key] = true
+		}
+	}
 
 	deletedCount := r.deleteOrphanedResources(ctx, previousKeys, currentKeys)
+	if ctx.Err() != nil {
+		return ReconciliationResult{
+			AppliedCount:   appliedCount,
+			FailedCount:    failedCount,
+			DeletedCount:   deletedCount,
+			TimedOutCount:  timedOutCount,
+			BackedOffCount: backedOffCount,
+			SkippedCount:   skippedCount,
+		}, ctx.Err()
+	}
 
 	return ReconciliationResult{
-		AppliedCount: appliedCount,
-		FailedCount:  failedCount,
-		DeletedCount: deletedCount,
-		ManagedKeys:  currentKeys,
+		AppliedCount:   appliedCount,
+		FailedCount:    failedCount,
+		DeletedCount:   deletedCount,
+		TimedOutCount:  timedOutCount,
+		BackedOffCount: backedOffCount,
+		SkippedCount:   skippedCount,
+		ManagedKeys:    currentKeys,
 	}, nil
 }
 
+// WithWorkers sets the number of workers applying the manifests of a priority group
+// concurrently. A non-positive n keeps DefaultWorkers.
+func WithWorkers(n int) Option {
+	return func(r *reconcilerImpl) {
+		if n > 0 {
+			r.workers = n
+		}
+	}
+}
+
+// applyOutcome is the result of applying a single manifest
+type applyOutcome int
+
+const (
+	outcomeApplied applyOutcome = iota
+	outcomeFailed
+	outcomeTimedOut
+	outcomeBackedOff
+	outcomeSkipped
+	// outcomeCanceled is an apply that did not happen because the reconciliation was
+	// canceled; it is neither a success nor a failure
+	outcomeCanceled
+)
+
+// applyBatch applies the manifests for keys with a pool of workers fed from a queue and returns
+// the applied, failed, timed out, backed off and skipped counts. A failed apply does not stop the other
+// workers; an apply that exceeds its deploy timeout is recorded as failed. Periodic
+// reconciliation skips the keys whose failure backoff has not expired. Once ctx is canceled
+// the workers finish their current apply and take no further keys.
+func (r *reconcilerImpl) applyBatch(ctx context.Context, manifests map[string][]byte, keys []string, params *conditionParams) ReconciliationResult {
+	queue := make(chan string, len(keys))
+	for _, key := range keys {
+		queue <- key
+	}
+	close(queue)
+
+	workers := r.workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+
+	var result ReconciliationResult
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				outcome := r.applyManifest(ctx, key, manifests[key], params)
+				mu.Lock()
+				switch outcome {
+				case outcomeApplied:
+					result.AppliedCount++
+				case outcomeFailed:
+					result.FailedCount++
+				case outcomeTimedOut:
+					result.FailedCount++
+					result.TimedOutCount++
+				case outcomeBackedOff:
+					result.BackedOffCount++
+				case outcomeSkipped:
+					result.SkippedCount++
+					result.skippedKeys = append(result.skippedKeys, key)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return result
+}
+
+// applyManifest parses and applies the manifest of key, updating its failure backoff. A
+// manifest whose conductor.io/condition annotation evaluates to false is skipped; one whose
+// condition cannot be evaluated fails.
+func (r *reconcilerImpl) applyManifest(ctx context.Context, key string, yamlData []byte, params *conditionParams) applyOutcome {
+	if skipsBackedOff(ctx) && r.inBackoff(key) {
+		r.logger.V(1).Info("skipping resource in failure backoff", "key", key)
+		return outcomeBackedOff
+	}
+
+	obj, err := r.parseYAML(ctx, yamlData, key)
+	if err != nil {
+		r.logger.Error(err, "failed to parse manifest YAML", "key", key, "error", err.Error())
+		r.metrics.ObserveApply(err)
+		r.recordFailure(key)
+		return outcomeFailed
+	}
+
+	apply, err := conditionMet(ctx, obj, params)
+	if err != nil && canceled(ctx, err) {
+		return outcomeCanceled
+	}
+	if err != nil {
+		r.logger.Error(err, "failed to evaluate manifest condition", "key", key, "error", err.Error())
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "apply", "Failed to evaluate condition", err))
+		r.metrics.ObserveApply(err)
+		r.recordFailure(key)
+		return outcomeFailed
+	}
+	if !apply {
+		r.logger.V(1).Info("skipping resource whose condition is false", "key", key)
+		r.resetBackoff(key)
+		return outcomeSkipped
+	}
+
+	timeout := r.deployTimeoutFor(obj, key)
+	err = r.applyObjectWithTimeout(ctx, obj, key, timeout)
+	r.metrics.ObserveApply(err)
+	if err != nil {
+		r.logger.Error(err, "failed to apply manifest to cluster", "key", key, "error", err.Error())
+		r.recordFailure(key)
+		if errors.Is(err, context.DeadlineExceeded) {
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "apply", fmt.Sprintf("Apply timed out after %s", timeout), err))
+			return outcomeTimedOut
+		}
+		return outcomeFailed
+	}
+
+	r.resetBackoff(key)
+	return outcomeApplied
+}
+
+// canceled reports whether err is the cancellation of ctx rather than a failure of its own
+func canceled(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
+}
+
 func (r *reconcilerImpl) deleteOrphanedResources(ctx context.Context, previousKeys, currentKeys map[string]bool) int {
-	deletedCount := 0
+	var orphanedKeys []string
 	for key := range previousKeys {
 		if !currentKeys[key] {
+			orphanedKeys = append(orphanedKeys, key)
+		}
+	}
+	sortForDelete(orphanedKeys)
 
-			obj, err := r.parseKey(key)
-			if err != nil {
-				r.logger.Error(err, "failed to parse key for deletion", "key", key, "error", err.Error())
-				events.StoreEventSafe(r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
-				continue
-			}
+	deletedCount := 0
+	for _, key := range orphanedKeys {
+		if ctx.Err() != nil {
+			break
+		}
+		obj, err := r.parseKey(key)
+		if err != nil {
+			r.logger.Error(err, "failed to parse key for deletion", "key", key, "error", err.Error())
+			events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "delete", "Failed to parse key for deletion", err))
+			r.metrics.ObserveDelete(err)
+			continue
+		}
 
-			if err := r.deleteObject(ctx, obj, key); err != nil {
-				if !k8serrors.IsNotFound(err) {
-					r.logger.Error(err, "failed to delete resource from cluster", "key", key, "error", err.Error())
-				} else {
-					deletedCount++
-				}
+		if err := r.deleteObject(ctx, obj, key); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				r.logger.Error(err, "failed to delete resource from cluster", "key", key, "error", err.Error())
+				r.metrics.ObserveDelete(err)
 			} else {
 				deletedCount++
+				r.metrics.ObserveDelete(nil)
 			}
+		} else {
+			deletedCount++
+			r.metrics.ObserveDelete(nil)
 		}
 	}
 	return deletedCount
@@ -94,18 +306,36 @@ func (r *reconcilerImpl) deleteOrphanedResources(ctx context.Context, previousKe
 func (r *reconcilerImpl) reconcileAll(ctx context.Context) {
 	manifests := r.store.List()
 
-	events.StoreEventSafe(r.eventStore, r.logger, events.Info("", "reconcile", "Reconciliation started"))
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Info("", "reconcile", "Reconciliation started"))
 
 	previousKeys := r.getAllManagedKeys(ctx)
 
-	result, err := r.reconcile(ctx, manifests, previousKeys)
+	result, err := r.reconcile(withBackoffSkip(ctx), manifests, previousKeys)
+	if err != nil && ctx.Err() != nil {
+		processed := result.AppliedCount + result.FailedCount + result.BackedOffCount + result.SkippedCount + result.DeletedCount
+		r.logger.Info("reconciliation canceled", "processed", processed)
+		event := events.Info("", "reconcile", fmt.Sprintf("reconciliation canceled after %d resources", processed))
+		event.Details["applied"] = result.AppliedCount
+		event.Details["failed"] = result.FailedCount
+		event.Details["skipped"] = result.SkippedCount
+		event.Details["deleted"] = result.DeletedCount
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, event)
+		return
+	}
 	if err != nil {
 		r.logger.Error(err, "reconciliation failed")
-		events.StoreEventSafe(r.eventStore, r.logger, events.Error("", "reconcile", "Reconciliation failed", err))
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error("", "reconcile", "Reconciliation failed", err))
 		return
 	}
 
 	r.setAllManagedKeys(ctx, result.ManagedKeys)
+	r.metrics.SetManagedResources(len(result.ManagedKeys))
+
+	if result.FailedCount == 0 {
+		if err := r.saveRollbackSnapshot(result.ManagedKeys, manifests); err != nil {
+			r.logger.Error(err, "failed to save rollback snapshot")
+		}
+	}
 
 	// Signal first reconciliation completion
 	r.firstReconcileMu.Lock()
@@ -122,15 +352,20 @@ func (r *reconcilerImpl) reconcileAll(ctx context.Context) {
 	event.Details["total"] = len(manifests)
 	event.Details["applied"] = result.AppliedCount
 	event.Details["failed"] = result.FailedCount
+	event.Details["timedOut"] = result.TimedOutCount
+	event.Details["backedOff"] = result.BackedOffCount
+	event.Details["skipped"] = result.SkippedCount
 	event.Details["deleted"] = result.DeletedCount
 	event.Details["managed"] = len(result.ManagedKeys)
-	events.StoreEventSafe(r.eventStore, r.logger, event)
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, event)
 
 	r.logger.Info("Reconciliation complete",
 		"total", len(manifests),
 		"applied", result.AppliedCount,
 		"failed", result.FailedCount,
+		"timedOut", result.TimedOutCount,
+		"backedOff", result.BackedOffCount,
+		"skipped", result.SkippedCount,
 		"deleted", result.DeletedCount,
 		"managed", len(result.ManagedKeys))
 }
-
@@ -0,0 +1,183 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// RollbackKeyPrefix is the database key prefix under which rollback snapshots are stored
+const RollbackKeyPrefix = "rollback/v"
+
+// DefaultRollbackRetention is the default number of rollback snapshots kept
+const DefaultRollbackRetention = 20
+
+// RollbackSnapshot captures the managed keys and manifests of a successful full reconciliation
+type RollbackSnapshot struct {
+	Version     int               `json:"version"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Hash        string            `json:"hash,omitempty"`
+	ManagedKeys []string          `json:"managedKeys"`
+	Manifests   map[string][]byte `json:"manifests"`
+}
+
+// Option configures optional reconciler behaviour
+type Option func(*reconcilerImpl)
+
+// WithRollbackDB enables rollback snapshots, persisting them in db
+func WithRollbackDB(db *database.DB) Option {
+	return func(r *reconcilerImpl) {
+		r.rollbackDB = db
+	}
+}
+
+// WithRollbackRetention sets how many rollback snapshots are kept; older ones are deleted
+// when a new snapshot is stored. Zero or less keeps DefaultRollbackRetention.
+func WithRollbackRetention(n int) Option {
+	return func(r *reconcilerImpl) {
+		if n > 0 {
+			r.rollbackRetention = n
+		}
+	}
+}
+
+func rollbackKey(version int) string {
+	return fmt.Sprintf("%s%d", RollbackKeyPrefix, version)
+}
+
+// rollbackHash returns a digest of the managed keys and manifests of a snapshot
+func rollbackHash(keys []string, manifests map[string][]byte) string {
+	manifestKeys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		manifestKeys = append(manifestKeys, key)
+	}
+	sort.Strings(manifestKeys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+	}
+	hash.Write([]byte{0})
+	for _, key := range manifestKeys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(manifests[key])
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// saveRollbackSnapshot stores the managed keys and manifests as the next snapshot version,
+// unless they match the latest snapshot, and deletes the snapshots past the retention
+func (r *reconcilerImpl) saveRollbackSnapshot(managedKeys map[string]bool, manifests map[string][]byte) error {
+	if r.rollbackDB == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(managedKeys))
+	for key := range managedKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := rollbackHash(keys, manifests)
+
+	return r.rollbackDB.Transaction(func(txn *database.DB) error {
+		snapshots, err := listRollbackSnapshots(txn)
+		if err != nil {
+			return err
+		}
+
+		version := 1
+		if len(snapshots) > 0 {
+			latest := snapshots[len(snapshots)-1]
+			if latest.Hash == hash {
+				return nil
+			}
+			version = latest.Version + 1
+		}
+
+		data, err := json.Marshal(RollbackSnapshot{
+			Version:     version,
+			Timestamp:   time.Now(),
+			Hash:        hash,
+			ManagedKeys: keys,
+			Manifests:   manifests,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: failed to marshal rollback snapshot: %w", apperrors.ErrStorage, err)
+		}
+		if err := txn.Set(rollbackKey(version), data); err != nil {
+			return err
+		}
+
+		// snapshots does not include the one just stored
+		for i := 0; i < len(snapshots)+1-r.rollbackRetention; i++ {
+			if err := txn.Delete(rollbackKey(snapshots[i].Version)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListRollbackSnapshots returns all stored snapshots ordered by version
+func (r *reconcilerImpl) ListRollbackSnapshots(ctx context.Context) ([]RollbackSnapshot, error) {
+	if r.rollbackDB == nil {
+		return []RollbackSnapshot{}, nil
+	}
+	return listRollbackSnapshots(r.rollbackDB)
+}
+
+// GetRollbackSnapshot returns the snapshot with the given version, or the latest one if version is 0
+func (r *reconcilerImpl) GetRollbackSnapshot(ctx context.Context, version int) (*RollbackSnapshot, error) {
+	snapshots, err := r.ListRollbackSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w: no rollback snapshots available", apperrors.ErrNotFound)
+	}
+	if version == 0 {
+		return &snapshots[len(snapshots)-1], nil
+	}
+	for i := range snapshots {
+		if snapshots[i].Version == version {
+			return &snapshots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: rollback snapshot version %d", apperrors.ErrNotFound, version)
+}
+
+func listRollbackSnapshots(db *database.DB) ([]RollbackSnapshot, error) {
+	items, err := db.List(RollbackKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]RollbackSnapshot, 0, len(items))
+	for key, data := range items {
+		if _, err := strconv.Atoi(strings.TrimPrefix(key, RollbackKeyPrefix)); err != nil {
+			continue
+		}
+		var snapshot RollbackSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("%w: failed to unmarshal rollback snapshot %s: %w", apperrors.ErrStorage, key, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Version < snapshots[j].Version
+	})
+	return snapshots, nil
+}
@@ -0,0 +1,204 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// setupRollbackTestReconciler returns a reconciler with rollback snapshots enabled
+// whose fake dynamic client accepts every apply and delete
+func setupRollbackTestReconciler(t *testing.T) (*reconcilerImpl, store.ManifestStore) {
+	t.Helper()
+	logger := logr.Discard()
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme)
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		obj := &unstructured.Unstructured{}
+		obj.SetName(patchAction.GetName())
+		obj.SetNamespace(patchAction.GetNamespace())
+		return true, obj, nil
+	})
+	dynamicClient.PrependReactor("delete", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+
+	testDB, err := database.NewDB(filepath.Join(t.TempDir(), "test-rollback-db"), logger)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	t.Cleanup(func() { testDB.Close() })
+
+	manifestStore := store.NewManifestStore(testDB, index.NewIndex(), logger)
+	rec, err := NewReconciler(kubefake.NewSimpleClientset(), dynamicClient, manifestStore, logger, events.NewStorage(testDB, logger), "test-app", WithRollbackDB(testDB))
+	if err != nil {
+		t.Fatalf("failed to create reconciler: %v", err)
+	}
+	return getReconcilerImpl(t, rec), manifestStore
+}
+
+func sortedManagedKeys(r *reconcilerImpl) []string {
+	keys := make([]string, 0)
+	for key := range r.getAllManagedKeys(context.Background()) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestReconciler_RollbackSnapshotVersions(t *testing.T) {
+	impl, manifestStore := setupRollbackTestReconciler(t)
+	ctx := context.Background()
+
+	if err := manifestStore.Create("default/ConfigMap/a", []byte(testConfigMapYAML("a"))); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	impl.reconcileAll(ctx)
+
+	if err := manifestStore.Create("default/ConfigMap/b", []byte(testConfigMapYAML("b"))); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	impl.reconcileAll(ctx)
+
+	snapshots, err := impl.ListRollbackSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListRollbackSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Version != 1 || snapshots[1].Version != 2 {
+		t.Fatalf("ListRollbackSnapshots() = %+v, want versions 1 and 2", snapshots)
+	}
+
+	latest, err := impl.GetRollbackSnapshot(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetRollbackSnapshot(0) error = %v", err)
+	}
+	if latest.Version != 2 || len(latest.ManagedKeys) != 2 {
+		t.Errorf("GetRollbackSnapshot(0) = version %d with %d keys, want version 2 with 2 keys", latest.Version, len(latest.ManagedKeys))
+	}
+
+	first, err := impl.GetRollbackSnapshot(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetRollbackSnapshot(1) error = %v", err)
+	}
+	if !reflect.DeepEqual(first.ManagedKeys, []string{"default/ConfigMap/a"}) {
+		t.Errorf("GetRollbackSnapshot(1).ManagedKeys = %v, want [default/ConfigMap/a]", first.ManagedKeys)
+	}
+
+	if _, err := impl.GetRollbackSnapshot(ctx, 5); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetRollbackSnapshot(5) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReconciler_RollbackSnapshotSkipsUnchanged(t *testing.T) {
+	impl, manifestStore := setupRollbackTestReconciler(t)
+	ctx := context.Background()
+
+	if err := manifestStore.Create("default/ConfigMap/a", []byte(testConfigMapYAML("a"))); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		impl.reconcileAll(ctx)
+	}
+
+	snapshots, err := impl.ListRollbackSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListRollbackSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("ListRollbackSnapshots() = %d snapshots after unchanged reconciles, want 1", len(snapshots))
+	}
+}
+
+func TestReconciler_RollbackSnapshotRetention(t *testing.T) {
+	impl, manifestStore := setupRollbackTestReconciler(t)
+	WithRollbackRetention(2)(impl)
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if err := manifestStore.Create("default/ConfigMap/"+name, []byte(testConfigMapYAML(name))); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		impl.reconcileAll(ctx)
+	}
+
+	snapshots, err := impl.ListRollbackSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListRollbackSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Version != 3 || snapshots[1].Version != 4 {
+		t.Errorf("ListRollbackSnapshots() = %+v, want versions 3 and 4", snapshots)
+	}
+}
+
+func TestReconciler_RollbackRestoresManagedKeys(t *testing.T) {
+	impl, manifestStore := setupRollbackTestReconciler(t)
+	ctx := context.Background()
+
+	if err := manifestStore.Create("default/ConfigMap/a", []byte(testConfigMapYAML("a"))); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	impl.reconcileAll(ctx)
+
+	snapshot, err := impl.GetRollbackSnapshot(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetRollbackSnapshot() error = %v", err)
+	}
+
+	deploy := map[string][]byte{
+		"default/ConfigMap/a": []byte(testConfigMapYAML("a")),
+		"default/ConfigMap/b": []byte(testConfigMapYAML("b")),
+	}
+	if _, err := impl.DeployManifests(ctx, deploy); err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	if got := sortedManagedKeys(impl); len(got) != 2 {
+		t.Fatalf("managed keys after deploy = %v, want 2 keys", got)
+	}
+
+	if _, err := impl.DeployManifests(ctx, snapshot.Manifests); err != nil {
+		t.Fatalf("DeployManifests(snapshot) error = %v", err)
+	}
+	if got := sortedManagedKeys(impl); !reflect.DeepEqual(got, snapshot.ManagedKeys) {
+		t.Errorf("managed keys after rollback = %v, want %v", got, snapshot.ManagedKeys)
+	}
+}
+
+func TestReconciler_RollbackSnapshotsDisabled(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	ctx := context.Background()
+
+	snapshots, err := rec.ListRollbackSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListRollbackSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("ListRollbackSnapshots() = %d snapshots, want 0", len(snapshots))
+	}
+
+	if _, err := rec.GetRollbackSnapshot(ctx, 0); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetRollbackSnapshot() error = %v, want ErrNotFound", err)
+	}
+}
+
+func testConfigMapYAML(name string) string {
+	return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: default\ndata:\n  key: value\n"
+}
@@ -0,0 +1,110 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Test ReconcileSelected leaves unselected resources and managed keys untouched
+func TestReconciler_ReconcileSelected(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	ctx := context.Background()
+	impl := getReconcilerImpl(t, rec)
+	gvr := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
+
+	createConfigMap := func(name string) {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+			},
+		}
+		if _, err := impl.dynamicClient.Resource(gvr).Namespace("default").Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create resource %s: %v", name, err)
+		}
+	}
+	exists := func(name string) bool {
+		_, err := impl.dynamicClient.Resource(gvr).Namespace("default").Get(ctx, name, metav1.GetOptions{})
+		return err == nil
+	}
+
+	// Five manifests in the store, all deployed and managed
+	for i := 1; i <= 5; i++ {
+		name := fmt.Sprintf("cm%d", i)
+		key := "default/ConfigMap/" + name
+		manifest := []byte(fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n  namespace: default\n", name))
+		if err := impl.store.Create(key, manifest); err != nil {
+			t.Fatalf("Failed to create manifest: %v", err)
+		}
+		createConfigMap(name)
+		impl.setManaged(key)
+	}
+
+	// Two managed resources that are no longer in the store
+	createConfigMap("gone-selected")
+	impl.setManaged("default/ConfigMap/gone-selected")
+	createConfigMap("gone-other")
+	impl.setManaged("default/ConfigMap/gone-other")
+
+	err := rec.ReconcileSelected(ctx, []string{
+		"default/ConfigMap/cm1",
+		"default/ConfigMap/cm2",
+		"default/ConfigMap/gone-selected",
+	})
+	if err != nil {
+		// Apply may fail due to fake client limitations
+		t.Logf("ReconcileSelected() returned error (may be due to fake client limitations): %v", err)
+	}
+
+	// Unselected manifests stay deployed and managed
+	for i := 3; i <= 5; i++ {
+		name := fmt.Sprintf("cm%d", i)
+		if !exists(name) {
+			t.Errorf("ReconcileSelected() deleted unselected resource %s", name)
+		}
+		if !impl.isManaged("default/ConfigMap/" + name) {
+			t.Errorf("ReconcileSelected() changed managed status of unselected key %s", name)
+		}
+	}
+
+	// Orphans are only pruned within the selection
+	if !exists("gone-other") || !impl.isManaged("default/ConfigMap/gone-other") {
+		t.Error("ReconcileSelected() pruned an orphan outside the selection")
+	}
+	if exists("gone-selected") {
+		t.Error("ReconcileSelected() did not delete selected orphan")
+	}
+	if impl.isManaged("default/ConfigMap/gone-selected") {
+		t.Error("ReconcileSelected() did not remove managed status of selected orphan")
+	}
+
+	// Selected manifests remain managed
+	for _, key := range []string{"default/ConfigMap/cm1", "default/ConfigMap/cm2"} {
+		if !impl.isManaged(key) {
+			t.Errorf("ReconcileSelected() lost managed status of selected key %s", key)
+		}
+	}
+}
+
+// Test ReconcileSelected with an empty selection is a no-op
+func TestReconciler_ReconcileSelected_Empty(t *testing.T) {
+	rec := setupTestReconcilerForTests(t)
+	impl := getReconcilerImpl(t, rec)
+	impl.setManaged("default/ConfigMap/existing")
+
+	if err := rec.ReconcileSelected(context.Background(), nil); err != nil {
+		t.Fatalf("ReconcileSelected() error = %v", err)
+	}
+	if !impl.isManaged("default/ConfigMap/existing") {
+		t.Error("ReconcileSelected() with empty selection changed managed keys")
+	}
+}
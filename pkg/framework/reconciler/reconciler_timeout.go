@@ -0,0 +1,74 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// DeployTimeoutAnnotation overrides, per manifest, how long a single apply may take, e.g. "90s"
+const DeployTimeoutAnnotation = "service.conductor.io/deploy-timeout"
+
+// WithDeployTimeout sets how long a single apply may take when a manifest has no
+// deploy-timeout annotation. Zero means applies are not bounded.
+func WithDeployTimeout(timeout time.Duration) Option {
+	return func(r *reconcilerImpl) {
+		r.deployTimeout = timeout
+	}
+}
+
+// deployTimeoutFor returns the apply timeout of obj, read from its deploy-timeout
+// annotation and falling back to the reconciler default
+func (r *reconcilerImpl) deployTimeoutFor(obj runtime.Object, key string) time.Duration {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return r.deployTimeout
+	}
+
+	value, ok := accessor.GetAnnotations()[DeployTimeoutAnnotation]
+	if !ok {
+		return r.deployTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		r.logger.Info("ignoring invalid deploy timeout annotation", "key", key, "value", value)
+		return r.deployTimeout
+	}
+	return timeout
+}
+
+// applyObjectWithTimeout applies obj and gives up after timeout. The apply is detached from
+// the cancellation of ctx, so one in progress when ctx is canceled still completes, bounded
+// only by timeout. The error of a timed out apply wraps context.DeadlineExceeded; the apply
+// keeps running in the background until the client observes the expired context.
+func (r *reconcilerImpl) applyObjectWithTimeout(ctx context.Context, obj runtime.Object, key string, timeout time.Duration) error {
+	applyCtx := context.WithoutCancel(ctx)
+	if timeout <= 0 {
+		return r.applyObject(applyCtx, obj, key)
+	}
+
+	applyCtx, cancel := context.WithTimeout(applyCtx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.applyObject(applyCtx, obj, key)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && errors.Is(applyCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: apply %s timed out after %s: %w", apperrors.ErrReconciliation, key, timeout, context.DeadlineExceeded)
+		}
+		return err
+	case <-applyCtx.Done():
+		return fmt.Errorf("%w: apply %s timed out after %s: %w", apperrors.ErrReconciliation, key, timeout, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,136 @@
+package reconciler
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+)
+
+// setupSlowTestReconciler returns a reconciler whose applies of the named objects take delay
+func setupSlowTestReconciler(t *testing.T, delay time.Duration, slow ...string) *reconcilerImpl {
+	t.Helper()
+	logger := logr.Discard()
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme)
+
+	slowNames := make(map[string]bool)
+	for _, name := range slow {
+		slowNames[name] = true
+	}
+	dynamicClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		if slowNames[patchAction.GetName()] {
+			time.Sleep(delay)
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetName(patchAction.GetName())
+		return true, obj, nil
+	})
+
+	testDB, err := database.NewDB(filepath.Join(t.TempDir(), "test-timeout-db"), logger)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	t.Cleanup(func() {
+		// Let abandoned slow applies finish before the database closes
+		time.Sleep(delay)
+		testDB.Close()
+	})
+
+	manifestStore := store.NewManifestStore(testDB, index.NewIndex(), logger)
+	rec, err := NewReconciler(kubefake.NewSimpleClientset(), dynamicClient, manifestStore, logger, events.NewStorage(testDB, logger), "test-app", WithDeployTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create reconciler: %v", err)
+	}
+	return getReconcilerImpl(t, rec)
+}
+
+func timeoutConfigMap(name, timeout string) []byte {
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: default\n"
+	if timeout != "" {
+		manifest += "  annotations:\n    service.conductor.io/deploy-timeout: \"" + timeout + "\"\n"
+	}
+	return []byte(manifest)
+}
+
+func TestReconciler_DeployTimeoutRecordsPartialFailure(t *testing.T) {
+	impl := setupSlowTestReconciler(t, 300*time.Millisecond, "slow")
+
+	manifests := map[string][]byte{
+		"default/ConfigMap/slow": timeoutConfigMap("slow", "50ms"),
+		"default/ConfigMap/fast": timeoutConfigMap("fast", "10s"),
+		// Without the annotation the one minute default applies
+		"default/ConfigMap/default": timeoutConfigMap("default", ""),
+	}
+
+	result, err := impl.DeployManifests(context.Background(), manifests)
+	if err != nil {
+		t.Fatalf("DeployManifests() error = %v", err)
+	}
+	if result.TimedOutCount != 1 {
+		t.Errorf("TimedOutCount = %d, want 1", result.TimedOutCount)
+	}
+	if result.FailedCount != 1 {
+		t.Errorf("FailedCount = %d, want 1", result.FailedCount)
+	}
+	if result.AppliedCount != 2 {
+		t.Errorf("AppliedCount = %d, want 2", result.AppliedCount)
+	}
+
+	errorEvents, err := impl.eventStore.GetEventsByResource("default/ConfigMap/slow", events.EventFilters{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetEventsByResource() error = %v", err)
+	}
+	found := false
+	for _, event := range errorEvents {
+		if event.Type == events.EventTypeError && strings.Contains(event.Message, "timed out") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no timeout event recorded for the slow resource, got %v", errorEvents)
+	}
+}
+
+func TestReconciler_DeployTimeoutFor(t *testing.T) {
+	impl := setupSlowTestReconciler(t, 0)
+
+	tests := []struct {
+		name       string
+		annotation string
+		want       time.Duration
+	}{
+		{name: "annotation", annotation: "90s", want: 90 * time.Second},
+		{name: "no annotation uses default", want: time.Minute},
+		{name: "invalid annotation uses default", annotation: "soon", want: time.Minute},
+		{name: "negative annotation uses default", annotation: "-5s", want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := impl.parseYAML(context.Background(), timeoutConfigMap("cm", tt.annotation), "default/ConfigMap/cm")
+			if err != nil {
+				t.Fatalf("parseYAML() error = %v", err)
+			}
+			if got := impl.deployTimeoutFor(obj, "default/ConfigMap/cm"); got != tt.want {
+				t.Errorf("deployTimeoutFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// applyTracker wraps a dynamic client to record applies. The fake dynamic client serializes its
+// reactors, so the delay runs in the wrapper to let concurrent applies overlap.
+type applyTracker struct {
+	dynamic.Interface
+	delay time.Duration
+	// onApply, when set, runs at the start of every apply
+	onApply func()
+
+	mu     sync.Mutex
+	active int
+	peak   int
+	order  []string
+}
+
+// before records the apply of name, waits for the delay and rejects names starting with "bad"
+func (a *applyTracker) before(name string) error {
+	a.mu.Lock()
+	a.active++
+	if a.active > a.peak {
+		a.peak = a.active
+	}
+	a.mu.Unlock()
+
+	if a.onApply != nil {
+		a.onApply()
+	}
+	time.Sleep(a.delay)
+
+	a.mu.Lock()
+	a.active--
+	a.order = append(a.order, name)
+	a.mu.Unlock()
+
+	if strings.HasPrefix(name, "bad") {
+		return fmt.Errorf("apply of %s rejected", name)
+	}
+	return nil
+}
+
+func (a *applyTracker) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &trackedResource{NamespaceableResourceInterface: a.Interface.Resource(gvr), tracker: a}
+}
+
+type trackedResource struct {
+	dynamic.NamespaceableResourceInterface
+	tracker *applyTracker
+}
+
+func (r *trackedResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &trackedNamespacedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(namespace), tracker: r.tracker}
+}
+
+type trackedNamespacedResource struct {
+	dynamic.ResourceInterface
+	tracker *applyTracker
+}
+
+func (r *trackedNamespacedResource) Apply(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if err := r.tracker.before(name); err != nil {
+		return nil, err
+	}
+	// Like a real client, an apply whose context is done fails
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.Apply(ctx, name, obj, options, subresources...)
+}
+
+func setupWorkerTestReconciler(t *testing.T, workers int, delay time.Duration) (*reconcilerImpl, *applyTracker) {
+	t.Helper()
+	impl := setupSlowTestReconciler(t, 0)
+	tracker := &applyTracker{Interface: impl.dynamicClient, delay: delay}
+	impl.dynamicClient = tracker
+	impl.workers = workers
+	return impl, tracker
+}
+
+func TestReconciler_WorkerPool(t *testing.T) {
+	impl, tracker := setupWorkerTestReconciler(t, 3, 20*time.Millisecond)
+
+	manifests := make(map[string][]byte)
+	for i := 0; i < 30; i++ {
+		name := fmt.Sprintf("config-%02d", i)
+		if i%10 == 0 {
+			name = fmt.Sprintf("bad-%02d", i)
+		}
+		manifests["default/ConfigMap/"+name] = timeoutConfigMap(name, "")
+	}
+
+	result, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+
+	if result.AppliedCount != 27 || result.FailedCount != 3 {
+		t.Errorf("reconcile() applied %d and failed %d, want 27 applied and 3 failed", result.AppliedCount, result.FailedCount)
+	}
+	if len(tracker.order) != 30 {
+		t.Errorf("reconcile() applied %d manifests, want all 30", len(tracker.order))
+	}
+	if tracker.peak > 3 || tracker.peak < 2 {
+		t.Errorf("reconcile() ran %d applies at once, want between 2 and the 3 workers", tracker.peak)
+	}
+}
+
+func TestReconciler_WorkerPoolCompletesPriorityGroups(t *testing.T) {
+	impl, tracker := setupWorkerTestReconciler(t, 4, 5*time.Millisecond)
+
+	manifests := make(map[string][]byte)
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("config-%d", i)
+		manifests["default/ConfigMap/"+name] = timeoutConfigMap(name, "")
+		deployment := fmt.Sprintf("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app-%d\n  namespace: default\n", i)
+		manifests[fmt.Sprintf("default/Deployment/app-%d", i)] = []byte(deployment)
+	}
+
+	result, err := impl.reconcile(context.Background(), manifests, map[string]bool{})
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if result.AppliedCount != 12 {
+		t.Fatalf("reconcile() applied %d manifests, want 12: %+v", result.AppliedCount, result)
+	}
+	for i, name := range tracker.order {
+		if i < 6 && !strings.HasPrefix(name, "config-") {
+			t.Fatalf("apply order = %v, want all ConfigMaps before the Deployments", tracker.order)
+		}
+	}
+}
+
+func TestWithWorkers(t *testing.T) {
+	impl := &reconcilerImpl{workers: DefaultWorkers}
+
+	WithWorkers(8)(impl)
+	if impl.workers != 8 {
+		t.Errorf("WithWorkers(8) workers = %d, want 8", impl.workers)
+	}
+	WithWorkers(0)(impl)
+	if impl.workers != 8 {
+		t.Errorf("WithWorkers(0) workers = %d, want the previous 8 kept", impl.workers)
+	}
+}
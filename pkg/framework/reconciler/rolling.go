@@ -0,0 +1,378 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/events"
+)
+
+// RolloutKeyPrefix is the database key prefix under which the state of each StatefulSet
+// rolling update in progress is stored
+const RolloutKeyPrefix = "rollout/"
+
+// DefaultRollingUpdateTimeout bounds how long a rolling update waits for one batch of pods
+const DefaultRollingUpdateTimeout = 10 * time.Minute
+
+// revisionLabel names the StatefulSet revision a pod was created from
+const revisionLabel = "controller-revision-hash"
+
+// RolloutState is the progress of a partitioned StatefulSet rolling update: pods with an
+// ordinal at or above Partition run the new revision. A Failed rollout is no longer advanced,
+// resumed or applied; Error says why it stopped.
+type RolloutState struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+	Partition int32  `json:"partition"`
+	BatchSize int32  `json:"batchSize"`
+	Failed    bool   `json:"failed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RollingUpdater moves StatefulSet updates through their pods a batch at a time by lowering
+// spec.updateStrategy.rollingUpdate.partition once the pods of the previous batch are Ready.
+// Its state survives restarts in the database, so an interrupted rollout resumes where it stopped.
+type RollingUpdater struct {
+	clientset kubernetes.Interface
+	db        *database.DB
+	timeout   time.Duration
+	logger    logr.Logger
+}
+
+// NewRollingUpdater returns a RollingUpdater that waits up to timeout for each batch; zero waits indefinitely
+func NewRollingUpdater(clientset kubernetes.Interface, db *database.DB, timeout time.Duration, logger logr.Logger) *RollingUpdater {
+	return &RollingUpdater{clientset: clientset, db: db, timeout: timeout, logger: logger}
+}
+
+func rolloutKey(key string) string {
+	return RolloutKeyPrefix + key
+}
+
+// Start records a rollout of the StatefulSet stored under key whose first batch updates the
+// last batchSize pods
+func (u *RollingUpdater) Start(key, namespace, name string, replicas, batchSize int32) (RolloutState, error) {
+	if batchSize < 1 {
+		return RolloutState{}, fmt.Errorf("%w: batch size must be at least 1", apperrors.ErrInvalid)
+	}
+	state := RolloutState{
+		Namespace: namespace,
+		Name:      name,
+		Replicas:  replicas,
+		Partition: max(replicas-batchSize, 0),
+		BatchSize: batchSize,
+	}
+	return state, u.save(key, state)
+}
+
+// State returns the rollout in progress for key
+func (u *RollingUpdater) State(key string) (RolloutState, bool) {
+	data, err := u.db.Get(rolloutKey(key))
+	if err != nil {
+		return RolloutState{}, false
+	}
+	var state RolloutState
+	if err := json.Unmarshal(data, &state); err != nil {
+		u.logger.Error(err, "ignoring unreadable rollout state", "key", key)
+		return RolloutState{}, false
+	}
+	return state, true
+}
+
+// Pending returns the keys of the rollouts that have neither completed nor failed, e.g. because
+// the process stopped while they ran
+func (u *RollingUpdater) Pending() ([]string, error) {
+	items, err := u.db.List(RolloutKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(items))
+	for dbKey, data := range items {
+		var state RolloutState
+		if err := json.Unmarshal(data, &state); err != nil {
+			u.logger.Error(err, "ignoring unreadable rollout state", "key", dbKey)
+			continue
+		}
+		if !state.Failed {
+			keys = append(keys, strings.TrimPrefix(dbKey, RolloutKeyPrefix))
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (u *RollingUpdater) save(key string, state RolloutState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("%w: encode rollout state %s: %w", apperrors.ErrStorage, key, err)
+	}
+	return u.db.Set(rolloutKey(key), data)
+}
+
+// Run waits for each batch of the rollout of key to become Ready and then lowers the
+// partition to release the next one, until every pod runs the new revision. A rollout that
+// stops because ctx is done is kept to be resumed; one that stops for any other reason is
+// marked failed.
+func (u *RollingUpdater) Run(ctx context.Context, key string) error {
+	state, ok := u.State(key)
+	if !ok {
+		return fmt.Errorf("%w: no rolling update in progress for %s", apperrors.ErrNotFound, key)
+	}
+	if state.Failed {
+		return fmt.Errorf("%w: rolling update %s failed: %s", apperrors.ErrInvalid, key, state.Error)
+	}
+
+	err := u.run(ctx, key, state)
+	if err != nil && ctx.Err() == nil {
+		if state, ok := u.State(key); ok {
+			state.Failed = true
+			state.Error = err.Error()
+			if saveErr := u.save(key, state); saveErr != nil {
+				u.logger.Error(saveErr, "failed to mark rolling update failed", "key", key)
+			}
+		}
+	}
+	return err
+}
+
+func (u *RollingUpdater) run(ctx context.Context, key string, state RolloutState) error {
+	for {
+		if err := u.waitForBatch(ctx, state); err != nil {
+			return fmt.Errorf("rolling update %s at partition %d: %w", key, state.Partition, err)
+		}
+		if state.Partition == 0 {
+			if err := u.db.Delete(rolloutKey(key)); err != nil {
+				return err
+			}
+			u.logger.Info("Rolling update complete", "key", key)
+			return nil
+		}
+
+		state.Partition = max(state.Partition-state.BatchSize, 0)
+		if err := u.save(key, state); err != nil {
+			return err
+		}
+		if err := u.setPartition(ctx, state); err != nil {
+			return fmt.Errorf("rolling update %s: %w", key, err)
+		}
+		u.logger.Info("Rolling update advanced", "key", key, "partition", state.Partition)
+	}
+}
+
+func (u *RollingUpdater) setPartition(ctx context.Context, state RolloutState) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{
+				"type":          "RollingUpdate",
+				"rollingUpdate": map[string]interface{}{"partition": state.Partition},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = u.clientset.AppsV1().StatefulSets(state.Namespace).Patch(ctx, state.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("%w: kubernetes patch statefulset %s/%s partition: %w", apperrors.ErrKubernetes, state.Namespace, state.Name, err)
+	}
+	return nil
+}
+
+// waitForBatch polls until every pod at or above the partition runs the update revision and is Ready
+func (u *RollingUpdater) waitForBatch(ctx context.Context, state RolloutState) error {
+	if u.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+	for {
+		pending, err := u.pendingPod(ctx, state)
+		if err != nil {
+			return err
+		}
+		if pending == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("pod %s not ready: %w", pending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// pendingPod returns the name of the first pod of the updated batch that is not yet Ready on
+// the update revision, or "" when there is none
+func (u *RollingUpdater) pendingPod(ctx context.Context, state RolloutState) (string, error) {
+	statefulSet, err := u.clientset.AppsV1().StatefulSets(state.Namespace).Get(ctx, state.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) || ctx.Err() != nil {
+			return state.Name, nil
+		}
+		return "", fmt.Errorf("%w: kubernetes get statefulset %s/%s: %w", apperrors.ErrKubernetes, state.Namespace, state.Name, err)
+	}
+
+	for ordinal := state.Partition; ordinal < state.Replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", state.Name, ordinal)
+		pod, err := u.clientset.CoreV1().Pods(state.Namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) || ctx.Err() != nil {
+				return podName, nil
+			}
+			return "", fmt.Errorf("%w: kubernetes get pod %s/%s: %w", apperrors.ErrKubernetes, state.Namespace, podName, err)
+		}
+		revision := statefulSet.Status.UpdateRevision
+		if revision != "" && pod.Labels[revisionLabel] != revision {
+			return podName, nil
+		}
+		if !podReady(pod) {
+			return podName, nil
+		}
+	}
+	return "", nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// WithRolloutDB enables partitioned rolling updates of StatefulSets, persisting their progress in db
+func WithRolloutDB(db *database.DB) Option {
+	return func(r *reconcilerImpl) {
+		r.rolloutDB = db
+	}
+}
+
+// WithRollingUpdateTimeout bounds how long a rolling update waits for each batch of pods
+func WithRollingUpdateTimeout(timeout time.Duration) Option {
+	return func(r *reconcilerImpl) {
+		r.rollingUpdateTimeout = timeout
+	}
+}
+
+// statefulSetDocument is the part of a manifest a rolling update reads
+type statefulSetDocument struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Replicas *int32 `yaml:"replicas"`
+	} `yaml:"spec"`
+}
+
+// StartRollingUpdate updates manifests like UpdateManifests, except that each StatefulSet
+// among them is applied with a partition that updates only its last batchSize pods. It
+// returns the keys of those StatefulSets, whose rollouts CompleteRollingUpdate finishes.
+func (r *reconcilerImpl) StartRollingUpdate(ctx context.Context, manifests map[string][]byte, batchSize int) (ReconciliationResult, []string, error) {
+	if r.rollouts == nil {
+		return ReconciliationResult{}, nil, fmt.Errorf("%w: rolling updates are not enabled", apperrors.ErrInvalid)
+	}
+	if batchSize < 1 {
+		return ReconciliationResult{}, nil, fmt.Errorf("%w: batch size must be at least 1", apperrors.ErrInvalid)
+	}
+
+	var keys []string
+	for key, content := range manifests {
+		var doc statefulSetDocument
+		if err := yaml.Unmarshal(content, &doc); err != nil || doc.Kind != "StatefulSet" {
+			continue
+		}
+		namespace := doc.Metadata.Namespace
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		if _, err := r.rollouts.Start(key, namespace, doc.Metadata.Name, desiredReplicas(doc.Spec.Replicas), int32(batchSize)); err != nil {
+			return ReconciliationResult{}, nil, err
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result, err := r.UpdateManifests(ctx, manifests)
+	if err != nil {
+		return result, nil, err
+	}
+	return result, keys, nil
+}
+
+// CompleteRollingUpdate releases the remaining batches of the StatefulSet rollout of key
+func (r *reconcilerImpl) CompleteRollingUpdate(ctx context.Context, key string) error {
+	if r.rollouts == nil {
+		return fmt.Errorf("%w: rolling updates are not enabled", apperrors.ErrInvalid)
+	}
+	if err := r.rollouts.Run(ctx, key); err != nil {
+		events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Error(key, "update", "Rolling update stopped", err))
+		return err
+	}
+	events.StoreEventSafeContext(ctx, r.eventStore, r.logger, events.Success(key, "update", "Rolling update complete"))
+	return nil
+}
+
+// ResumeRollingUpdates completes the rollouts that were still in progress when the process
+// last stopped, one at a time
+func (r *reconcilerImpl) ResumeRollingUpdates(ctx context.Context) error {
+	if r.rollouts == nil {
+		return nil
+	}
+	keys, err := r.rollouts.Pending()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		r.logger.Info("Resuming rolling update", "key", key)
+		if err := r.CompleteRollingUpdate(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// injectRolloutPartition applies the partition of the rollout in progress for resourceKey, so
+// that applies during the rollout do not release pods it has not reached yet. Failed rollouts
+// are not applied, so the next apply releases the remaining pods.
+func (r *reconcilerImpl) injectRolloutPartition(obj *unstructured.Unstructured, resourceKey string) {
+	if r.rollouts == nil || obj.GetKind() != "StatefulSet" {
+		return
+	}
+	state, ok := r.rollouts.State(resourceKey)
+	if !ok || state.Failed {
+		return
+	}
+	if err := unstructured.SetNestedField(obj.Object, "RollingUpdate", "spec", "updateStrategy", "type"); err != nil {
+		r.logger.Error(err, "failed to set rolling update strategy", "key", resourceKey)
+		return
+	}
+	if err := unstructured.SetNestedField(obj.Object, int64(state.Partition), "spec", "updateStrategy", "rollingUpdate", "partition"); err != nil {
+		r.logger.Error(err, "failed to set rolling update partition", "key", resourceKey)
+	}
+}
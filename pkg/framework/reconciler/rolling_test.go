@@ -0,0 +1,281 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+)
+
+const rollingTestKey = "default/StatefulSet/db"
+
+func rollingTestPod(ordinal int, revision string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("db-%d", ordinal),
+			Namespace: "default",
+			Labels:    map[string]string{revisionLabel: revision},
+		},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+// newRollingTestClientset returns a clientset holding a 3-replica StatefulSet whose last pod
+// already runs rev2, and which moves the pods at or above each patched partition to rev2 the
+// way the StatefulSet controller would. The returned function lists the patched partitions.
+func newRollingTestClientset(t *testing.T) (*kubefake.Clientset, func() []int32) {
+	t.Helper()
+	replicas := int32(3)
+	clientset := kubefake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{UpdateRevision: "rev2"},
+		},
+		rollingTestPod(0, "rev1", true),
+		rollingTestPod(1, "rev1", true),
+		rollingTestPod(2, "rev2", true),
+	)
+
+	var mu sync.Mutex
+	var partitions []int32
+	clientset.PrependReactor("patch", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		var patch struct {
+			Spec struct {
+				UpdateStrategy struct {
+					RollingUpdate struct {
+						Partition int32 `json:"partition"`
+					} `json:"rollingUpdate"`
+				} `json:"updateStrategy"`
+			} `json:"spec"`
+		}
+		if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &patch); err != nil {
+			return true, nil, err
+		}
+		partition := patch.Spec.UpdateStrategy.RollingUpdate.Partition
+		mu.Lock()
+		partitions = append(partitions, partition)
+		mu.Unlock()
+		for ordinal := int(partition); ordinal < int(replicas); ordinal++ {
+			pod := rollingTestPod(ordinal, "rev2", true)
+			if err := clientset.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), pod, "default"); err != nil {
+				return true, nil, err
+			}
+		}
+		return false, nil, nil
+	})
+
+	return clientset, func() []int32 {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int32(nil), partitions...)
+	}
+}
+
+func newRollingTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	return db
+}
+
+func TestRollingUpdater_DecrementsPartition(t *testing.T) {
+	useFastWaitPolling(t)
+	clientset, partitions := newRollingTestClientset(t)
+	updater := NewRollingUpdater(clientset, newRollingTestDB(t), 5*time.Second, logr.Discard())
+
+	state, err := updater.Start(rollingTestKey, "default", "db", 3, 1)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if state.Partition != 2 {
+		t.Errorf("Start() partition = %d, want 2", state.Partition)
+	}
+	if stored, ok := updater.State(rollingTestKey); !ok || stored.Partition != 2 {
+		t.Errorf("State() = %+v, %v, want partition 2", stored, ok)
+	}
+
+	if err := updater.Run(context.Background(), rollingTestKey); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := partitions(); !reflect.DeepEqual(got, []int32{1, 0}) {
+		t.Errorf("patched partitions = %v, want [1 0]", got)
+	}
+	if _, ok := updater.State(rollingTestKey); ok {
+		t.Error("State() still has the rollout after it completed")
+	}
+}
+
+func TestRollingUpdater_BatchSize(t *testing.T) {
+	useFastWaitPolling(t)
+	clientset, partitions := newRollingTestClientset(t)
+	updater := NewRollingUpdater(clientset, newRollingTestDB(t), 5*time.Second, logr.Discard())
+
+	if _, err := updater.Start(rollingTestKey, "default", "db", 3, 2); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	// The first batch of two pods includes db-1, which the controller has not updated yet
+	pod := rollingTestPod(1, "rev2", true)
+	if err := clientset.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), pod, "default"); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+
+	if err := updater.Run(context.Background(), rollingTestKey); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := partitions(); !reflect.DeepEqual(got, []int32{0}) {
+		t.Errorf("patched partitions = %v, want [0]", got)
+	}
+}
+
+func TestRollingUpdater_WaitsForReadyPod(t *testing.T) {
+	useFastWaitPolling(t)
+	clientset, partitions := newRollingTestClientset(t)
+	if err := clientset.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), rollingTestPod(2, "rev2", false), "default"); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+	updater := NewRollingUpdater(clientset, newRollingTestDB(t), 50*time.Millisecond, logr.Discard())
+
+	if _, err := updater.Start(rollingTestKey, "default", "db", 3, 1); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := updater.Run(context.Background(), rollingTestKey); err == nil {
+		t.Fatal("Run() error = nil, want a timeout while db-2 is not ready")
+	}
+	if got := partitions(); len(got) != 0 {
+		t.Errorf("patched partitions = %v, want none before db-2 is ready", got)
+	}
+	if state, ok := updater.State(rollingTestKey); !ok || !state.Failed || state.Partition != 2 {
+		t.Errorf("State() = %+v, %v, want the rollout marked failed at partition 2", state, ok)
+	}
+	if pending, err := updater.Pending(); err != nil || len(pending) != 0 {
+		t.Errorf("Pending() = %v, %v, want no pending rollouts", pending, err)
+	}
+	if err := updater.Run(context.Background(), rollingTestKey); err == nil {
+		t.Error("Run() of a failed rollout error = nil, want an error")
+	}
+}
+
+func TestRollingUpdater_KeepsInterruptedRollout(t *testing.T) {
+	useFastWaitPolling(t)
+	clientset, _ := newRollingTestClientset(t)
+	if err := clientset.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), rollingTestPod(2, "rev2", false), "default"); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+	updater := NewRollingUpdater(clientset, newRollingTestDB(t), 0, logr.Discard())
+
+	if _, err := updater.Start(rollingTestKey, "default", "db", 3, 1); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := updater.Run(ctx, rollingTestKey); err == nil {
+		t.Fatal("Run() error = nil, want the context error")
+	}
+	if state, ok := updater.State(rollingTestKey); !ok || state.Failed {
+		t.Errorf("State() = %+v, %v, want the rollout kept to resume", state, ok)
+	}
+	if pending, err := updater.Pending(); err != nil || !reflect.DeepEqual(pending, []string{rollingTestKey}) {
+		t.Errorf("Pending() = %v, %v, want [%s]", pending, err, rollingTestKey)
+	}
+}
+
+func TestReconciler_ResumeRollingUpdates(t *testing.T) {
+	useFastWaitPolling(t)
+	clientset, partitions := newRollingTestClientset(t)
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	rec.rollouts = NewRollingUpdater(clientset, newRollingTestDB(t), 5*time.Second, logr.Discard())
+
+	if _, err := rec.rollouts.Start(rollingTestKey, "default", "db", 3, 1); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := rec.ResumeRollingUpdates(context.Background()); err != nil {
+		t.Fatalf("ResumeRollingUpdates() error = %v", err)
+	}
+	if got := partitions(); !reflect.DeepEqual(got, []int32{1, 0}) {
+		t.Errorf("patched partitions = %v, want [1 0]", got)
+	}
+	if _, ok := rec.rollouts.State(rollingTestKey); ok {
+		t.Error("State() still has the resumed rollout after it completed")
+	}
+}
+
+func TestInjectRolloutPartition_SkipsFailedRollout(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	rec.rollouts = NewRollingUpdater(rec.clientset, newRollingTestDB(t), time.Second, logr.Discard())
+	if err := rec.rollouts.save(rollingTestKey, RolloutState{Namespace: "default", Name: "db", Replicas: 3, Partition: 2, BatchSize: 1, Failed: true}); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "StatefulSet"}}
+	rec.injectRolloutPartition(obj, rollingTestKey)
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "updateStrategy"); found {
+		t.Errorf("injectRolloutPartition() set %v for a failed rollout, want nothing", obj.Object["spec"])
+	}
+}
+
+func TestStartRollingUpdate_AppliesPartition(t *testing.T) {
+	rec := getReconcilerImpl(t, setupTestReconcilerForTests(t))
+	rec.rollouts = NewRollingUpdater(rec.clientset, newRollingTestDB(t), time.Second, logr.Discard())
+
+	var mu sync.Mutex
+	var applied *unstructured.Unstructured
+	rec.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		mu.Lock()
+		applied = obj
+		mu.Unlock()
+		return true, obj, nil
+	})
+
+	manifests := map[string][]byte{rollingTestKey: []byte(`apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: default
+spec:
+  replicas: 3
+`)}
+	_, keys, err := rec.StartRollingUpdate(context.Background(), manifests, 1)
+	if err != nil {
+		t.Fatalf("StartRollingUpdate() error = %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{rollingTestKey}) {
+		t.Errorf("StartRollingUpdate() keys = %v, want [%s]", keys, rollingTestKey)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if applied == nil {
+		t.Fatal("StartRollingUpdate() did not apply the StatefulSet")
+	}
+	// Numbers decoded from the applied JSON are float64
+	partition, found, _ := unstructured.NestedFloat64(applied.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if !found || partition != 2 {
+		t.Errorf("applied partition = %v (found %v), want 2", partition, found)
+	}
+}
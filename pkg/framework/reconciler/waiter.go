@@ -0,0 +1,152 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// waitPollInterval is how often WaitForReady checks the workloads it waits for
+var waitPollInterval = 2 * time.Second
+
+// WaitForReady polls the Deployments and StatefulSets among keys (namespace/Kind/name) until
+// all of their replicas are ready. Keys of other kinds are ignored. A workload that does not
+// exist yet counts as not ready. When ctx ends first, the error names the workloads still
+// pending and wraps the context error.
+func WaitForReady(ctx context.Context, keys []string, clientset kubernetes.Interface) error {
+	pending := make(map[string]struct{})
+	for _, key := range keys {
+		if _, kind, _, ok := splitWorkloadKey(key); ok && (kind == "Deployment" || kind == "StatefulSet") {
+			pending[key] = struct{}{}
+		}
+	}
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+	for {
+		for key := range pending {
+			ready, err := workloadReady(ctx, clientset, key)
+			if err != nil {
+				return err
+			}
+			if ready {
+				delete(pending, key)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("workloads not ready: %s: %w", strings.Join(sortedSet(pending), ", "), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// workloadReady reports whether the Deployment or StatefulSet stored under key has all replicas ready
+func workloadReady(ctx context.Context, clientset kubernetes.Interface, key string) (bool, error) {
+	namespace, kind, name, _ := splitWorkloadKey(key)
+
+	switch kind {
+	case "Deployment":
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return workloadGetError(ctx, key, err)
+		}
+		return deploymentReady(deployment), nil
+	case "StatefulSet":
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return workloadGetError(ctx, key, err)
+		}
+		return statefulSetReady(statefulSet), nil
+	}
+	return true, nil
+}
+
+// workloadGetError treats missing workloads and cancelled polls as not ready yet
+func workloadGetError(ctx context.Context, key string, err error) (bool, error) {
+	if k8serrors.IsNotFound(err) || ctx.Err() != nil {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to get %s: %w", key, err)
+}
+
+// deploymentReady reports whether a Deployment has rolled out its current spec: the controller
+// observed the latest generation, every replica runs the new template with no old replica
+// left, all of them are ready and the Deployment is Available. Without the generation and
+// updated replica checks, the status of the previous rollout would count as ready.
+func deploymentReady(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	desired := desiredReplicas(deployment.Spec.Replicas)
+	status := deployment.Status
+	if status.UpdatedReplicas != desired || status.Replicas != status.UpdatedReplicas || status.ReadyReplicas < desired {
+		return false
+	}
+	for _, condition := range status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// statefulSetReady reports whether a StatefulSet has rolled out its current spec: the
+// controller observed the latest generation, every desired replica is ready and, for rolling
+// updates, the replicas at or above the partition run the new revision
+func statefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return false
+	}
+	desired := desiredReplicas(statefulSet.Spec.Replicas)
+	if statefulSet.Status.ReadyReplicas < desired {
+		return false
+	}
+
+	strategy := statefulSet.Spec.UpdateStrategy
+	if strategy.Type != "" && strategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return true
+	}
+	var partition int32
+	if strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil {
+		partition = *strategy.RollingUpdate.Partition
+	}
+	return statefulSet.Status.UpdatedReplicas >= desired-partition
+}
+
+// desiredReplicas returns the replica count of a workload spec, which defaults to 1
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func splitWorkloadKey(key string) (namespace, kind, name string, ok bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+func sortedSet(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
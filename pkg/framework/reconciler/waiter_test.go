@@ -0,0 +1,177 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func useFastWaitPolling(t *testing.T) {
+	t.Helper()
+	previous := waitPollInterval
+	waitPollInterval = time.Millisecond
+	t.Cleanup(func() { waitPollInterval = previous })
+}
+
+func testDeployment(replicas, ready int32, available bool) *appsv1.Deployment {
+	status := corev1.ConditionFalse
+	if available {
+		status = corev1.ConditionTrue
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			Replicas:        replicas,
+			UpdatedReplicas: replicas,
+			ReadyReplicas:   ready,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: status},
+			},
+		},
+	}
+}
+
+func TestWaitForReady_DeploymentBecomesReady(t *testing.T) {
+	useFastWaitPolling(t)
+
+	clientset := kubefake.NewSimpleClientset()
+	var gets atomic.Int32
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if gets.Add(1) < 3 {
+			return true, testDeployment(2, 1, false), nil
+		}
+		return true, testDeployment(2, 2, true), nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitForReady(ctx, []string{"default/Deployment/web", "default/Service/web"}, clientset); err != nil {
+		t.Fatalf("WaitForReady() error = %v", err)
+	}
+	if got := gets.Load(); got != 3 {
+		t.Errorf("Deployment fetched %d times, want 3", got)
+	}
+}
+
+func TestWaitForReady_StatefulSet(t *testing.T) {
+	useFastWaitPolling(t)
+
+	replicas := int32(3)
+	clientset := kubefake.NewSimpleClientset(&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 3},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitForReady(ctx, []string{"default/StatefulSet/db"}, clientset); err != nil {
+		t.Fatalf("WaitForReady() error = %v", err)
+	}
+}
+
+func TestWaitForReady_Timeout(t *testing.T) {
+	useFastWaitPolling(t)
+
+	clientset := kubefake.NewSimpleClientset(testDeployment(2, 1, true))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := WaitForReady(ctx, []string{"default/Deployment/web", "default/Deployment/missing"}, clientset)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForReady() error = %v, want context.DeadlineExceeded", err)
+	}
+	for _, key := range []string{"default/Deployment/web", "default/Deployment/missing"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("WaitForReady() error = %q, want it to name %s", err, key)
+		}
+	}
+}
+
+func TestWaitForReady_NoWorkloads(t *testing.T) {
+	if err := WaitForReady(context.Background(), []string{"default/Service/web", "default/ConfigMap/cfg"}, kubefake.NewSimpleClientset()); err != nil {
+		t.Errorf("WaitForReady() error = %v, want nil", err)
+	}
+}
+
+func TestDeploymentReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		want       bool
+	}{
+		{name: "available with all replicas", deployment: testDeployment(2, 2, true), want: true},
+		{name: "available with missing replicas", deployment: testDeployment(2, 1, true), want: false},
+		{name: "replicas ready but not available", deployment: testDeployment(1, 1, false), want: false},
+		{name: "new generation not observed yet", deployment: func() *appsv1.Deployment {
+			d := testDeployment(2, 2, true)
+			d.Generation, d.Status.ObservedGeneration = 3, 2
+			return d
+		}(), want: false},
+		{name: "old replicas still running", deployment: func() *appsv1.Deployment {
+			d := testDeployment(2, 2, true)
+			d.Status.UpdatedReplicas, d.Status.Replicas = 1, 3
+			return d
+		}(), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deploymentReady(tt.deployment); got != tt.want {
+				t.Errorf("deploymentReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatefulSetReady(t *testing.T) {
+	statefulSet := func(updated int32, partition *int32) *appsv1.StatefulSet {
+		replicas := int32(3)
+		s := &appsv1.StatefulSet{
+			Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: updated},
+		}
+		if partition != nil {
+			s.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: partition},
+			}
+		}
+		return s
+	}
+	two := int32(2)
+
+	tests := []struct {
+		name        string
+		statefulSet *appsv1.StatefulSet
+		want        bool
+	}{
+		{name: "all replicas updated", statefulSet: statefulSet(3, nil), want: true},
+		{name: "rolling update in progress", statefulSet: statefulSet(1, nil), want: false},
+		{name: "replicas above the partition updated", statefulSet: statefulSet(1, &two), want: true},
+		{name: "new generation not observed yet", statefulSet: func() *appsv1.StatefulSet {
+			s := statefulSet(3, nil)
+			s.Generation = 2
+			return s
+		}(), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statefulSetReady(tt.statefulSet); got != tt.want {
+				t.Errorf("statefulSetReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
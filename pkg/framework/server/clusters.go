@@ -0,0 +1,91 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+)
+
+// ClusterConfig selects the kubeconfig file and context of a named cluster
+type ClusterConfig struct {
+	KubeconfigPath string // Optional; empty uses in-cluster config or the default kubeconfig
+	Context        string // Optional; empty uses the current context of the kubeconfig
+}
+
+// newClusterClients creates the Kubernetes clientset and dynamic client of a named cluster
+func newClusterClients(name string, cluster ClusterConfig, logger logr.Logger) (kubernetes.Interface, dynamic.Interface, error) {
+	logger.Info("Setting up Kubernetes client", "cluster", name, "kubeconfig", cluster.KubeconfigPath, "context", cluster.Context)
+	kubeConfig, err := reconciler.GetKubernetesConfigForCluster(cluster.KubeconfigPath, cluster.Context)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes config for cluster %s: %w", name, err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Kubernetes clientset for cluster %s: %w", name, err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create dynamic client for cluster %s: %w", name, err)
+	}
+
+	return clientset, dynamicClient, nil
+}
+
+// newClusterReconcilers wraps the default reconciler and one reconciler per additional cluster.
+// Additional clusters share the manifest and event stores and keep their rollback history,
+// rollouts, managed keys and settings in the database under clusterDBPrefix(name).
+// Manifest conditions on every cluster are evaluated against the parameters of parameterGetter.
+func newClusterReconcilers(cfg *Config, logger logr.Logger, storage *StorageComponents, appName string, defaultRec reconciler.Reconciler, parameterGetter manifest.ParameterGetter) (*reconciler.MultiReconciler, error) {
+	reconcilers := map[string]reconciler.Reconciler{reconciler.DefaultClusterName: defaultRec}
+	for name, cluster := range cfg.Clusters {
+		if name == reconciler.DefaultClusterName {
+			continue
+		}
+
+		clientset, dynamicClient, err := newClusterClients(name, cluster, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		db := storage.DB.WithPrefix(clusterDBPrefix(name))
+		rec, err := reconciler.NewReconciler(
+			clientset,
+			dynamicClient,
+			storage.ManifestStore,
+			logger.WithValues("cluster", name),
+			storage.EventStore,
+			appName,
+			reconciler.WithRollbackDB(db),
+			reconciler.WithRollbackRetention(cfg.RollbackRetention),
+			reconciler.WithRolloutDB(db),
+			reconciler.WithRollingUpdateTimeout(cfg.RollingUpdateTimeout),
+			reconciler.WithManagedKeysDB(db),
+			reconciler.WithSettingsDB(db),
+			reconciler.WithDeployTimeout(cfg.DeployTimeout),
+			reconciler.WithBackoff(cfg.BackoffBase, cfg.BackoffMax),
+			reconciler.WithWorkers(cfg.Workers),
+			reconciler.WithAutoCreateNamespace(cfg.AutoCreateNamespace),
+			reconciler.WithFinalizers(cfg.UseFinalizers),
+			reconciler.WithParameterGetter(parameterGetter),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reconciler for cluster %s: %w", name, err)
+		}
+		reconcilers[name] = rec
+	}
+
+	return reconciler.NewMultiReconciler(reconcilers)
+}
+
+// clusterDBPrefix is the database key prefix of the reconciler state of an additional cluster.
+// The default cluster keeps its state unprefixed, where it was before clusters were added.
+func clusterDBPrefix(name string) string {
+	return "cluster/" + name + "/"
+}
@@ -5,3 +5,6 @@ import "time"
 // DefaultShutdownTimeout is the default timeout for graceful server shutdown
 const DefaultShutdownTimeout = 30 * time.Second
 
+// DBSizeMetricInterval is how often the conductor_db_size_bytes gauge is refreshed
+const DBSizeMetricInterval = 30 * time.Second
+
@@ -26,18 +26,22 @@ func NewCRDClient(dynamicClient dynamic.Interface, cfg *Config, logger logr.Logg
 		logger.Error(err, "failed to get default DeploymentParameters, continuing without it")
 	} else if defaultParams == nil {
 		logger.Info("Creating default DeploymentParameters instance")
+		spec := crd.DeploymentParametersSpec{
+			"global": map[string]interface{}{
+				"namespace":  "default",
+				"namePrefix": "",
+				"replicas":   int32(1),
+			},
+		}
+		if len(cfg.DefaultParameters) > 0 {
+			spec = crd.DeploymentParametersSpec(cfg.DefaultParameters)
+		}
 		defaultParams = &crd.DeploymentParameters{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      crd.DefaultName,
 				Namespace: defaultNamespace,
 			},
-			Spec: crd.DeploymentParametersSpec{
-				"global": map[string]interface{}{
-					"namespace":  "default",
-					"namePrefix": "",
-					"replicas":   int32(1),
-				},
-			},
+			Spec: spec,
 		}
 		if err := parameterClient.Create(ctx, defaultParams); err != nil {
 			logger.Error(err, "failed to create default DeploymentParameters, continuing without it")
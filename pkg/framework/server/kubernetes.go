@@ -10,11 +10,29 @@ import (
 	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
 )
 
+// ClusterVersion returns the version of the Kubernetes API server of clientset, e.g. "v1.28.3",
+// or "" when it cannot be read, in which case .Cluster version comparisons in templates are false
+func ClusterVersion(clientset kubernetes.Interface, logger logr.Logger) string {
+	if clientset == nil {
+		return ""
+	}
+	info, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		logger.Info("Failed to get Kubernetes server version, .Cluster.Version is empty", "error", err)
+		return ""
+	}
+	return info.GitVersion
+}
+
 // NewKubernetesClients creates and returns Kubernetes clientset and dynamic client.
 // It handles Kubernetes configuration retrieval and client initialization.
 func NewKubernetesClients(cfg *Config, logger logr.Logger) (kubernetes.Interface, dynamic.Interface, error) {
-	logger.Info("Setting up Kubernetes client")
-	kubeConfig, err := reconciler.GetKubernetesConfig()
+	if cluster, ok := cfg.Clusters[reconciler.DefaultClusterName]; ok {
+		return newClusterClients(reconciler.DefaultClusterName, cluster, logger)
+	}
+
+	logger.Info("Setting up Kubernetes client", "context", cfg.KubernetesContext)
+	kubeConfig, err := reconciler.GetKubernetesConfigForContext(cfg.KubernetesContext)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get Kubernetes config: %w", err)
 	}
@@ -6,18 +6,45 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/api"
+	"github.com/garunski/conductor-framework/pkg/framework/database"
 )
 
 func (s *Server) Start(ctx context.Context) error {
 	s.logger.Info("Reconciler ready, manual reconciliation available via API")
-	s.reconciler.SetReady(true)
+	for _, name := range s.clusters.Names() {
+		rec, _ := s.clusters.Get(name)
+		rec.SetReady(true)
+	}
 
 	go s.startReconciliationHandler(ctx)
 
+	go func() {
+		if err := s.reconciler.ResumeRollingUpdates(ctx); err != nil {
+			s.logger.Error(err, "failed to resume rolling updates")
+		}
+	}()
+
 	go s.startLogCleanup(ctx)
 
+	if s.config.AutoGCInterval > 0 {
+		go s.startAutoGC(ctx)
+	}
+
+	go s.startDBSizeMetric(ctx)
+
+	if s.config.GitLoader != nil && s.config.GitLoader.PollInterval() > 0 {
+		go s.config.GitLoader.Watch(ctx, s.manifestStore, s.eventStore)
+	}
+
+	if s.config.ConfigMapLoader != nil {
+		go s.config.ConfigMapLoader.Watch(ctx, s.manifestStore, s.eventStore)
+	}
+
 	go func() {
 		s.logger.Info("Starting HTTP server", "port", s.config.Port)
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -60,6 +87,10 @@ func (s *Server) startReconciliationHandler(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case key := <-s.reconcileCh:
+			if key == api.ReconcileAllKey {
+				s.reconcileAll(ctx)
+				continue
+			}
 			if err := s.reconciler.ReconcileKey(ctx, key); err != nil {
 				s.logger.Error(err, "failed to reconcile key from API", "key", key)
 			}
@@ -67,6 +98,19 @@ func (s *Server) startReconciliationHandler(ctx context.Context) {
 	}
 }
 
+// reconcileAll reconciles every stored manifest, as requested by api.ReconcileAllKey
+func (s *Server) reconcileAll(ctx context.Context) {
+	manifests := s.manifestStore.List()
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if err := s.reconciler.ReconcileSelected(ctx, keys); err != nil {
+		s.logger.Error(err, "failed to reconcile all manifests from API", "count", len(keys))
+	}
+}
+
 func (s *Server) startLogCleanup(ctx context.Context) {
 	ticker := time.NewTicker(s.config.LogCleanupInterval)
 	defer ticker.Stop()
@@ -84,3 +128,52 @@ func (s *Server) startLogCleanup(ctx context.Context) {
 	}
 }
 
+// startDBSizeMetric refreshes the database size gauge every DBSizeMetricInterval
+func (s *Server) startDBSizeMetric(ctx context.Context) {
+	ticker := time.NewTicker(DBSizeMetricInterval)
+	defer ticker.Stop()
+
+	s.storeMetrics.SetDBSize(s.db.Size())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.storeMetrics.SetDBSize(s.db.Size())
+		}
+	}
+}
+
+// startAutoGC periodically garbage collects the value log while the database is larger than
+// the configured threshold
+func (s *Server) startAutoGC(ctx context.Context) {
+	ticker := time.NewTicker(s.config.AutoGCInterval)
+	defer ticker.Stop()
+
+	discardRatio := s.config.GCDiscardRatio
+	if discardRatio == 0 {
+		discardRatio = database.DefaultGCDiscardRatio
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := s.db.Stats()
+			if err != nil {
+				s.logger.Error(err, "failed to read database stats for garbage collection")
+				continue
+			}
+			if stats.LSMSizeBytes+stats.VLogSizeBytes < s.config.AutoGCThreshold {
+				continue
+			}
+			rewritten, err := s.db.RunGC(discardRatio)
+			if err != nil {
+				s.logger.Error(err, "failed to garbage collect database")
+				continue
+			}
+			s.logger.Info("database garbage collection finished", "rewritten", rewritten)
+		}
+	}
+}
@@ -1,19 +1,27 @@
 package server
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"net/http"
+	"text/template"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/garunski/conductor-framework/pkg/framework/api"
 	"github.com/garunski/conductor-framework/pkg/framework/crd"
 	"github.com/garunski/conductor-framework/pkg/framework/database"
 	"github.com/garunski/conductor-framework/pkg/framework/events"
 	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
+	"github.com/garunski/conductor-framework/pkg/framework/notifier"
 	"github.com/garunski/conductor-framework/pkg/framework/reconciler"
+	"github.com/garunski/conductor-framework/pkg/framework/store"
+	"github.com/garunski/conductor-framework/pkg/framework/webhook"
 )
 
 // Config holds server configuration
@@ -24,12 +32,66 @@ type Config struct {
 	Port               string
 	LogRetentionDays   int
 	LogCleanupInterval time.Duration
-	CRDGroup           string
-	CRDVersion         string
-	CRDResource        string
-	CustomTemplateFS   *embed.FS // Optional custom templates
-	ManifestFS         embed.FS  // Embedded manifest filesystem
-	ManifestRoot       string    // Root path for manifests
+	// MaxEventsPerResource evicts the oldest events of a resource past this many; zero is unlimited
+	MaxEventsPerResource int
+	// ManifestHistoryLimit is the number of previous versions kept per manifest; zero keeps none
+	ManifestHistoryLimit int
+	CRDGroup             string
+	CRDVersion           string
+	CRDResource          string
+	KubernetesContext    string // Optional kubeconfig context; empty uses the default
+	// Clusters adds named clusters next to the default one; a "default" entry overrides KubernetesContext
+	Clusters         map[string]ClusterConfig
+	CustomTemplateFS *embed.FS        // Optional custom templates
+	ManifestFS       embed.FS         // Embedded manifest filesystem
+	ManifestRoot     string           // Root path for manifests
+	TemplateFuncs    template.FuncMap // Custom manifest template functions
+	// DefaultParameters is the parameter spec served, and used for the default instance, until one exists
+	DefaultParameters  map[string]interface{}
+	PreDeployWebhooks  []webhook.Config
+	PostDeployWebhooks []webhook.Config
+	WebhookNotifiers   []notifier.Config
+	RateLimit          api.RateLimitConfig // Per-client limit for read endpoints
+	WriteRateLimit     api.RateLimitConfig // Per-client limit for deployment and parameter writes
+	MaxBodyBytes       int64               // Request body limit; zero disables it
+	MaxBulkBodyBytes   int64               // Request body limit of the bulk manifest uploads
+	Auth               api.AuthConfig
+	// WebhookTrigger configures POST /api/webhooks/trigger; an empty Secret disables it
+	WebhookTrigger api.WebhookTriggerConfig
+	// CORSAllowedOrigins restricts browser access to matching origins; nil allows every origin
+	CORSAllowedOrigins []string
+	DeployTimeout      time.Duration // Apply timeout for manifests without a deploy-timeout annotation
+	// BackoffBase and BackoffMax bound how long periodic reconciliation skips a failing
+	// resource; a zero BackoffBase retries failing resources on every reconciliation
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Workers is the number of manifests applied concurrently; zero uses reconciler.DefaultWorkers
+	Workers int
+	// RollbackRetention is the number of rollback snapshots kept; zero uses reconciler.DefaultRollbackRetention
+	RollbackRetention int
+	// RollingUpdateTimeout bounds the wait for each batch of a StatefulSet rolling update
+	RollingUpdateTimeout time.Duration
+	// AutoCreateNamespace creates missing namespaces when an apply fails because of them
+	AutoCreateNamespace bool
+	// UseFinalizers attaches reconciler.ManagedFinalizer to applied resources
+	UseFinalizers       bool
+	SkipCapacityCheck   bool          // Disables the capacity check Up runs before deploying
+	SkipConfirmation    bool          // Lets Down delete without a confirmation token
+	StrictKeyValidation bool          // Rejects manifests whose key does not match their metadata with 422
+	ProbeTimeout        time.Duration // Service probe timeout; zero selects api.DefaultProbeTimeout
+	// AutoGCInterval runs value log GC this often while the database is larger than
+	// AutoGCThreshold bytes; zero disables it
+	AutoGCInterval  time.Duration
+	AutoGCThreshold int64
+	GCDiscardRatio  float64 // Zero selects database.DefaultGCDiscardRatio
+	// TenantID scopes the manifest store to one tenant; see store.NewTenantManifestStore
+	TenantID string
+	// MetricsRegistry collects the metrics served on /metrics; a new registry is created when nil
+	MetricsRegistry *prometheus.Registry
+	// GitLoader, when set, loaded the manifests; Start polls it for changes when it has a PollInterval
+	GitLoader *manifest.GitLoader
+	// ConfigMapLoader, when set, overlaid the manifests of a ConfigMap; Start watches it for changes
+	ConfigMapLoader *manifest.ConfigMapLoader
 }
 
 type Server struct {
@@ -38,11 +100,14 @@ type Server struct {
 	db              *database.DB
 	index           *index.ManifestIndex
 	eventStore      events.EventStorage
+	manifestStore   store.ManifestStore
 	reconciler      reconciler.Reconciler
+	clusters        *reconciler.MultiReconciler
 	handler         *api.Handler
 	httpServer      *http.Server
 	reconcileCh     chan string
 	parameterClient *crd.Client
+	storeMetrics    *metrics.StoreMetrics
 }
 
 // NewServer creates a new server instance
@@ -60,8 +125,15 @@ func NewServer(cfg *Config, logger logr.Logger, manifests map[string][]byte) (*S
 		return nil, err
 	}
 
+	registry := cfg.MetricsRegistry
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
+	reconcilerMetrics := metrics.New(registry)
+	storeMetrics := metrics.NewStoreMetrics(registry)
+
 	// Create storage components
-	storage, err := NewStorageComponents(cfg, logger, manifests)
+	storage, err := NewStorageComponents(cfg, logger, manifests, store.WithMetrics(storeMetrics))
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +144,11 @@ func NewServer(cfg *Config, logger logr.Logger, manifests map[string][]byte) (*S
 		appName = "conductor"
 	}
 
+	// Manifest conditions are evaluated against the default DeploymentParameters instance
+	parameterGetter := func(ctx context.Context) (map[string]interface{}, error) {
+		return parameterClient.GetSpec(ctx, crd.DefaultName, "default")
+	}
+
 	// Create reconciler
 	rec, err := reconciler.NewReconciler(
 		clientset,
@@ -80,11 +157,29 @@ func NewServer(cfg *Config, logger logr.Logger, manifests map[string][]byte) (*S
 		logger,
 		storage.EventStore,
 		appName,
+		reconciler.WithRollbackDB(storage.DB),
+		reconciler.WithRollbackRetention(cfg.RollbackRetention),
+		reconciler.WithRolloutDB(storage.DB),
+		reconciler.WithRollingUpdateTimeout(cfg.RollingUpdateTimeout),
+		reconciler.WithManagedKeysDB(storage.DB),
+		reconciler.WithSettingsDB(storage.DB),
+		reconciler.WithDeployTimeout(cfg.DeployTimeout),
+		reconciler.WithBackoff(cfg.BackoffBase, cfg.BackoffMax),
+		reconciler.WithWorkers(cfg.Workers),
+		reconciler.WithAutoCreateNamespace(cfg.AutoCreateNamespace),
+		reconciler.WithFinalizers(cfg.UseFinalizers),
+		reconciler.WithParameterGetter(parameterGetter),
+		reconciler.WithMetrics(reconcilerMetrics),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reconciler: %w", err)
 	}
 
+	clusters, err := newClusterReconcilers(cfg, logger, storage, appName, rec, parameterGetter)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create handler
 	reconcileCh := make(chan string, 100)
 	handler, err := api.NewHandler(
@@ -103,6 +198,33 @@ func NewServer(cfg *Config, logger logr.Logger, manifests map[string][]byte) (*S
 	if err != nil {
 		return nil, fmt.Errorf("failed to create handler: %w", err)
 	}
+	handler.SetRateLimits(cfg.RateLimit, cfg.WriteRateLimit)
+	handler.SetBodyLimits(cfg.MaxBodyBytes, cfg.MaxBulkBodyBytes)
+	handler.SetAuth(cfg.Auth)
+	handler.SetWebhookTrigger(cfg.WebhookTrigger)
+	handler.SetMetrics(registry)
+	handler.SetDatabase(storage.DB, cfg.GCDiscardRatio)
+	handler.SetServiceProbe(nil, cfg.ProbeTimeout)
+	handler.SetClusters(clusters)
+	handler.SetSkipCapacityCheck(cfg.SkipCapacityCheck)
+	handler.SetDownConfirmation(!cfg.SkipConfirmation)
+	handler.SetStrictKeyValidation(cfg.StrictKeyValidation)
+	handler.SetTemplateFuncs(cfg.TemplateFuncs)
+	handler.SetClusterVersion(ClusterVersion(clientset, logger))
+	handler.SetDefaultParameterSpec(cfg.DefaultParameters)
+	handler.SetTenantStores(func(tenantID string) store.ManifestStore {
+		return store.NewTenantManifestStore(storage.DB, storage.Index, logger, tenantID,
+			store.WithHistoryRetention(cfg.ManifestHistoryLimit), store.WithMetrics(storeMetrics))
+	})
+	if cfg.CORSAllowedOrigins != nil {
+		handler.SetCORSAllowedOrigins(cfg.CORSAllowedOrigins)
+	}
+	if len(cfg.PreDeployWebhooks) > 0 || len(cfg.PostDeployWebhooks) > 0 {
+		handler.SetDeployWebhooks(webhook.NewHTTPInvoker(nil), cfg.PreDeployWebhooks, cfg.PostDeployWebhooks)
+	}
+	if len(cfg.WebhookNotifiers) > 0 {
+		handler.SetNotifier(notifier.NewHTTPNotifier(nil, cfg.WebhookNotifiers))
+	}
 
 	// Create HTTP server
 	router := handler.SetupRoutes()
@@ -117,11 +239,14 @@ func NewServer(cfg *Config, logger logr.Logger, manifests map[string][]byte) (*S
 		db:              storage.DB,
 		index:           storage.Index,
 		eventStore:      storage.EventStore,
+		manifestStore:   storage.ManifestStore,
 		reconciler:      rec,
+		clusters:        clusters,
 		handler:         handler,
 		httpServer:      httpServer,
 		reconcileCh:     reconcileCh,
 		parameterClient: parameterClient,
+		storeMetrics:    storeMetrics,
 	}, nil
 }
 
@@ -137,4 +262,3 @@ func (s *Server) Close() error {
 func int32Ptr(i int32) *int32 {
 	return &i
 }
-
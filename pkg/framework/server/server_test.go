@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/garunski/conductor-framework/pkg/framework/database"
 )
@@ -302,3 +305,34 @@ func TestServer_WaitForShutdown_Context(t *testing.T) {
 	cancel()
 }
 
+func TestManifestOverrides(t *testing.T) {
+	items := map[string][]byte{
+		"default/ConfigMap/app":          []byte("manifest"),
+		"default/ConfigMap/app/_etag":    []byte("etag"),
+		"events/00000000000000000001/id": []byte("{}"),
+		"audit/00000000000000000001/id":  []byte("{}"),
+		"rollback/v1":                    []byte("{}"),
+		"multidoc/default/Bundle/app":    []byte("default/ConfigMap/app"),
+		"managed/default/ConfigMap/app":  nil,
+	}
+
+	got := manifestOverrides(items)
+	if len(got) != 1 {
+		t.Fatalf("manifestOverrides() returned %d entries, want 1: %v", len(got), got)
+	}
+	if _, ok := got["default/ConfigMap/app"]; !ok {
+		t.Error("manifestOverrides() dropped the manifest")
+	}
+}
+
+func TestClusterVersion(t *testing.T) {
+	clientset := kubefake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.28.3"}
+
+	if got := ClusterVersion(clientset, logr.Discard()); got != "v1.28.3" {
+		t.Errorf("ClusterVersion() = %q, want v1.28.3", got)
+	}
+	if got := ClusterVersion(nil, logr.Discard()); got != "" {
+		t.Errorf("ClusterVersion(nil) = %q, want empty", got)
+	}
+}
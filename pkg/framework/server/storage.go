@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-logr/logr"
@@ -13,15 +14,16 @@ import (
 
 // StorageComponents holds all storage-related components
 type StorageComponents struct {
-	DB          *database.DB
-	Index       *index.ManifestIndex
-	EventStore  events.EventStorage
+	DB            *database.DB
+	Index         *index.ManifestIndex
+	EventStore    events.EventStorage
 	ManifestStore store.ManifestStore
 }
 
 // NewStorageComponents creates and initializes all storage components.
-// It opens the database, loads overrides, creates the index, event store, and manifest store.
-func NewStorageComponents(cfg *Config, logger logr.Logger, manifests map[string][]byte) (*StorageComponents, error) {
+// It opens the database, loads overrides, creates the index, event store, and manifest store,
+// which opts configure further.
+func NewStorageComponents(cfg *Config, logger logr.Logger, manifests map[string][]byte, opts ...store.Option) (*StorageComponents, error) {
 	logger.Info("Opening BadgerDB", "path", cfg.DataPath)
 	db, err := database.NewDB(cfg.DataPath, logger)
 	if err != nil {
@@ -33,21 +35,50 @@ func NewStorageComponents(cfg *Config, logger logr.Logger, manifests map[string]
 	if err != nil {
 		return nil, fmt.Errorf("failed to load DB overrides: %w", err)
 	}
+	dbOverrides = manifestOverrides(dbOverrides)
 	logger.Info("Loaded DB overrides", "count", len(dbOverrides))
 
 	idx := index.NewIndex()
-	idx.Merge(manifests, dbOverrides)
+	idx.Merge(tenantManifests(cfg.TenantID, manifests), dbOverrides)
 
-	eventStore := events.NewStorage(db, logger)
+	eventStore := events.NewStorage(db, logger, events.WithMaxEventsPerResource(cfg.MaxEventsPerResource))
 	logger.Info("Event storage initialized")
 
-	manifestStore := store.NewManifestStore(db, idx, logger)
+	opts = append([]store.Option{store.WithHistoryRetention(cfg.ManifestHistoryLimit)}, opts...)
+	manifestStore := store.NewTenantManifestStore(db, idx, logger, cfg.TenantID, opts...)
+	if err := manifestStore.Reconcile(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to reconcile manifest index: %w", err)
+	}
 
 	return &StorageComponents{
-		DB:           db,
-		Index:        idx,
-		EventStore:   eventStore,
+		DB:            db,
+		Index:         idx,
+		EventStore:    eventStore,
 		ManifestStore: manifestStore,
 	}, nil
 }
 
+// tenantManifests returns manifests keyed as tenantID/namespace/Kind/name, the way the
+// manifest store of tenantID stores them
+func tenantManifests(tenantID string, manifests map[string][]byte) map[string][]byte {
+	if tenantID == "" {
+		return manifests
+	}
+	result := make(map[string][]byte, len(manifests))
+	for key, value := range manifests {
+		result[tenantID+"/"+key] = value
+	}
+	return result
+}
+
+// manifestOverrides drops the database entries that are not manifests, such as events
+// and the ETags stored next to each manifest
+func manifestOverrides(items map[string][]byte) map[string][]byte {
+	result := make(map[string][]byte, len(items))
+	for key, value := range items {
+		if store.IsManifestKey(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
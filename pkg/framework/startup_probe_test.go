@@ -0,0 +1,156 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/version"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// freePort returns a TCP port that is currently free on localhost
+func freePort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %v", err)
+	}
+	defer listener.Close()
+	return fmt.Sprintf("%d", listener.Addr().(*net.TCPAddr).Port)
+}
+
+// TestRun_StartupProbeRespondsBeforeManifestLoad tests that the startup probe is served
+// while manifest loading is still in progress and is stopped when Run returns
+func TestRun_StartupProbeRespondsBeforeManifestLoad(t *testing.T) {
+	reached := make(chan struct{})
+	release := make(chan struct{})
+	original := loadManifestsFunc
+	loadManifestsFunc = func(ctx context.Context, cfg Config, parameterGetter manifest.ParameterGetter, renderOpts manifest.RenderOptions, gitLoader *manifest.GitLoader, configMapLoader *manifest.ConfigMapLoader) (map[string][]byte, error) {
+		close(reached)
+		<-release
+		return nil, errors.New("manifest loading aborted")
+	}
+	defer func() { loadManifestsFunc = original }()
+
+	probePort := freePort(t)
+	cfg := Config{
+		AppName:            "test",
+		DataPath:           t.TempDir(),
+		Port:               freePort(t),
+		StartupProbePort:   probePort,
+		LogRetentionDays:   7,
+		LogCleanupInterval: 1 * time.Hour,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- Run(ctx, cfg)
+	}()
+
+	select {
+	case <-reached:
+	case <-ctx.Done():
+		t.Fatal("Run() did not reach manifest loading")
+	}
+
+	url := "http://127.0.0.1:" + probePort + "/startup"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET /startup error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /startup status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	close(release)
+	select {
+	case err := <-runErr:
+		if err == nil || !contains(err.Error(), "manifest loading aborted") {
+			t.Errorf("Run() error = %v, want manifest loading error", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Run() did not return after manifest loading failed")
+	}
+
+	if resp, err := http.Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("startup probe still serving after Run() returned")
+	}
+}
+
+// TestConfigValidate_StartupProbePort tests that the startup probe cannot share the main port
+func TestConfigValidate_StartupProbePort(t *testing.T) {
+	cfg := Config{
+		AppName:            "test",
+		DataPath:           "/tmp/test",
+		Port:               "8080",
+		StartupProbePort:   "8080",
+		LogRetentionDays:   7,
+		LogCleanupInterval: 1 * time.Hour,
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject StartupProbePort equal to Port")
+	}
+}
+
+// flakyDiscovery fails the first failures ServerVersion calls and then succeeds
+type flakyDiscovery struct {
+	failures int
+	calls    int
+}
+
+func (d *flakyDiscovery) ServerVersion() (*version.Info, error) {
+	d.calls++
+	if d.calls <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+	return &version.Info{GitVersion: "v1.30.0"}, nil
+}
+
+func TestWaitForServerVersion_RetriesUntilReachable(t *testing.T) {
+	client := &flakyDiscovery{failures: 3}
+	probe := StartupProbeConfig{Enabled: true, MaxAttempts: 5, RetryInterval: time.Millisecond}
+
+	if err := waitForServerVersion(context.Background(), logr.Discard(), client, probe); err != nil {
+		t.Fatalf("waitForServerVersion() error = %v", err)
+	}
+	if client.calls != 4 {
+		t.Errorf("ServerVersion() called %d times, want 4", client.calls)
+	}
+}
+
+func TestWaitForServerVersion_GivesUp(t *testing.T) {
+	client := &flakyDiscovery{failures: 10}
+	probe := StartupProbeConfig{Enabled: true, MaxAttempts: 3, RetryInterval: time.Millisecond}
+
+	err := waitForServerVersion(context.Background(), logr.Discard(), client, probe)
+	if !errors.Is(err, apperrors.ErrKubernetes) {
+		t.Fatalf("waitForServerVersion() error = %v, want ErrKubernetes", err)
+	}
+	if client.calls != 3 {
+		t.Errorf("ServerVersion() called %d times, want 3", client.calls)
+	}
+}
+
+func TestWaitForServerVersion_Cancelled(t *testing.T) {
+	client := &flakyDiscovery{failures: 10}
+	probe := StartupProbeConfig{Enabled: true, MaxAttempts: 3, RetryInterval: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := waitForServerVersion(ctx, logr.Discard(), client, probe); !errors.Is(err, context.Canceled) {
+		t.Errorf("waitForServerVersion() error = %v, want context.Canceled", err)
+	}
+}
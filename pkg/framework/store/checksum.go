@@ -0,0 +1,60 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+)
+
+// ComputeChecksum returns the hex SHA-256 hash of manifests, hashing every key and value
+// in lexicographic key order
+func ComputeChecksum(manifests map[string][]byte) string {
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(manifests[key])
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Checksum returns the ComputeChecksum of List(). The result is cached until the index
+// records a write, so polling it between writes does not rehash the manifests.
+func (s *manifestStoreImpl) Checksum() string {
+	version := s.index.Version()
+
+	s.checksumMu.Lock()
+	defer s.checksumMu.Unlock()
+	if s.checksum != "" && s.checksumVersion == version {
+		return s.checksum
+	}
+	s.checksum = ComputeChecksum(s.List())
+	s.checksumVersion = version
+	return s.checksum
+}
+
+// LastModified returns when the manifest stored under key was last written, or loaded at
+// startup. A multi-document manifest was last modified when its newest document was.
+func (s *manifestStoreImpl) LastModified(key string) (time.Time, bool) {
+	if childKeys, isParent := s.Children(key); isParent {
+		var latest time.Time
+		for _, childKey := range childKeys {
+			if modTime, ok := s.index.ModTime(s.dbKey(childKey)); ok && modTime.After(latest) {
+				latest = modTime
+			}
+		}
+		return latest, true
+	}
+	if !s.owns(key) {
+		return time.Time{}, false
+	}
+	return s.index.ModTime(s.dbKey(key))
+}
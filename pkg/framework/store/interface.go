@@ -1,14 +1,44 @@
 package store
 
+import (
+	"context"
+	"time"
+)
+
 // ManifestStore defines the interface for manifest storage operations.
 // This interface allows for better testability and reduced coupling.
 type ManifestStore interface {
 	// Get retrieves a manifest by key, returning the value and whether it exists
 	Get(key string) ([]byte, bool)
 
+	// GetWithETag retrieves a manifest by key together with its ETag, the SHA-256 hash of its content
+	GetWithETag(key string) ([]byte, string, bool)
+
+	// Children returns the keys of the documents of a multi-document manifest and whether key is one
+	Children(key string) ([]string, bool)
+
 	// List returns all manifests as a map of key to value
 	List() map[string][]byte
 
+	// ListByKind returns the manifests whose key (namespace/kind/name) has the given kind
+	ListByKind(kind string) map[string][]byte
+
+	// ListByNamespace returns the manifests whose key (namespace/kind/name) has the given namespace
+	ListByNamespace(namespace string) map[string][]byte
+
+	// ListPaged returns one page, counted from 1, of the manifests whose key starts with prefix,
+	// sorted by SortByKey or SortByModified in SortAscending or SortDescending order
+	ListPaged(page, pageSize int, sortBy, sortOrder, prefix string) (ManifestPage, error)
+
+	// Checksum returns the SHA-256 hash of all manifests, which changes whenever one is created, updated or deleted
+	Checksum() string
+
+	// LastModified returns when the manifest stored under key was last written
+	LastModified(key string) (time.Time, bool)
+
+	// Count returns the number of manifests in the store
+	Count() int
+
 	// Create creates a new manifest entry
 	Create(key string, value []byte) error
 
@@ -17,8 +47,31 @@ type ManifestStore interface {
 
 	// Delete deletes a manifest entry by key
 	Delete(key string) error
+
+	// CreateBatch creates all entries in a single transaction; it fails without writing anything if a key already exists
+	CreateBatch(entries map[string][]byte) error
+
+	// PutBatch creates or updates all entries in a single transaction, keeping the previous values of updated manifests as versions
+	PutBatch(entries map[string][]byte) error
+
+	// Rename moves the manifest stored under oldKey to newKey, which must not exist, in a single transaction
+	Rename(oldKey, newKey string) error
+
+	// DeleteBatch deletes all keys in a single transaction; it fails without deleting anything if a key does not exist
+	DeleteBatch(keys []string) error
+
+	// GetHistory returns the versions Update replaced for key, newest first
+	GetHistory(key string) ([]ManifestVersion, error)
+
+	// GetVersion returns the version of key that Update replaced at timestamp (Unix nanoseconds)
+	GetVersion(key string, timestamp int64) (ManifestVersion, error)
+
+	// WithTransaction runs fn in a single transaction whose writes reach the index only after it commits
+	WithTransaction(fn func(txn *ManifestStoreTxn) error) error
+
+	// Reconcile rebuilds the index from the database, repairing entries that went out of sync
+	Reconcile(ctx context.Context) error
 }
 
 // Ensure *manifestStoreImpl implements ManifestStore interface
 var _ ManifestStore = (*manifestStoreImpl)(nil)
-
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// HistoryKeyPrefix prefixes the database keys that hold the previous versions of manifests,
+// stored as manifest_history/{key}/{timestamp}. Deleting a manifest keeps its versions, so the
+// space taken is bounded by the retention count per key ever written rather than per live key.
+const HistoryKeyPrefix = "manifest_history/"
+
+// DefaultHistoryRetention is the number of previous versions kept per manifest
+const DefaultHistoryRetention = 10
+
+// ManifestVersion is a previous version of a manifest
+type ManifestVersion struct {
+	// Timestamp is when the version was replaced, in Unix nanoseconds
+	Timestamp int64
+	Value     []byte
+}
+
+// Option configures optional manifest store behaviour
+type Option func(*manifestStoreImpl)
+
+// WithHistoryRetention keeps the n most recent previous versions of every manifest.
+// Zero keeps no history.
+func WithHistoryRetention(n int) Option {
+	return func(s *manifestStoreImpl) {
+		s.historyRetention = n
+	}
+}
+
+// historyPrefix returns the prefix of the database keys of the versions of the manifest stored
+// under the database key dbKey
+func historyPrefix(dbKey string) string {
+	return HistoryKeyPrefix + dbKey + "/"
+}
+
+// historyKey returns the database key of the version of dbKey replaced at timestamp. The
+// timestamp is zero-padded so keys sort chronologically.
+func historyKey(dbKey string, timestamp int64) string {
+	return fmt.Sprintf("%s%020d", historyPrefix(dbKey), timestamp)
+}
+
+// saveVersion stores previous as a version of the manifest stored under dbKey and deletes
+// the versions past the retention count. db is typically transaction-scoped so the version
+// commits together with the write that replaced it.
+func (s *manifestStoreImpl) saveVersion(db *database.DB, dbKey string, previous []byte) error {
+	if s.historyRetention <= 0 {
+		return nil
+	}
+
+	timestamp := time.Now().UnixNano()
+	// Versions replaced within the same clock tick get consecutive timestamps
+	for {
+		if _, err := db.Get(historyKey(dbKey, timestamp)); err != nil {
+			break
+		}
+		timestamp++
+	}
+	if err := db.Set(historyKey(dbKey, timestamp), previous); err != nil {
+		return fmt.Errorf("db set history: %w", err)
+	}
+	return s.trimHistory(db, dbKey)
+}
+
+// trimHistory deletes the oldest versions of the manifest stored under dbKey past the
+// retention count
+func (s *manifestStoreImpl) trimHistory(db *database.DB, dbKey string) error {
+	items, err := db.List(historyPrefix(dbKey))
+	if err != nil {
+		return fmt.Errorf("db list history: %w", err)
+	}
+	if len(items) <= s.historyRetention {
+		return nil
+	}
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if err := db.BatchDelete(keys[:len(keys)-s.historyRetention]); err != nil {
+		return fmt.Errorf("db delete history: %w", err)
+	}
+	return nil
+}
+
+// moveHistory moves the versions of the manifest stored under oldDBKey to newDBKey, keeping
+// their timestamps, and deletes the versions past the retention count
+func (s *manifestStoreImpl) moveHistory(db *database.DB, oldDBKey, newDBKey string) error {
+	oldPrefix := historyPrefix(oldDBKey)
+	items, err := db.List(oldPrefix)
+	if err != nil {
+		return fmt.Errorf("db list history: %w", err)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	moved := make(map[string][]byte, len(items))
+	oldKeys := make([]string, 0, len(items))
+	for key, value := range items {
+		moved[historyPrefix(newDBKey)+strings.TrimPrefix(key, oldPrefix)] = value
+		oldKeys = append(oldKeys, key)
+	}
+	if err := db.BatchSet(moved); err != nil {
+		return fmt.Errorf("db set history: %w", err)
+	}
+	if err := db.BatchDelete(oldKeys); err != nil {
+		return fmt.Errorf("db delete history: %w", err)
+	}
+	return s.trimHistory(db, newDBKey)
+}
+
+// GetHistory returns the previous versions of the manifest stored under key, newest first.
+// History outlives the manifest, so a deleted manifest can still be restored; renaming a
+// manifest moves its history to the new key.
+func (s *manifestStoreImpl) GetHistory(key string) ([]ManifestVersion, error) {
+	if err := s.checkKey(key); err != nil {
+		return nil, err
+	}
+	prefix := historyPrefix(s.dbKey(key))
+	items, err := s.db.List(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("db list history: %w", err)
+	}
+
+	versions := make([]ManifestVersion, 0, len(items))
+	for historyKey, value := range items {
+		timestamp, err := strconv.ParseInt(strings.TrimPrefix(historyKey, prefix), 10, 64)
+		if err != nil {
+			s.logger.V(1).Info("skipping malformed manifest history key", "key", historyKey)
+			continue
+		}
+		versions = append(versions, ManifestVersion{Timestamp: timestamp, Value: value})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Timestamp > versions[j].Timestamp })
+	return versions, nil
+}
+
+// GetVersion returns the version of the manifest stored under key that was replaced at timestamp
+func (s *manifestStoreImpl) GetVersion(key string, timestamp int64) (ManifestVersion, error) {
+	if err := s.checkKey(key); err != nil {
+		return ManifestVersion{}, err
+	}
+	value, err := s.db.Get(historyKey(s.dbKey(key), timestamp))
+	if errors.Is(err, database.ErrNotFound) {
+		return ManifestVersion{}, fmt.Errorf("%w: version %d of manifest %s", apperrors.ErrNotFound, timestamp, key)
+	}
+	if err != nil {
+		return ManifestVersion{}, fmt.Errorf("db get history: %w", err)
+	}
+	return ManifestVersion{Timestamp: timestamp, Value: value}, nil
+}
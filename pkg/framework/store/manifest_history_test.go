@@ -0,0 +1,177 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+)
+
+func historyTestManifest(version int) []byte {
+	return []byte(fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n  namespace: default\ndata:\n  version: \"%d\"\n", version))
+}
+
+func TestManifestStore_UpdateKeepsHistory(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard())
+	key := "default/ConfigMap/app"
+
+	if err := s.Create(key, historyTestManifest(0)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		if err := s.Update(key, historyTestManifest(i)); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	versions, err := s.GetHistory(key)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(versions) != 5 {
+		t.Fatalf("GetHistory() returned %d versions, want 5", len(versions))
+	}
+	// Newest first: the fifth update replaced version 4
+	for i, version := range versions {
+		if want := string(historyTestManifest(4 - i)); string(version.Value) != want {
+			t.Errorf("GetHistory()[%d] = %q, want %q", i, version.Value, want)
+		}
+		if i > 0 && version.Timestamp >= versions[i-1].Timestamp {
+			t.Errorf("GetHistory()[%d] timestamp %d is not older than %d", i, version.Timestamp, versions[i-1].Timestamp)
+		}
+	}
+
+	version, err := s.GetVersion(key, versions[2].Timestamp)
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if string(version.Value) != string(historyTestManifest(2)) {
+		t.Errorf("GetVersion() = %q, want version 2", version.Value)
+	}
+	if _, err := s.GetVersion(key, 1); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetVersion() of unknown timestamp error = %v, want ErrNotFound", err)
+	}
+
+	// History is not mistaken for manifests
+	if s.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", s.Count())
+	}
+}
+
+func TestManifestStore_PutBatchKeepsHistory(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard())
+	key := "default/ConfigMap/app"
+
+	if err := s.CreateBatch(map[string][]byte{key: historyTestManifest(0)}); err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+	if err := s.PutBatch(map[string][]byte{key: historyTestManifest(1), "default/ConfigMap/other": historyTestManifest(0)}); err != nil {
+		t.Fatalf("PutBatch() error = %v", err)
+	}
+
+	versions, err := s.GetHistory(key)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(versions) != 1 || string(versions[0].Value) != string(historyTestManifest(0)) {
+		t.Errorf("GetHistory() = %v, want the value PutBatch replaced", versions)
+	}
+	if versions, _ := s.GetHistory("default/ConfigMap/other"); len(versions) != 0 {
+		t.Errorf("GetHistory() of a created manifest = %v, want none", versions)
+	}
+}
+
+func TestManifestStore_HistoryRetention(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard(), WithHistoryRetention(3))
+	key := "default/ConfigMap/app"
+
+	if err := s.Create(key, historyTestManifest(0)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		if err := s.Update(key, historyTestManifest(i)); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	versions, err := s.GetHistory(key)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("GetHistory() returned %d versions, want 3", len(versions))
+	}
+	if string(versions[2].Value) != string(historyTestManifest(2)) {
+		t.Errorf("oldest kept version = %q, want version 2", versions[2].Value)
+	}
+
+	disabled := NewManifestStore(db, index.NewIndex(), logr.Discard(), WithHistoryRetention(0))
+	if err := disabled.Create("default/ConfigMap/other", historyTestManifest(0)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := disabled.Update("default/ConfigMap/other", historyTestManifest(1)); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if versions, _ := disabled.GetHistory("default/ConfigMap/other"); len(versions) != 0 {
+		t.Errorf("GetHistory() with retention 0 returned %d versions, want none", len(versions))
+	}
+}
+
+func TestManifestStore_RenameMovesHistory(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard())
+	oldKey, newKey := "default/ConfigMap/app", "staging/ConfigMap/app"
+
+	if err := s.Create(oldKey, historyTestManifest(0)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		if err := s.Update(oldKey, historyTestManifest(i)); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	before, err := s.GetHistory(oldKey)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+
+	if err := s.Rename(oldKey, newKey); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+
+	after, err := s.GetHistory(newKey)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("GetHistory() of the new key returned %d versions, want %d", len(after), len(before))
+	}
+	for i := range before {
+		if after[i].Timestamp != before[i].Timestamp || string(after[i].Value) != string(before[i].Value) {
+			t.Errorf("GetHistory()[%d] = %d %q, want %d %q", i, after[i].Timestamp, after[i].Value, before[i].Timestamp, before[i].Value)
+		}
+	}
+	if old, _ := s.GetHistory(oldKey); len(old) != 0 {
+		t.Errorf("GetHistory() of the old key returned %d versions, want 0", len(old))
+	}
+}
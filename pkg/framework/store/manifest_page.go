@@ -0,0 +1,93 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+)
+
+// Sort fields and orders accepted by ListPaged
+const (
+	SortByKey      = "key"
+	SortByModified = "modified"
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// ManifestPage is one page of the manifests of a store. Keys lists the keys of Items in the
+// requested order.
+type ManifestPage struct {
+	Items      map[string][]byte
+	Keys       []string
+	Total      int
+	Page       int
+	PageSize   int
+	TotalPages int
+}
+
+// ListPaged returns page (counted from 1) of the manifests whose key starts with prefix,
+// pageSize per page, sorted by key or by modification time. A page past the last one is empty.
+func (s *manifestStoreImpl) ListPaged(page, pageSize int, sortBy, sortOrder, prefix string) (ManifestPage, error) {
+	if page < 1 || pageSize < 1 {
+		return ManifestPage{}, fmt.Errorf("%w: page and page size must be positive", apperrors.ErrInvalidParameter)
+	}
+	if sortBy == "" {
+		sortBy = SortByKey
+	}
+	if sortBy != SortByKey && sortBy != SortByModified {
+		return ManifestPage{}, fmt.Errorf("%w: sort field %q (must be %s or %s)", apperrors.ErrInvalidParameter, sortBy, SortByKey, SortByModified)
+	}
+	if sortOrder == "" {
+		sortOrder = SortAscending
+	}
+	if sortOrder != SortAscending && sortOrder != SortDescending {
+		return ManifestPage{}, fmt.Errorf("%w: sort order %q (must be %s or %s)", apperrors.ErrInvalidParameter, sortOrder, SortAscending, SortDescending)
+	}
+
+	dbKeys := s.index.SortedKeys(s.tenantID, s.dbKey(prefix))
+	if sortBy == SortByModified {
+		modTimes := make(map[string]int64, len(dbKeys))
+		for _, dbKey := range dbKeys {
+			modTime, _ := s.index.ModTime(dbKey)
+			modTimes[dbKey] = modTime.UnixNano()
+		}
+		// Stable so manifests written at the same time stay in key order
+		sort.SliceStable(dbKeys, func(i, j int) bool { return modTimes[dbKeys[i]] < modTimes[dbKeys[j]] })
+	}
+	if sortOrder == SortDescending {
+		for i, j := 0, len(dbKeys)-1; i < j; i, j = i+1, j-1 {
+			dbKeys[i], dbKeys[j] = dbKeys[j], dbKeys[i]
+		}
+	}
+
+	result := ManifestPage{
+		Items:      make(map[string][]byte),
+		Keys:       []string{},
+		Total:      len(dbKeys),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (len(dbKeys) + pageSize - 1) / pageSize,
+	}
+	// Checked before multiplying so a huge page cannot overflow the offset
+	if page > result.TotalPages {
+		return result, nil
+	}
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if end > len(dbKeys) {
+		end = len(dbKeys)
+	}
+	for _, dbKey := range dbKeys[start:end] {
+		key, ok := s.storeKey(dbKey)
+		if !ok {
+			continue
+		}
+		// Keys deleted since they were listed are left out of the page
+		if value, ok := s.index.Get(dbKey); ok {
+			result.Items[key] = value
+			result.Keys = append(result.Keys, key)
+		}
+	}
+	return result, nil
+}
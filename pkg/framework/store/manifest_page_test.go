@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+)
+
+func newPagedTestStore(t *testing.T, count int) ManifestStore {
+	t.Helper()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard())
+	entries := make(map[string][]byte, count)
+	for i := 0; i < count; i++ {
+		entries[fmt.Sprintf("default/ConfigMap/cm-%03d", i)] = []byte(fmt.Sprintf("cm %d", i))
+	}
+	if err := s.CreateBatch(entries); err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+	return s
+}
+
+func TestManifestStore_ListPaged(t *testing.T) {
+	s := newPagedTestStore(t, 150)
+
+	page, err := s.ListPaged(2, 50, "", "", "")
+	if err != nil {
+		t.Fatalf("ListPaged() error = %v", err)
+	}
+	if page.Total != 150 || page.TotalPages != 3 || page.Page != 2 || page.PageSize != 50 {
+		t.Errorf("ListPaged() = total %d, %d pages, page %d of size %d; want 150, 3, 2 and 50", page.Total, page.TotalPages, page.Page, page.PageSize)
+	}
+	if len(page.Items) != 50 || len(page.Keys) != 50 {
+		t.Fatalf("ListPaged() returned %d items and %d keys, want 50", len(page.Items), len(page.Keys))
+	}
+	if page.Keys[0] != "default/ConfigMap/cm-050" || page.Keys[49] != "default/ConfigMap/cm-099" {
+		t.Errorf("ListPaged() page 2 spans %s to %s, want cm-050 to cm-099", page.Keys[0], page.Keys[49])
+	}
+	if string(page.Items["default/ConfigMap/cm-050"]) != "cm 50" {
+		t.Errorf("ListPaged() item = %q, want %q", page.Items["default/ConfigMap/cm-050"], "cm 50")
+	}
+
+	desc, err := s.ListPaged(1, 10, SortByKey, SortDescending, "")
+	if err != nil {
+		t.Fatalf("ListPaged(desc) error = %v", err)
+	}
+	if desc.Keys[0] != "default/ConfigMap/cm-149" {
+		t.Errorf("ListPaged(desc) first key = %s, want cm-149", desc.Keys[0])
+	}
+
+	past, err := s.ListPaged(4, 50, "", "", "")
+	if err != nil {
+		t.Fatalf("ListPaged(past end) error = %v", err)
+	}
+	if len(past.Items) != 0 || past.Total != 150 {
+		t.Errorf("ListPaged(past end) = %d items of %d, want none of 150", len(past.Items), past.Total)
+	}
+
+	prefixed, err := s.ListPaged(1, 50, "", "", "default/ConfigMap/cm-14")
+	if err != nil {
+		t.Fatalf("ListPaged(prefix) error = %v", err)
+	}
+	if prefixed.Total != 10 || prefixed.TotalPages != 1 {
+		t.Errorf("ListPaged(prefix) total = %d in %d pages, want 10 in 1", prefixed.Total, prefixed.TotalPages)
+	}
+
+	if _, err := s.ListPaged(1, 50, "name", "", ""); !errors.Is(err, apperrors.ErrInvalidParameter) {
+		t.Errorf("ListPaged(sort_by=name) error = %v, want ErrInvalidParameter", err)
+	}
+	if _, err := s.ListPaged(1, 50, "", "up", ""); !errors.Is(err, apperrors.ErrInvalidParameter) {
+		t.Errorf("ListPaged(sort_order=up) error = %v, want ErrInvalidParameter", err)
+	}
+}
+
+func TestManifestStore_ListPaged_PageOverflow(t *testing.T) {
+	s := newPagedTestStore(t, 10)
+
+	// (page-1)*pageSize overflows int for this page
+	page, err := s.ListPaged(36893488147419104, 500, "", "", "")
+	if err != nil {
+		t.Fatalf("ListPaged() error = %v", err)
+	}
+	if len(page.Keys) != 0 || page.Total != 10 {
+		t.Errorf("ListPaged() returned %d keys of %d, want an empty page of 10", len(page.Keys), page.Total)
+	}
+}
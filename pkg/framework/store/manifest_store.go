@@ -1,75 +1,380 @@
 package store
 
 import (
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/garunski/conductor-framework/pkg/framework/database"
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
 )
 
+// etagSuffix is appended to a manifest key to form the key its ETag is stored under
+const etagSuffix = "/_etag"
+
+// ETagKey returns the database key that holds the ETag of the manifest stored under key
+func ETagKey(key string) string {
+	return key + etagSuffix
+}
+
+// IsETagKey reports whether key holds a manifest ETag rather than a manifest
+func IsETagKey(key string) bool {
+	return strings.HasSuffix(key, etagSuffix)
+}
+
+// ComputeETag returns the ETag of a manifest, the hex SHA-256 hash of its content
+func ComputeETag(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:])
+}
+
 type manifestStoreImpl struct {
 	db     *database.DB
 	index  *index.ManifestIndex
 	logger logr.Logger
+
+	// tenantID prefixes every key in the database and the index; empty is the zero tenant
+	tenantID string
+
+	// historyRetention is the number of previous versions kept per manifest
+	historyRetention int
+
+	// metrics records store operations; nil records nothing
+	metrics *metrics.StoreMetrics
+
+	// writeMu serializes writes so the index applies them in the order they were committed
+	writeMu sync.Mutex
+
+	// parents maps each multi-document manifest key to the keys of its documents
+	parentsMu sync.RWMutex
+	parents   map[string][]string
+
+	// checksum caches Checksum for the index version it was computed at
+	checksumMu      sync.Mutex
+	checksum        string
+	checksumVersion uint64
 }
 
-func NewManifestStore(db *database.DB, idx *index.ManifestIndex, logger logr.Logger) ManifestStore {
-	return &manifestStoreImpl{
-		db:     db,
-		index:  idx,
-		logger: logger,
+func NewManifestStore(db *database.DB, idx *index.ManifestIndex, logger logr.Logger, opts ...Option) ManifestStore {
+	return NewTenantManifestStore(db, idx, logger, "", opts...)
+}
+
+// NewTenantManifestStore returns a store whose namespace/Kind/name keys are stored as
+// tenantID/namespace/Kind/name, so it neither sees nor overwrites the manifests of other
+// tenants. db and idx may be shared between tenants. An empty tenantID returns the zero-tenant
+// store of NewManifestStore, which sees every key that does not belong to a tenant.
+func NewTenantManifestStore(db *database.DB, idx *index.ManifestIndex, logger logr.Logger, tenantID string, opts ...Option) ManifestStore {
+	s := &manifestStoreImpl{
+		db:               db,
+		index:            idx,
+		logger:           logger,
+		tenantID:         tenantID,
+		historyRetention: DefaultHistoryRetention,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.parents = s.readParents()
+	return s
 }
 
-func (s *manifestStoreImpl) Create(key string, value []byte) error {
-	if err := s.db.Set(key, value); err != nil {
-		return fmt.Errorf("db set: %w", err)
+// ValidateTenantID checks that tenantID is a DNS-1123 label that does not collide with the
+// database prefixes of other components
+func ValidateTenantID(tenantID string) error {
+	if errs := validation.IsDNS1123Label(tenantID); len(errs) > 0 {
+		return fmt.Errorf("%w: tenant ID %q: %s", apperrors.ErrInvalid, tenantID, strings.Join(errs, "; "))
+	}
+	if reservedTenantIDs[tenantID] {
+		return fmt.Errorf("%w: tenant ID %q is reserved", apperrors.ErrInvalid, tenantID)
 	}
-	s.index.Set(key, value)
 	return nil
 }
 
-func (s *manifestStoreImpl) Update(key string, value []byte) error {
+// reservedTenantIDs are the first key segments the database uses for data other than manifests
+var reservedTenantIDs = map[string]bool{
+	"events":   true,
+	"audit":    true,
+	"rollback": true,
+	"rollout":  true,
+	"managed":  true,
+	"multidoc": true,
+	"config":   true,
+	"confirm":  true,
+	"cluster":  true,
+	// manifest_history is not a DNS-1123 label; it is listed so IsManifestKey excludes it
+	"manifest_history": true,
+}
+
+// dbKey returns the database and index key of the manifest key of this store's tenant
+func (s *manifestStoreImpl) dbKey(key string) string {
+	if s.tenantID == "" {
+		return key
+	}
+	return s.tenantID + "/" + key
+}
 
-	if _, exists := s.index.Get(key); !exists {
-		return fmt.Errorf("%w: manifest not found: %s", apperrors.ErrNotFound, key)
+// storeKey returns the key a database or index key has in this store, and false when it
+// belongs to another tenant
+func (s *manifestStoreImpl) storeKey(dbKey string) (string, bool) {
+	if index.TenantOf(dbKey) != s.tenantID {
+		return "", false
 	}
+	if s.tenantID == "" {
+		return dbKey, true
+	}
+	return strings.TrimPrefix(dbKey, s.tenantID+"/"), true
+}
+
+// owns reports whether key is a key of this store's tenant rather than a key another
+// tenant's manifests are stored under
+func (s *manifestStoreImpl) owns(key string) bool {
+	return index.TenantOf(s.dbKey(key)) == s.tenantID
+}
 
-	if err := s.db.Set(key, value); err != nil {
-		return fmt.Errorf("db set: %w", err)
+// checkKey rejects keys that would be stored outside of this store's tenant
+func (s *manifestStoreImpl) checkKey(key string) error {
+	if !s.owns(key) {
+		return fmt.Errorf("%w: key %s is outside of the tenant", apperrors.ErrInvalid, key)
 	}
-	s.index.Set(key, value)
 	return nil
 }
 
-func (s *manifestStoreImpl) Delete(key string) error {
+// dbEntries returns entries keyed by their database keys
+func (s *manifestStoreImpl) dbEntries(entries map[string][]byte) map[string][]byte {
+	if s.tenantID == "" {
+		return entries
+	}
+	result := make(map[string][]byte, len(entries))
+	for key, value := range entries {
+		result[s.dbKey(key)] = value
+	}
+	return result
+}
+
+// Create stores value under key. A multi-document YAML value is split into its documents,
+// each stored under its own namespace/Kind/name key, with key recorded as their parent.
+func (s *manifestStoreImpl) Create(key string, value []byte) (err error) {
+	defer s.observe(metrics.OperationCreate, time.Now(), &err)
 
-	if _, exists := s.index.Get(key); !exists {
-		return fmt.Errorf("%w: manifest not found: %s", apperrors.ErrNotFound, key)
+	if err := s.checkKey(key); err != nil {
+		return err
+	}
+	childKeys, children, err := splitChildren(value)
+	if err != nil {
+		return err
+	}
+	if children != nil {
+		return s.writeParent(key, childKeys, children, nil)
 	}
 
-	if err := s.db.Delete(key); err != nil {
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		return txn.Create(key, value)
+	})
+}
 
-		if !errors.Is(err, database.ErrNotFound) {
-			return fmt.Errorf("db delete: %w", err)
+// Update replaces the manifest stored under key, keeping the previous value as a version
+// (see GetHistory). Updating a multi-document parent re-splits value and replaces all of its
+// children in one transaction.
+func (s *manifestStoreImpl) Update(key string, value []byte) (err error) {
+	defer s.observe(metrics.OperationUpdate, time.Now(), &err)
+
+	if _, isParent := s.Children(key); isParent {
+		docs, err := manifest.SplitMultiDoc(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", apperrors.ErrInvalidYAML, err)
+		}
+		childKeys, children, err := childrenOf(docs)
+		if err != nil {
+			return err
 		}
+		previous, _ := s.getParent(key)
+		return s.writeParent(key, childKeys, children, previous)
+	}
+
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		return txn.Update(key, value)
+	})
+}
 
-		s.logger.Info("key exists in index but not in DB, removing from index", "key", key)
+func (s *manifestStoreImpl) Delete(key string) (err error) {
+	defer s.observe(metrics.OperationDelete, time.Now(), &err)
+
+	if childKeys, isParent := s.Children(key); isParent {
+		return s.deleteParent(key, childKeys)
 	}
 
-	s.index.Delete(key)
-	return nil
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		return txn.Delete(key)
+	})
+}
+
+// CreateBatch creates all entries in one transaction, splitting multi-document values as
+// Create does. It fails without writing anything when one of the keys already exists.
+func (s *manifestStoreImpl) CreateBatch(entries map[string][]byte) (err error) {
+	defer s.observe(metrics.OperationCreate, time.Now(), &err)
+
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		for _, key := range sortedKeys(entries) {
+			_, isParent := txn.children(key)
+			if _, exists := txn.Get(key); exists || isParent {
+				return fmt.Errorf("%w: manifest already exists: %s", apperrors.ErrInvalid, key)
+			}
+			if err := txn.put(key, entries[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutBatch writes all entries in one transaction: missing manifests are created and existing
+// ones updated, keeping their previous values as versions
+func (s *manifestStoreImpl) PutBatch(entries map[string][]byte) (err error) {
+	defer s.observe(metrics.OperationUpdate, time.Now(), &err)
+
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		for _, key := range sortedKeys(entries) {
+			var err error
+			if _, exists := txn.Get(key); exists {
+				err = txn.Update(key, entries[key])
+			} else {
+				err = txn.put(key, entries[key])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *manifestStoreImpl) DeleteBatch(keys []string) (err error) {
+	defer s.observe(metrics.OperationDelete, time.Now(), &err)
+
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		for _, key := range keys {
+			if childKeys, isParent := txn.children(key); isParent {
+				if err := txn.deleteParent(key, childKeys); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sortedKeys returns the keys of entries in order, so batches write them deterministically
+func sortedKeys(entries map[string][]byte) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Rename moves the manifest stored under oldKey to newKey in one transaction
+func (s *manifestStoreImpl) Rename(oldKey, newKey string) (err error) {
+	defer s.observe(metrics.OperationRename, time.Now(), &err)
+
+	return s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		return txn.Rename(oldKey, newKey)
+	})
 }
 
 func (s *manifestStoreImpl) Get(key string) ([]byte, bool) {
-	return s.index.Get(key)
+	defer s.observe(metrics.OperationGet, time.Now(), nil)
+
+	if value, isParent := s.getParent(key); isParent {
+		return value, true
+	}
+	if !s.owns(key) {
+		return nil, false
+	}
+	return s.index.Get(s.dbKey(key))
+}
+
+func (s *manifestStoreImpl) GetWithETag(key string) ([]byte, string, bool) {
+	if value, isParent := s.getParent(key); isParent {
+		return value, ComputeETag(value), true
+	}
+
+	value, exists := s.index.Get(s.dbKey(key))
+	if !exists || !s.owns(key) {
+		return nil, "", false
+	}
+
+	// Embedded manifests that were never written have no stored ETag
+	if stored, err := s.db.Get(ETagKey(s.dbKey(key))); err == nil {
+		return value, string(stored), true
+	}
+	return value, ComputeETag(value), true
 }
 
 func (s *manifestStoreImpl) List() map[string][]byte {
-	return s.index.List()
+	defer s.observe(metrics.OperationList, time.Now(), nil)
+
+	manifests := s.index.ListByTenant(s.tenantID)
+	if s.tenantID == "" {
+		return manifests
+	}
+	result := make(map[string][]byte, len(manifests))
+	for dbKey, value := range manifests {
+		if key, ok := s.storeKey(dbKey); ok {
+			result[key] = value
+		}
+	}
+	return result
 }
 
+func (s *manifestStoreImpl) ListByKind(kind string) map[string][]byte {
+	return s.getAll(s.index.ListByTenantKind(s.tenantID, kind))
+}
+
+func (s *manifestStoreImpl) ListByNamespace(namespace string) map[string][]byte {
+	return s.getAll(s.index.ListByTenantNamespace(s.tenantID, namespace))
+}
+
+// getAll returns the indexed manifests stored under the database keys dbKeys by their store
+// keys, skipping keys deleted in the meantime
+func (s *manifestStoreImpl) getAll(dbKeys []string) map[string][]byte {
+	result := make(map[string][]byte, len(dbKeys))
+	for _, dbKey := range dbKeys {
+		key, ok := s.storeKey(dbKey)
+		if !ok {
+			continue
+		}
+		if value, ok := s.index.Get(dbKey); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (s *manifestStoreImpl) Count() int {
+	return s.index.CountByTenant(s.tenantID)
+}
+
+// withETags returns entries together with the ETag key of every entry
+func withETags(entries map[string][]byte) map[string][]byte {
+	items := make(map[string][]byte, len(entries)*2)
+	for key, value := range entries {
+		items[key] = value
+		items[ETagKey(key)] = []byte(ComputeETag(value))
+	}
+	return items
+}
@@ -3,12 +3,13 @@ package store
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
-	"github.com/go-logr/logr"
 	"github.com/garunski/conductor-framework/pkg/framework/database"
 	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
 	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/go-logr/logr"
 )
 
 func TestManifestStore_Create(t *testing.T) {
@@ -425,3 +426,600 @@ func TestManifestStore_IndexSynchronization(t *testing.T) {
 	}
 }
 
+func TestManifestStore_Count(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	if got := store.Count(); got != 0 {
+		t.Errorf("Count() on empty store = %d, want 0", got)
+	}
+
+	if err := store.Create("key1", []byte("value1")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := store.Create("key2", []byte("value2")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if got := store.Count(); got != 2 {
+		t.Errorf("Count() after Create = %d, want 2", got)
+	}
+
+	if err := store.Update("key1", []byte("updated")); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if got := store.Count(); got != 2 {
+		t.Errorf("Count() after Update = %d, want 2", got)
+	}
+
+	if err := store.Delete("key2"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if got := store.Count(); got != 1 {
+		t.Errorf("Count() after Delete = %d, want 1", got)
+	}
+}
+
+func TestManifestStore_ListByKind(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	for _, key := range []string{"default/Service/api", "default/Deployment/api", "other/Service/web"} {
+		if err := store.Create(key, []byte("value")); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	services := store.ListByKind("Service")
+	if len(services) != 2 {
+		t.Fatalf("ListByKind(Service) returned %d manifests, want 2", len(services))
+	}
+	for _, key := range []string{"default/Service/api", "other/Service/web"} {
+		if _, ok := services[key]; !ok {
+			t.Errorf("ListByKind(Service) missing %s", key)
+		}
+	}
+
+	if got := store.ListByKind("Secret"); len(got) != 0 {
+		t.Errorf("ListByKind(Secret) = %v, want empty", got)
+	}
+}
+
+func TestManifestStore_ListByNamespace(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	for _, key := range []string{"default/Service/api", "default/Deployment/api", "other/Service/web"} {
+		if err := store.Create(key, []byte("value")); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	manifests := store.ListByNamespace("default")
+	if len(manifests) != 2 {
+		t.Fatalf("ListByNamespace(default) returned %d manifests, want 2", len(manifests))
+	}
+	for _, key := range []string{"default/Service/api", "default/Deployment/api"} {
+		if _, ok := manifests[key]; !ok {
+			t.Errorf("ListByNamespace(default) missing %s", key)
+		}
+	}
+
+	if err := store.Delete("default/Service/api"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if got := store.ListByNamespace("default"); len(got) != 1 {
+		t.Errorf("ListByNamespace(default) after Delete returned %d manifests, want 1", len(got))
+	}
+}
+
+func TestManifestStore_CreateBatchAndDeleteBatch(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	entries := map[string][]byte{
+		"default/ConfigMap/a": []byte("a"),
+		"default/ConfigMap/b": []byte("b"),
+	}
+	if err := store.CreateBatch(entries); err != nil {
+		t.Fatalf("CreateBatch() failed: %v", err)
+	}
+	for key, value := range entries {
+		if stored, err := db.Get(key); err != nil || string(stored) != string(value) {
+			t.Errorf("DB value for %s = %q, %v, want %q", key, stored, err, value)
+		}
+	}
+	if store.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", store.Count())
+	}
+
+	// A missing key rejects the whole batch
+	err = store.DeleteBatch([]string{"default/ConfigMap/a", "default/ConfigMap/missing"})
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("DeleteBatch() error = %v, want ErrNotFound", err)
+	}
+	if _, ok := store.Get("default/ConfigMap/a"); !ok {
+		t.Error("DeleteBatch() removed a manifest despite failing")
+	}
+
+	if err := store.DeleteBatch([]string{"default/ConfigMap/a", "default/ConfigMap/b"}); err != nil {
+		t.Fatalf("DeleteBatch() failed: %v", err)
+	}
+	if store.Count() != 0 {
+		t.Errorf("Count() = %d after DeleteBatch, want 0", store.Count())
+	}
+	if _, err := db.Get("default/ConfigMap/a"); err == nil {
+		t.Error("manifest still found in DB after DeleteBatch")
+	}
+}
+
+func TestManifestStore_CreateBatchRejectsExistingKeys(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	if err := store.Create("default/ConfigMap/a", []byte("a")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	err = store.CreateBatch(map[string][]byte{
+		"default/ConfigMap/a": []byte("replaced"),
+		"default/ConfigMap/b": []byte("b"),
+	})
+	if !errors.Is(err, apperrors.ErrInvalid) || !strings.Contains(err.Error(), "default/ConfigMap/a") {
+		t.Fatalf("CreateBatch() error = %v, want ErrInvalid naming the existing key", err)
+	}
+	if value, _ := store.Get("default/ConfigMap/a"); string(value) != "a" {
+		t.Errorf("Get() = %q after a rejected batch, want the original value", value)
+	}
+	if _, ok := store.Get("default/ConfigMap/b"); ok {
+		t.Error("CreateBatch() stored a manifest despite failing")
+	}
+}
+
+func TestManifestStore_BatchMultiDoc(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	parent := "default/Bundle/app"
+	if err := store.CreateBatch(map[string][]byte{parent: []byte(multiDocManifest)}); err != nil {
+		t.Fatalf("CreateBatch() failed: %v", err)
+	}
+	if children, ok := store.Children(parent); !ok || len(children) != 3 {
+		t.Fatalf("Children() = %v, %v, want 3 children", children, ok)
+	}
+	if store.Count() != 3 {
+		t.Errorf("Count() = %d, want 3 documents", store.Count())
+	}
+	restored := NewManifestStore(db, index.NewIndex(), logr.Discard())
+	if children, ok := restored.Children(parent); !ok || len(children) != 3 {
+		t.Errorf("Children() after reopening = %v, %v, want 3 children", children, ok)
+	}
+
+	if err := store.DeleteBatch([]string{parent}); err != nil {
+		t.Fatalf("DeleteBatch() failed: %v", err)
+	}
+	if _, ok := store.Children(parent); ok {
+		t.Error("Children() still lists the deleted parent")
+	}
+	if store.Count() != 0 {
+		t.Errorf("Count() = %d after DeleteBatch, want 0", store.Count())
+	}
+}
+
+func TestManifestStore_GetWithETag(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	key := "default/ConfigMap/etag"
+	if _, _, ok := store.GetWithETag(key); ok {
+		t.Error("GetWithETag() found a manifest that was never created")
+	}
+
+	if err := store.Create(key, []byte("v1")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	value, etag, ok := store.GetWithETag(key)
+	if !ok || string(value) != "v1" || etag != ComputeETag([]byte("v1")) {
+		t.Errorf("GetWithETag() = %q, %q, %v", value, etag, ok)
+	}
+	if stored, err := db.Get(ETagKey(key)); err != nil || string(stored) != etag {
+		t.Errorf("stored ETag = %q, %v, want %q", stored, err, etag)
+	}
+
+	if err := store.Update(key, []byte("v2")); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if _, updated, _ := store.GetWithETag(key); updated == etag {
+		t.Error("ETag did not change after Update")
+	}
+
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := db.Get(ETagKey(key)); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("ETag key still stored after Delete: %v", err)
+	}
+}
+
+const multiDocManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: default
+data:
+  mode: debug
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: default
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 1
+`
+
+func TestManifestStore_CreateMultiDoc(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	parent := "default/Bundle/app"
+	if err := store.Create(parent, []byte(multiDocManifest)); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	wantChildren := []string{"default/ConfigMap/app-config", "default/Service/app", "default/Deployment/app"}
+	children, ok := store.Children(parent)
+	if !ok || len(children) != len(wantChildren) {
+		t.Fatalf("Children() = %v, %v, want %v", children, ok, wantChildren)
+	}
+	for i, want := range wantChildren {
+		if children[i] != want {
+			t.Errorf("Children()[%d] = %q, want %q", i, children[i], want)
+		}
+	}
+
+	if store.Count() != 3 {
+		t.Errorf("Count() = %d, want 3 documents", store.Count())
+	}
+	if _, listed := store.List()[parent]; listed {
+		t.Error("List() includes the parent key")
+	}
+	if value, ok := store.Get("default/Service/app"); !ok || !strings.Contains(string(value), "kind: Service") {
+		t.Errorf("Get() child = %q, %v", value, ok)
+	}
+
+	value, ok := store.Get(parent)
+	if !ok || strings.Count(string(value), "---") != 2 {
+		t.Errorf("Get() parent = %q, %v, want the three documents", value, ok)
+	}
+
+	restored := NewManifestStore(db, index.NewIndex(), logr.Discard())
+	if children, ok := restored.Children(parent); !ok || len(children) != 3 {
+		t.Errorf("Children() after reopening = %v, %v, want 3 children", children, ok)
+	}
+}
+
+func TestManifestStore_UpdateMultiDoc(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	parent := "default/Bundle/app"
+	if err := store.Create(parent, []byte(multiDocManifest)); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	updated := strings.ReplaceAll(multiDocManifest, "namespace: default", "namespace: default\n  labels:\n    version: v2")
+	updated = updated[:strings.LastIndex(updated, "---")]
+	if err := store.Update(parent, []byte(updated)); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	children, _ := store.Children(parent)
+	if len(children) != 2 {
+		t.Fatalf("Children() after Update = %v, want 2 children", children)
+	}
+	for _, child := range children {
+		value, ok := store.Get(child)
+		if !ok || !strings.Contains(string(value), "version: v2") {
+			t.Errorf("Get(%q) = %q, want the updated document", child, value)
+		}
+	}
+	if _, ok := store.Get("default/Deployment/app"); ok {
+		t.Error("dropped document is still stored after Update")
+	}
+	if _, err := db.Get("default/Deployment/app"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("dropped document is still in the DB: %v", err)
+	}
+
+	if err := store.Delete(parent); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if store.Count() != 0 {
+		t.Errorf("Count() after deleting the parent = %d, want 0", store.Count())
+	}
+	if _, ok := store.Get(parent); ok {
+		t.Error("parent still found after Delete")
+	}
+}
+
+func TestManifestStore_CreateMultiDocRejectsInvalidDocument(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	parent := "default/Bundle/app"
+	invalid := multiDocManifest + "---\nkind: [Secret\n"
+	if err := store.Create(parent, []byte(invalid)); !errors.Is(err, apperrors.ErrInvalidYAML) {
+		t.Fatalf("Create() error = %v, want ErrInvalidYAML", err)
+	}
+	if _, ok := store.Get(parent); ok {
+		t.Error("invalid multi-document manifest stored as a single manifest")
+	}
+	if store.Count() != 0 {
+		t.Errorf("Count() = %d, want nothing stored", store.Count())
+	}
+}
+
+func TestManifestStore_CreateMultiDocRejectsChildOfAnotherParent(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	if err := store.Create("default/Bundle/app", []byte(multiDocManifest)); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	other := "apiVersion: v1\nkind: Service\nmetadata:\n  name: app\n  namespace: default\n" +
+		"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n  namespace: default\n"
+	if err := store.Create("default/Bundle/other", []byte(other)); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Fatalf("Create() of a parent claiming another parent's document error = %v, want ErrInvalid", err)
+	}
+	if _, ok := store.Children("default/Bundle/other"); ok {
+		t.Error("rejected parent was recorded")
+	}
+	if _, ok := store.Get("default/ConfigMap/other"); ok {
+		t.Error("document of the rejected parent was stored")
+	}
+	if children, _ := store.Children("default/Bundle/app"); len(children) != 3 {
+		t.Errorf("Children() of the first parent = %v, want its 3 documents", children)
+	}
+}
+
+func TestManifestStore_TenantIsolation(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	idx := index.NewIndex()
+	shared := NewManifestStore(db, idx, logr.Discard())
+	tenantA := NewTenantManifestStore(db, idx, logr.Discard(), "team-a")
+	tenantB := NewTenantManifestStore(db, idx, logr.Discard(), "team-b")
+
+	key := "default/ConfigMap/app"
+	for name, s := range map[string]ManifestStore{"shared": shared, "team-a": tenantA, "team-b": tenantB} {
+		if err := s.Create(key, []byte(name)); err != nil {
+			t.Fatalf("Create() for %s failed: %v", name, err)
+		}
+	}
+	if err := tenantA.Create("default/Service/api", []byte("team-a")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	for name, s := range map[string]ManifestStore{"shared": shared, "team-a": tenantA, "team-b": tenantB} {
+		if value, ok := s.Get(key); !ok || string(value) != name {
+			t.Errorf("Get() for %s = %q, %v, want its own manifest", name, value, ok)
+		}
+	}
+	if got := tenantA.Count(); got != 2 {
+		t.Errorf("Count() for team-a = %d, want 2", got)
+	}
+	if got := tenantB.List(); len(got) != 1 {
+		t.Errorf("List() for team-b = %v, want only its own manifest", got)
+	}
+	if got := shared.List(); len(got) != 1 {
+		t.Errorf("List() for the zero tenant = %v, want no tenant manifests", got)
+	}
+	if got := tenantA.ListByNamespace("default"); len(got) != 2 {
+		t.Errorf("ListByNamespace(default) for team-a returned %d manifests, want 2", len(got))
+	}
+	if got := tenantB.ListByKind("Service"); len(got) != 0 {
+		t.Errorf("ListByKind(Service) for team-b = %v, want none", got)
+	}
+	if stored, err := db.Get("team-a/" + key); err != nil || string(stored) != "team-a" {
+		t.Errorf("DB value under the tenant prefix = %q, %v", stored, err)
+	}
+
+	// Keys of other tenants can be neither read nor written through a tenant store
+	if _, ok := shared.Get("team-a/" + key); ok {
+		t.Error("zero tenant can read a team-a manifest by its prefixed key")
+	}
+	if err := tenantB.Create("team-a/"+key, []byte("team-b")); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("Create() of a key in another tenant error = %v, want ErrInvalid", err)
+	}
+	if err := tenantB.Delete("default/Service/api"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Delete() of a team-a manifest from team-b error = %v, want ErrNotFound", err)
+	}
+
+	if err := tenantA.Delete(key); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	for name, s := range map[string]ManifestStore{"shared": shared, "team-b": tenantB} {
+		if _, ok := s.Get(key); !ok {
+			t.Errorf("Delete() for team-a removed the manifest of %s", name)
+		}
+	}
+}
+
+func TestManifestStore_TenantMultiDoc(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	idx := index.NewIndex()
+	tenant := NewTenantManifestStore(db, idx, logr.Discard(), "team-a")
+
+	parent := "default/Bundle/app"
+	if err := tenant.Create(parent, []byte(multiDocManifest)); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if _, ok := NewManifestStore(db, idx, logr.Discard()).Children(parent); ok {
+		t.Error("zero tenant sees the parent of a team-a manifest")
+	}
+
+	restored := NewTenantManifestStore(db, index.NewIndex(), logr.Discard(), "team-a")
+	children, ok := restored.Children(parent)
+	if !ok || len(children) != 3 || children[0] != "default/ConfigMap/app-config" {
+		t.Errorf("Children() after reopening = %v, %v, want the three tenant-relative keys", children, ok)
+	}
+}
+
+func TestValidateTenantID(t *testing.T) {
+	tests := []struct {
+		tenantID string
+		wantErr  bool
+	}{
+		{tenantID: "team-a"},
+		{tenantID: "t1"},
+		{tenantID: "", wantErr: true},
+		{tenantID: "Team-A", wantErr: true},
+		{tenantID: "team/a", wantErr: true},
+		{tenantID: "events", wantErr: true},
+		{tenantID: "multidoc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tenantID, func(t *testing.T) {
+			err := ValidateTenantID(tt.tenantID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTenantID(%q) error = %v, wantErr %v", tt.tenantID, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, apperrors.ErrInvalid) {
+				t.Errorf("ValidateTenantID(%q) error = %v, want ErrInvalid", tt.tenantID, err)
+			}
+		})
+	}
+}
+
+func TestManifestStore_Rename(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	store := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	value := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")
+	if err := store.Create("default/ConfigMap/a", value); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := store.Create("default/ConfigMap/b", []byte("b")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	if err := store.Rename("default/ConfigMap/a", "default/ConfigMap/b"); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("Rename() onto an existing key error = %v, want ErrInvalid", err)
+	}
+	if err := store.Rename("default/ConfigMap/missing", "default/ConfigMap/c"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Rename() of a missing key error = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Rename("default/ConfigMap/a", "staging/ConfigMap/a"); err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+	if got, ok := store.Get("staging/ConfigMap/a"); !ok || string(got) != string(value) {
+		t.Errorf("Get(new key) = %q, %v, want %q", got, ok, value)
+	}
+	if _, ok := store.Get("default/ConfigMap/a"); ok {
+		t.Error("old key still found in index after Rename")
+	}
+	if _, err := db.Get("default/ConfigMap/a"); err == nil {
+		t.Error("old key still found in DB after Rename")
+	}
+	if _, err := db.Get(ETagKey("default/ConfigMap/a")); err == nil {
+		t.Error("ETag of the old key still found in DB after Rename")
+	}
+	if stored, err := db.Get("staging/ConfigMap/a"); err != nil || string(stored) != string(value) {
+		t.Errorf("DB value for new key = %q, %v, want %q", stored, err, value)
+	}
+	if got := store.ListByNamespace("staging"); len(got) != 1 {
+		t.Errorf("ListByNamespace(staging) = %v, want the renamed manifest", got)
+	}
+}
+
+func TestManifestStore_Checksum(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	idx := index.NewIndex()
+	store := NewManifestStore(db, idx, logr.Discard())
+	tenant := NewTenantManifestStore(db, idx, logr.Discard(), "team-a")
+
+	empty := store.Checksum()
+	if err := store.Create("default/ConfigMap/a", []byte("a")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	created := store.Checksum()
+	if created == empty {
+		t.Error("Checksum() did not change after Create")
+	}
+	if want := ComputeChecksum(map[string][]byte{"default/ConfigMap/a": []byte("a")}); created != want {
+		t.Errorf("Checksum() = %s, want %s", created, want)
+	}
+
+	// Manifests of other tenants are not part of the checksum
+	if err := tenant.Create("default/ConfigMap/a", []byte("tenant")); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if got := store.Checksum(); got != created {
+		t.Error("Checksum() changed after a write of another tenant")
+	}
+
+	if err := store.Update("default/ConfigMap/a", []byte("b")); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if store.Checksum() == created {
+		t.Error("Checksum() did not change after Update")
+	}
+
+	if err := store.Delete("default/ConfigMap/a"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if got := store.Checksum(); got != empty {
+		t.Errorf("Checksum() after Delete = %s, want %s", got, empty)
+	}
+}
@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+)
+
+// ManifestStoreTxn reads and writes single-document manifests inside one database transaction.
+// Its writes reach the index only after the transaction commits.
+type ManifestStoreTxn struct {
+	store *manifestStoreImpl
+	db    *database.DB
+
+	// pending holds the value written to each key in this transaction, nil for deleted keys
+	pending map[string][]byte
+	order   []string
+	// renames holds the old and new database keys of each Rename, which the index applies
+	// under one lock so readers never see a renamed manifest under both keys or neither
+	renames [][2]string
+	// parents holds the children written for each multi-document manifest, nil for deleted ones
+	parents map[string][]string
+}
+
+// Get returns the manifest stored under key as this transaction sees it. Reading a key
+// registers it with the transaction, which then fails to commit if another one wrote it.
+func (t *ManifestStoreTxn) Get(key string) ([]byte, bool) {
+	if !t.store.owns(key) {
+		return nil, false
+	}
+	dbKey := t.store.dbKey(key)
+	if value, written := t.pending[dbKey]; written {
+		return value, value != nil
+	}
+	value, err := t.db.Get(dbKey)
+	if err == nil {
+		return value, true
+	}
+	// Embedded manifests that were never written are only in the index
+	if errors.Is(err, database.ErrNotFound) {
+		return t.store.index.Get(dbKey)
+	}
+	return nil, false
+}
+
+// Create stores value under key, replacing any manifest stored there
+func (t *ManifestStoreTxn) Create(key string, value []byte) error {
+	if err := t.store.checkKey(key); err != nil {
+		return err
+	}
+	if err := t.checkSingleDocument(key, value); err != nil {
+		return err
+	}
+	return t.set(key, value)
+}
+
+// Update replaces the manifest stored under key, which must exist, and keeps the previous
+// value as a version
+func (t *ManifestStoreTxn) Update(key string, value []byte) error {
+	if err := t.checkSingleDocument(key, value); err != nil {
+		return err
+	}
+	previous, exists := t.Get(key)
+	if !exists {
+		return fmt.Errorf("%w: manifest not found: %s", apperrors.ErrNotFound, key)
+	}
+	if err := t.store.saveVersion(t.db, t.store.dbKey(key), previous); err != nil {
+		return err
+	}
+	return t.set(key, value)
+}
+
+// Delete removes the manifest stored under key, which must exist
+func (t *ManifestStoreTxn) Delete(key string) error {
+	if _, isParent := t.store.Children(key); isParent {
+		return fmt.Errorf("%w: multi-document manifest %s cannot be deleted in a transaction", apperrors.ErrInvalid, key)
+	}
+	if _, exists := t.Get(key); !exists {
+		return fmt.Errorf("%w: manifest not found: %s", apperrors.ErrNotFound, key)
+	}
+	dbKey := t.store.dbKey(key)
+	if err := t.db.BatchDelete([]string{dbKey, ETagKey(dbKey)}); err != nil {
+		return fmt.Errorf("db delete: %w", err)
+	}
+	t.record(dbKey, nil)
+	return nil
+}
+
+// Rename moves the manifest stored under oldKey to newKey, which must not exist. The documents
+// of a multi-document manifest are stored under keys derived from their content, so a parent
+// cannot be renamed.
+func (t *ManifestStoreTxn) Rename(oldKey, newKey string) error {
+	if err := t.store.checkKey(newKey); err != nil {
+		return err
+	}
+	if _, isParent := t.store.Children(oldKey); isParent {
+		return fmt.Errorf("%w: multi-document manifest %s cannot be renamed", apperrors.ErrInvalid, oldKey)
+	}
+	value, exists := t.Get(oldKey)
+	if !exists {
+		return fmt.Errorf("%w: manifest not found: %s", apperrors.ErrNotFound, oldKey)
+	}
+	_, isParent := t.store.Children(newKey)
+	if _, exists := t.Get(newKey); exists || isParent {
+		return fmt.Errorf("%w: manifest already exists: %s", apperrors.ErrInvalid, newKey)
+	}
+
+	if err := t.set(newKey, value); err != nil {
+		return err
+	}
+	oldDBKey := t.store.dbKey(oldKey)
+	if err := t.db.BatchDelete([]string{oldDBKey, ETagKey(oldDBKey)}); err != nil {
+		return fmt.Errorf("db delete: %w", err)
+	}
+	t.record(oldDBKey, nil)
+	if err := t.store.moveHistory(t.db, oldDBKey, t.store.dbKey(newKey)); err != nil {
+		return err
+	}
+	t.renames = append(t.renames, [2]string{oldDBKey, t.store.dbKey(newKey)})
+	return nil
+}
+
+// checkSingleDocument rejects multi-document manifests, whose documents the store keeps under
+// keys of their own
+func (t *ManifestStoreTxn) checkSingleDocument(key string, value []byte) error {
+	if _, isParent := t.store.Children(key); isParent {
+		return fmt.Errorf("%w: multi-document manifest %s cannot be written in a transaction", apperrors.ErrInvalid, key)
+	}
+	childKeys, _, err := splitChildren(value)
+	if err != nil {
+		return err
+	}
+	if childKeys != nil {
+		return fmt.Errorf("%w: multi-document value for %s cannot be written in a transaction", apperrors.ErrInvalid, key)
+	}
+	return nil
+}
+
+func (t *ManifestStoreTxn) set(key string, value []byte) error {
+	dbKey := t.store.dbKey(key)
+	if err := t.db.BatchSet(withETags(map[string][]byte{dbKey: value})); err != nil {
+		return fmt.Errorf("db set: %w", err)
+	}
+	t.record(dbKey, append([]byte{}, value...))
+	return nil
+}
+
+func (t *ManifestStoreTxn) record(dbKey string, value []byte) {
+	if _, seen := t.pending[dbKey]; !seen {
+		t.order = append(t.order, dbKey)
+	}
+	t.pending[dbKey] = value
+}
+
+// applyToIndex writes the outcome of a committed transaction to the index
+func (t *ManifestStoreTxn) applyToIndex() {
+	for _, rename := range t.renames {
+		t.store.index.Rename(rename[0], rename[1])
+	}
+	for _, dbKey := range t.order {
+		if value := t.pending[dbKey]; value != nil {
+			t.store.index.Set(dbKey, value)
+		} else {
+			t.store.index.Delete(dbKey)
+		}
+	}
+
+	if len(t.parents) == 0 {
+		return
+	}
+	t.store.parentsMu.Lock()
+	defer t.store.parentsMu.Unlock()
+	for key, childKeys := range t.parents {
+		if childKeys != nil {
+			t.store.parents[key] = childKeys
+		} else {
+			delete(t.store.parents, key)
+		}
+	}
+}
+
+// WithTransaction runs fn in a database transaction and updates the index once it commits.
+// Nothing is written when fn returns an error or the commit fails. Writes of the store are
+// serialized so the index applies them in the order the database committed them.
+func (s *manifestStoreImpl) WithTransaction(fn func(txn *ManifestStoreTxn) error) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var committed *ManifestStoreTxn
+	err := s.db.Transaction(func(db *database.DB) error {
+		txn := &ManifestStoreTxn{store: s, db: db, pending: make(map[string][]byte), parents: make(map[string][]string)}
+		if err := fn(txn); err != nil {
+			return err
+		}
+		committed = txn
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	committed.applyToIndex()
+	return nil
+}
+
+// componentPrefixes are the database key prefixes of data other than manifests
+var componentPrefixes = func() []string {
+	prefixes := make([]string, 0, len(reservedTenantIDs))
+	for id := range reservedTenantIDs {
+		prefixes = append(prefixes, id+"/")
+	}
+	return prefixes
+}()
+
+// IsManifestKey reports whether a database key holds a manifest rather than an ETag or the
+// data of another component, such as events
+func IsManifestKey(key string) bool {
+	if IsETagKey(key) {
+		return false
+	}
+	for _, prefix := range componentPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reconcile rebuilds the index entries and multi-document records of this store's tenant
+// from the database, dropping entries the database no longer has and restoring embedded
+// manifests that no database entry overrides
+func (s *manifestStoreImpl) Reconcile(ctx context.Context) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	items, err := s.db.List(s.dbKey(""))
+	if err != nil {
+		return fmt.Errorf("db list: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored := make(map[string][]byte, len(items)/2)
+	for key, value := range items {
+		if IsManifestKey(key) && index.TenantOf(key) == s.tenantID {
+			stored[key] = value
+		}
+	}
+	s.index.RebuildTenant(s.tenantID, stored)
+
+	parents := s.readParents()
+	s.parentsMu.Lock()
+	s.parents = parents
+	s.parentsMu.Unlock()
+	return nil
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+)
+
+func newTxnTestStore(t *testing.T) (*database.DB, *index.ManifestIndex, ManifestStore) {
+	t.Helper()
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	idx := index.NewIndex()
+	return db, idx, NewManifestStore(db, idx, logr.Discard())
+}
+
+func TestManifestStore_WithTransaction_Commit(t *testing.T) {
+	db, idx, s := newTxnTestStore(t)
+	if err := s.Create("default/ConfigMap/old", []byte("old")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	err := s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		if err := txn.Create("default/ConfigMap/new", []byte("new")); err != nil {
+			return err
+		}
+		if _, ok := idx.Get("default/ConfigMap/new"); ok {
+			t.Error("index has a write of an uncommitted transaction")
+		}
+		if value, ok := txn.Get("default/ConfigMap/new"); !ok || string(value) != "new" {
+			t.Errorf("txn.Get() = %q, %v, want the transaction's own write", value, ok)
+		}
+		return txn.Delete("default/ConfigMap/old")
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	if value, ok := s.Get("default/ConfigMap/new"); !ok || string(value) != "new" {
+		t.Errorf("Get(new) = %q, %v, want new", value, ok)
+	}
+	if _, ok := s.Get("default/ConfigMap/old"); ok {
+		t.Error("Get(old) found a manifest deleted in the transaction")
+	}
+	if _, err := db.Get("default/ConfigMap/old"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("db.Get(old) error = %v, want not found", err)
+	}
+}
+
+func TestManifestStore_WithTransaction_Rollback(t *testing.T) {
+	db, _, s := newTxnTestStore(t)
+	if err := s.Create("default/ConfigMap/app", []byte("v1")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	failure := errors.New("abort")
+	err := s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		if err := txn.Update("default/ConfigMap/app", []byte("v2")); err != nil {
+			return err
+		}
+		if err := txn.Create("default/ConfigMap/other", []byte("other")); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("WithTransaction() error = %v, want %v", err, failure)
+	}
+
+	if value, _ := s.Get("default/ConfigMap/app"); string(value) != "v1" {
+		t.Errorf("Get(app) = %q, want v1", value)
+	}
+	if value, _ := db.Get("default/ConfigMap/app"); string(value) != "v1" {
+		t.Errorf("db.Get(app) = %q, want v1", value)
+	}
+	if _, ok := s.Get("default/ConfigMap/other"); ok {
+		t.Error("Get(other) found a manifest of a rolled back transaction")
+	}
+}
+
+func TestManifestStore_WithTransaction_RejectsMultiDoc(t *testing.T) {
+	_, _, s := newTxnTestStore(t)
+
+	err := s.WithTransaction(func(txn *ManifestStoreTxn) error {
+		return txn.Create("bundle", []byte(multiDocManifest))
+	})
+	if !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("WithTransaction() error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestManifestStore_ConcurrentWritesStayInSync(t *testing.T) {
+	db, idx, s := newTxnTestStore(t)
+
+	const keys = 5
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				key := fmt.Sprintf("default/ConfigMap/cm-%d", (worker+i)%keys)
+				value := []byte(fmt.Sprintf("worker-%d-%d", worker, i))
+				switch i % 3 {
+				case 0:
+					_ = s.Create(key, value)
+				case 1:
+					_ = s.Update(key, value)
+				default:
+					_ = s.Delete(key)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("default/ConfigMap/cm-%d", i)
+		indexed, inIndex := idx.Get(key)
+		stored, err := db.Get(key)
+		inDB := err == nil
+		if inIndex != inDB || string(indexed) != string(stored) {
+			t.Errorf("%s: index = %q (%v), db = %q (%v)", key, indexed, inIndex, stored, inDB)
+		}
+	}
+}
+
+func TestManifestStore_Reconcile(t *testing.T) {
+	db, idx, _ := newTxnTestStore(t)
+	idx.Merge(map[string][]byte{"default/ConfigMap/embedded": []byte("embedded")}, nil)
+	s := NewManifestStore(db, idx, logr.Discard())
+
+	if err := s.Create("default/ConfigMap/stored", []byte("stored")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := db.Set("events/00000000000000000001/id", []byte("{}")); err != nil {
+		t.Fatalf("db.Set() error = %v", err)
+	}
+
+	// Drift the index away from the database
+	idx.Delete("default/ConfigMap/stored")
+	idx.Set("default/ConfigMap/ghost", []byte("ghost"))
+
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	want := map[string]string{
+		"default/ConfigMap/embedded": "embedded",
+		"default/ConfigMap/stored":   "stored",
+	}
+	got := s.List()
+	if len(got) != len(want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if string(got[key]) != value {
+			t.Errorf("List()[%s] = %q, want %q", key, got[key], value)
+		}
+	}
+}
+
+func TestIsManifestKey(t *testing.T) {
+	tests := map[string]bool{
+		"default/ConfigMap/app":          true,
+		"team-a/default/ConfigMap/app":   true,
+		"default/ConfigMap/app/_etag":    false,
+		"events/00000000000000000001/id": false,
+		"multidoc/default/Bundle/app":    false,
+		"managed/default/ConfigMap/app":  false,
+		"confirm/token":                  false,
+	}
+	for key, want := range tests {
+		if got := IsManifestKey(key); got != want {
+			t.Errorf("IsManifestKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
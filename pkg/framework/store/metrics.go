@@ -0,0 +1,31 @@
+package store
+
+import (
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
+)
+
+// WithMetrics records the create, update, delete, get and list operations of the store in m
+func WithMetrics(m *metrics.StoreMetrics) Option {
+	return func(s *manifestStoreImpl) {
+		s.metrics = m
+	}
+}
+
+// observe records an operation that started at start and failed when *errp is non-nil.
+// Writes also refresh the number of indexed manifests. Callers defer it with a pointer to
+// their named error result, or nil for operations that cannot fail.
+func (s *manifestStoreImpl) observe(operation string, start time.Time, errp *error) {
+	if s.metrics == nil {
+		return
+	}
+	var err error
+	if errp != nil {
+		err = *errp
+	}
+	s.metrics.ObserveOperation(operation, start, err)
+	if operation == metrics.OperationCreate || operation == metrics.OperationUpdate || operation == metrics.OperationDelete {
+		s.metrics.SetKeys(s.index.Count())
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	"github.com/garunski/conductor-framework/pkg/framework/index"
+	"github.com/garunski/conductor-framework/pkg/framework/metrics"
+)
+
+func TestManifestStore_Metrics(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	m := metrics.NewStoreMetrics(prometheus.NewRegistry())
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard(), WithMetrics(m))
+
+	for i := 0; i < 5; i++ {
+		if err := s.Create(fmt.Sprintf("default/ConfigMap/cm-%d", i), []byte("data")); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Update("default/ConfigMap/cm-0", []byte(fmt.Sprintf("data %d", i))); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	if err := s.Delete("default/ConfigMap/cm-4"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete("default/ConfigMap/missing"); err == nil {
+		t.Fatal("Delete() of a missing manifest should fail")
+	}
+	if err := s.Rename("default/ConfigMap/cm-3", "default/ConfigMap/cm-5"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	s.Get("default/ConfigMap/cm-1")
+	s.List()
+
+	counts := map[[2]string]float64{
+		{metrics.OperationCreate, "success"}: 5,
+		{metrics.OperationUpdate, "success"}: 2,
+		{metrics.OperationDelete, "success"}: 1,
+		{metrics.OperationDelete, "failure"}: 1,
+		{metrics.OperationRename, "success"}: 1,
+		{metrics.OperationGet, "success"}:    1,
+		{metrics.OperationList, "success"}:   1,
+	}
+	for labels, want := range counts {
+		if got := testutil.ToFloat64(m.OperationsTotal.WithLabelValues(labels[0], labels[1])); got != want {
+			t.Errorf("conductor_store_operations_total%v = %v, want %v", labels, got, want)
+		}
+	}
+	if got := testutil.ToFloat64(m.KeysTotal); got != 4 {
+		t.Errorf("conductor_store_keys_total = %v, want 4", got)
+	}
+	if got := testutil.CollectAndCount(m.OperationDuration); got != 6 {
+		t.Errorf("conductor_store_operation_duration_seconds has %d series, want one per operation", got)
+	}
+}
+
+func TestManifestStore_BatchMetrics(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	m := metrics.NewStoreMetrics(prometheus.NewRegistry())
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard(), WithMetrics(m))
+
+	entries := map[string][]byte{"default/ConfigMap/a": []byte("a"), "default/ConfigMap/b": []byte("b")}
+	if err := s.CreateBatch(entries); err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+	if err := s.CreateBatch(entries); err == nil {
+		t.Fatal("CreateBatch() of existing manifests should fail")
+	}
+	if err := s.PutBatch(map[string][]byte{"default/ConfigMap/a": []byte("a2")}); err != nil {
+		t.Fatalf("PutBatch() error = %v", err)
+	}
+	if err := s.DeleteBatch([]string{"default/ConfigMap/b"}); err != nil {
+		t.Fatalf("DeleteBatch() error = %v", err)
+	}
+
+	counts := map[[2]string]float64{
+		{metrics.OperationCreate, "success"}: 1,
+		{metrics.OperationCreate, "failure"}: 1,
+		{metrics.OperationUpdate, "success"}: 1,
+		{metrics.OperationDelete, "success"}: 1,
+	}
+	for labels, want := range counts {
+		if got := testutil.ToFloat64(m.OperationsTotal.WithLabelValues(labels[0], labels[1])); got != want {
+			t.Errorf("conductor_store_operations_total%v = %v, want %v", labels, got, want)
+		}
+	}
+	if got := testutil.ToFloat64(m.KeysTotal); got != 1 {
+		t.Errorf("conductor_store_keys_total = %v, want 1", got)
+	}
+}
+
+func TestManifestStore_WithoutMetrics(t *testing.T) {
+	db, err := database.NewTestDB(t)
+	if err != nil {
+		t.Fatalf("failed to create test DB: %v", err)
+	}
+	s := NewManifestStore(db, index.NewIndex(), logr.Discard())
+
+	if err := s.Create("default/ConfigMap/cm", []byte("data")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := s.Delete("default/ConfigMap/missing"); err == nil {
+		t.Fatal("Delete() of a missing manifest should fail")
+	}
+}
@@ -0,0 +1,290 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/garunski/conductor-framework/pkg/framework/database"
+	apperrors "github.com/garunski/conductor-framework/pkg/framework/errors"
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// ParentKeyPrefix prefixes the database keys that list the children of a multi-document manifest
+const ParentKeyPrefix = "multidoc/"
+
+// splitChildren returns the documents of value keyed by their namespace/Kind/name, in
+// document order. It returns nil when value holds a single document, and an error when
+// value has separators but one of its documents does not parse.
+func splitChildren(value []byte) ([]string, map[string][]byte, error) {
+	if !manifest.HasDocumentSeparator(value) {
+		return nil, nil, nil
+	}
+	docs, err := manifest.SplitMultiDoc(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", apperrors.ErrInvalidYAML, err)
+	}
+	if len(docs) < 2 {
+		return nil, nil, nil
+	}
+	return childrenOf(docs)
+}
+
+func childrenOf(docs [][]byte) ([]string, map[string][]byte, error) {
+	keys := make([]string, 0, len(docs))
+	children := make(map[string][]byte, len(docs))
+	for i, doc := range docs {
+		key, err := manifest.DocumentKey(doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: document %d: %w", apperrors.ErrInvalid, i+1, err)
+		}
+		if _, duplicate := children[key]; duplicate {
+			return nil, nil, fmt.Errorf("%w: document %d: duplicate resource %s", apperrors.ErrInvalid, i+1, key)
+		}
+		keys = append(keys, key)
+		children[key] = doc
+	}
+	return keys, children, nil
+}
+
+// readParents reads the parent records of this store's tenant written by earlier processes.
+// A record lists the keys of its children as the store sees them, without the tenant.
+func (s *manifestStoreImpl) readParents() map[string][]string {
+	parents := make(map[string][]string)
+	records, err := s.db.List(ParentKeyPrefix + s.dbKey(""))
+	if err != nil {
+		s.logger.Error(err, "failed to load multi-document manifests")
+		return parents
+	}
+	for recordKey, value := range records {
+		key, ok := s.storeKey(strings.TrimPrefix(recordKey, ParentKeyPrefix))
+		if !ok || len(value) == 0 {
+			continue
+		}
+		parents[key] = strings.Split(string(value), "\n")
+	}
+	return parents
+}
+
+// Children returns the keys of the documents stored for the multi-document manifest key
+func (s *manifestStoreImpl) Children(key string) ([]string, bool) {
+	s.parentsMu.RLock()
+	defer s.parentsMu.RUnlock()
+	children, ok := s.parents[key]
+	if !ok {
+		return nil, false
+	}
+	return append([]string(nil), children...), true
+}
+
+// parentOf returns the multi-document manifest that childKey is a document of
+func (s *manifestStoreImpl) parentOf(childKey string) (string, bool) {
+	s.parentsMu.RLock()
+	defer s.parentsMu.RUnlock()
+	for parent, children := range s.parents {
+		for _, child := range children {
+			if child == childKey {
+				return parent, true
+			}
+		}
+	}
+	return "", false
+}
+
+// getParent joins the documents of the multi-document manifest key in their original order
+func (s *manifestStoreImpl) getParent(key string) ([]byte, bool) {
+	childKeys, ok := s.Children(key)
+	if !ok {
+		return nil, false
+	}
+	docs := make([][]byte, 0, len(childKeys))
+	for _, childKey := range childKeys {
+		if doc, exists := s.index.Get(s.dbKey(childKey)); exists {
+			docs = append(docs, doc)
+		}
+	}
+	return manifest.JoinMultiDoc(docs), true
+}
+
+// writeParent stores every child of the multi-document manifest key and the record listing
+// them in one transaction, deleting children that a previous version had but this one drops.
+// A non-nil previous is kept as a version of key.
+func (s *manifestStoreImpl) writeParent(key string, childKeys []string, children map[string][]byte, previous []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	for _, childKey := range childKeys {
+		if err := s.checkKey(childKey); err != nil {
+			return err
+		}
+		if owner, owned := s.parentOf(childKey); owned && owner != key {
+			return fmt.Errorf("%w: %s is a document of multi-document manifest %s", apperrors.ErrInvalid, childKey, owner)
+		}
+	}
+	previousKeys, _ := s.Children(key)
+
+	var removed []string
+	var deleteKeys []string
+	for _, childKey := range previousKeys {
+		if _, kept := children[childKey]; !kept {
+			removed = append(removed, childKey)
+			deleteKeys = append(deleteKeys, s.dbKey(childKey), ETagKey(s.dbKey(childKey)))
+		}
+	}
+
+	entries := s.dbEntries(children)
+	items := withETags(entries)
+	items[ParentKeyPrefix+s.dbKey(key)] = []byte(strings.Join(childKeys, "\n"))
+	err := s.db.Transaction(func(db *database.DB) error {
+		if err := db.BatchDelete(deleteKeys); err != nil {
+			return err
+		}
+		if err := db.BatchSet(items); err != nil {
+			return err
+		}
+		if previous != nil {
+			return s.saveVersion(db, s.dbKey(key), previous)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("db batch write: %w", err)
+	}
+
+	for dbKey, value := range entries {
+		s.index.Set(dbKey, value)
+	}
+	for _, childKey := range removed {
+		s.index.Delete(s.dbKey(childKey))
+	}
+
+	s.parentsMu.Lock()
+	s.parents[key] = childKeys
+	s.parentsMu.Unlock()
+	return nil
+}
+
+// deleteParent removes the multi-document manifest key together with all of its children
+func (s *manifestStoreImpl) deleteParent(key string, childKeys []string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	deleteKeys := []string{ParentKeyPrefix + s.dbKey(key)}
+	for _, childKey := range childKeys {
+		deleteKeys = append(deleteKeys, s.dbKey(childKey), ETagKey(s.dbKey(childKey)))
+	}
+	if err := s.db.BatchDelete(deleteKeys); err != nil {
+		return fmt.Errorf("db batch delete: %w", err)
+	}
+
+	for _, childKey := range childKeys {
+		s.index.Delete(s.dbKey(childKey))
+	}
+
+	s.parentsMu.Lock()
+	delete(s.parents, key)
+	s.parentsMu.Unlock()
+	return nil
+}
+
+// children returns the documents of the multi-document manifest key as this transaction sees them
+func (t *ManifestStoreTxn) children(key string) ([]string, bool) {
+	if childKeys, written := t.parents[key]; written {
+		return childKeys, childKeys != nil
+	}
+	return t.store.Children(key)
+}
+
+// parentOf returns the multi-document manifest that childKey is a document of as this
+// transaction sees it
+func (t *ManifestStoreTxn) parentOf(childKey string) (string, bool) {
+	for parent, childKeys := range t.parents {
+		if slices.Contains(childKeys, childKey) {
+			return parent, true
+		}
+	}
+	parent, owned := t.store.parentOf(childKey)
+	if _, written := t.parents[parent]; owned && written {
+		return "", false
+	}
+	return parent, owned
+}
+
+// put creates the manifest key, splitting a multi-document value into its documents as
+// Create does
+func (t *ManifestStoreTxn) put(key string, value []byte) error {
+	if err := t.store.checkKey(key); err != nil {
+		return err
+	}
+	childKeys, children, err := splitChildren(value)
+	if err != nil {
+		return err
+	}
+	if children != nil {
+		return t.putParent(key, childKeys, children)
+	}
+	return t.Create(key, value)
+}
+
+// putParent writes every child of the multi-document manifest key and the record listing them,
+// like writeParent but inside the transaction. When key already has children, its previous
+// value is kept as a version and the children this version drops are deleted.
+func (t *ManifestStoreTxn) putParent(key string, childKeys []string, children map[string][]byte) error {
+	for _, childKey := range childKeys {
+		if err := t.store.checkKey(childKey); err != nil {
+			return err
+		}
+		if owner, owned := t.parentOf(childKey); owned && owner != key {
+			return fmt.Errorf("%w: %s is a document of multi-document manifest %s", apperrors.ErrInvalid, childKey, owner)
+		}
+	}
+
+	if previousKeys, isParent := t.children(key); isParent {
+		docs := make([][]byte, 0, len(previousKeys))
+		var deleteKeys []string
+		for _, childKey := range previousKeys {
+			if doc, exists := t.Get(childKey); exists {
+				docs = append(docs, doc)
+			}
+			if _, kept := children[childKey]; !kept {
+				deleteKeys = append(deleteKeys, t.store.dbKey(childKey), ETagKey(t.store.dbKey(childKey)))
+				t.record(t.store.dbKey(childKey), nil)
+			}
+		}
+		if err := t.store.saveVersion(t.db, t.store.dbKey(key), manifest.JoinMultiDoc(docs)); err != nil {
+			return err
+		}
+		if err := t.db.BatchDelete(deleteKeys); err != nil {
+			return fmt.Errorf("db delete: %w", err)
+		}
+	}
+
+	for _, childKey := range childKeys {
+		if err := t.set(childKey, children[childKey]); err != nil {
+			return err
+		}
+	}
+	record := map[string][]byte{ParentKeyPrefix + t.store.dbKey(key): []byte(strings.Join(childKeys, "\n"))}
+	if err := t.db.BatchSet(record); err != nil {
+		return fmt.Errorf("db set: %w", err)
+	}
+	t.parents[key] = childKeys
+	return nil
+}
+
+// deleteParent removes the multi-document manifest key together with all of its children,
+// like the store's deleteParent but inside the transaction
+func (t *ManifestStoreTxn) deleteParent(key string, childKeys []string) error {
+	deleteKeys := []string{ParentKeyPrefix + t.store.dbKey(key)}
+	for _, childKey := range childKeys {
+		deleteKeys = append(deleteKeys, t.store.dbKey(childKey), ETagKey(t.store.dbKey(childKey)))
+	}
+	if err := t.db.BatchDelete(deleteKeys); err != nil {
+		return fmt.Errorf("db delete: %w", err)
+	}
+	for _, childKey := range childKeys {
+		t.record(t.store.dbKey(childKey), nil)
+	}
+	t.parents[key] = nil
+	return nil
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/garunski/conductor-framework/pkg/framework/manifest"
+)
+
+// DefaultTimeout bounds a single webhook call made by an HTTPInvoker
+const DefaultTimeout = 10 * time.Second
+
+// Config describes an external endpoint called around a deployment
+type Config struct {
+	URL     string
+	Method  string // Defaults to POST
+	Headers map[string]string
+	// BodyTemplate is rendered with the manifest template functions and a Payload as data
+	BodyTemplate string
+	// Optional hooks only log failures instead of aborting the deployment
+	Optional bool
+}
+
+// Payload is the template data describing the deployment a hook is called for
+type Payload struct {
+	Stage     string   // "pre-deploy" or "post-deploy"
+	Action    string   // "up" or "update"
+	Services  []string // Selected services; empty means all
+	Timestamp time.Time
+}
+
+// Hook stages reported in Payload.Stage
+const (
+	StagePreDeploy  = "pre-deploy"
+	StagePostDeploy = "post-deploy"
+)
+
+// Invoker calls a webhook
+type Invoker interface {
+	Invoke(ctx context.Context, hook Config, payload Payload) error
+}
+
+// HTTPInvoker calls webhooks over HTTP and treats any non-2xx response as a failure
+type HTTPInvoker struct {
+	client *http.Client
+}
+
+// NewHTTPInvoker creates an HTTPInvoker using client, or a client with DefaultTimeout if nil
+func NewHTTPInvoker(client *http.Client) *HTTPInvoker {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &HTTPInvoker{client: client}
+}
+
+// Ensure *HTTPInvoker implements Invoker interface
+var _ Invoker = (*HTTPInvoker)(nil)
+
+func (i *HTTPInvoker) Invoke(ctx context.Context, hook Config, payload Payload) error {
+	body, err := RenderBody(hook.BodyTemplate, payload)
+	if err != nil {
+		return err
+	}
+
+	method := hook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request for %s: %w", hook.URL, err)
+	}
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s %s failed: %w", method, hook.URL, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s %s returned status %d", method, hook.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// RenderBody renders a hook body template with the manifest template functions
+func RenderBody(bodyTemplate string, payload Payload) ([]byte, error) {
+	if bodyTemplate == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("webhook").Funcs(manifest.TemplateFuncs(nil)).Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook body template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil {
+		return nil, fmt.Errorf("failed to execute webhook body template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
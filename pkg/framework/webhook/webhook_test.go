@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPInvoker_Invoke(t *testing.T) {
+	var gotMethod, gotHeader, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header.Get("X-Token")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := Config{
+		URL:          server.URL,
+		Method:       http.MethodPut,
+		Headers:      map[string]string{"X-Token": "secret"},
+		BodyTemplate: `{"stage":"{{ .Stage }}","services":"{{ join "," .Services | upper }}"}`,
+	}
+	payload := Payload{Stage: StagePreDeploy, Action: "up", Services: []string{"redis", "postgres"}}
+
+	if err := NewHTTPInvoker(nil).Invoke(context.Background(), hook, payload); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if gotMethod != http.MethodPut {
+		t.Errorf("method = %q, want %q", gotMethod, http.MethodPut)
+	}
+	if gotHeader != "secret" {
+		t.Errorf("X-Token header = %q, want %q", gotHeader, "secret")
+	}
+	if want := `{"stage":"pre-deploy","services":"REDIS,POSTGRES"}`; gotBody != want {
+		t.Errorf("body = %q, want %q", gotBody, want)
+	}
+}
+
+func TestHTTPInvoker_InvokeNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %q, want default POST", r.Method)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := NewHTTPInvoker(nil).Invoke(context.Background(), Config{URL: server.URL}, Payload{}); err == nil {
+		t.Error("Invoke() error = nil, want error for status 500")
+	}
+}
+
+func TestRenderBody_InvalidTemplate(t *testing.T) {
+	if _, err := RenderBody("{{ .Missing", Payload{}); err == nil {
+		t.Error("RenderBody() error = nil, want parse error")
+	}
+}